@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -22,6 +23,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/ainsp"
@@ -30,6 +32,8 @@ import (
 	"aahframe.work/cache"
 	"aahframe.work/config"
 	"aahframe.work/console"
+	"aahframe.work/discovery"
+	aerrors "aahframe.work/errors"
 	"aahframe.work/essentials"
 	"aahframe.work/i18n"
 	"aahframe.work/internal/settings"
@@ -37,12 +41,16 @@ import (
 	"aahframe.work/router"
 	"aahframe.work/security"
 	"aahframe.work/security/acrypto"
+	"aahframe.work/security/adminauth"
+	"aahframe.work/security/nonce"
 	"aahframe.work/security/session"
+	"aahframe.work/security/verify"
+	"aahframe.work/tracing"
 	"aahframe.work/valpar"
 	"aahframe.work/vfs"
 	"aahframe.work/view"
 	"aahframe.work/ws"
-	"github.com/go-aah/forge"
+	"golang.org/x/net/webdav"
 	"gopkg.in/go-playground/validator.v9"
 )
 
@@ -72,10 +80,17 @@ func newApp() *Application {
 		RWMutex: sync.RWMutex{},
 		cli:     console.NewApp(),
 		vfs:     new(vfs.VFS),
-		settings: &settings.Settings{
+		settingsHolder: settings.NewHolder(&settings.Settings{
 			VirtualBaseDir: "/app",
-		},
-		cacheMgr: cache.NewManager(),
+		}),
+		cacheMgr:    cache.NewManager(),
+		errRegistry: aerrors.NewRegistry(),
+		restarted:   make(chan struct{}),
+		goGroup:     newGoroutineGroup(),
+		reloadStat:  new(reloadStatus),
+		metrics:     newMetrics(),
+		health:      newHealthChecker(),
+		logLevels:   &routeLogLevels{},
 	}
 	aahApp.cli.Commands = make([]console.Command, 0)
 
@@ -104,27 +119,88 @@ func newApp() *Application {
 type Application struct {
 	sync.RWMutex
 	buildInfo      *BuildInfo
-	settings       *settings.Settings
+	settingsHolder *settings.Holder
 	cli            *console.Application
+	consoleCmds    []ConsoleCommand
 	cfg            *config.Config
 	vfs            *vfs.VFS
 	tlsCfg         *tls.Config
+	tlsCfgFn       func(*tls.Config)
+	dnsProvider    DNSProvider
 	he             *HTTPEngine
 	wse            *ws.Engine
 	server         *http.Server
 	redirectServer *http.Server
+	h3srv          HTTP3Server
+	h3Fn           HTTP3ServerFunc
+	h3AltSvc       string
 	router         *router.Router
+	routeDefs      []*router.Definition
 	eventStore     *EventStore
 	bindMgr        *bindManager
 	i18n           *i18n.I18n
+	contentLang    bool
+	respTranslator ResponseTranslator
 	securityMgr    *security.Manager
+	verifyMailer   verify.Mailer
 	viewMgr        *viewManager
 	staticMgr      *staticManager
+	wellKnown      *wellKnownManager
+	mirror         *mirrorManager
+	loadShed       *loadShedder
+	headerLimits   *headerLimiter
+	respHeaders    *router.HeaderRules
+	limits         limiter
+	respScan       *responseScanner
+	respScanners   []ResponseScanner
+	rawResponse    *rawResponse
+	jsonCache      *jsonReplyCache
+	failedReqs     *failedRequestCapture
+	metrics        *Metrics
+	tracer         *tracing.Tracer
+	traceExporters map[string]tracing.Exporter
+	webdavHandlers map[string]*webdav.Handler
+	debugAllowIPs  []*net.IPNet
+	health         *HealthChecker
+	healthEndpoint *healthEndpoint
+	reqMetrics     *requestMetrics
+	adminGuard     *adminauth.Guard
+	runtimeCtl     *runtimeControl
+	logLevels      *routeLogLevels
+	configDumpPath string
+	audit          *auditTrail
+	auditSinks     map[string]AuditSink
+	piiHandlers    map[string]PIIHandler
+	piiStop        chan struct{}
+	errRegistry    *aerrors.Registry
+	startup        *startupWaiter
+	depChecks      map[string]DependencyCheckFunc
+	seeder         *seeder
+	seeds          []*seedEntry
+	seedFileFn     SeedFileFunc
+	seedMarkers    SeedMarkerStore
+	boundAddr      net.Addr
+	listener       net.Listener
+	listenerSrvs   []*http.Server
+	activeSockets  map[string]net.Listener
+	restarted      chan struct{}
+	goGroup        *goroutineGroup
+	notFoundFn     NotFoundHandlerFunc
+	mnaFn          MethodNotAllowedHandlerFunc
+	httpClient     *http.Client
+	upstreamClient *http.Client
+	discovery      *discovery.Manager
 	errorMgr       *errorManager
 	cacheMgr       *cache.Manager
 	sc             chan os.Signal
+	cfgWatcher     *settings.ConfigWatcher
+	cfgPoller      *settings.ProviderWatcher
+	hotReloadMu    sync.Mutex
+	toggleMu       sync.Mutex
+	reloadStat     *reloadStatus
 	logger         log.Loggerer
 	accessLog      *accessLogger
+	accessLogFlds  map[string]AccessLogFieldFunc
 	dumpLog        *dumpLogger
 	diagnosis      *diagnosis.Diagnosis
 }
@@ -132,7 +208,7 @@ type Application struct {
 // InitForCLI method is for purpose aah CLI tool. IT IS NOT FOR AAH USER.
 // Introduced in v0.12.0 release.
 func (a *Application) InitForCLI(importPath string) error {
-	a.settings.ImportPath = path.Clean(importPath)
+	a.settingsHolder.Update(func(s *settings.Settings) { s.ImportPath = path.Clean(importPath) })
 	a.Log().(*log.Logger).SetLevel("warn")
 	var err error
 	if err = a.initPath(); err != nil {
@@ -141,7 +217,7 @@ func (a *Application) InitForCLI(importPath string) error {
 	if err = a.initConfig(); err != nil {
 		return err
 	}
-	if err = a.settings.Refresh(a.Config()); err != nil {
+	if err = a.settingsHolder.Refresh(a.Config()); err != nil {
 		return err
 	}
 	if err = a.initRouter(); err != nil {
@@ -191,12 +267,13 @@ func (a *Application) Copyrights() string {
 }
 
 // BaseDir method returns the application base or binary's base directory
-// 	For e.g.:
-// 		$GOPATH/src/github.com/user/myproject
-// 		<path/to/the/aah/myproject>
-// 		<app/binary/path/base/directory>
+//
+//	For e.g.:
+//		$GOPATH/src/github.com/user/myproject
+//		<path/to/the/aah/myproject>
+//		<app/binary/path/base/directory>
 func (a *Application) BaseDir() string {
-	return a.settings.BaseDir
+	return a.settings().BaseDir
 }
 
 // VirtualBaseDir method returns "/app". In `v0.11.0` Virtual FileSystem (VFS)
@@ -204,19 +281,44 @@ func (a *Application) BaseDir() string {
 // seamless experience of Read-Only access to application directory and its sub-tree
 // across OS platforms via `aah.App().VFS()`.
 func (a *Application) VirtualBaseDir() string {
-	return a.settings.VirtualBaseDir
+	return a.settings().VirtualBaseDir
 }
 
 // ImportPath method returns the application Go import path.
 func (a *Application) ImportPath() string {
-	return a.settings.ImportPath
+	return a.settings().ImportPath
 }
 
 // HTTPAddress method returns aah application HTTP address otherwise empty string
 //
-// Value of `server.address` from `aah.conf`.
+// Value of `server.address` from `aah.conf`. IPv6 address is returned without
+// brackets e.g. `::1`.
 func (a *Application) HTTPAddress() string {
-	return a.Config().StringDefault("server.address", "")
+	address := a.Config().StringDefault("server.address", "")
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		return address[1 : len(address)-1]
+	}
+	return address
+}
+
+// HTTPNetwork method returns the server listener network based on
+// `server.network` value. Possible values are `tcp4` (IPv4-only),
+// `tcp6` (IPv6-only) and `dual` (dual-stack), default is `dual`.
+func (a *Application) HTTPNetwork() (string, error) {
+	switch network := a.Config().StringDefault("server.network", "dual"); network {
+	case "dual":
+		return "tcp", nil
+	case "tcp4", "tcp6":
+		return network, nil
+	default:
+		return "", fmt.Errorf("'server.network' has unsupported value '%s', supported values are tcp4, tcp6 and dual", network)
+	}
+}
+
+// Timeouts method returns the parsed server timeout values of config
+// `server.timeout.*`, for e.g.: sub-second `read_header` and `grace_shutdown`.
+func (a *Application) Timeouts() settings.Timeouts {
+	return a.settings()
 }
 
 // HTTPPort method returns aah application HTTP port number based on `server.port`
@@ -229,6 +331,15 @@ func (a *Application) HTTPPort() string {
 	return a.parsePort(port)
 }
 
+// BoundAddr method returns the network address server listener is bound to,
+// it's useful with ephemeral port `server.port = "0"`. Returns nil if server
+// is not started yet.
+func (a *Application) BoundAddr() net.Addr {
+	a.RLock()
+	defer a.RUnlock()
+	return a.boundAddr
+}
+
 // BuildInfo method return user application version no.
 func (a *Application) BuildInfo() *BuildInfo {
 	return a.buildInfo
@@ -241,14 +352,14 @@ func (a *Application) SetBuildInfo(bi *BuildInfo) {
 
 // IsPackaged method returns true when application built for deployment.
 func (a *Application) IsPackaged() bool {
-	return a.settings.PackagedMode
+	return a.settings().PackagedMode
 }
 
 // SetPackaged method sets the info of binary is packaged or not.
 //
 // It is used by framework during application startup. IT'S NOT FOR AAH USER(S).
 func (a *Application) SetPackaged(pack bool) {
-	a.settings.PackagedMode = pack
+	a.settingsHolder.Update(func(s *settings.Settings) { s.PackagedMode = pack })
 }
 
 // EnvProfile returns active environment profile name of aah application.
@@ -258,7 +369,7 @@ func (a *Application) SetPackaged(pack bool) {
 func (a *Application) EnvProfile() string {
 	a.RLock()
 	defer a.RUnlock()
-	return a.settings.EnvProfile
+	return a.settings().EnvProfile
 }
 
 // IsEnvProfile method returns true if given environment profile match with active
@@ -285,13 +396,13 @@ func (a *Application) EnvProfiles() []string {
 // IsSSLEnabled method returns true if aah application is enabled with SSL
 // otherwise false.
 func (a *Application) IsSSLEnabled() bool {
-	return a.settings.SSLEnabled
+	return a.settings().SSLEnabled
 }
 
 // IsLetsEncryptEnabled method returns true if aah application is enabled with
 // Let's Encrypt certs otherwise false.
 func (a *Application) IsLetsEncryptEnabled() bool {
-	return a.settings.LetsEncryptEnabled
+	return a.settings().LetsEncryptEnabled
 }
 
 // IsWebSocketEnabled method returns to true if aah application enabled with
@@ -316,11 +427,50 @@ func (a *Application) SetTLSConfig(tlsCfg *tls.Config) {
 	a.tlsCfg = tlsCfg
 }
 
+// SetConfigProvider method is used to set remote config provider such as
+// Consul KV, etcd, Vault, etc. Provider config values are merged on top of
+// `aah.conf` and watched for changes to hot-reload the application.
+//
+// Use `func init() {...}` to assign your config provider.
+func (a *Application) SetConfigProvider(p config.Provider) {
+	a.settingsHolder.Update(func(s *settings.Settings) { s.ConfigProvider = p })
+}
+
+// DumpConfig method writes the fully-resolved effective configuration of
+// active env profile as `json` or `hocon` format into given writer. Values are
+// resolved after profile merge and environment variable overrides, secret
+// values are masked.
+func (a *Application) DumpConfig(w io.Writer, format string) error {
+	return a.settings().Dump(w, format)
+}
+
+// DiffConfigProfiles method returns the config keys which differs between
+// given env profiles, for e.g.: `a.DiffConfigProfiles("dev", "prod")`.
+func (a *Application) DiffConfigProfiles(profileA, profileB string) ([]settings.ProfileDiff, error) {
+	return a.settings().DiffProfiles(profileA, profileB)
+}
+
+// AddSecretResolver method adds the secret resolver which implements
+// `settings.SecretResolver` interface. Secret config values such as
+// `ENC(...)`, `vault://path` are resolved while loading the config. Built-in
+// AES-GCM resolver is configured via `runtime.secrets { ... }`.
+//
+// Use `func init() {...}` to add your secret resolver.
+func (a *Application) AddSecretResolver(r settings.SecretResolver) {
+	a.settingsHolder.Update(func(s *settings.Settings) { s.AddSecretResolver(r) })
+}
+
 // HTTPEngine method returns aah HTTP engine.
 func (a *Application) HTTPEngine() *HTTPEngine {
 	return a.he
 }
 
+// DiscoveryManager method returns the DNS based service discovery manager,
+// it's nil if services are not configured under `discovery { ... }`.
+func (a *Application) DiscoveryManager() *discovery.Manager {
+	return a.discovery
+}
+
 // WSEngine method returns aah WebSocket engine.
 //
 // Note: It could be nil if WebSocket is not enabled.
@@ -364,6 +514,20 @@ func (a *Application) SessionManager() *session.Manager {
 	return a.SecurityManager().SessionManager
 }
 
+// NonceStore method returns the application short-lived token store used by
+// OAuth2 state, replay protection, password reset tokens, etc. It's backed by
+// the cache configured in `security.nonce_store.cache` otherwise in-memory.
+func (a *Application) NonceStore() *nonce.Store {
+	return a.SecurityManager().NonceStore
+}
+
+// ClockSkew method returns the clock skew tolerance `security.clock_skew`
+// to be used by the time based validations such as JWT, HMAC signatures,
+// TOTP, etc. so that all of them share one configurable leeway.
+func (a *Application) ClockSkew() time.Duration {
+	return a.settings().ClockSkew
+}
+
 // ViewEngine method returns aah application view Engine instance.
 func (a *Application) ViewEngine() view.Enginer {
 	if a.viewMgr == nil {
@@ -400,11 +564,70 @@ func (a *Application) SetErrorHandler(handlerFunc ErrorHandlerFunc) {
 	a.errorMgr.SetHandler(handlerFunc)
 }
 
+// ErrorRegistry method returns the application error mapping registry. Errors
+// returned by controller action are mapped to HTTP status code, i18n message
+// key and problem details fields via registry.
+//
+// For e.g.:
+//
+//	aah.App().ErrorRegistry().Register(sql.ErrNoRows, errors.Mapping{
+//		Status: http.StatusNotFound,
+//		Key:    "error.record.notfound",
+//	})
+func (a *Application) ErrorRegistry() *aerrors.Registry {
+	return a.errRegistry
+}
+
+// SetNotFoundHandler method is used to register custom application not found
+// handler. It's invoked when route or static file not found, reply status
+// is already set to `404 Not Found`. If not registered then default error
+// handling takes place.
+func (a *Application) SetNotFoundHandler(handlerFunc NotFoundHandlerFunc) {
+	a.Lock()
+	defer a.Unlock()
+	a.notFoundFn = handlerFunc
+	if handlerFunc != nil {
+		a.Log().Infof("Custom not found handler is registered with: %v", ess.GetFunctionInfo(handlerFunc).QualifiedName)
+	}
+}
+
+// SetMethodNotAllowedHandler method is used to register custom application
+// method not allowed handler. It's invoked when route path exists for other
+// HTTP methods, reply status is already set to `405 Method Not Allowed` with
+// header `Allow` populated from the route table. If not registered then
+// default error handling takes place.
+func (a *Application) SetMethodNotAllowedHandler(handlerFunc MethodNotAllowedHandlerFunc) {
+	a.Lock()
+	defer a.Unlock()
+	a.mnaFn = handlerFunc
+	if handlerFunc != nil {
+		a.Log().Infof("Custom method not allowed handler is registered with: %v", ess.GetFunctionInfo(handlerFunc).QualifiedName)
+	}
+}
+
 // AddController method adds given controller into controller registory.
 func (a *Application) AddController(c interface{}, methods []*ainsp.Method) {
 	a.HTTPEngine().registry.Add(c, methods)
 }
 
+// AddRoute method registers the route programmatically for given HTTP method,
+// path and controller action, it's added after `routes.conf` routes. It's
+// useful for reusable modules (admin UI, metrics, health, etc.) to register
+// their routes without editing `routes.conf`. Use it from `func init() {...}`.
+//
+//	err := aah.App().AddRoute("GET", "/admin/users", (*admin.AdminController).Users,
+//		router.WithName("admin_users"), router.WithAuth("form_auth"))
+func (a *Application) AddRoute(method, path string, action interface{}, opts ...router.RouteOption) error {
+	d, err := router.NewDefinition(method, path, action, opts...)
+	if err != nil {
+		return err
+	}
+	a.Lock()
+	a.routeDefs = append(a.routeDefs, d)
+	a.Unlock()
+	return nil
+}
+
 // AddWebSocket method adds given WebSocket into WebSocket registry.
 func (a *Application) AddWebSocket(w interface{}, methods []*ainsp.Method) {
 	a.WSEngine().AddWebSocket(w, methods)
@@ -427,6 +650,25 @@ func (a *Application) AddSessionStore(name string, store session.Storer) error {
 	return session.AddStore(name, store)
 }
 
+// AddVerifyStore method allows you to add custom token store for password
+// reset and email verification flows which implements `verify.Storer` interface.
+// Then configure `name` parameter in the configuration as
+// `security.verify.store.type = "name"`.
+func (a *Application) AddVerifyStore(name string, store verify.Storer) error {
+	return verify.AddStore(name, store)
+}
+
+// SetVerifyMailer method sets the mailer which sends the generated password
+// reset and email verification tokens to the user.
+func (a *Application) SetVerifyMailer(mailer verify.Mailer) {
+	a.Lock()
+	a.verifyMailer = mailer
+	a.Unlock()
+	if a.securityMgr != nil && a.securityMgr.Verifier != nil {
+		a.securityMgr.Verifier.SetMailer(mailer)
+	}
+}
+
 // AddPasswordAlgorithm method adds given password algorithm to encoders list.
 // Implementation have to implement interface `PasswordEncoder`.
 //
@@ -445,7 +687,7 @@ func (a *Application) AddValueParser(typ reflect.Type, parser valpar.Parser) err
 func (a *Application) AddCommand(cmds ...console.Command) error {
 	for _, cmd := range cmds {
 		name := strings.ToLower(cmd.Name)
-		if name == "run" || name == "vfs" || name == "help" {
+		if name == "run" || name == "console" || name == "seed" || name == "vfs" || name == "help" {
 			return fmt.Errorf("aah: reserved command name '%s' cannot be used", name)
 		}
 		for _, c := range a.cli.Commands {
@@ -462,11 +704,11 @@ func (a *Application) AddCommand(cmds ...console.Command) error {
 //
 // Returns:
 //
-//  - For validation errors: returns `validator.ValidationErrors` and nil
+//   - For validation errors: returns `validator.ValidationErrors` and nil
 //
-//  - For invalid input: returns nil, error (invalid input such as nil, non-struct, etc.)
+//   - For invalid input: returns nil, error (invalid input such as nil, non-struct, etc.)
 //
-//  - For no validation errors: nil, nil
+//   - For no validation errors: nil, nil
 func (a *Application) Validate(s interface{}) (validator.ValidationErrors, error) {
 	return valpar.Validate(s)
 }
@@ -475,20 +717,20 @@ func (a *Application) Validate(s interface{}) (validator.ValidationErrors, error
 //
 // Returns -
 //
-//  - true: validation passed
+//   - true: validation passed
 //
-//  - false: validation failed
+//   - false: validation failed
 //
 // For example:
 //
-// 	i := 15
-// 	result := valpar.ValidateValue(i, "gt=1,lt=10")
+//	i := 15
+//	result := valpar.ValidateValue(i, "gt=1,lt=10")
 //
-// 	emailAddress := "sample@sample"
-// 	result := valpar.ValidateValue(emailAddress, "email")
+//	emailAddress := "sample@sample"
+//	result := valpar.ValidateValue(emailAddress, "email")
 //
-// 	numbers := []int{23, 67, 87, 23, 90}
-// 	result := valpar.ValidateValue(numbers, "unique")
+//	numbers := []int{23, 67, 87, 23, 90}
+//	result := valpar.ValidateValue(numbers, "unique")
 func (a *Application) ValidateValue(v interface{}, rules string) bool {
 	return valpar.ValidateValue(v, rules)
 }
@@ -497,7 +739,9 @@ func (a *Application) ValidateValue(v interface{}, rules string) bool {
 // If anything goes wrong during an initialize process, it would return an error.
 func (a *Application) Run(args []string) error {
 	var err error
-	a.settings.SetImportPath(args) // only needed for development via CLI
+	a.settingsHolder.Update(func(s *settings.Settings) {
+		s.SetImportPath(args) // only needed for development via CLI
+	})
 	if err = a.initPath(); err != nil {
 		return err
 	}
@@ -526,7 +770,7 @@ func (a *Application) initPath() error {
 				}
 			}
 		}
-		forge.RegisterFS(&aahVFS{fs: a.VFS()})
+		config.RegisterFS(&aahVFS{fs: a.VFS()})
 	}()
 
 	// Application is packaged, it means built via `aah build`
@@ -536,13 +780,14 @@ func (a *Application) initPath() error {
 			return err
 		}
 
-		if a.VFS().IsEmbeddedMode() {
-			a.settings.BaseDir = filepath.Dir(ep)
-		} else if a.settings.BaseDir, err = inferBaseDir(ep); err != nil {
-			return err
+		baseDir := filepath.Dir(ep)
+		if !a.VFS().IsEmbeddedMode() {
+			if baseDir, err = inferBaseDir(ep); err != nil {
+				return err
+			}
 		}
 
-		a.settings.BaseDir = filepath.Clean(a.settings.BaseDir)
+		a.setBaseDir(filepath.Clean(baseDir), false)
 		return nil
 	}
 
@@ -552,8 +797,7 @@ func (a *Application) initPath() error {
 			return fmt.Errorf("path does not exists: %s", a.ImportPath())
 		}
 
-		a.settings.BaseDir = filepath.Clean(a.ImportPath())
-		a.settings.PhysicalPathMode = true
+		a.setBaseDir(filepath.Clean(a.ImportPath()), true)
 		return nil
 	}
 
@@ -562,7 +806,7 @@ func (a *Application) initPath() error {
 		if err != nil {
 			return err
 		}
-		a.settings.BaseDir = cwd
+		a.setBaseDir(cwd, false)
 		return nil
 	}
 
@@ -573,7 +817,7 @@ func (a *Application) initPath() error {
 	}
 
 	// Import path mode
-	a.settings.BaseDir = filepath.Join(gopath, "src", filepath.FromSlash(a.ImportPath()))
+	a.setBaseDir(filepath.Join(gopath, "src", filepath.FromSlash(a.ImportPath())), false)
 	if !ess.IsFileExists(a.BaseDir()) {
 		return fmt.Errorf("import path does not exists: %s", a.ImportPath())
 	}
@@ -587,7 +831,7 @@ func (a *Application) initApp() error {
 		a.EventStore().sortEventSubscribers(event)
 	}
 	a.EventStore().PublishSync(&Event{Name: EventOnInit}) // publish `OnInit` server event
-	if err = a.settings.Refresh(a.Config()); err != nil {
+	if err = a.settingsHolder.Refresh(a.Config()); err != nil {
 		return err
 	}
 	if err = a.initLog(); err != nil {
@@ -611,15 +855,75 @@ func (a *Application) initApp() error {
 	if err = a.initStatic(); err != nil {
 		return err
 	}
+	if err = a.initWellKnown(); err != nil {
+		return err
+	}
+	if a.discovery, err = discovery.New(a.Config(), a.Log()); err != nil {
+		return err
+	}
+	if err = a.initHTTPClient(); err != nil {
+		return err
+	}
+	if err = a.initMirror(); err != nil {
+		return err
+	}
+	if err = a.initLoadShedding(); err != nil {
+		return err
+	}
+	if err = a.initHeaderLimits(); err != nil {
+		return err
+	}
+	if err = a.initResponseHeaders(); err != nil {
+		return err
+	}
+	if err = a.initResponseScan(); err != nil {
+		return err
+	}
+	if err = a.initRawResponse(); err != nil {
+		return err
+	}
+	if err = a.initJSONCache(); err != nil {
+		return err
+	}
+	if err = a.initAdmin(); err != nil {
+		return err
+	}
+	if err = a.initFailedRequests(); err != nil {
+		return err
+	}
+	if err = a.initMetrics(); err != nil {
+		return err
+	}
+	if err = a.initTracing(); err != nil {
+		return err
+	}
+	if err = a.initHealth(); err != nil {
+		return err
+	}
+	if err = a.initAudit(); err != nil {
+		return err
+	}
+	if err = a.initRuntimeControl(); err != nil {
+		return err
+	}
+	if err = a.initPII(); err != nil {
+		return err
+	}
+	if err = a.initStartup(); err != nil {
+		return err
+	}
+	if err = a.initSeed(); err != nil {
+		return err
+	}
 	if err = a.initError(); err != nil {
 		return err
 	}
-	if a.settings.AccessLogEnabled {
+	if a.settings().AccessLogEnabled {
 		if err = a.initAccessLog(); err != nil {
 			return err
 		}
 	}
-	if a.settings.DumpLogEnabled {
+	if a.settings().DumpLogEnabled {
 		if err = a.initDumpLog(); err != nil {
 			return err
 		}
@@ -632,10 +936,23 @@ func (a *Application) initApp() error {
 	if err := a.CacheManager().InitProviders(a.Config(), a.Log()); err != nil {
 		return err
 	}
-	a.settings.Initialized = true
+	a.settingsHolder.Update(func(s *settings.Settings) { s.Initialized = true })
 	return nil
 }
 
+// settings method returns the current settings snapshot, it must not be
+// modified, use `settings.Holder.Update`.
+func (a *Application) settings() *settings.Settings {
+	return a.settingsHolder.Load()
+}
+
+func (a *Application) setBaseDir(baseDir string, physicalPathMode bool) {
+	a.settingsHolder.Update(func(s *settings.Settings) {
+		s.BaseDir = baseDir
+		s.PhysicalPathMode = s.PhysicalPathMode || physicalPathMode
+	})
+}
+
 func (a *Application) binaryFilename() string {
 	if a.buildInfo == nil {
 		return ""
@@ -677,15 +994,55 @@ func (a *Application) Config() *config.Config {
 }
 
 func (a *Application) initConfig() error {
-	cfg, err := config.LoadFile(path.Join(a.VirtualBaseDir(), "config", "aah.conf"))
+	cfg, err := a.loadConfig()
 	if err != nil {
-		return fmt.Errorf("aah.conf: %s", err)
+		return err
 	}
-
 	a.cfg = cfg
 	return nil
 }
 
+// loadConfig method loads the application config files merged with config
+// provider values, it does not apply the config to application.
+func (a *Application) loadConfig() (*config.Config, error) {
+	cfgFile := a.configFile("aah")
+	cfg, err := config.LoadFile(cfgFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path.Base(cfgFile), err)
+	}
+
+	if err = a.settings().MergeProviderConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	// Environment variable overrides for config keys, precedence order is
+	// env > profile > default. For e.g.: `AAH_SERVER_PORT` => `server.port`
+	if cfg.BoolDefault("runtime.env_override.enable", false) {
+		var profiles []string
+		for _, k := range cfg.KeysByPath("env") {
+			if p := settings.ProfilePrefix + k; cfg.HasProfile(p) {
+				profiles = append(profiles, p)
+			}
+		}
+		if _, err = cfg.ApplyEnvOverrides(cfg.StringDefault("runtime.env_override.prefix", "AAH"), profiles...); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// configFile method returns the config file path for given name from app
+// config directory. File format is selected by extension in the order of
+// `config.FileExts`, for e.g.: `aah.conf`, `aah.yaml`, `aah.yml`, `aah.toml`.
+func (a *Application) configFile(name string) string {
+	for _, ext := range config.FileExts {
+		if f := path.Join(a.VirtualBaseDir(), "config", name+ext); a.VFS().IsExists(f) {
+			return f
+		}
+	}
+	return path.Join(a.VirtualBaseDir(), "config", name+".conf")
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Log Definitions
 //______________________________________________________________________________
@@ -727,10 +1084,15 @@ func (a *Application) initLog() error {
 		"appname": a.Name(),
 		"insname": a.InstanceName(),
 	})
+	if a.Config().BoolDefault("log.enrich.app_version", false) &&
+		a.BuildInfo() != nil && len(a.BuildInfo().Version) > 0 {
+		al.AddContext(log.Fields{"appversion": a.BuildInfo().Version})
+	}
+	al.SetRedactFields(a.settings().PIIFields...)
 
 	a.logger = al
 	log.SetDefaultLogger(al)
-	return nil
+	return a.initLogLevels()
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -751,6 +1113,8 @@ func (a *Application) DefaultI18nLang() string {
 }
 
 func (a *Application) initI18n() error {
+	a.contentLang = a.Config().BoolDefault("i18n.content_language", true)
+
 	i18nPath := path.Join(a.VirtualBaseDir(), "i18n")
 	if !a.VFS().IsExists(i18nPath) {
 		// i18n directory not exists, scenario could be only API application
@@ -774,7 +1138,7 @@ func (a *Application) initI18n() error {
 // ServeHTTP method implementation of http.Handler interface.
 func (a *Application) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer a.aahRecover()
-	if a.settings.Redirect {
+	if a.settings().Redirect {
 		if a.he.doRedirect(w, r) {
 			return
 		}
@@ -795,44 +1159,91 @@ func (a *Application) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 //______________________________________________________________________________
 
 func (a *Application) listenForHotReload() {
-	if !a.settings.HotReloadEnabled || a.IsEnvProfile(settings.DefaultEnvProfile) || !a.IsPackaged() {
+	if !a.settings().HotReloadEnabled || a.IsEnvProfile(settings.DefaultEnvProfile) || !a.IsPackaged() {
 		return
 	}
-	if runtime.GOOS == "windows" && (a.settings.HotReloadSignalStr == "SIGUSR1" ||
-		a.settings.HotReloadSignalStr == "SIGUSR2") {
+	if runtime.GOOS == "windows" && (a.settings().HotReloadSignalStr == "SIGUSR1" ||
+		a.settings().HotReloadSignalStr == "SIGUSR2") {
 		a.Log().Warn("OS Windows does not support signal SIGUSR1/SIGUSR2 let's fallback to default SIGHUP")
 	}
 	a.sc = make(chan os.Signal, 1)
-	signal.Notify(a.sc, a.settings.HotReloadSignal())
+	signal.Notify(a.sc, a.settings().HotReloadSignal())
 	for {
 		<-a.sc
-		a.Log().Warnf("Hangup signal (%s) received", a.settings.HotReloadSignalStr)
+		a.Log().Warnf("Hangup signal (%s) received", a.settings().HotReloadSignalStr)
 		a.performHotReload()
 	}
 }
 
+// OnConfigChange method adds the callback func, it's called after the
+// application config is reloaded and reinitialized successfully at runtime
+// either by hot-reload signal or config watcher
+// `runtime.config_hotreload.watch`.
+func (a *Application) OnConfigChange(fn func()) {
+	a.settingsHolder.OnConfigChange(fn)
+}
+
+// watchConfig method starts the config files and config provider watcher,
+// change in config triggers the application hot-reload.
+func (a *Application) watchConfig() {
+	if !a.settings().HotReloadEnabled {
+		return
+	}
+	if p := a.settings().ConfigProvider; p != nil {
+		a.cfgPoller = settings.NewProviderWatcher(p, a.settings().ProviderPollInterval, func() {
+			a.Log().Warnf("Config provider '%s' change detected", p.Name())
+			a.performHotReload()
+		})
+		go a.cfgPoller.Start()
+	}
+	if !a.settings().ConfigWatchEnabled {
+		return
+	}
+	if a.VFS().IsEmbeddedMode() {
+		a.Log().Warn("Config watcher is not supported on embedded config files, use hot-reload signal")
+		return
+	}
+	cfgDir := filepath.Join(a.BaseDir(), "config")
+	if !ess.IsFileExists(cfgDir) {
+		a.Log().Warnf("Config directory '%s' does not exists, config watcher is not started", cfgDir)
+		return
+	}
+	a.cfgWatcher = settings.NewConfigWatcher(cfgDir, a.settings().ConfigWatchInterval, func() {
+		a.Log().Warn("Config file change detected")
+		a.performHotReload()
+	})
+	go a.cfgWatcher.Start()
+}
+
 func (a *Application) performHotReload() {
-	a.settings.HotReload = true
-	defer func() { a.settings.HotReload = false }()
+	a.hotReloadMu.Lock()
+	defer a.hotReloadMu.Unlock()
+	a.settingsHolder.Update(func(s *settings.Settings) { s.HotReload = true })
+	defer a.settingsHolder.Update(func(s *settings.Settings) { s.HotReload = false })
 
 	activeProfile := a.EnvProfile()
 
 	a.Log().Info("Application hot-reload and reinitialization starts ...")
 	var err error
+	defer func() { a.reloadStat.record(err) }()
 
-	if err = a.initConfig(); err != nil {
+	cfg, err := a.loadConfig()
+	if err != nil {
 		a.Log().Errorf("Unable to reload aah.conf: %v", err)
 		return
 	}
 	a.Log().Info("Configuration files reload succeeded")
 
 	// Set activeProfile into reloaded configuration
-	a.Config().SetString("env.active", activeProfile)
+	cfg.SetString("env.active", activeProfile)
 
-	if err = a.settings.Refresh(a.Config()); err != nil {
-		a.Log().Errorf("Unable to reinitialize aah application settings: %v", err)
+	// Validate the reloaded config on settings snapshot, application keeps
+	// serving with previous config and settings if it's invalid
+	if err = a.settingsHolder.Refresh(cfg); err != nil {
+		a.rejectHotReload(cfg, err)
 		return
 	}
+	a.cfg = cfg
 	a.Log().Info("Configuration values reinitialize succeeded")
 
 	if err = a.initLog(); err != nil {
@@ -869,7 +1280,97 @@ func (a *Application) performHotReload() {
 	}
 	a.Log().Info("Security reinitialize succeeded")
 
-	if a.settings.AccessLogEnabled {
+	if err = a.initWellKnown(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application well-known endpoints: %v", err)
+		return
+	}
+
+	if a.discovery, err = discovery.New(a.Config(), a.Log()); err != nil {
+		a.Log().Errorf("Unable to reinitialize application service discovery: %v", err)
+		return
+	}
+
+	if err = a.initHTTPClient(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application HTTP client: %v", err)
+		return
+	}
+
+	if err = a.initMirror(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application request mirroring: %v", err)
+		return
+	}
+
+	if err = a.initLoadShedding(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application load shedding: %v", err)
+		return
+	}
+
+	if err = a.initHeaderLimits(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application header limits: %v", err)
+		return
+	}
+
+	if err = a.initResponseHeaders(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application response header rules: %v", err)
+		return
+	}
+
+	if err = a.initResponseScan(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application response scan: %v", err)
+		return
+	}
+
+	if err = a.initRawResponse(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application raw response: %v", err)
+		return
+	}
+
+	if err = a.initJSONCache(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application JSON reply cache: %v", err)
+		return
+	}
+
+	if err = a.initAdmin(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application admin endpoints: %v", err)
+		return
+	}
+
+	if err = a.initFailedRequests(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application failed requests capture: %v", err)
+		return
+	}
+
+	if err = a.initMetrics(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application metrics: %v", err)
+		return
+	}
+
+	if err = a.initTracing(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application tracing: %v", err)
+		return
+	}
+
+	if err = a.initHealth(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application health endpoints: %v", err)
+		return
+	}
+
+	if err = a.initAudit(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application audit trail: %v", err)
+		return
+	}
+
+	if err = a.initRuntimeControl(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application runtime control: %v", err)
+		return
+	}
+
+	if err = a.initPII(); err != nil {
+		a.Log().Errorf("Unable to reinitialize application PII retention: %v", err)
+		return
+	}
+
+	if a.settings().AccessLogEnabled {
 		if err = a.initAccessLog(); err != nil {
 			a.Log().Errorf("Unable to reinitialize application access log: %v", err)
 			return
@@ -877,7 +1378,7 @@ func (a *Application) performHotReload() {
 		a.Log().Info("Access logging reinitialize succeeded")
 	}
 
-	if a.settings.DumpLogEnabled {
+	if a.settings().DumpLogEnabled {
 		if err = a.initDumpLog(); err != nil {
 			a.Log().Errorf("Unable to reinitialize application dump log: %v", err)
 			return
@@ -887,6 +1388,7 @@ func (a *Application) performHotReload() {
 
 	a.Log().Info("Application hot-reload and reinitialization was successful")
 	a.EventStore().PublishSync(&Event{Name: EventOnConfigHotReload})
+	a.settingsHolder.PublishConfigChange()
 }
 
 func inferBaseDir(p string) (string, error) {
@@ -7,6 +7,8 @@ package aah
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -27,7 +29,9 @@ import (
 	"aahframe.work/config"
 	"aahframe.work/console"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/log"
+	"aahframe.work/router"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -188,6 +192,8 @@ func TestAppMisc(t *testing.T) {
 	assert.Nil(t, er)
 	er = a.AddCommand(console.Command{Name: "vfs"})
 	assert.Equal(t, errors.New("aah: reserved command name 'vfs' cannot be used"), er)
+	er = a.AddCommand(console.Command{Name: "console"})
+	assert.Equal(t, errors.New("aah: reserved command name 'console' cannot be used"), er)
 	er = a.AddCommand(console.Command{Name: "test2"})
 	assert.Equal(t, errors.New("aah: command name 'test2' already exists"), er)
 
@@ -197,7 +203,7 @@ func TestAppMisc(t *testing.T) {
 	// simualate CLI call
 	t.Log("simualate CLI call")
 	a.SetBuildInfo(nil)
-	a.settings.PackagedMode = false
+	a.settingsHolder.Update(func(s *settings.Settings) { s.PackagedMode = false })
 	err := a.InitForCLI(importPath)
 	assert.Nil(t, err)
 
@@ -206,12 +212,12 @@ func TestAppMisc(t *testing.T) {
 	a.SetTLSConfig(nil)
 	a.Config().SetBool("server.ssl.enable", true)
 	a.Config().SetBool("server.ssl.lets_encrypt.enable", true)
-	err = a.settings.Refresh(a.Config())
+	err = a.settingsHolder.Refresh(a.Config())
 	assert.Nil(t, err)
 
 	// simulate import path
 	t.Log("simulate import path")
-	a.settings.ImportPath = "github.com/jeevatkm/noapp"
+	a.settingsHolder.Update(func(s *settings.Settings) { s.ImportPath = "github.com/jeevatkm/noapp" })
 	_ = a.initPath()
 	// assert.True(t, strings.HasPrefix(err.Error(), "import path does not exists:"))
 
@@ -309,6 +315,223 @@ func TestAccessLogInitAbsPath(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestAccessLogJSONFormat(t *testing.T) {
+	logPath := filepath.Join(testdataBaseDir(), "sample-test-access-json.log")
+	defer ess.DeleteFiles(logPath)
+
+	a := newApp()
+	cfg, _ := config.ParseString(fmt.Sprintf(`server {
+    access_log {
+      file = "%s"
+      format = "json"
+      fields = ["request_id", "method", "path", "route", "status", "bytes", "user", "referer"]
+    }
+  }`, filepath.ToSlash(logPath)))
+	a.cfg = cfg
+	assert.Nil(t, a.initAccessLog())
+	assert.Equal(t, 8, len(a.accessLog.jsonFields))
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost:8080/users/1?debug=1", nil)
+	r.Header.Set(ahttp.HeaderReferer, `https://example.com/"home"`)
+	al := &accessLog{
+		StartTime: time.Now(),
+		Request:   ahttp.AcquireRequest(r),
+		RequestID: "a1b2c3",
+		RouteName: "user_get",
+		User:      "jeeva",
+		ResStatus: http.StatusOK,
+		ResBytes:  128,
+	}
+	assert.Equal(t, `{"request_id":"a1b2c3","method":"GET","path":"/users/1","route":"user_get",`+
+		`"status":200,"bytes":128,"user":"jeeva","referer":"https://example.com/\"home\""}`,
+		a.accessLog.accessLogFormatter(al))
+
+	// default fields
+	a.cfg, _ = config.ParseString(fmt.Sprintf(`server {
+    access_log {
+      file = "%s"
+      format = "json"
+    }
+  }`, filepath.ToSlash(logPath)))
+	assert.Nil(t, a.initAccessLog())
+	assert.Equal(t, defaultAccessLogJSONFields, a.accessLog.jsonFields)
+
+	// errors
+	errCfg, _ := config.ParseString(`server { access_log { fields = ["status", "geo"]; } }`)
+	assert.Nil(t, a.cfg.Merge(errCfg))
+	assert.Equal(t, "'server.access_log.fields' has unsupported field 'geo'", a.initAccessLog().Error())
+
+	a.cfg.SetString("server.access_log.format", "logfmt")
+	assert.Equal(t, "'server.access_log.format' value must be 'text' or 'json', found 'logfmt'", a.initAccessLog().Error())
+}
+
+func TestAccessLogCustomFieldsAndSkip(t *testing.T) {
+	logPath := filepath.Join(testdataBaseDir(), "sample-test-access-custom.log")
+	defer ess.DeleteFiles(logPath)
+
+	a := newApp()
+	cfg, _ := config.ParseString(fmt.Sprintf(`server {
+    access_log {
+      file = "%s"
+      pattern = "%%reqmethod %%ctx:tenant_id %%{region}ctx %%ctx:plan %%resstatus"
+      skip_paths = ["/assets/**"]
+      skip_status_codes = ["2xx", "304"]
+    }
+  }`, filepath.ToSlash(logPath)))
+	a.cfg = cfg
+	assert.Nil(t, a.AddAccessLogField("tenant_id", func(ctx *Context) string {
+		return ctx.Req.Header.Get("X-Tenant-Id")
+	}))
+	assert.Equal(t, "aah: access log field 'tenant_id' is already added",
+		a.AddAccessLogField("tenant_id", func(ctx *Context) string { return "" }).Error())
+	assert.Equal(t, "aah: access log field func is nil", a.AddAccessLogField("plan", nil).Error())
+	assert.Nil(t, a.initAccessLog())
+	assert.Equal(t, []string{"tenant_id", "region", "plan"}, a.accessLog.ctxFields)
+
+	newCtx := func(target string, status int) *Context {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("X-Tenant-Id", "acme")
+		ctx := newContext(httptest.NewRecorder(), r)
+		ctx.a = a
+		ctx.Set("region", "eu-west-1")
+		ctx.Res.WriteHeader(status)
+		return ctx
+	}
+
+	ctx := newCtx("http://localhost:8080/orders", http.StatusOK)
+	assert.False(t, a.accessLog.skip.match(ctx))
+	al := &accessLog{Request: ctx.Req, ResStatus: http.StatusOK,
+		CtxValues: map[string]string{}}
+	for _, name := range a.accessLog.ctxFields {
+		al.CtxValues[name] = a.accessLog.resolveField(ctx, name)
+	}
+	assert.Equal(t, "GET acme eu-west-1 - 200", a.accessLog.accessLogFormatter(al))
+
+	assert.True(t, a.accessLog.skip.match(newCtx("http://localhost:8080/assets/js/app.js", http.StatusOK)))
+	assert.True(t, a.accessLog.skip.match(newCtx("http://localhost:8080/assets/logo.png", http.StatusNotModified)))
+	assert.False(t, a.accessLog.skip.match(newCtx("http://localhost:8080/assets/missing.css", http.StatusNotFound)))
+
+	assert.Nil(t, setTestConfig(a, fmt.Sprintf(`server { access_log { file = "%s"; skip_status_codes = ["6xx"]; } }`,
+		filepath.ToSlash(logPath))))
+	assert.Equal(t, "'server.access_log.skip_status_codes' has invalid value '6xx'", a.initAccessLog().Error())
+}
+
+func TestDumpLogFilter(t *testing.T) {
+	logPath := filepath.Join(testdataBaseDir(), "sample-test-dump.log")
+	defer ess.DeleteFiles(logPath)
+
+	a := newApp()
+	cfg, _ := config.ParseString(fmt.Sprintf(`server {
+    dump_log {
+      file = "%s"
+      filter {
+        routes = ["api_v1"]
+        paths = ["/api/**"]
+        content_types = ["application/json"]
+        status_codes = ["5xx", "400-404"]
+      }
+    }
+  }`, filepath.ToSlash(logPath)))
+	a.cfg = cfg
+
+	err := a.initDumpLog()
+	assert.Nil(t, err)
+
+	newCtx := func(p string, status int, ct string) *Context {
+		r := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080"+p, nil)
+		w := httptest.NewRecorder()
+		w.Header().Set(ahttp.HeaderContentType, ct)
+		ctx := &Context{Req: ahttp.AcquireRequest(r), Res: ahttp.AcquireResponseWriter(w)}
+		ctx.route = &router.Route{Name: "get_users", ParentName: "api_v1"}
+		ctx.Res.WriteHeader(status)
+		return ctx
+	}
+
+	d := a.dumpLog
+	assert.True(t, d.IsDumpable(newCtx("/api/v1/users", 500, "application/json; charset=utf-8")))
+	assert.True(t, d.IsDumpable(newCtx("/api", 404, "application/json")))
+	assert.False(t, d.IsDumpable(newCtx("/api/v1/users", 200, "application/json")))
+	assert.False(t, d.IsDumpable(newCtx("/api/v1/users", 503, "text/html")))
+	assert.False(t, d.IsDumpable(newCtx("/apiv1/users", 500, "application/json")))
+
+	ctx := newCtx("/api/v1/users", 500, "application/json")
+	ctx.route = &router.Route{Name: "index"}
+	assert.False(t, d.IsRequestDumpable(ctx))
+
+	// invalid status codes
+	for _, v := range []string{"6xx", "abc", "500-400", "99"} {
+		_, err = parseStatusCodeRange(v)
+		assert.NotNil(t, err, v)
+	}
+	cfg, _ = config.ParseString(`filter {
+    status_codes = ["9xx"]
+  }`)
+	_, err = parseDumpLogFilter(cfg, "filter")
+	assert.Equal(t, "'filter.status_codes' has invalid value '9xx'", err.Error())
+}
+
+func TestDumpLogRedactJSON(t *testing.T) {
+	logPath := filepath.Join(testdataBaseDir(), "sample-test-dump-json.log")
+	defer ess.DeleteFiles(logPath)
+
+	a := newApp()
+	cfg, _ := config.ParseString(fmt.Sprintf(`server {
+    dump_log {
+      file = "%s"
+      request_body = true
+      response_body = true
+      max_body_size = "16b"
+      redact_fields = ["password"]
+      format = "json"
+    }
+  }`, filepath.ToSlash(logPath)))
+	a.cfg = cfg
+
+	err := a.initDumpLog()
+	assert.Nil(t, err)
+	d := a.dumpLog
+	assert.Equal(t, int64(16), d.maxBodySize)
+	assert.True(t, ess.IsSliceContainsString(d.redactHeaders, ahttp.HeaderAuthorization))
+
+	r := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/login?user=jeeva&password=welcome123",
+		strings.NewReader("user=jeeva&password=welcome123"))
+	r.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeForm.String())
+	r.Header.Set(ahttp.HeaderAuthorization, "Bearer abc123")
+	ctx := newContext(httptest.NewRecorder(), r)
+	ctx.Res.Header().Set(ahttp.HeaderContentType, ahttp.ContentTypePlainText.String())
+	ctx.Res.WriteHeader(http.StatusOK)
+	ctx.Reply().ContType = ahttp.ContentTypePlainText.String()
+
+	_, _ = io.Copy(d.CaptureBody(keyAahRequestBodyBuf, ctx), strings.NewReader("password=welcome123"))
+	_, _ = io.Copy(d.CaptureBody(keyAahResponseBodyBuf, ctx), strings.NewReader("response body is longer than limit"))
+	d.Dump(ctx)
+	assert.Nil(t, d.ring.Close(context.Background()))
+
+	b, err := ioutil.ReadFile(logPath)
+	assert.Nil(t, err)
+	var de dumpEntry
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(b), &de))
+	assert.Equal(t, "http://localhost:8080/login?password=%2A%2A%2A%2A%2A%2A&user=jeeva", de.Request.URI)
+	assert.Equal(t, settings.MaskedValue, de.Request.Header.Get(ahttp.HeaderAuthorization))
+	assert.Equal(t, "password=%2A%2A%2A%2A%2A%2A", de.Request.Body)
+	assert.True(t, de.Request.Truncated)
+	assert.Equal(t, "response body is", de.Response.Body)
+	assert.True(t, de.Response.Truncated)
+	assert.Equal(t, http.StatusOK, de.Response.Status)
+
+	// text format
+	d.format = "text"
+	ctx.Set(keyAahRequestBodyBuf, nil)
+	ctx.Set(keyAahResponseBodyBuf, nil)
+	d.Dump(ctx)
+	b, _ = ioutil.ReadFile(logPath)
+	assert.True(t, strings.Contains(string(b), "Authorization: "+settings.MaskedValue))
+	assert.False(t, strings.Contains(string(b), "Bearer abc123"))
+
+	a.cfg.SetString("server.dump_log.format", "xml")
+	assert.Equal(t, "'server.dump_log.format' value must be 'text' or 'json'", a.initDumpLog().Error())
+}
+
 type testErrorController1 struct {
 }
 
@@ -389,12 +612,12 @@ func newTestApp(t *testing.T, importPath string) *Application {
 	err := a.VFS().AddMount(a.VirtualBaseDir(), importPath)
 	assert.Nil(t, err, "not expecting any error")
 
-	a.settings.ImportPath = importPath
+	a.settingsHolder.Update(func(s *settings.Settings) { s.ImportPath = importPath })
 	err = a.initPath()
 	assert.Nil(t, err, "app initPath failure")
 	err = a.initConfig()
 	assert.Nil(t, err, "app initConfig failure")
-	err = a.settings.Refresh(a.Config())
+	err = a.settingsHolder.Refresh(a.Config())
 	assert.Nil(t, err, "app settings failure")
 	err = a.initLog()
 	assert.Nil(t, err, "app log failure")
@@ -665,10 +888,76 @@ func (s *testSiteController) FinallyText() {
 	s.Log().Info("Finally action Text interceptor")
 }
 
+func TestAppSettingsValidation(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString(`
+	server {
+		ssl { enable = "yes"; }
+		timeout {
+			read = "90"
+			request = "500ms"
+		}
+		max_header_bytes = "1zb"
+	}
+	request {
+		limits { max_form_keys = -1; }
+	}
+	render {
+		gzip { level = 10; }
+	}
+	env { dev { } }
+	`)
+	err := a.settingsHolder.Refresh(a.Config())
+	errs, ok := err.(settings.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, 5, len(errs))
+	assert.Equal(t, `5 invalid settings:
+  - 'server.ssl.enable' value 'yes' is invalid, expected boolean (true or false)
+  - 'server.timeout.read' value '90' is invalid, expected duration with unit ms, s, m, h (e.g. 30h)
+  - 'server.max_header_bytes' value '1zb' is invalid, expected size with unit (e.g. 512kb, 5mb)
+  - 'request.limits.max_form_keys' value '-1' is invalid, expected integer >= 0
+  - 'render.gzip.level' value '10' is invalid, expected integer between 1 and 9`, err.Error())
+
+	a.cfg, _ = config.ParseString(`server { timeout { write = 90; } } env { dev { } }`)
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.timeout.write' value '90' is invalid, expected duration with unit ms, s, m, h (e.g. 30h)", err.Error())
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Test util methods
 //______________________________________________________________________________
 
+// newWebApp1TestServer method creates the test server of testdata `webapp1`.
+func newWebApp1TestServer(t *testing.T) *testServer {
+	return newTestServer(t, filepath.Join(testdataBaseDir(), "webapp1"))
+}
+
+// newWebApp1TestApp method creates the test application of testdata `webapp1`.
+func newWebApp1TestApp(t *testing.T) *Application {
+	return newTestApp(t, filepath.Join(testdataBaseDir(), "webapp1"))
+}
+
+// mergeTestConfig method parses the given config string and merges it into
+// the application config.
+func mergeTestConfig(a *Application, cfgStr string) error {
+	cfg, err := config.ParseString(cfgStr)
+	if err != nil {
+		return err
+	}
+	return a.Config().Merge(cfg)
+}
+
+// setTestConfig method parses the given config string and sets it as the
+// application config.
+func setTestConfig(a *Application, cfgStr string) error {
+	cfg, err := config.ParseString(cfgStr)
+	if err != nil {
+		return err
+	}
+	a.cfg = cfg
+	return nil
+}
+
 func testdataBaseDir() string {
 	wd, _ := os.Getwd()
 	if idx := strings.Index(wd, "testdata"); idx > 0 {
@@ -687,3 +976,123 @@ func responseBody(res *http.Response) string {
 	io.Copy(buf, body)
 	return buf.String()
 }
+
+func TestAppSettingsDumpAndDiff(t *testing.T) {
+	a := newApp()
+	a.cfg, _ = config.ParseString(`
+	name = "dumpapp"
+	server {
+		port = "8080"
+		timeout { read = "90s"; }
+	}
+	security {
+		session { sign_key = "secretvalue"; }
+	}
+	env {
+		active = "dev"
+		dev {
+			server { port = "9090"; }
+		}
+		prod {
+			server { timeout { read = "30s"; } }
+			security { session { sign_key = "prodsecret"; } }
+		}
+	}
+	`)
+	_ = os.Setenv("AAHDUMPTEST_NAME", "envapp")
+	defer os.Unsetenv("AAHDUMPTEST_NAME")
+	_, err := a.Config().ApplyEnvOverrides("AAHDUMPTEST")
+	assert.Nil(t, err)
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, a.DumpConfig(buf, "hocon"))
+	assert.Equal(t, `env {
+  active = "dev"
+}
+name = "envapp"
+security {
+  session {
+    sign_key = "******"
+  }
+}
+server {
+  port = "9090"
+  timeout {
+    read = "90s"
+  }
+}
+`, buf.String())
+
+	buf.Reset()
+	assert.Nil(t, a.DumpConfig(buf, "json"))
+	assert.True(t, strings.Contains(buf.String(), `"port": "9090"`))
+
+	err = a.DumpConfig(buf, "yaml")
+	assert.Equal(t, "settings: unsupported dump format 'yaml', supported formats are json, hocon", err.Error())
+
+	diffs, err := a.DiffConfigProfiles("dev", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(diffs))
+	assert.Equal(t, "server.port: 9090 => 8080", diffs[0].String())
+	assert.Equal(t, "server.timeout.read: 90s => 30s", diffs[1].String())
+
+	_, err = a.DiffConfigProfiles("dev", "staging")
+	assert.Equal(t, "settings: profile doesn't exists: staging", err.Error())
+}
+
+type vaultResolver struct{}
+
+func (vaultResolver) Supports(v string) bool { return strings.HasPrefix(v, "vault://") }
+func (vaultResolver) Resolve(v string) (string, error) {
+	return "resolved-" + strings.TrimPrefix(v, "vault://"), nil
+}
+
+func TestAppSettingsSecrets(t *testing.T) {
+	key := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	r, err := settings.NewAESGCMEnvResolver("AAHSECRETTEST_KEY")
+	assert.Equal(t, "environment variable 'AAHSECRETTEST_KEY' does not exists", err.Error())
+	assert.Nil(t, r)
+
+	_ = os.Setenv("AAHSECRETTEST_KEY", key)
+	defer os.Unsetenv("AAHSECRETTEST_KEY")
+	r, err = settings.NewAESGCMEnvResolver("AAHSECRETTEST_KEY")
+	assert.Nil(t, err)
+	encrypted, err := r.Encrypt("s3cr3t")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "ENC("))
+
+	a := newApp()
+	a.AddSecretResolver(vaultResolver{})
+	a.cfg, _ = config.ParseString(fmt.Sprintf(`
+	runtime { secrets { key_env = "AAHSECRETTEST_KEY"; } }
+	datasource {
+		dsn = "%s"
+		token = "vault://db/token"
+	}
+	env {
+		dev {
+			datasource { hosts = ["%s", "localhost"]; }
+		}
+	}
+	`, encrypted, encrypted))
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+	assert.Equal(t, "s3cr3t", a.Config().StringDefault("datasource.dsn", ""))
+	assert.Equal(t, "resolved-db/token", a.Config().StringDefault("datasource.token", ""))
+	hosts, _ := a.Config().StringList("datasource.hosts")
+	assert.Equal(t, []string{"s3cr3t", "localhost"}, hosts)
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, a.DumpConfig(buf, "json"))
+	assert.False(t, strings.Contains(buf.String(), "s3cr3t"))
+
+	// key not configured
+	a = newApp()
+	a.cfg, _ = config.ParseString(fmt.Sprintf(`
+	runtime { secrets { key_env = "AAHSECRETTEST_NOKEY"; } }
+	datasource { dsn = "%s"; }
+	env { dev { } }
+	`, encrypted))
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'datasource.dsn' value is encrypted, however secret key is not configured", err.Error())
+}
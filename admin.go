@@ -0,0 +1,105 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/security/adminauth"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initAdmin method initializes the access control of framework admin
+// endpoints from config `security.admin_auth { ... }` and the effective
+// config dump endpoint from config `runtime.config_dump { ... }`. Admin
+// endpoints are not served without `security.admin_auth`.
+//
+//	runtime {
+//	  config_dump {
+//	    enable = true
+//	    path = "/_aah/config"
+//	  }
+//	}
+func (a *Application) initAdmin() error {
+	guard, err := adminauth.New(a.Config())
+	if err != nil {
+		return err
+	}
+
+	keyPrefix := "runtime.config_dump"
+	configDumpPath := ""
+	if a.Config().BoolDefault(keyPrefix+".enable", false) {
+		if guard == nil {
+			return fmt.Errorf("'%s' requires 'security.admin_auth' config", keyPrefix)
+		}
+		configDumpPath = a.Config().StringDefault(keyPrefix+".path", "/_aah/config")
+		if !strings.HasPrefix(configDumpPath, "/") {
+			return fmt.Errorf("'%s.path' value must begin with '/'", keyPrefix)
+		}
+	}
+
+	a.adminGuard, a.configDumpPath = guard, configDumpPath
+	return nil
+}
+
+// verifyAdmin method verifies the admin endpoint request using
+// `security.admin_auth` guard, it replies `401` or `403` on failure.
+// Request is denied if the guard is not configured. Returns true if request
+// is allowed otherwise false.
+func (a *Application) verifyAdmin(ctx *Context) bool {
+	if a.adminGuard == nil {
+		ctx.Reply().Forbidden().Error(newError(ErrAccessDenied, http.StatusForbidden))
+		return false
+	}
+
+	switch err := a.adminGuard.Verify(ctx.Req.Unwrap()); err {
+	case nil:
+		return true
+	case adminauth.ErrTokenInvalid:
+		ctx.Log().Warnf("Admin endpoint authentication failed, Path: %s", ctx.Req.Path)
+		ctx.Reply().Header(ahttp.HeaderWWWAuthenticate, "Bearer").
+			Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
+	default:
+		ctx.Log().Warnf("Admin endpoint access denied, Path: %s, Reason: %v", ctx.Req.Path, err)
+		ctx.Reply().Forbidden().Error(newError(ErrAccessDenied, http.StatusForbidden))
+	}
+	return false
+}
+
+// serveConfigDump method replies the effective config dump on admin
+// endpoint, format is chosen via query parameter `format`, default is `json`.
+// Returns true if request is served otherwise false.
+func (a *Application) serveConfigDump(ctx *Context) bool {
+	if len(a.configDumpPath) == 0 || ctx.Req.Path != a.configDumpPath || ctx.Req.Method != ahttp.MethodGet {
+		return false
+	}
+	if !a.verifyAdmin(ctx) {
+		return true
+	}
+
+	format := ctx.Req.QueryValue("format")
+	if len(format) == 0 {
+		format = "json"
+	}
+	buf := new(bytes.Buffer)
+	if err := a.DumpConfig(buf, format); err != nil {
+		ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, err.Error()))
+		return true
+	}
+
+	contentType := ahttp.ContentTypeJSON.String()
+	if format != "json" {
+		contentType = ahttp.ContentTypePlainText.String()
+	}
+	ctx.Reply().Ok().ContentType(contentType).Binary(buf.Bytes())
+	return true
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminConfigDump(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Admin Config Dump]: %s", ts.URL)
+
+	// not enabled
+	resp, err := http.Get(ts.URL + "/_aah/config")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	runtime {
+	  config_dump {
+	    enable = true
+	  }
+	}
+	security {
+	  admin_auth {
+	    tokens = ["admintoken"]
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initAdmin())
+
+	resp, err = http.Get(ts.URL + "/_aah/config")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "Bearer", resp.Header.Get(ahttp.HeaderWWWAuthenticate))
+
+	get := func(query string) *http.Response {
+		req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/_aah/config"+query, nil)
+		req.Header.Set(ahttp.HeaderAuthorization, "Bearer admintoken")
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	resp = get("")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ahttp.ContentTypeJSON.String(), resp.Header.Get(ahttp.HeaderContentType))
+	var dump map[string]interface{}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&dump))
+	assert.True(t, len(dump) > 0)
+
+	resp = get("?format=yaml")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAdminInitErrors(t *testing.T) {
+	a := newApp()
+
+	// admin endpoint without admin auth
+	assert.Nil(t, setTestConfig(a, `
+	runtime {
+	  config_dump {
+	    enable = true
+	  }
+	}
+	`))
+	err := a.initAdmin()
+	assert.Equal(t, "'runtime.config_dump' requires 'security.admin_auth' config", err.Error())
+
+	assert.Nil(t, setTestConfig(a, `
+	runtime {
+	  config_dump {
+	    enable = true
+	    path = "config"
+	  }
+	}
+	security {
+	  admin_auth {
+	    allow_ips = ["127.0.0.1"]
+	  }
+	}
+	`))
+	err = a.initAdmin()
+	assert.Equal(t, "'runtime.config_dump.path' value must begin with '/'", err.Error())
+}
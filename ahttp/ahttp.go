@@ -88,10 +88,18 @@ func ReleaseResponseWriter(aw ResponseWriter) {
 	}
 }
 
-// WrapGzipWriter wraps `ahttp.ResponseWriter` with Gzip writer.
+// WrapGzipWriter wraps `ahttp.ResponseWriter` with Gzip writer of
+// compression level `ahttp.GzipLevel`.
 func WrapGzipWriter(w io.Writer) ResponseWriter {
+	return WrapGzipWriterLevel(w, GzipLevel)
+}
+
+// WrapGzipWriterLevel wraps `ahttp.ResponseWriter` with Gzip writer of given
+// compression level, invalid level falls back to `gzip.DefaultCompression`.
+func WrapGzipWriterLevel(w io.Writer, level int) ResponseWriter {
 	gr := grPool.Get().(*GzipResponse)
-	gr.gw = acquireGzipWriter(w)
+	gr.level = validGzipLevel(level)
+	gr.gw = acquireGzipWriter(w, gr.level)
 	gr.r = w.(*Response)
 	return gr
 }
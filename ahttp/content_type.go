@@ -45,6 +45,13 @@ var (
 
 	// ContentTypeCSSText content type for stylesheets/CSS.
 	ContentTypeCSSText = parseMediaType("text/css; charset=utf-8")
+
+	// ContentTypeHALJSON HAL (Hypertext Application Language) content type.
+	ContentTypeHALJSON = parseMediaType("application/hal+json; charset=utf-8")
+
+	// ContentTypeJSONAPI JSON:API content type, media type parameters are
+	// not allowed by the specification.
+	ContentTypeJSONAPI = parseMediaType("application/vnd.api+json")
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
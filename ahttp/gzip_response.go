@@ -14,11 +14,14 @@ import (
 )
 
 var (
-	// GzipLevel holds value from app config.
+	// GzipLevel holds the compression level used by `WrapGzipWriter`.
 	GzipLevel int
 
 	grPool = &sync.Pool{New: func() interface{} { return &GzipResponse{} }}
-	gwPool = &sync.Pool{}
+
+	// gwPools holds the gzip writers by compression level, index is the
+	// level offset by `gzip.HuffmanOnly`
+	gwPools [gzip.BestCompression - gzip.HuffmanOnly + 1]sync.Pool
 
 	// interface compliance
 	_ http.CloseNotifier = (*GzipResponse)(nil)
@@ -36,8 +39,9 @@ var (
 // GzipResponse extends `ahttp.Response` to provides gzip compression for response
 // bytes to the underlying response.
 type GzipResponse struct {
-	r  *Response
-	gw *gzip.Writer
+	r     *Response
+	gw    *gzip.Writer
+	level int
 }
 
 // Status method returns HTTP response status code. If status is not yet written
@@ -116,15 +120,15 @@ func (g *GzipResponse) Push(target string, opts *http.PushOptions) error {
 // releaseGzipResponse method resets and puts the gzip response into pool.
 func releaseGzipResponse(gw *GzipResponse) {
 	_ = gw.Close()
-	gwPool.Put(gw.gw)
+	gzipWriterPool(gw.level).Put(gw.gw)
 	releaseResponse(gw.r)
 	grPool.Put(gw)
 }
 
-func acquireGzipWriter(w io.Writer) *gzip.Writer {
-	gw := gwPool.Get()
+func acquireGzipWriter(w io.Writer, level int) *gzip.Writer {
+	gw := gzipWriterPool(level).Get()
 	if gw == nil {
-		if ngw, err := gzip.NewWriterLevel(w, GzipLevel); err == nil {
+		if ngw, err := gzip.NewWriterLevel(w, level); err == nil {
 			return ngw
 		}
 		return nil
@@ -133,3 +137,16 @@ func acquireGzipWriter(w io.Writer) *gzip.Writer {
 	ngw.Reset(w)
 	return ngw
 }
+
+func gzipWriterPool(level int) *sync.Pool {
+	return &gwPools[level-gzip.HuffmanOnly]
+}
+
+// validGzipLevel method returns the given level if it's valid compression
+// level otherwise `gzip.DefaultCompression`.
+func validGzipLevel(level int) int {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		return gzip.DefaultCompression
+	}
+	return level
+}
@@ -90,7 +90,7 @@ func TestHTTPGzipHijack(t *testing.T) {
 		GzipLevel = gzip.BestSpeed
 		for i := 0; i < 5; i++ {
 			ngw, _ := gzip.NewWriterLevel(w, GzipLevel)
-			gwPool.Put(ngw)
+			gzipWriterPool(GzipLevel).Put(ngw)
 		}
 		gw := WrapGzipWriter(AcquireResponseWriter(w))
 
@@ -126,3 +126,22 @@ func gzipCallAndValidate(t *testing.T, handler http.HandlerFunc) []byte {
 	bytes, _ := ioutil.ReadAll(resp.Body)
 	return bytes
 }
+
+func TestHTTPGzipWriterLevel(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		gw := WrapGzipWriterLevel(AcquireResponseWriter(w), gzip.BestCompression)
+		defer ReleaseResponseWriter(gw)
+		assert.Equal(t, gzip.BestCompression, gw.(*GzipResponse).level)
+
+		gw.Header().Set(HeaderContentEncoding, "gzip")
+		gw.WriteHeader(http.StatusOK)
+		_, _ = gw.Write([]byte("aah framework - testing gzip writer level"))
+	}
+
+	resp := gzipCallAndValidate(t, handler)
+	assert.Equal(t, "aah framework - testing gzip writer level", string(resp))
+
+	assert.Equal(t, gzip.DefaultCompression, validGzipLevel(10))
+	assert.Equal(t, gzip.DefaultCompression, validGzipLevel(-3))
+	assert.Equal(t, gzip.BestSpeed, validGzipLevel(gzip.BestSpeed))
+}
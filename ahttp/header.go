@@ -34,17 +34,21 @@ const (
 	HeaderAccessControlRequestMethod      = "Access-Control-Request-Method"
 	HeaderAge                             = "Age"
 	HeaderAllow                           = "Allow"
+	HeaderAltSvc                          = "Alt-Svc"
 	HeaderAuthorization                   = "Authorization"
 	HeaderCacheControl                    = "Cache-Control"
 	HeaderConnection                      = "Connection"
 	HeaderContentDisposition              = "Content-Disposition"
 	HeaderContentEncoding                 = "Content-Encoding"
+	HeaderContentLanguage                 = "Content-Language"
 	HeaderContentLength                   = "Content-Length"
+	HeaderContentRange                    = "Content-Range"
 	HeaderContentType                     = "Content-Type"
 	HeaderContentSecurityPolicy           = "Content-Security-Policy"
 	HeaderContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
 	HeaderCookie                          = "Cookie"
 	HeaderDate                            = "Date"
+	HeaderExpect                          = "Expect"
 	HeaderETag                            = "Etag"
 	HeaderExpires                         = "Expires"
 	HeaderHost                            = "Host"
@@ -55,6 +59,7 @@ const (
 	HeaderIfUnmodifiedSince               = "If-Unmodified-Since"
 	HeaderKeepAlive                       = "Keep-Alive"
 	HeaderLastModified                    = "Last-Modified"
+	HeaderLink                            = "Link"
 	HeaderLocation                        = "Location"
 	HeaderOrigin                          = "Origin"
 	HeaderMethod                          = "Method"
@@ -63,10 +68,12 @@ const (
 	HeaderReferer                         = "Referer"
 	HeaderReferrerPolicy                  = "Referrer-Policy"
 	HeaderRetryAfter                      = "Retry-After"
+	HeaderSecFetchSite                    = "Sec-Fetch-Site"
 	HeaderServer                          = "Server"
 	HeaderSetCookie                       = "Set-Cookie"
 	HeaderStatus                          = "Status"
 	HeaderStrictTransportSecurity         = "Strict-Transport-Security"
+	HeaderTrailer                         = "Trailer"
 	HeaderTransferEncoding                = "Transfer-Encoding"
 	HeaderUpgrade                         = "Upgrade"
 	HeaderUserAgent                       = "User-Agent"
@@ -92,6 +99,12 @@ const (
 	HeaderXXSSProtection                  = "X-Xss-Protection"
 )
 
+// HTTP Header names of CORS Private Network Access (PNA)
+const (
+	HeaderAccessControlAllowPrivateNetwork   = "Access-Control-Allow-Private-Network"
+	HeaderAccessControlRequestPrivateNetwork = "Access-Control-Request-Private-Network"
+)
+
 type (
 	// AcceptSpec used for HTTP Accept, Accept-Language, Accept-Encoding header
 	// value and it's quality. Implementation follows the specification of RFC7231
@@ -5,6 +5,8 @@
 package ahttp
 
 import (
+	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -134,6 +136,22 @@ func (r *Request) ClientIP() string {
 	return ClientIP(r.Unwrap())
 }
 
+// ClientCertificate method returns the verified TLS client certificate of
+// mutual TLS (mTLS) connection, leaf certificate of the first verified chain.
+// Returns nil if connection is not TLS or client certificate is not verified,
+// see `server.ssl.client_auth { ... }`.
+func (r *Request) ClientCertificate() *x509.Certificate {
+	if r.raw == nil || r.raw.TLS == nil {
+		return nil
+	}
+	for _, chain := range r.raw.TLS.VerifiedChains {
+		if len(chain) > 0 {
+			return chain[0]
+		}
+	}
+	return nil
+}
+
 // Cookie method returns a named cookie from HTTP request otherwise error.
 func (r *Request) Cookie(name string) (*http.Cookie, error) {
 	return r.Unwrap().Cookie(name)
@@ -254,6 +272,20 @@ func (r *Request) Body() io.ReadCloser {
 	return r.Unwrap().Body
 }
 
+// Context method returns the request context. It's cancelled when the client
+// connection closes or request timeout elapses, pass it to database and
+// outbound calls.
+func (r *Request) Context() context.Context {
+	return r.Unwrap().Context()
+}
+
+// SetContext method sets the given context into the request, typically derived
+// from `Request.Context()`.
+func (r *Request) SetContext(ctx context.Context) *Request {
+	r.raw = r.raw.WithContext(ctx)
+	return r
+}
+
 // Unwrap method returns the underlying *http.Request instance of Go HTTP server,
 // direct interaction with raw object is not encouraged. Use it appropriately.
 func (r *Request) Unwrap() *http.Request {
@@ -6,7 +6,10 @@ package ahttp
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"mime/multipart"
 	"net/http"
@@ -15,6 +18,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/essentials"
 	"github.com/stretchr/testify/assert"
@@ -204,6 +208,34 @@ func TestRequestSchemeDerived(t *testing.T) {
 	assert.Equal(t, "http", Scheme(req))
 }
 
+func TestRequestClientCertificate(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/", nil)
+	assert.Nil(t, AcquireRequest(req).ClientCertificate())
+
+	// TLS without verified client certificate
+	req.TLS = &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "unverified"}}},
+	}
+	assert.Nil(t, AcquireRequest(req).ClientCertificate())
+
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: "orders-service"}}
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{leaf, {Subject: pkix.Name{CommonName: "ca"}}}}
+	assert.Equal(t, "orders-service", AcquireRequest(req).ClientCertificate().Subject.CommonName)
+}
+
+func TestRequestContextDeadline(t *testing.T) {
+	req := AcquireRequest(httptest.NewRequest("GET", "http://localhost:8080/welcome.html", nil))
+	_, ok := req.Context().Deadline()
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(req.Context(), 2*time.Second)
+	defer cancel()
+	assert.True(t, req.SetContext(ctx) == req)
+	_, ok = req.Context().Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, ctx, req.Unwrap().Context())
+}
+
 func TestRequestSaveFile(t *testing.T) {
 	aahReq, path, teardown := setUpRequestSaveFile(t)
 	defer teardown()
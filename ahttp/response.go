@@ -51,6 +51,7 @@ type Response struct {
 	status       int
 	wroteStatus  bool
 	bytesWritten int
+	firstByteFns []func()
 }
 
 // Status method returns HTTP response status code. If status is not yet written
@@ -65,6 +66,9 @@ func (r *Response) WriteHeader(code int) {
 		r.status = code
 		r.wroteStatus = true
 		r.w.WriteHeader(code)
+		for _, fn := range r.firstByteFns {
+			fn()
+		}
 	}
 }
 
@@ -86,6 +90,14 @@ func (r *Response) BytesWritten() int {
 	return r.bytesWritten
 }
 
+// OnFirstByte method adds the callback func, it's called once right after
+// the response status is written i.e. when the first byte goes on the wire.
+func (r *Response) OnFirstByte(fn func()) {
+	if fn != nil {
+		r.firstByteFns = append(r.firstByteFns, fn)
+	}
+}
+
 // Close method closes the writer if possible.
 // TODO for removal
 func (r *Response) Close() error {
@@ -138,6 +150,7 @@ func (r *Response) Reset() {
 	r.status = 0
 	r.bytesWritten = 0
 	r.wroteStatus = false
+	r.firstByteFns = nil
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -60,6 +60,30 @@ func TestHTTPMultipleStatusWritten(t *testing.T) {
 	callAndValidate(t, handler, "aah framework mutiple status written")
 }
 
+func TestHTTPResponseOnFirstByte(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		writer := AcquireResponseWriter(w)
+		defer ReleaseResponseWriter(writer)
+
+		var calls []int
+		res := writer.(*Response)
+		res.OnFirstByte(nil)
+		res.OnFirstByte(func() { calls = append(calls, res.Status()) })
+		assert.Nil(t, calls)
+
+		_, _ = writer.Write([]byte("aah framework "))
+		_, _ = writer.Write([]byte("first byte"))
+		writer.WriteHeader(http.StatusAccepted)
+		assert.Equal(t, []int{http.StatusOK}, calls)
+
+		res.Reset()
+		assert.Nil(t, res.firstByteFns)
+		res.w = w
+	}
+
+	callAndValidate(t, handler, "aah framework first byte")
+}
+
 func TestHTTPHijackCall(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		writer := AcquireResponseWriter(w)
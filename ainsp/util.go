@@ -77,15 +77,38 @@ func isPkgAliasExists(importPaths map[string]string, pkgAlias string) bool {
 	return found
 }
 
+// isActionResults method returns true if method does not have results or
+// returns `error` or `(T, error)` otherwise false.
+func isActionResults(results *ast.FieldList) bool {
+	if results == nil {
+		return true
+	}
+
+	var types []ast.Expr
+	for _, f := range results.List {
+		for i := 0; i < len(f.Names) || (i == 0 && len(f.Names) == 0); i++ {
+			types = append(types, f.Type)
+		}
+	}
+	if len(types) == 0 {
+		return true
+	}
+	if len(types) > 2 {
+		return false
+	}
+	id, ok := types[len(types)-1].(*ast.Ident)
+	return ok && id.Name == "error"
+}
+
 func processMethods(pkg *packageInfo, routeMethods map[string]map[string]uint8, decl ast.Decl, imports map[string]string) {
 	fn, ok := decl.(*ast.FuncDecl)
 
 	// Do not process if these met:
 	// 		1. does not have receiver, it means package function/method
 	// 		2. method is not exported
-	// 		3. method returns result
+	// 		3. method returns result other than `error` or `(T, error)`
 	if !ok || fn.Recv == nil || !fn.Name.IsExported() ||
-		fn.Type.Results != nil {
+		!isActionResults(fn.Type.Results) {
 		return
 	}
 
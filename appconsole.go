@@ -0,0 +1,189 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"aahframe.work/internal/settings"
+)
+
+// consoleBuiltins are the interactive console built-in command names with
+// its usage.
+var consoleBuiltins = [][2]string{
+	{"help", "Shows the list of commands"},
+	{"env", "Shows the application name, version, profile and base directory"},
+	{"config", "Shows the config value of given key, e.g.: config server.port"},
+	{"keys", "Shows the config keys of given path, e.g.: keys server"},
+	{"routes", "Shows the application routes by domain"},
+	{"exit", "Exits the console, also 'quit'"},
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// ConsoleCommand is the command of application interactive console, it's
+// invoked as `<name> [args...]` from the console. Use it to invoke the
+// application services for operational one-offs.
+type ConsoleCommand struct {
+	Name   string
+	Usage  string
+	Action func(w io.Writer, args []string) error
+}
+
+// AddConsoleCommand method adds the commands into application interactive
+// console, started via `<app-binary> console`. Console boots the
+// application with config, modules and `OnInit`, `OnStart` events without
+// HTTP server.
+//
+//	aah.App().AddConsoleCommand(aah.ConsoleCommand{
+//		Name:  "reindex",
+//		Usage: "Reindex the products of given category",
+//		Action: func(w io.Writer, args []string) error {
+//			n, err := products.Reindex(args[0])
+//			fmt.Fprintf(w, "%d products reindexed\n", n)
+//			return err
+//		},
+//	})
+func (a *Application) AddConsoleCommand(cmds ...ConsoleCommand) error {
+	for _, cmd := range cmds {
+		name := strings.ToLower(strings.TrimSpace(cmd.Name))
+		if len(name) == 0 || strings.ContainsAny(name, " \t") {
+			return fmt.Errorf("aah: console command name '%s' is invalid", name)
+		}
+		if cmd.Action == nil {
+			return fmt.Errorf("aah: console command '%s' action is nil", name)
+		}
+		if name == "quit" || isConsoleBuiltin(name) {
+			return fmt.Errorf("aah: reserved console command name '%s' cannot be used", name)
+		}
+		if a.consoleCommand(name) != nil {
+			return fmt.Errorf("aah: console command name '%s' already exists", name)
+		}
+		cmd.Name = name
+		a.consoleCmds = append(a.consoleCmds, cmd)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// runConsole method reads the commands from given reader line by line and
+// writes the result into given writer until `exit`, `quit` or end of input.
+// Command error or panic is written and console continues.
+func (a *Application) runConsole(r io.Reader, w io.Writer) error {
+	fmt.Fprintf(w, "%s console, profile: %s. Type 'help' for commands.\n", a.Name(), a.EnvProfile())
+	scanner := bufio.NewScanner(r)
+	for {
+		fmt.Fprintf(w, "%s> ", a.Name())
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return scanner.Err()
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(fields[0])
+		if name == "exit" || name == "quit" {
+			return nil
+		}
+		if err := a.execConsoleCommand(w, name, fields[1:]); err != nil {
+			fmt.Fprintf(w, "error: %v\n", err)
+		}
+	}
+}
+
+func (a *Application) execConsoleCommand(w io.Writer, name string, args []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	switch name {
+	case "help":
+		a.writeConsoleHelp(w)
+	case "env":
+		fmt.Fprintf(w, "%-12s: %s\n", "Name", a.Name())
+		fmt.Fprintf(w, "%-12s: %s\n", "Version", a.BuildInfo().Version)
+		fmt.Fprintf(w, "%-12s: %s\n", "Profile", a.EnvProfile())
+		fmt.Fprintf(w, "%-12s: %s\n", "Base Dir", a.BaseDir())
+	case "config":
+		if len(args) != 1 {
+			return errors.New("usage: config <key>")
+		}
+		v, found := a.Config().Get(args[0])
+		if !found {
+			return fmt.Errorf("config key '%s' does not exists", args[0])
+		}
+		if a.settings().IsSecretKey(args[0]) {
+			v = settings.MaskedValue
+		}
+		fmt.Fprintf(w, "%v\n", v)
+	case "keys":
+		keys := a.Config().Keys()
+		if len(args) > 0 {
+			keys = a.Config().KeysByPath(args[0])
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintln(w, k)
+		}
+	case "routes":
+		if a.Router() == nil {
+			return errors.New("router is not initialized")
+		}
+		for _, d := range a.Router().Domains {
+			fmt.Fprintf(w, "Domain: %s\n", d.Key)
+			for _, r := range d.Routes() {
+				fmt.Fprintf(w, "    %-8s %-40s %s\n", r.Method, r.Path, r.Name)
+			}
+		}
+	default:
+		cmd := a.consoleCommand(name)
+		if cmd == nil {
+			return fmt.Errorf("unknown command '%s', type 'help' for commands", name)
+		}
+		return cmd.Action(w, args)
+	}
+	return nil
+}
+
+func (a *Application) writeConsoleHelp(w io.Writer) {
+	fmt.Fprintln(w, "Commands:")
+	for _, b := range consoleBuiltins {
+		fmt.Fprintf(w, "    %-12s %s\n", b[0], b[1])
+	}
+	for _, c := range a.consoleCmds {
+		fmt.Fprintf(w, "    %-12s %s\n", c.Name, c.Usage)
+	}
+}
+
+func (a *Application) consoleCommand(name string) *ConsoleCommand {
+	for i := range a.consoleCmds {
+		if a.consoleCmds[i].Name == name {
+			return &a.consoleCmds[i]
+		}
+	}
+	return nil
+}
+
+func isConsoleBuiltin(name string) bool {
+	for _, b := range consoleBuiltins {
+		if b[0] == name {
+			return true
+		}
+	}
+	return false
+}
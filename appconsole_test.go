@@ -0,0 +1,69 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppConsole(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+	a := ts.app
+
+	err := a.AddConsoleCommand(ConsoleCommand{
+		Name:  "Greet",
+		Usage: "Greets the given name",
+		Action: func(w io.Writer, args []string) error {
+			if len(args) == 0 {
+				return errors.New("name is required")
+			}
+			fmt.Fprintf(w, "hello %s\n", args[0])
+			return nil
+		},
+	}, ConsoleCommand{
+		Name:   "boom",
+		Action: func(w io.Writer, args []string) error { panic("boom") },
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, errors.New("aah: console command name '' is invalid"),
+		a.AddConsoleCommand(ConsoleCommand{Name: " "}))
+	assert.Equal(t, errors.New("aah: console command 'nop' action is nil"),
+		a.AddConsoleCommand(ConsoleCommand{Name: "nop"}))
+	assert.Equal(t, errors.New("aah: reserved console command name 'routes' cannot be used"),
+		a.AddConsoleCommand(ConsoleCommand{Name: "routes", Action: func(io.Writer, []string) error { return nil }}))
+	assert.Equal(t, errors.New("aah: console command name 'greet' already exists"),
+		a.AddConsoleCommand(ConsoleCommand{Name: "greet", Action: func(io.Writer, []string) error { return nil }}))
+
+	in := strings.NewReader("help\n\nenv\nconfig server.port\nconfig unknown.key\nkeys server.access_log\n" +
+		"routes\ngreet aah\ngreet\nboom\nunknown\nexit\ngreet never\n")
+	var out bytes.Buffer
+	assert.Nil(t, a.runConsole(in, &out))
+
+	result := out.String()
+	t.Log(result)
+	assert.True(t, strings.Contains(result, "greet        Greets the given name"))
+	assert.True(t, strings.Contains(result, "Profile     : dev"))
+	assert.True(t, strings.Contains(result, "config key 'unknown.key' does not exists"))
+	assert.True(t, strings.Contains(result, "Domain: localhost"))
+	assert.True(t, strings.Contains(result, "hello aah"))
+	assert.True(t, strings.Contains(result, "error: name is required"))
+	assert.True(t, strings.Contains(result, "error: panic: boom"))
+	assert.True(t, strings.Contains(result, "error: unknown command 'unknown'"))
+	assert.False(t, strings.Contains(result, "hello never"))
+
+	// end of input
+	out.Reset()
+	assert.Nil(t, a.runConsole(strings.NewReader("env"), &out))
+	assert.True(t, strings.Contains(out.String(), "Name        : webapp1"))
+}
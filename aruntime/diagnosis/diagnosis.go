@@ -21,6 +21,7 @@ import (
 
 	"aahframe.work/config"
 	"aahframe.work/log"
+	"aahframe.work/security/adminauth"
 )
 
 // New method creates new Diagnosis instance to collection various
@@ -33,7 +34,9 @@ func New(appName string, appCfg *config.Config, al log.Loggerer) (*Diagnosis, er
 	mode := appCfg.StringDefault("runtime.diagnosis.mode", "http")
 	d := &Diagnosis{Config: appCfg, Mode: strings.ToLower(mode), appName: appName, log: al}
 	if d.IsHTTPMode() {
-		d.createHTTPServer()
+		if err := d.createHTTPServer(); err != nil {
+			return nil, err
+		}
 	} else {
 		d.createFiles()
 	}
@@ -52,6 +55,8 @@ type Diagnosis struct {
 	log                log.Loggerer
 	pathPrefix         string
 	serverWriteTimeout time.Duration
+	sslCert            string
+	sslKey             string
 }
 
 // IsHTTPMode method returns true if diagnosis enabled in HTTP mode otherwise false.
@@ -63,7 +68,13 @@ func (d *Diagnosis) IsHTTPMode() bool {
 // given diagnosis configuration on application startup.
 func (d *Diagnosis) Run() {
 	if d.IsHTTPMode() {
-		if err := d.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if d.server.TLSConfig != nil {
+			err = d.server.ListenAndServeTLS(d.sslCert, d.sslKey)
+		} else {
+			err = d.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			d.log.Error(err)
 		}
 		return
@@ -81,7 +92,13 @@ func (d *Diagnosis) Stop() {
 	// stop the profilers for file mode and close the file descriptors
 }
 
-func (d *Diagnosis) createHTTPServer() {
+func (d *Diagnosis) createHTTPServer() error {
+	// Admin endpoint access control, refer to `security.admin_auth`
+	guard, err := adminauth.New(d.Config)
+	if err != nil {
+		return err
+	}
+
 	d.pathPrefix = "/diagnosis"
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -94,16 +111,25 @@ func (d *Diagnosis) createHTTPServer() {
 	mux.HandleFunc(d.pathPrefix+"/pprof/profile", d.cpuProfileHandler)
 	mux.HandleFunc(d.pathPrefix+"/pprof/symbol", d.symbolHandler)
 	mux.HandleFunc(d.pathPrefix+"/pprof/trace", d.traceHandler)
-	var err error
 	d.serverWriteTimeout, err = time.ParseDuration(d.Config.StringDefault("runtime.diagnosis.http.timeout.write", "2m"))
 	if err != nil {
 		d.serverWriteTimeout = time.Minute * 2
 	}
 	d.server = &http.Server{
 		Addr:         d.Config.StringDefault("runtime.diagnosis.http.address", ":7070"),
-		Handler:      mux,
+		Handler:      guard.Handler(mux),
 		WriteTimeout: d.serverWriteTimeout,
+		TLSConfig:    guard.TLSConfig(),
 	}
+
+	if d.server.TLSConfig != nil {
+		d.sslCert = d.Config.StringDefault("runtime.diagnosis.http.ssl.cert", "")
+		d.sslKey = d.Config.StringDefault("runtime.diagnosis.http.ssl.key", "")
+		if len(d.sslCert) == 0 || len(d.sslKey) == 0 {
+			return errors.New("diagnosis: 'runtime.diagnosis.http.ssl.cert' and 'runtime.diagnosis.http.ssl.key' are required for mTLS")
+		}
+	}
+	return nil
 }
 
 func (d *Diagnosis) createFiles() {
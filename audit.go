@@ -0,0 +1,522 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+	"aahframe.work/log"
+)
+
+// KeyAuditEntity key is used to set the audited entity identifier of the
+// request, for e.g.: `ctx.Set(aah.KeyAuditEntity, "order:1001")`.
+const KeyAuditEntity = "_aahAuditEntity"
+
+// Audit record result values.
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// AddAuditSink method adds the audit sink for given name, for e.g.: DB
+// table, Kafka topic, etc. Then configure `name` parameter in the
+// configuration as `server.audit.sinks = ["name"]`. Built-in sinks are
+// `file` and `memory`.
+func (a *Application) AddAuditSink(name string, sink AuditSink) error {
+	if sink == nil {
+		return errors.New("aah: audit sink is nil")
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if _, found := a.auditSinks[name]; found || name == "file" || name == "memory" {
+		return fmt.Errorf("aah: audit sink '%s' is already added", name)
+	}
+	if a.auditSinks == nil {
+		a.auditSinks = make(map[string]AuditSink)
+	}
+	a.auditSinks[name] = sink
+	return nil
+}
+
+// QueryAudit method returns the audit records matching the given query,
+// recent first. Query is served by first configured sink which implements
+// `AuditQuerier` interface.
+func (a *Application) QueryAudit(q AuditQuery) ([]*AuditRecord, error) {
+	if a.audit != nil {
+		for _, s := range a.audit.sinks {
+			if qs, ok := s.(AuditQuerier); ok {
+				return qs.Query(q)
+			}
+		}
+	}
+	return nil, ErrAuditQueryNotSupported
+}
+
+// AuditMetrics method returns the snapshot of audit trail metrics, see
+// `server.audit { ... }`.
+func (a *Application) AuditMetrics() AuditMetrics {
+	if a.audit == nil {
+		return AuditMetrics{}
+	}
+	return AuditMetrics{
+		Recorded: atomic.LoadInt64(&a.audit.recorded),
+		Dropped:  atomic.LoadInt64(&a.audit.dropped),
+		Failed:   atomic.LoadInt64(&a.audit.failed),
+	}
+}
+
+// AuditMetrics holds the audit trail metrics.
+type AuditMetrics struct {
+	Recorded int64
+	Dropped  int64
+	Failed   int64
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Audit record, sink and query
+//______________________________________________________________________________
+
+// AuditRecord holds the audited request details, who did what on which
+// route and entity and its result. Parameter values are redacted.
+type AuditRecord struct {
+	ID             string            `json:"id"`
+	Time           time.Time         `json:"time"`
+	Subject        string            `json:"subject,omitempty"`
+	ImpersonatedBy string            `json:"impersonated_by,omitempty"`
+	Route          string            `json:"route"`
+	Method         string            `json:"method"`
+	Path           string            `json:"path"`
+	Params         map[string]string `json:"params,omitempty"`
+	Entity         string            `json:"entity,omitempty"`
+	Status         int               `json:"status"`
+	Result         string            `json:"result"`
+	Error          string            `json:"error,omitempty"`
+	ClientIP       string            `json:"client_ip,omitempty"`
+	Duration       time.Duration     `json:"duration"`
+}
+
+// AuditSink interface is to implement the audit record destination, for
+// e.g.: DB table, file, Kafka, etc. Records are written sequentially from
+// the audit worker goroutine.
+type AuditSink interface {
+	Write(r *AuditRecord) error
+}
+
+// AuditQuerier interface is implemented by the audit sink which supports
+// querying the records.
+type AuditQuerier interface {
+	Query(q AuditQuery) ([]*AuditRecord, error)
+}
+
+// AuditQuery holds the audit record query criteria, zero value fields are
+// not applied. Limit zero means no limit.
+type AuditQuery struct {
+	Subject string
+	Route   string
+	Entity  string
+	Result  string
+	From    time.Time
+	To      time.Time
+	Limit   int
+}
+
+// Match method returns true if given record matches the query criteria.
+func (q AuditQuery) Match(r *AuditRecord) bool {
+	return (len(q.Subject) == 0 || q.Subject == r.Subject) &&
+		(len(q.Route) == 0 || q.Route == r.Route) &&
+		(len(q.Entity) == 0 || q.Entity == r.Entity) &&
+		(len(q.Result) == 0 || q.Result == r.Result) &&
+		(q.From.IsZero() || !r.Time.Before(q.From)) &&
+		(q.To.IsZero() || r.Time.Before(q.To))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initAudit method initializes the request audit trail from config
+// `server.audit { ... }`. Requests of configured route names are recorded
+// into configured sinks asynchronously, records are dropped if the queue
+// is full. Route name `*` means all the routes.
+//
+//	server {
+//	  audit {
+//	    enable = true
+//	    routes = ["create_order", "delete_user"]
+//	    methods = ["POST", "PUT", "PATCH", "DELETE"]
+//	    sinks = ["file"]
+//	    queue_size = 1000
+//	    redact_fields = ["password", "token"]
+//	    file {
+//	      path = "audit.log"
+//	    }
+//	  }
+//	}
+func (a *Application) initAudit() error {
+	keyPrefix := "server.audit"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.stopAudit()
+		return nil
+	}
+
+	queueSize := cfg.IntDefault(keyPrefix+".queue_size", 1000)
+	if queueSize <= 0 {
+		return fmt.Errorf("'%s.queue_size' value must be greater than zero", keyPrefix)
+	}
+
+	at := &auditTrail{
+		routes:       make(map[string]bool),
+		redactFields: defaultRedactFields,
+		queue:        make(chan *AuditRecord, queueSize),
+		stop:         make(chan struct{}),
+	}
+	routes, _ := cfg.StringList(keyPrefix + ".routes")
+	if len(routes) == 0 {
+		return fmt.Errorf("'%s.routes' value is required", keyPrefix)
+	}
+	for _, r := range routes {
+		at.routes[r] = true
+	}
+	methods, _ := cfg.StringList(keyPrefix + ".methods")
+	for _, m := range methods {
+		at.methods = append(at.methods, strings.ToUpper(m))
+	}
+	if v, found := cfg.StringList(keyPrefix + ".redact_fields"); found {
+		at.redactFields = v
+	}
+	at.redactFields = a.piiRedactFields(at.redactFields)
+
+	sinkNames, found := cfg.StringList(keyPrefix + ".sinks")
+	if !found {
+		sinkNames = []string{"file"}
+	}
+	for _, name := range sinkNames {
+		s, err := a.auditSink(keyPrefix, name)
+		if err != nil {
+			at.close()
+			return err
+		}
+		at.sinks = append(at.sinks, s)
+	}
+
+	a.stopAudit()
+	a.audit = at
+	a.Go(at.run)
+	return nil
+}
+
+func (a *Application) auditSink(keyPrefix, name string) (AuditSink, error) {
+	cfg := a.Config()
+	switch name {
+	case "file":
+		p := cfg.StringDefault(keyPrefix+".file.path", "audit.log")
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(a.logsDir(), p)
+		}
+		return newAuditFileSink(p)
+	case "memory":
+		capacity := cfg.IntDefault(keyPrefix+".memory.capacity", 1000)
+		if capacity <= 0 {
+			return nil, fmt.Errorf("'%s.memory.capacity' value must be greater than zero", keyPrefix)
+		}
+		// previously recorded entries are retained on hot-reload
+		if a.audit != nil {
+			for _, s := range a.audit.sinks {
+				if ms, ok := s.(*auditMemorySink); ok && len(ms.entries) == capacity {
+					return ms, nil
+				}
+			}
+		}
+		return &auditMemorySink{entries: make([]*AuditRecord, capacity)}, nil
+	}
+
+	a.RLock()
+	defer a.RUnlock()
+	if s, found := a.auditSinks[name]; found {
+		return s, nil
+	}
+	return nil, fmt.Errorf("'%s.sinks' has unknown sink '%s'", keyPrefix, name)
+}
+
+func (a *Application) stopAudit() {
+	if a.audit != nil {
+		close(a.audit.stop)
+		a.audit = nil
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Audit trail
+//______________________________________________________________________________
+
+type auditTrail struct {
+	recorded     int64
+	dropped      int64
+	failed       int64
+	routes       map[string]bool
+	methods      []string
+	redactFields []string
+	sinks        []AuditSink
+	queue        chan *AuditRecord
+	stop         chan struct{}
+}
+
+// Record method queues the audit record of request if its route is
+// configured for audit.
+func (at *auditTrail) Record(ctx *Context, start time.Time) {
+	if ctx.route == nil || !at.isAudited(ctx.route.Name, ctx.Req.Method) {
+		return
+	}
+
+	r := ctx.Req.Unwrap()
+	ar := &AuditRecord{
+		ID:       firstNonZeroString(ctx.Req.Header.Get(ctx.a.settings().RequestIDHeaderKey), ess.NewGUID()),
+		Time:     start,
+		Route:    ctx.route.Name,
+		Method:   r.Method,
+		Path:     ctx.Req.Path,
+		Params:   make(map[string]string),
+		Status:   ctx.Res.Status(),
+		Result:   AuditSuccess,
+		ClientIP: ctx.Req.ClientIP(),
+		Duration: time.Since(start),
+	}
+	if sub := ctx.subject; sub != nil {
+		if sub.AuthenticationInfo != nil && sub.AuthenticationInfo.PrimaryPrincipal() != nil {
+			ar.Subject = sub.AuthenticationInfo.PrimaryPrincipal().Value
+		}
+		if sub.Impersonator != nil && sub.Impersonator.PrimaryPrincipal() != nil {
+			ar.ImpersonatedBy = sub.Impersonator.PrimaryPrincipal().Value
+		}
+	}
+	for k, v := range r.URL.Query() {
+		ar.Params[k] = at.redact(k, strings.Join(v, ","))
+	}
+	for _, p := range ctx.Req.URLParams {
+		ar.Params[p.Key] = at.redact(p.Key, p.Value)
+	}
+	if v, ok := ctx.Get(KeyAuditEntity).(string); ok {
+		ar.Entity = v
+	}
+	if ar.Status >= http.StatusBadRequest {
+		ar.Result = AuditFailure
+	}
+	if err := ctx.Reply().err; err != nil {
+		ar.Error = err.Message
+		if err.Reason != nil {
+			ar.Error = err.Reason.Error() + ": " + err.Message
+		}
+	}
+
+	at.enqueue(ar, ctx.Log())
+}
+
+// enqueue method queues the audit record, it's dropped if queue is full.
+func (at *auditTrail) enqueue(ar *AuditRecord, logger log.Loggerer) {
+	select {
+	case at.queue <- ar:
+	default:
+		atomic.AddInt64(&at.dropped, 1)
+		logger.Warnf("Audit queue is full, record dropped, Route: %s", ar.Route)
+	}
+}
+
+func (at *auditTrail) isAudited(routeName, method string) bool {
+	if !at.routes[routeName] && !at.routes["*"] {
+		return false
+	}
+	if len(at.methods) == 0 {
+		return true
+	}
+	for _, m := range at.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (at *auditTrail) redact(name, value string) string {
+	if isRedactField(at.redactFields, name) {
+		return settings.MaskedValue
+	}
+	return value
+}
+
+// run method writes the queued records into sinks until application
+// shutdown or audit trail reinitialize, pending records are written
+// before it returns.
+func (at *auditTrail) run(ctx context.Context) {
+	defer at.close()
+	for {
+		select {
+		case r := <-at.queue:
+			at.write(r)
+		case <-ctx.Done():
+			at.drain()
+			return
+		case <-at.stop:
+			at.drain()
+			return
+		}
+	}
+}
+
+func (at *auditTrail) drain() {
+	for {
+		select {
+		case r := <-at.queue:
+			at.write(r)
+		default:
+			return
+		}
+	}
+}
+
+func (at *auditTrail) write(r *AuditRecord) {
+	atomic.AddInt64(&at.recorded, 1)
+	for _, s := range at.sinks {
+		if err := s.Write(r); err != nil {
+			atomic.AddInt64(&at.failed, 1)
+		}
+	}
+}
+
+// close method closes the file sinks, memory and application added sinks
+// are retained.
+func (at *auditTrail) close() {
+	for _, s := range at.sinks {
+		if fs, ok := s.(*auditFileSink); ok {
+			_ = fs.Close()
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Built-in sinks
+//______________________________________________________________________________
+
+// auditMemorySink keeps the last `capacity` records in memory.
+type auditMemorySink struct {
+	sync.Mutex
+	entries []*AuditRecord
+	next    int
+}
+
+func (ms *auditMemorySink) Write(r *AuditRecord) error {
+	ms.Lock()
+	defer ms.Unlock()
+	ms.entries[ms.next] = r
+	ms.next = (ms.next + 1) % len(ms.entries)
+	return nil
+}
+
+func (ms *auditMemorySink) Query(q AuditQuery) ([]*AuditRecord, error) {
+	ms.Lock()
+	defer ms.Unlock()
+	result := make([]*AuditRecord, 0)
+	for i := 1; i <= len(ms.entries); i++ {
+		r := ms.entries[(ms.next-i+len(ms.entries))%len(ms.entries)]
+		if r == nil || !q.Match(r) {
+			continue
+		}
+		result = append(result, r)
+		if q.Limit > 0 && len(result) == q.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// auditFileSink appends the records as JSON lines into the file.
+type auditFileSink struct {
+	sync.Mutex
+	path string
+	f    *os.File
+}
+
+func newAuditFileSink(path string) (*auditFileSink, error) {
+	if err := ess.MkDirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &auditFileSink{path: path, f: f}, nil
+}
+
+func (fs *auditFileSink) Write(r *AuditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	fs.Lock()
+	defer fs.Unlock()
+	_, err = fs.f.Write(append(b, '\n'))
+	return err
+}
+
+// Query method scans the file, it's meant for occasional compliance
+// lookups. Use DB sink for frequent queries.
+func (fs *auditFileSink) Query(q AuditQuery) ([]*AuditRecord, error) {
+	fs.Lock()
+	defer fs.Unlock()
+	f, err := os.Open(fs.path)
+	if err != nil {
+		return nil, err
+	}
+	defer ess.CloseQuietly(f)
+
+	var matched []*AuditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		r := &AuditRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), r); err != nil {
+			continue
+		}
+		if q.Match(r) {
+			matched = append(matched, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*AuditRecord, 0, len(matched))
+	for i := len(matched) - 1; i >= 0; i-- {
+		result = append(result, matched[i])
+		if q.Limit > 0 && len(result) == q.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (fs *auditFileSink) Close() error {
+	fs.Lock()
+	defer fs.Unlock()
+	return fs.f.Close()
+}
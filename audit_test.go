@@ -0,0 +1,146 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframe.work/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuditSink struct {
+	records chan *AuditRecord
+}
+
+func (s *testAuditSink) Write(r *AuditRecord) error {
+	s.records <- r
+	return nil
+}
+
+func TestAuditTrail(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Audit Trail]: %s", ts.URL)
+
+	_, err := ts.app.QueryAudit(AuditQuery{})
+	assert.Equal(t, ErrAuditQueryNotSupported, err)
+
+	sink := &testAuditSink{records: make(chan *AuditRecord, 10)}
+	assert.Nil(t, ts.app.AddAuditSink("test", sink))
+	assert.Equal(t, "aah: audit sink 'test' is already added", ts.app.AddAuditSink("test", sink).Error())
+	assert.Equal(t, "aah: audit sink 'file' is already added", ts.app.AddAuditSink("file", sink).Error())
+	assert.Equal(t, "aah: audit sink is nil", ts.app.AddAuditSink("nil", nil).Error())
+
+	auditFile := filepath.Join(t.TempDir(), "audit.log")
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  audit {
+	    enable = true
+	    routes = ["text_get", "trigger_panic"]
+	    sinks = ["memory", "file", "test"]
+	    file {
+	      path = "`+auditFile+`"
+	    }
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initAudit())
+	defer ts.app.stopAudit()
+
+	resp, err := http.Get(ts.URL + "/get-text.html?card=4111&q=go")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	r := waitAuditRecord(t, sink)
+	assert.Equal(t, "text_get", r.Route)
+	assert.Equal(t, http.MethodGet, r.Method)
+	assert.Equal(t, "/get-text.html", r.Path)
+	assert.Equal(t, AuditSuccess, r.Result)
+	assert.Equal(t, settings.MaskedValue, r.Params["card"])
+	assert.Equal(t, "go", r.Params["q"])
+
+	resp, err = http.Get(ts.URL + "/trigger-panic")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	r = waitAuditRecord(t, sink)
+	assert.Equal(t, "trigger_panic", r.Route)
+	assert.Equal(t, AuditFailure, r.Result)
+	assert.Equal(t, http.StatusInternalServerError, r.Status)
+
+	// route not configured for audit
+	resp, err = http.Get(ts.URL + "/get-jsonp")
+	assert.Nil(t, err)
+	select {
+	case r = <-sink.records:
+		t.Errorf("unexpected audit record: %s", r.Route)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	records, err := ts.app.QueryAudit(AuditQuery{Result: AuditFailure})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, "trigger_panic", records[0].Route)
+	assert.Equal(t, int64(2), ts.app.AuditMetrics().Recorded)
+
+	fs, err := newAuditFileSink(auditFile)
+	assert.Nil(t, err)
+	defer fs.Close()
+	records, err = fs.Query(AuditQuery{Limit: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, "trigger_panic", records[0].Route)
+
+	// disabled on reinitialize
+	ts.app.Config().SetBool("server.audit.enable", false)
+	assert.Nil(t, ts.app.initAudit())
+	assert.Nil(t, ts.app.audit)
+	assert.Equal(t, AuditMetrics{}, ts.app.AuditMetrics())
+}
+
+func TestAuditConfigErrors(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	assert.Nil(t, mergeTestConfig(a, `server { audit { enable = true; } }`))
+	assert.Equal(t, "'server.audit.routes' value is required", a.initAudit().Error())
+
+	assert.Nil(t, mergeTestConfig(a, `server { audit { routes = ["*"]; sinks = ["kafka"]; } }`))
+	assert.Equal(t, "'server.audit.sinks' has unknown sink 'kafka'", a.initAudit().Error())
+
+	a.Config().SetInt("server.audit.queue_size", 0)
+	assert.Equal(t, "'server.audit.queue_size' value must be greater than zero", a.initAudit().Error())
+}
+
+func TestAuditQueryMatch(t *testing.T) {
+	now := time.Now()
+	r := &AuditRecord{Subject: "jeeva", Route: "delete_user", Entity: "user:1", Result: AuditSuccess, Time: now}
+	assert.True(t, AuditQuery{}.Match(r))
+	assert.True(t, AuditQuery{Subject: "jeeva", Entity: "user:1"}.Match(r))
+	assert.False(t, AuditQuery{Route: "create_user"}.Match(r))
+	assert.True(t, AuditQuery{From: now, To: now.Add(time.Second)}.Match(r))
+	assert.False(t, AuditQuery{To: now}.Match(r))
+
+	ms := &auditMemorySink{entries: make([]*AuditRecord, 2)}
+	for _, e := range []string{"a", "b", "c"} {
+		_ = ms.Write(&AuditRecord{Entity: e})
+	}
+	records, _ := ms.Query(AuditQuery{})
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, "c", records[0].Entity)
+	assert.Equal(t, "b", records[1].Entity)
+}
+
+func waitAuditRecord(t *testing.T, sink *testAuditSink) *AuditRecord {
+	select {
+	case r := <-sink.records:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("audit record is not written")
+	}
+	return nil
+}
@@ -5,8 +5,12 @@
 package aah
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -25,6 +29,11 @@ const (
 
 	keyOverrideI18nName = "lang"
 	allContentTypes     = "*/*"
+
+	expectContinueValue  = "100-continue"
+	expectContinueAuto   = "auto"
+	expectContinueDefer  = "defer"
+	expectContinueReject = "reject"
 )
 
 var (
@@ -73,28 +82,57 @@ func BindMiddleware(ctx *Context, m *Middleware) {
 			}
 		}
 
-		// Prevent DDoS attacks by large HTTP request bodies by enforcing configured hard limit
-		// TODO: integrate the max bytes reader error into aah error handling flow
-		ctx.Req.Unwrap().Body = http.MaxBytesReader(ctx.Res, ctx.Req.Body(), ctx.route.MaxBodySize)
-
-		// Set the tee reader if dump log enabled with request body enabled
-		if ctx.a.settings.DumpLogEnabled && ctx.a.dumpLog.logRequestBody {
-			reqBuf := acquireBuffer()
-			ctx.Req.Unwrap().Body = ioutil.NopCloser(io.TeeReader(ctx.Req.Body(), reqBuf))
-			ctx.Set(keyAahRequestBodyBuf, reqBuf)
-		}
-
-		// Parse request content by Content-Type
-		if parser, found := ctx.a.bindMgr.requestParsers[ctx.Req.ContentType().Mime]; found {
-			if res := parser(ctx); res == flowAbort {
+		// Handle `Expect: 100-continue` as per config `request.expect_continue`
+		if strings.EqualFold(ctx.Req.Header.Get(ahttp.HeaderExpect), expectContinueValue) {
+			switch ctx.a.bindMgr.expectContinue {
+			case expectContinueReject:
+				ctx.Reply().Status(http.StatusExpectationFailed).Error(newError(ErrExpectationFailed, http.StatusExpectationFailed))
+				return
+			case expectContinueDefer:
+				// request body is parsed in the action middleware, after
+				// authentication and authorization passes
+				ctx.deferBody = true
+				m.Next(ctx)
 				return
+			default:
+				// zero-length read sends the `100 Continue` right away
+				_, _ = ctx.Req.Body().Read(nil)
 			}
 		}
+
+		if parseRequestBody(ctx) == flowAbort {
+			return
+		}
 	}
 
 	m.Next(ctx)
 }
 
+// parseRequestBody method enforces the request body size limit and parses
+// the request body by Content-Type. On route streaming mode `stream_body`,
+// request body is neither parsed nor buffered, handler reads it via
+// `ctx.Req.Body()`.
+func parseRequestBody(ctx *Context) flowResult {
+	// Prevent DDoS attacks by large HTTP request bodies by enforcing configured hard limit
+	// TODO: integrate the max bytes reader error into aah error handling flow
+	ctx.Req.Unwrap().Body = http.MaxBytesReader(ctx.Res, ctx.Req.Body(), ctx.route.MaxBodySize)
+	if ctx.route.StreamBody {
+		return flowCont
+	}
+
+	// Set the tee reader if dump log enabled with request body enabled
+	if ctx.a.settings().DumpLogEnabled && ctx.a.dumpLog.logRequestBody && ctx.a.dumpLog.IsRequestDumpable(ctx) {
+		reqBuf := ctx.a.dumpLog.CaptureBody(keyAahRequestBodyBuf, ctx)
+		ctx.Req.Unwrap().Body = ioutil.NopCloser(io.TeeReader(ctx.Req.Body(), reqBuf))
+	}
+
+	// Parse request content by Content-Type
+	if parser, found := ctx.a.bindMgr.requestParsers[ctx.Req.ContentType().Mime]; found {
+		return parser(ctx)
+	}
+	return flowCont
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // app Unexported methods
 //______________________________________________________________________________
@@ -137,6 +175,13 @@ func (a *Application) initBind() error {
 	bindMgr.requestParsers[ahttp.ContentTypeMultipartForm.Mime] = multipartFormParser
 	bindMgr.requestParsers[ahttp.ContentTypeForm.Mime] = formParser
 
+	// Expect 100-continue policy
+	bindMgr.expectContinue = strings.ToLower(cfg.StringDefault("request.expect_continue", expectContinueAuto))
+	if !ess.IsSliceContainsString([]string{expectContinueAuto, expectContinueDefer, expectContinueReject}, bindMgr.expectContinue) {
+		return fmt.Errorf("'request.expect_continue' value must be one of [%s, %s, %s]",
+			expectContinueAuto, expectContinueDefer, expectContinueReject)
+	}
+
 	bindMgr.autobindPriority = reverseSlice(strings.Split(cfg.StringDefault("request.auto_bind.priority", "PFQ"), ""))
 	timeFormats, found := cfg.StringList("format.time")
 	if !found {
@@ -161,6 +206,7 @@ type bindManager struct {
 	contentNegotiationEnabled bool
 	keyQueryParamName         string
 	keyPathParamName          string
+	expectContinue            string
 	acceptedContentTypes      []string
 	offeredContentTypes       []string
 	autobindPriority          []string
@@ -173,19 +219,172 @@ type bindManager struct {
 //______________________________________________________________________________
 
 func multipartFormParser(ctx *Context) flowResult {
+	var lr *multipartLimitReader
+	if ctx.a.settings().MaxMultipartParts > 0 || ctx.a.settings().MaxMultipartHeaders > 0 ||
+		ctx.a.settings().MaxFormKeys > 0 {
+		if _, params, err := mime.ParseMediaType(ctx.Req.Header.Get(ahttp.HeaderContentType)); err == nil {
+			lr = newMultipartLimitReader(ctx.Req.Body(), params["boundary"], ctx.a.settings().MaxMultipartParts,
+				ctx.a.settings().MaxMultipartHeaders, ctx.a.settings().MaxFormKeys)
+			ctx.Req.Unwrap().Body = ioutil.NopCloser(lr)
+		}
+	}
+
 	if err := ctx.Req.Unwrap().ParseMultipartForm(ctx.route.MaxBodySize); err != nil {
+		if lr != nil && lr.err != nil {
+			return replyRequestLimitExceeded(ctx, lr.err.Error())
+		}
 		ctx.Log().Errorf("Unable to parse multipart form: %s", err)
 	}
 	return flowCont
 }
 
 func formParser(ctx *Context) flowResult {
+	var lr *formKeysLimitReader
+	if ctx.a.settings().MaxFormKeys > 0 {
+		lr = &formKeysLimitReader{r: ctx.Req.Body(), maxKeys: ctx.a.settings().MaxFormKeys}
+		ctx.Req.Unwrap().Body = ioutil.NopCloser(lr)
+	}
+
 	if err := ctx.Req.Unwrap().ParseForm(); err != nil {
+		if lr != nil && lr.err != nil {
+			return replyRequestLimitExceeded(ctx, lr.err.Error())
+		}
 		ctx.Log().Errorf("Unable to parse form: %s", err)
 	}
 	return flowCont
 }
 
+func replyRequestLimitExceeded(ctx *Context, limit string) flowResult {
+	ctx.Log().Warnf("Request %s exceeds the configured limit", limit)
+	ctx.Reply().BadRequest().Error(newError(ErrRequestLimitExceeded, http.StatusBadRequest))
+	return flowAbort
+}
+
+// countParams method returns the count of key-value pairs in the given
+// URL encoded string without parsing it.
+func countParams(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	return strings.Count(s, "&") + 1
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Form limit readers
+//______________________________________________________________________________
+
+// formKeysLimitReader counts the URL encoded form keys while the form body
+// is being read, it returns an error once the limit is exceeded. So parsing
+// stops early without consuming the entire body.
+type formKeysLimitReader struct {
+	r       io.Reader
+	maxKeys int
+	keys    int
+	err     error
+}
+
+func (l *formKeysLimitReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	n, err := l.r.Read(p)
+	if n > 0 && l.keys == 0 {
+		l.keys = 1
+	}
+	for _, b := range p[:n] {
+		if b == '&' {
+			l.keys++
+		}
+	}
+	if l.keys > l.maxKeys {
+		l.err = errors.New("form keys")
+		return 0, l.err
+	}
+	return n, err
+}
+
+// multipartLimitReader counts the multipart parts and part headers while
+// the multipart body is being read, it returns an error once the limit is
+// exceeded. So parsing stops early without consuming the entire body.
+// Each part is counted as form key for the form keys limit.
+type multipartLimitReader struct {
+	r          io.Reader
+	delimiter  []byte
+	maxParts   int
+	maxHeaders int
+	maxKeys    int
+	parts      int
+	headers    int
+	inHeaders  bool
+	line       []byte
+	err        error
+}
+
+func newMultipartLimitReader(r io.Reader, boundary string, maxParts, maxHeaders, maxKeys int) *multipartLimitReader {
+	return &multipartLimitReader{
+		r:          r,
+		delimiter:  []byte("--" + boundary),
+		maxParts:   maxParts,
+		maxHeaders: maxHeaders,
+		maxKeys:    maxKeys,
+		line:       make([]byte, 0, len(boundary)+4),
+	}
+}
+
+func (l *multipartLimitReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	n, err := l.r.Read(p)
+	for _, b := range p[:n] {
+		if b == '\n' {
+			l.endOfLine()
+			if l.err != nil {
+				return 0, l.err
+			}
+			continue
+		}
+		// only line prefix is needed to identify delimiter and empty line
+		if len(l.line) < cap(l.line) {
+			l.line = append(l.line, b)
+		}
+	}
+	return n, err
+}
+
+func (l *multipartLimitReader) endOfLine() {
+	line := bytes.TrimRight(l.line, "\r")
+	l.line = l.line[:0]
+
+	if bytes.HasPrefix(line, l.delimiter) {
+		// closing delimiter, followed by epilogue
+		l.inHeaders = !bytes.HasPrefix(line[len(l.delimiter):], []byte("--"))
+		if !l.inHeaders {
+			return
+		}
+		l.parts++
+		if l.maxParts > 0 && l.parts > l.maxParts {
+			l.err = errors.New("multipart parts")
+		} else if l.maxKeys > 0 && l.parts > l.maxKeys {
+			l.err = errors.New("form keys")
+		}
+		return
+	}
+
+	if l.inHeaders {
+		if len(line) == 0 {
+			l.inHeaders = false
+			return
+		}
+		l.headers++
+		if l.maxHeaders > 0 && l.headers > l.maxHeaders {
+			l.err = errors.New("multipart headers")
+		}
+	}
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Context - Action Parameters Auto Parse
 //______________________________________________________________________________
@@ -208,8 +407,8 @@ func (ctx *Context) parseParameters() ([]reflect.Value, *Error) {
 			result, err = vpFn(val.Name, val.Type, params)
 		} else if val.Kind == reflect.Struct {
 			ct := ctx.Req.ContentType().Mime
-			if ct == ahttp.ContentTypeJSON.Mime || ct == ahttp.ContentTypeXML.Mime ||
-				ct == ahttp.ContentTypeJSONText.Mime || ct == ahttp.ContentTypeXMLText.Mime {
+			if !ctx.route.StreamBody && (ct == ahttp.ContentTypeJSON.Mime || ct == ahttp.ContentTypeXML.Mime ||
+				ct == ahttp.ContentTypeJSONText.Mime || ct == ahttp.ContentTypeXMLText.Mime) {
 				result, err = valpar.Body(ct, ctx.Req.Body(), val.Type)
 			} else {
 				result, err = valpar.Struct("", val.Type, params)
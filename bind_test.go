@@ -5,7 +5,9 @@
 package aah
 
 import (
+	"bytes"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -18,6 +20,7 @@ import (
 	"aahframe.work/config"
 	"aahframe.work/essentials"
 	"aahframe.work/log"
+	"aahframe.work/router"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,6 +62,86 @@ func TestBindParamContentNegotiation(t *testing.T) {
 	assert.Equal(t, http.StatusNotAcceptable, ctx2.Reply().err.Code)
 }
 
+func TestBindStreamBody(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	assert.Nil(t, a.initLog())
+	assert.Nil(t, a.initBind())
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	newStreamCtx := func(maxBodySize int64) *Context {
+		r := httptest.NewRequest("POST", "http://localhost:8080/v1/upload", strings.NewReader("name=aah"))
+		r.Header.Set(ahttp.HeaderContentType, "application/x-www-form-urlencoded")
+		ctx := newContext(httptest.NewRecorder(), r)
+		ctx.a = a
+		ctx.route = &router.Route{MaxBodySize: maxBodySize, StreamBody: true}
+		return ctx
+	}
+
+	// body is not parsed, handler reads it
+	ctx := newStreamCtx(1 << 20)
+	called := false
+	BindMiddleware(ctx, &Middleware{next: func(ctx *Context, m *Middleware) { called = true }})
+	assert.True(t, called)
+	assert.Nil(t, ctx.Req.Unwrap().PostForm)
+	b, err := ioutil.ReadAll(ctx.Req.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, "name=aah", string(b))
+
+	// route max body size is enforced on read
+	ctx = newStreamCtx(4)
+	assert.Equal(t, flowCont, parseRequestBody(ctx))
+	_, err = ioutil.ReadAll(ctx.Req.Body())
+	assert.NotNil(t, err)
+}
+
+func TestBindExpectContinue(t *testing.T) {
+	newExpectCtx := func(a *Application) *Context {
+		r := httptest.NewRequest("POST", "http://localhost:8080/v1/upload", strings.NewReader("name=aah"))
+		r.Header.Set(ahttp.HeaderContentType, "application/x-www-form-urlencoded")
+		r.Header.Set(ahttp.HeaderExpect, "100-Continue")
+		ctx := newContext(httptest.NewRecorder(), r)
+		ctx.a = a
+		ctx.route = &router.Route{MaxBodySize: 1 << 20}
+		return ctx
+	}
+
+	for _, policy := range []string{"auto", "defer", "reject"} {
+		t.Run(policy, func(t *testing.T) {
+			a := newApp()
+			a.cfg, _ = config.ParseString(`request { expect_continue = "` + policy + `"; }`)
+			assert.Nil(t, a.initLog())
+			assert.Nil(t, a.initBind())
+			a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+			called := false
+			ctx := newExpectCtx(a)
+			BindMiddleware(ctx, &Middleware{next: func(ctx *Context, m *Middleware) { called = true }})
+
+			switch policy {
+			case "auto":
+				assert.True(t, called)
+				assert.Equal(t, "aah", ctx.Req.Unwrap().PostForm.Get("name"))
+			case "defer":
+				assert.True(t, called)
+				assert.True(t, ctx.deferBody)
+				assert.Nil(t, ctx.Req.Unwrap().PostForm)
+				assert.Equal(t, flowCont, parseRequestBody(ctx))
+				assert.Equal(t, "aah", ctx.Req.Unwrap().PostForm.Get("name"))
+			case "reject":
+				assert.False(t, called)
+				assert.Equal(t, http.StatusExpectationFailed, ctx.Reply().Code)
+				assert.Equal(t, ErrExpectationFailed, ctx.Reply().err.Reason)
+			}
+		})
+	}
+
+	a := newApp()
+	a.cfg, _ = config.ParseString(`request { expect_continue = "ignore"; }`)
+	err := a.initBind()
+	assert.Equal(t, "'request.expect_continue' value must be one of [auto, defer, reject]", err.Error())
+}
+
 func TestBindAddValueParser(t *testing.T) {
 	app := newApp()
 	err := app.AddValueParser(reflect.TypeOf(time.Time{}), func(key string, typ reflect.Type, params url.Values) (reflect.Value, error) {
@@ -127,3 +210,65 @@ func TestBindParamTemplateFuncs(t *testing.T) {
 	v3 := a.viewMgr.tmplPathParam(viewArgs, "userId")
 	assert.Equal(t, "100001", v3)
 }
+
+func TestBindMultipartLimitReader(t *testing.T) {
+	buf := new(bytes.Buffer)
+	mw := multipart.NewWriter(buf)
+	for _, f := range []string{"name", "email", "city"} {
+		_ = mw.WriteField(f, "value of "+f+"\n--not a delimiter")
+	}
+	_ = mw.Close()
+	body := buf.Bytes()
+
+	testcases := []struct {
+		maxParts, maxHeaders, maxKeys int
+		err                           string
+	}{
+		{maxParts: 3, maxHeaders: 3, maxKeys: 3},
+		{maxParts: 0, maxHeaders: 0, maxKeys: 0},
+		{maxParts: 2, maxHeaders: 0, err: "multipart parts"},
+		{maxParts: 0, maxHeaders: 2, err: "multipart headers"},
+		{maxParts: 0, maxHeaders: 0, maxKeys: 2, err: "form keys"},
+	}
+
+	for _, tc := range testcases {
+		lr := newMultipartLimitReader(bytes.NewReader(body), mw.Boundary(), tc.maxParts, tc.maxHeaders, tc.maxKeys)
+		form, err := multipart.NewReader(lr, mw.Boundary()).ReadForm(1 << 20)
+		if tc.err == "" {
+			assert.Nil(t, err)
+			assert.Nil(t, lr.err)
+			assert.Equal(t, 3, lr.parts)
+			assert.Equal(t, 3, lr.headers)
+			assert.Equal(t, "value of city\n--not a delimiter", form.Value["city"][0])
+		} else {
+			assert.NotNil(t, err)
+			assert.Equal(t, tc.err, lr.err.Error())
+		}
+	}
+}
+
+func TestBindFormKeysLimitReader(t *testing.T) {
+	newReq := func(body string, maxKeys int) (*http.Request, *formKeysLimitReader) {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost:8080/users", nil)
+		r.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeForm.String())
+		lr := &formKeysLimitReader{r: strings.NewReader(body), maxKeys: maxKeys}
+		r.Body = ioutil.NopCloser(lr)
+		return r, lr
+	}
+
+	r, lr := newReq("name=jeeva&city=chennai&city=madurai", 3)
+	assert.Nil(t, r.ParseForm())
+	assert.Nil(t, lr.err)
+	assert.Equal(t, []string{"chennai", "madurai"}, r.PostForm["city"])
+
+	r, lr = newReq("name=jeeva&city=chennai&city=madurai", 2)
+	assert.NotNil(t, r.ParseForm())
+	assert.Equal(t, "form keys", lr.err.Error())
+	assert.Nil(t, r.PostForm["name"])
+}
+
+func TestBindCountParams(t *testing.T) {
+	assert.Equal(t, 0, countParams(""))
+	assert.Equal(t, 1, countParams("a=1"))
+	assert.Equal(t, 3, countParams("a=1&b=2&a=3"))
+}
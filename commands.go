@@ -5,6 +5,7 @@
 package aah
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -30,7 +31,7 @@ func (a *Application) initCli() {
 	a.cli.Version = bi.Version
 	a.cli.Copyright = a.Config().StringDefault("copyright", "")
 	a.cli.Metadata["BuildTimestamp"] = bi.Timestamp
-	a.cli.Commands = append([]console.Command{a.cliCmdRun(), a.cliCmdVfs()}, a.cli.Commands...)
+	a.cli.Commands = append([]console.Command{a.cliCmdRun(), a.cliCmdConsole(), a.cliCmdSeed(), a.cliCmdVfs()}, a.cli.Commands...)
 	a.cli.Commands = append(a.cli.Commands, a.cliCmdHelp())
 	a.cli.HideHelp = true
 	a.cli.Flags = []console.Flag{
@@ -99,25 +100,8 @@ func (a *Application) cliCmdRun() console.Command {
 		Action: func(c *console.Context) error {
 			a.Log().Infof("aah framework v%s, requires >= go1.11", a.BuildInfo().AahVersion)
 
-			// External config file
-			extCfgFile := c.String("config")
-			if !ess.IsStrEmpty(extCfgFile) {
-				cpath, err := filepath.Abs(extCfgFile)
-				if err != nil {
-					return fmt.Errorf("Unable to resolve external config: %s", extCfgFile)
-				}
-				extCfg, err := config.LoadFile(cpath)
-				if err != nil {
-					return fmt.Errorf("Unable to load external config, error: %s", err)
-				}
-				if err = a.Config().Merge(extCfg); err != nil {
-					return fmt.Errorf("Unable to merge external config into aah application[%s]: %s", a.Name(), err)
-				}
-			}
-
-			envProfile := c.String("envprofile")
-			if !ess.IsStrEmpty(envProfile) {
-				a.Config().SetString("env.active", envProfile)
+			if err := a.applyConfigFlags(c); err != nil {
+				return err
 			}
 			proxyPort := c.String("proxyport")
 			if !ess.IsStrEmpty(proxyPort) {
@@ -141,14 +125,19 @@ func (a *Application) cliCmdRun() console.Command {
 			go a.Start()
 
 			// Listen to OS signal's SIGINT & SIGTERM for aah server Shutdown
+			// and zero-downtime restart completion
 			sc := make(chan os.Signal, 1)
 			signal.Notify(sc, os.Interrupt, syscall.SIGTERM)
-			sig := <-sc
-			switch sig {
-			case os.Interrupt:
-				a.Log().Warn("Interrupt signal (SIGINT) received")
-			case syscall.SIGTERM:
-				a.Log().Warn("Termination signal (SIGTERM) received")
+			select {
+			case sig := <-sc:
+				switch sig {
+				case os.Interrupt:
+					a.Log().Warn("Interrupt signal (SIGINT) received")
+				case syscall.SIGTERM:
+					a.Log().Warn("Termination signal (SIGTERM) received")
+				}
+			case <-a.restarted:
+				a.Log().Warn("Restart completed, new process is serving the requests")
 			}
 
 			// aah server shutdown
@@ -161,6 +150,69 @@ func (a *Application) cliCmdRun() console.Command {
 	}
 }
 
+func (a *Application) cliCmdConsole() console.Command {
+	return console.Command{
+		Name:  "console",
+		Usage: "Starts the interactive console with application context",
+		Description: `Boots the application (config, modules, 'OnInit' and 'OnStart' events)
+	without HTTP server and starts the interactive console to inspect config, routes
+	and invoke the application console commands, see 'aah.App().AddConsoleCommand'.
+
+		Example:
+			<app-binary> console --envprofile prod`,
+		Flags: []console.Flag{
+			console.StringFlag{
+				Name:  "envprofile, e",
+				Value: "dev",
+				Usage: "Environment profile name to activate (e.g: dev, qa, prod)",
+			},
+			console.StringFlag{
+				Name:  "config, c",
+				Usage: "External config `FILE` for adding or overriding 'config/**/*.conf' values",
+			},
+		},
+		Action: func(c *console.Context) error {
+			return a.runWithoutServer(c, func() error {
+				return a.runConsole(os.Stdin, c.App.Writer)
+			})
+		},
+	}
+}
+
+func (a *Application) cliCmdSeed() console.Command {
+	return console.Command{
+		Name:  "seed",
+		Usage: "Applies the pending data seeds of environment profile",
+		Description: `Boots the application without HTTP server and applies the pending data
+	seeds (seed files and 'aah.App().AddSeed' funcs) of environment profile. Applied
+	seeds are skipped, environment profile must be allowed in 'seed.profiles'.
+
+		Example:
+			<app-binary> seed --envprofile test`,
+		Flags: []console.Flag{
+			console.StringFlag{
+				Name:  "envprofile, e",
+				Value: "dev",
+				Usage: "Environment profile name to activate (e.g: dev, test)",
+			},
+			console.StringFlag{
+				Name:  "config, c",
+				Usage: "External config `FILE` for adding or overriding 'config/**/*.conf' values",
+			},
+		},
+		Action: func(c *console.Context) error {
+			return a.runWithoutServer(c, func() error {
+				if err := a.waitForDependencies(); err != nil {
+					return err
+				}
+				n, err := a.RunSeeds(context.Background())
+				fmt.Fprintf(c.App.Writer, "%d seed(s) applied for environment profile '%s'\n", n, a.EnvProfile())
+				return err
+			})
+		},
+	}
+}
+
 func (a *Application) cliCmdVfs() console.Command {
 	return console.Command{
 		Name:    "vfs",
@@ -212,3 +264,48 @@ func (a *Application) cliCmdVfs() console.Command {
 		},
 	}
 }
+
+// runWithoutServer method boots the application with 'OnStart' event without
+// HTTP server, runs the given func and then publishes the shutdown events.
+func (a *Application) runWithoutServer(c *console.Context, fn func() error) error {
+	if err := a.applyConfigFlags(c); err != nil {
+		return err
+	}
+	if err := a.initApp(); err != nil {
+		return err
+	}
+	a.EventStore().sortAndPublishSync(&Event{Name: EventOnStart})
+	defer func() {
+		a.EventStore().sortAndPublishSync(&Event{Name: EventOnPreShutdown})
+		ctx, cancel := context.WithTimeout(context.Background(), a.Timeouts().GraceTimeout())
+		defer cancel()
+		_ = a.goGroup.stop(ctx)
+		a.EventStore().sortAndPublishSync(&Event{Name: EventOnPostShutdown})
+	}()
+	return fn()
+}
+
+// applyConfigFlags method merges the external config file of flag `config`
+// and activates the env profile of flag `envprofile`.
+func (a *Application) applyConfigFlags(c *console.Context) error {
+	extCfgFile := c.String("config")
+	if !ess.IsStrEmpty(extCfgFile) {
+		cpath, err := filepath.Abs(extCfgFile)
+		if err != nil {
+			return fmt.Errorf("Unable to resolve external config: %s", extCfgFile)
+		}
+		extCfg, err := config.LoadFile(cpath)
+		if err != nil {
+			return fmt.Errorf("Unable to load external config, error: %s", err)
+		}
+		if err = a.Config().Merge(extCfg); err != nil {
+			return fmt.Errorf("Unable to merge external config into aah application[%s]: %s", a.Name(), err)
+		}
+	}
+
+	envProfile := c.String("envprofile")
+	if !ess.IsStrEmpty(envProfile) {
+		a.Config().SetString("env.active", envProfile)
+	}
+	return nil
+}
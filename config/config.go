@@ -6,6 +6,9 @@
 // which is similar to HOCON syntax. Internally `aah/config` uses `forge`
 // syntax developed by `https://github.com/brettlangdon`.
 //
+// Config files can be written in YAML (`.yaml`, `.yml`) or TOML (`.toml`)
+// as well, format is selected by file extension.
+//
 // aah framework is powered with `aahframework.org/config` library.
 package config
 
@@ -38,6 +41,9 @@ func NewEmpty() *Config {
 // Config handles the configuration values and enables environment profile's,
 // merge, etc. Also it provide nice and handly methods for accessing config values.
 // Internally `aah config` uses `forge syntax` developed by `https://github.com/brettlangdon`.
+//
+// Config values can be overridden by environment variables, refer to
+// `Config.ApplyEnvOverrides`.
 type Config struct {
 	sync.RWMutex
 	profile string
@@ -388,7 +394,7 @@ func (c *Config) Merge(source *Config) error {
 	}
 	c.Lock()
 	defer c.Unlock()
-	return c.cfg.Merge(source.cfg)
+	return mergeSection(c.cfg, source.cfg)
 }
 
 // Merge2Section method allows to merge config into existing config section.
@@ -406,7 +412,7 @@ func (c *Config) Merge2Section(key string, source *Config) error {
 	sec := c.getSection(parts)
 	c.Lock()
 	defer c.Unlock()
-	return sec.Merge(source.cfg)
+	return mergeSection(sec, source.cfg)
 }
 
 // IsExists returns true if given is exists in the config otherwise returns false
@@ -425,6 +431,15 @@ func (c *Config) ToJSON() string {
 	return "{}"
 }
 
+// ResolveStrings method calls the given func for every string value in the
+// configuration including profile sections and list items, returned value
+// replaces the original value. For e.g.: secret values `ENC(...)`.
+func (c *Config) ResolveStrings(fn func(key, value string) (string, error)) error {
+	c.Lock()
+	defer c.Unlock()
+	return resolveStrings("", c.cfg, fn)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Config load/parse methods
 //______________________________________________________________________________
@@ -468,7 +483,7 @@ func ParseString(cfg string) (*Config, error) {
 //______________________________________________________________________________
 
 func loadFile(filename string) (*forge.Section, error) {
-	setting, err := forge.ParseFile(filename)
+	setting, err := parseFile(filename)
 	if os.IsNotExist(err) {
 		return nil, fmt.Errorf("configuration file does not exists: %v", filename)
 	}
@@ -555,6 +570,68 @@ func (c *Config) addValue(key string, value forge.Value) {
 	}
 }
 
+func resolveStrings(prefix string, sec *forge.Section, fn func(key, value string) (string, error)) error {
+	for _, k := range sec.Keys() {
+		v, _ := sec.Get(k)
+		if err := resolveValue(prefix+k, v, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveValue(key string, v forge.Value, fn func(key, value string) (string, error)) error {
+	switch v.GetType() {
+	case forge.SECTION:
+		return resolveStrings(key+".", v.(*forge.Section), fn)
+	case forge.LIST:
+		for _, lv := range v.(*forge.List).GetValues() {
+			if err := resolveValue(key, lv, fn); err != nil {
+				return err
+			}
+		}
+	case forge.STRING:
+		if _, ok := v.(*forge.Primative); !ok {
+			return nil
+		}
+		rv, err := fn(key, v.GetValue().(string))
+		if err != nil {
+			return err
+		}
+		return v.UpdateValue(rv)
+	}
+	return nil
+}
+
+// mergeSection method merges the source section into target section, list
+// value of source replaces the target list value.
+func mergeSection(target, source *forge.Section) error {
+	for _, key := range source.Keys() {
+		sv, _ := source.Get(key)
+		tv, err := target.Get(key)
+		if err != nil || (tv.GetType() == forge.LIST && sv.GetType() == forge.LIST) {
+			target.Set(key, sv)
+			continue
+		}
+
+		if tv.GetType() == forge.SECTION {
+			if sv.GetType() != forge.SECTION {
+				return fmt.Errorf("source (%v) and target (%v) type doesn't match: %v",
+					sv.GetType(), tv.GetType(), key)
+			}
+			if err = mergeSection(tv.(*forge.Section), sv.(*forge.Section)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = tv.UpdateValue(sv.GetValue()); err != nil {
+			return fmt.Errorf("%v: %v", err, key)
+		}
+	}
+	return nil
+}
+
 func newConfig(sec *forge.Section) *Config {
 	return &Config{RWMutex: sync.RWMutex{}, cfg: sec}
 }
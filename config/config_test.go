@@ -346,6 +346,15 @@ prod {
 
 	err = cfg1.Merge(nil)
 	assert.Equal(t, "source is nil", err.Error())
+
+	// list value is overridden
+	cfg3, _ := ParseString(`hosts = ["a.example.org", "b.example.org"]; ports = [80];`)
+	cfg4, _ := ParseString(`hosts = ["c.example.org"];`)
+	assert.Nil(t, cfg3.Merge(cfg4))
+	hosts, _ := cfg3.StringList("hosts")
+	assert.Equal(t, []string{"c.example.org"}, hosts)
+	ports, _ := cfg3.IntList("ports")
+	assert.Equal(t, []int{80}, ports)
 }
 
 func TestLoadFiles(t *testing.T) {
@@ -432,6 +441,97 @@ func TestConfigSetValues(t *testing.T) {
 	assert.Equal(t, "My-Request-Hdr", cfg.StringDefault("request.id.header", ""))
 }
 
+func TestConfigEnvOverride(t *testing.T) {
+	cfg := initString(t, `
+	server {
+		port = "8080"
+		timeout {
+			read = "90s"
+		}
+		http2 {
+			enable = true
+		}
+		max_conns = 100
+		ratio = 0.5
+		allow_ips = ["127.0.0.1"]
+		ports = [8080]
+	}
+	request {
+		max_body_size = "5mb"
+	}
+	env {
+		prod {
+			server {
+				port = "80"
+				debug = false
+			}
+		}
+	}
+	`)
+
+	envs := map[string]string{
+		"AAHTEST_SERVER_PORT":           "9090",
+		"AAHTEST_REQUEST_MAX_BODY_SIZE": "10mb",
+		"AAHTEST_SERVER_HTTP2_ENABLE":   "false",
+		"AAHTEST_SERVER_MAX_CONNS":      "512",
+		"AAHTEST_SERVER_RATIO":          "0.75",
+		"AAHTEST_SERVER_ALLOW_IPS":      "10.0.0.1, 10.0.0.2",
+		"AAHTEST_SERVER_PORTS":          "80,443",
+		"AAHTEST_SERVER_DEBUG":          "true",
+		"AAHTEST_SERVER_NOT_DEFINED":    "value",
+	}
+	for k, v := range envs {
+		_ = os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envs {
+			_ = os.Unsetenv(k)
+		}
+	}()
+
+	// empty prefix, not enabled
+	keys, err := cfg.ApplyEnvOverrides("")
+	assert.Nil(t, err)
+	assert.Nil(t, keys)
+	assert.Equal(t, "8080", cfg.StringDefault("server.port", ""))
+
+	keys, err = cfg.ApplyEnvOverrides("aahtest_", "env.prod")
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(keys))
+	assert.Contains(t, keys, "env.prod.server.port")
+	assert.Contains(t, keys, "server.allow_ips")
+
+	// env > profile > default
+	setProfileForTest(t, cfg, "env.prod")
+	assert.Equal(t, "9090", cfg.StringDefault("server.port", ""))
+	assert.True(t, cfg.BoolDefault("server.debug", false))
+	cfg.ClearProfile()
+	assert.Equal(t, "9090", cfg.StringDefault("server.port", ""))
+	assert.Equal(t, "10mb", cfg.StringDefault("request.max_body_size", ""))
+	assert.Equal(t, "90s", cfg.StringDefault("server.timeout.read", ""))
+	assert.False(t, cfg.IsExists("server.not_defined"))
+
+	// value type is retained
+	assert.False(t, cfg.BoolDefault("server.http2.enable", true))
+	assert.Equal(t, 512, cfg.IntDefault("server.max_conns", 0))
+	assert.Equal(t, float32(0.75), cfg.Float32Default("server.ratio", 0))
+	v, _ := cfg.Get("server.max_conns")
+	assert.Equal(t, int64(512), v)
+
+	ips, found := cfg.StringList("server.allow_ips")
+	assert.True(t, found)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, ips)
+	ports, found := cfg.IntList("server.ports")
+	assert.True(t, found)
+	assert.Equal(t, []int{80, 443}, ports)
+
+	// value conversion error
+	_ = os.Setenv("AAHTEST_SERVER_MAX_CONNS", "many")
+	_, err = cfg.ApplyEnvOverrides("AAHTEST")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "config: environment variable 'AAHTEST_SERVER_MAX_CONNS' value is invalid for 'server.max_conns'")
+}
+
 func initString(t *testing.T, configStr string) *Config {
 	cfg, err := ParseString(configStr)
 	if !assert.NoErrorf(t, err, "loading failed") {
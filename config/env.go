@@ -0,0 +1,133 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-aah/forge"
+)
+
+// ApplyEnvOverrides method overrides the config values with environment
+// variables of given prefix. Environment variable name is derived from config
+// key by replacing `.` with `_` and upper cased, for e.g.: with prefix `AAH`
+//
+//	server.port          => AAH_SERVER_PORT
+//	server.timeout.read  => AAH_SERVER_TIMEOUT_READ
+//
+// Keys within given profile sections are derived without the profile, for
+// e.g.: `env.prod.server.port` => `AAH_SERVER_PORT`. So precedence order is
+// environment variable, then enabled profile value and then default value.
+//
+// Overrides are resolved once, only the keys defined in the config are
+// overridden. Environment variable value is converted into the type of config
+// value, list values are comma separated. It returns the overridden keys or
+// error if the value conversion fails.
+func (c *Config) ApplyEnvOverrides(prefix string, profiles ...string) ([]string, error) {
+	prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_"))
+	if len(prefix) == 0 {
+		return nil, nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	var keys []string
+	err := applyEnvOverrides(prefix, "", "", c.cfg, profiles, &keys)
+	return keys, err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func applyEnvOverrides(prefix, path, envPath string, sec *forge.Section, profiles []string, keys *[]string) error {
+	for _, k := range sec.Keys() {
+		v, _ := sec.Get(k)
+		key, ename := path+k, envPath+k
+		if v.GetType() == forge.SECTION {
+			if isProfileKey(profiles, key) {
+				ename = ""
+			} else {
+				ename += "."
+			}
+			if err := applyEnvOverrides(prefix, key+".", ename, v.(*forge.Section), profiles, keys); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := envName(prefix, ename)
+		ev, found := os.LookupEnv(name)
+		if !found {
+			continue
+		}
+		nv, err := envValue(v, ev)
+		if err != nil {
+			return fmt.Errorf("config: environment variable '%s' value is invalid for '%s': %v", name, key, err)
+		}
+		sec.Set(k, nv)
+		*keys = append(*keys, key)
+	}
+	return nil
+}
+
+func envName(prefix, key string) string {
+	return prefix + "_" + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+func isProfileKey(profiles []string, key string) bool {
+	for _, p := range profiles {
+		if p == key {
+			return true
+		}
+	}
+	return false
+}
+
+// envValue method converts the environment variable value into the type of
+// given config value.
+func envValue(v forge.Value, ev string) (forge.Value, error) {
+	switch v.GetType() {
+	case forge.BOOLEAN:
+		b, err := strconv.ParseBool(ev)
+		if err != nil {
+			return nil, err
+		}
+		return forge.NewBoolean(b), nil
+	case forge.INTEGER:
+		i, err := strconv.ParseInt(ev, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return forge.NewInteger(i), nil
+	case forge.FLOAT:
+		f, err := strconv.ParseFloat(ev, 64)
+		if err != nil {
+			return nil, err
+		}
+		return forge.NewFloat(f), nil
+	case forge.LIST:
+		var elem forge.Value = forge.NewString("")
+		if values := v.(*forge.List).GetValues(); len(values) > 0 {
+			elem = values[0]
+		}
+		lst := forge.NewList()
+		for _, s := range strings.Split(ev, ",") {
+			if s = strings.TrimSpace(s); len(s) == 0 {
+				continue
+			}
+			lv, err := envValue(elem, s)
+			if err != nil {
+				return nil, err
+			}
+			lst.Append(lv)
+		}
+		return lst, nil
+	}
+	return forge.NewString(ev), nil
+}
@@ -0,0 +1,360 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-aah/forge"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported config file formats, format is selected by file extension.
+// Default format is forge (HOCON like) syntax.
+const (
+	FormatForge = "forge"
+	FormatYAML  = "yaml"
+	FormatTOML  = "toml"
+)
+
+// FileExts are the supported config file extensions in the lookup order.
+var FileExts = []string{".conf", ".yaml", ".yml", ".toml"}
+
+var efs forge.FileSystem
+
+// RegisterFS method registers the file system used to read the config files,
+// for e.g.: aah virtual file system. It's registered into forge as well.
+func RegisterFS(fs forge.FileSystem) {
+	efs = fs
+	forge.RegisterFS(fs)
+}
+
+// FormatByExt method returns the config format for given file name by its
+// extension.
+func FormatByExt(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatForge
+}
+
+// ParseYAML parses the configuration values from YAML string. Only the
+// first document is used and its root must be a mapping. Mapping inside the
+// list is not supported, since config list holds only scalar values.
+func ParseYAML(cfg string) (*Config, error) {
+	setting, err := parseYAML(strings.NewReader(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return newConfig(setting), nil
+}
+
+// ParseTOML parses the configuration values from TOML string. Array of
+// tables is not supported, since config list holds only scalar values.
+func ParseTOML(cfg string) (*Config, error) {
+	setting, err := parseTOML(strings.NewReader(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return newConfig(setting), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+var errMappingInList = errors.New("mapping in list is not supported")
+
+func parseFile(filename string) (*forge.Section, error) {
+	format := FormatByExt(filename)
+	if format == FormatForge {
+		return forge.ParseFile(filename)
+	}
+
+	var (
+		r   io.Reader
+		err error
+	)
+	if _, serr := os.Lstat(filename); efs == nil || serr == nil {
+		r, err = os.Open(filename)
+	} else {
+		r, err = efs.Open(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer func() { _ = c.Close() }()
+	}
+
+	var setting *forge.Section
+	if format == FormatYAML {
+		setting, err = parseYAML(r)
+	} else {
+		setting, err = parseTOML(r)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	return setting, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// YAML
+//___________________________________
+
+// parseYAML method parses the YAML document into node tree and converts it
+// into forge section. Node tree is used instead of Go values, so that scalar
+// values such as `90s`, `2018-01-02` are kept as written.
+func parseYAML(r io.Reader) (*forge.Section, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if err == io.EOF { // empty document
+			return forge.NewSection(), nil
+		}
+		return nil, err
+	}
+
+	root := forge.NewSection()
+	if len(doc.Content) == 0 {
+		return root, nil
+	}
+	n := yamlResolveAlias(doc.Content[0])
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null" {
+		return root, nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: root must be a mapping", n.Line)
+	}
+	if err := yamlMapping(root, n); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// yamlMapping method adds the mapping node values into given section.
+// Merge key `<<` values are added only if the key is not defined explicitly.
+func yamlMapping(sec *forge.Section, n *yaml.Node) error {
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], yamlResolveAlias(n.Content[i+1])
+		if k.ShortTag() == "!!merge" {
+			if v.Kind == yaml.SequenceNode {
+				for _, m := range v.Content {
+					merges = append(merges, yamlResolveAlias(m))
+				}
+			} else {
+				merges = append(merges, v)
+			}
+			continue
+		}
+		if err := yamlSet(sec, k.Value, v); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range merges {
+		if m.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: merge value must be a mapping", m.Line)
+		}
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			key := m.Content[i].Value
+			if _, err := sec.Get(key); err == nil {
+				continue
+			}
+			if err := yamlSet(sec, key, yamlResolveAlias(m.Content[i+1])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func yamlSet(sec *forge.Section, key string, n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return yamlMapping(sec.AddSection(key), n)
+	case yaml.SequenceNode:
+		list := forge.NewList()
+		for _, item := range n.Content {
+			item = yamlResolveAlias(item)
+			if item.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: %s", item.Line, errMappingInList)
+			}
+			v, err := yamlScalar(item)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+		sec.Set(key, list)
+		return nil
+	}
+
+	v, err := yamlScalar(n)
+	if err != nil {
+		return err
+	}
+	sec.Set(key, v)
+	return nil
+}
+
+func yamlScalar(n *yaml.Node) (forge.Value, error) {
+	switch n.ShortTag() {
+	case "!!null":
+		return forge.NewNull(), nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return nil, err
+		}
+		return forge.NewBoolean(b), nil
+	case "!!int":
+		var i int64
+		if err := n.Decode(&i); err != nil {
+			return nil, err
+		}
+		return forge.NewInteger(i), nil
+	case "!!float":
+		var f float64
+		if err := n.Decode(&f); err != nil {
+			return nil, err
+		}
+		return forge.NewFloat(f), nil
+	}
+	return forge.NewString(n.Value), nil
+}
+
+func yamlResolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// TOML
+//___________________________________
+
+func parseTOML(r io.Reader) (*forge.Section, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	if _, err = toml.Decode(string(b), &values); err != nil {
+		return nil, err
+	}
+
+	root := forge.NewSection()
+	if err = tomlTable(root, "", values); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// tomlTable method adds the table values into given section in sorted key
+// order, since decoded map does not keep the order of the document.
+func tomlTable(sec *forge.Section, path string, values map[string]interface{}) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		keyPath := strings.TrimPrefix(path+"."+k, ".")
+		switch v := values[k].(type) {
+		case map[string]interface{}:
+			if err := tomlTable(sec.AddSection(k), keyPath, v); err != nil {
+				return err
+			}
+		case []map[string]interface{}:
+			return fmt.Errorf("key '%s': array of tables is not supported", keyPath)
+		case []interface{}:
+			list := forge.NewList()
+			for _, item := range v {
+				iv, err := tomlValue(item)
+				if err != nil {
+					return fmt.Errorf("key '%s': %s", keyPath, err)
+				}
+				list.Append(iv)
+			}
+			sec.Set(k, list)
+		default:
+			tv, err := tomlValue(v)
+			if err != nil {
+				return fmt.Errorf("key '%s': %s", keyPath, err)
+			}
+			sec.Set(k, tv)
+		}
+	}
+	return nil
+}
+
+func tomlValue(v interface{}) (forge.Value, error) {
+	switch tv := v.(type) {
+	case string:
+		return forge.NewString(tv), nil
+	case bool:
+		return forge.NewBoolean(tv), nil
+	case int64:
+		return forge.NewInteger(tv), nil
+	case float64:
+		return forge.NewFloat(tv), nil
+	case time.Time:
+		if layout, found := tomlLocalLayouts()[tv.Location()]; found {
+			return forge.NewString(tv.Format(layout)), nil
+		}
+		return forge.NewString(tv.Format(time.RFC3339Nano)), nil
+	case map[string]interface{}, []map[string]interface{}:
+		return nil, errMappingInList
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+var (
+	tomlLocalOnce sync.Once
+	tomlLocals    map[*time.Location]string
+)
+
+// tomlLocalLayouts method returns the layouts of TOML local date and time
+// values by its decoded location. Locations are obtained by decoding the
+// sample values, so it does not rely on the TOML library internals.
+func tomlLocalLayouts() map[*time.Location]string {
+	tomlLocalOnce.Do(func() {
+		layouts := map[*time.Location]string{}
+		for _, s := range []struct{ value, layout string }{
+			{"2006-01-02", "2006-01-02"},
+			{"15:04:05", "15:04:05.999999999"},
+			{"2006-01-02T15:04:05", "2006-01-02T15:04:05.999999999"},
+		} {
+			var v map[string]interface{}
+			if _, err := toml.Decode("v = "+s.value, &v); err != nil {
+				continue
+			}
+			if t, ok := v["v"].(time.Time); ok && t.Location() != time.UTC {
+				if _, found := layouts[t.Location()]; !found {
+					layouts[t.Location()] = s.layout
+				}
+			}
+		}
+		tomlLocals = layouts
+	})
+	return tomlLocals
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFormatYAML(t *testing.T) {
+	assert.Equal(t, FormatYAML, FormatByExt("aah.yml"))
+	assert.Equal(t, FormatTOML, FormatByExt("aah.TOML"))
+	assert.Equal(t, FormatForge, FormatByExt("aah.conf"))
+
+	cfg, err := LoadFile(join(testdataBaseDir(), "app.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, "yamlapp", cfg.StringDefault("name", ""))
+	assert.Equal(t, "aah YAML app", cfg.StringDefault("desc", ""))
+	assert.Equal(t, "8080", cfg.StringDefault("server.port", ""))
+	assert.Equal(t, "90s", cfg.StringDefault("server.timeout.read", ""))
+	assert.Equal(t, "5mb", cfg.StringDefault("request.max_body_size", ""))
+	assert.True(t, cfg.BoolDefault("request.id.enable", false))
+	assert.Equal(t, 10, cfg.IntDefault("count", 0))
+	assert.Equal(t, float32(0.75), cfg.Float32Default("ratio", 0))
+	assert.Equal(t, "Hello\nWorld\n", cfg.StringDefault("message", ""))
+
+	v, found := cfg.Get("empty")
+	assert.True(t, found)
+	assert.Nil(t, v)
+
+	hosts, _ := cfg.StringList("hosts")
+	assert.Equal(t, []string{"localhost", "example.com"}, hosts)
+	allowed, _ := cfg.StringList("server.allowed")
+	assert.Equal(t, []string{"GET", "POST", "PUT"}, allowed)
+
+	assert.Nil(t, cfg.SetProfile("env.dev"))
+	assert.Equal(t, "debug", cfg.StringDefault("log.level", ""))
+	assert.Equal(t, "aah.log", cfg.StringDefault("log.file", ""))
+
+	_, err = ParseYAML("server:\n  port: 80\n   read: 1")
+	assert.Equal(t, "yaml: line 3: mapping values are not allowed in this context", err.Error())
+
+	_, err = ParseYAML("hosts:\n  - a\n  - {b: c}")
+	assert.Equal(t, "line 3: mapping in list is not supported", err.Error())
+
+	_, err = ParseYAML("- a\n- b")
+	assert.Equal(t, "line 1: root must be a mapping", err.Error())
+
+	// anchor, alias and merge key
+	cfg, err = ParseYAML("base: &base\n  port: 80\n  host: localhost\nprod:\n  <<: *base\n  port: 443\nhosts: [&h a, *h]")
+	assert.Nil(t, err)
+	assert.Equal(t, 443, cfg.IntDefault("prod.port", 0))
+	assert.Equal(t, "localhost", cfg.StringDefault("prod.host", ""))
+	hosts, _ = cfg.StringList("hosts")
+	assert.Equal(t, []string{"a", "a"}, hosts)
+
+	cfg, err = ParseYAML("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(cfg.Keys()))
+}
+
+func TestConfigFormatTOML(t *testing.T) {
+	cfg, err := LoadFile(join(testdataBaseDir(), "app.toml"))
+	assert.Nil(t, err)
+	assert.Equal(t, "tomlapp", cfg.StringDefault("name", ""))
+	assert.Equal(t, "aah TOML app", cfg.StringDefault("desc", ""))
+	assert.Equal(t, 1000, cfg.IntDefault("count", 0))
+	assert.Equal(t, float32(0.75), cfg.Float32Default("ratio", 0))
+	assert.Equal(t, "8080", cfg.StringDefault("server.port", ""))
+	assert.Equal(t, "90s", cfg.StringDefault("server.timeout.read", ""))
+	assert.True(t, cfg.BoolDefault("request.id.enable", false))
+
+	allowed, _ := cfg.StringList("server.allowed")
+	assert.Equal(t, []string{"GET", "POST"}, allowed)
+
+	assert.Nil(t, cfg.SetProfile("env.dev"))
+	assert.Equal(t, "debug", cfg.StringDefault("log.level", ""))
+
+	_, err = ParseTOML("[[servers]]\nname = \"a\"")
+	assert.Equal(t, "key 'servers': array of tables is not supported", err.Error())
+
+	_, err = ParseTOML("name = unquoted")
+	assert.Equal(t, `toml: line 1 (last key "name"): expected value but found "unquoted" instead`, err.Error())
+
+	_, err = ParseTOML("name = \"a\"\n[name]")
+	assert.Equal(t, "toml: line 2: Key 'name' has already been defined.", err.Error())
+
+	cfg, err = ParseTOML("date = 2018-01-02\ntime = 07:32:00\nat = 2018-01-02T07:32:00Z\nlocal = 2018-01-02T07:32:00.5")
+	assert.Nil(t, err)
+	assert.Equal(t, "2018-01-02", cfg.StringDefault("date", ""))
+	assert.Equal(t, "07:32:00", cfg.StringDefault("time", ""))
+	assert.Equal(t, "2018-01-02T07:32:00Z", cfg.StringDefault("at", ""))
+	assert.Equal(t, "2018-01-02T07:32:00.5", cfg.StringDefault("local", ""))
+}
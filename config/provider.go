@@ -0,0 +1,147 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider interface is used to fetch the configuration from remote source
+// such as Consul KV, etcd, Vault, etc. Provider config values are merged on
+// top of local config files.
+type Provider interface {
+	// Name method returns the provider name, used for logging.
+	Name() string
+
+	// Load method fetches and parses the configuration from provider.
+	Load() (*Config, error)
+}
+
+// WatchProvider interface is implemented by the providers which supports
+// change notification natively, for e.g.: Consul blocking queries, etcd
+// watch. Otherwise provider is polled periodically for changes.
+type WatchProvider interface {
+	Provider
+
+	// Watch method blocks and calls the `onChange` on every configuration
+	// change until `stop` channel is closed.
+	Watch(stop <-chan struct{}, onChange func()) error
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Consul KV Provider
+//______________________________________________________________________________
+
+var _ WatchProvider = (*ConsulProvider)(nil)
+
+// ConsulProvider fetches the configuration (forge syntax) stored in the
+// Consul KV `Key` via Consul HTTP API. It watches the key changes using
+// Consul blocking queries.
+type ConsulProvider struct {
+	// Address is Consul HTTP API address, for e.g.: `http://127.0.0.1:8500`.
+	Address string
+
+	// Key is Consul KV key name, for e.g.: `myapp/aah.conf`.
+	Key string
+
+	// Token is Consul ACL token, optional.
+	Token string
+
+	// WaitTime is blocking query wait time. Default value is `5m`.
+	WaitTime time.Duration
+
+	// Client is HTTP client used for requests. Default is `http.DefaultClient`.
+	Client *http.Client
+}
+
+// Name method returns the provider name.
+func (c *ConsulProvider) Name() string {
+	return "consul:" + c.Key
+}
+
+// Load method fetches the configuration from Consul KV.
+func (c *ConsulProvider) Load() (*Config, error) {
+	b, _, err := c.get(0)
+	if err != nil {
+		return nil, err
+	}
+	return ParseString(string(b))
+}
+
+// Watch method watches the Consul KV key using blocking queries.
+func (c *ConsulProvider) Watch(stop <-chan struct{}, onChange func()) error {
+	_, index, err := c.get(0)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		_, nindex, err := c.get(index)
+		if err != nil || nindex == 0 {
+			// Consul unreachable or not a blocking response, back-off
+			// before next attempt
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		if nindex != index {
+			index = nindex
+			onChange()
+		}
+	}
+}
+
+func (c *ConsulProvider) get(index uint64) ([]byte, uint64, error) {
+	u := strings.TrimSuffix(c.Address, "/") + "/v1/kv/" + strings.TrimPrefix(c.Key, "/") + "?raw"
+	if index > 0 {
+		wait := c.WaitTime
+		if wait <= 0 {
+			wait = 5 * time.Minute
+		}
+		u += fmt.Sprintf("&index=%d&wait=%ds", index, int(wait.Seconds()))
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(c.Token) > 0 {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("config: consul key '%s' responded with status %d", c.Key, resp.StatusCode)
+	}
+
+	nindex, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+	return b, nindex, nil
+}
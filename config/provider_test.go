@@ -0,0 +1,60 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsulProvider(t *testing.T) {
+	var index int64 = 10
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/myapp/aah.conf" || r.Header.Get("X-Consul-Token") != "secret" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("index") != "" {
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt64(&index, 1)
+		}
+		w.Header().Set("X-Consul-Index", strconv.FormatInt(atomic.LoadInt64(&index), 10))
+		_, _ = w.Write([]byte(`server { port = "9090"; }`))
+	}))
+	defer ts.Close()
+
+	p := &ConsulProvider{Address: ts.URL + "/", Key: "/myapp/aah.conf", Token: "secret"}
+	assert.Equal(t, "consul:/myapp/aah.conf", p.Name())
+	cfg, err := p.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "9090", cfg.StringDefault("server.port", ""))
+
+	stop := make(chan struct{})
+	changed := make(chan struct{}, 1)
+	go func() {
+		_ = p.Watch(stop, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+	}()
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("consul watch change not received")
+	}
+	close(stop)
+
+	p.Token = ""
+	_, err = p.Load()
+	assert.Equal(t, "config: consul key '/myapp/aah.conf' responded with status 404", err.Error())
+}
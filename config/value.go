@@ -0,0 +1,113 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"aahframe.work/essentials"
+)
+
+// base64Encodings are the accepted base64 encodings in the order, padded and
+// unpadded of standard and URL alphabet.
+var base64Encodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.URLEncoding,
+	base64.RawStdEncoding,
+	base64.RawURLEncoding,
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Typed value methods
+//______________________________________________________________________________
+
+// ByteSize gets the byte size value for the given key from the configuration,
+// for e.g.: `512kb`, `5mb`, `1GiB`. Integer value is treated as bytes.
+// It returns error with key name if value is not a valid size.
+//
+//	max_body_size = "5mb"
+//
+//	size, found, err := cfg.ByteSize("max_body_size")
+func (c *Config) ByteSize(key string) (int64, bool, error) {
+	v, found := c.Get(key)
+	if !found {
+		return 0, false, nil
+	}
+	switch tv := v.(type) {
+	case int64:
+		if tv >= 0 {
+			return tv, true, nil
+		}
+	case string:
+		if size, err := ess.StrToBytes(tv); err == nil {
+			return size, true, nil
+		}
+	}
+	return 0, true, fmt.Errorf("config: '%s' value '%v' is invalid, expected size with unit (e.g. 512kb, 5mb)", key, v)
+}
+
+// ByteSizeDefault gets the byte size value for the given key from the
+// configuration. If key does not exists it returns default value.
+func (c *Config) ByteSizeDefault(key string, defaultValue int64) (int64, error) {
+	size, found, err := c.ByteSize(key)
+	if !found {
+		return defaultValue, nil
+	}
+	return size, err
+}
+
+// Base64 gets the base64 decoded bytes for the given key from the
+// configuration, for e.g.: keys, salts and HMAC secrets. It accepts standard
+// and URL alphabet with or without padding. It returns error with key name
+// if value is not a valid base64.
+func (c *Config) Base64(key string) ([]byte, bool, error) {
+	s, found, err := c.encodedString(key, "base64")
+	if !found || err != nil {
+		return nil, found, err
+	}
+	for _, enc := range base64Encodings {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, true, nil
+		}
+	}
+	return nil, true, fmt.Errorf("config: '%s' value is invalid, expected base64", key)
+}
+
+// Hex gets the hex decoded bytes for the given key from the configuration,
+// for e.g.: keys, salts and HMAC secrets. Prefix `0x` is optional. It returns
+// error with key name if value is not a valid hex.
+func (c *Config) Hex(key string) ([]byte, bool, error) {
+	s, found, err := c.encodedString(key, "hex")
+	if !found || err != nil {
+		return nil, found, err
+	}
+	if len(s) > 1 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, true, fmt.Errorf("config: '%s' value is invalid, expected hex", key)
+	}
+	return b, true, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func (c *Config) encodedString(key, expected string) (string, bool, error) {
+	v, found := c.Get(key)
+	if !found {
+		return "", false, nil
+	}
+	s, ok := v.(string)
+	if !ok || len(strings.TrimSpace(s)) == 0 {
+		return "", true, fmt.Errorf("config: '%s' value is invalid, expected %s", key, expected)
+	}
+	return strings.TrimSpace(s), true, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigByteSize(t *testing.T) {
+	cfg, err := ParseString(`
+	max_body_size = "5mb"
+	buffer_size = 4096
+	frame_size = "1GiB"
+	invalid_size = "5 megs"
+	negative_size = -1
+	flag = true
+	`)
+	assert.Nil(t, err)
+
+	size, found, err := cfg.ByteSize("max_body_size")
+	assert.True(t, found)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5242880), size)
+
+	size, found, err = cfg.ByteSize("buffer_size")
+	assert.True(t, found)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4096), size)
+
+	size, err = cfg.ByteSizeDefault("frame_size", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1073741824), size)
+
+	size, err = cfg.ByteSizeDefault("not_exists", 1024)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1024), size)
+
+	for _, key := range []string{"invalid_size", "negative_size", "flag"} {
+		_, found, err = cfg.ByteSize(key)
+		assert.True(t, found)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "config: '"+key+"' value")
+	}
+
+	// environment variable override
+	_ = os.Setenv("AAHTEST_MAX_BODY_SIZE", "10kb")
+	defer func() { _ = os.Unsetenv("AAHTEST_MAX_BODY_SIZE") }()
+	_, err = cfg.ApplyEnvOverrides("AAHTEST")
+	assert.Nil(t, err)
+	size, err = cfg.ByteSizeDefault("max_body_size", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10240), size)
+}
+
+func TestConfigBase64Hex(t *testing.T) {
+	cfg, err := ParseString(`
+	security {
+	  std_key = "c2VjcmV0IGtleSE/Pz8="
+	  url_key = "c2VjcmV0IGtleSE_Pz8"
+	  hex_key = "73656372657420"
+	  hex_prefix_key = "0x736563"
+	  invalid = "not encoded !"
+	  empty = ""
+	  number = 10
+	}
+	`)
+	assert.Nil(t, err)
+
+	b, found, err := cfg.Base64("security.std_key")
+	assert.True(t, found)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret key!???", string(b))
+
+	b, _, err = cfg.Base64("security.url_key")
+	assert.Nil(t, err)
+	assert.Equal(t, "secret key!???", string(b))
+
+	b, found, err = cfg.Hex("security.hex_key")
+	assert.True(t, found)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret ", string(b))
+
+	b, _, err = cfg.Hex("security.hex_prefix_key")
+	assert.Nil(t, err)
+	assert.Equal(t, "sec", string(b))
+
+	b, found, err = cfg.Base64("security.not_exists")
+	assert.False(t, found)
+	assert.Nil(t, err)
+	assert.Nil(t, b)
+
+	_, _, err = cfg.Base64("security.invalid")
+	assert.Equal(t, "config: 'security.invalid' value is invalid, expected base64", err.Error())
+	_, _, err = cfg.Hex("security.invalid")
+	assert.Equal(t, "config: 'security.invalid' value is invalid, expected hex", err.Error())
+	_, _, err = cfg.Hex("security.empty")
+	assert.Equal(t, "config: 'security.empty' value is invalid, expected hex", err.Error())
+	_, _, err = cfg.Base64("security.number")
+	assert.Equal(t, "config: 'security.number' value is invalid, expected base64", err.Error())
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"aahframe.work/ahttp"
+)
+
+// limitRetryAfter is the `Retry-After` header value in seconds of
+// connection and concurrent request limit responses.
+const limitRetryAfter = "1"
+
+type connOverLimitKey struct{}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// LimitMetrics method returns the snapshot of server connection and
+// concurrent request limit metrics, see `server.max_connections` and
+// `server.max_concurrent_requests`.
+func (a *Application) LimitMetrics() LimitMetrics {
+	return LimitMetrics{
+		Connections:         atomic.LoadInt64(&a.limits.conns),
+		InFlight:            atomic.LoadInt64(&a.limits.inFlight),
+		RejectedConnections: atomic.LoadInt64(&a.limits.rejectedConns),
+		RejectedRequests:    atomic.LoadInt64(&a.limits.rejectedReqs),
+	}
+}
+
+// LimitMetrics holds the server connection and concurrent request limit
+// metrics.
+type LimitMetrics struct {
+	Connections         int64
+	InFlight            int64
+	RejectedConnections int64
+	RejectedRequests    int64
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// limitConnections method sets the connection tracking hooks on given
+// server. Connection accepted beyond `server.max_connections` is marked, its
+// requests are responded with `503 Service Unavailable` and closed. Value
+// zero means no limit.
+func (a *Application) limitConnections(srv *http.Server) {
+	srv.ConnContext = func(ctx context.Context, c net.Conn) context.Context {
+		n := atomic.AddInt64(&a.limits.conns, 1)
+		if max := int64(a.settings().MaxConnections); max > 0 && n > max {
+			atomic.AddInt64(&a.limits.rejectedConns, 1)
+			return context.WithValue(ctx, connOverLimitKey{}, true)
+		}
+		return ctx
+	}
+	srv.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateClosed || state == http.StateHijacked {
+			atomic.AddInt64(&a.limits.conns, -1)
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Limiter
+//______________________________________________________________________________
+
+type limiter struct {
+	conns         int64
+	inFlight      int64
+	rejectedConns int64
+	rejectedReqs  int64
+}
+
+// acquire method returns false with `503 Service Unavailable` reply if the
+// request connection is over the limit or concurrent requests exceeds
+// `server.max_concurrent_requests`. Otherwise `release` has to be called
+// once request is completed.
+func (l *limiter) acquire(ctx *Context) bool {
+	if over, _ := ctx.Req.Context().Value(connOverLimitKey{}).(bool); over {
+		ctx.Log().Warnf("Connection limit exceeded, request rejected, Path: %s", ctx.Req.Path)
+		ctx.Reply().
+			Header(ahttp.HeaderConnection, "close").
+			Header(ahttp.HeaderRetryAfter, limitRetryAfter).
+			ServiceUnavailable().
+			Error(newError(ErrConnectionLimitExceeded, http.StatusServiceUnavailable))
+		return false
+	}
+
+	n := atomic.AddInt64(&l.inFlight, 1)
+	if max := int64(ctx.a.settings().MaxConcurrentRequests); max > 0 && n > max {
+		atomic.AddInt64(&l.inFlight, -1)
+		atomic.AddInt64(&l.rejectedReqs, 1)
+		ctx.Log().Warnf("Concurrent request limit exceeded, request rejected, Path: %s", ctx.Req.Path)
+		ctx.Reply().
+			Header(ahttp.HeaderRetryAfter, limitRetryAfter).
+			ServiceUnavailable().
+			Error(newError(ErrConcurrencyExceeded, http.StatusServiceUnavailable))
+		return false
+	}
+	return true
+}
+
+func (l *limiter) release() {
+	atomic.AddInt64(&l.inFlight, -1)
+}
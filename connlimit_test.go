@@ -0,0 +1,74 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerLimits(t *testing.T) {
+	a := newWebApp1TestApp(t)
+	a.Config().SetInt("server.max_connections", 1)
+	a.Config().SetInt("server.max_concurrent_requests", 1)
+	err := a.settingsHolder.Refresh(a.Config())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, a.settings().MaxConnections)
+	assert.Equal(t, 1, a.settings().MaxConcurrentRequests)
+
+	newCtx := func(c context.Context) *Context {
+		r := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil)
+		ctx := newContext(httptest.NewRecorder(), r.WithContext(c))
+		ctx.a = a
+		return ctx
+	}
+
+	// concurrent requests
+	ctx1 := newCtx(context.Background())
+	assert.True(t, a.limits.acquire(ctx1))
+	ctx2 := newCtx(context.Background())
+	assert.False(t, a.limits.acquire(ctx2))
+	assert.Equal(t, http.StatusServiceUnavailable, ctx2.Reply().Code)
+	assert.Equal(t, ErrConcurrencyExceeded, ctx2.Reply().err.Reason)
+	assert.Equal(t, "1", ctx2.Res.Header().Get(ahttp.HeaderRetryAfter))
+	a.limits.release()
+	assert.True(t, a.limits.acquire(newCtx(context.Background())))
+	a.limits.release()
+
+	// connections
+	srv := &http.Server{}
+	a.limitConnections(srv)
+	c1 := srv.ConnContext(context.Background(), nil)
+	c2 := srv.ConnContext(context.Background(), nil)
+	assert.True(t, a.limits.acquire(newCtx(c1)))
+	a.limits.release()
+	ctx := newCtx(c2)
+	assert.False(t, a.limits.acquire(ctx))
+	assert.Equal(t, ErrConnectionLimitExceeded, ctx.Reply().err.Reason)
+	assert.Equal(t, "close", ctx.Res.Header().Get(ahttp.HeaderConnection))
+	assert.Equal(t, "1", ctx.Res.Header().Get(ahttp.HeaderRetryAfter))
+
+	assert.Equal(t, LimitMetrics{Connections: 2, RejectedConnections: 1, RejectedRequests: 1}, a.LimitMetrics())
+	srv.ConnState(nil, http.StateClosed)
+	srv.ConnState(nil, http.StateHijacked)
+	srv.ConnState(nil, http.StateIdle)
+	assert.Equal(t, int64(0), a.LimitMetrics().Connections)
+
+	// no limit
+	a.settingsHolder.Update(func(s *settings.Settings) { s.MaxConcurrentRequests = 0 })
+	assert.True(t, a.limits.acquire(newCtx(context.Background())))
+	assert.True(t, a.limits.acquire(newCtx(context.Background())))
+	assert.Equal(t, int64(2), a.LimitMetrics().InFlight)
+
+	a.Config().SetInt("server.max_connections", -1)
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.NotNil(t, err)
+}
@@ -5,11 +5,13 @@
 package aah
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/ainsp"
@@ -19,12 +21,14 @@ import (
 	"aahframe.work/security"
 	"aahframe.work/security/authz"
 	"aahframe.work/security/session"
+	"aahframe.work/soap"
 )
 
 var (
 	_ ess.Valuer = (*Context)(nil)
 
 	ctxPtrType = reflect.TypeOf((*Context)(nil))
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
 
 	errTargetNotFound = errors.New("target not found")
 )
@@ -65,7 +69,11 @@ type Context struct {
 	values     map[string]interface{}
 	abort      bool
 	decorated  bool
+	deferBody  bool
+	rawResBody bool
 	logger     log.Loggerer
+	clientCtx  context.Context
+	clientGone *clientGone
 }
 
 // Reply method gives you control and convenient way to write
@@ -234,7 +242,14 @@ func (ctx *Context) reset() {
 	ctx.values = nil
 	ctx.abort = false
 	ctx.decorated = false
+	ctx.deferBody = false
+	ctx.rawResBody = false
 	ctx.logger = nil
+	ctx.clientCtx = nil
+	if ctx.clientGone != nil {
+		ctx.clientGone.finish()
+		ctx.clientGone = nil
+	}
 }
 
 // Set method is used to set value for the given key in the current request flow.
@@ -250,31 +265,93 @@ func (ctx *Context) Get(key string) interface{} {
 	return ctx.values[key]
 }
 
-// Log method adds field `Request ID` into current log context and returns
-// the logger.
+// Log method adds fields `Request ID` and `Trace ID` (if tracing is enabled)
+// into current log context and returns the logger. Logging level is of
+// matching request path prefix if it's set, see `Application.SetLogLevel`.
 func (ctx *Context) Log() log.Loggerer {
 	if ctx.logger == nil {
-		if h := ctx.Req.Header[ctx.a.settings.RequestIDHeaderKey]; len(h) > 0 {
-			ctx.logger = ctx.a.Log().WithFields(log.Fields{
-				"reqid": h[0],
-			})
+		logger := ctx.a.logLevels.logger(ctx.Req.Path)
+		if logger == nil {
+			logger = ctx.a.Log()
+		}
+		fields := make(log.Fields)
+		if h := ctx.Req.Header[ctx.a.settings().RequestIDHeaderKey]; len(h) > 0 {
+			fields["reqid"] = h[0]
+		}
+		if span := ctx.Span(); span != nil {
+			fields["traceid"] = span.SpanContext().TraceID.String()
+		}
+		if len(fields) > 0 {
+			ctx.logger = logger.WithFields(fields)
 		} else {
-			ctx.logger = ctx.a.Log()
+			ctx.logger = logger
 		}
 	}
 	return ctx.logger
 }
 
+// OnClientGone method registers the callback func, it's called when the
+// client connection is closed before the request is completed. So that
+// long-running actions (exports, SSE, etc.) can abort the work early instead
+// of computing a response nobody will receive.
+//
+// Callback is called from a separate goroutine and at most once, it's not
+// called after the request is completed or when request deadline exceeds.
+//
+// Note: For HTTP/1.x, Go server detects the closed connection only after
+// the request body is read completely.
+func (ctx *Context) OnClientGone(fn func()) {
+	if fn == nil {
+		return
+	}
+	if ctx.clientGone == nil {
+		// Watch the connection's request context, since request and route
+		// timeout contexts are cancelled on completion of normal request too
+		reqCtx := ctx.clientCtx
+		if reqCtx == nil {
+			reqCtx = ctx.Req.Context()
+		}
+		ctx.clientGone = &clientGone{done: make(chan struct{})}
+		go ctx.clientGone.watch(reqCtx, ctx.Log())
+	}
+	ctx.clientGone.add(fn)
+}
+
+// SOAPEnvelope method parses the SOAP envelope from request body. Use
+// `Envelope.DecodeBody` to unmarshal the operation and reply with
+// `Reply().SOAP`. Define the action without struct parameter, so that
+// request body is not bound by aah.
+//
+//	env, err := ctx.SOAPEnvelope()
+//	if err != nil {
+//		ctx.Reply().SOAP(soap.NewFault(soap.FaultClient, err.Error()))
+//		return
+//	}
+func (ctx *Context) SOAPEnvelope() (*soap.Envelope, error) {
+	return soap.Decode(ctx.Req.Body())
+}
+
+// SOAPAction method returns the SOAP action of request, it's header
+// `SOAPAction` for SOAP 1.1 and `action` parameter of `Content-Type` for
+// SOAP 1.2. Surrounding quotes are removed.
+func (ctx *Context) SOAPAction() string {
+	action := ctx.Req.Header.Get("SOAPAction")
+	if len(action) == 0 {
+		action = ctx.Req.ContentType().GetParam("action")
+	}
+	return strings.Trim(action, `"`)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Context Unexported methods
 //______________________________________________________________________________
 
 func (ctx *Context) setRequestID() {
-	h := ctx.Req.Header[ctx.a.settings.RequestIDHeaderKey]
+	h := ctx.Req.Header[ctx.a.settings().RequestIDHeaderKey]
 	if len(h) == 0 {
 		guid := ess.NewGUID()
-		ctx.Req.Header.Set(ctx.a.settings.RequestIDHeaderKey, guid)
-		ctx.Reply().Header(ctx.a.settings.RequestIDHeaderKey, guid)
+		ctx.Req.Header.Set(ctx.a.settings().RequestIDHeaderKey, guid)
+		ctx.Reply().Header(ctx.a.settings().RequestIDHeaderKey, guid)
 		return
 	}
 	ctx.Log().Debugf("Request already has traceability ID: %v", h[0])
@@ -319,11 +396,21 @@ func (ctx *Context) detectContentType() string {
 	acceptContType := ctx.Req.AcceptContentType()
 	if acceptContType.Mime == "" || acceptContType.Mime == "*/*" {
 		// as per 'render.default' from aah.conf
-		return ctx.a.settings.DefaultContentType
+		return ctx.a.settings().DefaultContentType
 	}
 	return acceptContType.String()
 }
 
+// writeTrailers method writes the declared trailer values, it must be
+// called after the response body is written.
+func (ctx *Context) writeTrailers() {
+	for _, t := range ctx.Reply().trailers {
+		if v := t.fn(); len(v) > 0 {
+			ctx.Res.Header().Set(t.key, v)
+		}
+	}
+}
+
 // writeCookies method writes the user provided cookies and session cookie; also
 // saves the session data into session store if its stateful.
 func (ctx *Context) writeCookies() {
@@ -341,13 +428,13 @@ func (ctx *Context) writeCookies() {
 }
 
 func (ctx *Context) writeHeaders() {
-	if ctx.a.settings.ServerHeaderEnabled {
-		ctx.Res.Header().Set(ahttp.HeaderServer, ctx.a.settings.ServerHeader)
+	if ctx.a.settings().ServerHeaderEnabled {
+		ctx.Res.Header().Set(ahttp.HeaderServer, ctx.a.settings().ServerHeader)
 	}
 
 	// Write application security headers with many safe defaults and
 	// configured header values.
-	if ctx.a.settings.SecureHeadersEnabled {
+	if ctx.a.settings().SecureHeadersEnabled {
 		secureHeaders := ctx.a.SecurityManager().SecureHeaders
 		// Write common secure headers for all request
 		for header, value := range secureHeaders.Common {
@@ -384,6 +471,14 @@ func (ctx *Context) writeHeaders() {
 			}
 		}
 	}
+
+	// Configured response header rules, global and then route
+	if hr := ctx.a.respHeaders; hr != nil {
+		hr.Apply(ctx.Res.Header())
+	}
+	if ctx.route != nil && ctx.route.Headers != nil {
+		ctx.route.Headers.Apply(ctx.Res.Header())
+	}
 }
 
 // hasAccess method checks the subject's access by defined access rule in the
@@ -391,3 +486,65 @@ func (ctx *Context) writeHeaders() {
 func (ctx *Context) hasAccess() (bool, []*authz.Reason) {
 	return ctx.route.HasAccess(ctx.Subject())
 }
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// clientGone
+//______________________________________________________________________________
+
+type clientGone struct {
+	sync.Mutex
+	done     chan struct{}
+	finished bool
+	fns      []func()
+}
+
+func (cg *clientGone) add(fn func()) {
+	cg.Lock()
+	defer cg.Unlock()
+	cg.fns = append(cg.fns, fn)
+}
+
+func (cg *clientGone) finish() {
+	cg.Lock()
+	defer cg.Unlock()
+	if !cg.finished {
+		cg.finished = true
+		close(cg.done)
+	}
+}
+
+func (cg *clientGone) watch(reqCtx context.Context, logger log.Loggerer) {
+	select {
+	case <-cg.done:
+		return
+	case <-reqCtx.Done():
+	}
+
+	// Deadline exceeded is not a client disconnect
+	if reqCtx.Err() != context.Canceled {
+		return
+	}
+
+	cg.Lock()
+	if cg.finished {
+		cg.Unlock()
+		return
+	}
+	cg.finished = true
+	close(cg.done)
+	fns := cg.fns
+	cg.Unlock()
+
+	for _, fn := range fns {
+		cg.call(fn, logger)
+	}
+}
+
+func (cg *clientGone) call(fn func(), logger log.Loggerer) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("panic occurred in client gone callback: %v", r)
+		}
+	}()
+	fn()
+}
@@ -5,9 +5,11 @@
 package aah
 
 import (
+	"context"
 	"io/ioutil"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
@@ -105,3 +107,47 @@ func TestContextSetMethod(t *testing.T) {
 	ctx.SetMethod("nomethod")
 	assert.Equal(t, "GET", ctx.Req.Method)
 }
+
+func TestContextOnClientGone(t *testing.T) {
+	a := newApp()
+	a.cfg = config.NewEmpty()
+	err := a.initLog()
+	assert.Nil(t, err)
+	a.Log().(*log.Logger).SetWriter(ioutil.Discard)
+
+	// client closes the connection
+	reqCtx, cancel := context.WithCancel(context.Background())
+	ctx := newContext(nil, httptest.NewRequest("GET", "http://localhost:8080/export", nil).WithContext(reqCtx))
+	ctx.a = a
+	called := make(chan bool, 2)
+	ctx.OnClientGone(nil)
+	ctx.OnClientGone(func() { panic("callback panic is recovered") })
+	ctx.OnClientGone(func() { called <- true })
+	cancel()
+	select {
+	case <-called:
+	case <-time.After(2 * time.Second):
+		t.Error("client gone callback is not called")
+	}
+
+	// request completed
+	reqCtx, cancel = context.WithCancel(context.Background())
+	ctx = newContext(nil, httptest.NewRequest("GET", "http://localhost:8080/export", nil).WithContext(reqCtx))
+	ctx.a = a
+	ctx.OnClientGone(func() { called <- true })
+	ctx.reset()
+	assert.Nil(t, ctx.clientGone)
+	cancel()
+
+	// request deadline exceeded
+	reqCtx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	ctx = newContext(nil, httptest.NewRequest("GET", "http://localhost:8080/export", nil).WithContext(reqCtx))
+	ctx.a = a
+	ctx.OnClientGone(func() { called <- true })
+	select {
+	case <-called:
+		t.Error("client gone callback should not be called")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"aahframe.work/ainsp"
+	"aahframe.work/router"
+	"aahframe.work/security/anticsrf"
+)
+
+const (
+	debugPprofTarget = "aahframe.work/debugController.Pprof"
+	debugVarsTarget  = "aahframe.work/debugController.Vars"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initDebug method returns the route definitions of `net/http/pprof` and
+// `expvar` endpoints from config `server.debug.pprof { ... }`. Endpoints are
+// served as application routes, so configured auth scheme is applied as
+// usual. It requires `allow_ips` or `auth` config. Enable it on specific
+// environment profile, for e.g.: `env.prod.server.debug.pprof.enable`.
+//
+//	server {
+//	  debug {
+//	    pprof {
+//	      enable = true
+//	      prefix = "/debug"
+//	      allow_ips = ["127.0.0.1", "10.0.0.0/8"]
+//	      auth = "admin_basic"
+//	    }
+//	  }
+//	}
+func (a *Application) initDebug() ([]*router.Definition, error) {
+	keyPrefix := "server.debug.pprof"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.debugAllowIPs = nil
+		return nil, nil
+	}
+
+	prefix := strings.TrimSuffix(cfg.StringDefault(keyPrefix+".prefix", "/debug"), "/")
+	if !strings.HasPrefix(prefix, "/") {
+		return nil, fmt.Errorf("'%s.prefix' value must begin with '/'", keyPrefix)
+	}
+
+	var allowIPs []*net.IPNet
+	ips, _ := cfg.StringList(keyPrefix + ".allow_ips")
+	for _, v := range ips {
+		ipNet, err := parseIPNet(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("'%s.allow_ips' has invalid value '%s'", keyPrefix, v)
+		}
+		allowIPs = append(allowIPs, ipNet)
+	}
+	auth := cfg.StringDefault(keyPrefix+".auth", "")
+	if len(allowIPs) == 0 && len(auth) == 0 {
+		return nil, fmt.Errorf("'%s' requires 'allow_ips' or 'auth' config", keyPrefix)
+	}
+	if len(auth) == 0 {
+		auth = "anonymous"
+	}
+
+	a.HTTPEngine().registry.Add((*debugController)(nil), []*ainsp.Method{{Name: "Pprof"}, {Name: "Vars"}})
+	var defs []*router.Definition
+	for _, r := range []struct {
+		method, path, target, name string
+	}{
+		{http.MethodGet, prefix + "/pprof/", debugPprofTarget, "aah_debug_pprof_index"},
+		{http.MethodGet, prefix + "/pprof/*name", debugPprofTarget, "aah_debug_pprof"},
+		{http.MethodPost, prefix + "/pprof/*name", debugPprofTarget, "aah_debug_pprof_post"},
+		{http.MethodGet, prefix + "/vars", debugVarsTarget, "aah_debug_vars"},
+	} {
+		d, err := router.NewDefinition(r.method, r.path, r.target,
+			router.WithName(r.name),
+			router.WithAuth(auth),
+			router.WithAntiCSRFPolicy(anticsrf.PolicyExempt))
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(r.path, "/") {
+			// pprof index page uses relative links to profiles
+			d.Route.Path += "/"
+		}
+		defs = append(defs, d)
+	}
+
+	a.debugAllowIPs = allowIPs
+	return defs, nil
+}
+
+// isDebugAllowed method returns true if `server.debug.pprof.allow_ips` is
+// not configured or client IP address is allowed. Client IP address is taken
+// from connection remote address, since proxy headers can be spoofed.
+func (a *Application) isDebugAllowed(r *http.Request) bool {
+	allowIPs := a.debugAllowIPs
+	if len(allowIPs) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range allowIPs {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseIPNet(v string) (*net.IPNet, error) {
+	if strings.Contains(v, "/") {
+		_, ipNet, err := net.ParseCIDR(v)
+		return ipNet, err
+	}
+	ip := net.ParseIP(v)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip address: %s", v)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Debug controller
+//______________________________________________________________________________
+
+// debugController serves the `net/http/pprof` and `expvar` routes
+// registered from config `server.debug.pprof { ... }`.
+type debugController struct {
+	*Context
+}
+
+// Pprof method serves the pprof index and profiles, response is written
+// directly.
+func (c *debugController) Pprof() {
+	if !c.allowed() {
+		return
+	}
+
+	c.Reply().Done()
+	w, r := c.Res, c.Req.Unwrap()
+	switch name := strings.TrimPrefix(c.Req.PathValue("name"), "/"); name {
+	case "":
+		pprof.Index(w, r)
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	case "profile":
+		pprof.Profile(w, r)
+	case "symbol":
+		pprof.Symbol(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	default:
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
+
+// Vars method serves the `expvar` variables in JSON format, response is
+// written directly.
+func (c *debugController) Vars() {
+	if !c.allowed() {
+		return
+	}
+
+	c.Reply().Done()
+	expvar.Handler().ServeHTTP(c.Res, c.Req.Unwrap())
+}
+
+func (c *debugController) allowed() bool {
+	if c.a.isDebugAllowed(c.Req.Unwrap()) {
+		return true
+	}
+	c.Log().Warnf("Debug endpoint access denied, Path: %s, Remote Address: %s", c.Req.Path, c.Req.Unwrap().RemoteAddr)
+	c.Reply().Forbidden().Error(newError(ErrAccessDenied, http.StatusForbidden))
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugPprofEndpoints(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Debug Pprof]: %s", ts.URL)
+
+	// not enabled
+	resp, err := http.Get(ts.URL + "/debug/vars")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  debug {
+	    pprof {
+	      enable = true
+	      allow_ips = ["127.0.0.1", "::1"]
+	    }
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initRouter())
+	assert.NotNil(t, ts.app.Router().RootDomain().LookupByName("aah_debug_pprof"))
+
+	get := func(path string) (*http.Response, string) {
+		resp, err := http.Get(ts.URL + path)
+		assert.Nil(t, err)
+		return resp, responseBody(resp)
+	}
+
+	resp, body := get("/debug/pprof/")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.Contains(body, "Types of profiles available"))
+
+	resp, body = get("/debug/pprof/goroutine?debug=1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.Contains(body, "goroutine profile"))
+
+	resp, body = get("/debug/vars")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.Contains(body, `"memstats"`))
+
+	// custom prefix, client IP not allowed
+	ts.app.Config().SetString("server.debug.pprof.prefix", "/_aah/debug")
+	assert.Nil(t, mergeTestConfig(ts.app, `server { debug { pprof { allow_ips = ["10.0.0.0/8"]; } } }`))
+	assert.Nil(t, ts.app.initRouter())
+
+	resp, _ = get("/_aah/debug/pprof/heap?debug=1")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = get("/debug/vars")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// init errors
+	ts.app.Config().SetString("server.debug.pprof.prefix", "debug")
+	_, err = ts.app.initDebug()
+	assert.Equal(t, "'server.debug.pprof.prefix' value must begin with '/'", err.Error())
+
+	ts.app.Config().SetString("server.debug.pprof.prefix", "/debug")
+	assert.Nil(t, mergeTestConfig(ts.app, `server { debug { pprof { allow_ips = ["10.0.0.300"]; } } }`))
+	_, err = ts.app.initDebug()
+	assert.Equal(t, "'server.debug.pprof.allow_ips' has invalid value '10.0.0.300'", err.Error())
+
+	assert.Nil(t, setTestConfig(ts.app, `server { debug { pprof { enable = true; } } }`))
+	_, err = ts.app.initDebug()
+	assert.Equal(t, "'server.debug.pprof' requires 'allow_ips' or 'auth' config", err.Error())
+}
@@ -0,0 +1,314 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package discovery provides DNS based service discovery for outbound targets
+// of aah application. Service endpoints are resolved via DNS SRV records,
+// works with Consul DNS interface too. Resolved endpoints are refreshed
+// periodically and picked by load balancing strategy `round_robin` or
+// `least_conn`.
+//
+//	discovery {
+//	  services {
+//	    orders {
+//	      srv = "_orders._tcp.service.consul"
+//	      dns_server = "127.0.0.1:8600"
+//	      scheme = "http"
+//	      refresh = "30s"
+//	      strategy = "least_conn"
+//	    }
+//	  }
+//	}
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+	"aahframe.work/log"
+)
+
+// Load balancing strategies
+const (
+	StrategyRoundRobin = "round_robin"
+	StrategyLeastConn  = "least_conn"
+)
+
+var (
+	// ErrNoEndpoints returned when service does not have any resolved endpoints.
+	ErrNoEndpoints = errors.New("aah/discovery: no endpoints available")
+
+	// ErrServiceNotFound returned when service is not configured.
+	ErrServiceNotFound = errors.New("aah/discovery: service not found")
+)
+
+// LookupSRVFunc func type is used to resolve the SRV records of given name.
+type LookupSRVFunc func(ctx context.Context, name string) ([]*net.SRV, error)
+
+// New method creates the discovery manager from the config `discovery { ... }`.
+// Returns nil manager if services are not configured.
+func New(appCfg *config.Config, logger log.Loggerer) (*Manager, error) {
+	keyPrefix := "discovery.services"
+	names := appCfg.KeysByPath(keyPrefix)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	m := &Manager{services: make(map[string]*Service), logger: logger}
+	for _, name := range names {
+		s, err := newService(appCfg, keyPrefix+"."+name, name, logger)
+		if err != nil {
+			return nil, err
+		}
+		m.services[name] = s
+	}
+
+	return m, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Manager
+//______________________________________________________________________________
+
+// Manager holds the configured discovery services.
+type Manager struct {
+	services map[string]*Service
+	logger   log.Loggerer
+}
+
+// Service method returns the discovery service for given name otherwise nil.
+func (m *Manager) Service(name string) *Service {
+	return m.services[name]
+}
+
+// Names method returns all the configured service names.
+func (m *Manager) Names() []string {
+	var names []string
+	for name := range m.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Next method returns the next endpoint of given service name as per
+// service load balancing strategy.
+func (m *Manager) Next(name string) (*Endpoint, error) {
+	s := m.Service(name)
+	if s == nil {
+		return nil, ErrServiceNotFound
+	}
+	return s.Next()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Service
+//______________________________________________________________________________
+
+// Service holds the resolved endpoints of single discovery service.
+type Service struct {
+	sync.Mutex
+	Name     string
+	SRV      string
+	Scheme   string
+	Strategy string
+	Refresh  time.Duration
+
+	lookupSRV   LookupSRVFunc
+	endpoints   []*Endpoint
+	lastRefresh time.Time
+	next        int
+	logger      log.Loggerer
+}
+
+// SetLookupSRV method sets the SRV record resolver of the service.
+func (s *Service) SetLookupSRV(fn LookupSRVFunc) {
+	s.Lock()
+	defer s.Unlock()
+	s.lookupSRV = fn
+	s.lastRefresh = time.Time{}
+}
+
+// Endpoints method returns the currently resolved endpoints of the service.
+func (s *Service) Endpoints() []*Endpoint {
+	s.Lock()
+	defer s.Unlock()
+	result := make([]*Endpoint, len(s.endpoints))
+	copy(result, s.endpoints)
+	return result
+}
+
+// Next method returns the endpoint as per service load balancing strategy.
+// Endpoints are re-resolved when refresh interval elapsed, on resolve
+// failure previously resolved endpoints are used. Caller must call
+// `Endpoint.Done` after the use of endpoint.
+func (s *Service) Next() (*Endpoint, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if time.Since(s.lastRefresh) >= s.Refresh {
+		s.refresh()
+	}
+
+	if len(s.endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	var ep *Endpoint
+	if s.Strategy == StrategyLeastConn {
+		for _, e := range s.endpoints {
+			if ep == nil || e.ActiveConns() < ep.ActiveConns() {
+				ep = e
+			}
+		}
+	} else {
+		ep = s.endpoints[s.next%len(s.endpoints)]
+		s.next++
+	}
+
+	ep.acquire()
+	return ep, nil
+}
+
+func (s *Service) refresh() {
+	s.lastRefresh = time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	records, err := s.lookupSRV(ctx, s.SRV)
+	if err != nil || len(records) == 0 {
+		s.logger.Warnf("discovery: unable to resolve service '%s' [%s]: %v", s.Name, s.SRV, err)
+		return
+	}
+
+	// Only the lowest priority targets are used, refer RFC 2782
+	sort.Slice(records, func(i, j int) bool { return records[i].Priority < records[j].Priority })
+	existing := make(map[string]*Endpoint)
+	for _, e := range s.endpoints {
+		existing[e.Address()] = e
+	}
+
+	var endpoints []*Endpoint
+	for _, r := range records {
+		if r.Priority != records[0].Priority {
+			break
+		}
+		host := strings.TrimSuffix(r.Target, ".")
+		addr := net.JoinHostPort(host, strconv.Itoa(int(r.Port)))
+		if e, found := existing[addr]; found {
+			endpoints = append(endpoints, e)
+			continue
+		}
+		endpoints = append(endpoints, &Endpoint{Scheme: s.Scheme, Host: host, Port: int(r.Port), Weight: int(r.Weight)})
+	}
+	s.endpoints = endpoints
+	s.logger.Debugf("discovery: service '%s' resolved to %d endpoint(s)", s.Name, len(endpoints))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Endpoint
+//______________________________________________________________________________
+
+// Endpoint holds the resolved service target.
+type Endpoint struct {
+	Scheme string
+	Host   string
+	Port   int
+	Weight int
+
+	mu     sync.Mutex
+	active int
+}
+
+// Address method returns the endpoint address in the form of `host:port`.
+func (e *Endpoint) Address() string {
+	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+}
+
+// URL method returns the endpoint base URL e.g. `http://10.0.0.12:8080`.
+func (e *Endpoint) URL() string {
+	return e.Scheme + "://" + e.Address()
+}
+
+// ActiveConns method returns the number of in-flight usage of the endpoint.
+func (e *Endpoint) ActiveConns() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.active
+}
+
+// Done method releases the endpoint usage, it's used by `least_conn` strategy.
+func (e *Endpoint) Done() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.active > 0 {
+		e.active--
+	}
+}
+
+func (e *Endpoint) acquire() {
+	e.mu.Lock()
+	e.active++
+	e.mu.Unlock()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func newService(cfg *config.Config, keyPrefix, name string, logger log.Loggerer) (*Service, error) {
+	s := &Service{
+		Name:     name,
+		SRV:      cfg.StringDefault(keyPrefix+".srv", ""),
+		Scheme:   cfg.StringDefault(keyPrefix+".scheme", "http"),
+		Strategy: cfg.StringDefault(keyPrefix+".strategy", StrategyRoundRobin),
+		logger:   logger,
+	}
+
+	if ess.IsStrEmpty(s.SRV) {
+		return nil, fmt.Errorf("discovery: '%s.srv' key is missing", keyPrefix)
+	}
+
+	if s.Strategy != StrategyRoundRobin && s.Strategy != StrategyLeastConn {
+		return nil, fmt.Errorf("discovery: unsupported value '%s' for '%s.strategy'", s.Strategy, keyPrefix)
+	}
+
+	var err error
+	if s.Refresh, err = settings.ParseDuration(cfg, keyPrefix+".refresh", 30*time.Second); err != nil {
+		return nil, fmt.Errorf("discovery: %s", err)
+	}
+
+	s.lookupSRV = dnsLookupSRV(cfg.StringDefault(keyPrefix+".dns_server", ""))
+	return s, nil
+}
+
+// dnsLookupSRV method returns the SRV resolver, if DNS server address is given
+// (e.g. Consul DNS `127.0.0.1:8600`) then queries are sent to that server
+// otherwise system resolver is used.
+func dnsLookupSRV(dnsServer string) LookupSRVFunc {
+	resolver := net.DefaultResolver
+	if !ess.IsStrEmpty(dnsServer) {
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				d := net.Dialer{}
+				return d.DialContext(ctx, network, dnsServer)
+			},
+		}
+	}
+
+	return func(ctx context.Context, name string) ([]*net.SRV, error) {
+		_, records, err := resolver.LookupSRV(ctx, "", "", name)
+		return records, err
+	}
+}
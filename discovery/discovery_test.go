@@ -0,0 +1,150 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoveryRoundRobin(t *testing.T) {
+	m := createTestManager(t, `
+	discovery {
+	  services {
+	    orders {
+	      srv = "_orders._tcp.service.consul"
+	      dns_server = "127.0.0.1:8600"
+	    }
+	  }
+	}
+	`)
+	assert.Equal(t, []string{"orders"}, m.Names())
+
+	s := m.Service("orders")
+	assert.Equal(t, StrategyRoundRobin, s.Strategy)
+	s.SetLookupSRV(func(_ context.Context, name string) ([]*net.SRV, error) {
+		assert.Equal(t, "_orders._tcp.service.consul", name)
+		return []*net.SRV{
+			{Target: "10.0.0.1.", Port: 8080, Priority: 1},
+			{Target: "10.0.0.2.", Port: 8080, Priority: 1},
+			{Target: "10.0.0.3.", Port: 8080, Priority: 2},
+		}, nil
+	})
+
+	var urls []string
+	for i := 0; i < 3; i++ {
+		ep, err := m.Next("orders")
+		assert.Nil(t, err)
+		urls = append(urls, ep.URL())
+		ep.Done()
+	}
+	assert.Equal(t, []string{"http://10.0.0.1:8080", "http://10.0.0.2:8080", "http://10.0.0.1:8080"}, urls)
+	assert.Equal(t, 2, len(s.Endpoints()))
+
+	_, err := m.Next("payments")
+	assert.Equal(t, ErrServiceNotFound, err)
+}
+
+func TestDiscoveryLeastConn(t *testing.T) {
+	m := createTestManager(t, `
+	discovery {
+	  services {
+	    orders {
+	      srv = "_orders._tcp.service.consul"
+	      strategy = "least_conn"
+	      scheme = "https"
+	    }
+	  }
+	}
+	`)
+
+	s := m.Service("orders")
+	s.SetLookupSRV(func(_ context.Context, name string) ([]*net.SRV, error) {
+		return []*net.SRV{{Target: "a.example.com.", Port: 443}, {Target: "b.example.com.", Port: 443}}, nil
+	})
+
+	ep1, _ := s.Next()
+	ep2, _ := s.Next()
+	assert.NotEqual(t, ep1.Address(), ep2.Address())
+	assert.Equal(t, 1, ep1.ActiveConns())
+
+	ep2.Done()
+	ep3, _ := s.Next()
+	assert.Equal(t, ep2.Address(), ep3.Address())
+	assert.Equal(t, "https://b.example.com:443", ep3.URL())
+}
+
+func TestDiscoveryResolveFailure(t *testing.T) {
+	m := createTestManager(t, `
+	discovery {
+	  services {
+	    orders {
+	      srv = "_orders._tcp.service.consul"
+	      refresh = "1s"
+	    }
+	  }
+	}
+	`)
+
+	s := m.Service("orders")
+	s.SetLookupSRV(func(_ context.Context, name string) ([]*net.SRV, error) {
+		return nil, errors.New("no such host")
+	})
+	_, err := s.Next()
+	assert.Equal(t, ErrNoEndpoints, err)
+
+	// previously resolved endpoints are used on failure
+	s.SetLookupSRV(func(_ context.Context, name string) ([]*net.SRV, error) {
+		return []*net.SRV{{Target: "10.0.0.1.", Port: 8080}}, nil
+	})
+	ep, err := s.Next()
+	assert.Nil(t, err)
+	s.SetLookupSRV(func(_ context.Context, name string) ([]*net.SRV, error) {
+		return nil, errors.New("no such host")
+	})
+	ep2, err := s.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, ep, ep2)
+}
+
+func TestDiscoveryConfigErrors(t *testing.T) {
+	m, err := New(config.NewEmpty(), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, m)
+
+	for _, c := range []struct{ cfg, err string }{
+		{`discovery { services { orders { scheme = "http"; } } }`,
+			"discovery: 'discovery.services.orders.srv' key is missing"},
+		{`discovery { services { orders {
+			srv = "_orders._tcp.service.consul"
+			strategy = "random"
+		} } }`, "discovery: unsupported value 'random' for 'discovery.services.orders.strategy'"},
+		{`discovery { services { orders {
+			srv = "_orders._tcp.service.consul"
+			refresh = "30"
+		} } }`, "discovery: 'discovery.services.orders.refresh' value is not a valid time unit"},
+	} {
+		cfg, _ := config.ParseString(c.cfg)
+		_, err := New(cfg, nil)
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
+
+func createTestManager(t *testing.T, cfgStr string) *Manager {
+	cfg, _ := config.ParseString(cfgStr)
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(ioutil.Discard)
+	m, err := New(cfg, l)
+	assert.Nil(t, err)
+	return m
+}
@@ -12,10 +12,13 @@ import (
 	"strings"
 
 	"aahframe.work/ahttp"
+	aerrors "aahframe.work/errors"
 	"aahframe.work/essentials"
 	"aahframe.work/internal/util"
 )
 
+const contentTypeProblemJSON = "application/problem+json; charset=utf-8"
+
 // aah errors
 var (
 	ErrPanicRecovery              = errors.New("aah: panic recovery")
@@ -27,16 +30,29 @@ var (
 	ErrContentTypeNotAccepted     = errors.New("aah: content type not accepted")
 	ErrContentTypeNotOffered      = errors.New("aah: content type not offered")
 	ErrHTTPMethodNotAllowed       = errors.New("aah: http method not allowed")
+	ErrRequestURITooLong          = errors.New("aah: request uri too long")
+	ErrRequestLimitExceeded       = errors.New("aah: request parameters limit exceeded")
 	ErrNotAuthenticated           = errors.New("aah: not authenticated")
 	ErrAccessDenied               = errors.New("aah: access denied")
 	ErrAuthenticationFailed       = errors.New("aah: authentication failed")
 	ErrAuthorizationFailed        = errors.New("aah: authorization failed")
 	ErrSessionAuthenticationInfo  = errors.New("aah: session authentication info")
 	ErrUnableToGetPrincipal       = errors.New("aah: unable to get principal")
+	ErrSessionNotStateful         = errors.New("aah: session is not stateful")
+	ErrImpersonationInProgress    = errors.New("aah: impersonation is in progress")
+	ErrNotImpersonated            = errors.New("aah: not impersonated")
 	ErrGeneric                    = errors.New("aah: generic error")
 	ErrValidation                 = errors.New("aah: validation error")
 	ErrRenderResponse             = errors.New("aah: render response error")
 	ErrWriteResponse              = errors.New("aah: write response error")
+	ErrRouteConcurrencyExceeded   = errors.New("aah: route concurrency limit exceeded")
+	ErrLoadShed                   = errors.New("aah: request shed due to overload")
+	ErrMaintenance                = errors.New("aah: application is under maintenance")
+	ErrRequestHeaderTooLarge      = errors.New("aah: request header fields too large")
+	ErrExpectationFailed          = errors.New("aah: expectation failed")
+	ErrConnectionLimitExceeded    = errors.New("aah: server connection limit exceeded")
+	ErrConcurrencyExceeded        = errors.New("aah: server concurrent request limit exceeded")
+	ErrAuditQueryNotSupported     = errors.New("aah: audit query is not supported by configured sinks")
 )
 
 var defaultErrorHTMLTemplate = template.Must(template.New("error_template").Parse(`<!DOCTYPE html>
@@ -180,6 +196,11 @@ func (er *errorManager) DefaultHandler(ctx *Context, err *Error) bool {
 
 	switch ct {
 	case ahttp.ContentTypeJSON.Mime, ahttp.ContentTypeJSONText.Mime:
+		if err.problem != nil {
+			ctx.Reply().ContType = contentTypeProblemJSON
+			ctx.Reply().JSON(err.problem)
+			break
+		}
 		ctx.Reply().JSON(err)
 	case ahttp.ContentTypeXML.Mime, ahttp.ContentTypeXMLText.Mime:
 		ctx.Reply().XML(err)
@@ -215,6 +236,9 @@ type Error struct {
 	Code    int         `json:"code,omitempty" xml:"code,omitempty"`
 	Message string      `json:"message,omitempty" xml:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty" xml:"data,omitempty"`
+
+	// problem details of mapped application error
+	problem *aerrors.Problem
 }
 
 // Error method is to comply error interface.
@@ -229,3 +253,25 @@ func newError(err error, code int) *Error {
 func newErrorWithData(err error, code int, data interface{}) *Error {
 	return &Error{Reason: err, Code: code, Message: http.StatusText(code), Data: data}
 }
+
+// newErrorFromMapping method creates the error from error registry mapping,
+// message is localized via i18n message key if exists.
+func newErrorFromMapping(ctx *Context, err error, m aerrors.Mapping) *Error {
+	e := &Error{Reason: err, Code: m.Status, Message: m.Title}
+	if len(m.Key) > 0 && ctx.a.I18n() != nil {
+		if msg := ctx.Msg(m.Key); len(msg) > 0 {
+			e.Message = msg
+		}
+	}
+	if len(m.Type) > 0 {
+		e.problem = &aerrors.Problem{
+			Type:       m.Type,
+			Title:      m.Title,
+			Status:     m.Status,
+			Detail:     e.Message,
+			Instance:   ctx.Req.Path,
+			Extensions: m.Fields,
+		}
+	}
+	return e
+}
@@ -0,0 +1,189 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package errors provides typed application errors with error kinds and
+// a registry to map application error types onto HTTP status code, i18n
+// message key and problem details (RFC 7807) fields. aah error pipeline
+// uses the registry when action returns an error.
+//
+// For e.g.:
+//	return nil, errors.NotFound("user not found").WithKey("error.user.notfound")
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Kind type represents the kind of application error.
+type Kind uint8
+
+// Error kinds
+const (
+	KindUnknown Kind = iota
+	KindNotFound
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+	KindValidation
+	KindInternal
+)
+
+var kindNames = map[Kind]string{
+	KindUnknown:      "unknown",
+	KindNotFound:     "not_found",
+	KindConflict:     "conflict",
+	KindUnauthorized: "unauthorized",
+	KindForbidden:    "forbidden",
+	KindValidation:   "validation",
+	KindInternal:     "internal",
+}
+
+var kindStatusCodes = map[Kind]int{
+	KindUnknown:      http.StatusInternalServerError,
+	KindNotFound:     http.StatusNotFound,
+	KindConflict:     http.StatusConflict,
+	KindUnauthorized: http.StatusUnauthorized,
+	KindForbidden:    http.StatusForbidden,
+	KindValidation:   http.StatusUnprocessableEntity,
+	KindInternal:     http.StatusInternalServerError,
+}
+
+// String method returns the name of error kind.
+func (k Kind) String() string {
+	if n, found := kindNames[k]; found {
+		return n
+	}
+	return kindNames[KindUnknown]
+}
+
+// StatusCode method returns the HTTP status code of error kind.
+func (k Kind) StatusCode() int {
+	if c, found := kindStatusCodes[k]; found {
+		return c
+	}
+	return http.StatusInternalServerError
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package methods
+//______________________________________________________________________________
+
+// New method creates typed error for given kind and message.
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap method creates typed error for given kind and message with the cause.
+// It returns nil if given err is nil.
+func Wrap(err error, kind Kind, message string) *Error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// NotFound method creates typed error of kind `KindNotFound`.
+func NotFound(message string) *Error {
+	return New(KindNotFound, message)
+}
+
+// Conflict method creates typed error of kind `KindConflict`.
+func Conflict(message string) *Error {
+	return New(KindConflict, message)
+}
+
+// Unauthorized method creates typed error of kind `KindUnauthorized`.
+func Unauthorized(message string) *Error {
+	return New(KindUnauthorized, message)
+}
+
+// Forbidden method creates typed error of kind `KindForbidden`.
+func Forbidden(message string) *Error {
+	return New(KindForbidden, message)
+}
+
+// Validation method creates typed error of kind `KindValidation`, given
+// fields are added into problem details as `errors`.
+func Validation(message string, fields map[string]string) *Error {
+	e := New(KindValidation, message)
+	if len(fields) > 0 {
+		e.WithField("errors", fields)
+	}
+	return e
+}
+
+// KindOf method returns the kind of given error, it looks into the error
+// chain via `Unwrap`. Otherwise `KindUnknown` is returned.
+func KindOf(err error) Kind {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Kind != KindUnknown {
+			return e.Kind
+		}
+		err = unwrap(err)
+	}
+	return KindUnknown
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Error type and its methods
+//______________________________________________________________________________
+
+// Error type represents the typed application error.
+type Error struct {
+	Kind    Kind
+	Message string
+	Key     string
+	Fields  map[string]interface{}
+	Err     error
+}
+
+// Error method is to comply error interface.
+func (e *Error) Error() string {
+	msg := e.Message
+	if len(msg) == 0 {
+		msg = http.StatusText(e.Kind.StatusCode())
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Kind, msg, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Kind, msg)
+}
+
+// Unwrap method returns the cause of error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode method returns the HTTP status code of error kind.
+func (e *Error) StatusCode() int {
+	return e.Kind.StatusCode()
+}
+
+// WithKey method sets the i18n message key, it is used to localize the
+// error message in the response.
+func (e *Error) WithKey(key string) *Error {
+	e.Key = key
+	return e
+}
+
+// WithField method adds the problem details extension field.
+func (e *Error) WithField(name string, value interface{}) *Error {
+	if e.Fields == nil {
+		e.Fields = make(map[string]interface{})
+	}
+	e.Fields[name] = value
+	return e
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func unwrap(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
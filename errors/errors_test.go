@@ -0,0 +1,108 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type wrapErr struct{ err error }
+
+func (w wrapErr) Error() string { return "wrap: " + w.err.Error() }
+func (w wrapErr) Unwrap() error { return w.err }
+
+func TestErrorKinds(t *testing.T) {
+	testcases := []struct {
+		err    *Error
+		kind   string
+		status int
+	}{
+		{NotFound("user not found"), "not_found", http.StatusNotFound},
+		{Conflict("duplicate"), "conflict", http.StatusConflict},
+		{Unauthorized("no token"), "unauthorized", http.StatusUnauthorized},
+		{Forbidden("denied"), "forbidden", http.StatusForbidden},
+		{Validation("invalid", nil), "validation", http.StatusUnprocessableEntity},
+		{New(Kind(100), ""), "unknown", http.StatusInternalServerError},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.kind, tc.err.Kind.String())
+		assert.Equal(t, tc.status, tc.err.StatusCode())
+	}
+
+	cause := fmt.Errorf("duplicate key")
+	e := Wrap(cause, KindConflict, "email exists")
+	assert.Equal(t, "conflict: email exists: duplicate key", e.Error())
+	assert.Equal(t, cause, e.Unwrap())
+	assert.Nil(t, Wrap(nil, KindConflict, "email exists"))
+	assert.Equal(t, "not_found: Not Found", New(KindNotFound, "").Error())
+
+	assert.Equal(t, KindConflict, KindOf(wrapErr{e}))
+	assert.Equal(t, KindUnknown, KindOf(cause))
+	assert.Equal(t, KindUnknown, KindOf(nil))
+
+	v := Validation("invalid input", map[string]string{"email": "required"})
+	assert.Equal(t, map[string]interface{}{"errors": map[string]string{"email": "required"}}, v.Fields)
+}
+
+func TestErrorRegistry(t *testing.T) {
+	errNoRows := fmt.Errorf("no rows")
+	r := NewRegistry()
+	r.Register(errNoRows, Mapping{Status: http.StatusNotFound, Key: "error.record.notfound"})
+	r.RegisterType((*os.PathError)(nil), Mapping{Status: http.StatusServiceUnavailable})
+	r.RegisterKind(KindValidation, Mapping{Status: http.StatusBadRequest, Type: "about:blank"})
+
+	// sentinel value in chain
+	m, found := r.Lookup(wrapErr{errNoRows})
+	assert.True(t, found)
+	assert.Equal(t, Mapping{Status: http.StatusNotFound, Key: "error.record.notfound", Title: "Not Found"}, m)
+
+	// error type
+	m, found = r.Lookup(&os.PathError{Op: "open", Path: "/tmp/x", Err: os.ErrNotExist})
+	assert.True(t, found)
+	assert.Equal(t, http.StatusServiceUnavailable, m.Status)
+
+	// typed error merges key and fields
+	m, found = r.Lookup(Validation("invalid", map[string]string{"name": "required"}).WithKey("error.invalid"))
+	assert.True(t, found)
+	assert.Equal(t, http.StatusBadRequest, m.Status)
+	assert.Equal(t, "error.invalid", m.Key)
+	assert.Equal(t, "about:blank", m.Type)
+	assert.Equal(t, map[string]string{"name": "required"}, m.Fields["errors"])
+
+	// typed error default mapping from kind
+	m, found = r.Lookup(Wrap(errNoRows, KindConflict, "conflict"))
+	assert.True(t, found)
+	assert.Equal(t, http.StatusNotFound, m.Status, "registered sentinel in chain wins")
+
+	m, found = r.Lookup(Conflict("conflict"))
+	assert.True(t, found)
+	assert.Equal(t, Mapping{Status: http.StatusConflict, Title: "Conflict"}, m)
+
+	// not mapped
+	_, found = r.Lookup(fmt.Errorf("db down"))
+	assert.False(t, found)
+	_, found = r.Lookup(nil)
+	assert.False(t, found)
+}
+
+func TestProblemMarshalJSON(t *testing.T) {
+	p := &Problem{
+		Type:       "https://example.com/probs/out-of-credit",
+		Title:      "Forbidden",
+		Status:     http.StatusForbidden,
+		Detail:     "Your current balance is 30",
+		Instance:   "/account/12345",
+		Extensions: map[string]interface{}{"balance": 30},
+	}
+	b, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"balance":30,"detail":"Your current balance is 30","instance":"/account/12345","status":403,"title":"Forbidden","type":"https://example.com/probs/out-of-credit"}`, string(b))
+}
@@ -0,0 +1,190 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// Mapping struct holds the HTTP mapping of application error, it is used by
+// aah error pipeline to compose the error response.
+//
+//  - Status HTTP status code; default is status code of error kind.
+//
+//  - Key i18n message key of error message.
+//
+//  - Type and Title problem details (RFC 7807) fields; when `Type` is
+// non-empty, JSON error response is written as `application/problem+json`.
+//
+//  - Fields problem details extension fields.
+type Mapping struct {
+	Status int
+	Key    string
+	Type   string
+	Title  string
+	Fields map[string]interface{}
+}
+
+// NewRegistry method creates the error mapping registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		values: make(map[error]Mapping),
+		types:  make(map[reflect.Type]Mapping),
+		kinds:  make(map[Kind]Mapping),
+	}
+}
+
+// Registry type holds the mapping of application errors to HTTP
+// status code, i18n message key and problem details fields. Lookup order is
+// sentinel error value, error type then error kind of each error in the
+// chain.
+type Registry struct {
+	mu     sync.RWMutex
+	values map[error]Mapping
+	types  map[reflect.Type]Mapping
+	kinds  map[Kind]Mapping
+}
+
+// Register method maps the sentinel error value, for e.g.: `sql.ErrNoRows`.
+func (r *Registry) Register(target error, m Mapping) {
+	if target == nil || !reflect.TypeOf(target).Comparable() {
+		return
+	}
+	r.mu.Lock()
+	r.values[target] = m
+	r.mu.Unlock()
+}
+
+// RegisterType method maps the error type of given target, every error of
+// same type is mapped. For e.g.: `(*os.PathError)(nil)`.
+func (r *Registry) RegisterType(target error, m Mapping) {
+	if target == nil {
+		return
+	}
+	r.mu.Lock()
+	r.types[reflect.TypeOf(target)] = m
+	r.mu.Unlock()
+}
+
+// RegisterKind method maps the error kind, it overrides the default mapping
+// of kind.
+func (r *Registry) RegisterKind(kind Kind, m Mapping) {
+	r.mu.Lock()
+	r.kinds[kind] = m
+	r.mu.Unlock()
+}
+
+// Lookup method returns the mapping for given error. It returns false if
+// error and its chain is not mapped and not a typed error. Typed error's
+// message key and fields are merged into the returned mapping.
+func (r *Registry) Lookup(err error) (Mapping, bool) {
+	if err == nil {
+		return Mapping{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, found := r.lookup(err)
+	if !found {
+		kind := KindOf(err)
+		if kind == KindUnknown {
+			return Mapping{}, false
+		}
+		m, found = r.kinds[kind]
+		if !found {
+			m = Mapping{}
+		}
+		if m.Status == 0 {
+			m.Status = kind.StatusCode()
+		}
+	}
+
+	if m.Status == 0 {
+		m.Status = http.StatusInternalServerError
+	}
+	if len(m.Title) == 0 {
+		m.Title = http.StatusText(m.Status)
+	}
+
+	// merge typed error values
+	var e *Error
+	for c := err; c != nil; c = unwrap(c) {
+		if te, ok := c.(*Error); ok {
+			e = te
+			break
+		}
+	}
+	if e != nil {
+		if len(e.Key) > 0 {
+			m.Key = e.Key
+		}
+		if len(e.Fields) > 0 {
+			fields := make(map[string]interface{}, len(m.Fields)+len(e.Fields))
+			for k, v := range m.Fields {
+				fields[k] = v
+			}
+			for k, v := range e.Fields {
+				fields[k] = v
+			}
+			m.Fields = fields
+		}
+	}
+	return m, true
+}
+
+func (r *Registry) lookup(err error) (Mapping, bool) {
+	for c := err; c != nil; c = unwrap(c) {
+		t := reflect.TypeOf(c)
+		if t.Comparable() {
+			if m, found := r.values[c]; found {
+				return m, true
+			}
+		}
+		if m, found := r.types[t]; found {
+			return m, true
+		}
+	}
+	return Mapping{}, false
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Problem type and its methods
+//______________________________________________________________________________
+
+// Problem struct represents the problem details (RFC 7807) of error
+// response, extension fields are written as top-level members.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// MarshalJSON method is to comply `json.Marshaler` interface.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	if len(p.Title) > 0 {
+		m["title"] = p.Title
+	}
+	if p.Status > 0 {
+		m["status"] = p.Status
+	}
+	if len(p.Detail) > 0 {
+		m["detail"] = p.Detail
+	}
+	if len(p.Instance) > 0 {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
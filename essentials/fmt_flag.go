@@ -56,10 +56,10 @@ func ParseFmtFlag(pattern string, fmtFlags map[string]FmtFlag) ([]FmtFlagPart, e
 		part := FmtFlagPart{Flag: flag, Name: parts[0]}
 		switch len(parts) {
 		case 2:
-			// handle `time` related flag, `custom` flag
+			// handle `time` related flag, `custom` flag, `ctx` flag
 			// and `hdr` flag particularly
 			if strings.Contains(parts[0], "time") || parts[0] == "custom" ||
-				strings.HasSuffix(parts[0], "hdr") {
+				parts[0] == "ctx" || strings.HasSuffix(parts[0], "hdr") {
 				part.Format = parts[1]
 			} else {
 				part.Format = "%" + parts[1] + "v"
@@ -61,6 +61,7 @@ func TestFmtParseFlagAccessLog(t *testing.T) {
 		fmtFlagResponseSize
 		fmtFlagResponseHeader
 		fmtFlagResponseTime
+		fmtFlagContext
 	)
 
 	accessLogFmtFlags := map[string]FmtFlag{
@@ -75,9 +76,10 @@ func TestFmtParseFlagAccessLog(t *testing.T) {
 		"ressize":   fmtFlagResponseSize,
 		"reshdr":    fmtFlagResponseHeader,
 		"restime":   fmtFlagResponseTime,
+		"ctx":       fmtFlagContext,
 	}
 
-	flagParts, err := ParseFmtFlag("%clientip %reqid %reqtime %restime %resstatus %ressize %reqmethod %requrl %reqhdr:Referer %reshdr:Server", accessLogFmtFlags)
+	flagParts, err := ParseFmtFlag("%clientip %reqid %reqtime %restime %resstatus %ressize %reqmethod %requrl %reqhdr:Referer %reshdr:Server %ctx:tenant_id", accessLogFmtFlags)
 	assert.Nil(t, err)
 
 	assertFlagPart(t, "clientip", "%v", FmtFlag(0), flagParts[0])
@@ -90,6 +92,7 @@ func TestFmtParseFlagAccessLog(t *testing.T) {
 	assertFlagPart(t, "requrl", "%v", FmtFlag(2), flagParts[7])
 	assertFlagPart(t, "reqhdr", "Referer", FmtFlag(5), flagParts[8])
 	assertFlagPart(t, "reshdr", "Server", FmtFlag(9), flagParts[9])
+	assertFlagPart(t, "ctx", "tenant_id", FmtFlag(11), flagParts[10])
 }
 
 func assertFlagPart(t *testing.T, name, format string, fflag FmtFlag, flagPart FmtFlagPart) {
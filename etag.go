@@ -0,0 +1,133 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+)
+
+const weakETagPrefix = "W/"
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// HTTPEngine Unexported methods
+//______________________________________________________________________________
+
+// checkNotModified method sets the ETag of the reply, either from
+// `Reply().ETag(...)` or computed from given body if `render.etag.enable`
+// is true. It writes `304 Not Modified` and returns true if the response
+// is not modified as per conditional request headers `If-None-Match` and
+// `If-Modified-Since`. Strong ETag gets the content encoding suffix, since
+// it's byte-for-byte representation.
+func (e *HTTPEngine) checkNotModified(ctx *Context, body []byte, encoded bool) bool {
+	re := ctx.Reply()
+	if re.Code != http.StatusOK ||
+		!(ctx.Req.Method == ahttp.MethodGet || ctx.Req.Method == ahttp.MethodHead) {
+		return false
+	}
+
+	etag := re.etag
+	if !re.etagSet && body != nil && e.a.settings().ETagEnabled {
+		h := fnv.New64a()
+		_, _ = h.Write(body)
+		etag = makeETag(fmt.Sprintf("%x-%x", len(body), h.Sum64()), e.a.settings().ETagWeak)
+	}
+	if len(etag) > 0 {
+		if encoded {
+			etag = encodedETag(etag, gzipContentEncoding)
+		}
+		ctx.Res.Header().Set(ahttp.HeaderETag, etag)
+	}
+
+	if !isNotModified(ctx.Req.Unwrap(), ctx.Res.Header()) {
+		return false
+	}
+
+	// same as net/http 'writeNotModified'
+	hdr := ctx.Res.Header()
+	hdr.Del(ahttp.HeaderContentType)
+	hdr.Del(ahttp.HeaderContentLength)
+	hdr.Del(ahttp.HeaderContentEncoding)
+	if len(hdr.Get(ahttp.HeaderETag)) > 0 {
+		hdr.Del(ahttp.HeaderLastModified)
+	}
+	ctx.Res.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// fileETag method returns the ETag of static file derived from its size
+// and modification time.
+func fileETag(fi os.FileInfo, weak bool) string {
+	return makeETag(fmt.Sprintf("%x-%x", fi.Size(), fi.ModTime().UnixNano()), weak)
+}
+
+// makeETag method returns the quoted ETag of given value, it's returned as
+// is if already quoted.
+func makeETag(value string, weak bool) string {
+	if strings.HasPrefix(value, weakETagPrefix) || strings.HasPrefix(value, `"`) {
+		return value
+	}
+	if weak {
+		return weakETagPrefix + `"` + value + `"`
+	}
+	return `"` + value + `"`
+}
+
+func encodedETag(etag, encoding string) string {
+	if strings.HasPrefix(etag, weakETagPrefix) || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + encoding + `"`
+}
+
+// isNotModified method evaluates the conditional request headers against
+// response header `ETag` and `Last-Modified`. Header `If-None-Match` takes
+// precedence over `If-Modified-Since`, refer to RFC 7232 section 6.
+func isNotModified(r *http.Request, hdr http.Header) bool {
+	if inm := r.Header.Get(ahttp.HeaderIfNoneMatch); len(inm) > 0 {
+		etag := hdr.Get(ahttp.HeaderETag)
+		if len(etag) == 0 {
+			return false
+		}
+		for _, v := range strings.Split(inm, ",") {
+			v = strings.TrimSpace(v)
+			if v == "*" || weakETagMatch(v, etag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := r.Header.Get(ahttp.HeaderIfModifiedSince)
+	lm := hdr.Get(ahttp.HeaderLastModified)
+	if len(ims) == 0 || len(lm) == 0 {
+		return false
+	}
+	imsTime, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	lmTime, err := http.ParseTime(lm)
+	if err != nil {
+		return false
+	}
+	return !lmTime.Truncate(time.Second).After(imsTime)
+}
+
+// weakETagMatch method compares the ETags ignoring weak prefix, refer to
+// RFC 7232 section 2.3.2.
+func weakETagMatch(a, b string) bool {
+	return strings.TrimPrefix(a, weakETagPrefix) == strings.TrimPrefix(b, weakETagPrefix)
+}
@@ -39,6 +39,23 @@ const (
 	// config `runtime.config_hotreload.signal`.
 	EventOnConfigHotReload = "OnConfigHotReload"
 
+	// EventOnImpersonate is published synchronously when subject starts
+	// impersonating another user via `ctx.Impersonate`. Event data is
+	// `*aah.Impersonation`, typically used for audit trail.
+	EventOnImpersonate = "OnImpersonate"
+
+	// EventOnImpersonateRevert is published synchronously when subject reverts
+	// the impersonation via `ctx.RevertImpersonation`. Event data is
+	// `*aah.Impersonation`.
+	EventOnImpersonateRevert = "OnImpersonateRevert"
+
+	// EventOnSessionExpired is published asynchronously when the expired session
+	// is cleaned up from the server side session store. Event data is
+	// `*security.Subject` of that session, `AuthenticationInfo` is nil if the
+	// session was not authenticated. Typically used to clean up per-session
+	// server resources.
+	EventOnSessionExpired = "OnSessionExpired"
+
 	//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 	// HTTP Engine events
 	//______________________________________________________________________________
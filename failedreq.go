@@ -0,0 +1,381 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+)
+
+// headerXAahReplay request header is added to the replayed requests, value
+// is the failed request ID.
+const headerXAahReplay = "X-Aah-Replay"
+
+const keyAahFailedReqBody = "_aahFailedReqBody"
+
+// unparsableOmitted is used in place of the body or query string which
+// cannot be parsed for redaction, so field values are not leaked.
+const unparsableOmitted = "[unparsable content omitted]"
+
+var (
+	defaultRedactHeaders = []string{ahttp.HeaderAuthorization, ahttp.HeaderCookie,
+		"Proxy-Authorization", "X-Api-Key", "X-Auth-Token"}
+	defaultRedactFields = []string{"password", "secret", "token", "api_key", "card", "ssn"}
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// FailedRequests method returns the captured failed (5xx) requests, recent
+// first, see `server.failed_requests { ... }`.
+func (a *Application) FailedRequests() []*FailedRequest {
+	if a.failedReqs == nil {
+		return nil
+	}
+	return a.failedReqs.list()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Failed request
+//______________________________________________________________________________
+
+// FailedRequest holds the captured failed request details, header and body
+// values are redacted.
+type FailedRequest struct {
+	ID        string      `json:"id"`
+	Time      time.Time   `json:"time"`
+	Method    string      `json:"method"`
+	Host      string      `json:"host"`
+	URI       string      `json:"uri"`
+	Header    http.Header `json:"header"`
+	Body      string      `json:"body,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"`
+	Status    int         `json:"status"`
+	Route     string      `json:"route,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// Replay method sends the failed request to given base URL, for e.g.: dev
+// instance `http://localhost:8080`. Request header `X-Aah-Replay` is added
+// with failed request ID. Redacted values are sent as-is.
+func (fr *FailedRequest) Replay(client *http.Client, baseURL string) (*http.Response, error) {
+	req, err := http.NewRequest(fr.Method, strings.TrimSuffix(baseURL, "/")+fr.URI, strings.NewReader(fr.Body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range fr.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	for _, h := range hopByHopHeaders {
+		req.Header.Del(h)
+	}
+	req.Header.Del(ahttp.HeaderContentLength)
+	req.Host = fr.Host
+	req.Header.Set(headerXAahReplay, fr.ID)
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initFailedRequests method initializes the failed (5xx) request capture
+// from config `server.failed_requests { ... }`. Last `capacity` failed
+// requests are kept in memory. Admin endpoint lists the captured requests
+// in JSON, it requires `security.admin_auth` config.
+//
+//	server {
+//	  failed_requests {
+//	    enable = true
+//	    capacity = 50
+//	    max_body_size = "64kb"
+//	    redact_headers = ["Authorization", "Cookie"]
+//	    redact_fields = ["password", "token"]
+//	    admin {
+//	      enable = true
+//	      path = "/_aah/failed-requests"
+//	    }
+//	  }
+//	}
+func (a *Application) initFailedRequests() error {
+	keyPrefix := "server.failed_requests"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.failedReqs = nil
+		return nil
+	}
+
+	capacity := cfg.IntDefault(keyPrefix+".capacity", 50)
+	if capacity <= 0 {
+		return fmt.Errorf("'%s.capacity' value must be greater than zero", keyPrefix)
+	}
+
+	maxBodySize, err := ess.StrToBytes(cfg.StringDefault(keyPrefix+".max_body_size", "64kb"))
+	if err != nil {
+		return fmt.Errorf("'%s.max_body_size': %s", keyPrefix, err)
+	}
+
+	fc := &failedRequestCapture{
+		entries:      make([]*FailedRequest, capacity),
+		maxBodySize:  maxBodySize,
+		redactFields: defaultRedactFields,
+	}
+	if cfg.BoolDefault(keyPrefix+".admin.enable", false) {
+		if !cfg.IsExists("security.admin_auth") {
+			return fmt.Errorf("'%s.admin' requires 'security.admin_auth' config", keyPrefix)
+		}
+		fc.adminPath = cfg.StringDefault(keyPrefix+".admin.path", "/_aah/failed-requests")
+		if !strings.HasPrefix(fc.adminPath, "/") {
+			return fmt.Errorf("'%s.admin.path' value must begin with '/'", keyPrefix)
+		}
+	}
+
+	headers := defaultRedactHeaders
+	if v, found := cfg.StringList(keyPrefix + ".redact_headers"); found {
+		headers = v
+	}
+	for _, h := range headers {
+		fc.redactHeaders = append(fc.redactHeaders, http.CanonicalHeaderKey(h))
+	}
+	if v, found := cfg.StringList(keyPrefix + ".redact_fields"); found {
+		fc.redactFields = v
+	}
+	fc.redactFields = a.piiRedactFields(fc.redactFields)
+
+	// previously captured requests are retained on hot-reload
+	if a.failedReqs != nil {
+		for _, fr := range a.failedReqs.list() {
+			fc.add(fr)
+		}
+	}
+	a.failedReqs = fc
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Failed request capture
+//______________________________________________________________________________
+
+type failedRequestCapture struct {
+	sync.Mutex
+	entries       []*FailedRequest
+	next          int
+	maxBodySize   int64
+	redactHeaders []string
+	redactFields  []string
+	adminPath     string
+}
+
+type capturedBody struct {
+	data      []byte
+	truncated bool
+}
+
+// Capture method buffers the request body up to `max_body_size`, request
+// body is restored for the application.
+func (fc *failedRequestCapture) Capture(ctx *Context) {
+	r := ctx.Req.Unwrap()
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, fc.maxBodySize+1))
+	r.Body = &mirrorBody{Reader: io.MultiReader(bytes.NewReader(data), r.Body), Closer: r.Body}
+	if err != nil {
+		return
+	}
+	cb := &capturedBody{data: data}
+	if int64(len(data)) > fc.maxBodySize {
+		cb.data, cb.truncated = data[:fc.maxBodySize], true
+	}
+	ctx.Set(keyAahFailedReqBody, cb)
+}
+
+// Record method adds the request into ring buffer if response status is 5xx.
+func (fc *failedRequestCapture) Record(ctx *Context) {
+	status := ctx.Res.Status()
+	if status < http.StatusInternalServerError {
+		return
+	}
+
+	r := ctx.Req.Unwrap()
+	fr := &FailedRequest{
+		ID:     firstNonZeroString(ctx.Req.Header.Get(ctx.a.settings().RequestIDHeaderKey), ess.NewGUID()),
+		Time:   time.Now(),
+		Method: r.Method,
+		Host:   r.Host,
+		URI:    fc.redactURI(r.URL),
+		Header: make(http.Header, len(r.Header)),
+		Status: status,
+	}
+	for k, v := range r.Header {
+		fr.Header[k] = append([]string(nil), v...)
+	}
+	for _, h := range fc.redactHeaders {
+		if _, found := fr.Header[h]; found {
+			fr.Header[h] = []string{settings.MaskedValue}
+		}
+	}
+	if cb, ok := ctx.Get(keyAahFailedReqBody).(*capturedBody); ok {
+		fr.Body, fr.Truncated = fc.redactBody(ctx.Req.ContentType().Mime, cb.data), cb.truncated
+	}
+	if ctx.route != nil {
+		fr.Route = ctx.route.Name
+	}
+	if err := ctx.Reply().err; err != nil {
+		fr.Error = err.Message
+		if err.Reason != nil {
+			fr.Error = err.Reason.Error() + ": " + err.Message
+		}
+	}
+	fc.add(fr)
+}
+
+// Serve method replies the captured failed requests on admin endpoint.
+// Returns true if request is served otherwise false.
+func (fc *failedRequestCapture) Serve(ctx *Context) bool {
+	if len(fc.adminPath) == 0 || ctx.Req.Path != fc.adminPath || ctx.Req.Method != ahttp.MethodGet {
+		return false
+	}
+	if !ctx.a.verifyAdmin(ctx) {
+		return true
+	}
+
+	entries := fc.list()
+	if id := ctx.Req.QueryValue("id"); len(id) > 0 {
+		var found []*FailedRequest
+		for _, fr := range entries {
+			if fr.ID == id {
+				found = append(found, fr)
+			}
+		}
+		entries = found
+	}
+	if entries == nil {
+		entries = []*FailedRequest{}
+	}
+	ctx.Reply().Ok().JSON(entries)
+	return true
+}
+
+func (fc *failedRequestCapture) add(fr *FailedRequest) {
+	fc.Lock()
+	defer fc.Unlock()
+	fc.entries[fc.next] = fr
+	fc.next = (fc.next + 1) % len(fc.entries)
+}
+
+func (fc *failedRequestCapture) list() []*FailedRequest {
+	fc.Lock()
+	defer fc.Unlock()
+	var result []*FailedRequest
+	for i := 1; i <= len(fc.entries); i++ {
+		if fr := fc.entries[(fc.next-i+len(fc.entries))%len(fc.entries)]; fr != nil {
+			result = append(result, fr)
+		}
+	}
+	return result
+}
+
+// redactBody method masks the configured field values of JSON and form
+// body, other content types are kept as-is except multipart.
+func (fc *failedRequestCapture) redactBody(mime string, data []byte) string {
+	return redactBody(fc.redactFields, mime, data)
+}
+
+// redactURI method returns the request URI with configured field values of
+// query string masked.
+func (fc *failedRequestCapture) redactURI(u *url.URL) string {
+	if len(u.RawQuery) == 0 {
+		return u.RequestURI()
+	}
+	ru := *u
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		ru.RawQuery = ""
+		return ru.RequestURI() + "?" + unparsableOmitted
+	}
+	ru.RawQuery = redactValues(fc.redactFields, values).Encode()
+	return ru.RequestURI()
+}
+
+// redactBody method masks the given field values of JSON and form body,
+// other content types are kept as-is except multipart. JSON and form body
+// which cannot be parsed (e.g. truncated) is omitted.
+func redactBody(fields []string, mime string, data []byte) string {
+	switch {
+	case strings.Contains(mime, "json"):
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return unparsableOmitted
+		}
+		b, _ := json.Marshal(redactValue(fields, v))
+		return string(b)
+	case mime == ahttp.ContentTypeForm.Mime:
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return unparsableOmitted
+		}
+		return redactValues(fields, values).Encode()
+	case strings.HasPrefix(mime, "multipart/"):
+		return "[multipart body omitted]"
+	}
+	return string(data)
+}
+
+func redactValues(fields []string, values url.Values) url.Values {
+	for k := range values {
+		if isRedactField(fields, k) {
+			values[k] = []string{settings.MaskedValue}
+		}
+	}
+	return values
+}
+
+func redactValue(fields []string, v interface{}) interface{} {
+	switch tv := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range tv {
+			if isRedactField(fields, k) {
+				tv[k] = settings.MaskedValue
+			} else {
+				tv[k] = redactValue(fields, fv)
+			}
+		}
+	case []interface{}:
+		for i, iv := range tv {
+			tv[i] = redactValue(fields, iv)
+		}
+	}
+	return v
+}
+
+// isRedactField method returns true if given name contains any of the
+// redact fields, case insensitive.
+func isRedactField(fields []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, f := range fields {
+		if strings.Contains(name, strings.ToLower(f)) {
+			return true
+		}
+	}
+	return false
+}
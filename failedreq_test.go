@@ -0,0 +1,142 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailedRequests(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Failed Requests]: %s", ts.URL)
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  failed_requests {
+	    enable = true
+	    capacity = 2
+	    admin {
+	      enable = true
+	    }
+	  }
+	}
+	security {
+	  admin_auth {
+	    tokens = ["admintoken"]
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initAdmin())
+	assert.Nil(t, ts.app.initFailedRequests())
+
+	send := func(body string) *http.Response {
+		req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/trigger-panic?q=1&api_token=abc", strings.NewReader(body))
+		req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeJSON.String())
+		req.Header.Set(ahttp.HeaderAuthorization, "Bearer usertoken")
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	// successful request is not captured
+	resp, err := http.Get(ts.URL + "/get-text.html")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 0, len(ts.app.FailedRequests()))
+
+	resp = send(`{"username":"jeeva","password":"welcome123","items":[{"card_number":"4111"}]}`)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	frs := ts.app.FailedRequests()
+	assert.Equal(t, 1, len(frs))
+	fr := frs[0]
+	assert.Equal(t, ahttp.MethodGet, fr.Method)
+	assert.Equal(t, "/trigger-panic?api_token=%2A%2A%2A%2A%2A%2A&q=1", fr.URI)
+	assert.Equal(t, http.StatusInternalServerError, fr.Status)
+	assert.Equal(t, "trigger_panic", fr.Route)
+	assert.Equal(t, settings.MaskedValue, fr.Header.Get(ahttp.HeaderAuthorization))
+	assert.Equal(t, `{"items":[{"card_number":"******"}],"password":"******","username":"jeeva"}`, fr.Body)
+
+	// ring buffer keeps last N
+	send(`{"attempt":2}`)
+	send(`{"attempt":3}`)
+	frs = ts.app.FailedRequests()
+	assert.Equal(t, 2, len(frs))
+	assert.Equal(t, `{"attempt":3}`, frs[0].Body)
+	assert.Equal(t, `{"attempt":2}`, frs[1].Body)
+
+	// admin endpoint
+	resp, err = http.Get(ts.URL + "/_aah/failed-requests")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/_aah/failed-requests?id="+frs[1].ID, nil)
+	req.Header.Set(ahttp.HeaderAuthorization, "Bearer admintoken")
+	resp, err = http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var listed []*FailedRequest
+	assert.Nil(t, json.Unmarshal([]byte(responseBody(resp)), &listed))
+	assert.Equal(t, 1, len(listed))
+	assert.Equal(t, frs[1].ID, listed[0].ID)
+
+	// replay against dev instance
+	var replayed *http.Request
+	var replayedBody string
+	dev := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		replayed = r
+		b := make([]byte, 64)
+		n, _ := r.Body.Read(b)
+		replayedBody = string(b[:n])
+	}))
+	defer dev.Close()
+	resp, err = listed[0].Replay(nil, dev.URL+"/")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/trigger-panic?api_token=%2A%2A%2A%2A%2A%2A&q=1", replayed.URL.RequestURI())
+	assert.Equal(t, listed[0].ID, replayed.Header.Get(headerXAahReplay))
+	assert.Equal(t, `{"attempt":2}`, replayedBody)
+
+	// form body and invalid config
+	fc := ts.app.failedReqs
+	assert.Equal(t, "password=%2A%2A%2A%2A%2A%2A&user=jeeva",
+		fc.redactBody(ahttp.ContentTypeForm.Mime, []byte("user=jeeva&password=secret")))
+	assert.Equal(t, "[multipart body omitted]", fc.redactBody("multipart/form-data", []byte("--abc")))
+
+	// unparsable or truncated body and query string are omitted
+	assert.Equal(t, unparsableOmitted, fc.redactBody(ahttp.ContentTypeJSON.Mime, []byte(`{"user":"jeeva","password":"sec`)))
+	assert.Equal(t, unparsableOmitted, fc.redactBody(ahttp.ContentTypeForm.Mime, []byte("password=%zz")))
+	assert.Equal(t, "/users?"+unparsableOmitted, fc.redactURI(&url.URL{Path: "/users", RawQuery: "token=%zz"}))
+	assert.Equal(t, "/users", fc.redactURI(&url.URL{Path: "/users"}))
+
+	cfg := ts.app.Config()
+	assert.Nil(t, setTestConfig(ts.app, `
+	server {
+	  failed_requests {
+	    enable = true
+	    admin {
+	      enable = true
+	    }
+	  }
+	}
+	`))
+	err = ts.app.initFailedRequests()
+	assert.Equal(t, "'server.failed_requests.admin' requires 'security.admin_auth' config", err.Error())
+	ts.app.cfg = cfg
+
+	ts.app.Config().SetInt("server.failed_requests.capacity", 0)
+	err = ts.app.initFailedRequests()
+	assert.Equal(t, "'server.failed_requests.capacity' value must be greater than zero", err.Error())
+}
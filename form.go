@@ -0,0 +1,370 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"html/template"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/valpar"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+const keyFlashForm = "_aahForm"
+
+func init() {
+	gob.Register(&formFlash{})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Form
+//______________________________________________________________________________
+
+// Form is HTML form builder for the given model struct. It renders the inputs
+// with model values, validation errors and Anti-CSRF token. After failed
+// POST submission the user input is repopulated either from current request
+// or from flash, see `Form.Flash` for Post/Redirect/Get flow.
+//
+// Field name is taken from bind struct tag (`request.auto_bind.tag_name`),
+// label from `label` tag and input type from `input` tag otherwise it's
+// inferred from field type.
+//
+//	type SignupForm struct {
+//	  Email    string `bind:"email" label:"Email Address" validate:"required,email"`
+//	  Password string `bind:"password" input:"password" validate:"required,min=8"`
+//	}
+//
+// In the controller:
+//
+//	ctx.AddViewArg("Form", ctx.Form(&SignupForm{}))
+//
+// In the view:
+//
+//	<form action="{{ .Form.Action }}" method="{{ .Form.Method }}">
+//	  {{ .Form.CSRFField }}
+//	  {{ .Form.Input "email" }}
+//	  {{ forminput .Form "password" "class" "form-control" }}
+//	</form>
+type Form struct {
+	Action string
+	Method string
+	Errors map[string]string
+
+	ctx       *Context
+	fields    []*FormField
+	input     url.Values
+	csrfName  string
+	csrfToken string
+}
+
+// FormField holds the single form field details.
+type FormField struct {
+	Name     string
+	Label    string
+	Type     string
+	Value    string
+	Error    string
+	Required bool
+
+	structName string
+}
+
+// Form method creates the HTML form builder for the given model struct.
+func (ctx *Context) Form(model interface{}) *Form {
+	f := &Form{
+		Action: ctx.Req.Path,
+		Method: ahttp.MethodPost,
+		Errors: make(map[string]string),
+		ctx:    ctx,
+		fields: formFields(model),
+	}
+
+	// Old input, flash values takes precedence over current request
+	if ctx.subject != nil && ctx.subject.Session != nil {
+		if ff, ok := ctx.subject.Session.GetFlash(keyFlashForm).(*formFlash); ok {
+			f.input = ff.Input
+			for k, v := range ff.Errors {
+				f.Errors[k] = v
+			}
+		}
+	}
+	if f.input == nil && ctx.a.bindMgr != nil &&
+		ctx.a.bindMgr.payloadSupported.MatchString(ctx.Req.Method) {
+		f.input = ctx.Req.Unwrap().Form
+	}
+
+	if ac := ctx.a.SecurityManager().AntiCSRF; ac != nil && ac.Enabled {
+		if cs, found := ctx.viewArgs[keyAntiCSRF]; found {
+			f.csrfName = ac.FormFieldName()
+			f.csrfToken = ac.SaltCipherSecret(cs.([]byte))
+		}
+	}
+
+	return f
+}
+
+// SetValidationErrors method sets the validation errors into form fields.
+// Error message is resolved from i18n key `validation.<tag>` with args
+// field label and tag param, otherwise default message is used.
+func (f *Form) SetValidationErrors(errs validator.ValidationErrors) *Form {
+	for _, fe := range errs {
+		field := f.fieldByStructName(fe.StructField())
+		if field == nil {
+			continue
+		}
+		var msg string
+		if f.ctx.a.I18n() != nil {
+			msg = f.ctx.Msg("validation."+fe.Tag(), field.Label, fe.Param())
+		}
+		if len(msg) == 0 {
+			if fe.Tag() == "required" {
+				msg = field.Label + " is required"
+			} else {
+				msg = fmt.Sprintf("%s is invalid", field.Label)
+			}
+		}
+		f.Errors[field.Name] = msg
+	}
+	return f
+}
+
+// AddError method adds the error message for the given field name.
+func (f *Form) AddError(name, msg string) *Form {
+	f.Errors[name] = msg
+	return f
+}
+
+// HasErrors method returns true if form has errors otherwise false.
+func (f *Form) HasErrors() bool {
+	return len(f.Errors) > 0
+}
+
+// Error method returns the error message for the given field name.
+func (f *Form) Error(name string) string {
+	return f.Errors[name]
+}
+
+// Value method returns the field value, old input takes precedence over
+// model value. Password field value is always empty.
+func (f *Form) Value(name string) string {
+	if field := f.Field(name); field != nil {
+		return field.Value
+	}
+	if v := f.input[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Field method returns the form field for the given name otherwise nil.
+// Field value and error reflects the current form state.
+func (f *Form) Field(name string) *FormField {
+	for _, field := range f.fields {
+		if field.Name == name {
+			ff := *field
+			if v := f.input[name]; len(v) > 0 {
+				ff.Value = v[0]
+			}
+			if field.Type == "password" {
+				ff.Value = ""
+			}
+			ff.Error = f.Errors[name]
+			return &ff
+		}
+	}
+	return nil
+}
+
+// Fields method returns all the form fields in the struct order.
+func (f *Form) Fields() []*FormField {
+	fields := make([]*FormField, 0, len(f.fields))
+	for _, field := range f.fields {
+		fields = append(fields, f.Field(field.Name))
+	}
+	return fields
+}
+
+// Input method renders the HTML input for the given field name along with
+// error message. Additional attributes are given as key and value pairs,
+// for e.g.: `"class", "form-control", "placeholder", "Email"`.
+func (f *Form) Input(name string, attrs ...string) template.HTML {
+	field := f.Field(name)
+	if field == nil {
+		f.ctx.Log().Warnf("form: field '%s' not found", name)
+		return ""
+	}
+
+	buf := new(bytes.Buffer)
+	ename := template.HTMLEscapeString(field.Name)
+	switch field.Type {
+	case "textarea":
+		fmt.Fprintf(buf, `<textarea id="%s" name="%s"`, ename, ename)
+	default:
+		fmt.Fprintf(buf, `<input type="%s" id="%s" name="%s"`, template.HTMLEscapeString(field.Type), ename, ename)
+		if field.Type == "checkbox" {
+			buf.WriteString(` value="true"`)
+			if field.Value == "true" || field.Value == "on" {
+				buf.WriteString(` checked`)
+			}
+		} else {
+			fmt.Fprintf(buf, ` value="%s"`, template.HTMLEscapeString(field.Value))
+		}
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		fmt.Fprintf(buf, ` %s="%s"`, template.HTMLEscapeString(attrs[i]), template.HTMLEscapeString(attrs[i+1]))
+	}
+	if field.Required {
+		buf.WriteString(` required`)
+	}
+	if len(field.Error) > 0 {
+		buf.WriteString(` aria-invalid="true"`)
+	}
+	if field.Type == "textarea" {
+		fmt.Fprintf(buf, `>%s</textarea>`, template.HTMLEscapeString(field.Value))
+	} else {
+		buf.WriteString(`>`)
+	}
+	if len(field.Error) > 0 {
+		fmt.Fprintf(buf, `<span class="form-error">%s</span>`, template.HTMLEscapeString(field.Error))
+	}
+
+	/* #nosec */
+	return template.HTML(buf.String())
+}
+
+// CSRFField method renders the hidden input of Anti-CSRF token, it's empty
+// if Anti-CSRF is not enabled.
+func (f *Form) CSRFField() template.HTML {
+	if len(f.csrfToken) == 0 {
+		return ""
+	}
+	/* #nosec */
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+		template.HTMLEscapeString(f.csrfName), template.HTMLEscapeString(f.csrfToken)))
+}
+
+// Flash method stores the current request input (except password fields and
+// Anti-CSRF token) and form errors into session flash. So that form is
+// repopulated on the next request after redirect.
+func (f *Form) Flash() *Form {
+	input := make(url.Values)
+	for k, v := range f.ctx.Req.Unwrap().Form {
+		if k == f.csrfName {
+			continue
+		}
+		if field := f.Field(k); field != nil && field.Type == "password" {
+			continue
+		}
+		input[k] = v
+	}
+	f.ctx.Session().SetFlash(keyFlashForm, &formFlash{Input: input, Errors: f.Errors})
+	return f
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// View Template methods
+//______________________________________________________________________________
+
+// tmplFormInput method renders the HTML input for the given form field.
+// Mapped to Go template func.
+func (vm *viewManager) tmplFormInput(f *Form, name string, attrs ...string) template.HTML {
+	if f == nil {
+		return ""
+	}
+	return f.Input(name, attrs...)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type formFlash struct {
+	Input  url.Values
+	Errors map[string]string
+}
+
+func (f *Form) fieldByStructName(name string) *FormField {
+	for _, field := range f.fields {
+		if field.structName == name {
+			return field
+		}
+	}
+	return nil
+}
+
+func formFields(model interface{}) []*FormField {
+	mv := reflect.Indirect(reflect.ValueOf(model))
+	if mv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	mt := mv.Type()
+	var fields []*FormField
+	for i := 0; i < mt.NumField(); i++ {
+		ft := mt.Field(i)
+		if len(ft.PkgPath) > 0 { // unexported
+			continue
+		}
+		name := ft.Tag.Get(valpar.StructTagName)
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = ft.Name
+		}
+		field := &FormField{
+			Name:       name,
+			Label:      ft.Tag.Get("label"),
+			Type:       ft.Tag.Get("input"),
+			Required:   strings.Contains(ft.Tag.Get("validate"), "required"),
+			structName: ft.Name,
+		}
+		if len(field.Label) == 0 {
+			field.Label = ft.Name
+		}
+
+		fv := reflect.Indirect(mv.Field(i))
+		if len(field.Type) == 0 {
+			field.Type = inferInputType(fv)
+		}
+		if fv.IsValid() {
+			switch v := fv.Interface().(type) {
+			case time.Time:
+				if !v.IsZero() {
+					field.Value = v.Format("2006-01-02")
+				}
+			default:
+				field.Value = fmt.Sprintf("%v", v)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func inferInputType(v reflect.Value) string {
+	if !v.IsValid() {
+		return "text"
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return "checkbox"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	if _, ok := v.Interface().(time.Time); ok {
+		return "date"
+	}
+	return "text"
+}
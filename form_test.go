@@ -0,0 +1,78 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"aahframe.work/security"
+	"aahframe.work/valpar"
+	"github.com/stretchr/testify/assert"
+)
+
+type signupForm struct {
+	Email     string `bind:"email" label:"Email Address" validate:"required,email"`
+	Password  string `bind:"password" input:"password" validate:"required,min=8"`
+	Age       int    `bind:"age" validate:"gte=18"`
+	Subscribe bool   `bind:"subscribe"`
+	Bio       string `bind:"bio" input:"textarea"`
+	Internal  string `bind:"-"`
+	secret    string
+}
+
+func TestContextForm(t *testing.T) {
+	valpar.StructTagName = "bind"
+	a := newApp()
+	a.securityMgr = security.New()
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost:8080/signup", nil))
+	ctx.a = a
+
+	f := ctx.Form(&signupForm{Email: "jeeva@example.com", Age: 30, Subscribe: true, Bio: "<b>Gopher</b>"})
+	assert.Equal(t, "/signup", f.Action)
+	assert.Equal(t, "POST", f.Method)
+	assert.False(t, f.HasErrors())
+	assert.Equal(t, "", string(f.CSRFField()))
+	assert.Equal(t, 5, len(f.Fields()))
+	assert.Nil(t, f.Field("Internal"))
+	assert.Nil(t, f.Field("secret"))
+
+	assert.Equal(t, `<input type="text" id="email" name="email" value="jeeva@example.com" class="form-control" required>`,
+		string(f.Input("email", "class", "form-control")))
+	assert.Equal(t, `<input type="number" id="age" name="age" value="30">`, string(f.Input("age")))
+	assert.Equal(t, `<input type="checkbox" id="subscribe" name="subscribe" value="true" checked>`, string(f.Input("subscribe")))
+	assert.Equal(t, `<textarea id="bio" name="bio">&lt;b&gt;Gopher&lt;/b&gt;</textarea>`, string(f.Input("bio")))
+	assert.Equal(t, "", string(f.Input("notexists")))
+	assert.Equal(t, "", string(a.viewMgr.tmplFormInput(nil, "email")))
+}
+
+func TestContextFormValidationErrors(t *testing.T) {
+	valpar.StructTagName = "bind"
+	a := newApp()
+	a.securityMgr = security.New()
+
+	r := httptest.NewRequest("POST", "http://localhost:8080/signup",
+		strings.NewReader("email=jeeva&password=secret123&age=12"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_ = r.ParseForm()
+	ctx := newContext(httptest.NewRecorder(), r)
+	ctx.a = a
+	a.bindMgr = &bindManager{payloadSupported: regexp.MustCompile(`(POST|PUT|DELETE)`)}
+
+	model := &signupForm{Email: "jeeva", Password: "secret123", Age: 12}
+	errs, _ := a.Validate(model)
+	f := ctx.Form(model).SetValidationErrors(errs).AddError("email", "Email Address is invalid")
+	assert.True(t, f.HasErrors())
+	assert.Equal(t, "Email Address is invalid", f.Error("email"))
+	assert.Equal(t, "Age is invalid", f.Error("age"))
+	assert.Equal(t, "jeeva", f.Value("email"))
+	assert.Equal(t, "", f.Value("password"))
+	assert.Equal(t, `<input type="password" id="password" name="password" value="" required>`, string(f.Input("password")))
+	assert.Equal(t, `<input type="number" id="age" name="age" value="12" aria-invalid="true"><span class="form-error">Age is invalid</span>`,
+		string(f.Input("age")))
+}
@@ -1,19 +1,29 @@
 module aahframe.work
 
+go 1.20
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/go-aah/forge v0.8.0
+	github.com/gobwas/ws v1.0.0
+	github.com/stretchr/testify v1.2.2
+	github.com/urfave/cli v1.20.0
+	golang.org/x/crypto v0.17.0
+	golang.org/x/net v0.19.0
+	golang.org/x/oauth2 v0.0.0-20181003184128-c57b0facaced
+	gopkg.in/go-playground/validator.v9 v9.21.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
 require (
 	cloud.google.com/go v0.30.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/go-aah/forge v0.8.0
 	github.com/go-playground/locales v0.12.1 // indirect
 	github.com/go-playground/universal-translator v0.16.0 // indirect
 	github.com/gobwas/httphead v0.0.0-20180130184737-2c6c146eadee // indirect
 	github.com/gobwas/pool v0.2.0 // indirect
-	github.com/gobwas/ws v1.0.0
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.2.2
-	github.com/urfave/cli v1.20.0
-	golang.org/x/crypto v0.0.0-20181012144002-a92615f3c490
-	golang.org/x/net v0.0.0-20181201002055-351d144fa1fc
-	golang.org/x/oauth2 v0.0.0-20181003184128-c57b0facaced
-	gopkg.in/go-playground/validator.v9 v9.21.0
+	golang.org/x/sys v0.15.0 // indirect
+	golang.org/x/term v0.15.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
 )
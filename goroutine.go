@@ -0,0 +1,147 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"aahframe.work/aruntime"
+	"aahframe.work/essentials"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// Go method runs the given func in the background goroutine tied to the
+// application lifecycle. Panic is recovered and logged with stacktrace, it
+// does not crash the application. Given context is cancelled on server
+// shutdown, aah waits for running goroutines until
+// `server.timeout.grace_shutdown`.
+//
+// For e.g.:
+//	aah.App().Go(func(ctx context.Context) {
+//		ticker := time.NewTicker(time.Minute)
+//		defer ticker.Stop()
+//		for {
+//			select {
+//			case <-ctx.Done():
+//				return
+//			case <-ticker.C:
+//				// do the work
+//			}
+//		}
+//	})
+//
+// It returns false if application is shutting down, func is not executed.
+func (a *Application) Go(fn func(ctx context.Context)) bool {
+	return a.goGroup.Go(a, fn)
+}
+
+// GoroutineMetrics method returns the snapshot of background goroutine
+// metrics started via `Application.Go`.
+func (a *Application) GoroutineMetrics() GoroutineMetrics {
+	return a.goGroup.snapshot()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Goroutine metrics
+//______________________________________________________________________________
+
+// GoroutineMetrics holds the background goroutine metrics of application.
+type GoroutineMetrics struct {
+	Running   int64
+	Started   int64
+	Completed int64
+	Panicked  int64
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// goroutine group
+//______________________________________________________________________________
+
+func newGoroutineGroup() *goroutineGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &goroutineGroup{ctx: ctx, cancel: cancel}
+}
+
+// goroutineGroup counters are placed first for 64-bit atomic alignment.
+type goroutineGroup struct {
+	running   int64
+	started   int64
+	completed int64
+	panicked  int64
+	sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	stopped bool
+}
+
+func (g *goroutineGroup) Go(a *Application, fn func(ctx context.Context)) bool {
+	g.Lock()
+	if g.stopped {
+		g.Unlock()
+		a.Log().Warnf("Application is shutting down, goroutine is not started: %s",
+			ess.GetFunctionInfo(fn).QualifiedName)
+		return false
+	}
+	g.wg.Add(1)
+	g.Unlock()
+
+	atomic.AddInt64(&g.started, 1)
+	atomic.AddInt64(&g.running, 1)
+	go func() {
+		defer g.wg.Done()
+		defer atomic.AddInt64(&g.running, -1)
+		defer func() {
+			if r := recover(); r != nil {
+				atomic.AddInt64(&g.panicked, 1)
+				strace := aruntime.NewStacktrace(r, a.Config())
+				buf := acquireBuffer()
+				defer releaseBuffer(buf)
+				strace.Print(buf)
+				a.Log().Errorf("Recovered from panic in goroutine: %s", ess.GetFunctionInfo(fn).QualifiedName)
+				a.Log().Error(buf.String())
+				return
+			}
+			atomic.AddInt64(&g.completed, 1)
+		}()
+		fn(g.ctx)
+	}()
+	return true
+}
+
+// stop method cancels the goroutines context and waits for them to return
+// until given context is done.
+func (g *goroutineGroup) stop(ctx context.Context) error {
+	g.Lock()
+	g.stopped = true
+	g.Unlock()
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *goroutineGroup) snapshot() GoroutineMetrics {
+	return GoroutineMetrics{
+		Running:   atomic.LoadInt64(&g.running),
+		Started:   atomic.LoadInt64(&g.started),
+		Completed: atomic.LoadInt64(&g.completed),
+		Panicked:  atomic.LoadInt64(&g.panicked),
+	}
+}
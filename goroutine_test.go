@@ -0,0 +1,59 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppGo(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	done := make(chan struct{})
+	assert.True(t, a.Go(func(ctx context.Context) {
+		close(done)
+	}))
+	<-done
+
+	panicked := make(chan struct{})
+	assert.True(t, a.Go(func(ctx context.Context) {
+		defer close(panicked)
+		panic("background job failure")
+	}))
+	<-panicked
+
+	// long running goroutine cancelled on stop
+	cancelled := make(chan struct{})
+	assert.True(t, a.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, a.goGroup.stop(ctx))
+	<-cancelled
+
+	m := a.GoroutineMetrics()
+	assert.Equal(t, GoroutineMetrics{Running: 0, Started: 3, Completed: 2, Panicked: 1}, m)
+
+	// not started after stop
+	assert.False(t, a.Go(func(ctx context.Context) {}))
+	assert.Equal(t, int64(3), a.GoroutineMetrics().Started)
+
+	// stop waits until context deadline
+	a = newWebApp1TestApp(t)
+	block := make(chan struct{})
+	defer close(block)
+	a.Go(func(ctx context.Context) { <-block })
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, a.goGroup.stop(ctx))
+	assert.Equal(t, int64(1), a.GoroutineMetrics().Running)
+}
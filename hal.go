@@ -0,0 +1,234 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"aahframe.work/ahttp"
+)
+
+// ErrHALStateNotObject returned when HAL resource state is not serialized
+// as JSON object.
+var ErrHALStateNotObject = errors.New("aah: HAL resource state must be JSON object")
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Reply methods
+//______________________________________________________________________________
+
+// HAL method renders given resource as HAL (Hypertext Application Language)
+// response and it sets HTTP 'Content-Type' as
+// 'application/hal+json; charset=utf-8'.
+//
+//	res := aah.NewHALResource(order).
+//	  AddLink("self", "/orders/123").
+//	  Embed("items", itemResources...)
+//	ctx.Reply().HAL(res)
+func (r *Reply) HAL(res *HALResource) *Reply {
+	r.ContentType(ahttp.ContentTypeHALJSON.String())
+	r.Render(&jsonRender{Data: res})
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// HAL resource
+//______________________________________________________________________________
+
+// HALLink holds the HAL link object details.
+type HALLink struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// HALResource holds the HAL resource state, links and embedded resources.
+// State is serialized as top level properties of the resource.
+type HALResource struct {
+	State interface{}
+
+	links      map[string][]*HALLink
+	linkRels   []string
+	multiRels  map[string]bool
+	embedded   map[string][]*HALResource
+	embedRels  []string
+	multiEmbed map[string]bool
+	props      []halProperty
+}
+
+type halProperty struct {
+	name  string
+	value interface{}
+}
+
+// NewHALResource method creates the HAL resource for given state, state
+// could be struct, map or nil.
+func NewHALResource(state interface{}) *HALResource {
+	return &HALResource{
+		State:      state,
+		links:      make(map[string][]*HALLink),
+		multiRels:  make(map[string]bool),
+		embedded:   make(map[string][]*HALResource),
+		multiEmbed: make(map[string]bool),
+	}
+}
+
+// AddLink method adds the link for given relation, multiple links of same
+// relation are serialized as array.
+func (h *HALResource) AddLink(rel, href string) *HALResource {
+	return h.AddHALLink(rel, &HALLink{Href: href})
+}
+
+// AddHALLink method adds the link object for given relation.
+func (h *HALResource) AddHALLink(rel string, link *HALLink) *HALResource {
+	if _, found := h.links[rel]; !found {
+		h.linkRels = append(h.linkRels, rel)
+	} else {
+		h.multiRels[rel] = true
+	}
+	h.links[rel] = append(h.links[rel], link)
+	return h
+}
+
+// AddLinks method adds the links for given relation and it's always
+// serialized as array, for e.g.: `curies`.
+func (h *HALResource) AddLinks(rel string, links ...*HALLink) *HALResource {
+	for _, l := range links {
+		h.AddHALLink(rel, l)
+	}
+	h.multiRels[rel] = true
+	return h
+}
+
+// Embed method embeds the given resources for relation. Single resource is
+// serialized as object and more than one as array.
+func (h *HALResource) Embed(rel string, resources ...*HALResource) *HALResource {
+	if _, found := h.embedded[rel]; !found {
+		h.embedRels = append(h.embedRels, rel)
+	}
+	h.embedded[rel] = append(h.embedded[rel], resources...)
+	if len(h.embedded[rel]) != 1 {
+		h.multiEmbed[rel] = true
+	}
+	return h
+}
+
+// EmbedList method embeds the given resources for relation and it's always
+// serialized as array even it's empty.
+func (h *HALResource) EmbedList(rel string, resources ...*HALResource) *HALResource {
+	h.Embed(rel, resources...)
+	h.multiEmbed[rel] = true
+	return h
+}
+
+// Set method sets the property into resource in addition to the state.
+func (h *HALResource) Set(name string, value interface{}) *HALResource {
+	h.props = append(h.props, halProperty{name: name, value: value})
+	return h
+}
+
+// Paginate method adds the pagination links `first`, `prev`, `next` and
+// `last` and properties `page`, `per_page`, `total_items` and `total_pages`.
+func (h *HALResource) Paginate(p *Pagination) *HALResource {
+	for _, l := range []struct {
+		rel  string
+		link *PageLink
+	}{{"first", p.First}, {"prev", p.Prev}, {"next", p.Next}, {"last", p.Last}} {
+		if l.link != nil {
+			h.AddLink(l.rel, l.link.URL)
+		}
+	}
+	return h.Set("page", p.Page).
+		Set("per_page", p.PerPage).
+		Set("total_items", p.TotalItems).
+		Set("total_pages", p.TotalPages)
+}
+
+// MarshalJSON method is implementation of `json.Marshaler`, `_links` and
+// `_embedded` are followed by properties and state.
+func (h *HALResource) MarshalJSON() ([]byte, error) {
+	props := make([]halProperty, 0, 2+len(h.props))
+	if len(h.linkRels) > 0 {
+		links := make(halObject, 0, len(h.linkRels))
+		for _, rel := range h.linkRels {
+			if h.multiRels[rel] {
+				links = append(links, halProperty{name: rel, value: h.links[rel]})
+			} else {
+				links = append(links, halProperty{name: rel, value: h.links[rel][0]})
+			}
+		}
+		props = append(props, halProperty{name: "_links", value: links})
+	}
+	if len(h.embedRels) > 0 {
+		embedded := make(halObject, 0, len(h.embedRels))
+		for _, rel := range h.embedRels {
+			if h.multiEmbed[rel] {
+				resources := h.embedded[rel]
+				if resources == nil {
+					resources = []*HALResource{}
+				}
+				embedded = append(embedded, halProperty{name: rel, value: resources})
+			} else {
+				embedded = append(embedded, halProperty{name: rel, value: h.embedded[rel][0]})
+			}
+		}
+		props = append(props, halProperty{name: "_embedded", value: embedded})
+	}
+	props = append(props, h.props...)
+
+	b, err := halObject(props).MarshalJSON()
+	if err != nil || h.State == nil {
+		return b, err
+	}
+
+	state, err := json.Marshal(h.State)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(state, []byte("null")) {
+		return b, nil
+	}
+	if len(state) < 2 || state[0] != '{' || state[len(state)-1] != '}' {
+		return nil, ErrHALStateNotObject
+	}
+	if state = state[1 : len(state)-1]; len(state) == 0 {
+		return b, nil
+	}
+
+	// merge state properties into resource object
+	b = b[:len(b)-1]
+	if len(b) > 1 {
+		b = append(b, ',')
+	}
+	b = append(b, state...)
+	return append(b, '}'), nil
+}
+
+// halObject is the JSON object of properties in the added order.
+type halObject []halProperty
+
+// MarshalJSON method is implementation of `json.Marshaler`.
+func (o halObject) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, p := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(p.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		b, err := json.Marshal(p.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type halOrder struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+func TestHALResource(t *testing.T) {
+	items := []*HALResource{
+		NewHALResource(map[string]int{"qty": 1}).AddLink("self", "/items/1"),
+		NewHALResource(map[string]int{"qty": 2}).AddLink("self", "/items/2"),
+	}
+	res := NewHALResource(&halOrder{ID: 123, Status: "shipped"}).
+		AddLink("self", "/orders/123").
+		AddHALLink("find", &HALLink{Href: "/orders{?id}", Templated: true}).
+		AddLinks("curies", &HALLink{Href: "/docs/rels/{rel}", Name: "doc", Templated: true}).
+		Embed("items", items...).
+		Embed("customer", NewHALResource(map[string]string{"name": "Jeeva"})).
+		EmbedList("coupons").
+		Set("currency", "USD")
+
+	b, err := json.Marshal(res)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"_links":{"self":{"href":"/orders/123"},"find":{"href":"/orders{?id}","templated":true},`+
+		`"curies":[{"href":"/docs/rels/{rel}","templated":true,"name":"doc"}]},`+
+		`"_embedded":{"items":[{"_links":{"self":{"href":"/items/1"}},"qty":1},{"_links":{"self":{"href":"/items/2"}},"qty":2}],`+
+		`"customer":{"name":"Jeeva"},"coupons":[]},"currency":"USD","id":123,"status":"shipped"}`, string(b))
+
+	// same relation links are serialized as array
+	b, _ = json.Marshal(NewHALResource(nil).AddLink("item", "/items/1").AddLink("item", "/items/2"))
+	assert.Equal(t, `{"_links":{"item":[{"href":"/items/1"},{"href":"/items/2"}]}}`, string(b))
+
+	b, _ = json.Marshal(NewHALResource(struct{}{}))
+	assert.Equal(t, `{}`, string(b))
+
+	_, err = json.Marshal(NewHALResource([]int{1, 2}))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), ErrHALStateNotObject.Error())
+}
+
+func TestHALResourcePaginate(t *testing.T) {
+	p := &Pagination{Page: 2, PerPage: 10, TotalItems: 45, TotalPages: 5,
+		First: &PageLink{URL: "/orders"}, Prev: &PageLink{URL: "/orders"},
+		Next: &PageLink{URL: "/orders?page=3"}, Last: &PageLink{URL: "/orders?page=5"}}
+	res := NewHALResource(nil).AddLink("self", "/orders?page=2").Paginate(p)
+
+	b, err := json.Marshal(res)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"_links":{"self":{"href":"/orders?page=2"},"first":{"href":"/orders"},"prev":{"href":"/orders"},`+
+		`"next":{"href":"/orders?page=3"},"last":{"href":"/orders?page=5"}},`+
+		`"page":2,"per_page":10,"total_items":45,"total_pages":5}`, string(b))
+}
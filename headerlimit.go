@@ -0,0 +1,201 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"aahframe.work/essentials"
+)
+
+// Header size metric names for the aggregated values.
+const (
+	// HeaderSizeMetricTotal is the metric name of total request header size.
+	HeaderSizeMetricTotal = "_total"
+
+	// HeaderSizeMetricOther is the metric name of headers which does not have
+	// its own limit and exceeds the `default_max_size`.
+	HeaderSizeMetricOther = "_other"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// HeaderSizeMetrics method returns the snapshot of request header size
+// metrics by header name, see `server.header_limits { ... }`.
+func (a *Application) HeaderSizeMetrics() map[string]HeaderSizeMetric {
+	if a.headerLimits == nil {
+		return nil
+	}
+	return a.headerLimits.snapshot()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Header size metric
+//______________________________________________________________________________
+
+// HeaderSizeMetric holds the request header size limit metrics of single
+// header.
+type HeaderSizeMetric struct {
+	Header   string
+	Limit    int64
+	Exceeded int64
+	Rejected int64
+	MaxSize  int64
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initHeaderLimits method initializes the request header size limits from
+// config `server.header_limits { ... }`. Header names in the `headers`
+// section are canonicalized, underscore is treated as hyphen
+// e.g. `x_api_key` => `X-Api-Key`. Action `log` only records the metrics
+// and logs, action `reject` responds with `431 Request Header Fields Too Large`.
+//
+// Note: requests exceeding `server.max_header_bytes` are rejected by Go HTTP
+// server before it reaches aah, configure it higher than `max_total_size`
+// to have those requests audited.
+//
+//	server {
+//	  header_limits {
+//	    enable = true
+//	    action = "reject"
+//	    max_total_size = "32kb"
+//	    default_max_size = "8kb"
+//	    headers {
+//	      cookie = "4kb"
+//	      authorization = "2kb"
+//	    }
+//	  }
+//	}
+func (a *Application) initHeaderLimits() error {
+	keyPrefix := "server.header_limits"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.headerLimits = nil
+		return nil
+	}
+
+	action := cfg.StringDefault(keyPrefix+".action", "log")
+	if action != "log" && action != "reject" {
+		return fmt.Errorf("'%s.action' has unsupported value '%s', supported values are log and reject", keyPrefix, action)
+	}
+
+	hl := &headerLimiter{
+		reject:  action == "reject",
+		limits:  make(map[string]int64),
+		metrics: make(map[string]*HeaderSizeMetric),
+	}
+
+	var err error
+	if hl.maxTotal, err = ess.StrToBytes(cfg.StringDefault(keyPrefix+".max_total_size", "0b")); err != nil {
+		return fmt.Errorf("'%s.max_total_size': %s", keyPrefix, err)
+	}
+	if hl.defaultMax, err = ess.StrToBytes(cfg.StringDefault(keyPrefix+".default_max_size", "0b")); err != nil {
+		return fmt.Errorf("'%s.default_max_size': %s", keyPrefix, err)
+	}
+
+	for _, k := range cfg.KeysByPath(keyPrefix + ".headers") {
+		size, er := ess.StrToBytes(cfg.StringDefault(keyPrefix+".headers."+k, ""))
+		if er != nil {
+			return fmt.Errorf("'%s.headers.%s': %s", keyPrefix, k, er)
+		}
+		hl.limits[http.CanonicalHeaderKey(strings.Replace(k, "_", "-", -1))] = size
+	}
+
+	if hl.maxTotal > 0 {
+		hl.metrics[HeaderSizeMetricTotal] = &HeaderSizeMetric{Header: HeaderSizeMetricTotal, Limit: hl.maxTotal}
+	}
+	if hl.defaultMax > 0 {
+		hl.metrics[HeaderSizeMetricOther] = &HeaderSizeMetric{Header: HeaderSizeMetricOther, Limit: hl.defaultMax}
+	}
+	for name, limit := range hl.limits {
+		hl.metrics[name] = &HeaderSizeMetric{Header: name, Limit: limit}
+	}
+
+	a.headerLimits = hl
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Header limiter
+//______________________________________________________________________________
+
+type headerLimiter struct {
+	sync.Mutex
+	reject     bool
+	maxTotal   int64
+	defaultMax int64
+	limits     map[string]int64
+	metrics    map[string]*HeaderSizeMetric
+}
+
+// IsExceeded method audits the request header sizes against the configured
+// limits. Returns true if request has to be rejected.
+func (hl *headerLimiter) IsExceeded(ctx *Context) bool {
+	var total int64
+	exceeded := false
+	for name, values := range ctx.Req.Header {
+		size := headerSize(name, values)
+		total += size
+
+		metricName, limit := name, hl.limits[name]
+		if _, found := hl.limits[name]; !found {
+			metricName, limit = HeaderSizeMetricOther, hl.defaultMax
+		}
+		if limit > 0 && size > limit {
+			ctx.Log().Warnf("Request header '%s' size %d exceeds the limit %d", name, size, limit)
+			hl.record(metricName, size)
+			exceeded = true
+		}
+	}
+
+	if hl.maxTotal > 0 && total > hl.maxTotal {
+		ctx.Log().Warnf("Request headers total size %d exceeds the limit %d", total, hl.maxTotal)
+		hl.record(HeaderSizeMetricTotal, total)
+		exceeded = true
+	}
+
+	return exceeded && hl.reject
+}
+
+func (hl *headerLimiter) record(name string, size int64) {
+	hl.Lock()
+	defer hl.Unlock()
+	m := hl.metrics[name]
+	m.Exceeded++
+	if hl.reject {
+		m.Rejected++
+	}
+	if size > m.MaxSize {
+		m.MaxSize = size
+	}
+}
+
+func (hl *headerLimiter) snapshot() map[string]HeaderSizeMetric {
+	hl.Lock()
+	defer hl.Unlock()
+	result := make(map[string]HeaderSizeMetric, len(hl.metrics))
+	for name, m := range hl.metrics {
+		result[name] = *m
+	}
+	return result
+}
+
+// headerSize method returns the wire size of header in the form
+// of `Name: Value\r\n` for each value.
+func headerSize(name string, values []string) int64 {
+	var size int64
+	for _, v := range values {
+		size += int64(len(name) + len(v) + 4)
+	}
+	return size
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderLimits(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Header Limits]: %s", ts.URL)
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  header_limits {
+	    enable = true
+	    action = "reject"
+	    max_total_size = "4kb"
+	    default_max_size = "1kb"
+	    headers {
+	      x_api_key = "64b"
+	    }
+	  }
+	}
+	`))
+	err := ts.app.initHeaderLimits()
+	assert.Nil(t, err)
+
+	send := func(hdr, value string) *http.Response {
+		req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/get-text.html", nil)
+		req.Header.Set(hdr, value)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	resp := send("X-Api-Key", "abc")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = send("X-Api-Key", strings.Repeat("k", 100))
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+
+	resp = send("X-Custom", strings.Repeat("c", 2048))
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+
+	metrics := ts.app.HeaderSizeMetrics()
+	assert.Equal(t, int64(1), metrics["X-Api-Key"].Rejected)
+	assert.Equal(t, int64(64), metrics["X-Api-Key"].Limit)
+	assert.Equal(t, int64(113), metrics["X-Api-Key"].MaxSize)
+	assert.Equal(t, int64(1), metrics[HeaderSizeMetricOther].Exceeded)
+	assert.Equal(t, int64(0), metrics[HeaderSizeMetricTotal].Exceeded)
+
+	// log only
+	ts.app.Config().SetString("server.header_limits.action", "log")
+	err = ts.app.initHeaderLimits()
+	assert.Nil(t, err)
+	resp = send("X-Custom", strings.Repeat("c", 5000))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	metrics = ts.app.HeaderSizeMetrics()
+	assert.Equal(t, int64(1), metrics[HeaderSizeMetricTotal].Exceeded)
+	assert.Equal(t, int64(0), metrics[HeaderSizeMetricTotal].Rejected)
+}
+
+func TestHeaderLimitsConfigErrors(t *testing.T) {
+	a := newWebApp1TestApp(t)
+	assert.Nil(t, a.HeaderSizeMetrics())
+
+	for _, c := range []struct{ cfg, err string }{
+		{`server { header_limits {
+			enable = true
+			action = "drop"
+		} }`, "'server.header_limits.action' has unsupported value 'drop', supported values are log and reject"},
+		{`server { header_limits {
+			enable = true
+			max_total_size = "large"
+		} }`, "'server.header_limits.max_total_size': format: invalid input 'large'"},
+		{`server { header_limits {
+			enable = true
+			headers { cookie = "4"; }
+		} }`, "'server.header_limits.headers.cookie': format: invalid input '4'"},
+	} {
+		assert.Nil(t, setTestConfig(a, c.cfg))
+		err := a.initHeaderLimits()
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+)
+
+// Health check status values.
+const (
+	HealthPass = "pass"
+	HealthFail = "fail"
+)
+
+// ErrShuttingDown is reported by readiness check during graceful shutdown.
+var ErrShuttingDown = errors.New("aah: server is shutting down")
+
+// HealthCheckFunc func type is used to check the health of application
+// dependency such as database, cache, etc. Returns nil when it's healthy.
+type HealthCheckFunc func(ctx context.Context) error
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// HealthChecker method returns the application health check registry, use
+// it to register the readiness checks, see `server.health { ... }`.
+//
+//	err := aah.App().HealthChecker().Register("primary_db", 2*time.Second,
+//		func(ctx context.Context) error {
+//			return db.PingContext(ctx)
+//		})
+func (a *Application) HealthChecker() *HealthChecker {
+	return a.health
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Health checker
+//______________________________________________________________________________
+
+// HealthChecker is the registry of named health checks. Liveness reports
+// the process is serving, readiness runs the registered checks and fails
+// during graceful shutdown so load balancers drain the traffic.
+type HealthChecker struct {
+	mu             sync.RWMutex
+	checks         []*healthCheck
+	defaultTimeout int64
+	shuttingDown   int32
+}
+
+// HealthReport holds the result of health check.
+type HealthReport struct {
+	Status string                        `json:"status"`
+	Error  string                        `json:"error,omitempty"`
+	Checks map[string]*HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult holds the result of single named check.
+type HealthCheckResult struct {
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+type healthCheck struct {
+	name    string
+	timeout time.Duration
+	fn      HealthCheckFunc
+}
+
+// Register method registers the readiness check for given name, timeout
+// `0` means `server.health.timeout` value is used.
+func (h *HealthChecker) Register(name string, timeout time.Duration, fn HealthCheckFunc) error {
+	if ess.IsStrEmpty(name) {
+		return errors.New("aah: health check name is empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("aah: health check '%s' func is nil", name)
+	}
+	if timeout < 0 {
+		return fmt.Errorf("aah: health check '%s' timeout must not be negative", name)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, c := range h.checks {
+		if c.name == name {
+			return fmt.Errorf("aah: health check '%s' is already registered", name)
+		}
+	}
+	h.checks = append(h.checks, &healthCheck{name: name, timeout: timeout, fn: fn})
+	return nil
+}
+
+// Liveness method returns the liveness report, it passes as long as the
+// process is able to serve the request.
+func (h *HealthChecker) Liveness() *HealthReport {
+	return &HealthReport{Status: HealthPass}
+}
+
+// Readiness method runs the registered checks concurrently with its timeout
+// and returns the readiness report. It fails if any check fails or server
+// is shutting down.
+func (h *HealthChecker) Readiness(ctx context.Context) *HealthReport {
+	h.mu.RLock()
+	checks := h.checks
+	h.mu.RUnlock()
+
+	report := &HealthReport{Status: HealthPass}
+	if atomic.LoadInt32(&h.shuttingDown) == 1 {
+		report.Status, report.Error = HealthFail, ErrShuttingDown.Error()
+	}
+	if len(checks) == 0 {
+		return report
+	}
+
+	results := make([]*HealthCheckResult, len(checks))
+	wg := sync.WaitGroup{}
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *healthCheck) {
+			defer wg.Done()
+			results[i] = h.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report.Checks = make(map[string]*HealthCheckResult, len(checks))
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status == HealthFail {
+			report.Status = HealthFail
+		}
+	}
+	return report
+}
+
+func (h *HealthChecker) run(ctx context.Context, c *healthCheck) *HealthCheckResult {
+	timeout := c.timeout
+	if timeout == 0 {
+		timeout = time.Duration(atomic.LoadInt64(&h.defaultTimeout))
+	}
+	cctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		errCh <- c.fn(cctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-cctx.Done():
+		err = cctx.Err()
+	}
+
+	result := &HealthCheckResult{Status: HealthPass, Duration: time.Since(start)}
+	if err != nil {
+		result.Status, result.Error = HealthFail, err.Error()
+	}
+	return result
+}
+
+func (h *HealthChecker) setShuttingDown() {
+	atomic.StoreInt32(&h.shuttingDown, 1)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initHealth method initializes the liveness and readiness endpoints from
+// config `server.health { ... }`. Application routes takes precedence over
+// these endpoints. Readiness fails on graceful shutdown and server waits
+// for `shutdown_delay` before it stops accepting the connections.
+//
+//	server {
+//	  health {
+//	    enable = true
+//	    liveness_path = "/healthz"
+//	    readiness_path = "/readyz"
+//	    timeout = "5s"
+//	    shutdown_delay = "5s"
+//	  }
+//	}
+func (a *Application) initHealth() error {
+	keyPrefix := "server.health"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.healthEndpoint = nil
+		return nil
+	}
+
+	he := &healthEndpoint{
+		livenessPath:  cfg.StringDefault(keyPrefix+".liveness_path", "/healthz"),
+		readinessPath: cfg.StringDefault(keyPrefix+".readiness_path", "/readyz"),
+	}
+	for _, p := range [][2]string{{"liveness_path", he.livenessPath}, {"readiness_path", he.readinessPath}} {
+		if !strings.HasPrefix(p[1], "/") {
+			return fmt.Errorf("'%s.%s' value must begin with '/'", keyPrefix, p[0])
+		}
+	}
+
+	timeout, err := settings.ParseDuration(cfg, keyPrefix+".timeout", 5*time.Second)
+	if err != nil {
+		return err
+	}
+	if timeout == 0 {
+		return fmt.Errorf("'%s.timeout' value must be greater than zero", keyPrefix)
+	}
+	if he.shutdownDelay, err = settings.ParseDuration(cfg, keyPrefix+".shutdown_delay", 0); err != nil {
+		return err
+	}
+
+	atomic.StoreInt64(&a.health.defaultTimeout, int64(timeout))
+	a.healthEndpoint = he
+	return nil
+}
+
+// drainHealth method flips the readiness to failing and waits for
+// `server.health.shutdown_delay`, so the load balancers stop sending the
+// new requests before the server stops accepting the connections.
+func (a *Application) drainHealth() {
+	a.health.setShuttingDown()
+	if he := a.healthEndpoint; he != nil && he.shutdownDelay > 0 {
+		a.Log().Infof("Readiness is failing, waiting %s for load balancers to drain the traffic", he.shutdownDelay)
+		time.Sleep(he.shutdownDelay)
+	}
+}
+
+func newHealthChecker() *HealthChecker {
+	return &HealthChecker{defaultTimeout: int64(5 * time.Second)}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Health endpoint
+//______________________________________________________________________________
+
+type healthEndpoint struct {
+	livenessPath  string
+	readinessPath string
+	shutdownDelay time.Duration
+}
+
+// Serve method replies the liveness and readiness report, status is `200`
+// if it passes otherwise `503`. Returns true if request is served otherwise
+// false.
+func (he *healthEndpoint) Serve(ctx *Context) bool {
+	if ctx.Req.Method != ahttp.MethodGet && ctx.Req.Method != ahttp.MethodHead {
+		return false
+	}
+
+	var report *HealthReport
+	switch ctx.Req.Path {
+	case he.livenessPath:
+		report = ctx.a.health.Liveness()
+	case he.readinessPath:
+		report = ctx.a.health.Readiness(ctx.Req.Context())
+	default:
+		return false
+	}
+
+	status := http.StatusOK
+	if report.Status != HealthPass {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.Reply().Status(status).
+		NoCache().
+		JSON(report)
+	return true
+}
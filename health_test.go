@@ -0,0 +1,96 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Health]: %s", ts.URL)
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  health {
+	    enable = true
+	    timeout = "50ms"
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initHealth())
+
+	hc := ts.app.HealthChecker()
+	assert.Nil(t, hc.Register("primary_db", time.Second, func(ctx context.Context) error { return nil }))
+	assert.Equal(t, "aah: health check 'primary_db' is already registered",
+		hc.Register("primary_db", 0, func(ctx context.Context) error { return nil }).Error())
+	assert.Equal(t, "aah: health check 'cache' func is nil", hc.Register("cache", 0, nil).Error())
+	assert.Equal(t, "aah: health check name is empty", hc.Register("", 0, nil).Error())
+
+	get := func(path string) (int, *HealthReport) {
+		resp, err := http.Get(ts.URL + path)
+		assert.Nil(t, err)
+		assert.Equal(t, "no-cache, no-store, must-revalidate", resp.Header.Get(ahttp.HeaderCacheControl))
+		report := &HealthReport{}
+		assert.Nil(t, json.Unmarshal([]byte(responseBody(resp)), report))
+		return resp.StatusCode, report
+	}
+
+	status, report := get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, HealthPass, report.Status)
+	assert.Nil(t, report.Checks)
+
+	status, report = get("/readyz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, HealthPass, report.Checks["primary_db"].Status)
+
+	// failing and timed out checks, default timeout is applied
+	assert.Nil(t, hc.Register("cache", 0, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	assert.Nil(t, hc.Register("queue", 0, func(ctx context.Context) error { return errors.New("connection refused") }))
+	status, report = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, HealthFail, report.Status)
+	assert.Equal(t, HealthPass, report.Checks["primary_db"].Status)
+	assert.Equal(t, "context deadline exceeded", report.Checks["cache"].Error)
+	assert.Equal(t, "connection refused", report.Checks["queue"].Error)
+
+	// liveness is not affected by readiness checks
+	status, _ = get("/healthz")
+	assert.Equal(t, http.StatusOK, status)
+
+	// readiness fails during graceful shutdown
+	ts.app.drainHealth()
+	report = hc.Readiness(context.Background())
+	assert.Equal(t, HealthFail, report.Status)
+	assert.Equal(t, ErrShuttingDown.Error(), report.Error)
+
+	// invalid config
+	ts.app.Config().SetString("server.health.readiness_path", "readyz")
+	err := ts.app.initHealth()
+	assert.Equal(t, "'server.health.readiness_path' value must begin with '/'", err.Error())
+
+	ts.app.Config().SetString("server.health.readiness_path", "/readyz")
+	ts.app.Config().SetString("server.health.shutdown_delay", "5x")
+	err = ts.app.initHealth()
+	assert.Equal(t, "'server.health.shutdown_delay' value is not a valid time unit", err.Error())
+
+	ts.app.Config().SetBool("server.health.enable", false)
+	assert.Nil(t, ts.app.initHealth())
+	assert.Nil(t, ts.app.healthEndpoint)
+}
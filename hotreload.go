@@ -0,0 +1,111 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/internal/settings"
+	"aahframe.work/log"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// HotReloadStatus method returns the snapshot of config hot-reload status,
+// it's useful for health and metrics endpoints to report the rejected
+// config. Application keeps serving with previous config when reloaded
+// config is invalid.
+func (a *Application) HotReloadStatus() HotReloadStatus {
+	return a.reloadStat.snapshot()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Hot-reload status
+//______________________________________________________________________________
+
+// HotReloadStatus holds the config hot-reload metrics and the last failure
+// details. `Rejected` has the config keys of last rejected config which
+// differs from the config in use, secret values are masked.
+type HotReloadStatus struct {
+	Reloads     int64
+	Failures    int64
+	Healthy     bool
+	LastError   string
+	LastAttempt time.Time
+	LastSuccess time.Time
+	Rejected    []settings.ProfileDiff
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// rejectHotReload method logs the rejected config keys with validation error
+// in structured form, config in use is not modified.
+func (a *Application) rejectHotReload(cfg *config.Config, err error) {
+	diffs, derr := a.settings().DiffConfig(cfg)
+	if derr != nil {
+		a.Log().Warnf("Unable to diff rejected config: %v", derr)
+	}
+	a.reloadStat.setRejected(diffs)
+
+	changes := make(map[string]interface{}, len(diffs))
+	for _, d := range diffs {
+		changes[d.Key] = d.String()
+	}
+	a.Log().WithFields(log.Fields{
+		"error":   err.Error(),
+		"changes": changes,
+	}).Errorf("Hot-reload config is invalid, rejected; continuing with previous config")
+}
+
+type reloadStatus struct {
+	sync.RWMutex
+	reloads     int64
+	failures    int64
+	lastError   string
+	lastAttempt time.Time
+	lastSuccess time.Time
+	rejected    []settings.ProfileDiff
+}
+
+func (rs *reloadStatus) record(err error) {
+	rs.Lock()
+	defer rs.Unlock()
+	rs.reloads++
+	rs.lastAttempt = time.Now()
+	if err != nil {
+		rs.failures++
+		rs.lastError = err.Error()
+		return
+	}
+	rs.lastError = ""
+	rs.lastSuccess = rs.lastAttempt
+	rs.rejected = nil
+}
+
+func (rs *reloadStatus) setRejected(diffs []settings.ProfileDiff) {
+	rs.Lock()
+	rs.rejected = diffs
+	rs.Unlock()
+}
+
+func (rs *reloadStatus) snapshot() HotReloadStatus {
+	rs.RLock()
+	defer rs.RUnlock()
+	return HotReloadStatus{
+		Reloads:     rs.reloads,
+		Failures:    rs.failures,
+		Healthy:     len(rs.lastError) == 0,
+		LastError:   rs.lastError,
+		LastAttempt: rs.lastAttempt,
+		LastSuccess: rs.lastSuccess,
+		Rejected:    append([]settings.ProfileDiff(nil), rs.rejected...),
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/internal/settings"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+type testReloadProvider struct {
+	cfg string
+}
+
+func (p *testReloadProvider) Name() string { return "test" }
+
+func (p *testReloadProvider) Load() (*config.Config, error) {
+	return config.ParseString(p.cfg)
+}
+
+func TestHotReloadRollback(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	a := ts.app
+	a.Config().SetString("env.active", a.EnvProfile())
+	cfg := a.Config()
+	readTimeout := a.settings().HTTPReadTimeout
+	p := &testReloadProvider{cfg: `server { timeout { read = "2x"; } }`}
+	a.settingsHolder.Update(func(s *settings.Settings) { s.ConfigProvider = p })
+
+	// invalid config is rejected, previous config and settings are in use
+	a.performHotReload()
+	assert.True(t, cfg == a.Config())
+	assert.Equal(t, readTimeout, a.settings().HTTPReadTimeout)
+	assert.Equal(t, p, a.settings().ConfigProvider)
+
+	status := a.HotReloadStatus()
+	assert.False(t, status.Healthy)
+	assert.Equal(t, int64(1), status.Reloads)
+	assert.Equal(t, int64(1), status.Failures)
+	assert.Contains(t, status.LastError, "'server.timeout.read'")
+	assert.True(t, status.LastSuccess.IsZero())
+	assert.Equal(t, 1, len(status.Rejected))
+	assert.Equal(t, "server.timeout.read", status.Rejected[0].Key)
+	assert.Equal(t, "2x", status.Rejected[0].B)
+
+	// valid config is applied, snapshot held by reader is unchanged
+	snapshot := a.settings()
+	p.cfg = `server { timeout { read = "30s"; } }`
+	a.performHotReload()
+	assert.False(t, cfg == a.Config())
+	assert.Equal(t, 30*time.Second, a.settings().HTTPReadTimeout)
+	assert.Equal(t, readTimeout, snapshot.HTTPReadTimeout)
+	assert.False(t, snapshot == a.settings())
+
+	status = a.HotReloadStatus()
+	assert.True(t, status.Healthy)
+	assert.Equal(t, int64(2), status.Reloads)
+	assert.Equal(t, int64(1), status.Failures)
+	assert.Equal(t, "", status.LastError)
+	assert.Equal(t, status.LastAttempt, status.LastSuccess)
+	assert.Nil(t, status.Rejected)
+}
+
+func TestSettingsHolderSnapshot(t *testing.T) {
+	h := settings.NewHolder(&settings.Settings{VirtualBaseDir: "/app"})
+	s1 := h.Load()
+
+	h.Update(func(s *settings.Settings) { s.EnvProfile = "prod" })
+	s2 := h.Load()
+	assert.False(t, s1 == s2)
+	assert.Equal(t, "", s1.EnvProfile)
+	assert.Equal(t, "prod", s2.EnvProfile)
+	assert.Equal(t, "/app", s2.VirtualBaseDir)
+
+	cfg, _ := config.ParseString(`server { timeout { read = "2x"; } }`)
+	err := h.Refresh(cfg)
+	assert.NotNil(t, err)
+	assert.True(t, s2 == h.Load())
+
+	// autocert manager is not shared between snapshots
+	h.Update(func(s *settings.Settings) { s.Autocert = &autocert.Manager{Email: "admin@example.com"} })
+	s3 := h.Load()
+	h.Update(func(s *settings.Settings) { s.Autocert.Email = "ops@example.com" })
+	assert.Equal(t, "admin@example.com", s3.Autocert.Email)
+	assert.Equal(t, "ops@example.com", h.Load().Autocert.Email)
+
+	var called int
+	h.OnConfigChange(func() { called++ })
+	h.PublishConfigChange()
+	assert.Equal(t, 1, called)
+}
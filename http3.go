@@ -0,0 +1,126 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// HTTP3Server interface is implemented by the QUIC based HTTP/3 server, for
+// e.g.: wrapper of `github.com/quic-go/quic-go/http3.Server`. aah does not
+// bundle QUIC implementation, application provides it via
+// `Application.SetHTTP3Server`.
+type HTTP3Server interface {
+	// ListenAndServe method listens on the UDP address and serves the
+	// HTTP/3 requests, it blocks until server is closed.
+	ListenAndServe() error
+
+	// Close method closes the HTTP/3 server.
+	Close() error
+}
+
+// HTTP3ServerFunc type is used to create the HTTP/3 server for given UDP
+// address, TLS config and aah application handler.
+type HTTP3ServerFunc func(addr string, tlsCfg *tls.Config, handler http.Handler) HTTP3Server
+
+// SetHTTP3Server method sets the HTTP/3 server func, it's used when
+// `server.http3.enable` is true. HTTP/3 server shares the same router and
+// TLS configuration of HTTPS server, HTTPS responses advertise the HTTP/3
+// via header `Alt-Svc`.
+//
+// For e.g.:
+//	aah.App().SetHTTP3Server(func(addr string, tlsCfg *tls.Config, h http.Handler) aah.HTTP3Server {
+//		return &http3.Server{Addr: addr, TLSConfig: tlsCfg, Handler: h}
+//	})
+func (a *Application) SetHTTP3Server(fn HTTP3ServerFunc) {
+	a.Lock()
+	defer a.Unlock()
+	a.h3Fn = fn
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// startHTTP3 method starts the HTTP/3 server if `server.http3.enable` is
+// true, it has to be called after HTTPS server TLS config is set.
+func (a *Application) startHTTP3() error {
+	if !a.settings().HTTP3Enabled {
+		return nil
+	}
+
+	a.RLock()
+	fn := a.h3Fn
+	a.RUnlock()
+	if fn == nil {
+		return errors.New("'server.http3.enable' is true, however HTTP/3 server is not set, use 'SetHTTP3Server'")
+	}
+
+	tlsCfg, err := a.http3TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	port := firstNonZeroString(a.settings().HTTP3Port, a.HTTPPort())
+	addr := net.JoinHostPort(a.HTTPAddress(), port)
+	srv := fn(addr, tlsCfg, a.server.Handler)
+	if srv == nil {
+		return errors.New("HTTP/3 server func returned nil")
+	}
+
+	a.Lock()
+	a.h3srv = srv
+	a.h3AltSvc = fmt.Sprintf(`h3=":%s"; ma=%d`, port, int64(a.settings().HTTP3AltSvcMaxAge.Seconds()))
+	a.Unlock()
+
+	go func() {
+		a.Log().Infof("aah go HTTP/3 server running on %s (udp)", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			a.Log().Error(err)
+		}
+	}()
+	return nil
+}
+
+// http3TLSConfig method returns the copy of HTTPS server TLS config with
+// HTTP/3 ALPN, TLS 1.3 is mandatory for QUIC.
+func (a *Application) http3TLSConfig() (*tls.Config, error) {
+	var tlsCfg *tls.Config
+	if a.server.TLSConfig != nil {
+		tlsCfg = a.server.TLSConfig.Clone()
+	} else {
+		cert, err := tls.LoadX509KeyPair(a.settings().SSLCert, a.settings().SSLKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	tlsCfg.NextProtos = []string{"h3"}
+	tlsCfg.MinVersion = tls.VersionTLS13
+	return tlsCfg, nil
+}
+
+// http3AltSvc method returns the `Alt-Svc` header value if HTTP/3 server is
+// running otherwise empty string.
+func (a *Application) http3AltSvc() string {
+	a.RLock()
+	defer a.RUnlock()
+	return a.h3AltSvc
+}
+
+func (a *Application) shutdownHTTP3() {
+	a.RLock()
+	srv := a.h3srv
+	a.RUnlock()
+	if srv != nil {
+		if err := srv.Close(); err != nil {
+			a.Log().Error(err)
+		}
+	}
+}
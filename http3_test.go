@@ -0,0 +1,80 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"aahframe.work/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+type testHTTP3Server struct {
+	addr    string
+	tlsCfg  *tls.Config
+	handler http.Handler
+	closed  chan struct{}
+}
+
+func (s *testHTTP3Server) ListenAndServe() error {
+	<-s.closed
+	return http.ErrServerClosed
+}
+
+func (s *testHTTP3Server) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestServerHTTP3(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `
+	server {
+	  address = "127.0.0.1"
+	  http3 {
+	    enable = true
+	  }
+	}
+	env {
+	  dev { }
+	}`))
+	err := a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.http3.enable' requires TLS, enable 'server.ssl.enable'", err.Error())
+
+	a.settingsHolder.Update(func(s *settings.Settings) {
+		s.HTTP3Enabled = true
+		s.HTTP3Port = "8443"
+		s.HTTP3AltSvcMaxAge = 24 * time.Hour
+	})
+	a.server = &http.Server{Handler: a, TLSConfig: &tls.Config{NextProtos: []string{"h2", "http/1.1"}}}
+
+	// HTTP/3 server is not set
+	err = a.startHTTP3()
+	assert.Equal(t, "'server.http3.enable' is true, however HTTP/3 server is not set, use 'SetHTTP3Server'", err.Error())
+	assert.Equal(t, "", a.http3AltSvc())
+
+	var h3 *testHTTP3Server
+	a.SetHTTP3Server(func(addr string, tlsCfg *tls.Config, handler http.Handler) HTTP3Server {
+		h3 = &testHTTP3Server{addr: addr, tlsCfg: tlsCfg, handler: handler, closed: make(chan struct{})}
+		return h3
+	})
+	assert.Nil(t, a.startHTTP3())
+	assert.Equal(t, "127.0.0.1:8443", h3.addr)
+	assert.Equal(t, []string{"h3"}, h3.tlsCfg.NextProtos)
+	assert.Equal(t, uint16(tls.VersionTLS13), h3.tlsCfg.MinVersion)
+	assert.Equal(t, []string{"h2", "http/1.1"}, a.server.TLSConfig.NextProtos)
+	assert.Equal(t, a, h3.handler)
+	assert.Equal(t, `h3=":8443"; ma=86400`, a.http3AltSvc())
+
+	a.shutdownHTTP3()
+	select {
+	case <-h3.closed:
+	default:
+		t.Error("HTTP/3 server is not closed")
+	}
+}
@@ -5,9 +5,16 @@
 package aah
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +23,7 @@ import (
 	"aahframe.work/aruntime"
 	"aahframe.work/essentials"
 	"aahframe.work/internal/settings"
+	"aahframe.work/internal/util"
 	"aahframe.work/log"
 	"aahframe.work/security"
 	"aahframe.work/security/authc"
@@ -26,13 +34,7 @@ const (
 	flowAbort
 )
 
-const (
-	gzipContentEncoding = "gzip"
-
-	// Standard frame type MTU size is 1500 bytes so 1400 bytes would make sense
-	// to Gzip by default. Read: https://en.wikipedia.org/wiki/Maximum_transmission_unit
-	defaultGzipMinSize = 1400
-)
+const gzipContentEncoding = "gzip"
 
 var (
 	errFileNotFound = errors.New("file not found")
@@ -42,6 +44,10 @@ type (
 	// MinifierFunc is to minify the HTML buffer and write the response into writer.
 	MinifierFunc func(contentType string, w io.Writer, r io.Reader) error
 
+	// ResponseHookFunc is signature of response hook function, see
+	// `HTTPEngine.OnResponseFirstByte` and `HTTPEngine.OnResponseComplete`.
+	ResponseHookFunc func(ctx *Context, info *ResponseInfo)
+
 	// flowResult is result of engine activities flow.
 	// For e.g.: route, authentication, authorization, etc.
 	flowResult uint8
@@ -67,6 +73,29 @@ type HTTPEngine struct {
 	onPostReplyFunc   EventCallbackFunc
 	onPreAuthFunc     EventCallbackFunc
 	onPostAuthFunc    EventCallbackFunc
+
+	// response hooks
+	onResFirstByte []ResponseHookFunc
+	onResComplete  []ResponseHookFunc
+}
+
+// ResponseInfo holds the response status, bytes and timing details, it's
+// supplied to the response hooks.
+type ResponseInfo struct {
+	Status        int
+	BytesWritten  int
+	StartTime     time.Time
+	FirstByteTime time.Time
+	Duration      time.Duration
+}
+
+// TimeToFirstByte method returns the duration between request start and
+// the first byte of response. Returns 0 if nothing is written yet.
+func (ri *ResponseInfo) TimeToFirstByte() time.Duration {
+	if ri.FirstByteTime.IsZero() {
+		return 0
+	}
+	return ri.FirstByteTime.Sub(ri.StartTime)
 }
 
 // Handle method is HTTP handler for aah application.
@@ -75,31 +104,118 @@ func (e *HTTPEngine) Handle(w http.ResponseWriter, r *http.Request) {
 	defer e.releaseContext(ctx)
 
 	// Record access log
-	if e.a.settings.AccessLogEnabled {
+	if e.a.settings().AccessLogEnabled {
 		ctx.Set(reqStartTimeKey, time.Now())
 		defer e.a.accessLog.Log(ctx)
 	}
 
 	ctx.Req, ctx.Res = ahttp.AcquireRequest(r), ahttp.AcquireResponseWriter(w)
+	ctx.clientCtx = r.Context()
+
+	// Advertise HTTP/3 on HTTPS responses
+	if r.TLS != nil && r.ProtoMajor < 3 {
+		if altSvc := e.a.http3AltSvc(); len(altSvc) > 0 {
+			w.Header().Set(ahttp.HeaderAltSvc, altSvc)
+		}
+	}
+
+	// Response hooks
+	if len(e.onResFirstByte) > 0 || len(e.onResComplete) > 0 {
+		ri := &ResponseInfo{StartTime: time.Now()}
+		ctx.Res.(*ahttp.Response).OnFirstByte(func() {
+			ri.FirstByteTime = time.Now()
+			ri.Status = ctx.Res.Status()
+			e.publishResponseHooks(e.onResFirstByte, ctx, ri)
+		})
+		defer e.publishResponseComplete(ctx, ri)
+	}
+
+	// Server span of request, ended after the recovery handling
+	if t := e.a.tracer; t != nil {
+		defer ctx.endServerSpan(ctx.startServerSpan(t))
+	}
+
+	// Request context deadline from `server.timeout.request`, route
+	// `timeout` is applied on top of it in the route middleware
+	if e.a.settings().HTTPRequestTimeout > 0 {
+		c, cancel := context.WithTimeout(ctx.Req.Context(), e.a.settings().HTTPRequestTimeout)
+		defer cancel()
+		ctx.Req.SetContext(c)
+	}
+
+	// Failed (5xx) request capture, recorded after the recovery handling
+	if fc := e.a.failedReqs; fc != nil {
+		fc.Capture(ctx)
+		defer fc.Record(ctx)
+	}
+
+	// Request metrics, recorded after the recovery handling
+	if rm := e.a.reqMetrics; rm != nil {
+		defer rm.Record(ctx, time.Now(), rm.Begin())
+	}
+
+	// Slow request log, logged after the recovery handling
+	if rc := e.a.runtimeCtl; rc != nil && rc.isSlowRequestLog() {
+		defer rc.LogSlowRequest(ctx, time.Now())
+	}
+
+	// Audit trail of configured routes, recorded after the recovery handling
+	if at := e.a.audit; at != nil {
+		defer at.Record(ctx, time.Now())
+	}
 
 	// Recovery handling
 	defer e.handleRecovery(ctx)
 
-	if e.a.settings.RequestIDEnabled {
+	if e.a.settings().RequestIDEnabled {
 		ctx.setRequestID()
 	}
 
+	// Compression and minification bypass for payload debugging
+	if rr := e.a.rawResponse; rr != nil && rr.Qualify(ctx) {
+		ctx.Req.IsGzipAccepted = false
+		ctx.rawResBody = true
+	}
+
+	// Server connection and concurrent request limits
+	if !e.a.limits.acquire(ctx) {
+		e.writeReply(ctx)
+		return
+	}
+	defer e.a.limits.release()
+
 	// Load session from request if its `stateful` and subject authentication info.
 	if ctx.a.SessionManager().IsStateful() {
 		ctx.Subject().Session = ctx.a.SessionManager().GetSession(ctx.Req.Unwrap())
 		if ctx.Session().IsKeyExists(KeyViewArgAuthcInfo) {
 			populateAuthenticationInfo(ctx.Session().Get(KeyViewArgAuthcInfo).(*authc.AuthenticationInfo), ctx)
 		}
+		if ctx.Session().IsKeyExists(KeyImpersonatorAuthcInfo) {
+			ctx.Subject().Impersonator = ctx.Session().Get(KeyImpersonatorAuthcInfo).(*authc.AuthenticationInfo)
+		}
 	}
 
 	// 'OnRequest' HTTP engine event
 	e.publishOnRequestEvent(ctx)
 
+	// Request URL length and query parameters limit
+	if e.isRequestLimitExceeded(ctx) {
+		e.writeReply(ctx)
+		return
+	}
+
+	// Request header size limits
+	if e.a.headerLimits != nil && e.a.headerLimits.IsExceeded(ctx) {
+		ctx.Reply().RequestHeaderFieldsTooLarge().Error(newError(ErrRequestHeaderTooLarge, http.StatusRequestHeaderFieldsTooLarge))
+		e.writeReply(ctx)
+		return
+	}
+
+	// Mirror request to shadow upstream
+	if e.a.mirror != nil {
+		e.a.mirror.Mirror(ctx)
+	}
+
 	// Middlewares, interceptors, targeted controller
 	if len(e.mwChain) == 0 {
 		if e.a.Type() == "websocket" {
@@ -217,6 +333,25 @@ func (e *HTTPEngine) OnPostAuth(sef EventCallbackFunc) {
 	e.onPostAuthFunc = sef
 }
 
+// OnResponseFirstByte method adds the response hook, it's called when the
+// first byte of response is written i.e. right after the response status.
+// Unlike server extensions, multiple hooks can be added, typically by the
+// modules such as metrics, audit, etc.
+//
+// Note: `ResponseInfo` has `Status`, `StartTime` and `FirstByteTime` at this
+// point.
+func (e *HTTPEngine) OnResponseFirstByte(fn ResponseHookFunc) {
+	e.onResFirstByte = append(e.onResFirstByte, fn)
+}
+
+// OnResponseComplete method adds the response hook, it's called once the
+// request is completed with final response status, bytes written and
+// timing. It's called for every request including `Reply().Done()`,
+// `Reply().Redirect(...)` and recovered panics.
+func (e *HTTPEngine) OnResponseComplete(fn ResponseHookFunc) {
+	e.onResComplete = append(e.onResComplete, fn)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // HTTP Engine - Server Extension Publish
 //______________________________________________________________________________
@@ -259,6 +394,19 @@ func (e *HTTPEngine) publishOnPostAuthEvent(ctx *Context) {
 	}
 }
 
+func (e *HTTPEngine) publishResponseComplete(ctx *Context, ri *ResponseInfo) {
+	ri.Status = ctx.Res.Status()
+	ri.BytesWritten = ctx.Res.BytesWritten()
+	ri.Duration = time.Since(ri.StartTime)
+	e.publishResponseHooks(e.onResComplete, ctx, ri)
+}
+
+func (e *HTTPEngine) publishResponseHooks(hooks []ResponseHookFunc, ctx *Context, ri *ResponseInfo) {
+	for _, fn := range hooks {
+		fn(ctx, ri)
+	}
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Engine Unexported methods
 //______________________________________________________________________________
@@ -267,6 +415,25 @@ func (e *HTTPEngine) newContext() *Context {
 	return &Context{a: e.a, e: e}
 }
 
+// isRequestLimitExceeded method checks the request URL length and query
+// parameters count against config `request.limits.*`.
+func (e *HTTPEngine) isRequestLimitExceeded(ctx *Context) bool {
+	r := ctx.Req.Unwrap()
+	if e.a.settings().MaxURLLength > 0 && len(r.RequestURI) > e.a.settings().MaxURLLength {
+		ctx.Log().Warnf("Request URI length %d exceeds the limit %d", len(r.RequestURI), e.a.settings().MaxURLLength)
+		ctx.Reply().RequestURITooLong().Error(newError(ErrRequestURITooLong, http.StatusRequestURITooLong))
+		return true
+	}
+
+	if e.a.settings().MaxQueryParams > 0 && countParams(r.URL.RawQuery) > e.a.settings().MaxQueryParams {
+		ctx.Log().Warnf("Request query parameters exceeds the limit %d", e.a.settings().MaxQueryParams)
+		ctx.Reply().BadRequest().Error(newError(ErrRequestLimitExceeded, http.StatusBadRequest))
+		return true
+	}
+
+	return false
+}
+
 // handleRecovery method handles application panics and recovers from it.
 // Panic gets translated into HTTP Internal Server Error (Status 500).
 func (e *HTTPEngine) handleRecovery(ctx *Context) {
@@ -309,6 +476,7 @@ func (e *HTTPEngine) writeReply(ctx *Context) {
 
 	// HTTP headers
 	ctx.writeHeaders()
+	ctx.writeContentLanguage()
 
 	// Set Cookies
 	ctx.writeCookies()
@@ -338,6 +506,7 @@ func (e *HTTPEngine) writeReply(ctx *Context) {
 		}
 
 		e.writeOnWire(ctx)
+		ctx.writeTrailers()
 	} else {
 		ctx.Res.Header().Del(ahttp.HeaderContentType)
 		ctx.Res.WriteHeader(re.Code)
@@ -347,7 +516,7 @@ func (e *HTTPEngine) writeReply(ctx *Context) {
 	e.publishOnPostReplyEvent(ctx)
 
 	// Dump request and response
-	if e.a.settings.DumpLogEnabled {
+	if e.a.settings().DumpLogEnabled {
 		e.a.dumpLog.Dump(ctx)
 	}
 }
@@ -355,8 +524,19 @@ func (e *HTTPEngine) writeReply(ctx *Context) {
 func (e *HTTPEngine) writeOnWire(ctx *Context) {
 	re := ctx.Reply()
 	if _, ok := re.Rdr.(*binaryRender); ok {
-		e.writeBinary(ctx)
-		return
+		// Binary, reader and file reply is not scannable
+		if e.a.respScan != nil {
+			e.a.respScan.Unscanned(ctx, "binary reply")
+		}
+		if _, ok = re.Rdr.(*binaryRender); ok {
+			// binary reply size is not known, only the reply ETag is used
+			if e.checkNotModified(ctx, nil, e.qualifyBinaryGzip(ctx)) {
+				ess.CloseQuietly(re.Rdr.(*binaryRender).Reader)
+				return
+			}
+			e.writeBinary(ctx)
+			return
+		}
 	}
 
 	// Render it
@@ -364,32 +544,52 @@ func (e *HTTPEngine) writeOnWire(ctx *Context) {
 		ctx.Res.WriteHeader(re.Code)
 		return
 	}
+	if e.a.respTranslator != nil {
+		e.a.translateReply(ctx)
+	}
 	re.body = acquireBuffer()
 	if err := re.Rdr.Render(re.body); err != nil {
 		ctx.Log().Error("Response render error: ", err)
 		panic(ErrRenderResponse)
 	}
 
+	// Outbound response scanning for sensitive data
+	if e.a.respScan != nil {
+		e.a.respScan.Inspect(ctx)
+	}
+
 	// Check response qualify for Gzip
-	if e.qualifyGzip(ctx) && re.body.Len() > defaultGzipMinSize {
-		ctx.Res = wrapGzipWriter(ctx.Res)
+	gz := e.qualifyGzip(ctx) && e.qualifyCompress(ctx, int64(re.body.Len()))
+
+	// ETag and conditional request
+	if e.checkNotModified(ctx, re.body.Bytes(), gz) {
+		return
+	}
+
+	// HTTP HEAD, body is suppressed however headers are preserved as GET
+	// response
+	if ctx.Req.Method == ahttp.MethodHead {
+		e.writeHeadReply(ctx)
+		return
+	}
+
+	if gz {
+		ctx.Res = wrapGzipWriter(ctx.Res, e.a.settings().GzipLevel)
 	}
 
 	ctx.Res.WriteHeader(re.Code)
 	var w io.Writer = ctx.Res
 
 	// If response dump log enabled with response body
-	if e.a.settings.DumpLogEnabled && e.a.dumpLog.logResponseBody {
-		resBuf := acquireBuffer()
-		w = io.MultiWriter([]io.Writer{w, resBuf}...)
-		ctx.Set(keyAahResponseBodyBuf, resBuf)
+	if e.a.settings().DumpLogEnabled && e.a.dumpLog.logResponseBody && e.a.dumpLog.IsRequestDumpable(ctx) {
+		w = io.MultiWriter([]io.Writer{w, e.a.dumpLog.CaptureBody(keyAahResponseBodyBuf, ctx)}...)
 	}
 
 	// currently write error on wire is not propagated to error
 	// since we can't do anything after that.
 	// It could be network error, client is gone, etc.
 	if re.isHTML() {
-		if e.a.IsEnvProfile(settings.DefaultEnvProfile) || !e.minifierExists() {
+		if !e.qualifyMinify(ctx) {
 			if _, err := re.body.WriteTo(w); err != nil {
 				ctx.Log().Error(err)
 			}
@@ -401,12 +601,49 @@ func (e *HTTPEngine) writeOnWire(ctx *Context) {
 	}
 }
 
+// writeHeadReply method writes the headers of HTTP HEAD reply. Headers
+// `Content-Length` and `Content-Encoding` are derived through the same
+// minify and gzip path of GET reply.
+func (e *HTTPEngine) writeHeadReply(ctx *Context) {
+	re := ctx.Reply()
+	body := re.body
+	if re.isHTML() && e.qualifyMinify(ctx) {
+		buf := acquireBuffer()
+		defer releaseBuffer(buf)
+		if err := e.a.viewMgr.minifier(re.ContType, buf, bytes.NewReader(re.body.Bytes())); err == nil {
+			body = buf
+		}
+	}
+
+	size := body.Len()
+	if e.qualifyGzip(ctx) && e.qualifyCompress(ctx, int64(re.body.Len())) {
+		gbuf := acquireBuffer()
+		defer releaseBuffer(gbuf)
+		if gw, err := gzip.NewWriterLevel(gbuf, e.a.settings().GzipLevel); err == nil {
+			_, _ = gw.Write(body.Bytes())
+			_ = gw.Close()
+			size = gbuf.Len()
+			ctx.Res.Header().Add(ahttp.HeaderVary, ahttp.HeaderAcceptEncoding)
+			ctx.Res.Header().Add(ahttp.HeaderContentEncoding, gzipContentEncoding)
+		}
+	}
+	ctx.Res.Header().Set(ahttp.HeaderContentLength, strconv.Itoa(size))
+	ctx.Res.WriteHeader(re.Code)
+}
+
 func (e *HTTPEngine) writeBinary(ctx *Context) {
 	re := ctx.Reply()
 
-	// Check response qualify for Gzip
-	if e.qualifyGzip(ctx) {
-		ctx.Res = wrapGzipWriter(ctx.Res)
+	// Check response qualify for Gzip, binary reply size is not known
+	if e.qualifyBinaryGzip(ctx) {
+		ctx.Res = wrapGzipWriter(ctx.Res, e.a.settings().GzipLevel)
+	}
+
+	// File reply is served via 'http.ServeContent', so that byte-range
+	// requests are replied with '206 Partial Content'
+	if br := re.Rdr.(*binaryRender); len(br.Path) > 0 && re.Code == http.StatusOK {
+		e.serveFile(ctx, br.Path)
+		return
 	}
 
 	ctx.Res.WriteHeader(re.Code)
@@ -419,12 +656,66 @@ func (e *HTTPEngine) writeBinary(ctx *Context) {
 	}
 }
 
+// serveFile method writes the file reply via 'http.ServeContent', it honors
+// the request headers 'Range', 'If-Range' and HTTP HEAD.
+func (e *HTTPEngine) serveFile(ctx *Context, file string) {
+	f, err := os.Open(file)
+	if err != nil {
+		ctx.Log().Error("Response write error: ", err)
+		if os.IsNotExist(err) {
+			ctx.Res.WriteHeader(http.StatusNotFound)
+		} else {
+			ctx.Res.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	defer ess.CloseQuietly(f)
+
+	fi, err := f.Stat()
+	if err == nil && fi.IsDir() {
+		err = fmt.Errorf("'%s' is a directory", file)
+	}
+	if err != nil {
+		ctx.Log().Error("Response write error: ", err)
+		ctx.Res.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	http.ServeContent(ctx.Res, ctx.Req.Unwrap(), fi.Name(), fi.ModTime(), f)
+}
+
 func (e *HTTPEngine) minifierExists() bool {
 	return e.a.viewMgr != nil && e.a.viewMgr.minifier != nil
 }
 
+func (e *HTTPEngine) qualifyMinify(ctx *Context) bool {
+	return !ctx.rawResBody && !e.a.IsEnvProfile(settings.DefaultEnvProfile) && e.minifierExists()
+}
+
 func (e *HTTPEngine) qualifyGzip(ctx *Context) bool {
-	return e.a.settings.GzipEnabled && ctx.Req.IsGzipAccepted && ctx.Reply().gzip
+	return e.a.settings().GzipEnabled && ctx.Req.IsGzipAccepted && ctx.Reply().gzip
+}
+
+// qualifyBinaryGzip method returns true if the binary reply qualifies for
+// Gzip. Byte-range request is not compressed, since range applies to the
+// encoded content.
+func (e *HTTPEngine) qualifyBinaryGzip(ctx *Context) bool {
+	return e.qualifyGzip(ctx) && !isRangeRequest(ctx.Req) && e.qualifyCompress(ctx, -1)
+}
+
+// qualifyCompress method returns true if the response qualifies the
+// compression filters `render.gzip.min_size` and `render.gzip.mime_types`,
+// it applies to all the response encodings. Size -1 means unknown.
+func (e *HTTPEngine) qualifyCompress(ctx *Context, size int64) bool {
+	s := e.a.settings()
+	if size >= 0 && size < s.GzipMinSize {
+		return false
+	}
+	ct := ctx.Res.Header().Get(ahttp.HeaderContentType)
+	if len(ct) == 0 {
+		ct = ctx.Reply().ContType
+	}
+	return isCompressibleType(s.GzipMimeTypes, util.OnlyMIME(ct))
 }
 
 func (e *HTTPEngine) releaseContext(ctx *Context) {
@@ -464,6 +755,28 @@ func (e *HTTPEngine) doRedirect(w http.ResponseWriter, r *http.Request) bool {
 	return false
 }
 
+// isCompressibleType method returns true if given MIME type matches any of
+// the types, suffix `/*` matches all subtypes. Empty types matches all.
+func isCompressibleType(types []string, mime string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if strings.HasSuffix(t, "/*") {
+			if strings.HasPrefix(mime, t[:len(t)-1]) {
+				return true
+			}
+		} else if mime == t {
+			return true
+		}
+	}
+	return false
+}
+
+func isRangeRequest(r *ahttp.Request) bool {
+	return len(r.Header.Get(ahttp.HeaderRange)) > 0
+}
+
 // bodyAllowedForStatus reports whether a given response status code
 // permits a body. See RFC 2616, section 4.4.
 //
@@ -8,11 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
+	"aahframe.work/internal/settings"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -167,6 +170,15 @@ func TestHTTPEngineTestRequests(t *testing.T) {
 	assert.Equal(t, "135", resp.Header.Get(ahttp.HeaderContentLength))
 	assert.True(t, strings.HasPrefix(responseBody(resp), `)]}',`))
 
+	// HEAD derived from GET route - /secure-json
+	t.Log("HEAD derived from GET route - /secure-json")
+	resp, err = httpClient.Head(ts.URL + "/secure-json")
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get(ahttp.HeaderContentType))
+	assert.Equal(t, "135", resp.Header.Get(ahttp.HeaderContentLength))
+	assert.Equal(t, "", responseBody(resp))
+
 	// GET Binary bytes - /binary-bytes
 	t.Log("GET Binary bytes - /binary-bytes")
 	resp, err = httpClient.Get(ts.URL + "/binary-bytes")
@@ -205,7 +217,7 @@ func TestHTTPEngineTestRequests(t *testing.T) {
 	resp, err = httpClient.Do(req)
 	assert.Nil(t, err)
 	assert.Equal(t, 200, resp.StatusCode)
-	assert.Equal(t, "GET, OPTIONS", resp.Header.Get(ahttp.HeaderAllow))
+	assert.Equal(t, "GET, HEAD, OPTIONS", resp.Header.Get(ahttp.HeaderAllow))
 	assert.Equal(t, "0", resp.Header.Get(ahttp.HeaderContentLength))
 
 	// POST - Method Not allowed - /binary-bytes
@@ -213,11 +225,244 @@ func TestHTTPEngineTestRequests(t *testing.T) {
 	resp, err = httpClient.Post(ts.URL+"/binary-bytes", ahttp.ContentTypeJSON.String(), strings.NewReader(`{"message":"accept this request"}`))
 	assert.Nil(t, err)
 	assert.Equal(t, 405, resp.StatusCode)
-	assert.Equal(t, "GET, OPTIONS", resp.Header.Get(ahttp.HeaderAllow))
+	assert.Equal(t, "GET, HEAD, OPTIONS", resp.Header.Get(ahttp.HeaderAllow))
 	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get(ahttp.HeaderContentType))
 	assert.True(t, strings.Contains(responseBody(resp), "405 Method Not Allowed"))
 }
 
+func TestHTTPEngineResponseHooks(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	firstByte := make(chan ResponseInfo, 1)
+	complete := make(chan ResponseInfo, 1)
+	he := ts.app.HTTPEngine()
+	he.OnResponseFirstByte(func(ctx *Context, ri *ResponseInfo) {
+		assert.Equal(t, "/get-xml", ctx.Req.Path)
+		firstByte <- *ri
+	})
+	he.OnResponseComplete(func(ctx *Context, ri *ResponseInfo) {
+		complete <- *ri
+	})
+
+	resp, err := new(http.Client).Get(ts.URL + "/get-xml")
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	fri := <-firstByte
+	assert.Equal(t, 200, fri.Status)
+	assert.Equal(t, 0, fri.BytesWritten)
+	assert.True(t, fri.TimeToFirstByte() > 0)
+
+	select {
+	case cri := <-complete:
+		assert.Equal(t, 200, cri.Status)
+		assert.Equal(t, 120, cri.BytesWritten)
+		assert.True(t, cri.Duration >= cri.TimeToFirstByte())
+	case <-time.After(2 * time.Second):
+		t.Error("response complete hook is not called")
+	}
+
+	assert.Equal(t, time.Duration(0), (&ResponseInfo{StartTime: time.Now()}).TimeToFirstByte())
+}
+
+func TestHTTPEngineClientGoneOnCompletedRequest(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	// request timeout context is cancelled on completion of every request
+	ts.app.settingsHolder.Update(func(s *settings.Settings) { s.HTTPRequestTimeout = 5 * time.Second })
+
+	called := make(chan bool, 1)
+	ts.app.HTTPEngine().OnRequest(func(e *Event) {
+		e.Data.(*Context).OnClientGone(func() { called <- true })
+	})
+
+	resp, err := new(http.Client).Get(ts.URL + "/get-xml")
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	select {
+	case <-called:
+		t.Error("client gone callback should not be called on completed request")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHTTPEngineHeadReplyGzip(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+	ts.app.settingsHolder.Update(func(s *settings.Settings) { s.GzipEnabled = true })
+
+	text := strings.Repeat("aah framework HEAD reply, ", 100)
+	reply := func(method string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "http://localhost:8080/large-text", nil)
+		r.Header.Set(ahttp.HeaderAcceptEncoding, "gzip, deflate")
+		ctx := newContext(w, r)
+		ctx.a = ts.app
+		ctx.Reply().Ok().Text(text)
+		ts.app.HTTPEngine().writeOnWire(ctx)
+		ahttp.ReleaseResponseWriter(ctx.Res)
+		return w
+	}
+
+	get, head := reply(ahttp.MethodGet), reply(ahttp.MethodHead)
+	assert.Equal(t, "gzip", get.Header().Get(ahttp.HeaderContentEncoding))
+	assert.Equal(t, "gzip", head.Header().Get(ahttp.HeaderContentEncoding))
+	assert.Equal(t, ahttp.HeaderAcceptEncoding, head.Header().Get(ahttp.HeaderVary))
+	assert.Equal(t, strconv.Itoa(get.Body.Len()), head.Header().Get(ahttp.HeaderContentLength))
+	assert.True(t, get.Body.Len() < len(text))
+	assert.Equal(t, 0, head.Body.Len())
+}
+
+func TestHTTPEngineGzipFilters(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+	ts.app.settingsHolder.Update(func(s *settings.Settings) {
+		s.GzipEnabled = true
+		s.GzipMinSize = 2048
+		s.GzipMimeTypes = settings.DefaultGzipMimeTypes
+	})
+
+	reply := func(ct, text string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/large-text", nil)
+		r.Header.Set(ahttp.HeaderAcceptEncoding, "gzip, deflate")
+		ctx := newContext(w, r)
+		ctx.a = ts.app
+		ctx.Reply().Ok().ContentType(ct).Text(text)
+		ts.app.HTTPEngine().writeOnWire(ctx)
+		ahttp.ReleaseResponseWriter(ctx.Res)
+		return w
+	}
+
+	large := strings.Repeat("aah framework gzip filters, ", 100)
+	assert.Equal(t, "gzip", reply(ahttp.ContentTypePlainText.String(), large).Header().Get(ahttp.HeaderContentEncoding))
+	assert.Equal(t, "", reply(ahttp.ContentTypePlainText.String(), large[:2000]).Header().Get(ahttp.HeaderContentEncoding))
+	assert.Equal(t, "", reply("image/png", large).Header().Get(ahttp.HeaderContentEncoding))
+
+	assert.True(t, isCompressibleType(nil, "video/mp4"))
+	assert.True(t, isCompressibleType([]string{"text/*"}, "text/csv"))
+	assert.True(t, isCompressibleType([]string{"image/svg+xml"}, "image/svg+xml"))
+	assert.False(t, isCompressibleType([]string{"text/*", "application/json"}, "image/jpeg"))
+}
+
+func TestHTTPEngineETag(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+	ts.app.settingsHolder.Update(func(s *settings.Settings) {
+		s.GzipEnabled = false
+		s.ETagEnabled = true
+		s.ETagWeak = false
+	})
+
+	reply := func(method string, hdrs map[string]string, fn func(re *Reply)) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "http://localhost:8080/etag", nil)
+		for k, v := range hdrs {
+			r.Header.Set(k, v)
+		}
+		ctx := newContext(w, r)
+		ctx.a = ts.app
+		fn(ctx.Reply().Ok())
+		ts.app.HTTPEngine().writeOnWire(ctx)
+		ahttp.ReleaseResponseWriter(ctx.Res)
+		return w
+	}
+	text := func(re *Reply) { re.Text("aah framework etag") }
+
+	w := reply(ahttp.MethodGet, nil, text)
+	etag := w.Header().Get(ahttp.HeaderETag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(etag, `"12-`))
+	assert.Equal(t, "aah framework etag", responseBody(w.Result()))
+
+	w = reply(ahttp.MethodGet, map[string]string{ahttp.HeaderIfNoneMatch: `"other", W/` + etag}, text)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get(ahttp.HeaderETag))
+	assert.Equal(t, "", w.Header().Get(ahttp.HeaderContentType))
+	assert.Equal(t, 0, w.Body.Len())
+
+	w = reply(ahttp.MethodHead, map[string]string{ahttp.HeaderIfNoneMatch: etag}, text)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = reply(ahttp.MethodGet, map[string]string{ahttp.HeaderIfNoneMatch: `"other"`}, text)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// reply ETag override
+	w = reply(ahttp.MethodGet, map[string]string{ahttp.HeaderIfNoneMatch: `"v2"`}, func(re *Reply) {
+		re.ETag("v2").Text("aah framework etag")
+	})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"v2"`, w.Header().Get(ahttp.HeaderETag))
+
+	w = reply(ahttp.MethodGet, nil, func(re *Reply) { re.ETag("").Text("no etag") })
+	assert.Equal(t, "", w.Header().Get(ahttp.HeaderETag))
+
+	// If-Modified-Since with reply Last-Modified
+	lastModified := time.Date(2018, 6, 1, 10, 0, 0, 0, time.UTC)
+	lm := func(re *Reply) {
+		re.ETag("").Header(ahttp.HeaderLastModified, lastModified.Format(http.TimeFormat)).Text("modified")
+	}
+	w = reply(ahttp.MethodGet, map[string]string{ahttp.HeaderIfModifiedSince: lastModified.Format(http.TimeFormat)}, lm)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	w = reply(ahttp.MethodGet, map[string]string{ahttp.HeaderIfModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat)}, lm)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// weak and encoded ETag
+	assert.Equal(t, `W/"abc"`, makeETag("abc", true))
+	assert.Equal(t, `"abc-gzip"`, encodedETag(`"abc"`, gzipContentEncoding))
+	assert.Equal(t, `W/"abc"`, encodedETag(`W/"abc"`, gzipContentEncoding))
+}
+
+func TestHTTPEngineFileReplyRange(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+	file := filepath.Join(testdataBaseDir(), "webapp1", "static", "css", "aah.css")
+
+	reply := func(method, rng string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "http://localhost:8080/download", nil)
+		r.Header.Set(ahttp.HeaderAcceptEncoding, "gzip")
+		if len(rng) > 0 {
+			r.Header.Set(ahttp.HeaderRange, rng)
+		}
+		ctx := newContext(w, r)
+		ctx.a = ts.app
+		ctx.Reply().Ok().File(file)
+		ts.app.HTTPEngine().writeOnWire(ctx)
+		ahttp.ReleaseResponseWriter(ctx.Res)
+		return w
+	}
+
+	w := reply(ahttp.MethodGet, "bytes=3-9")
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes 3-9/700", w.Header().Get(ahttp.HeaderContentRange))
+	assert.Equal(t, "", w.Header().Get(ahttp.HeaderContentEncoding))
+	assert.Equal(t, "Minimal", w.Body.String())
+
+	w = reply(ahttp.MethodGet, "bytes=0-1,3-9")
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get(ahttp.HeaderContentType), "multipart/byteranges; boundary="))
+	assert.True(t, strings.Contains(w.Body.String(), "Content-Range: bytes 3-9/700"))
+
+	w = reply(ahttp.MethodGet, "bytes=800-900")
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+
+	w = reply(ahttp.MethodHead, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bytes", w.Header().Get(ahttp.HeaderAcceptRanges))
+	assert.Equal(t, 0, w.Body.Len())
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/download", nil))
+	ctx.a = ts.app
+	ctx.Reply().Ok().File("not-exists.pdf")
+	ts.app.HTTPEngine().writeOnWire(ctx)
+	assert.Equal(t, http.StatusNotFound, ctx.Res.Status())
+}
+
 func TestServerRedirect(t *testing.T) {
 	a := newApp()
 	a.cfg = config.NewEmpty()
@@ -0,0 +1,233 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"aahframe.work/discovery"
+	"aahframe.work/internal/settings"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// HTTPClient method returns the aah provided HTTP client for outbound calls.
+// Its transport is configured via `http_client { ... }` and it records
+// per-host metrics, see `Application.HTTPClientMetrics`.
+//
+// Request URL host which matches the discovery service name
+// (e.g. `http://orders/api/v1/orders`) is resolved to service endpoint,
+// see `Application.DiscoveryManager`.
+//
+//	http_client {
+//	  timeout = "30s"
+//	  max_idle_conns = 100
+//	  max_idle_conns_per_host = 10
+//	  max_conns_per_host = 0
+//	  idle_conn_timeout = "90s"
+//	  dial_timeout = "30s"
+//	  keep_alive = "30s"
+//	  tls_handshake_timeout = "10s"
+//	  response_header_timeout = "0s"
+//	}
+func (a *Application) HTTPClient() *http.Client {
+	return a.httpClient
+}
+
+// HTTPClientMetrics method returns the snapshot of outbound HTTP client
+// metrics by host.
+func (a *Application) HTTPClientMetrics() map[string]HTTPClientMetric {
+	if a.httpClient == nil {
+		return nil
+	}
+	return a.httpClient.Transport.(*metricsTransport).snapshot()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// HTTP client metric
+//______________________________________________________________________________
+
+// HTTPClientMetric holds the outbound request metrics of single host.
+type HTTPClientMetric struct {
+	Host         string
+	Requests     int64
+	Errors       int64
+	ServerErrors int64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// AvgLatency method returns the average latency of host requests.
+func (m HTTPClientMetric) AvgLatency() time.Duration {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.TotalLatency / time.Duration(m.Requests)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) initHTTPClient() error {
+	keyPrefix := "http_client"
+	cfg := a.Config()
+
+	durations := map[string]time.Duration{}
+	for _, k := range []struct {
+		key string
+		def time.Duration
+	}{
+		{"timeout", 30 * time.Second}, {"idle_conn_timeout", 90 * time.Second}, {"dial_timeout", 30 * time.Second},
+		{"keep_alive", 30 * time.Second}, {"tls_handshake_timeout", 10 * time.Second}, {"response_header_timeout", 0},
+	} {
+		d, err := settings.ParseDuration(cfg, keyPrefix+"."+k.key, k.def)
+		if err != nil {
+			return err
+		}
+		durations[k.key] = d
+	}
+
+	counts := map[string]int{}
+	for _, k := range []struct {
+		key string
+		def int
+	}{
+		{"max_idle_conns", 100}, {"max_idle_conns_per_host", http.DefaultMaxIdleConnsPerHost},
+		{"max_conns_per_host", 0},
+	} {
+		v := cfg.IntDefault(keyPrefix+"."+k.key, k.def)
+		if v < 0 {
+			return fmt.Errorf("'%s.%s' value must not be negative", keyPrefix, k.key)
+		}
+		counts[k.key] = v
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   durations["dial_timeout"],
+		KeepAlive: durations["keep_alive"],
+	}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          counts["max_idle_conns"],
+		MaxIdleConnsPerHost:   counts["max_idle_conns_per_host"],
+		MaxConnsPerHost:       counts["max_conns_per_host"],
+		IdleConnTimeout:       durations["idle_conn_timeout"],
+		TLSHandshakeTimeout:   durations["tls_handshake_timeout"],
+		ResponseHeaderTimeout: durations["response_header_timeout"],
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	a.httpClient = &http.Client{
+		Transport: &metricsTransport{
+			transport: transport,
+			discovery: a.discovery,
+			metrics:   make(map[string]*HTTPClientMetric),
+		},
+		Timeout: durations["timeout"],
+	}
+
+	// upstream client of 'Context.Proxy' streams the response body, so
+	// overall timeout is not applied and redirects are not followed
+	a.upstreamClient = &http.Client{
+		Transport: a.httpClient.Transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return nil
+}
+
+// metricsTransport records per-host latency and error metrics of
+// outbound requests and resolves discovery service targets.
+type metricsTransport struct {
+	sync.Mutex
+	transport *http.Transport
+	discovery *discovery.Manager
+	metrics   map[string]*HTTPClientMetric
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var ep *discovery.Endpoint
+	if t.discovery != nil {
+		if s := t.discovery.Service(req.URL.Hostname()); s != nil {
+			var err error
+			if ep, err = s.Next(); err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.URL.Scheme, req.URL.Host, req.Host = ep.Scheme, ep.Address(), ep.Address()
+		}
+	}
+
+	start := time.Now()
+	res, err := t.transport.RoundTrip(req)
+	t.record(req.URL.Host, time.Since(start), res, err)
+	if ep != nil {
+		if err != nil {
+			ep.Done()
+		} else {
+			res.Body = &endpointBody{ReadCloser: res.Body, ep: ep}
+		}
+	}
+	return res, err
+}
+
+// CloseIdleConnections method closes the idle connections of transport,
+// it's called by `http.Client.CloseIdleConnections`.
+func (t *metricsTransport) CloseIdleConnections() {
+	t.transport.CloseIdleConnections()
+}
+
+func (t *metricsTransport) record(host string, latency time.Duration, res *http.Response, err error) {
+	t.Lock()
+	defer t.Unlock()
+	m, found := t.metrics[host]
+	if !found {
+		m = &HTTPClientMetric{Host: host}
+		t.metrics[host] = m
+	}
+
+	m.Requests++
+	m.TotalLatency += latency
+	if latency > m.MaxLatency {
+		m.MaxLatency = latency
+	}
+	if err != nil {
+		m.Errors++
+	} else if res.StatusCode >= http.StatusInternalServerError {
+		m.ServerErrors++
+	}
+}
+
+// endpointBody releases the discovery endpoint usage on response body close.
+type endpointBody struct {
+	io.ReadCloser
+	ep   *discovery.Endpoint
+	once sync.Once
+}
+
+func (b *endpointBody) Close() error {
+	b.once.Do(b.ep.Done)
+	return b.ReadCloser.Close()
+}
+
+func (t *metricsTransport) snapshot() map[string]HTTPClientMetric {
+	t.Lock()
+	defer t.Unlock()
+	result := make(map[string]HTTPClientMetric, len(t.metrics))
+	for host, m := range t.metrics {
+		result[host] = *m
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"aahframe.work/discovery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPClientConfigAndMetrics(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	assert.Nil(t, setTestConfig(a, `
+	http_client {
+	  timeout = "5s"
+	  max_idle_conns = 50
+	  max_idle_conns_per_host = 5
+	  max_conns_per_host = 20
+	  tls_handshake_timeout = "3s"
+	}
+	`))
+	err := a.initHTTPClient()
+	assert.Nil(t, err)
+
+	client := a.HTTPClient()
+	assert.Equal(t, 5*time.Second, client.Timeout)
+	transport := client.Transport.(*metricsTransport).transport
+	assert.Equal(t, 50, transport.MaxIdleConns)
+	assert.Equal(t, 5, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 20, transport.MaxConnsPerHost)
+	assert.Equal(t, 3*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer upstream.Close()
+
+	for _, p := range []string{"/", "/ok", "/error"} {
+		resp, err := client.Get(upstream.URL + p)
+		assert.Nil(t, err)
+		resp.Body.Close()
+	}
+	_, err = client.Get("http://127.0.0.1:1/unreachable")
+	assert.NotNil(t, err)
+
+	u, _ := url.Parse(upstream.URL)
+	metrics := a.HTTPClientMetrics()
+	m := metrics[u.Host]
+	assert.Equal(t, int64(3), m.Requests)
+	assert.Equal(t, int64(1), m.ServerErrors)
+	assert.Equal(t, int64(0), m.Errors)
+	assert.True(t, m.MaxLatency > 0)
+	assert.True(t, m.AvgLatency() <= m.MaxLatency)
+	assert.Equal(t, int64(1), metrics["127.0.0.1:1"].Errors)
+	assert.Equal(t, time.Duration(0), HTTPClientMetric{}.AvgLatency())
+}
+
+func TestHTTPClientDiscovery(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+	port, _ := strconv.Atoi(u.Port())
+
+	assert.Nil(t, setTestConfig(a, `
+	discovery {
+	  services {
+	    orders {
+	      srv = "_orders._tcp.service.consul"
+	      strategy = "least_conn"
+	    }
+	  }
+	}
+	`))
+	a.discovery, _ = discovery.New(a.Config(), a.Log())
+	a.DiscoveryManager().Service("orders").SetLookupSRV(func(_ context.Context, name string) ([]*net.SRV, error) {
+		return []*net.SRV{{Target: "127.0.0.1.", Port: uint16(port)}}, nil
+	})
+	err := a.initHTTPClient()
+	assert.Nil(t, err)
+
+	resp, err := a.HTTPClient().Get("http://orders/api/v1/orders")
+	assert.Nil(t, err)
+	assert.Equal(t, "/api/v1/orders", responseBody(resp))
+
+	ep := a.DiscoveryManager().Service("orders").Endpoints()[0]
+	assert.Equal(t, 0, ep.ActiveConns())
+	assert.Equal(t, int64(1), a.HTTPClientMetrics()[u.Host].Requests)
+}
+
+func TestHTTPClientConfigErrors(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	for _, c := range []struct{ cfg, err string }{
+		{`http_client { dial_timeout = "30"; }`, "'http_client.dial_timeout' value is not a valid time unit"},
+		{`http_client { max_idle_conns = -1; }`, "'http_client.max_idle_conns' value must not be negative"},
+	} {
+		assert.Nil(t, setTestConfig(a, c.cfg))
+		err := a.initHTTPClient()
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
@@ -151,6 +151,22 @@ notExists:
 	return ""
 }
 
+// Resolve returns the language tag of message store locale for given locale,
+// for e.g.: `en-US`. It follows the same fallback order of `Lookup`, if none
+// of them exists in the message store then it returns empty string.
+func (s *I18n) Resolve(locale *ahttp.Locale) string {
+	candidates := []string{s.DefaultLocale}
+	if locale != nil {
+		candidates = []string{locale.String(), locale.Language, s.DefaultLocale}
+	}
+	for _, c := range candidates {
+		if s.findStoreByLocale(c) != nil {
+			return languageTag(c)
+		}
+	}
+	return ""
+}
+
 // Locales returns all the loaded locales from message store
 func (s *I18n) Locales() []string {
 	var locales []string
@@ -215,3 +231,13 @@ func (s *I18n) isDir(name string) bool {
 	}
 	return fi.IsDir()
 }
+
+// languageTag method returns the locale in language tag case, for e.g.:
+// `en-us` => `en-US`.
+func languageTag(locale string) string {
+	parts := strings.SplitN(locale, "-", 2)
+	if len(parts) == 2 {
+		return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+	}
+	return strings.ToLower(locale)
+}
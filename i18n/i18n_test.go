@@ -161,3 +161,18 @@ func TestMsgRetriveNotFoundLocale(t *testing.T) {
 func newI18n() *I18n {
 	return New()
 }
+
+func TestResolveLocale(t *testing.T) {
+	wd, _ := os.Getwd()
+	store := newI18n()
+	_ = store.Load(filepath.Join(wd, "testdata"))
+
+	store.DefaultLocale = "en"
+	assert.Equal(t, "en-US", store.Resolve(ahttp.NewLocale("en-us")))
+	assert.Equal(t, "fr", store.Resolve(ahttp.NewLocale("fr-BE")))
+	assert.Equal(t, "en", store.Resolve(ahttp.NewLocale("ja-JP")))
+	assert.Equal(t, "en", store.Resolve(nil))
+
+	store.DefaultLocale = "ja"
+	assert.Equal(t, "", store.Resolve(ahttp.NewLocale("de")))
+}
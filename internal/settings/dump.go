@@ -0,0 +1,259 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aahframe.work/config"
+)
+
+// MaskedValue is used in place of secret config values on dump and diff.
+const MaskedValue = "******"
+
+// secretKeyNames are the config key name parts considered as secret value.
+var secretKeyNames = []string{"password", "secret", "token", "sign_key",
+	"enc_key", "private_key", "api_key", "credential"}
+
+// ProfileDiff holds the config key which differs between two env profiles.
+// Value is nil if key does not exists in that profile.
+type ProfileDiff struct {
+	Key string
+	A   interface{}
+	B   interface{}
+}
+
+// String method is stringer interface.
+func (d ProfileDiff) String() string {
+	return fmt.Sprintf("%s: %v => %v", d.Key, d.A, d.B)
+}
+
+// Dump method writes the fully-resolved effective configuration of active
+// env profile into given writer. Values are resolved after profile merge and
+// environment variable overrides, secret values are masked. Supported
+// formats are `json` and `hocon` (aah config syntax).
+func (s *Settings) Dump(w io.Writer, format string) error {
+	values, err := s.effectiveValues(s.EnvProfile)
+	if err != nil {
+		return err
+	}
+	s.maskSecrets(values)
+
+	switch strings.ToLower(format) {
+	case "json":
+		b, err := json.MarshalIndent(unflatten(values), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(b, '\n'))
+		return err
+	case "hocon", "conf":
+		buf := new(bytes.Buffer)
+		writeHOCON(buf, unflatten(values), 0)
+		_, err = w.Write(buf.Bytes())
+		return err
+	}
+	return fmt.Errorf("settings: unsupported dump format '%s', supported formats are json, hocon", format)
+}
+
+// DiffProfiles method returns the config keys which differs between the
+// given env profiles `a` and `b` sorted by key name. Values are resolved
+// after profile merge, secret values are masked.
+func (s *Settings) DiffProfiles(a, b string) ([]ProfileDiff, error) {
+	av, err := s.effectiveValues(a)
+	if err != nil {
+		return nil, err
+	}
+	bv, err := s.effectiveValues(b)
+	if err != nil {
+		return nil, err
+	}
+	s.maskSecrets(av)
+	s.maskSecrets(bv)
+	return diffValues(av, bv), nil
+}
+
+// DiffConfig method returns the config keys of given config which value
+// differs from current config for active env profile sorted by key name, for
+// e.g.: rejected hot-reload config. Keys not defined in given config are not
+// compared, since current config has the values applied at runtime.
+// Secret values are masked.
+func (s *Settings) DiffConfig(cfg *config.Config) ([]ProfileDiff, error) {
+	av, err := s.effectiveValues(s.EnvProfile)
+	if err != nil {
+		return nil, err
+	}
+	o := &Settings{cfg: cfg, EnvProfile: s.EnvProfile, secretKeys: s.secretKeys}
+	bv, err := o.effectiveValues(o.EnvProfile)
+	if err != nil {
+		return nil, err
+	}
+	for k := range av {
+		if _, found := bv[k]; !found {
+			delete(av, k)
+		}
+	}
+	s.maskSecrets(av)
+	s.maskSecrets(bv)
+	return diffValues(av, bv), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func diffValues(av, bv map[string]interface{}) []ProfileDiff {
+	keys := make(map[string]bool)
+	for k := range av {
+		keys[k] = true
+	}
+	for k := range bv {
+		keys[k] = true
+	}
+
+	var diffs []ProfileDiff
+	for k := range keys {
+		if !reflect.DeepEqual(av[k], bv[k]) {
+			diffs = append(diffs, ProfileDiff{Key: k, A: av[k], B: bv[k]})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
+
+// effectiveValues method returns the flattened config values of given env
+// profile merged on top of base config.
+func (s *Settings) effectiveValues(profile string) (map[string]interface{}, error) {
+	if s.cfg == nil {
+		return nil, errors.New("settings: config is not loaded")
+	}
+	profile = strings.TrimPrefix(profile, ProfilePrefix)
+	if !s.cfg.HasProfile(ProfilePrefix + profile) {
+		return nil, fmt.Errorf("settings: profile doesn't exists: %s", profile)
+	}
+
+	d := json.NewDecoder(strings.NewReader(s.cfg.ToJSON()))
+	d.UseNumber()
+	var root map[string]interface{}
+	if err := d.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	var profileValues map[string]interface{}
+	if env, ok := root["env"].(map[string]interface{}); ok {
+		profileValues, _ = env[profile].(map[string]interface{})
+		for k, v := range env {
+			if _, ok := v.(map[string]interface{}); !ok { // for e.g.: env.active
+				values["env."+k] = v
+			}
+		}
+	}
+	delete(root, "env")
+	flatten("", root, values)
+	flatten("", profileValues, values)
+	return values, nil
+}
+
+func flatten(prefix string, m map[string]interface{}, values map[string]interface{}) {
+	for k, v := range m {
+		if sm, ok := v.(map[string]interface{}); ok {
+			flatten(prefix+k+".", sm, values)
+			continue
+		}
+		values[prefix+k] = v
+	}
+}
+
+func unflatten(values map[string]interface{}) map[string]interface{} {
+	root := make(map[string]interface{})
+	for k, v := range values {
+		parts := strings.Split(k, ".")
+		current := root
+		for _, p := range parts[:len(parts)-1] {
+			next, ok := current[p].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[p] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = v
+	}
+	return root
+}
+
+// maskSecrets method masks the values of secret key names, PII tagged key
+// names `security.pii.fields` and resolved secret values, refer to
+// `SecretResolver`.
+func (s *Settings) maskSecrets(values map[string]interface{}) {
+	for k := range values {
+		if s.IsSecretKey(k) {
+			values[k] = MaskedValue
+		}
+	}
+}
+
+// IsSecretKey method returns true if value of given config key is masked
+// on dump, see `Dump`.
+func (s *Settings) IsSecretKey(key string) bool {
+	if s.secretKeys[key] {
+		return true
+	}
+	name := strings.ToLower(key[strings.LastIndex(key, ".")+1:])
+	return containsAny(name, secretKeyNames) || containsAny(name, s.PIIFields)
+}
+
+func containsAny(name string, parts []string) bool {
+	for _, p := range parts {
+		if strings.Contains(name, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeHOCON(buf *bytes.Buffer, m map[string]interface{}, depth int) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	indent := strings.Repeat("  ", depth)
+	for _, k := range keys {
+		if sm, ok := m[k].(map[string]interface{}); ok {
+			fmt.Fprintf(buf, "%s%s {\n", indent, k)
+			writeHOCON(buf, sm, depth+1)
+			fmt.Fprintf(buf, "%s}\n", indent)
+			continue
+		}
+		fmt.Fprintf(buf, "%s%s = %s\n", indent, k, hoconValue(m[k]))
+	}
+}
+
+func hoconValue(v interface{}) string {
+	switch tv := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(tv)
+	case []interface{}:
+		items := make([]string, 0, len(tv))
+		for _, i := range tv {
+			items = append(items, hoconValue(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", v)
+}
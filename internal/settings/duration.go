@@ -0,0 +1,92 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package settings
+
+import (
+	"fmt"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/internal/util"
+)
+
+// TimeUnits are the supported time unit suffixes of duration config values,
+// "ms = milliseconds", "s = seconds", "m = minutes" and "h = hours".
+var TimeUnits = []string{"ms", "s", "m", "h"}
+
+// Timeouts interface provides the parsed server timeout values from config
+// `server.timeout.*`, `Settings` implements it.
+type Timeouts interface {
+	// ReadTimeout is mapped to `http.Server.ReadTimeout`.
+	ReadTimeout() time.Duration
+
+	// ReadHeaderTimeout is mapped to `http.Server.ReadHeaderTimeout`.
+	ReadHeaderTimeout() time.Duration
+
+	// WriteTimeout is mapped to `http.Server.WriteTimeout`.
+	WriteTimeout() time.Duration
+
+	// IdleTimeout is mapped to `http.Server.IdleTimeout`.
+	IdleTimeout() time.Duration
+
+	// RequestTimeout is the request context deadline.
+	RequestTimeout() time.Duration
+
+	// GraceTimeout is the server graceful shutdown timeout.
+	GraceTimeout() time.Duration
+}
+
+var _ Timeouts = (*Settings)(nil)
+
+// ReadTimeout method returns the value of `server.timeout.read`.
+func (s *Settings) ReadTimeout() time.Duration {
+	return s.HTTPReadTimeout
+}
+
+// ReadHeaderTimeout method returns the value of `server.timeout.read_header`.
+func (s *Settings) ReadHeaderTimeout() time.Duration {
+	return s.HTTPReadHdrTimeout
+}
+
+// WriteTimeout method returns the value of `server.timeout.write`.
+func (s *Settings) WriteTimeout() time.Duration {
+	return s.HTTPWriteTimeout
+}
+
+// IdleTimeout method returns the value of `server.timeout.idle`.
+func (s *Settings) IdleTimeout() time.Duration {
+	return s.HTTPIdleTimeout
+}
+
+// RequestTimeout method returns the value of `server.timeout.request`.
+func (s *Settings) RequestTimeout() time.Duration {
+	return s.HTTPRequestTimeout
+}
+
+// GraceTimeout method returns the value of `server.timeout.grace_shutdown`.
+func (s *Settings) GraceTimeout() time.Duration {
+	return s.ShutdownGraceTimeout
+}
+
+// ParseDuration method parses the duration value of given config key, it
+// accepts the `TimeUnits` and value must not be negative. Default value is
+// returned if key not exists.
+func ParseDuration(cfg *config.Config, key string, defaultValue time.Duration) (time.Duration, error) {
+	v, found := cfg.String(key)
+	if !found {
+		return defaultValue, nil
+	}
+	if !util.IsValidTimeUnit(v, TimeUnits...) {
+		return 0, fmt.Errorf("'%s' value is not a valid time unit", key)
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("'%s': %s", key, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("'%s' value must not be negative", key)
+	}
+	return d, nil
+}
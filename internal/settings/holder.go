@@ -0,0 +1,75 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package settings
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"aahframe.work/config"
+)
+
+// NewHolder method creates the settings holder with given initial snapshot.
+func NewHolder(s *Settings) *Holder {
+	h := &Holder{}
+	h.v.Store(s)
+	return h
+}
+
+// Holder holds the application settings as immutable snapshot. `Refresh` and
+// `Update` build the new snapshot from copy of current one and swap it
+// atomically, so readers always see the consistent settings without locks
+// during hot-reload. Snapshot returned by `Load` must not be modified.
+type Holder struct {
+	mu             sync.Mutex
+	v              atomic.Pointer[Settings]
+	onConfigChange []func()
+}
+
+// Load method returns the current settings snapshot.
+func (h *Holder) Load() *Settings {
+	return h.v.Load()
+}
+
+// Refresh method parses/infers the given config values into new snapshot,
+// it's swapped only if all the values are valid. Otherwise current snapshot
+// stays in use.
+func (h *Holder) Refresh(cfg *config.Config) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.Load().clone()
+	if err := s.Refresh(cfg); err != nil {
+		return err
+	}
+	h.v.Store(s)
+	return nil
+}
+
+// Update method calls given func with copy of current snapshot and swaps it.
+func (h *Holder) Update(fn func(s *Settings)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.Load().clone()
+	fn(s)
+	h.v.Store(s)
+}
+
+// OnConfigChange method adds the callback func, it's called after the
+// application config is reloaded and reinitialized successfully at runtime.
+func (h *Holder) OnConfigChange(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onConfigChange = append(h.onConfigChange, fn)
+}
+
+// PublishConfigChange method calls the config change callbacks.
+func (h *Holder) PublishConfigChange() {
+	h.mu.Lock()
+	callbacks := append([]func(){}, h.onConfigChange...)
+	h.mu.Unlock()
+	for _, fn := range callbacks {
+		fn()
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package settings
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+)
+
+// MergeProviderConfig method loads the config from `Settings.ConfigProvider`
+// and merges it on top of given config. Precedence order is provider value
+// and then local config files.
+func (s *Settings) MergeProviderConfig(cfg *config.Config) error {
+	if s.ConfigProvider == nil {
+		return nil
+	}
+	pcfg, err := s.ConfigProvider.Load()
+	if err != nil {
+		return fmt.Errorf("config provider '%s': %s", s.ConfigProvider.Name(), err)
+	}
+	if err = cfg.Merge(pcfg); err != nil {
+		return fmt.Errorf("config provider '%s': %s", s.ConfigProvider.Name(), err)
+	}
+	return nil
+}
+
+// ProviderWatcher watches the config provider for changes and calls the
+// change func. Provider is watched natively if it implements
+// `config.WatchProvider` otherwise it's polled on the interval and compared
+// with last loaded config values.
+type ProviderWatcher struct {
+	provider config.Provider
+	interval time.Duration
+	onChange func()
+	last     string
+	stop     chan struct{}
+	once     sync.Once
+}
+
+// NewProviderWatcher method creates the config provider watcher.
+func NewProviderWatcher(p config.Provider, interval time.Duration, onChange func()) *ProviderWatcher {
+	w := &ProviderWatcher{
+		provider: p,
+		interval: interval,
+		onChange: onChange,
+		stop:     make(chan struct{}),
+	}
+	if cfg, err := p.Load(); err == nil {
+		w.last = cfg.ToJSON()
+	}
+	return w
+}
+
+// Start method starts watching the config provider, it blocks until the
+// `ProviderWatcher.Stop` is called.
+func (w *ProviderWatcher) Start() {
+	if wp, ok := w.provider.(config.WatchProvider); ok {
+		if err := wp.Watch(w.stop, w.onChange); err != nil {
+			log.Errorf("Config provider '%s' watch failed, fallback to polling: %s", w.provider.Name(), err)
+		} else {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Check method loads the config from provider and calls the change func if
+// config values are changed since last load.
+func (w *ProviderWatcher) Check() {
+	cfg, err := w.provider.Load()
+	if err != nil {
+		log.Warnf("Config provider '%s' load failed: %s", w.provider.Name(), err)
+		return
+	}
+	if current := cfg.ToJSON(); current != w.last {
+		w.last = current
+		w.onChange()
+	}
+}
+
+// Stop method stops the config provider watcher.
+func (w *ProviderWatcher) Stop() {
+	w.once.Do(func() { close(w.stop) })
+}
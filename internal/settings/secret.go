@@ -0,0 +1,195 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package settings
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aahframe.work/security/acrypto"
+)
+
+const (
+	encPrefix           = "ENC("
+	encSuffix           = ")"
+	secretKeyID         = "aah"
+	defaultSecretKeyEnv = "AAH_SECRETS_KEY"
+)
+
+// SecretResolver interface is used to decrypt or resolve the secret config
+// values transparently during `Settings.Refresh`. For e.g.: `ENC(...)`
+// encrypted values, `vault://path` references, etc.
+type SecretResolver interface {
+	// Supports method returns true if given config value is handled by the
+	// resolver otherwise false.
+	Supports(value string) bool
+
+	// Resolve method returns the decrypted or resolved value.
+	Resolve(value string) (string, error)
+}
+
+// AddSecretResolver method adds the secret resolver, resolvers are tried in
+// the order they are added.
+func (s *Settings) AddSecretResolver(r SecretResolver) {
+	s.SecretResolvers = append(s.SecretResolvers, r)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// AES-GCM Secret Resolver
+//___________________________________
+
+var _ SecretResolver = (*AESGCMResolver)(nil)
+
+// AESGCMResolver resolves the `ENC(...)` config values encrypted with
+// AES-256-GCM. Use `AESGCMResolver.Encrypt` to create the encrypted value.
+type AESGCMResolver struct {
+	aead *acrypto.AEAD
+}
+
+// NewAESGCMResolver method creates the AES-GCM secret resolver for given
+// 32 bytes key.
+func NewAESGCMResolver(key []byte) (*AESGCMResolver, error) {
+	aead, err := acrypto.NewAEAD(acrypto.AlgAES256GCM, secretKeyID, key)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMResolver{aead: aead}, nil
+}
+
+// NewAESGCMFileResolver method creates the AES-GCM secret resolver with key
+// from given file. Key is either raw 32 bytes or hex or base64 encoded.
+func NewAESGCMFileResolver(keyFile string) (*AESGCMResolver, error) {
+	b, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := decodeSecretKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", keyFile, err)
+	}
+	return NewAESGCMResolver(key)
+}
+
+// NewAESGCMEnvResolver method creates the AES-GCM secret resolver with key
+// from given environment variable. Key is either hex or base64 encoded.
+func NewAESGCMEnvResolver(envName string) (*AESGCMResolver, error) {
+	v, found := os.LookupEnv(envName)
+	if !found {
+		return nil, fmt.Errorf("environment variable '%s' does not exists", envName)
+	}
+	key, err := decodeSecretKey([]byte(v))
+	if err != nil {
+		return nil, fmt.Errorf("environment variable '%s': %s", envName, err)
+	}
+	return NewAESGCMResolver(key)
+}
+
+// Supports method returns true if value is `ENC(...)`.
+func (r *AESGCMResolver) Supports(value string) bool {
+	return isEncValue(value)
+}
+
+// Resolve method decrypts the `ENC(...)` value.
+func (r *AESGCMResolver) Resolve(value string) (string, error) {
+	return r.aead.DecryptString(value[len(encPrefix) : len(value)-len(encSuffix)])
+}
+
+// Encrypt method encrypts the given text and returns `ENC(...)` value to
+// use in the config.
+func (r *AESGCMResolver) Encrypt(text string) (string, error) {
+	v, err := r.aead.EncryptString(text)
+	if err != nil {
+		return "", err
+	}
+	return encPrefix + v + encSuffix, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// resolveSecrets method resolves all the secret config values using the
+// added resolvers and built-in AES-GCM resolver configured via
+//
+//	runtime.secrets {
+//	  key_file = "config/secrets.key"
+//	  key_env = "AAH_SECRETS_KEY"
+//	}
+func (s *Settings) resolveSecrets() error {
+	resolvers := append([]SecretResolver{}, s.SecretResolvers...)
+	if keyFile := s.cfg.StringDefault("runtime.secrets.key_file", ""); len(keyFile) > 0 {
+		if !filepath.IsAbs(keyFile) {
+			keyFile = filepath.Join(s.BaseDir, keyFile)
+		}
+		r, err := NewAESGCMFileResolver(keyFile)
+		if err != nil {
+			return fmt.Errorf("'runtime.secrets.key_file': %s", err)
+		}
+		resolvers = append(resolvers, r)
+	}
+	keyEnv := s.cfg.StringDefault("runtime.secrets.key_env", defaultSecretKeyEnv)
+	if _, found := os.LookupEnv(keyEnv); found {
+		r, err := NewAESGCMEnvResolver(keyEnv)
+		if err != nil {
+			return fmt.Errorf("'runtime.secrets.key_env': %s", err)
+		}
+		resolvers = append(resolvers, r)
+	}
+
+	var errs Errors
+	s.secretKeys = make(map[string]bool)
+	_ = s.cfg.ResolveStrings(func(key, value string) (string, error) {
+		for _, r := range resolvers {
+			if r.Supports(value) {
+				v, err := r.Resolve(value)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("'%s' unable to resolve secret value: %s", key, err))
+					return value, nil
+				}
+				// profile key is recorded without profile prefix, e.g.:
+				// `env.prod.datasource.password` => `datasource.password`
+				s.secretKeys[key] = true
+				if parts := strings.SplitN(key, ".", 3); len(parts) == 3 && parts[0]+"." == ProfilePrefix {
+					s.secretKeys[parts[2]] = true
+				}
+				return v, nil
+			}
+		}
+		if isEncValue(value) {
+			errs = append(errs, fmt.Errorf("'%s' value is encrypted, however secret key is not configured", key))
+		}
+		return value, nil
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func isEncValue(value string) bool {
+	return strings.HasPrefix(value, encPrefix) && strings.HasSuffix(value, encSuffix)
+}
+
+func decodeSecretKey(b []byte) ([]byte, error) {
+	if len(b) == 32 {
+		return b, nil
+	}
+	s := strings.TrimSpace(string(b))
+	if k, err := hex.DecodeString(s); err == nil && len(k) == 32 {
+		return k, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if k, err := enc.DecodeString(s); err == nil && len(k) == 32 {
+			return k, nil
+		}
+	}
+	return nil, errors.New("invalid secret key, it must be 32 bytes raw or hex or base64 encoded")
+}
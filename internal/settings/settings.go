@@ -5,9 +5,14 @@
 package settings
 
 import (
+	"crypto/tls"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,6 +21,7 @@ import (
 	"aahframe.work/essentials"
 	"aahframe.work/internal/util"
 	"aahframe.work/log"
+	"golang.org/x/crypto/acme"
 	"golang.org/x/crypto/acme/autocert"
 )
 
@@ -25,8 +31,18 @@ const (
 	DefaultHTTPPort         = "8080"
 	DefaultSecureJSONPrefix = ")]}',\n"
 	ProfilePrefix           = "env."
+
+	// Standard frame type MTU size is 1500 bytes so 1400 bytes would make sense
+	// to Gzip by default. Read: https://en.wikipedia.org/wiki/Maximum_transmission_unit
+	DefaultGzipMinSize = 1400
 )
 
+// DefaultGzipMimeTypes is the compressible response content types, used if
+// `render.gzip.mime_types` is not configured.
+var DefaultGzipMimeTypes = []string{"text/*", "application/json", "application/javascript",
+	"application/xml", "application/rss+xml", "application/atom+xml", "application/problem+json",
+	"application/hal+json", "application/vnd.api+json", "image/svg+xml"}
+
 // Settings represents parsed and inferred config values for the application.
 type Settings struct {
 	PhysicalPathMode       bool
@@ -36,6 +52,8 @@ type Settings struct {
 	SSLEnabled             bool
 	LetsEncryptEnabled     bool
 	GzipEnabled            bool
+	ETagEnabled            bool
+	ETagWeak               bool
 	SecureHeadersEnabled   bool
 	AccessLogEnabled       bool
 	StaticAccessLogEnabled bool
@@ -43,10 +61,29 @@ type Settings struct {
 	Initialized            bool
 	HotReload              bool
 	HotReloadEnabled       bool
+	ConfigWatchEnabled     bool
+	HTTP2Enabled           bool
+	H2CEnabled             bool
+	HTTP3Enabled           bool
+	RestartEnabled         bool
+	SSLClientAuthEnabled   bool
+	ProxyProtocolEnabled   bool
+	SocketActivation       bool
 	AuthSchemeExists       bool
 	Redirect               bool
 	Pid                    int
 	HTTPMaxHdrBytes        int
+	HTTP2MaxStreams        int
+	HTTP2MaxFrameSize      int
+	MaxURLLength           int
+	MaxQueryParams         int
+	MaxFormKeys            int
+	MaxMultipartParts      int
+	MaxMultipartHeaders    int
+	MaxConnections         int
+	MaxConcurrentRequests  int
+	GzipLevel              int
+	GzipMinSize            int64
 	ImportPath             string
 	BaseDir                string
 	VirtualBaseDir         string
@@ -54,21 +91,44 @@ type Settings struct {
 	EnvProfile             string
 	SSLCert                string
 	SSLKey                 string
+	SSLProfile             string
+	LetsEncryptChallenge   string
 	ServerHeader           string
 	RequestIDHeaderKey     string
 	SecureJSONPrefix       string
 	ShutdownGraceTimeStr   string
 	DefaultContentType     string
 	HotReloadSignalStr     string
+	HTTP3Port              string
+	SSLClientCACert        string
 	HTTPReadTimeout        time.Duration
+	HTTPReadHdrTimeout     time.Duration
 	HTTPWriteTimeout       time.Duration
+	HTTPIdleTimeout        time.Duration
+	HTTPRequestTimeout     time.Duration
 	ShutdownGraceTimeout   time.Duration
+	ClockSkew              time.Duration
+	HTTP3AltSvcMaxAge      time.Duration
+	ConfigWatchInterval    time.Duration
+	ProviderPollInterval   time.Duration
+	ProxyProtocolTimeout   time.Duration
+	ProxyProtocolTrusted   []*net.IPNet
+	PIIFields              []string
+	JSONViewRoles          []string
+	GzipMimeTypes          []string
+	ConfigProvider         config.Provider
+	SecretResolvers        []SecretResolver
+	Listeners              []Listener
 	Autocert               *autocert.Manager
+	SSLClientAuthMode      tls.ClientAuthType
 
-	cfg *config.Config
+	cfg        *config.Config
+	secretKeys map[string]bool
 }
 
 // Refresh method to parse/infer config values and populate settings instance.
+// Use `Holder.Refresh` for the settings in use, it swaps the snapshot only
+// if config values are valid.
 func (s *Settings) Refresh(cfg *config.Config) error {
 	s.cfg = cfg
 
@@ -76,22 +136,32 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 	if err = s.setEnvProfile(s.cfg.StringDefault("env.active", DefaultEnvProfile)); err != nil {
 		return err
 	}
+
+	// Decrypt/resolve the secret values, for e.g.: `ENC(...)`
+	if err = s.resolveSecrets(); err != nil {
+		return err
+	}
+
+	// Validate all the settings upfront and report every invalid value,
+	// so operators can fix them in one iteration
+	if errs := Validate(s.cfg); len(errs) > 0 {
+		return errs
+	}
+
 	s.SSLEnabled = s.cfg.BoolDefault("server.ssl.enable", false)
 	s.LetsEncryptEnabled = s.cfg.BoolDefault("server.ssl.lets_encrypt.enable", false)
 	s.Redirect = s.cfg.BoolDefault("server.redirect.enable", false)
+	s.SocketActivation = s.cfg.BoolDefault("server.socket_activation", false)
+	s.PIIFields, _ = s.cfg.StringList("security.pii.fields")
 
-	readTimeout := s.cfg.StringDefault("server.timeout.read", "90s")
-	writeTimeout := s.cfg.StringDefault("server.timeout.write", "90s")
-	if !util.IsValidTimeUnit(readTimeout, "s", "m") || !util.IsValidTimeUnit(writeTimeout, "s", "m") {
-		return errors.New("'server.timeout.{read|write}' value is not a valid time unit")
-	}
-
-	if s.HTTPReadTimeout, err = time.ParseDuration(readTimeout); err != nil {
-		return fmt.Errorf("'server.timeout.read': %s", err)
+	if err = s.parseTimeouts(); err != nil {
+		return err
 	}
 
-	if s.HTTPWriteTimeout, err = time.ParseDuration(writeTimeout); err != nil {
-		return fmt.Errorf("'server.timeout.write': %s", err)
+	s.MaxConnections = s.cfg.IntDefault("server.max_connections", 0)
+	s.MaxConcurrentRequests = s.cfg.IntDefault("server.max_concurrent_requests", 0)
+	if s.MaxConnections < 0 || s.MaxConcurrentRequests < 0 {
+		return errors.New("'server.{max_connections|max_concurrent_requests}' value must not be negative")
 	}
 
 	maxHdrBytesStr := s.cfg.StringDefault("server.max_header_bytes", "1mb")
@@ -103,9 +173,31 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 
 	s.SSLCert = s.cfg.StringDefault("server.ssl.cert", "")
 	s.SSLKey = s.cfg.StringDefault("server.ssl.key", "")
+	s.SSLProfile = strings.ToLower(s.cfg.StringDefault("server.ssl.profile", ""))
+	switch s.SSLProfile {
+	case "", "modern", "intermediate", "old":
+	default:
+		return fmt.Errorf("'server.ssl.profile' has unsupported value '%s', supported values are modern, intermediate and old", s.SSLProfile)
+	}
 	if err = s.checkSSLConfigValues(); err != nil {
 		return err
 	}
+	if err = s.parseClientAuth(); err != nil {
+		return err
+	}
+
+	if err = s.parseHTTP2(); err != nil {
+		return err
+	}
+	if err = s.parseHTTP3(); err != nil {
+		return err
+	}
+	if err = s.parseProxyProtocol(); err != nil {
+		return err
+	}
+	if err = s.parseListeners(); err != nil {
+		return err
+	}
 	if s.SSLEnabled && s.LetsEncryptEnabled {
 		cfgKeyPrefix := "server.ssl.lets_encrypt"
 		hostPolicy, found := s.cfg.StringList(cfgKeyPrefix + ".host_policy")
@@ -123,6 +215,21 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 
 		cacheDir := s.cfg.StringDefault(cfgKeyPrefix+".cache_dir", filepath.Join(s.BaseDir, "autocert"))
 		s.Autocert.Cache = autocert.DirCache(cacheDir)
+		s.Autocert.ForceRSA = s.cfg.BoolDefault(cfgKeyPrefix+".force_rsa", false)
+
+		// Custom ACME directory, for e.g.: Pebble, ZeroSSL, Let's Encrypt staging
+		if directoryURL := s.cfg.StringDefault(cfgKeyPrefix+".directory_url", ""); !ess.IsStrEmpty(directoryURL) {
+			if u, er := url.Parse(directoryURL); er != nil || u.Scheme != "https" {
+				return fmt.Errorf("'%s.directory_url' value must be a valid https URL", cfgKeyPrefix)
+			}
+			s.Autocert.Client = &acme.Client{DirectoryURL: directoryURL}
+		}
+
+		s.LetsEncryptChallenge = strings.ToLower(s.cfg.StringDefault(cfgKeyPrefix+".challenge", "tls-alpn-01"))
+		if s.LetsEncryptChallenge != "tls-alpn-01" && s.LetsEncryptChallenge != "dns-01" {
+			return fmt.Errorf("'%s.challenge' has unsupported value '%s', supported values are tls-alpn-01 and dns-01",
+				cfgKeyPrefix, s.LetsEncryptChallenge)
+		}
 	}
 
 	s.Type = s.cfg.StringDefault("type", "")
@@ -131,12 +238,18 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 			return errors.New("'request.max_body_size' value is not a valid size unit")
 		}
 
+		if err = s.parseRequestLimits(); err != nil {
+			return err
+		}
+
 		s.ServerHeader = s.cfg.StringDefault("server.header", "")
 		s.ServerHeaderEnabled = !ess.IsStrEmpty(s.ServerHeader)
 		s.RequestIDEnabled = s.cfg.BoolDefault("request.id.enable", true)
 		s.RequestIDHeaderKey = s.cfg.StringDefault("request.id.header", ahttp.HeaderXRequestID)
 		s.SecureHeadersEnabled = s.cfg.BoolDefault("security.http_header.enable", true)
 		s.GzipEnabled = s.cfg.BoolDefault("render.gzip.enable", true)
+		s.ETagEnabled = s.cfg.BoolDefault("render.etag.enable", false)
+		s.ETagWeak = s.cfg.BoolDefault("render.etag.weak", false)
 		s.AccessLogEnabled = s.cfg.BoolDefault("server.access_log.enable", false)
 		s.StaticAccessLogEnabled = s.cfg.BoolDefault("server.access_log.static_file", true)
 		s.DumpLogEnabled = s.cfg.BoolDefault("server.dump_log.enable", false)
@@ -145,26 +258,101 @@ func (s *Settings) Refresh(cfg *config.Config) error {
 		}
 
 		s.SecureJSONPrefix = s.cfg.StringDefault("render.secure_json.prefix", DefaultSecureJSONPrefix)
+		s.JSONViewRoles, _ = s.cfg.StringList("render.json_view.roles")
 
-		ahttp.GzipLevel = s.cfg.IntDefault("render.gzip.level", 4)
-		if !(ahttp.GzipLevel >= 1 && ahttp.GzipLevel <= 9) {
-			return fmt.Errorf("'render.gzip.level' is not a valid level value: %v", ahttp.GzipLevel)
+		s.GzipLevel = s.cfg.IntDefault("render.gzip.level", 4)
+		if !(s.GzipLevel >= 1 && s.GzipLevel <= 9) {
+			return fmt.Errorf("'render.gzip.level' is not a valid level value: %v", s.GzipLevel)
+		}
+		if s.GzipMinSize, err = s.cfg.ByteSizeDefault("render.gzip.min_size", DefaultGzipMinSize); err != nil {
+			return err
+		}
+		mimeTypes, found := s.cfg.StringList("render.gzip.mime_types")
+		if !found {
+			mimeTypes = DefaultGzipMimeTypes
+		}
+		s.GzipMimeTypes = make([]string, 0, len(mimeTypes))
+		for _, m := range mimeTypes {
+			s.GzipMimeTypes = append(s.GzipMimeTypes, strings.ToLower(strings.TrimSpace(m)))
 		}
 	}
 
 	s.HotReloadEnabled = s.cfg.BoolDefault("runtime.config_hotreload.enable", true)
 	s.HotReloadSignalStr = strings.ToUpper(s.cfg.StringDefault("runtime.config_hotreload.signal", "SIGHUP"))
+	s.ConfigWatchEnabled = s.cfg.BoolDefault("runtime.config_hotreload.watch.enable", false)
+	if s.ConfigWatchInterval, err = ParseDuration(s.cfg, "runtime.config_hotreload.watch.interval", 2*time.Second); err != nil {
+		return err
+	}
+	if s.ConfigWatchInterval <= 0 {
+		return errors.New("'runtime.config_hotreload.watch.interval' value must be a positive duration")
+	}
+	if s.ProviderPollInterval, err = ParseDuration(s.cfg, "runtime.config_provider.poll_interval", 30*time.Second); err != nil {
+		return err
+	}
+	if s.ProviderPollInterval <= 0 {
+		return errors.New("'runtime.config_provider.poll_interval' value must be a positive duration")
+	}
 
-	s.ShutdownGraceTimeStr = s.cfg.StringDefault("server.timeout.grace_shutdown", "60s")
-	if !util.IsValidTimeUnit(s.ShutdownGraceTimeStr, "s", "m") {
-		log.Warn("'server.timeout.grace_shutdown' value is not a valid time unit, assigning default value 60s")
-		s.ShutdownGraceTimeStr = "60s"
+	s.RestartEnabled = s.cfg.BoolDefault("server.restart.enable", false)
+	if s.RestartEnabled && s.HotReloadEnabled && s.HotReloadSignalStr == "SIGUSR2" {
+		return errors.New("'server.restart.enable' uses signal SIGUSR2, choose different 'runtime.config_hotreload.signal'")
+	}
+
+	if s.ClockSkew, err = ClockSkew(s.cfg); err != nil {
+		return err
 	}
-	s.ShutdownGraceTimeout, _ = time.ParseDuration(s.ShutdownGraceTimeStr)
 
 	return nil
 }
 
+// ClockSkew method returns the clock skew tolerance from config
+// `security.clock_skew`. It's the single leeway shared by all time based
+// validations of externally supplied timestamps such as session and anti-CSRF
+// cookie timestamps, OAuth2 state, JWT, HMAC signatures, TOTP, etc. Value
+// must be less than `1h`, default value is `0s`.
+func ClockSkew(cfg *config.Config) (time.Duration, error) {
+	skew, err := ParseDuration(cfg, "security.clock_skew", 0)
+	if err != nil {
+		return 0, err
+	}
+	if skew >= time.Hour {
+		return 0, errors.New("'security.clock_skew' value must be less than 1h")
+	}
+	return skew, nil
+}
+
+// clone method returns the copy of settings, slice and map values are copied
+// so changes on copy does not affect the snapshot. Autocert manager is copied
+// with its config, ACME client is shared since it's safe for concurrent use.
+func (s *Settings) clone() *Settings {
+	c := *s
+	c.SecretResolvers = append([]SecretResolver(nil), s.SecretResolvers...)
+	c.Listeners = append([]Listener(nil), s.Listeners...)
+	c.ProxyProtocolTrusted = append([]*net.IPNet(nil), s.ProxyProtocolTrusted...)
+	c.PIIFields = append([]string(nil), s.PIIFields...)
+	c.GzipMimeTypes = append([]string(nil), s.GzipMimeTypes...)
+	if s.secretKeys != nil {
+		c.secretKeys = make(map[string]bool, len(s.secretKeys))
+		for k, v := range s.secretKeys {
+			c.secretKeys[k] = v
+		}
+	}
+	if s.Autocert != nil {
+		c.Autocert = &autocert.Manager{
+			Prompt:                 s.Autocert.Prompt,
+			Cache:                  s.Autocert.Cache,
+			HostPolicy:             s.Autocert.HostPolicy,
+			RenewBefore:            s.Autocert.RenewBefore,
+			Client:                 s.Autocert.Client,
+			Email:                  s.Autocert.Email,
+			ForceRSA:               s.Autocert.ForceRSA,
+			ExtraExtensions:        append([]pkix.Extension(nil), s.Autocert.ExtraExtensions...),
+			ExternalAccountBinding: s.Autocert.ExternalAccountBinding,
+		}
+	}
+	return &c
+}
+
 // SetImportPath method process import path and sets it into settings instance.
 func (s *Settings) SetImportPath(args []string) {
 	for i, arg := range args {
@@ -187,6 +375,230 @@ func (s *Settings) setEnvProfile(p string) error {
 	return nil
 }
 
+// parseTimeouts method parses the server timeouts from config
+// `server.timeout.*`, values accept the `TimeUnits`. For e.g.: sub-second
+// `read_header = "500ms"` and long `grace_shutdown = "2h"`.
+func (s *Settings) parseTimeouts() error {
+	keyPrefix := "server.timeout."
+	var err error
+	if s.HTTPReadTimeout, err = ParseDuration(s.cfg, keyPrefix+"read", 90*time.Second); err != nil {
+		return err
+	}
+	if s.HTTPReadHdrTimeout, err = ParseDuration(s.cfg, keyPrefix+"read_header", s.HTTPReadTimeout); err != nil {
+		return err
+	}
+	if s.HTTPWriteTimeout, err = ParseDuration(s.cfg, keyPrefix+"write", 90*time.Second); err != nil {
+		return err
+	}
+	if s.HTTPIdleTimeout, err = ParseDuration(s.cfg, keyPrefix+"idle", s.HTTPReadTimeout); err != nil {
+		return err
+	}
+	if s.HTTPRequestTimeout, err = ParseDuration(s.cfg, keyPrefix+"request", s.HTTPWriteTimeout); err != nil {
+		return err
+	}
+
+	s.ShutdownGraceTimeStr = s.cfg.StringDefault(keyPrefix+"grace_shutdown", "60s")
+	if s.ShutdownGraceTimeout, err = ParseDuration(s.cfg, keyPrefix+"grace_shutdown", time.Minute); err != nil {
+		log.Warnf("%s, assigning default value 60s", err)
+		s.ShutdownGraceTimeStr, s.ShutdownGraceTimeout = "60s", time.Minute
+	}
+	return nil
+}
+
+// parseRequestLimits method parses the request parsing limits from config
+// `request.limits.*`. Value zero means no limit, URL length, query params
+// and form keys limits are not enabled by default.
+func (s *Settings) parseRequestLimits() error {
+	keyPrefix := "request.limits."
+	s.MaxURLLength = s.cfg.IntDefault(keyPrefix+"max_url_length", 0)
+	s.MaxQueryParams = s.cfg.IntDefault(keyPrefix+"max_query_params", 0)
+	s.MaxFormKeys = s.cfg.IntDefault(keyPrefix+"max_form_keys", 0)
+	s.MaxMultipartParts = s.cfg.IntDefault(keyPrefix+"max_multipart_parts", 1000)
+	s.MaxMultipartHeaders = s.cfg.IntDefault(keyPrefix+"max_multipart_headers", 10000)
+	for k, v := range map[string]int{
+		"max_url_length":        s.MaxURLLength,
+		"max_query_params":      s.MaxQueryParams,
+		"max_form_keys":         s.MaxFormKeys,
+		"max_multipart_parts":   s.MaxMultipartParts,
+		"max_multipart_headers": s.MaxMultipartHeaders,
+	} {
+		if v < 0 {
+			return fmt.Errorf("'%s%s' value must not be negative", keyPrefix, k)
+		}
+	}
+	return nil
+}
+
+// parseHTTP2 method parses the HTTP/2 settings from config `server.http2.*`.
+// HTTP/2 cleartext (h2c) is applicable only to non-TLS server.
+func (s *Settings) parseHTTP2() error {
+	keyPrefix := "server.http2."
+	s.HTTP2Enabled = s.cfg.BoolDefault(keyPrefix+"enable", !s.cfg.BoolDefault("server.ssl.disable_http2", false))
+	s.H2CEnabled = s.HTTP2Enabled && s.cfg.BoolDefault(keyPrefix+"h2c", false)
+	if s.H2CEnabled && s.SSLEnabled {
+		log.Warnf("'%sh2c' is not applicable to TLS server, it's ignored", keyPrefix)
+		s.H2CEnabled = false
+	}
+
+	s.HTTP2MaxStreams = s.cfg.IntDefault(keyPrefix+"max_concurrent_streams", 250)
+	if s.HTTP2MaxStreams < 1 {
+		return fmt.Errorf("'%smax_concurrent_streams' value must be greater than zero", keyPrefix)
+	}
+
+	// HTTP/2 allowed frame size is 16kb to 16mb-1, RFC 7540 section 4.2
+	frameSize, err := ess.StrToBytes(s.cfg.StringDefault(keyPrefix+"max_read_frame_size", "1mb"))
+	if err != nil {
+		return fmt.Errorf("'%smax_read_frame_size' value is not a valid size unit", keyPrefix)
+	}
+	if frameSize < 1<<14 || frameSize >= 1<<24 {
+		return fmt.Errorf("'%smax_read_frame_size' value must be at least 16kb and less than 16mb", keyPrefix)
+	}
+	s.HTTP2MaxFrameSize = int(frameSize)
+	return nil
+}
+
+// parseHTTP3 method parses the HTTP/3 settings from config `server.http3.*`.
+// HTTP/3 requires TLS, listener port defaults to `server.port`.
+func (s *Settings) parseHTTP3() error {
+	keyPrefix := "server.http3."
+	s.HTTP3Enabled = s.cfg.BoolDefault(keyPrefix+"enable", false)
+	if !s.HTTP3Enabled {
+		return nil
+	}
+	if !s.SSLEnabled {
+		return fmt.Errorf("'%senable' requires TLS, enable 'server.ssl.enable'", keyPrefix)
+	}
+
+	s.HTTP3Port = s.cfg.StringDefault(keyPrefix+"port", "")
+	var err error
+	s.HTTP3AltSvcMaxAge, err = ParseDuration(s.cfg, keyPrefix+"alt_svc_max_age", 24*time.Hour)
+	return err
+}
+
+// parseProxyProtocol method parses the HAProxy PROXY protocol settings from
+// config `server.proxy_protocol.*`. Header is parsed only for the connections
+// from `trusted_cidrs` sources, so it's required when PROXY protocol is
+// enabled. Additional listener can enable it via
+// `server.listeners.<name>.proxy_protocol`.
+func (s *Settings) parseProxyProtocol() error {
+	keyPrefix := "server.proxy_protocol."
+	s.ProxyProtocolEnabled = s.cfg.BoolDefault(keyPrefix+"enable", false)
+	s.ProxyProtocolTrusted = nil
+
+	var err error
+	if s.ProxyProtocolTimeout, err = ParseDuration(s.cfg, keyPrefix+"header_timeout", 5*time.Second); err != nil {
+		return err
+	}
+	cidrs, _ := s.cfg.StringList(keyPrefix + "trusted_cidrs")
+	for _, v := range cidrs {
+		_, ipNet, er := net.ParseCIDR(strings.TrimSpace(v))
+		if er != nil {
+			return fmt.Errorf("'%strusted_cidrs' has invalid CIDR '%s'", keyPrefix, v)
+		}
+		s.ProxyProtocolTrusted = append(s.ProxyProtocolTrusted, ipNet)
+	}
+	if s.ProxyProtocolEnabled && len(s.ProxyProtocolTrusted) == 0 {
+		return fmt.Errorf("'%strusted_cidrs' is required to enable PROXY protocol", keyPrefix)
+	}
+	return nil
+}
+
+// Listener represents the additional server listener from config
+// `server.listeners.<name>.*`, it serves the same application over its own
+// network address with independent timeouts.
+type Listener struct {
+	ProxyProtocol bool
+	Name          string
+	Network       string
+	Address       string
+	ReadTimeout   time.Duration
+	WriteTimeout  time.Duration
+}
+
+// IsUnix method returns true if listener is unix domain socket.
+func (l Listener) IsUnix() bool {
+	return l.Network == "unix"
+}
+
+// parseListeners method parses the additional listeners from config
+// `server.listeners.*`. Address `unix:/path/to/app.sock` creates unix domain
+// socket listener, timeouts default to `server.timeout.{read|write}`.
+func (s *Settings) parseListeners() error {
+	keyPrefix := "server.listeners"
+	names := s.cfg.KeysByPath(keyPrefix)
+	sort.Strings(names)
+	s.Listeners = make([]Listener, 0, len(names))
+	for _, name := range names {
+		key := keyPrefix + "." + name
+		l := Listener{Name: name, Network: "tcp"}
+		l.ProxyProtocol = s.cfg.BoolDefault(key+".proxy_protocol", s.ProxyProtocolEnabled)
+		if l.ProxyProtocol && len(s.ProxyProtocolTrusted) == 0 {
+			return fmt.Errorf("'server.proxy_protocol.trusted_cidrs' is required to enable PROXY protocol on '%s'", key)
+		}
+		address := s.cfg.StringDefault(key+".address", "")
+		if strings.HasPrefix(address, "unix:") {
+			l.Network, l.Address = "unix", address[5:]
+			if ess.IsStrEmpty(l.Address) {
+				return fmt.Errorf("'%s.address' unix socket path is empty", key)
+			}
+		} else {
+			port := s.cfg.StringDefault(key+".port", "")
+			if ess.IsStrEmpty(port) {
+				return fmt.Errorf("'%s.port' is required for TCP listener", key)
+			}
+			l.Address = net.JoinHostPort(address, port)
+		}
+
+		var err error
+		if l.ReadTimeout, err = ParseDuration(s.cfg, key+".timeout.read", s.HTTPReadTimeout); err != nil {
+			return err
+		}
+		if l.WriteTimeout, err = ParseDuration(s.cfg, key+".timeout.write", s.HTTPWriteTimeout); err != nil {
+			return err
+		}
+		s.Listeners = append(s.Listeners, l)
+	}
+	return nil
+}
+
+// parseClientAuth method parses the mutual TLS (mTLS) client certificate
+// authentication settings from config `server.ssl.client_auth.*`.
+func (s *Settings) parseClientAuth() error {
+	keyPrefix := "server.ssl.client_auth."
+	s.SSLClientAuthEnabled = s.cfg.BoolDefault(keyPrefix+"enable", false)
+	if !s.SSLClientAuthEnabled {
+		return nil
+	}
+	if !s.SSLEnabled {
+		return fmt.Errorf("'%senable' requires TLS, enable 'server.ssl.enable'", keyPrefix)
+	}
+
+	mode := s.cfg.StringDefault(keyPrefix+"mode", "require_and_verify")
+	switch mode {
+	case "request":
+		s.SSLClientAuthMode = tls.RequestClientCert
+	case "require":
+		s.SSLClientAuthMode = tls.RequireAnyClientCert
+	case "verify_if_given":
+		s.SSLClientAuthMode = tls.VerifyClientCertIfGiven
+	case "require_and_verify":
+		s.SSLClientAuthMode = tls.RequireAndVerifyClientCert
+	default:
+		return fmt.Errorf("'%smode' has unsupported value '%s', supported values are "+
+			"request, require, verify_if_given and require_and_verify", keyPrefix, mode)
+	}
+
+	s.SSLClientCACert = s.cfg.StringDefault(keyPrefix+"ca_cert", "")
+	if ess.IsStrEmpty(s.SSLClientCACert) {
+		if s.SSLClientAuthMode >= tls.VerifyClientCertIfGiven {
+			return fmt.Errorf("'%sca_cert' is required for mode '%s'", keyPrefix, mode)
+		}
+	} else if !ess.IsFileExists(s.SSLClientCACert) {
+		return fmt.Errorf("SSL client CA cert file not found: %s", s.SSLClientCACert)
+	}
+	return nil
+}
+
 func (s *Settings) checkSSLConfigValues() error {
 	if s.SSLEnabled {
 		if !s.LetsEncryptEnabled && (ess.IsStrEmpty(s.SSLCert) || ess.IsStrEmpty(s.SSLKey)) {
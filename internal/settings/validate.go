@@ -0,0 +1,183 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package settings
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"aahframe.work/internal/util"
+)
+
+// Errors type represents the aggregated invalid settings, it lists every
+// invalid setting with the offending key and expected format.
+type Errors []error
+
+// Error method is error interface implementation.
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d invalid settings:", len(e)))
+	for _, err := range e {
+		sb.WriteString("\n  - ")
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// Validate method checks the type and format of all the known settings in
+// the given config and returns every invalid setting. Keys not present in
+// the config are not validated, since framework uses default value.
+func Validate(cfg *config.Config) Errors {
+	var errs Errors
+	for _, r := range settingRules {
+		v, found := cfg.Get(r.key)
+		if !found {
+			continue
+		}
+		if err := r.check(v); err != nil {
+			errs = append(errs, fmt.Errorf("'%s' %s", r.key, err))
+		}
+	}
+	return errs
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//___________________________________
+
+const (
+	kindBool     = "bool"
+	kindInt      = "int"
+	kindString   = "string"
+	kindDuration = "duration"
+	kindSize     = "size"
+)
+
+type settingRule struct {
+	key   string
+	kind  string
+	units []string // duration units
+	min   int      // int range, max -1 means no upper bound and
+	max   int      // equal min and max means not checked
+}
+
+var settingRules = []settingRule{
+	{key: "env.active", kind: kindString},
+	{key: "type", kind: kindString},
+	{key: "server.header", kind: kindString},
+	{key: "server.ssl.enable", kind: kindBool},
+	{key: "server.ssl.cert", kind: kindString},
+	{key: "server.ssl.key", kind: kindString},
+	{key: "server.ssl.profile", kind: kindString},
+	{key: "server.ssl.client_auth.enable", kind: kindBool},
+	{key: "server.ssl.client_auth.ca_cert", kind: kindString},
+	{key: "server.ssl.client_auth.mode", kind: kindString},
+	{key: "server.ssl.lets_encrypt.enable", kind: kindBool},
+	{key: "server.ssl.lets_encrypt.renew_before", kind: kindInt, min: 1, max: 89},
+	{key: "server.ssl.lets_encrypt.directory_url", kind: kindString},
+	{key: "server.ssl.lets_encrypt.challenge", kind: kindString},
+	{key: "server.ssl.lets_encrypt.force_rsa", kind: kindBool},
+	{key: "server.redirect.enable", kind: kindBool},
+	{key: "server.http2.enable", kind: kindBool},
+	{key: "server.http2.h2c", kind: kindBool},
+	{key: "server.http2.max_concurrent_streams", kind: kindInt, min: 1, max: -1},
+	{key: "server.http2.max_read_frame_size", kind: kindSize},
+	{key: "server.http3.enable", kind: kindBool},
+	{key: "server.http3.port", kind: kindString},
+	{key: "server.http3.alt_svc_max_age", kind: kindDuration, units: []string{"s", "m", "h"}},
+	{key: "server.timeout.read", kind: kindDuration, units: TimeUnits},
+	{key: "server.timeout.write", kind: kindDuration, units: TimeUnits},
+	{key: "server.timeout.request", kind: kindDuration, units: TimeUnits},
+	{key: "server.timeout.read_header", kind: kindDuration, units: TimeUnits},
+	{key: "server.timeout.idle", kind: kindDuration, units: TimeUnits},
+	{key: "server.timeout.grace_shutdown", kind: kindString},
+	{key: "server.restart.enable", kind: kindBool},
+	{key: "server.socket_activation", kind: kindBool},
+	{key: "server.proxy_protocol.enable", kind: kindBool},
+	{key: "server.proxy_protocol.header_timeout", kind: kindDuration, units: TimeUnits},
+	{key: "server.max_header_bytes", kind: kindSize},
+	{key: "server.max_connections", kind: kindInt, min: 0, max: -1},
+	{key: "server.max_concurrent_requests", kind: kindInt, min: 0, max: -1},
+	{key: "server.access_log.enable", kind: kindBool},
+	{key: "server.access_log.static_file", kind: kindBool},
+	{key: "server.access_log.format", kind: kindString},
+	{key: "server.dump_log.enable", kind: kindBool},
+	{key: "request.max_body_size", kind: kindSize},
+	{key: "request.id.enable", kind: kindBool},
+	{key: "request.id.header", kind: kindString},
+	{key: "request.limits.max_url_length", kind: kindInt, min: 0, max: -1},
+	{key: "request.limits.max_query_params", kind: kindInt, min: 0, max: -1},
+	{key: "request.limits.max_form_keys", kind: kindInt, min: 0, max: -1},
+	{key: "request.limits.max_multipart_parts", kind: kindInt, min: 0, max: -1},
+	{key: "request.limits.max_multipart_headers", kind: kindInt, min: 0, max: -1},
+	{key: "security.http_header.enable", kind: kindBool},
+	{key: "security.clock_skew", kind: kindDuration, units: []string{"ms", "s", "m"}},
+	{key: "render.default", kind: kindString},
+	{key: "render.secure_json.prefix", kind: kindString},
+	{key: "render.gzip.enable", kind: kindBool},
+	{key: "render.gzip.level", kind: kindInt, min: 1, max: 9},
+	{key: "runtime.config_hotreload.enable", kind: kindBool},
+	{key: "runtime.config_hotreload.signal", kind: kindString},
+	{key: "runtime.config_hotreload.watch.enable", kind: kindBool},
+	{key: "runtime.config_hotreload.watch.interval", kind: kindDuration, units: []string{"ms", "s", "m"}},
+	{key: "runtime.config_provider.poll_interval", kind: kindDuration, units: []string{"s", "m", "h"}},
+	{key: "runtime.secrets.key_file", kind: kindString},
+	{key: "runtime.secrets.key_env", kind: kindString},
+}
+
+// check method validates the given value.
+func (r settingRule) check(v interface{}) error {
+	switch r.kind {
+	case kindBool:
+		if _, ok := v.(bool); ok {
+			return nil
+		}
+		return fmt.Errorf("value '%v' is invalid, expected boolean (true or false)", v)
+	case kindInt:
+		i, ok := v.(int64)
+		if !ok {
+			return fmt.Errorf("value '%v' is invalid, expected integer", v)
+		}
+		if r.max < 0 && i < int64(r.min) {
+			return fmt.Errorf("value '%v' is invalid, expected integer >= %d", v, r.min)
+		}
+		if r.min < r.max && (i < int64(r.min) || i > int64(r.max)) {
+			return fmt.Errorf("value '%v' is invalid, expected integer between %d and %d", v, r.min, r.max)
+		}
+		return nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("value '%v' is invalid, expected %s", v, r.expected())
+	}
+	switch r.kind {
+	case kindDuration:
+		if d, err := time.ParseDuration(s); err != nil || d < 0 || !util.IsValidTimeUnit(s, r.units...) {
+			return fmt.Errorf("value '%v' is invalid, expected %s", v, r.expected())
+		}
+	case kindSize:
+		if _, err := ess.StrToBytes(s); err != nil {
+			return fmt.Errorf("value '%v' is invalid, expected %s", v, r.expected())
+		}
+	}
+	return nil
+}
+
+func (r settingRule) expected() string {
+	switch r.kind {
+	case kindDuration:
+		return fmt.Sprintf("duration with unit %s (e.g. 30%s)", strings.Join(r.units, ", "), r.units[len(r.units)-1])
+	case kindSize:
+		return "size with unit (e.g. 512kb, 5mb)"
+	}
+	return r.kind
+}
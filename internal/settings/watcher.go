@@ -0,0 +1,98 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+// ConfigWatcher watches the config files (`*.conf`) of given directory
+// including sub-directories for changes and calls the change func. Files are
+// polled on the interval by modification time and size, so it works on all
+// platforms and file systems without additional dependency. Changes are
+// debounced, func is called once per interval even if multiple files changed.
+type ConfigWatcher struct {
+	dir      string
+	interval time.Duration
+	onChange func()
+	files    map[string]fileState
+	stop     chan struct{}
+	once     sync.Once
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewConfigWatcher method creates the config watcher for given directory.
+func NewConfigWatcher(dir string, interval time.Duration, onChange func()) *ConfigWatcher {
+	w := &ConfigWatcher{
+		dir:      dir,
+		interval: interval,
+		onChange: onChange,
+		stop:     make(chan struct{}),
+	}
+	w.files = w.scan()
+	return w
+}
+
+// Start method starts watching the config files, it blocks until the
+// `ConfigWatcher.Stop` is called.
+func (w *ConfigWatcher) Start() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Check method scans the config files and calls the change func if any of
+// the file is added, modified or removed since last scan.
+func (w *ConfigWatcher) Check() {
+	files := w.scan()
+	changed := len(files) != len(w.files)
+	if !changed {
+		for name, st := range files {
+			if pst, found := w.files[name]; !found || pst != st {
+				changed = true
+				break
+			}
+		}
+	}
+	w.files = files
+	if changed {
+		w.onChange()
+	}
+}
+
+// Stop method stops the config watcher.
+func (w *ConfigWatcher) Stop() {
+	w.once.Do(func() { close(w.stop) })
+}
+
+func (w *ConfigWatcher) scan() map[string]fileState {
+	files := make(map[string]fileState)
+	_ = filepath.Walk(w.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !ess.IsSliceContainsString(config.FileExts, strings.ToLower(filepath.Ext(info.Name()))) {
+			return nil
+		}
+		files[p] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	return files
+}
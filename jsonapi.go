@@ -0,0 +1,209 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+
+	"aahframe.work/ahttp"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Reply methods
+//______________________________________________________________________________
+
+// JSONAPI method renders given document as JSON:API response and it sets
+// HTTP 'Content-Type' as 'application/vnd.api+json'.
+//
+//	article := aah.NewJSONAPIResource("articles", "1", attrs)
+//	article.ToOne("author", &aah.JSONAPIResourceID{Type: "people", ID: "9"})
+//	ctx.Reply().JSONAPI(aah.NewJSONAPIDocument(article).Include(author))
+func (r *Reply) JSONAPI(doc *JSONAPIDocument) *Reply {
+	r.ContentType(ahttp.ContentTypeJSONAPI.String())
+	r.Render(&jsonRender{Data: doc})
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JSON:API document
+//______________________________________________________________________________
+
+// JSONAPIDocument holds the JSON:API top level document. Primary data is
+// serialized as `null` if it's nil and document has no errors.
+type JSONAPIDocument struct {
+	Data     interface{}            `json:"data,omitempty"`
+	Errors   []*JSONAPIError        `json:"errors,omitempty"`
+	Included []*JSONAPIResource     `json:"included,omitempty"`
+	Links    map[string]string      `json:"links,omitempty"`
+	Meta     map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPIResource holds the JSON:API resource object.
+type JSONAPIResource struct {
+	Type          string                          `json:"type"`
+	ID            string                          `json:"id,omitempty"`
+	Attributes    interface{}                     `json:"attributes,omitempty"`
+	Relationships map[string]*JSONAPIRelationship `json:"relationships,omitempty"`
+	Links         map[string]string               `json:"links,omitempty"`
+	Meta          map[string]interface{}          `json:"meta,omitempty"`
+}
+
+// JSONAPIResourceID holds the JSON:API resource identifier object.
+type JSONAPIResourceID struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// JSONAPIRelationship holds the JSON:API relationship object, `Data` is
+// `*JSONAPIResourceID` for to-one and `[]*JSONAPIResourceID` for to-many
+// relationship.
+type JSONAPIRelationship struct {
+	Data  interface{}            `json:"data"`
+	Links map[string]string      `json:"links,omitempty"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPIError holds the JSON:API error object.
+type JSONAPIError struct {
+	ID     string                 `json:"id,omitempty"`
+	Status string                 `json:"status,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Source *JSONAPIErrorSource    `json:"source,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// JSONAPIErrorSource holds the reference to the source of JSON:API error.
+type JSONAPIErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// NewJSONAPIDocument method creates the JSON:API document for given primary
+// data, it could be `*JSONAPIResource`, `[]*JSONAPIResource` or nil.
+func NewJSONAPIDocument(data interface{}) *JSONAPIDocument {
+	return &JSONAPIDocument{Data: data}
+}
+
+// NewJSONAPIErrorDocument method creates the JSON:API document for given
+// errors.
+func NewJSONAPIErrorDocument(errs ...*JSONAPIError) *JSONAPIDocument {
+	return &JSONAPIDocument{Errors: errs}
+}
+
+// Include method adds the given resources as included (compound document)
+// resources.
+func (d *JSONAPIDocument) Include(resources ...*JSONAPIResource) *JSONAPIDocument {
+	d.Included = append(d.Included, resources...)
+	return d
+}
+
+// AddLink method adds the top level link.
+func (d *JSONAPIDocument) AddLink(name, href string) *JSONAPIDocument {
+	if d.Links == nil {
+		d.Links = make(map[string]string)
+	}
+	d.Links[name] = href
+	return d
+}
+
+// SetMeta method sets the top level meta value.
+func (d *JSONAPIDocument) SetMeta(key string, value interface{}) *JSONAPIDocument {
+	if d.Meta == nil {
+		d.Meta = make(map[string]interface{})
+	}
+	d.Meta[key] = value
+	return d
+}
+
+// Paginate method adds the pagination links `first`, `prev`, `next` and
+// `last` and meta `page` with `number`, `size`, `total_items` and
+// `total_pages`.
+func (d *JSONAPIDocument) Paginate(p *Pagination) *JSONAPIDocument {
+	for _, l := range []struct {
+		name string
+		link *PageLink
+	}{{"first", p.First}, {"prev", p.Prev}, {"next", p.Next}, {"last", p.Last}} {
+		if l.link != nil {
+			d.AddLink(l.name, l.link.URL)
+		}
+	}
+	return d.SetMeta("page", map[string]int{
+		"number":      p.Page,
+		"size":        p.PerPage,
+		"total_items": p.TotalItems,
+		"total_pages": p.TotalPages,
+	})
+}
+
+// MarshalJSON method is implementation of `json.Marshaler`. Member `data`
+// is always present for document without errors.
+func (d *JSONAPIDocument) MarshalJSON() ([]byte, error) {
+	type document JSONAPIDocument
+	if len(d.Errors) > 0 {
+		return json.Marshal((*document)(d))
+	}
+	return json.Marshal(struct {
+		Data interface{} `json:"data"`
+		*document
+	}{Data: d.Data, document: (*document)(d)})
+}
+
+// NewJSONAPIResource method creates the JSON:API resource object for given
+// type, id and attributes.
+func NewJSONAPIResource(resType, id string, attributes interface{}) *JSONAPIResource {
+	return &JSONAPIResource{Type: resType, ID: id, Attributes: attributes}
+}
+
+// Identifier method returns the resource identifier object of the resource.
+func (r *JSONAPIResource) Identifier() *JSONAPIResourceID {
+	return &JSONAPIResourceID{Type: r.Type, ID: r.ID}
+}
+
+// ToOne method sets the to-one relationship, nil resource identifier
+// represents the empty relationship.
+func (r *JSONAPIResource) ToOne(name string, rid *JSONAPIResourceID) *JSONAPIRelationship {
+	var data interface{}
+	if rid != nil {
+		data = rid
+	}
+	return r.setRelationship(name, data)
+}
+
+// ToMany method sets the to-many relationship.
+func (r *JSONAPIResource) ToMany(name string, rids ...*JSONAPIResourceID) *JSONAPIRelationship {
+	if rids == nil {
+		rids = []*JSONAPIResourceID{}
+	}
+	return r.setRelationship(name, rids)
+}
+
+// AddLink method adds the resource level link.
+func (r *JSONAPIResource) AddLink(name, href string) *JSONAPIResource {
+	if r.Links == nil {
+		r.Links = make(map[string]string)
+	}
+	r.Links[name] = href
+	return r
+}
+
+// AddLink method adds the relationship link, for e.g.: `self`, `related`.
+func (rel *JSONAPIRelationship) AddLink(name, href string) *JSONAPIRelationship {
+	if rel.Links == nil {
+		rel.Links = make(map[string]string)
+	}
+	rel.Links[name] = href
+	return rel
+}
+
+func (r *JSONAPIResource) setRelationship(name string, data interface{}) *JSONAPIRelationship {
+	if r.Relationships == nil {
+		r.Relationships = make(map[string]*JSONAPIRelationship)
+	}
+	rel := &JSONAPIRelationship{Data: data}
+	r.Relationships[name] = rel
+	return rel
+}
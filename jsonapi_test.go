@@ -0,0 +1,48 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONAPIDocument(t *testing.T) {
+	author := NewJSONAPIResource("people", "9", map[string]string{"name": "Jeeva"})
+	article := NewJSONAPIResource("articles", "1", map[string]string{"title": "aah"}).
+		AddLink("self", "/articles/1")
+	article.ToOne("author", author.Identifier()).AddLink("related", "/articles/1/author")
+	article.ToMany("comments")
+	article.ToOne("editor", nil)
+
+	b, err := json.Marshal(NewJSONAPIDocument(article).Include(author).AddLink("self", "/articles/1"))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"data":{"type":"articles","id":"1","attributes":{"title":"aah"},"relationships":{`+
+		`"author":{"data":{"type":"people","id":"9"},"links":{"related":"/articles/1/author"}},`+
+		`"comments":{"data":[]},"editor":{"data":null}},"links":{"self":"/articles/1"}},`+
+		`"included":[{"type":"people","id":"9","attributes":{"name":"Jeeva"}}],"links":{"self":"/articles/1"}}`, string(b))
+
+	// primary data null
+	b, _ = json.Marshal(NewJSONAPIDocument(nil))
+	assert.Equal(t, `{"data":null}`, string(b))
+
+	// errors document does not have data
+	b, _ = json.Marshal(NewJSONAPIErrorDocument(&JSONAPIError{Status: "422", Title: "Invalid Attribute",
+		Source: &JSONAPIErrorSource{Pointer: "/data/attributes/title"}}))
+	assert.Equal(t, `{"errors":[{"status":"422","title":"Invalid Attribute","source":{"pointer":"/data/attributes/title"}}]}`, string(b))
+}
+
+func TestJSONAPIDocumentPaginate(t *testing.T) {
+	p := &Pagination{Page: 1, PerPage: 10, TotalItems: 15, TotalPages: 2,
+		Next: &PageLink{URL: "/articles?page=2"}, Last: &PageLink{URL: "/articles?page=2"}}
+	doc := NewJSONAPIDocument([]*JSONAPIResource{NewJSONAPIResource("articles", "1", nil)}).Paginate(p)
+
+	b, err := json.Marshal(doc)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"data":[{"type":"articles","id":"1"}],"links":{"last":"/articles?page=2","next":"/articles?page=2"},`+
+		`"meta":{"page":{"number":1,"size":10,"total_items":15,"total_pages":2}}}`, string(b))
+}
@@ -0,0 +1,237 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// jsonCacheMaxDepth is maximum nesting depth of reply data hashing, data
+// beyond it (for e.g.: cyclic references) is not cached.
+const jsonCacheMaxDepth = 32
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initJSONCache method initializes the JSON reply cache of routes configured
+// with `json_cache` TTL. Config `render.json_cache.max_entries` limits the
+// cached replies.
+func (a *Application) initJSONCache() error {
+	maxEntries := a.Config().IntDefault("render.json_cache.max_entries", 1000)
+	if maxEntries <= 0 {
+		return fmt.Errorf("'render.json_cache.max_entries' value must be greater than zero")
+	}
+	a.jsonCache = &jsonReplyCache{
+		maxEntries: maxEntries,
+		entries:    make(map[[16]byte]*jsonCacheEntry),
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JSON reply cache
+//______________________________________________________________________________
+
+type jsonCacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// jsonReplyCache holds the serialized JSON replies by hash of reply data.
+type jsonReplyCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[[16]byte]*jsonCacheEntry
+}
+
+func (c *jsonReplyCache) get(key [16]byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, found := c.entries[key]
+	if !found {
+		return nil
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return e.body
+}
+
+// put method adds the serialized reply into cache. If cache is full, expired
+// entries are removed and reply is not cached if it is still full.
+func (c *jsonReplyCache) put(key [16]byte, body []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= c.maxEntries {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+	c.entries[key] = &jsonCacheEntry{body: body, expires: now.Add(ttl)}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Cached JSON Render
+//______________________________________________________________________________
+
+// cachedJSONRender renders the JSON response, serialized JSON is reused for
+// identical data within the route `json_cache` TTL.
+type cachedJSONRender struct {
+	Data  interface{}
+	TTL   time.Duration
+	cache *jsonReplyCache
+}
+
+// Render method writes cached JSON into HTTP response, data which cannot be
+// hashed (for e.g.: func, chan) is serialized every time.
+func (j *cachedJSONRender) Render(w io.Writer) error {
+	key, ok := hashReplyData(j.Data)
+	if !ok {
+		return json.NewEncoder(w).Encode(j.Data)
+	}
+	if b := j.cache.get(key); b != nil {
+		_, err := w.Write(b)
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(j.Data); err != nil {
+		return err
+	}
+	j.cache.put(key, buf.Bytes(), j.TTL)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// hashReplyData method returns the 128-bit hash of given data by walking its
+// value, unexported fields are included since custom `json.Marshaler` may
+// use them.
+func hashReplyData(data interface{}) ([16]byte, bool) {
+	var key [16]byte
+	h := fnv.New128a()
+	if !hashValue(h, reflect.ValueOf(data), 0) {
+		return key, false
+	}
+	copy(key[:], h.Sum(nil))
+	return key, true
+}
+
+func hashValue(h hash.Hash, v reflect.Value, depth int) bool {
+	if depth > jsonCacheMaxDepth {
+		return false
+	}
+	if !v.IsValid() {
+		_, _ = h.Write([]byte{0})
+		return true
+	}
+
+	var b [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(b[:], u)
+		_, _ = h.Write(b[:])
+	}
+
+	_, _ = h.Write([]byte{byte(v.Kind())})
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(v.Complex())))
+		writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		_, _ = h.Write([]byte(v.String()))
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			writeUint(0)
+			return true
+		}
+		writeUint(1)
+		return hashValue(h, v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			writeUint(math.MaxUint64)
+			return true
+		}
+		writeUint(uint64(v.Len()))
+		if v.Type().Elem().Kind() == reflect.Uint8 && v.Kind() == reflect.Slice {
+			_, _ = h.Write(v.Bytes())
+			return true
+		}
+		for i := 0; i < v.Len(); i++ {
+			if !hashValue(h, v.Index(i), depth+1) {
+				return false
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			writeUint(math.MaxUint64)
+			return true
+		}
+		// map entries are hashed independently and summed up, since map
+		// iteration order is random
+		writeUint(uint64(v.Len()))
+		var sum [2]uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			eh := fnv.New128a()
+			if !hashValue(eh, iter.Key(), depth+1) || !hashValue(eh, iter.Value(), depth+1) {
+				return false
+			}
+			s := eh.Sum(nil)
+			sum[0] += binary.LittleEndian.Uint64(s[:8])
+			sum[1] += binary.LittleEndian.Uint64(s[8:])
+		}
+		writeUint(sum[0])
+		writeUint(sum[1])
+	case reflect.Struct:
+		_, _ = h.Write([]byte(v.Type().String()))
+		if v.Type() == timeType && v.CanInterface() {
+			t := v.Interface().(time.Time)
+			writeUint(uint64(t.UnixNano()))
+			_, _ = h.Write([]byte(t.Location().String()))
+			return true
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if !hashValue(h, v.Field(i), depth+1) {
+				return false
+			}
+		}
+	default:
+		// func, chan and unsafe pointer
+		return false
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonCacheProduct struct {
+	ID      int               `json:"id"`
+	Name    string            `json:"name"`
+	Tags    []string          `json:"tags"`
+	Attrs   map[string]string `json:"attrs"`
+	Updated time.Time         `json:"updated"`
+	secret  string
+}
+
+func TestJSONReplyCache(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `render { json_cache { max_entries = 2; } }`))
+	assert.Nil(t, a.initJSONCache())
+
+	updated := time.Date(2018, 8, 1, 10, 0, 0, 0, time.UTC)
+	newProduct := func() *jsonCacheProduct {
+		return &jsonCacheProduct{ID: 1, Name: "aah", Tags: []string{"web", "go"},
+			Attrs: map[string]string{"a": "1", "b": "2", "c": "3"}, Updated: updated, secret: "x"}
+	}
+
+	// identical data has same hash regardless of map order
+	k1, ok := hashReplyData(newProduct())
+	assert.True(t, ok)
+	k2, _ := hashReplyData(newProduct())
+	assert.Equal(t, k1, k2)
+
+	p := newProduct()
+	p.Attrs["c"] = "4"
+	k3, _ := hashReplyData(p)
+	assert.NotEqual(t, k1, k3)
+	p = newProduct()
+	p.secret = "y"
+	k4, _ := hashReplyData(p)
+	assert.NotEqual(t, k1, k4)
+
+	_, ok = hashReplyData(map[string]interface{}{"fn": func() {}})
+	assert.False(t, ok)
+
+	render := func(data interface{}) string {
+		buf := &bytes.Buffer{}
+		assert.Nil(t, (&cachedJSONRender{Data: data, TTL: time.Minute, cache: a.jsonCache}).Render(buf))
+		return buf.String()
+	}
+	body := render(newProduct())
+	assert.Equal(t, `{"id":1,"name":"aah","tags":["web","go"],"attrs":{"a":"1","b":"2","c":"3"},"updated":"2018-08-01T10:00:00Z"}`+"\n", body)
+	assert.Equal(t, 1, len(a.jsonCache.entries))
+	assert.Equal(t, body, render(newProduct()))
+	assert.Equal(t, 1, len(a.jsonCache.entries))
+
+	// cache is full, reply is rendered without caching
+	render(map[string]int{"count": 1})
+	assert.Equal(t, `{"count":2}`+"\n", render(map[string]int{"count": 2}))
+	assert.Equal(t, 2, len(a.jsonCache.entries))
+
+	// expired entries are removed on get and on put when cache is full
+	c := &jsonReplyCache{maxEntries: 1, entries: make(map[[16]byte]*jsonCacheEntry)}
+	c.put(k1, []byte("{}"), -time.Second)
+	c.put(k3, []byte("[]"), time.Minute)
+	assert.Equal(t, 1, len(c.entries))
+	assert.Nil(t, c.get(k1))
+	assert.Equal(t, []byte("[]"), c.get(k3))
+
+	assert.Nil(t, setTestConfig(a, `render { json_cache { max_entries = 0; } }`))
+	err := a.initJSONCache()
+	assert.Equal(t, "'render.json_cache.max_entries' value must be greater than zero", err.Error())
+}
@@ -0,0 +1,263 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// jsonViewTag is the struct tag key of JSON views, for e.g.:
+//
+//	type User struct {
+//	  ID    int    `json:"id"`
+//	  Name  string `json:"name"`
+//	  Email string `json:"email" view:"admin,self"`
+//	}
+//
+// Fields without view tag are serialized in every view.
+const jsonViewTag = "view"
+
+// jsonViewMaxDepth is maximum nesting depth of reply data to apply the
+// JSON view, data beyond it is serialized as-is.
+const jsonViewMaxDepth = 32
+
+var (
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Reply Unexported methods
+//______________________________________________________________________________
+
+// resolveJSONView method returns the JSON view of the reply. View is resolved
+// in the order of `Reply.JSONView`, first role of config
+// `render.json_view.roles` the subject has and route `json_view`.
+func (r *Reply) resolveJSONView() string {
+	if len(r.jsonView) > 0 {
+		return r.jsonView
+	}
+	if s := r.ctx.subject; s != nil && s.AuthorizationInfo != nil {
+		for _, role := range r.ctx.a.settings().JSONViewRoles {
+			if s.HasRole(role) {
+				return role
+			}
+		}
+	}
+	if r.ctx.route != nil {
+		return r.ctx.route.JSONView
+	}
+	return ""
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JSON view
+//______________________________________________________________________________
+
+// applyJSONView method returns the data with struct fields of given view,
+// structs are converted into ordered JSON object. Types which implement
+// `json.Marshaler` or `encoding.TextMarshaler` are serialized as-is.
+func applyJSONView(data interface{}, view string) interface{} {
+	if len(view) == 0 || data == nil {
+		return data
+	}
+	return jsonViewValue(reflect.ValueOf(data), view, 0)
+}
+
+func jsonViewValue(v reflect.Value, view string, depth int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if depth > jsonViewMaxDepth || isJSONMarshaler(v.Type()) {
+		return v.Interface()
+	}
+	if v.CanAddr() && isJSONMarshaler(v.Addr().Type()) {
+		return v.Addr().Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		return jsonViewValue(v.Elem(), view, depth+1)
+	case reflect.Slice, reflect.Array:
+		if (v.Kind() == reflect.Slice && v.IsNil()) || v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		values := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values[i] = jsonViewValue(v.Index(i), view, depth+1)
+		}
+		return values
+	case reflect.Map:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		m := reflect.MakeMapWithSize(reflect.MapOf(v.Type().Key(), emptyInterfaceType), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			val := jsonViewValue(iter.Value(), view, depth+1)
+			if val == nil {
+				m.SetMapIndex(iter.Key(), reflect.Zero(emptyInterfaceType))
+			} else {
+				m.SetMapIndex(iter.Key(), reflect.ValueOf(val))
+			}
+		}
+		return m.Interface()
+	case reflect.Struct:
+		obj := &jsonViewObject{index: make(map[string]int)}
+		obj.collect(v, view, depth, 0)
+		return obj
+	}
+	return v.Interface()
+}
+
+func isJSONMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+func isInJSONView(tag, view string) bool {
+	if len(tag) == 0 {
+		return true
+	}
+	for _, v := range strings.Split(tag, ",") {
+		if strings.TrimSpace(v) == view {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyJSONValue method reports the empty value as per `omitempty` option
+// of `encoding/json`.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+type jsonViewField struct {
+	name  string
+	value interface{}
+	level int
+}
+
+// jsonViewObject is the JSON object of struct fields in the declared order.
+type jsonViewObject struct {
+	fields []jsonViewField
+	index  map[string]int
+}
+
+// collect method adds the struct fields of given view, fields of embedded
+// structs are promoted same as `encoding/json`, shallower field wins.
+func (o *jsonViewObject) collect(v reflect.Value, view string, depth, level int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" || !isInJSONView(sf.Tag.Get(jsonViewTag), view) {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx >= 0 {
+			name, opts = tag[:idx], tag[idx:]
+		}
+
+		fv := v.Field(i)
+		if sf.Anonymous && len(name) == 0 {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				ft, fv = ft.Elem(), fv.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isJSONMarshaler(ft) && !isJSONMarshaler(reflect.PtrTo(ft)) {
+				o.collect(fv, view, depth+1, level+1)
+				continue
+			}
+		}
+		// unexported fields are not serialized
+		if len(sf.PkgPath) > 0 || !fv.CanInterface() {
+			continue
+		}
+		if len(name) == 0 {
+			name = sf.Name
+		}
+		if strings.Contains(opts, ",omitempty") && isEmptyJSONValue(fv) {
+			continue
+		}
+
+		var value interface{}
+		if strings.Contains(opts, ",string") && isJSONScalar(fv.Kind()) {
+			b, _ := json.Marshal(fv.Interface())
+			value = string(b)
+		} else {
+			value = jsonViewValue(fv, view, depth+1)
+		}
+		o.add(jsonViewField{name: name, value: value, level: level})
+	}
+}
+
+func (o *jsonViewObject) add(f jsonViewField) {
+	if idx, found := o.index[f.name]; found {
+		if o.fields[idx].level > f.level {
+			o.fields[idx] = f
+		}
+		return
+	}
+	o.index[f.name] = len(o.fields)
+	o.fields = append(o.fields, f)
+}
+
+// MarshalJSON method is implementation of `json.Marshaler`.
+func (o *jsonViewObject) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, f := range o.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		buf.Write(name)
+		buf.WriteByte(':')
+		b, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func isJSONScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aahframe.work/internal/settings"
+	"aahframe.work/router"
+	"aahframe.work/security/authz"
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonViewAudit struct {
+	CreatedBy string `json:"created_by" view:"admin"`
+	UpdatedBy string `json:"updated_by,omitempty" view:"admin"`
+}
+
+type jsonViewUser struct {
+	jsonViewAudit
+	ID       int                      `json:"id,string"`
+	Name     string                   `json:"name"`
+	Email    string                   `json:"email" view:"admin,self"`
+	Password string                   `json:"-"`
+	Joined   time.Time                `json:"joined"`
+	Friends  []*jsonViewUser          `json:"friends,omitempty"`
+	Labels   map[string]*jsonViewUser `json:"labels,omitempty"`
+	secret   string
+}
+
+func TestJSONViewApply(t *testing.T) {
+	joined := time.Date(2018, 8, 1, 10, 0, 0, 0, time.UTC)
+	user := &jsonViewUser{
+		jsonViewAudit: jsonViewAudit{CreatedBy: "jeeva"},
+		ID:            1, Name: "aah", Email: "aah@example.com", Password: "secret", Joined: joined,
+		Friends: []*jsonViewUser{{ID: 2, Name: "go", Email: "go@example.com", Joined: joined}},
+		secret:  "x",
+	}
+
+	marshal := func(view string) string {
+		b, err := json.Marshal(applyJSONView(user, view))
+		assert.Nil(t, err)
+		return string(b)
+	}
+
+	// no view, as-is
+	b, _ := json.Marshal(user)
+	assert.Equal(t, string(b), marshal(""))
+
+	assert.Equal(t, `{"id":"1","name":"aah","joined":"2018-08-01T10:00:00Z",`+
+		`"friends":[{"id":"2","name":"go","joined":"2018-08-01T10:00:00Z"}]}`, marshal("public"))
+	assert.Equal(t, `{"id":"1","name":"aah","email":"aah@example.com","joined":"2018-08-01T10:00:00Z",`+
+		`"friends":[{"id":"2","name":"go","email":"go@example.com","joined":"2018-08-01T10:00:00Z"}]}`, marshal("self"))
+	assert.Equal(t, `{"created_by":"jeeva","id":"1","name":"aah","email":"aah@example.com","joined":"2018-08-01T10:00:00Z",`+
+		`"friends":[{"created_by":"","id":"2","name":"go","email":"go@example.com","joined":"2018-08-01T10:00:00Z"}]}`, marshal("admin"))
+
+	// maps and slices of structs
+	b, _ = json.Marshal(applyJSONView(map[string][]jsonViewUser{"users": {*user.Friends[0]}}, "public"))
+	assert.Equal(t, `{"users":[{"id":"2","name":"go","joined":"2018-08-01T10:00:00Z"}]}`, string(b))
+
+	var nilUser *jsonViewUser
+	b, _ = json.Marshal(applyJSONView(nilUser, "public"))
+	assert.Equal(t, "null", string(b))
+}
+
+func TestJSONViewReply(t *testing.T) {
+	a := newApp()
+	a.settingsHolder.Update(func(s *settings.Settings) { s.JSONViewRoles = []string{"admin", "self"} })
+
+	user := jsonViewUser{ID: 1, Name: "aah", Email: "aah@example.com"}
+	reply := func(route *router.Route, roles []string, view string) string {
+		ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		ctx.a = a
+		ctx.route = route
+		if roles != nil {
+			ctx.Subject().AuthorizationInfo = authz.NewAuthorizationInfo()
+			ctx.Subject().AuthorizationInfo.AddRole(roles...)
+		}
+		if len(view) > 0 {
+			ctx.Reply().JSONView(view)
+		}
+		ctx.Reply().JSON(user)
+		b, err := json.Marshal(ctx.Reply().Rdr.(*jsonRender).Data)
+		assert.Nil(t, err)
+		return string(b)
+	}
+
+	route := &router.Route{Name: "user", JSONView: "public"}
+	assert.Equal(t, `{"id":"1","name":"aah","joined":"0001-01-01T00:00:00Z"}`, reply(route, nil, ""))
+	assert.Equal(t, `{"id":"1","name":"aah","email":"aah@example.com","joined":"0001-01-01T00:00:00Z"}`,
+		reply(route, []string{"self"}, ""))
+	assert.Equal(t, `{"created_by":"","id":"1","name":"aah","email":"aah@example.com","joined":"0001-01-01T00:00:00Z"}`,
+		reply(route, []string{"self", "admin"}, ""))
+	assert.Equal(t, `{"id":"1","name":"aah","joined":"0001-01-01T00:00:00Z"}`,
+		reply(route, []string{"admin"}, "public"))
+}
@@ -0,0 +1,399 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const acmeAccountKeyName = "acme_account+key"
+
+// DNSProvider interface is implemented by the DNS providers to solve the
+// ACME DNS-01 challenge, for e.g.: Route 53, Cloudflare, Google Cloud DNS.
+// It's required when `server.ssl.lets_encrypt.challenge = "dns-01"`.
+type DNSProvider interface {
+	// Present method creates the TXT record `fqdn` with given value. It should
+	// return once the record is propagated to authoritative name servers.
+	Present(ctx context.Context, domain, fqdn, value string) error
+
+	// CleanUp method removes the TXT record created by `Present`.
+	CleanUp(ctx context.Context, domain, fqdn, value string) error
+}
+
+// SetDNSProvider method sets the DNS provider for Let's Encrypt DNS-01
+// challenge. DNS-01 challenge does not require the inbound HTTP/TLS access
+// from CA, it's useful for internal hosts and load balanced deployments.
+//
+// Use `aah.OnInit` or `func init() {...}` to assign your DNS provider.
+func (a *Application) SetDNSProvider(p DNSProvider) {
+	a.Lock()
+	defer a.Unlock()
+	a.dnsProvider = p
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// letsEncryptTLSConfig method returns the TLS config of Let's Encrypt for
+// configured challenge `server.ssl.lets_encrypt.challenge`.
+func (a *Application) letsEncryptTLSConfig() (*tls.Config, error) {
+	if a.settings().LetsEncryptChallenge != "dns-01" {
+		return a.settings().Autocert.TLSConfig(), nil
+	}
+
+	a.RLock()
+	provider := a.dnsProvider
+	a.RUnlock()
+	if provider == nil {
+		return nil, errors.New("'server.ssl.lets_encrypt.challenge' is dns-01, however DNS provider is not set, use 'SetDNSProvider'")
+	}
+	dm := &dnsCertManager{
+		a:        a,
+		m:        a.settings().Autocert,
+		provider: provider,
+		certs:    make(map[string]*tls.Certificate),
+		pending:  make(map[string]*dnsCertRequest),
+	}
+	return dm.TLSConfig(), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// DNS-01 certificate manager
+//______________________________________________________________________________
+
+// dnsCertManager obtains and renews the certificates via ACME DNS-01
+// challenge. It shares the host policy, cache, renew before, email, ACME
+// directory and key type settings of autocert manager. Cached certificates
+// are compatible with autocert cache format.
+type dnsCertManager struct {
+	a        *Application
+	m        *autocert.Manager
+	provider DNSProvider
+
+	sync.Mutex
+	certs   map[string]*tls.Certificate
+	pending map[string]*dnsCertRequest
+
+	clientMu sync.Mutex
+	client   *acme.Client
+}
+
+type dnsCertRequest struct {
+	done chan struct{}
+	cert *tls.Certificate
+	err  error
+}
+
+func (dm *dnsCertManager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: dm.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+}
+
+func (dm *dnsCertManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if len(name) == 0 {
+		return nil, errors.New("aah: missing server name")
+	}
+	if strings.ContainsAny(name, `+/\`) {
+		return nil, errors.New("aah: server name contains invalid character")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	return dm.cert(ctx, name)
+}
+
+func (dm *dnsCertManager) cert(ctx context.Context, name string) (*tls.Certificate, error) {
+	dm.Lock()
+	cert, found := dm.certs[name]
+	dm.Unlock()
+	if !found {
+		var err error
+		if cert, err = dm.cacheGet(ctx, name); err == nil {
+			dm.Lock()
+			dm.certs[name] = cert
+			dm.Unlock()
+		} else if err != autocert.ErrCacheMiss {
+			dm.a.Log().Errorf("Let's Encrypt: unable to read cached certificate '%s': %v", name, err)
+		}
+	}
+
+	if cert != nil {
+		if dm.needsRenewal(cert) && time.Now().Before(cert.Leaf.NotAfter) {
+			dm.a.Go(func(ctx context.Context) {
+				if _, err := dm.obtain(ctx, name); err != nil {
+					dm.a.Log().Errorf("Let's Encrypt: unable to renew certificate '%s': %v", name, err)
+				}
+			})
+		}
+		if time.Now().Before(cert.Leaf.NotAfter) {
+			return cert, nil
+		}
+	}
+
+	if err := dm.m.HostPolicy(ctx, name); err != nil {
+		return nil, err
+	}
+	return dm.obtain(ctx, name)
+}
+
+// obtain method obtains the certificate for given name, concurrent requests
+// of same name wait for single ACME order.
+func (dm *dnsCertManager) obtain(ctx context.Context, name string) (*tls.Certificate, error) {
+	dm.Lock()
+	if req, found := dm.pending[name]; found {
+		dm.Unlock()
+		select {
+		case <-req.done:
+			return req.cert, req.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	req := &dnsCertRequest{done: make(chan struct{})}
+	dm.pending[name] = req
+	dm.Unlock()
+
+	req.cert, req.err = dm.issue(ctx, name)
+
+	dm.Lock()
+	delete(dm.pending, name)
+	if req.err == nil {
+		dm.certs[name] = req.cert
+	}
+	dm.Unlock()
+	close(req.done)
+	return req.cert, req.err
+}
+
+func (dm *dnsCertManager) issue(ctx context.Context, name string) (*tls.Certificate, error) {
+	client, err := dm.acmeClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	authz, err := client.Authorize(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if authz.Status != acme.StatusValid {
+		var chal *acme.Challenge
+		for _, c := range authz.Challenges {
+			if c.Type == "dns-01" {
+				chal = c
+				break
+			}
+		}
+		if chal == nil {
+			return nil, fmt.Errorf("aah: ACME server does not offer dns-01 challenge for '%s'", name)
+		}
+
+		value, err := client.DNS01ChallengeRecord(chal.Token)
+		if err != nil {
+			return nil, err
+		}
+		fqdn := "_acme-challenge." + name + "."
+		if err = dm.provider.Present(ctx, name, fqdn, value); err != nil {
+			return nil, fmt.Errorf("aah: DNS provider present '%s': %v", fqdn, err)
+		}
+		defer func() {
+			if err := dm.provider.CleanUp(context.Background(), name, fqdn, value); err != nil {
+				dm.a.Log().Warnf("Let's Encrypt: DNS provider cleanup '%s': %v", fqdn, err)
+			}
+		}()
+
+		if _, err = client.Accept(ctx, chal); err != nil {
+			return nil, err
+		}
+		if _, err = client.WaitAuthorization(ctx, authz.URI); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := dm.newCertKey()
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: name},
+		DNSNames: []string{name},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	der, _, err := client.CreateCert(ctx, csr, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	leaf, err := x509.ParseCertificate(der[0])
+	if err != nil {
+		return nil, err
+	}
+	if err = leaf.VerifyHostname(name); err != nil {
+		return nil, err
+	}
+	cert := &tls.Certificate{Certificate: der, PrivateKey: key, Leaf: leaf}
+	if err = dm.cachePut(ctx, name, cert); err != nil {
+		dm.a.Log().Errorf("Let's Encrypt: unable to cache certificate '%s': %v", name, err)
+	}
+	dm.a.Log().Infof("Let's Encrypt: certificate obtained for '%s' via dns-01, expires on %s", name, leaf.NotAfter)
+	return cert, nil
+}
+
+// acmeClient method returns the ACME client with registered account, account
+// key is stored in the cache.
+func (dm *dnsCertManager) acmeClient(ctx context.Context) (*acme.Client, error) {
+	dm.clientMu.Lock()
+	defer dm.clientMu.Unlock()
+	if dm.client != nil {
+		return dm.client, nil
+	}
+
+	key, err := dm.accountKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client := &acme.Client{Key: key}
+	if dm.m.Client != nil {
+		client.DirectoryURL = dm.m.Client.DirectoryURL
+	}
+
+	var contact []string
+	if len(dm.m.Email) > 0 {
+		contact = []string{"mailto:" + dm.m.Email}
+	}
+	_, err = client.Register(ctx, &acme.Account{Contact: contact}, acme.AcceptTOS)
+	if ae, ok := err.(*acme.Error); err == nil || ok && ae.StatusCode == http.StatusConflict {
+		dm.client = client
+		return client, nil
+	}
+	return nil, err
+}
+
+func (dm *dnsCertManager) accountKey(ctx context.Context) (crypto.Signer, error) {
+	if dm.m.Cache != nil {
+		data, err := dm.m.Cache.Get(ctx, acmeAccountKeyName)
+		if err == nil {
+			block, _ := pem.Decode(data)
+			if block == nil || block.Type != "EC PRIVATE KEY" {
+				return nil, errors.New("aah: invalid ACME account key in cache")
+			}
+			return x509.ParseECPrivateKey(block.Bytes)
+		}
+		if err != autocert.ErrCacheMiss {
+			return nil, err
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if dm.m.Cache != nil {
+		b, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if err = dm.m.Cache.Put(ctx, acmeAccountKeyName, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+func (dm *dnsCertManager) newCertKey() (crypto.Signer, error) {
+	if dm.m.ForceRSA {
+		return rsa.GenerateKey(rand.Reader, 2048)
+	}
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+func (dm *dnsCertManager) needsRenewal(cert *tls.Certificate) bool {
+	return time.Now().Add(dm.m.RenewBefore).After(cert.Leaf.NotAfter)
+}
+
+// cacheKey method returns the cache key of certificate, same as autocert.
+func (dm *dnsCertManager) cacheKey(name string) string {
+	if dm.m.ForceRSA {
+		return name + "+rsa"
+	}
+	return name
+}
+
+// cacheGet method reads the certificate from cache, data is PEM encoded
+// private key followed by certificate chain.
+func (dm *dnsCertManager) cacheGet(ctx context.Context, name string) (*tls.Certificate, error) {
+	if dm.m.Cache == nil {
+		return nil, autocert.ErrCacheMiss
+	}
+	data, err := dm.m.Cache.Get(ctx, dm.cacheKey(name))
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return nil, err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return nil, err
+	}
+	if err = cert.Leaf.VerifyHostname(name); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+func (dm *dnsCertManager) cachePut(ctx context.Context, name string, cert *tls.Certificate) error {
+	if dm.m.Cache == nil {
+		return nil
+	}
+	var block *pem.Block
+	switch key := cert.PrivateKey.(type) {
+	case *ecdsa.PrivateKey:
+		b, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}
+	case *rsa.PrivateKey:
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	default:
+		return errors.New("aah: unsupported certificate key type")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := pem.Encode(buf, block); err != nil {
+		return err
+	}
+	for _, der := range cert.Certificate {
+		if err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+			return err
+		}
+	}
+	return dm.m.Cache.Put(ctx, dm.cacheKey(name), buf.Bytes())
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDNSProvider struct{}
+
+func (testDNSProvider) Present(ctx context.Context, domain, fqdn, value string) error { return nil }
+func (testDNSProvider) CleanUp(ctx context.Context, domain, fqdn, value string) error { return nil }
+
+func TestLetsEncryptDNS01(t *testing.T) {
+	a := newWebApp1TestApp(t)
+	cacheDir := t.TempDir()
+	assert.Nil(t, mergeTestConfig(a, `
+	server {
+	  ssl {
+	    enable = true
+	    lets_encrypt {
+	      enable = true
+	      host_policy = ["example.org"]
+	      challenge = "dns-01"
+	      directory_url = "https://localhost:14000/dir"
+	      cache_dir = "`+cacheDir+`"
+	    }
+	  }
+	}
+	`))
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+	assert.Equal(t, "dns-01", a.settings().LetsEncryptChallenge)
+	assert.Equal(t, "https://localhost:14000/dir", a.settings().Autocert.Client.DirectoryURL)
+
+	// DNS provider is not set
+	_, err := a.letsEncryptTLSConfig()
+	assert.Equal(t, "'server.ssl.lets_encrypt.challenge' is dns-01, however DNS provider is not set, use 'SetDNSProvider'", err.Error())
+
+	a.SetDNSProvider(testDNSProvider{})
+	tlsCfg, err := a.letsEncryptTLSConfig()
+	assert.Nil(t, err)
+	assert.NotNil(t, tlsCfg.GetCertificate)
+
+	// cached certificate is served without ACME order
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.org"},
+		DNSNames:     []string{"example.org"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	leaf, _ := x509.ParseCertificate(der)
+
+	dm := &dnsCertManager{a: a, m: a.settings().Autocert, provider: testDNSProvider{},
+		certs: make(map[string]*tls.Certificate), pending: make(map[string]*dnsCertRequest)}
+	assert.Nil(t, dm.cachePut(context.Background(), "example.org", &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}))
+
+	cert, err := tlsCfg.GetCertificate(&tls.ClientHelloInfo{ServerName: "Example.org."})
+	assert.Nil(t, err)
+	assert.Equal(t, "example.org", cert.Leaf.Subject.CommonName)
+	assert.False(t, dm.needsRenewal(cert))
+	assert.True(t, dm.needsRenewal(&tls.Certificate{Leaf: &x509.Certificate{NotAfter: time.Now().Add(24 * time.Hour)}}))
+
+	// host policy
+	_, err = tlsCfg.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.org"})
+	assert.NotNil(t, err)
+	_, err = tlsCfg.GetCertificate(&tls.ClientHelloInfo{})
+	assert.Equal(t, "aah: missing server name", err.Error())
+
+	// invalid values
+	a.Config().SetString("server.ssl.lets_encrypt.directory_url", "http://localhost:14000/dir")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.ssl.lets_encrypt.directory_url' value must be a valid https URL", err.Error())
+
+	a.Config().SetString("server.ssl.lets_encrypt.directory_url", "")
+	a.Config().SetString("server.ssl.lets_encrypt.challenge", "http-01")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.ssl.lets_encrypt.challenge' has unsupported value 'http-01', supported values are tls-alpn-01 and dns-01", err.Error())
+}
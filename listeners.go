@@ -0,0 +1,94 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+
+	"aahframe.work/internal/settings"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// startListeners method starts the additional listeners configured via
+// `server.listeners { ... }` along with main server, for e.g.: TCP for
+// health checks and unix domain socket behind nginx. Each listener has its
+// own `http.Server` with independent timeouts and serves plain HTTP.
+//
+//	server {
+//	  listeners {
+//	    nginx {
+//	      address = "unix:/var/run/myapp/app.sock"
+//	    }
+//	    internal {
+//	      address = "127.0.0.1"
+//	      port = "9090"
+//	      timeout {
+//	        read = "5s"
+//	        write = "10s"
+//	      }
+//	    }
+//	  }
+//	}
+func (a *Application) startListeners() error {
+	for _, l := range a.settings().Listeners {
+		listener := a.activatedListener(l.Name)
+		if listener == nil {
+			var err error
+			if listener, err = listenOn(l); err != nil {
+				a.shutdownListeners(context.Background())
+				return err
+			}
+		}
+		listener = a.proxyProtocolListener(listener, l.ProxyProtocol)
+
+		srv := &http.Server{
+			Handler:        a.server.Handler,
+			ReadTimeout:    l.ReadTimeout,
+			WriteTimeout:   l.WriteTimeout,
+			MaxHeaderBytes: a.server.MaxHeaderBytes,
+			ErrorLog:       a.server.ErrorLog,
+		}
+		a.limitConnections(srv)
+		a.Lock()
+		a.listenerSrvs = append(a.listenerSrvs, srv)
+		a.Unlock()
+
+		go func(name string, srv *http.Server, listener net.Listener) {
+			a.Log().Infof("aah go server listener '%s' running on %s", name, listener.Addr())
+			if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+				a.Log().Error(err)
+			}
+		}(l.Name, srv, listener)
+	}
+	return nil
+}
+
+func (a *Application) shutdownListeners(ctx context.Context) {
+	a.Lock()
+	srvs := a.listenerSrvs
+	a.listenerSrvs = nil
+	a.Unlock()
+	for _, srv := range srvs {
+		if err := srv.Shutdown(ctx); err != nil && err != http.ErrServerClosed {
+			a.Log().Error(err)
+		}
+	}
+}
+
+func listenOn(l settings.Listener) (net.Listener, error) {
+	if l.IsUnix() {
+		// remove stale socket file from previous run
+		if err := os.Remove(l.Address); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return net.Listen(l.Network, l.Address)
+}
@@ -0,0 +1,262 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/internal/settings"
+)
+
+const (
+	priorityHigh = "high"
+	priorityLow  = "low"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package methods
+//______________________________________________________________________________
+
+// LoadSheddingMiddleware sheds the fraction of low priority requests with
+// `503 Service Unavailable` when application is overloaded. Overload is
+// determined by p99 latency of recent requests, goroutine count or process
+// CPU usage exceeding the configured thresholds.
+//
+// It has to be added after `aah.RouteMiddleware` in the middleware chain, so
+// that route priority classes can be applied.
+func LoadSheddingMiddleware(ctx *Context, m *Middleware) {
+	ls := ctx.a.loadShed
+	if ls == nil {
+		m.Next(ctx)
+		return
+	}
+
+	ls.evaluate()
+	if ls.IsOverloaded() && ls.priority(ctx) == priorityLow && rand.Intn(100) < ls.shedPercentage {
+		ctx.Log().Warnf("Load shedding, request rejected, Path: %s", ctx.Req.Path)
+		ctx.Reply().
+			Header(ahttp.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(ls.interval.Seconds())))).
+			ServiceUnavailable().
+			Error(newError(ErrLoadShed, http.StatusServiceUnavailable))
+		return
+	}
+
+	start := time.Now()
+	m.Next(ctx)
+	ls.record(time.Since(start))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initLoadShedding method initializes the adaptive load shedding from config
+// `server.load_shedding { ... }`. Threshold with value zero is not evaluated.
+//
+//	server {
+//	  load_shedding {
+//	    enable = true
+//	    p99_latency = "500ms"
+//	    max_goroutines = 10000
+//	    max_cpu = 90
+//	    shed_percentage = 50
+//	    interval = "1s"
+//	    sample_size = 1000
+//	    priority {
+//	      header = "X-Request-Priority"
+//	      default = "low"
+//	      high_routes = ["login_submit", "health_check"]
+//	      low_routes = ["reports"]
+//	    }
+//	  }
+//	}
+func (a *Application) initLoadShedding() error {
+	keyPrefix := "server.load_shedding"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.loadShed = nil
+		return nil
+	}
+
+	durations := map[string]time.Duration{}
+	for _, k := range []struct {
+		key string
+		def time.Duration
+	}{{"p99_latency", 0}, {"interval", time.Second}} {
+		d, err := settings.ParseDuration(cfg, keyPrefix+"."+k.key, k.def)
+		if err != nil {
+			return err
+		}
+		durations[k.key] = d
+	}
+	if durations["interval"] <= 0 {
+		return fmt.Errorf("'%s.interval' value must be greater than zero", keyPrefix)
+	}
+
+	ls := &loadShedder{
+		p99Latency:      durations["p99_latency"],
+		maxGoroutines:   cfg.IntDefault(keyPrefix+".max_goroutines", 0),
+		maxCPU:          cfg.IntDefault(keyPrefix+".max_cpu", 0),
+		shedPercentage:  cfg.IntDefault(keyPrefix+".shed_percentage", 50),
+		interval:        durations["interval"],
+		header:          cfg.StringDefault(keyPrefix+".priority.header", "X-Request-Priority"),
+		defaultPriority: strings.ToLower(cfg.StringDefault(keyPrefix+".priority.default", priorityLow)),
+		highRoutes:      make(map[string]bool),
+		lowRoutes:       make(map[string]bool),
+		lastEval:        time.Now(),
+		cpuTime:         processCPUTime,
+		numGoroutine:    runtime.NumGoroutine,
+	}
+
+	if ls.p99Latency == 0 && ls.maxGoroutines <= 0 && ls.maxCPU <= 0 {
+		return fmt.Errorf("'%s' requires at least one of 'p99_latency', 'max_goroutines' or 'max_cpu'", keyPrefix)
+	}
+	if ls.maxCPU < 0 || ls.maxCPU > 100 {
+		return fmt.Errorf("'%s.max_cpu' value must be between 0 and 100", keyPrefix)
+	}
+	if ls.shedPercentage <= 0 || ls.shedPercentage > 100 {
+		return fmt.Errorf("'%s.shed_percentage' value must be between 1 and 100", keyPrefix)
+	}
+	if ls.defaultPriority != priorityHigh && ls.defaultPriority != priorityLow {
+		return fmt.Errorf("'%s.priority.default' has unsupported value '%s', supported values are high and low",
+			keyPrefix, ls.defaultPriority)
+	}
+
+	sampleSize := cfg.IntDefault(keyPrefix+".sample_size", 1000)
+	if sampleSize <= 0 {
+		return fmt.Errorf("'%s.sample_size' value must be greater than zero", keyPrefix)
+	}
+	ls.samples = make([]time.Duration, 0, sampleSize)
+
+	highRoutes, _ := cfg.StringList(keyPrefix + ".priority.high_routes")
+	for _, name := range highRoutes {
+		ls.highRoutes[name] = true
+	}
+	lowRoutes, _ := cfg.StringList(keyPrefix + ".priority.low_routes")
+	for _, name := range lowRoutes {
+		ls.lowRoutes[name] = true
+	}
+	ls.lastCPUTime = ls.cpuTime()
+
+	a.loadShed = ls
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Load shedder
+//______________________________________________________________________________
+
+type loadShedder struct {
+	sync.Mutex
+	p99Latency      time.Duration
+	maxGoroutines   int
+	maxCPU          int
+	shedPercentage  int
+	interval        time.Duration
+	header          string
+	defaultPriority string
+	highRoutes      map[string]bool
+	lowRoutes       map[string]bool
+	samples         []time.Duration
+	next            int
+	lastEval        time.Time
+	lastCPUTime     time.Duration
+	overloaded      int32
+	cpuTime         func() time.Duration
+	numGoroutine    func() int
+}
+
+// IsOverloaded method returns true if any of the threshold exceeded on the
+// last evaluation.
+func (ls *loadShedder) IsOverloaded() bool {
+	return atomic.LoadInt32(&ls.overloaded) == 1
+}
+
+// priority method returns the request priority. Route priority classes
+// take precedence over the priority request header.
+func (ls *loadShedder) priority(ctx *Context) string {
+	if ctx.route != nil {
+		if ls.highRoutes[ctx.route.Name] {
+			return priorityHigh
+		}
+		if ls.lowRoutes[ctx.route.Name] {
+			return priorityLow
+		}
+	}
+
+	switch strings.ToLower(ctx.Req.Header.Get(ls.header)) {
+	case priorityHigh:
+		return priorityHigh
+	case priorityLow:
+		return priorityLow
+	}
+	return ls.defaultPriority
+}
+
+func (ls *loadShedder) record(latency time.Duration) {
+	ls.Lock()
+	defer ls.Unlock()
+	if len(ls.samples) < cap(ls.samples) {
+		ls.samples = append(ls.samples, latency)
+		return
+	}
+	ls.samples[ls.next] = latency
+	ls.next = (ls.next + 1) % len(ls.samples)
+}
+
+// evaluate method determines the overload state once per interval.
+func (ls *loadShedder) evaluate() {
+	ls.Lock()
+	defer ls.Unlock()
+	now := time.Now()
+	elapsed := now.Sub(ls.lastEval)
+	if elapsed < ls.interval {
+		return
+	}
+	ls.lastEval = now
+
+	overloaded := false
+	if ls.p99Latency > 0 && ls.percentile(0.99) > ls.p99Latency {
+		overloaded = true
+	}
+	if ls.maxGoroutines > 0 && ls.numGoroutine() > ls.maxGoroutines {
+		overloaded = true
+	}
+	if ls.maxCPU > 0 {
+		cpuTime := ls.cpuTime()
+		usage := float64(cpuTime-ls.lastCPUTime) / float64(elapsed*time.Duration(runtime.NumCPU())) * 100
+		ls.lastCPUTime = cpuTime
+		if usage > float64(ls.maxCPU) {
+			overloaded = true
+		}
+	}
+
+	var v int32
+	if overloaded {
+		v = 1
+	}
+	atomic.StoreInt32(&ls.overloaded, v)
+}
+
+func (ls *loadShedder) percentile(p float64) time.Duration {
+	if len(ls.samples) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(ls.samples))
+	copy(sorted, ls.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+}
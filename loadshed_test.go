@@ -0,0 +1,161 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadShedding(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	assert.Nil(t, setTestConfig(a, `
+	server {
+	  load_shedding {
+	    enable = true
+	    p99_latency = "100ms"
+	    max_goroutines = 5000
+	    shed_percentage = 100
+	    sample_size = 10
+	    priority {
+	      high_routes = ["login_submit"]
+	      low_routes = ["reports"]
+	    }
+	  }
+	}
+	`))
+	err := a.initLoadShedding()
+	assert.Nil(t, err)
+
+	ls := a.loadShed
+	assert.Equal(t, time.Second, ls.interval)
+	assert.Equal(t, "X-Request-Priority", ls.header)
+
+	newCtx := func(routeName, priority string) *Context {
+		r := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil)
+		if len(priority) > 0 {
+			r.Header.Set(ls.header, priority)
+		}
+		ctx := newContext(httptest.NewRecorder(), r)
+		ctx.a = a
+		ctx.route = &router.Route{Name: routeName}
+		return ctx
+	}
+
+	// priority classes
+	assert.Equal(t, priorityHigh, ls.priority(newCtx("login_submit", "low")))
+	assert.Equal(t, priorityLow, ls.priority(newCtx("reports", "high")))
+	assert.Equal(t, priorityHigh, ls.priority(newCtx("index", "High")))
+	assert.Equal(t, priorityLow, ls.priority(newCtx("index", "")))
+
+	// p99 latency
+	for i := 1; i <= 20; i++ {
+		ls.record(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, 10, len(ls.samples))
+	assert.Equal(t, 20*time.Millisecond, ls.percentile(0.99))
+	ls.record(150 * time.Millisecond)
+	ls.lastEval = time.Now().Add(-2 * time.Second)
+	ls.evaluate()
+	assert.True(t, ls.IsOverloaded())
+
+	// shed low priority requests only
+	ctx := newCtx("reports", "")
+	LoadSheddingMiddleware(ctx, &Middleware{})
+	assert.Equal(t, http.StatusServiceUnavailable, ctx.Reply().Code)
+	assert.Equal(t, ErrLoadShed, ctx.Reply().err.Reason)
+	assert.Equal(t, "1", ctx.Res.Header().Get(ahttp.HeaderRetryAfter))
+
+	ctx = newCtx("login_submit", "")
+	LoadSheddingMiddleware(ctx, &Middleware{})
+	assert.Nil(t, ctx.Reply().err)
+
+	// not re-evaluated within interval
+	ls.samples = ls.samples[:0]
+	ls.evaluate()
+	assert.True(t, ls.IsOverloaded())
+
+	ls.lastEval = time.Now().Add(-2 * time.Second)
+	ls.evaluate()
+	assert.False(t, ls.IsOverloaded())
+
+	// goroutines
+	ls.numGoroutine = func() int { return 6000 }
+	ls.lastEval = time.Now().Add(-2 * time.Second)
+	ls.evaluate()
+	assert.True(t, ls.IsOverloaded())
+}
+
+func TestLoadSheddingCPU(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	assert.Nil(t, setTestConfig(a, `server { load_shedding {
+		enable = true
+		max_cpu = 80
+	} }`))
+	err := a.initLoadShedding()
+	assert.Nil(t, err)
+
+	ls := a.loadShed
+	var cpuTime time.Duration
+	ls.cpuTime = func() time.Duration { return cpuTime }
+	ls.lastCPUTime = 0
+	ls.lastEval = time.Now().Add(-time.Second)
+	cpuTime = time.Duration(float64(time.Second) * 0.9 * float64(runtime.NumCPU()))
+	ls.evaluate()
+	assert.True(t, ls.IsOverloaded())
+
+	ls.lastEval = time.Now().Add(-time.Second)
+	cpuTime += time.Duration(float64(time.Second) * 0.1 * float64(runtime.NumCPU()))
+	ls.evaluate()
+	assert.False(t, ls.IsOverloaded())
+
+	// disabled
+	a.cfg = config.NewEmpty()
+	err = a.initLoadShedding()
+	assert.Nil(t, err)
+	assert.Nil(t, a.loadShed)
+}
+
+func TestLoadSheddingConfigErrors(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	for _, c := range []struct{ cfg, err string }{
+		{`server { load_shedding { enable = true; } }`,
+			"'server.load_shedding' requires at least one of 'p99_latency', 'max_goroutines' or 'max_cpu'"},
+		{`server { load_shedding {
+			enable = true
+			p99_latency = "500"
+		} }`, "'server.load_shedding.p99_latency' value is not a valid time unit"},
+		{`server { load_shedding {
+			enable = true
+			max_cpu = 120
+		} }`, "'server.load_shedding.max_cpu' value must be between 0 and 100"},
+		{`server { load_shedding {
+			enable = true
+			max_goroutines = 1000
+			shed_percentage = 0
+		} }`, "'server.load_shedding.shed_percentage' value must be between 1 and 100"},
+		{`server { load_shedding {
+			enable = true
+			max_goroutines = 1000
+			priority { default = "medium"; }
+		} }`, "'server.load_shedding.priority.default' has unsupported value 'medium', supported values are high and low"},
+	} {
+		assert.Nil(t, setTestConfig(a, c.cfg))
+		err := a.initLoadShedding()
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
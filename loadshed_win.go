@@ -0,0 +1,31 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package aah
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime method returns the user and kernel CPU time consumed by
+// the process.
+func processCPUTime() time.Duration {
+	h, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return 0
+	}
+	var creation, exit, kernel, user syscall.Filetime
+	if err = syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return 0
+	}
+	return time.Duration((filetimeTicks(kernel) + filetimeTicks(user)) * 100)
+}
+
+// filetimeTicks method returns the filetime duration in 100-nanosecond ticks.
+func filetimeTicks(ft syscall.Filetime) int64 {
+	return int64(ft.HighDateTime)<<32 | int64(ft.LowDateTime)
+}
@@ -6,11 +6,18 @@ package aah
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,6 +25,7 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/internal/util"
 	"aahframe.work/log"
 )
@@ -40,6 +48,7 @@ const (
 	fmtFlagResponseHeader
 	fmtFlagResponseTime
 	fmtFlagCustom
+	fmtFlagContext
 )
 
 var (
@@ -57,12 +66,50 @@ var (
 		"reshdr":    fmtFlagResponseHeader,
 		"restime":   fmtFlagResponseTime,
 		"custom":    fmtFlagCustom,
+		"ctx":       fmtFlagContext,
 	}
 
+	// accessLogCtxFlagRegex matches the alternate form of context field
+	// `%{name}ctx`, it's rewritten to `%ctx:name`.
+	accessLogCtxFlagRegex = regexp.MustCompile(`%\{([^}]+)\}ctx`)
+
 	defaultAccessLogPattern = "%clientip %custom:- %reqtime %reqmethod %requrl %reqproto %resstatus %ressize %restime %reqhdr:referer"
 	reqStartTimeKey         = "_appReqStartTimeKey"
+
+	// accessLogJSONFields is supported fields of JSON access log format in
+	// the default order.
+	accessLogJSONFields = []string{"time", "client_ip", "request_id", "method",
+		"path", "query", "proto", "route", "status", "bytes", "latency_ms", "user",
+		"user_agent", "referer"}
+	defaultAccessLogJSONFields = []string{"time", "client_ip", "request_id", "method",
+		"path", "route", "status", "bytes", "latency_ms", "user"}
 )
 
+// AccessLogFieldFunc func type is used to resolve the custom access log field
+// value from the request context. It's called on the request goroutine.
+type AccessLogFieldFunc func(ctx *Context) string
+
+// AddAccessLogField method adds the custom access log field resolver for
+// given name. Field is used in the access log pattern as `%ctx:name` or
+// `%{name}ctx` and in the JSON format fields as `ctx:name`. Field without
+// resolver is resolved from request context value `ctx.Get(name)`.
+func (a *Application) AddAccessLogField(name string, fn AccessLogFieldFunc) error {
+	if fn == nil {
+		return errors.New("aah: access log field func is nil")
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if _, found := a.accessLogFlds[name]; found {
+		return fmt.Errorf("aah: access log field '%s' is already added", name)
+	}
+	if a.accessLogFlds == nil {
+		a.accessLogFlds = make(map[string]AccessLogFieldFunc)
+	}
+	a.accessLogFlds[name] = fn
+	return nil
+}
+
 func (a *Application) initAccessLog() error {
 	// log file configuration
 	cfg := config.NewEmpty()
@@ -93,33 +140,73 @@ func (a *Application) initAccessLog() error {
 		logPool: &sync.Pool{New: func() interface{} { return new(accessLog) }},
 	}
 
-	// parse request access log pattern
-	pattern := a.Config().StringDefault("server.access_log.pattern", defaultAccessLogPattern)
-	aaLogFmtFlags, err := ess.ParseFmtFlag(pattern, accessLogFmtFlags)
-	if err != nil {
+	switch format := a.Config().StringDefault("server.access_log.format", "text"); format {
+	case "text":
+		// parse request access log pattern
+		pattern := a.Config().StringDefault("server.access_log.pattern", defaultAccessLogPattern)
+		pattern = accessLogCtxFlagRegex.ReplaceAllString(pattern, "%ctx:$1")
+		aaLogFmtFlags, err := ess.ParseFmtFlag(pattern, accessLogFmtFlags)
+		if err != nil {
+			return err
+		}
+		aaLogger.fmtFlags = aaLogFmtFlags
+		for _, part := range aaLogFmtFlags {
+			if part.Flag == fmtFlagContext {
+				aaLogger.ctxFields = append(aaLogger.ctxFields, part.Format)
+			}
+		}
+	case "json":
+		fields, found := a.Config().StringList("server.access_log.fields")
+		if !found || len(fields) == 0 {
+			fields = defaultAccessLogJSONFields
+		}
+		for _, f := range fields {
+			if strings.HasPrefix(f, "ctx:") && len(f) > 4 {
+				aaLogger.jsonFields = append(aaLogger.jsonFields, f)
+				aaLogger.ctxFields = append(aaLogger.ctxFields, f[4:])
+				continue
+			}
+			if !ess.IsSliceContainsString(accessLogJSONFields, f) {
+				return fmt.Errorf("'server.access_log.fields' has unsupported field '%s'", f)
+			}
+			aaLogger.jsonFields = append(aaLogger.jsonFields, strings.ToLower(f))
+		}
+	default:
+		return fmt.Errorf("'server.access_log.format' value must be 'text' or 'json', found '%s'", format)
+	}
+
+	if aaLogger.skip, err = parseAccessLogSkip(a.Config(), "server.access_log"); err != nil {
 		return err
 	}
-	aaLogger.fmtFlags = aaLogFmtFlags
 
-	// initialize request access log channel
-	aaLogger.logChan = make(chan *accessLog, a.Config().IntDefault("server.access_log.channel_buffer_size", 500))
+	// initialize request access log ring buffer, previous one is flushed
+	aaLogger.ring = newLogRing(a.Config().IntDefault("server.access_log.channel_buffer_size", 500), aaLogger.write)
 
+	old := a.accessLog
 	a.accessLog = aaLogger
-	go a.accessLog.listenToLogChan()
+	if old != nil {
+		_ = old.ring.Close(context.Background())
+	}
 
 	return nil
 }
 
 type accessLogger struct {
-	a        *Application
-	logger   *log.Logger
-	fmtFlags []ess.FmtFlagPart
-	logChan  chan *accessLog
-	logPool  *sync.Pool
+	a          *Application
+	logger     *log.Logger
+	fmtFlags   []ess.FmtFlagPart
+	jsonFields []string
+	ctxFields  []string
+	skip       *accessLogSkip
+	ring       *logRing
+	logPool    *sync.Pool
 }
 
 func (aal *accessLogger) Log(ctx *Context) {
-	if ctx.IsStaticRoute() && !aal.a.settings.StaticAccessLogEnabled {
+	if ctx.IsStaticRoute() && !aal.a.settings().StaticAccessLogEnabled {
+		return
+	}
+	if aal.skip != nil && aal.skip.match(ctx) {
 		return
 	}
 	al := aal.logPool.Get().(*accessLog)
@@ -131,22 +218,32 @@ func (aal *accessLogger) Log(ctx *Context) {
 
 	req := *ctx.Req
 	al.Request = &req
-	if h := req.Header[aal.a.settings.RequestIDHeaderKey]; len(h) > 0 {
+	if h := req.Header[aal.a.settings().RequestIDHeaderKey]; len(h) > 0 {
 		al.RequestID = h[0]
-	} else {
-		al.RequestID = "-"
 	}
 	al.ResStatus = ctx.Res.Status()
 	al.ResBytes = ctx.Res.BytesWritten()
 	al.ResHdr = ctx.Res.Header()
+	if ctx.route != nil {
+		al.RouteName = ctx.route.Name
+	}
+	if ctx.subject != nil && ctx.subject.AuthenticationInfo != nil {
+		if p := ctx.subject.AuthenticationInfo.PrimaryPrincipal(); p != nil {
+			al.User = p.Value
+		}
+	}
+	if len(aal.ctxFields) > 0 {
+		al.CtxValues = make(map[string]string, len(aal.ctxFields))
+		for _, name := range aal.ctxFields {
+			al.CtxValues[name] = aal.resolveField(ctx, name)
+		}
+	}
 
-	aal.logChan <- al
+	aal.ring.Push(al)
 }
 
-func (aal *accessLogger) listenToLogChan() {
-	for al := range aal.logChan {
-		aal.logger.Print(aal.accessLogFormatter(al))
-	}
+func (aal *accessLogger) write(v interface{}) {
+	aal.logger.Print(aal.accessLogFormatter(v.(*accessLog)))
 }
 
 func (aal *accessLogger) accessLogFormatter(al *accessLog) string {
@@ -154,6 +251,10 @@ func (aal *accessLogger) accessLogFormatter(al *accessLog) string {
 	buf := acquireBuffer()
 	defer releaseBuffer(buf)
 
+	if len(aal.jsonFields) > 0 {
+		return aal.jsonFormatter(al, buf)
+	}
+
 	for _, part := range aal.fmtFlags {
 		switch part.Flag {
 		case fmtFlagClientIP:
@@ -167,7 +268,11 @@ func (aal *accessLogger) accessLogFormatter(al *accessLog) string {
 		case fmtFlagRequestProto:
 			buf.WriteString(al.Request.Unwrap().Proto)
 		case fmtFlagRequestID:
-			buf.WriteString(al.RequestID)
+			if len(al.RequestID) == 0 {
+				buf.WriteString("-")
+			} else {
+				buf.WriteString(al.RequestID)
+			}
 		case fmtFlagRequestHeader:
 			buf.WriteString(al.GetRequestHdr(part.Format))
 		case fmtFlagQueryString:
@@ -182,12 +287,57 @@ func (aal *accessLogger) accessLogFormatter(al *accessLog) string {
 			buf.WriteString(fmt.Sprintf("%.4f", al.ElapsedDuration.Seconds()*1e3))
 		case fmtFlagCustom:
 			buf.WriteString(part.Format)
+		case fmtFlagContext:
+			if v := al.CtxValues[part.Format]; len(v) > 0 {
+				buf.WriteString(v)
+			} else {
+				buf.WriteString("-")
+			}
 		}
 		buf.WriteByte(' ')
 	}
 	return strings.TrimSpace(buf.String())
 }
 
+// jsonFormatter method writes the access log as one JSON object with
+// configured fields in the configured order.
+func (aal *accessLogger) jsonFormatter(al *accessLog, buf *bytes.Buffer) string {
+	buf.WriteByte('{')
+	for idx, f := range aal.jsonFields {
+		if idx > 0 {
+			buf.WriteByte(',')
+		}
+		var v interface{}
+		if strings.HasPrefix(f, "ctx:") {
+			f = f[4:]
+			v = al.CtxValues[f]
+		} else {
+			v = al.fieldValue(f)
+		}
+		buf.WriteString(strconv.Quote(f))
+		buf.WriteByte(':')
+		b, _ := json.Marshal(v)
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// resolveField method returns the custom field value from registered
+// resolver otherwise request context value.
+func (aal *accessLogger) resolveField(ctx *Context, name string) string {
+	aal.a.RLock()
+	fn := aal.a.accessLogFlds[name]
+	aal.a.RUnlock()
+	if fn != nil {
+		return fn(ctx)
+	}
+	if v := ctx.Get(name); v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
 func (aal *accessLogger) releaseAccessLog(al *accessLog) {
 	al.Reset()
 	aal.logPool.Put(al)
@@ -199,11 +349,49 @@ type accessLog struct {
 	ElapsedDuration time.Duration
 	Request         *ahttp.Request
 	RequestID       string
+	RouteName       string
+	User            string
+	CtxValues       map[string]string
 	ResStatus       int
 	ResBytes        int
 	ResHdr          http.Header
 }
 
+// fieldValue method returns the value of JSON access log field.
+func (al *accessLog) fieldValue(f string) interface{} {
+	switch f {
+	case "time":
+		return al.StartTime.Format(time.RFC3339Nano)
+	case "client_ip":
+		return al.Request.ClientIP()
+	case "request_id":
+		return al.RequestID
+	case "method":
+		return al.Request.Method
+	case "path":
+		return al.Request.Path
+	case "query":
+		return al.Request.URL().RawQuery
+	case "proto":
+		return al.Request.Unwrap().Proto
+	case "route":
+		return al.RouteName
+	case "status":
+		return al.ResStatus
+	case "bytes":
+		return al.ResBytes
+	case "latency_ms":
+		return json.Number(strconv.FormatFloat(al.ElapsedDuration.Seconds()*1e3, 'f', 4, 64))
+	case "user":
+		return al.User
+	case "user_agent":
+		return al.Request.Header.Get(ahttp.HeaderUserAgent)
+	case "referer":
+		return al.Request.Header.Get(ahttp.HeaderReferer)
+	}
+	return nil
+}
+
 // FmtRequestTime method returns the formatted request time. There are three
 // possibilities to handle, `%reqtime`, `%reqtime:` and `%reqtime:<format>`.
 func (al *accessLog) FmtRequestTime(format string) string {
@@ -241,11 +429,69 @@ func (al *accessLog) Reset() {
 	al.StartTime = time.Time{}
 	al.ElapsedDuration = 0
 	al.Request = nil
+	al.RequestID = ""
+	al.RouteName = ""
+	al.User = ""
+	al.CtxValues = nil
 	al.ResStatus = 0
 	al.ResBytes = 0
 	al.ResHdr = nil
 }
 
+// accessLogSkip holds the access log skip criteria, configured via
+// `server.access_log.skip_paths` and `server.access_log.skip_status_codes`.
+// Request is skipped if it matches all the configured criteria, for e.g.:
+// successful static file hits.
+type accessLogSkip struct {
+	paths       []string
+	statusCodes [][2]int
+}
+
+func (s *accessLogSkip) match(ctx *Context) bool {
+	if len(s.paths) > 0 {
+		matched := false
+		for _, p := range s.paths {
+			if matched = isDumpPathMatch(p, ctx.Req.Path); matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(s.statusCodes) > 0 {
+		matched, status := false, ctx.Res.Status()
+		for _, sc := range s.statusCodes {
+			if matched = status >= sc[0] && status <= sc[1]; matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseAccessLogSkip(cfg *config.Config, keyPrefix string) (*accessLogSkip, error) {
+	s := &accessLogSkip{}
+	s.paths, _ = cfg.StringList(keyPrefix + ".skip_paths")
+	statusCodes, _ := cfg.StringList(keyPrefix + ".skip_status_codes")
+	for _, sc := range statusCodes {
+		r, err := parseStatusCodeRange(sc)
+		if err != nil {
+			return nil, fmt.Errorf("'%s.skip_status_codes' has invalid value '%s'", keyPrefix, sc)
+		}
+		s.statusCodes = append(s.statusCodes, r)
+	}
+	if len(s.paths) == 0 && len(s.statusCodes) == 0 {
+		return nil, nil
+	}
+	return s, nil
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Dump logger Definitions
 //______________________________________________________________________________
@@ -255,10 +501,28 @@ const (
 	keyAahResponseBodyBuf = "_aahResponseBodyBuf"
 )
 
+// initDumpLog method initializes the dump log from config
+// `server.dump_log { ... }`. Header and field values are redacted and
+// captured body is limited to `max_body_size`, so that dump log could be
+// enabled in staging environment.
+//
+//	server {
+//	  dump_log {
+//	    enable = true
+//	    request_body = true
+//	    response_body = true
+//	    max_body_size = "64kb"
+//	    redact_headers = ["Authorization", "Cookie"]
+//	    redact_fields = ["password", "token"]
+//	    format = "json"
+//	  }
+//	}
 func (a *Application) initDumpLog() error {
+	keyPrefix := "server.dump_log"
+
 	// log file configuration
 	cfg := config.NewEmpty()
-	file := a.Config().StringDefault("server.dump_log.file", "")
+	file := a.Config().StringDefault(keyPrefix+".file", "")
 
 	cfg.SetString("log.receiver", "file")
 	if ess.IsStrEmpty(file) {
@@ -273,38 +537,162 @@ func (a *Application) initDumpLog() error {
 
 	cfg.SetString("log.pattern", "%message")
 
+	format := strings.ToLower(a.Config().StringDefault(keyPrefix+".format", "text"))
+	if format != "text" && format != "json" {
+		return fmt.Errorf("'%s.format' value must be 'text' or 'json'", keyPrefix)
+	}
+
+	var maxBodySize int64
+	if v := a.Config().StringDefault(keyPrefix+".max_body_size", ""); len(v) > 0 {
+		var err error
+		if maxBodySize, err = ess.StrToBytes(v); err != nil {
+			return fmt.Errorf("'%s.max_body_size': %s", keyPrefix, err)
+		}
+	}
+
 	adLog, err := log.New(cfg)
 	if err != nil {
 		return err
 	}
 
-	a.dumpLog = &dumpLogger{
+	filter, err := parseDumpLogFilter(a.Config(), keyPrefix+".filter")
+	if err != nil {
+		return err
+	}
+
+	d := &dumpLogger{
 		a:               a,
 		logger:          adLog,
-		logRequestBody:  a.Config().BoolDefault("server.dump_log.request_body", false),
-		logResponseBody: a.Config().BoolDefault("server.dump_log.response_body", false),
+		filter:          filter,
+		format:          format,
+		maxBodySize:     maxBodySize,
+		logRequestBody:  a.Config().BoolDefault(keyPrefix+".request_body", false),
+		logResponseBody: a.Config().BoolDefault(keyPrefix+".response_body", false),
+		redactFields:    defaultRedactFields,
 	}
 
+	headers := defaultRedactHeaders
+	if v, found := a.Config().StringList(keyPrefix + ".redact_headers"); found {
+		headers = v
+	}
+	for _, h := range headers {
+		d.redactHeaders = append(d.redactHeaders, http.CanonicalHeaderKey(h))
+	}
+	if v, found := a.Config().StringList(keyPrefix + ".redact_fields"); found {
+		d.redactFields = v
+	}
+	d.redactFields = a.piiRedactFields(d.redactFields)
+
+	// initialize dump log ring buffer, previous one is flushed
+	d.ring = newLogRing(a.Config().IntDefault(keyPrefix+".buffer_size", 500), d.write)
+	old := a.dumpLog
+	a.dumpLog = d
+	if old != nil {
+		_ = old.ring.Close(context.Background())
+	}
 	return nil
 }
 
 type dumpLogger struct {
 	a               *Application
 	logger          *log.Logger
+	filter          *dumpLogFilter
+	format          string
+	maxBodySize     int64
 	logRequestBody  bool
 	logResponseBody bool
+	redactHeaders   []string
+	redactFields    []string
+	ring            *logRing
+}
+
+// dumpEntry holds the request and response details of JSON dump log format.
+type dumpEntry struct {
+	Time     time.Time    `json:"time"`
+	Request  dumpRequest  `json:"request"`
+	Response dumpResponse `json:"response"`
+}
+
+type dumpRequest struct {
+	URI       string      `json:"uri"`
+	Method    string      `json:"method"`
+	Proto     string      `json:"proto"`
+	Header    http.Header `json:"header"`
+	Body      string      `json:"body,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+type dumpResponse struct {
+	Status       int         `json:"status"`
+	BytesWritten int         `json:"bytes_written"`
+	Header       http.Header `json:"header"`
+	Body         string      `json:"body,omitempty"`
+	Truncated    bool        `json:"truncated,omitempty"`
+}
+
+// dumpBody holds the captured request or response body up to
+// `max_body_size`, remaining bytes are discarded.
+type dumpBody struct {
+	buf       *bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+// Write method always reports the given bytes as written, so that it does
+// not interrupt the tee reader and multi writer.
+func (db *dumpBody) Write(p []byte) (int, error) {
+	n := len(p)
+	if db.limit > 0 {
+		if remain := db.limit - int64(db.buf.Len()); int64(n) > remain {
+			p, db.truncated = p[:remain], true
+		}
+	}
+	_, _ = db.buf.Write(p)
+	return n, nil
+}
+
+// IsRequestDumpable method returns true if request route and path
+// qualifies the dump log filter otherwise false. It's used to avoid
+// capturing request and response body for non-qualifying requests.
+func (d *dumpLogger) IsRequestDumpable(ctx *Context) bool {
+	return d.filter == nil || d.filter.matchRequest(ctx)
+}
+
+// IsDumpable method returns true if request and response qualifies the
+// dump log filter otherwise false.
+func (d *dumpLogger) IsDumpable(ctx *Context) bool {
+	return d.filter == nil || (d.filter.matchRequest(ctx) && d.filter.matchResponse(ctx))
+}
+
+// CaptureBody method returns the writer which captures the body for dump
+// log under given key.
+func (d *dumpLogger) CaptureBody(key string, ctx *Context) io.Writer {
+	db := &dumpBody{buf: acquireBuffer(), limit: d.maxBodySize}
+	ctx.Set(key, db)
+	return db
 }
 
 func (d *dumpLogger) Dump(ctx *Context) {
-	buf := acquireBuffer()
-	defer releaseBuffer(buf)
+	if !d.IsDumpable(ctx) {
+		d.releaseBody(keyAahRequestBodyBuf, ctx)
+		d.releaseBody(keyAahResponseBodyBuf, ctx)
+		return
+	}
 
 	// Request
 	uri := fmt.Sprintf("%s://%s%s", ctx.Req.Scheme, ctx.Req.Host, ctx.Req.Path)
 	if qs := ctx.Req.URL().RawQuery; len(qs) > 0 {
-		uri += "?" + qs
+		uri += "?" + d.redactQuery(qs)
+	}
+
+	if d.format == "json" {
+		d.dumpJSON(uri, ctx)
+		return
 	}
 
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+
 	buf.WriteString(fmt.Sprintf("\nURI: %s\n", uri))
 	buf.WriteString(fmt.Sprintf("METHOD: %s\n", ctx.Req.Method))
 	buf.WriteString(fmt.Sprintf("PROTO: %s\n", ctx.Req.Proto))
@@ -329,31 +717,120 @@ func (d *dumpLogger) Dump(ctx *Context) {
 
 	buf.WriteString("\n\n=======================================================================")
 
-	d.logger.Print(buf.String())
+	d.ring.Push(buf.String())
+}
+
+func (d *dumpLogger) write(v interface{}) {
+	d.logger.Print(v.(string))
+}
+
+// dumpJSON method writes the request and response details as single line
+// JSON object.
+func (d *dumpLogger) dumpJSON(uri string, ctx *Context) {
+	de := &dumpEntry{
+		Time: time.Now(),
+		Request: dumpRequest{
+			URI:    uri,
+			Method: ctx.Req.Method,
+			Proto:  ctx.Req.Proto,
+			Header: d.redactHeader(ctx.Req.Header),
+		},
+		Response: dumpResponse{
+			Status:       ctx.Res.Status(),
+			BytesWritten: ctx.Res.BytesWritten(),
+			Header:       d.redactHeader(ctx.Res.Header()),
+		},
+	}
+	if d.logRequestBody {
+		de.Request.Body, de.Request.Truncated = d.body(keyAahRequestBodyBuf, ctx.Req.ContentType().Mime, ctx)
+	}
+	if d.logResponseBody {
+		de.Response.Body, de.Response.Truncated = d.body(keyAahResponseBodyBuf, ctx.Reply().ContType, ctx)
+	}
+
+	b, err := json.Marshal(de)
+	if err != nil {
+		d.a.Log().Errorf("dump log: %s", err)
+		return
+	}
+	d.ring.Push(string(b))
+}
+
+// body method returns the redacted body of given key and true if body is
+// truncated by `max_body_size`. Captured body buffer is released.
+func (d *dumpLogger) body(key, ct string, ctx *Context) (string, bool) {
+	db, ok := ctx.Get(key).(*dumpBody)
+	if !ok {
+		return "", false
+	}
+	defer releaseBuffer(db.buf)
+	mime := util.OnlyMIME(ct)
+	if mime == ahttp.ContentTypeForm.Mime || strings.Contains(mime, "json") {
+		return redactBody(d.redactFields, mime, db.buf.Bytes()), db.truncated
+	}
+	return db.buf.String(), db.truncated
 }
 
 func (d *dumpLogger) writeBody(key, ct string, w *bytes.Buffer, ctx *Context) {
-	cbuf := ctx.Get(key)
-	if cbuf == nil {
+	if ctx.Get(key) == nil {
 		w.WriteString("    ***** NO CONTENT *****")
 		return
 	}
 
-	b := cbuf.(*bytes.Buffer)
+	body, truncated := d.body(key, ct, ctx)
 	switch util.OnlyMIME(ct) {
 	case ahttp.ContentTypeHTML.Mime, ahttp.ContentTypeForm.Mime,
 		ahttp.ContentTypeMultipartForm.Mime, ahttp.ContentTypePlainText.Mime:
-		_, _ = b.WriteTo(w)
+		w.WriteString(body)
 	case ahttp.ContentTypeJSON.Mime, ahttp.ContentTypeJSONText.Mime:
-		_ = json.Indent(w, b.Bytes(), "", "    ")
+		if err := json.Indent(w, []byte(body), "", "    "); err != nil {
+			w.WriteString(body)
+		}
 	case ahttp.ContentTypeXML.Mime, ahttp.ContentTypeXMLText.Mime:
 		// TODO XML formatting
-		_, _ = b.WriteTo(w)
+		w.WriteString(body)
+	}
+	if truncated {
+		w.WriteString(fmt.Sprintf("\n    ***** TRUNCATED AT %d BYTES *****", d.maxBodySize))
+	}
+}
+
+// redactQuery method masks the configured and PII tagged query parameter
+// values.
+func (d *dumpLogger) redactQuery(qs string) string {
+	if len(d.redactFields) == 0 {
+		return qs
+	}
+	values, err := url.ParseQuery(qs)
+	if err != nil {
+		return unparsableOmitted
+	}
+	return redactValues(d.redactFields, values).Encode()
+}
+
+// redactHeader method returns the copy of given headers with configured
+// header values masked.
+func (d *dumpLogger) redactHeader(hdrs http.Header) http.Header {
+	rh := make(http.Header, len(hdrs))
+	for k, v := range hdrs {
+		rh[k] = v
+	}
+	for _, h := range d.redactHeaders {
+		if _, found := rh[h]; found {
+			rh[h] = []string{settings.MaskedValue}
+		}
+	}
+	return rh
+}
+
+func (d *dumpLogger) releaseBody(key string, ctx *Context) {
+	if db, ok := ctx.Get(key).(*dumpBody); ok {
+		releaseBuffer(db.buf)
 	}
-	releaseBuffer(b)
 }
 
 func (d *dumpLogger) composeHeaders(hdrs http.Header) string {
+	hdrs = d.redactHeader(hdrs)
 	var str []string
 	for _, k := range sortHeaderKeys(hdrs) {
 		str = append(str, fmt.Sprintf("    %s: %s", k, strings.Join(hdrs[k], ", ")))
@@ -361,6 +838,146 @@ func (d *dumpLogger) composeHeaders(hdrs http.Header) string {
 	return strings.Join(str, "\n")
 }
 
+// dumpLogFilter holds the dump log filter criteria, configured via
+// `server.dump_log.filter { ... }`. Empty criteria matches all.
+//
+//	filter {
+//	  # route names or parent route names (route group)
+//	  routes = ["api_v1"]
+//	  # request paths, suffix `/**` matches path and its sub paths
+//	  paths = ["/api/**"]
+//	  # response content types, suffix `/*` matches all subtypes
+//	  content_types = ["application/json"]
+//	  # response status codes, supports class and range
+//	  status_codes = ["5xx", "400-404", "429"]
+//	}
+type dumpLogFilter struct {
+	routes       []string
+	paths        []string
+	contentTypes []string
+	statusCodes  [][2]int
+}
+
+func (f *dumpLogFilter) matchRequest(ctx *Context) bool {
+	if len(f.routes) > 0 {
+		if ctx.route == nil || !(ess.IsSliceContainsString(f.routes, ctx.route.Name) ||
+			ess.IsSliceContainsString(f.routes, ctx.route.ParentName)) {
+			return false
+		}
+	}
+
+	if len(f.paths) > 0 {
+		matched := false
+		for _, p := range f.paths {
+			if matched = isDumpPathMatch(p, ctx.Req.Path); matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *dumpLogFilter) matchResponse(ctx *Context) bool {
+	if len(f.statusCodes) > 0 {
+		matched, status := false, ctx.Res.Status()
+		for _, sc := range f.statusCodes {
+			if matched = status >= sc[0] && status <= sc[1]; matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(f.contentTypes) > 0 {
+		matched, ct := false, util.OnlyMIME(ctx.Res.Header().Get(ahttp.HeaderContentType))
+		for _, c := range f.contentTypes {
+			if strings.HasSuffix(c, "/*") {
+				matched = strings.HasPrefix(ct, c[:len(c)-1])
+			} else {
+				matched = ct == c
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseDumpLogFilter(cfg *config.Config, keyPrefix string) (*dumpLogFilter, error) {
+	if !cfg.IsExists(keyPrefix) {
+		return nil, nil
+	}
+
+	f := &dumpLogFilter{}
+	f.routes, _ = cfg.StringList(keyPrefix + ".routes")
+	f.paths, _ = cfg.StringList(keyPrefix + ".paths")
+	contentTypes, _ := cfg.StringList(keyPrefix + ".content_types")
+	for _, ct := range contentTypes {
+		f.contentTypes = append(f.contentTypes, strings.ToLower(strings.TrimSpace(ct)))
+	}
+
+	statusCodes, _ := cfg.StringList(keyPrefix + ".status_codes")
+	for _, sc := range statusCodes {
+		r, err := parseStatusCodeRange(sc)
+		if err != nil {
+			return nil, fmt.Errorf("'%s.status_codes' has invalid value '%s'", keyPrefix, sc)
+		}
+		f.statusCodes = append(f.statusCodes, r)
+	}
+
+	return f, nil
+}
+
+// parseStatusCodeRange method parses the status code value `404`,
+// class `5xx` and range `400-499` into lower and upper bound.
+func parseStatusCodeRange(v string) ([2]int, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if len(v) == 3 && strings.HasSuffix(v, "xx") {
+		c, err := strconv.Atoi(v[:1])
+		if err != nil || c < 1 || c > 5 {
+			return [2]int{}, errors.New("invalid status code class")
+		}
+		return [2]int{c * 100, c*100 + 99}, nil
+	}
+
+	lower, upper := v, v
+	if idx := strings.IndexByte(v, '-'); idx > 0 {
+		lower, upper = v[:idx], v[idx+1:]
+	}
+	l, err := strconv.Atoi(strings.TrimSpace(lower))
+	if err != nil {
+		return [2]int{}, err
+	}
+	u, err := strconv.Atoi(strings.TrimSpace(upper))
+	if err != nil {
+		return [2]int{}, err
+	}
+	if l < 100 || u > 599 || l > u {
+		return [2]int{}, errors.New("invalid status code range")
+	}
+	return [2]int{l, u}, nil
+}
+
+func isDumpPathMatch(pattern, p string) bool {
+	if strings.HasSuffix(pattern, "/**") {
+		prefix := pattern[:len(pattern)-3]
+		return p == prefix || strings.HasPrefix(p, prefix+"/")
+	}
+	matched, _ := path.Match(pattern, p)
+	return matched
+}
+
 func sortHeaderKeys(hdrs http.Header) []string {
 	var keys []string
 	for key := range hdrs {
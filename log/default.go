@@ -128,6 +128,11 @@ func AddHook(name string, hook HookFunc) error {
 	return dl.AddHook(name, hook)
 }
 
+// AddEnricher method is to add logger entry enricher function.
+func AddEnricher(name string, enricher EnricherFunc) error {
+	return dl.AddEnricher(name, enricher)
+}
+
 // WithFields method to add multiple key-value pairs into log.
 func WithFields(fields Fields) Loggerer {
 	return dl.WithFields(fields)
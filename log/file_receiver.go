@@ -5,11 +5,14 @@
 package log
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -41,6 +44,13 @@ type FileReceiver struct {
 	isUTC        bool
 	maxSize      int64
 	maxLines     int64
+	maxAge       time.Duration
+	openTime     time.Time
+	compress     bool
+	maxBackups   int
+	backupMaxAge time.Duration
+	bg           sync.WaitGroup
+	bgMu         sync.Mutex
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -83,6 +93,26 @@ func (f *FileReceiver) Init(cfg *config.Config) error {
 			return err
 		}
 		f.maxSize = maxSize
+	case "age":
+		maxAge, err := parseDuration(cfg, "log.rotate.age", "24h")
+		if err != nil {
+			return err
+		}
+		f.maxAge = maxAge
+	}
+
+	// Backup files compression and retention
+	f.compress = cfg.BoolDefault("log.rotate.compress", false)
+	f.maxBackups = cfg.IntDefault("log.rotate.max_backups", 0)
+	if f.maxBackups < 0 {
+		return fmt.Errorf("log: invalid value '%d' for 'log.rotate.max_backups'", f.maxBackups)
+	}
+	if cfg.IsExists("log.rotate.max_age") {
+		backupMaxAge, err := parseDuration(cfg, "log.rotate.max_age", "")
+		if err != nil {
+			return err
+		}
+		f.backupMaxAge = backupMaxAge
 	}
 
 	f.mu = sync.Mutex{}
@@ -122,7 +152,9 @@ func (f *FileReceiver) Log(entry *Entry) {
 	defer f.mu.Unlock()
 
 	if f.isRotate() {
-		_ = f.rotateFile()
+		if err := f.rotateFile(); err != nil {
+			fmt.Fprintf(os.Stderr, "log: unable to rotate file '%s': %v\n", f.filename, err)
+		}
 
 		// reset rotation values
 		f.openDay = f.getDay()
@@ -130,6 +162,15 @@ func (f *FileReceiver) Log(entry *Entry) {
 		f.stats.bytes = 0
 	}
 
+	// file could not be reopened on rotation, retry it so that entries
+	// are not lost
+	if f.isClosed {
+		if err := f.openFile(); err != nil {
+			fmt.Fprintf(os.Stderr, "log: unable to open file '%s': %v\n", f.filename, err)
+			return
+		}
+	}
+
 	var msg []byte
 	if f.formatter == textFmt {
 		msg = textFormatter(f.flags, entry)
@@ -162,6 +203,8 @@ func (f *FileReceiver) isRotate() bool {
 		return f.maxLines != 0 && f.stats.lines >= f.maxLines
 	case "size":
 		return f.maxSize != 0 && f.stats.bytes >= f.maxSize
+	case "age":
+		return time.Since(f.openTime) >= f.maxAge
 	default:
 		return false
 	}
@@ -170,14 +213,92 @@ func (f *FileReceiver) isRotate() bool {
 func (f *FileReceiver) rotateFile() error {
 	if _, err := os.Lstat(f.filename); err == nil {
 		f.close()
-		if err = os.Rename(f.filename, f.backupFileName()); err != nil {
+		backupName := f.backupFileName()
+		if err = os.Rename(f.filename, backupName); err != nil {
+			// continue logging into the current file
+			if oerr := f.openFile(); oerr != nil {
+				return oerr
+			}
 			return err
 		}
+
+		// compress and cleanup backup files in the background
+		if f.compress || f.maxBackups > 0 || f.backupMaxAge > 0 {
+			f.bg.Add(1)
+			go func() {
+				defer f.bg.Done()
+				f.bgMu.Lock()
+				defer f.bgMu.Unlock()
+				if f.compress {
+					if err := compressFile(backupName); err != nil {
+						fmt.Fprintf(os.Stderr, "log: unable to compress backup file '%s': %v\n", backupName, err)
+					}
+				}
+				f.removeBackups()
+			}()
+		}
 	}
 
 	return f.openFile()
 }
 
+// removeBackups method deletes the backup files beyond `log.rotate.max_backups`
+// and older than `log.rotate.max_age`.
+func (f *FileReceiver) removeBackups() {
+	if f.maxBackups == 0 && f.backupMaxAge == 0 {
+		return
+	}
+
+	backups := f.backupFiles()
+	for idx, b := range backups {
+		if (f.maxBackups > 0 && idx >= f.maxBackups) ||
+			(f.backupMaxAge > 0 && time.Since(b.t) > f.backupMaxAge) {
+			if err := os.Remove(b.name); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "log: unable to remove backup file '%s': %v\n", b.name, err)
+			}
+		}
+	}
+}
+
+type backupFile struct {
+	name string
+	t    time.Time
+}
+
+// backupFiles method returns the backup files of log file, recent first.
+func (f *FileReceiver) backupFiles() []backupFile {
+	dir := filepath.Dir(f.filename)
+	fileName := filepath.Base(f.filename)
+	ext := filepath.Ext(fileName)
+	prefix := ess.StripExt(fileName) + "-"
+
+	infos, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	loc := time.Local
+	if f.isUTC {
+		loc = time.UTC
+	}
+
+	var backups []backupFile
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimSuffix(name[len(prefix):], ".gz"), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, ts, loc)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{name: filepath.Join(dir, name), t: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].t.After(backups[j].t) })
+	return backups
+}
+
 func (f *FileReceiver) openFile() error {
 	dir := filepath.Dir(f.filename)
 	_ = ess.MkDirAll(dir, filePermission)
@@ -194,6 +315,7 @@ func (f *FileReceiver) openFile() error {
 
 	f.SetWriter(file)
 	f.isClosed = false
+	f.openTime = time.Now()
 	f.stats = &receiverStats{}
 	f.stats.bytes = fileStat.Size()
 	f.stats.lines = int64(ess.LineCntr(file))
@@ -220,6 +342,45 @@ func (f *FileReceiver) backupFileName() string {
 	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", baseName, t.Format(backupTimeFormat), ext))
 }
 
+// compressFile method gzips the given file into `<name>.gz` and removes
+// the given file.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer ess.CloseQuietly(src)
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, filePermission)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(dst)
+	if _, err = io.Copy(gw, src); err == nil {
+		err = gw.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(name + ".gz")
+		return err
+	}
+
+	ess.CloseQuietly(src)
+	return os.Remove(name)
+}
+
+func parseDuration(cfg *config.Config, key, defaultValue string) (time.Duration, error) {
+	v := cfg.StringDefault(key, defaultValue)
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("log: invalid value '%s' for '%s'", v, key)
+	}
+	return d, nil
+}
+
 func (f *FileReceiver) getDay() int {
 	if f.isUTC {
 		return time.Now().UTC().Day()
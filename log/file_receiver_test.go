@@ -7,7 +7,10 @@ package log
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/config"
 	"github.com/stretchr/testify/assert"
@@ -151,6 +154,100 @@ func TestFileLoggerIncorrectSizeValue(t *testing.T) {
 	assert.Equal(t, "format: invalid input '500kbs'", err.Error())
 }
 
+func TestFileLoggerRotateCompressAndRetention(t *testing.T) {
+	defer cleaupFiles("rotate-aah-filename*")
+	configStr := `
+  log {
+    receiver = "file"
+    level = "debug"
+    pattern = "%level:-5 %message"
+    file = "rotate-aah-filename.log"
+    rotate {
+      policy = "lines"
+      lines = 10
+      compress = true
+      max_backups = 2
+    }
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	fr := logger.receiver.(*FileReceiver)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 10; j++ {
+			logger.Info("rotate, compress and retain backup files")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	logger.Info("rotate, compress and retain backup files")
+	fr.bg.Wait()
+
+	backups := fr.backupFiles()
+	assert.Equal(t, 2, len(backups))
+	for _, b := range backups {
+		assert.True(t, strings.HasSuffix(b.name, ".log.gz"), b.name)
+	}
+	files, _ := filepath.Glob(filepath.Join(getPwd(), "rotate-aah-filename*"))
+	assert.Equal(t, 3, len(files))
+
+	// retention by age
+	fr.maxBackups = 0
+	fr.backupMaxAge = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	fr.removeBackups()
+	assert.Equal(t, 0, len(fr.backupFiles()))
+}
+
+func TestFileLoggerReopenAfterRotateFailure(t *testing.T) {
+	defer cleaupFiles("reopen-aah-filename*")
+	cfg, _ := config.ParseString(`
+  log {
+    receiver = "file"
+    pattern = "%level:-5 %message"
+    file = "reopen-aah-filename.log"
+  }
+  `)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	fr := logger.receiver.(*FileReceiver)
+
+	logger.Info("before rotate failure")
+
+	// file is closed and could not be reopened on rotation
+	fr.close()
+	logger.Info("after rotate failure")
+	assert.False(t, fr.isClosed)
+
+	b, err := ioutil.ReadFile(fr.filename)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(b), "before rotate failure"))
+	assert.True(t, strings.Contains(string(b), "after rotate failure"))
+}
+
+func TestFileLoggerRotateConfigErrors(t *testing.T) {
+	defer cleaupFiles("*.log")
+	for cfgStr, errMsg := range map[string]string{
+		`policy = "age"
+      age = "1x"`: "log: invalid value '1x' for 'log.rotate.age'",
+		`max_backups = -1`: "log: invalid value '-1' for 'log.rotate.max_backups'",
+		`max_age = "0s"`:   "log: invalid value '0s' for 'log.rotate.max_age'",
+	} {
+		cfg, _ := config.ParseString(`
+  log {
+    receiver = "file"
+    file = "daily-aah-filename.log"
+    rotate {
+      ` + cfgStr + `
+    }
+  }
+  `)
+		_, err := New(cfg)
+		assert.Equal(t, errMsg, err.Error())
+	}
+}
+
 func testFileLogger(t *testing.T, cfgStr string, loop int) {
 	cfg, _ := config.ParseString(cfgStr)
 	logger, err := New(cfg)
@@ -5,8 +5,10 @@
 package log
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -66,3 +68,62 @@ func TestLogHook(t *testing.T) {
 
 	time.Sleep(1 * time.Millisecond)
 }
+
+func TestLogAddEnricher(t *testing.T) {
+	err := AddEnricher("enricher1", func(e Entry) Entry { return e })
+	assert.Nil(t, err)
+
+	// Already added
+	err = AddEnricher("enricher1", func(e Entry) Entry { return e })
+	assert.Equal(t, "log: enricher name 'enricher1' is already added, skip it", err.Error())
+
+	// Nil enricher
+	err = AddEnricher("nilenricher", nil)
+	assert.Equal(t, ErrEnricherFuncIsNil, err)
+}
+
+func TestLogEnricher(t *testing.T) {
+	cfg, _ := config.ParseString(`
+  log {
+    receiver = "console"
+    level = "debug"
+    format = "json"
+  }
+  `)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	logger.SetRedactFields("user_email")
+	buf := new(bytes.Buffer)
+	logger.SetWriter(buf)
+
+	_ = logger.AddEnricher("version", func(e Entry) Entry {
+		e.Fields["app_version"] = "1.0.0"
+		return e
+	})
+	_ = logger.AddEnricher("user", func(e Entry) Entry {
+		assert.Equal(t, "1.0.0", e.Fields["app_version"])
+		e.Principal = "jeeva"
+		e.Fields = Fields{"user_email": "jeeva@example.com"}
+		return e
+	})
+
+	entries := make(chan Entry, 1)
+	_ = logger.AddHook("ship", func(e Entry) {
+		entries <- e
+	})
+
+	logger.WithField("traceid", "abc").Info("enriched entry")
+	out := buf.String()
+	assert.True(t, strings.Contains(out, `"principal":"jeeva"`))
+	assert.True(t, strings.Contains(out, `"user_email":"******"`))
+	assert.False(t, strings.Contains(out, "traceid"))
+
+	select {
+	case e := <-entries:
+		assert.Equal(t, "enriched entry", e.Message)
+		assert.Equal(t, "jeeva", e.Principal)
+		assert.Equal(t, "******", e.Fields["user_email"])
+	case <-time.After(time.Second):
+		t.Error("hook is not called")
+	}
+}
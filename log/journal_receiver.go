@@ -0,0 +1,170 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+const defaultJournalSocket = "/run/systemd/journal/socket"
+
+var _ Receiver = (*JournalReceiver)(nil)
+
+// JournalReceiver writes the log entry into systemd journald via native
+// protocol. Entry fields are written as journal fields in upper case, for
+// e.g.: field `user_id` as `USER_ID`.
+//
+//	log {
+//	  receiver = "journal"
+//	  journal {
+//	    identifier = "myapp"
+//	  }
+//	}
+type JournalReceiver struct {
+	out          io.Writer
+	flags        []ess.FmtFlagPart
+	isCallerInfo bool
+	identifier   string
+	mu           sync.Mutex
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// JournalReceiver methods
+//___________________________________
+
+// Init method initializes the journal receiver instance.
+func (j *JournalReceiver) Init(cfg *config.Config) error {
+	j.identifier = cfg.StringDefault("log.journal.identifier", filepath.Base(os.Args[0]))
+
+	w, err := newNetWriter(cfg, "log.journal", "unixgram",
+		cfg.StringDefault("log.journal.socket", defaultJournalSocket))
+	if err != nil {
+		return err
+	}
+	j.out = w
+	j.mu = sync.Mutex{}
+
+	return nil
+}
+
+// SetPattern method initializes the logger format pattern, journal uses it
+// only to find caller info is required.
+func (j *JournalReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	j.flags = flags
+	j.isCallerInfo = isCallerInfo(j.flags)
+	return nil
+}
+
+// SetWriter method sets the given writer into journal receiver.
+func (j *JournalReceiver) SetWriter(w io.Writer) {
+	j.out = w
+}
+
+// IsCallerInfo method returns true if log receiver is configured with caller info
+// otherwise false.
+func (j *JournalReceiver) IsCallerInfo() bool {
+	return j.isCallerInfo
+}
+
+// Log method writes the log entry into journald.
+func (j *JournalReceiver) Log(entry *Entry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+
+	writeJournalField(buf, "MESSAGE", entry.Message)
+	writeJournalField(buf, "PRIORITY", strconv.Itoa(levelToSyslogSeverity[entry.Level]))
+	writeJournalField(buf, "SYSLOG_IDENTIFIER", j.identifier)
+	if len(entry.File) > 0 {
+		writeJournalField(buf, "CODE_FILE", entry.File)
+		writeJournalField(buf, "CODE_LINE", strconv.Itoa(entry.Line))
+	}
+	for _, f := range [][2]string{
+		{"APP_NAME", entry.AppName},
+		{"INSTANCE_NAME", entry.InstanceName},
+		{"REQUEST_ID", entry.RequestID},
+		{"PRINCIPAL", entry.Principal},
+	} {
+		if len(f[1]) > 0 {
+			writeJournalField(buf, f[0], f[1])
+		}
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		if !entry.isSkipField(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if name := journalFieldName(k); len(name) > 0 {
+			writeJournalField(buf, name, fmt.Sprint(entry.Fields[k]))
+		}
+	}
+
+	_, _ = j.out.Write(buf.Bytes())
+}
+
+// Writer method returns the current log writer.
+func (j *JournalReceiver) Writer() io.Writer {
+	return j.out
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// writeJournalField method writes the field in journal native protocol,
+// multi-line value is written with its length in binary.
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if strings.IndexByte(value, '\n') == -1 {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+	} else {
+		buf.WriteByte('\n')
+		_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value)
+	}
+	buf.WriteByte('\n')
+}
+
+// journalFieldName method returns the valid journal field name, it allows
+// only upper case letters, digits and underscores, it cannot begin with
+// underscore and maximum 64 characters.
+func journalFieldName(name string) string {
+	name = strings.TrimLeft(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		}
+		return '_'
+	}, name), "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
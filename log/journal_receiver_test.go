@@ -0,0 +1,62 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalLoggerNativeProtocol(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("journald is not available on windows")
+	}
+
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	cfg, _ := config.ParseString(fmt.Sprintf(`
+  log {
+    receiver = "journal"
+    journal {
+      identifier = "myapp"
+      socket = "%s"
+    }
+  }
+  `, socket))
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	logger.WithField("reqid", "40139CA6368607085BF6").
+		WithFields(Fields{"user-id": 10, "_private": "yes"}).
+		Warn("line one\nline two")
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	b := make([]byte, 4096)
+	n, err := conn.Read(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "MESSAGE\n\x11\x00\x00\x00\x00\x00\x00\x00line one\nline two\n"+
+		"PRIORITY=4\n"+
+		"SYSLOG_IDENTIFIER=myapp\n"+
+		"REQUEST_ID=40139CA6368607085BF6\n"+
+		"PRIVATE=yes\n"+
+		"USER_ID=10\n", string(b[:n]))
+}
+
+func TestJournalFieldName(t *testing.T) {
+	assert.Equal(t, "USER_ID", journalFieldName("user.id"))
+	assert.Equal(t, "PRIVATE", journalFieldName("__private"))
+	assert.Equal(t, "ID", journalFieldName("1id"))
+	assert.Equal(t, "", journalFieldName("_"))
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Named logger methods
+//___________________________________
+
+// Named method creates a child logger with given name and name is logged as
+// field `logger`. Name of the nested named logger is joined by dot, for e.g.:
+// `payments.gateway`. Logging level of the named logger can be changed at
+// runtime using method `SetNamedLevel`.
+//
+//	pl := logger.Named("payments")
+//	pl.Debug("Charging the card")
+func (l *Logger) Named(name string) *Logger {
+	if len(l.name) > 0 {
+		name = l.name + "." + name
+	}
+	nl := l.New(Fields{"logger": name})
+	nl.name = name
+	return nl
+}
+
+// Name method returns the logger name, it's empty for unnamed logger.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetNamedLevel method sets the logging level of the named loggers created
+// from this logger without restart. Level applies to the nested names too,
+// for e.g.: `payments` applies to `payments.gateway` unless it has its own
+// level. Level is reverted after given ttl if it's greater than zero and
+// empty level removes the named level.
+func (l *Logger) SetNamedLevel(name, level string, ttl time.Duration) error {
+	if len(level) == 0 {
+		l.levels.remove(name)
+		return nil
+	}
+	levelFlag := levelByName(level)
+	if levelFlag == LevelUnknown {
+		return fmt.Errorf("log: unknown log level '%s'", level)
+	}
+	o := levelOverride{level: levelFlag}
+	if ttl > 0 {
+		o.expires = time.Now().Add(ttl)
+	}
+	l.levels.set(name, o)
+	return nil
+}
+
+// NamedLevels method returns the current named logging levels, expired ones
+// are not included.
+func (l *Logger) NamedLevels() map[string]string {
+	return l.levels.all()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// effectiveLevel method returns the named level of the logger if present
+// otherwise logger level.
+func (l *Logger) effectiveLevel() level {
+	if len(l.name) > 0 && l.levels != nil {
+		if lvl, found := l.levels.lookup(l.name); found {
+			return lvl
+		}
+	}
+	return l.level
+}
+
+type levelOverride struct {
+	level   level
+	expires time.Time
+}
+
+func (o levelOverride) isExpired(now time.Time) bool {
+	return !o.expires.IsZero() && now.After(o.expires)
+}
+
+// namedLevels holds the logging levels by logger name, it's shared between
+// the logger and its child loggers.
+type namedLevels struct {
+	sync.RWMutex
+	m map[string]levelOverride
+}
+
+func (n *namedLevels) set(name string, o levelOverride) {
+	n.Lock()
+	n.m[name] = o
+	n.Unlock()
+}
+
+func (n *namedLevels) remove(name string) {
+	n.Lock()
+	delete(n.m, name)
+	n.Unlock()
+}
+
+// lookup method returns the level of given name or its nearest parent name.
+func (n *namedLevels) lookup(name string) (level, bool) {
+	n.RLock()
+	defer n.RUnlock()
+	if len(n.m) == 0 {
+		return LevelUnknown, false
+	}
+	now := time.Now()
+	for {
+		if o, found := n.m[name]; found && !o.isExpired(now) {
+			return o.level, true
+		}
+		idx := strings.LastIndexByte(name, '.')
+		if idx == -1 {
+			return LevelUnknown, false
+		}
+		name = name[:idx]
+	}
+}
+
+func (n *namedLevels) all() map[string]string {
+	n.RLock()
+	defer n.RUnlock()
+	now := time.Now()
+	levels := make(map[string]string, len(n.m))
+	for name, o := range n.m {
+		if !o.isExpired(now) {
+			levels[name] = o.level.String()
+		}
+	}
+	return levels
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogNamedLevel(t *testing.T) {
+	cfg, _ := config.ParseString(`
+  log {
+    level = "info"
+    pattern = "%level %message %fields"
+  }
+  `)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := new(bytes.Buffer)
+	logger.SetWriter(buf)
+
+	pl := logger.Named("payments")
+	gl := pl.Named("gateway")
+	ol := logger.Named("orders")
+	assert.Equal(t, "payments", pl.Name())
+	assert.Equal(t, "payments.gateway", gl.Name())
+	assert.Equal(t, "", logger.Name())
+
+	gl.Debug("gateway debug before")
+	assert.Equal(t, "", buf.String())
+
+	assert.Nil(t, logger.SetNamedLevel("payments", "trace", 0))
+	assert.Equal(t, "TRACE", pl.Level())
+	assert.Equal(t, "TRACE", gl.Level())
+	assert.Equal(t, "INFO", ol.Level())
+	assert.Equal(t, "INFO", logger.Level())
+
+	gl.Trace("gateway trace")
+	gl.WithField("order_id", 10).Debug("gateway debug")
+	ol.Debug("orders debug")
+	logger.Debug("app debug")
+	out := buf.String()
+	assert.Contains(t, out, "TRACE gateway trace fields[logger: payments.gateway]")
+	assert.Contains(t, out, "DEBUG gateway debug")
+	assert.NotContains(t, out, "orders debug")
+	assert.NotContains(t, out, "app debug")
+
+	// nearest name takes precedence
+	assert.Nil(t, logger.SetNamedLevel("payments.gateway", "error", 0))
+	assert.Equal(t, "ERROR", gl.Level())
+	assert.Equal(t, "TRACE", pl.Level())
+	assert.Equal(t, map[string]string{"payments": "TRACE", "payments.gateway": "ERROR"}, logger.NamedLevels())
+
+	// remove
+	assert.Nil(t, logger.SetNamedLevel("payments.gateway", "", 0))
+	assert.Equal(t, "TRACE", gl.Level())
+
+	// ttl
+	assert.Nil(t, logger.SetNamedLevel("orders", "debug", 20*time.Millisecond))
+	assert.Equal(t, "DEBUG", ol.Level())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, "INFO", ol.Level())
+	assert.Equal(t, map[string]string{"payments": "TRACE"}, logger.NamedLevels())
+
+	err = logger.SetNamedLevel("payments", "verbose", 0)
+	assert.Equal(t, "log: unknown log level 'verbose'", err.Error())
+}
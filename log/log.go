@@ -3,8 +3,9 @@
 // license that can be found in the LICENSE file.
 
 // Package log simple logger and provides capabilities to fulfill application
-// use cases. It supports two receivers `console` and `file` and extensible
-// by interface and Hook.
+// use cases. It supports receivers `console`, `file`, `syslog` (RFC 5424),
+// `journal` (systemd journald) and `remote` (TCP/UDP) and extensible by
+// interface and Hook.
 //
 // Also provides standard logger crossover binding (drop-in replacement
 // for standard go logger) for unified logging.
@@ -15,6 +16,39 @@
 // 	// Output:
 // 	2016-07-03 19:22:11.504 INFO  Welcome to aah logger
 // 	2016-07-03 19:22:11.504 INFO  simple, flexible, logger
+//
+// File receiver rotates the log file by `daily`, `lines`, `size` or `age`
+// policy. Backup files can be gzip compressed and retained by count and age.
+//
+// 	log {
+// 	  receiver = "file"
+// 	  file = "myapp.log"
+// 	  rotate {
+// 	    policy = "size"
+// 	    size = "100mb"
+// 	    compress = true
+// 	    max_backups = 10
+// 	    max_age = "720h"
+// 	  }
+// 	}
+//
+// Syslog and remote receivers write asynchronously and reconnect with
+// exponential backoff on network failure.
+//
+// 	log {
+// 	  receiver = "remote"
+// 	  format = "json"
+// 	  remote {
+// 	    network = "tcp"
+// 	    address = "logs.example.com:5140"
+// 	    timeout = "5s"
+// 	    buffer_size = 1024
+// 	    backoff {
+// 	      min = "500ms"
+// 	      max = "30s"
+// 	    }
+// 	  }
+// 	}
 package log
 
 import (
@@ -35,6 +69,11 @@ type level uint8
 // HookFunc type is aah framework logger custom hook.
 type HookFunc func(e Entry)
 
+// EnricherFunc type is aah framework logger entry enricher. It's called
+// synchronously before the entry is logged and returned entry is logged and
+// passed on to the hooks, for e.g.: add trace id, user id, app version.
+type EnricherFunc func(e Entry) Entry
+
 // Log Level definition
 const (
 	LevelFatal level = iota
@@ -54,8 +93,14 @@ var (
 	// ErrHookFuncIsNil is returned when hook function is nil.
 	ErrHookFuncIsNil = errors.New("log: hook func is nil")
 
+	// ErrEnricherFuncIsNil is returned when enricher function is nil.
+	ErrEnricherFuncIsNil = errors.New("log: enricher func is nil")
+
 	filePermission = os.FileMode(0755)
 
+	// redactedValue is logged in place of redact field values.
+	redactedValue = "******"
+
 	// abstract it, can be unit tested
 	exit = os.Exit
 
@@ -67,12 +112,21 @@ type (
 	// format flags. Logger can be used simultaneously from multiple goroutines;
 	// it guarantees to serialize access to the Receivers.
 	Logger struct {
-		cfg      *config.Config
-		m        *sync.RWMutex
-		level    level
-		receiver Receiver
-		ctx      Fields
-		hooks    map[string]HookFunc
+		cfg       *config.Config
+		m         *sync.RWMutex
+		level     level
+		receiver  Receiver
+		ctx       Fields
+		hooks     map[string]HookFunc
+		enrichers []namedEnricher
+		redact    []string
+		name      string
+		levels    *namedLevels
+	}
+
+	namedEnricher struct {
+		name string
+		fn   EnricherFunc
 	}
 
 	// Receiver is the interface for pluggable log receiver.
@@ -156,6 +210,7 @@ func New(cfg *config.Config) (*Logger, error) {
 
 	logger.ctx = make(Fields)
 	logger.hooks = make(map[string]HookFunc)
+	logger.levels = &namedLevels{m: make(map[string]levelOverride)}
 
 	return logger, nil
 }
@@ -211,9 +266,42 @@ func (l *Logger) AddHook(name string, hook HookFunc) error {
 	return nil
 }
 
+// AddEnricher method is to add logger entry enricher function. Enrichers are
+// called in the added order before redaction, so enriched fields are
+// redacted too. Child logger inherits the enrichers added before its
+// creation.
+func (l *Logger) AddEnricher(name string, enricher EnricherFunc) error {
+	if enricher == nil {
+		return ErrEnricherFuncIsNil
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+	for _, e := range l.enrichers {
+		if e.name == name {
+			return fmt.Errorf("log: enricher name '%v' is already added, skip it", name)
+		}
+	}
+
+	l.enrichers = append(l.enrichers, namedEnricher{name: name, fn: enricher})
+	return nil
+}
+
+// SetRedactFields method sets the field names to redact, field values are
+// logged as `******`. Field name is matched by containment and case
+// insensitive, for e.g.: `email` matches `user_email`.
+func (l *Logger) SetRedactFields(names ...string) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.redact = make([]string, 0, len(names))
+	for _, n := range names {
+		l.redact = append(l.redact, strings.ToLower(n))
+	}
+}
+
 // Level method returns currently enabled logging level.
 func (l *Logger) Level() string {
-	return levelToLevelName[l.level]
+	return levelToLevelName[l.effectiveLevel()]
 }
 
 // SetLevel method sets the given logging level for the logger.
@@ -270,7 +358,7 @@ func (l *Logger) ToGoLogger() *slog.Logger {
 
 // Error logs message as `ERROR`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Error(v ...interface{}) {
-	if l.level >= LevelError {
+	if l.effectiveLevel() >= LevelError {
 		e := acquireEntry(l)
 		e.Error(v...)
 		releaseEntry(e)
@@ -279,7 +367,7 @@ func (l *Logger) Error(v ...interface{}) {
 
 // Errorf logs message as `ERROR`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.level >= LevelError {
+	if l.effectiveLevel() >= LevelError {
 		e := acquireEntry(l)
 		e.Errorf(format, v...)
 		releaseEntry(e)
@@ -288,7 +376,7 @@ func (l *Logger) Errorf(format string, v ...interface{}) {
 
 // Warn logs message as `WARN`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Warn(v ...interface{}) {
-	if l.level >= LevelWarn {
+	if l.effectiveLevel() >= LevelWarn {
 		e := acquireEntry(l)
 		e.Warn(v...)
 		releaseEntry(e)
@@ -297,7 +385,7 @@ func (l *Logger) Warn(v ...interface{}) {
 
 // Warnf logs message as `WARN`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	if l.level >= LevelWarn {
+	if l.effectiveLevel() >= LevelWarn {
 		e := acquireEntry(l)
 		e.Warnf(format, v...)
 		releaseEntry(e)
@@ -306,7 +394,7 @@ func (l *Logger) Warnf(format string, v ...interface{}) {
 
 // Info logs message as `INFO`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Info(v ...interface{}) {
-	if l.level >= LevelInfo {
+	if l.effectiveLevel() >= LevelInfo {
 		e := acquireEntry(l)
 		e.Info(v...)
 		releaseEntry(e)
@@ -315,7 +403,7 @@ func (l *Logger) Info(v ...interface{}) {
 
 // Infof logs message as `INFO`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.level >= LevelInfo {
+	if l.effectiveLevel() >= LevelInfo {
 		e := acquireEntry(l)
 		e.Infof(format, v...)
 		releaseEntry(e)
@@ -324,7 +412,7 @@ func (l *Logger) Infof(format string, v ...interface{}) {
 
 // Debug logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Debug(v ...interface{}) {
-	if l.level >= LevelDebug {
+	if l.effectiveLevel() >= LevelDebug {
 		e := acquireEntry(l)
 		e.Debug(v...)
 		releaseEntry(e)
@@ -333,7 +421,7 @@ func (l *Logger) Debug(v ...interface{}) {
 
 // Debugf logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.level >= LevelDebug {
+	if l.effectiveLevel() >= LevelDebug {
 		e := acquireEntry(l)
 		e.Debugf(format, v...)
 		releaseEntry(e)
@@ -342,7 +430,7 @@ func (l *Logger) Debugf(format string, v ...interface{}) {
 
 // Trace logs message as `TRACE`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Trace(v ...interface{}) {
-	if l.level >= LevelTrace {
+	if l.effectiveLevel() >= LevelTrace {
 		e := acquireEntry(l)
 		e.Trace(v...)
 		releaseEntry(e)
@@ -351,7 +439,7 @@ func (l *Logger) Trace(v ...interface{}) {
 
 // Tracef logs message as `TRACE`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Tracef(format string, v ...interface{}) {
-	if l.level >= LevelTrace {
+	if l.effectiveLevel() >= LevelTrace {
 		e := acquireEntry(l)
 		e.Tracef(format, v...)
 		releaseEntry(e)
@@ -450,37 +538,37 @@ func (l *Logger) Panicln(v ...interface{}) {
 
 // IsLevelInfo method returns true if log level is INFO otherwise false.
 func (l *Logger) IsLevelInfo() bool {
-	return l.level == LevelInfo
+	return l.effectiveLevel() == LevelInfo
 }
 
 // IsLevelError method returns true if log level is ERROR otherwise false.
 func (l *Logger) IsLevelError() bool {
-	return l.level == LevelError
+	return l.effectiveLevel() == LevelError
 }
 
 // IsLevelWarn method returns true if log level is WARN otherwise false.
 func (l *Logger) IsLevelWarn() bool {
-	return l.level == LevelWarn
+	return l.effectiveLevel() == LevelWarn
 }
 
 // IsLevelDebug method returns true if log level is DEBUG otherwise false.
 func (l *Logger) IsLevelDebug() bool {
-	return l.level == LevelDebug
+	return l.effectiveLevel() == LevelDebug
 }
 
 // IsLevelTrace method returns true if log level is TRACE otherwise false.
 func (l *Logger) IsLevelTrace() bool {
-	return l.level == LevelTrace
+	return l.effectiveLevel() == LevelTrace
 }
 
 // IsLevelFatal method returns true if log level is FATAL otherwise false.
 func (l *Logger) IsLevelFatal() bool {
-	return l.level == LevelFatal
+	return l.effectiveLevel() == LevelFatal
 }
 
 // IsLevelPanic method returns true if log level is PANIC otherwise false.
 func (l *Logger) IsLevelPanic() bool {
-	return l.level == LevelPanic
+	return l.effectiveLevel() == LevelPanic
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -488,6 +576,8 @@ func (l *Logger) IsLevelPanic() bool {
 //___________________________________
 
 func (l *Logger) output(e *Entry) {
+	l.enrich(e)
+	l.redactFields(e)
 	if l.receiver.IsCallerInfo() {
 		e.File, e.Line = fetchCallerInfo()
 	}
@@ -497,6 +587,36 @@ func (l *Logger) output(e *Entry) {
 	go l.executeHooks(*e)
 }
 
+func (l *Logger) enrich(e *Entry) {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	for _, en := range l.enrichers {
+		ne := en.fn(*e)
+		if ne.Fields == nil {
+			ne.Fields = make(Fields)
+		}
+		ne.logger = e.logger
+		*e = ne
+	}
+}
+
+func (l *Logger) redactFields(e *Entry) {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	if len(l.redact) == 0 {
+		return
+	}
+	for k := range e.Fields {
+		name := strings.ToLower(k)
+		for _, r := range l.redact {
+			if strings.Contains(name, r) {
+				e.Fields[k] = redactedValue
+				break
+			}
+		}
+	}
+}
+
 func (l *Logger) executeHooks(e Entry) {
 	l.m.RLock()
 	defer l.m.RUnlock()
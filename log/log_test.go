@@ -5,6 +5,7 @@
 package log
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -76,6 +77,25 @@ func TestMisc(t *testing.T) {
 	stdLogger.Print("This is aah logger binds go logger")
 }
 
+func TestLogRedactFields(t *testing.T) {
+	configStr := `
+  log {
+    pattern = "%level %message %fields"
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+	logger.SetRedactFields("Email", "phone")
+
+	logger.WithFields(Fields{"user_email": "jeeva@example.com", "phone": "1234", "id": 10}).Info("user signup")
+	assert.Contains(t, buf.String(), "user_email: ******")
+	assert.Contains(t, buf.String(), "phone: ******")
+	assert.Contains(t, buf.String(), "id: 10")
+}
+
 func testPanic(logger *Logger, method, msg string) {
 	defer func() {
 		if r := recover(); r != nil {
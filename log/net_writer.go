@@ -0,0 +1,120 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+// netWriter writes the log messages into network connection asynchronously,
+// so logging does not block on network. On connect or write failure it
+// reconnects with exponential backoff, messages are dropped while the queue
+// is full.
+type netWriter struct {
+	dropped  int64 // first field, 64-bit aligned for atomic
+	network  string
+	address  string
+	timeout  time.Duration
+	minDelay time.Duration
+	maxDelay time.Duration
+	delay    time.Duration
+	conn     net.Conn
+	queue    chan []byte
+}
+
+// newNetWriter method creates the network writer from config of given key
+// prefix, for e.g.: `log.remote`.
+//
+//	timeout = "5s"
+//	buffer_size = 1024
+//	backoff {
+//	  min = "500ms"
+//	  max = "30s"
+//	}
+func newNetWriter(cfg *config.Config, keyPrefix, network, address string) (*netWriter, error) {
+	w := &netWriter{network: network, address: address}
+
+	var err error
+	if w.timeout, err = parseDuration(cfg, keyPrefix+".timeout", "5s"); err != nil {
+		return nil, err
+	}
+	if w.minDelay, err = parseDuration(cfg, keyPrefix+".backoff.min", "500ms"); err != nil {
+		return nil, err
+	}
+	if w.maxDelay, err = parseDuration(cfg, keyPrefix+".backoff.max", "30s"); err != nil {
+		return nil, err
+	}
+	if w.maxDelay < w.minDelay {
+		return nil, fmt.Errorf("log: '%s.backoff.min' must not be greater than 'max'", keyPrefix)
+	}
+
+	bufferSize := cfg.IntDefault(keyPrefix+".buffer_size", 1024)
+	if bufferSize <= 0 {
+		return nil, fmt.Errorf("log: invalid value '%d' for '%s.buffer_size'", bufferSize, keyPrefix)
+	}
+	w.queue = make(chan []byte, bufferSize)
+
+	go w.run()
+	return w, nil
+}
+
+// Write method queues the copy of given message and returns immediately.
+func (w *netWriter) Write(b []byte) (int, error) {
+	msg := make([]byte, len(b))
+	copy(msg, b)
+	select {
+	case w.queue <- msg:
+	default:
+		atomic.AddInt64(&w.dropped, 1)
+	}
+	return len(b), nil
+}
+
+func (w *netWriter) run() {
+	for msg := range w.queue {
+		for !w.send(msg) {
+			time.Sleep(w.delay)
+		}
+	}
+}
+
+func (w *netWriter) send(msg []byte) bool {
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.network, w.address, w.timeout)
+		if err != nil {
+			w.backoff()
+			return false
+		}
+		w.conn = conn
+		if dropped := atomic.SwapInt64(&w.dropped, 0); dropped > 0 {
+			fmt.Fprintf(os.Stderr, "log: connected to '%s', %d messages dropped\n", w.address, dropped)
+		}
+		w.delay = 0
+	}
+
+	_ = w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	if _, err := w.conn.Write(msg); err != nil {
+		ess.CloseQuietly(w.conn)
+		w.conn = nil
+		w.backoff()
+		return false
+	}
+	return true
+}
+
+func (w *netWriter) backoff() {
+	if w.delay == 0 {
+		w.delay = w.minDelay
+	} else if w.delay *= 2; w.delay > w.maxDelay {
+		w.delay = w.maxDelay
+	}
+}
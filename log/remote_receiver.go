@@ -0,0 +1,111 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+var _ Receiver = (*RemoteReceiver)(nil)
+
+// RemoteReceiver writes the log entry into remote TCP or UDP endpoint, one
+// entry per line (TCP) or datagram (UDP). Connection is re-established
+// with backoff on failure.
+//
+//	log {
+//	  receiver = "remote"
+//	  remote {
+//	    network = "tcp"
+//	    address = "logs.example.com:5140"
+//	  }
+//	}
+type RemoteReceiver struct {
+	out          io.Writer
+	formatter    string
+	flags        []ess.FmtFlagPart
+	isCallerInfo bool
+	mu           sync.Mutex
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// RemoteReceiver methods
+//___________________________________
+
+// Init method initializes the remote receiver instance.
+func (r *RemoteReceiver) Init(cfg *config.Config) error {
+	network := cfg.StringDefault("log.remote.network", "tcp")
+	if !(network == "tcp" || network == "udp") {
+		return fmt.Errorf("log: unsupported remote network '%s'", network)
+	}
+	address := cfg.StringDefault("log.remote.address", "")
+	if ess.IsStrEmpty(address) {
+		return errors.New("log: 'log.remote.address' is required")
+	}
+
+	r.formatter = cfg.StringDefault("log.format", "text")
+	if !(r.formatter == textFmt || r.formatter == jsonFmt) {
+		return fmt.Errorf("log: unsupported format '%s'", r.formatter)
+	}
+
+	w, err := newNetWriter(cfg, "log.remote", network, address)
+	if err != nil {
+		return err
+	}
+	r.out = w
+	r.mu = sync.Mutex{}
+
+	return nil
+}
+
+// SetPattern method initializes the logger format pattern.
+func (r *RemoteReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	r.flags = flags
+	if r.formatter == textFmt {
+		r.isCallerInfo = isCallerInfo(r.flags)
+	}
+	return nil
+}
+
+// SetWriter method sets the given writer into remote receiver.
+func (r *RemoteReceiver) SetWriter(w io.Writer) {
+	r.out = w
+}
+
+// IsCallerInfo method returns true if log receiver is configured with caller info
+// otherwise false.
+func (r *RemoteReceiver) IsCallerInfo() bool {
+	return r.isCallerInfo
+}
+
+// Log method writes the log entry into remote endpoint.
+func (r *RemoteReceiver) Log(entry *Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var msg []byte
+	if r.formatter == textFmt {
+		msg = textFormatter(r.flags, entry)
+	} else {
+		msg, _ = json.Marshal(entry)
+		msg = append(msg, '\n')
+	}
+	_, _ = r.out.Write(msg)
+}
+
+// Writer method returns the current log writer.
+func (r *RemoteReceiver) Writer() io.Writer {
+	return r.out
+}
@@ -0,0 +1,174 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteLoggerTCPReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	cfg, _ := config.ParseString(fmt.Sprintf(`
+  log {
+    receiver = "remote"
+    level = "debug"
+    format = "json"
+    remote {
+      address = "%s"
+      backoff {
+        min = "10ms"
+        max = "40ms"
+      }
+    }
+  }
+  `, ln.Addr()))
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	lines := make(chan string, 100)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err == nil {
+				lines <- line
+			}
+			// drop the connection after first line, receiver reconnects
+			_ = conn.Close()
+		}
+	}()
+
+	logger.WithField("reqid", "40139CA6368607085BF6").Info("first message")
+	select {
+	case line := <-lines:
+		var m map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &m))
+		assert.Equal(t, "INFO", m["level"])
+		assert.Equal(t, "first message", m["message"])
+		assert.Equal(t, "40139CA6368607085BF6", m["request_id"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("remote receiver did not deliver the message")
+	}
+
+	// messages are delivered after reconnect
+	deadline := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		logger.Infof("retry message %d", i)
+		select {
+		case line := <-lines:
+			assert.True(t, strings.Contains(line, "retry message"))
+			return
+		case <-deadline:
+			t.Fatal("remote receiver did not reconnect")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func TestRemoteLoggerUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer pc.Close()
+
+	cfg, _ := config.ParseString(fmt.Sprintf(`
+  log {
+    receiver = "remote"
+    pattern = "%%level:-5 %%message"
+    remote {
+      network = "udp"
+      address = "%s"
+    }
+  }
+  `, pc.LocalAddr()))
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	logger.Warn("udp message")
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	b := make([]byte, 1024)
+	n, _, err := pc.ReadFrom(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "WARN  udp message \n", string(b[:n]))
+}
+
+func TestRemoteLoggerConfigErrors(t *testing.T) {
+	for _, tc := range []struct {
+		cfg string
+		err string
+	}{
+		{`
+		  log {
+		    receiver = "remote"
+		  }
+		`, "log: 'log.remote.address' is required"},
+		{`
+		  log {
+		    receiver = "remote"
+		    remote {
+		      network = "unix"
+		      address = "/tmp/log.sock"
+		    }
+		  }
+		`, "log: unsupported remote network 'unix'"},
+		{`
+		  log {
+		    receiver = "remote"
+		    format = "xml"
+		    remote {
+		      address = "127.0.0.1:5140"
+		    }
+		  }
+		`, "log: unsupported format 'xml'"},
+		{`
+		  log {
+		    receiver = "remote"
+		    remote {
+		      address = "127.0.0.1:5140"
+		      timeout = "5"
+		    }
+		  }
+		`, "log: invalid value '5' for 'log.remote.timeout'"},
+		{`
+		  log {
+		    receiver = "remote"
+		    remote {
+		      address = "127.0.0.1:5140"
+		      buffer_size = 0
+		    }
+		  }
+		`, "log: invalid value '0' for 'log.remote.buffer_size'"},
+		{`
+		  log {
+		    receiver = "remote"
+		    remote {
+		      address = "127.0.0.1:5140"
+		      backoff {
+		        min = "1m"
+		      }
+		    }
+		  }
+		`, "log: 'log.remote.backoff.min' must not be greater than 'max'"},
+	} {
+		cfg, err := config.ParseString(tc.cfg)
+		assert.Nil(t, err)
+		_, err = New(cfg)
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+var (
+	// syslogFacilities is the facility codes of RFC 5424, section 6.2.1.
+	syslogFacilities = map[string]int{
+		"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+		"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+		"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+		"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+	}
+
+	// levelToSyslogSeverity maps the log level to RFC 5424 severity.
+	levelToSyslogSeverity = []int{
+		LevelFatal: 2, // critical
+		LevelPanic: 2, // critical
+		LevelError: 3, // error
+		LevelWarn:  4, // warning
+		LevelInfo:  6, // informational
+		LevelDebug: 7, // debug
+		LevelTrace: 7, // debug
+	}
+
+	// syslogSockets is the well known local syslog sockets.
+	syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+	_ Receiver = (*SyslogReceiver)(nil)
+)
+
+// SyslogReceiver writes the log entry into local or remote syslog in
+// RFC 5424 format. Remote TCP messages are framed by newline.
+//
+//	log {
+//	  receiver = "syslog"
+//	  syslog {
+//	    network = "udp"
+//	    address = "syslog.example.com:514"
+//	    facility = "local0"
+//	    tag = "myapp"
+//	  }
+//	}
+type SyslogReceiver struct {
+	out          io.Writer
+	formatter    string
+	flags        []ess.FmtFlagPart
+	isCallerInfo bool
+	isTCP        bool
+	facility     int
+	hostname     string
+	tag          string
+	pid          string
+	mu           sync.Mutex
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// SyslogReceiver methods
+//___________________________________
+
+// Init method initializes the syslog receiver instance. Without network
+// config it writes into local syslog socket.
+func (s *SyslogReceiver) Init(cfg *config.Config) error {
+	network := cfg.StringDefault("log.syslog.network", "unixgram")
+	address := cfg.StringDefault("log.syslog.address", "")
+	switch network {
+	case "tcp", "udp":
+		if ess.IsStrEmpty(address) {
+			return errors.New("log: 'log.syslog.address' is required for network syslog")
+		}
+	case "unix", "unixgram":
+		if ess.IsStrEmpty(address) {
+			for _, v := range syslogSockets {
+				if ess.IsFileExists(v) {
+					address = v
+					break
+				}
+			}
+			if ess.IsStrEmpty(address) {
+				return errors.New("log: local syslog socket not found, configure 'log.syslog.address'")
+			}
+		}
+	default:
+		return fmt.Errorf("log: unsupported syslog network '%s'", network)
+	}
+	s.isTCP = network == "tcp"
+
+	facilityName := cfg.StringDefault("log.syslog.facility", "user")
+	facility, found := syslogFacilities[strings.ToLower(facilityName)]
+	if !found {
+		return fmt.Errorf("log: unsupported syslog facility '%s'", facilityName)
+	}
+	s.facility = facility
+
+	s.formatter = cfg.StringDefault("log.format", "text")
+	if !(s.formatter == textFmt || s.formatter == jsonFmt) {
+		return fmt.Errorf("log: unsupported format '%s'", s.formatter)
+	}
+
+	s.tag = syslogHeaderValue(cfg.StringDefault("log.syslog.tag", ""), 48)
+	if s.hostname, _ = os.Hostname(); len(s.hostname) == 0 {
+		s.hostname = "-"
+	}
+	s.hostname = syslogHeaderValue(s.hostname, 255)
+	s.pid = strconv.Itoa(os.Getpid())
+
+	w, err := newNetWriter(cfg, "log.syslog", network, address)
+	if err != nil {
+		return err
+	}
+	s.out = w
+	s.mu = sync.Mutex{}
+
+	return nil
+}
+
+// SetPattern method initializes the logger format pattern.
+func (s *SyslogReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	s.flags = flags
+	if s.formatter == textFmt {
+		s.isCallerInfo = isCallerInfo(s.flags)
+	}
+	return nil
+}
+
+// SetWriter method sets the given writer into syslog receiver.
+func (s *SyslogReceiver) SetWriter(w io.Writer) {
+	s.out = w
+}
+
+// IsCallerInfo method returns true if log receiver is configured with caller info
+// otherwise false.
+func (s *SyslogReceiver) IsCallerInfo() bool {
+	return s.isCallerInfo
+}
+
+// Log method writes the log entry into syslog.
+func (s *SyslogReceiver) Log(entry *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var msg []byte
+	if s.formatter == textFmt {
+		msg = bytes.TrimRight(textFormatter(s.flags, entry), " \n")
+	} else {
+		msg, _ = json.Marshal(entry)
+	}
+
+	tag := s.tag
+	if len(tag) == 0 {
+		tag = syslogHeaderValue(entry.AppName, 48)
+	}
+	if len(tag) == 0 {
+		tag = syslogHeaderValue(filepath.Base(os.Args[0]), 48)
+	}
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %s - - ", s.facility*8+levelToSyslogSeverity[entry.Level],
+		entry.Time.Format(syslogTimeFormat), s.hostname, tag, s.pid)
+	buf.Write(msg)
+	if s.isTCP {
+		buf.WriteByte('\n')
+	}
+	_, _ = s.out.Write(buf.Bytes())
+}
+
+// Writer method returns the current log writer.
+func (s *SyslogReceiver) Writer() io.Writer {
+	return s.out
+}
+
+// syslogHeaderValue method returns the printable US-ASCII value without
+// space for syslog header field, truncated to given length.
+func syslogHeaderValue(v string, maxLen int) string {
+	v = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, v)
+	if len(v) > maxLen {
+		v = v[:maxLen]
+	}
+	return v
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogLoggerRFC5424(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer pc.Close()
+
+	cfg, _ := config.ParseString(fmt.Sprintf(`
+  log {
+    receiver = "syslog"
+    pattern = "%%message %%fields"
+    syslog {
+      network = "udp"
+      address = "%s"
+      facility = "local0"
+    }
+  }
+  `, pc.LocalAddr()))
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	read := func() string {
+		_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		b := make([]byte, 1024)
+		n, _, err := pc.ReadFrom(b)
+		assert.Nil(t, err)
+		return string(b[:n])
+	}
+
+	// local0 (16) * 8 + error (3)
+	logger.WithField("appname", "my app").WithField("user", "jeeva").Error("syslog message")
+	re := regexp.MustCompile(`^<131>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}\S+ \S+ myapp ` +
+		strconv.Itoa(os.Getpid()) + ` - - syslog message fields\[user: jeeva\]$`)
+	msg := read()
+	assert.True(t, re.MatchString(msg), msg)
+
+	// local0 (16) * 8 + debug (7)
+	logger.Debug("debug message")
+	assert.Regexp(t, `^<135>1 .* - - debug message$`, read())
+}
+
+func TestSyslogLoggerConfigErrors(t *testing.T) {
+	for _, tc := range []struct {
+		cfg string
+		err string
+	}{
+		{`
+		  log {
+		    receiver = "syslog"
+		    syslog {
+		      network = "tcp"
+		    }
+		  }
+		`, "log: 'log.syslog.address' is required for network syslog"},
+		{`
+		  log {
+		    receiver = "syslog"
+		    syslog {
+		      network = "http"
+		    }
+		  }
+		`, "log: unsupported syslog network 'http'"},
+		{`
+		  log {
+		    receiver = "syslog"
+		    syslog {
+		      network = "udp"
+		      address = "127.0.0.1:514"
+		      facility = "local9"
+		    }
+		  }
+		`, "log: unsupported syslog facility 'local9'"},
+	} {
+		cfg, err := config.ParseString(tc.cfg)
+		assert.Nil(t, err)
+		_, err = New(cfg)
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
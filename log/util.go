@@ -101,6 +101,12 @@ func getReceiverByName(name string) Receiver {
 		return &FileReceiver{}
 	case "CONSOLE":
 		return &ConsoleReceiver{}
+	case "SYSLOG":
+		return &SyslogReceiver{}
+	case "JOURNAL":
+		return &JournalReceiver{}
+	case "REMOTE":
+		return &RemoteReceiver{}
 	default:
 		return nil
 	}
@@ -0,0 +1,233 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// NamedLogger method creates a child logger with given name from aah
+// application logger, its logging level can be changed at runtime using
+// `SetLogLevel`. Named logger is bound to the current application logger,
+// create it again on `OnConfigHotReload`.
+//
+//	pl := aah.App().NamedLogger("payments")
+func (a *Application) NamedLogger(name string) log.Loggerer {
+	return a.Log().(*log.Logger).Named(name)
+}
+
+// SetLogLevel method sets the logging level of given scope without restart.
+// Scope begins with `/` is the request path prefix and trailing `*` is
+// optional, for e.g.: `/api/payments/*`, it applies to the request logger
+// `ctx.Log()`. Otherwise scope is the logger name, see `NamedLogger`.
+//
+// Level is reverted after given ttl if it's greater than zero and empty level
+// removes the scope level. Levels are reset to configured values of
+// `log.level_overrides` on hot-reload.
+//
+//	err := aah.App().SetLogLevel("/api/payments/*", "trace", 15*time.Minute)
+func (a *Application) SetLogLevel(scope, level string, ttl time.Duration) error {
+	scope = strings.TrimSpace(scope)
+	if len(scope) == 0 {
+		return errors.New("aah: log level scope is empty")
+	}
+	if scope[0] != '/' {
+		return a.Log().(*log.Logger).SetNamedLevel(scope, level, ttl)
+	}
+
+	prefix := strings.TrimSuffix(scope, "*")
+	if len(level) == 0 {
+		a.logLevels.remove(prefix)
+		return nil
+	}
+	rl := a.Log().(*log.Logger).New(nil)
+	if err := rl.SetLevel(level); err != nil {
+		return err
+	}
+	rule := &routeLogLevel{prefix: prefix, logger: rl}
+	if ttl > 0 {
+		rule.expires = time.Now().Add(ttl)
+	}
+	a.logLevels.set(rule)
+	return nil
+}
+
+// LogLevels method returns the current logging levels of request path
+// prefixes and logger names, expired ones are not included.
+func (a *Application) LogLevels() map[string]string {
+	levels := a.Log().(*log.Logger).NamedLevels()
+	for prefix, level := range a.logLevels.all() {
+		levels[prefix+"*"] = level
+	}
+	return levels
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initLogLevels method initializes the logging levels of request path
+// prefixes and logger names from config `log.level_overrides`, value format
+// is `scope=level`.
+//
+//	log {
+//	  level_overrides = ["/api/payments/*=trace", "payments=debug"]
+//	}
+func (a *Application) initLogLevels() error {
+	a.logLevels = &routeLogLevels{}
+	keyPrefix := "log.level_overrides"
+	overrides, _ := a.Config().StringList(keyPrefix)
+	for _, o := range overrides {
+		idx := strings.LastIndexByte(o, '=')
+		if idx <= 0 || idx == len(o)-1 {
+			return fmt.Errorf("'%s' value '%s' is invalid, expected scope=level", keyPrefix, o)
+		}
+		if err := a.SetLogLevel(o[:idx], strings.TrimSpace(o[idx+1:]), 0); err != nil {
+			return fmt.Errorf("'%s' value '%s' is invalid: %v", keyPrefix, o, err)
+		}
+	}
+	return nil
+}
+
+func (a *Application) setLogLevel(scope, level string, ttl time.Duration, o *toggleOrigin) error {
+	if err := a.SetLogLevel(scope, level, ttl); err != nil {
+		return err
+	}
+
+	params := map[string]string{"scope": scope, "level": level}
+	if ttl > 0 {
+		params["ttl"] = ttl.String()
+	}
+	if len(level) == 0 {
+		level = "default"
+	}
+	a.Log().Warnf("Log level of '%s' is set to %s by %s", scope, strings.ToUpper(level), o.subject)
+	a.auditRuntimeChange("log_level:"+scope, params, o)
+	return nil
+}
+
+// serveLogLevels method replies the logging levels on `GET` and sets the
+// level on `POST` with form or query parameters `scope`, `level` and
+// optional `ttl`, for e.g.: `scope=/api/payments/*&level=trace&ttl=15m`.
+func (rc *runtimeControl) serveLogLevels(ctx *Context) {
+	if ctx.Req.Method == ahttp.MethodPost {
+		r := ctx.Req.Unwrap()
+		if err := r.ParseForm(); err != nil {
+			ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, err.Error()))
+			return
+		}
+
+		var ttl time.Duration
+		if v := r.Form.Get("ttl"); len(v) > 0 {
+			var err error
+			if ttl, err = time.ParseDuration(v); err != nil || ttl < 0 {
+				ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest,
+					fmt.Sprintf("invalid value '%s' for 'ttl'", v)))
+				return
+			}
+		}
+
+		if err := ctx.a.setLogLevel(r.Form.Get("scope"), r.Form.Get("level"), ttl, rc.origin(ctx)); err != nil {
+			ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, err.Error()))
+			return
+		}
+	}
+
+	ctx.Reply().Ok().
+		NoCache().
+		JSON(ctx.a.LogLevels())
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Request path log levels
+//______________________________________________________________________________
+
+type routeLogLevel struct {
+	prefix  string
+	logger  *log.Logger
+	expires time.Time
+}
+
+// routeLogLevels holds the loggers of request path prefixes, sorted by
+// prefix length in descending order to match the longest prefix first.
+type routeLogLevels struct {
+	sync.RWMutex
+	rules []*routeLogLevel
+}
+
+func (rl *routeLogLevels) set(rule *routeLogLevel) {
+	rl.Lock()
+	defer rl.Unlock()
+	rules := make([]*routeLogLevel, 0, len(rl.rules)+1)
+	for _, r := range rl.rules {
+		if r.prefix != rule.prefix {
+			rules = append(rules, r)
+		}
+	}
+	rules = append(rules, rule)
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].prefix) > len(rules[j].prefix) })
+	rl.rules = rules
+}
+
+func (rl *routeLogLevels) remove(prefix string) {
+	rl.Lock()
+	defer rl.Unlock()
+	rules := make([]*routeLogLevel, 0, len(rl.rules))
+	for _, r := range rl.rules {
+		if r.prefix != prefix {
+			rules = append(rules, r)
+		}
+	}
+	rl.rules = rules
+}
+
+// logger method returns the logger of longest matching path prefix,
+// otherwise nil.
+func (rl *routeLogLevels) logger(path string) log.Loggerer {
+	if rl == nil {
+		return nil
+	}
+	rl.RLock()
+	defer rl.RUnlock()
+	if len(rl.rules) == 0 {
+		return nil
+	}
+	now := time.Now()
+	for _, r := range rl.rules {
+		if strings.HasPrefix(path, r.prefix) && (r.expires.IsZero() || now.Before(r.expires)) {
+			return r.logger
+		}
+	}
+	return nil
+}
+
+func (rl *routeLogLevels) all() map[string]string {
+	levels := make(map[string]string)
+	if rl == nil {
+		return levels
+	}
+	rl.RLock()
+	defer rl.RUnlock()
+	now := time.Now()
+	for _, r := range rl.rules {
+		if r.expires.IsZero() || now.Before(r.expires) {
+			levels[r.prefix] = r.logger.Level()
+		}
+	}
+	return levels
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevels(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `
+	log {
+	  level = "info"
+	  pattern = "%level %message"
+	  level_overrides = ["/api/payments/*=trace", "payments=debug"]
+	}
+	`))
+	assert.Nil(t, a.initLog())
+	buf := new(bytes.Buffer)
+	a.Log().(*log.Logger).SetWriter(buf)
+
+	assert.Equal(t, map[string]string{"/api/payments/*": "TRACE", "payments": "DEBUG"}, a.LogLevels())
+
+	logFor := func(path string) log.Loggerer {
+		ctx := newContext(nil, httptest.NewRequest(ahttp.MethodGet, path, nil))
+		ctx.a = a
+		return ctx.Log()
+	}
+	logFor("/api/payments/123").Trace("payments trace")
+	logFor("/api/orders/123").Debug("orders debug")
+	a.NamedLogger("payments").Debug("named payments debug")
+	a.NamedLogger("orders").Debug("named orders debug")
+	assert.Contains(t, buf.String(), "TRACE payments trace")
+	assert.Contains(t, buf.String(), "DEBUG named payments debug")
+	assert.NotContains(t, buf.String(), "orders debug")
+
+	// longest prefix, ttl and remove
+	assert.Nil(t, a.SetLogLevel("/api/payments/refunds", "error", 20*time.Millisecond))
+	assert.Equal(t, "ERROR", logFor("/api/payments/refunds/1").(*log.Logger).Level())
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, "TRACE", logFor("/api/payments/refunds/1").(*log.Logger).Level())
+	assert.Nil(t, a.SetLogLevel("/api/payments/*", "", 0))
+	assert.Equal(t, "INFO", logFor("/api/payments/1").(*log.Logger).Level())
+	assert.Equal(t, map[string]string{"payments": "DEBUG"}, a.LogLevels())
+
+	assert.Equal(t, "log: unknown log level 'verbose'", a.SetLogLevel("/api", "verbose", 0).Error())
+	assert.Equal(t, "aah: log level scope is empty", a.SetLogLevel(" ", "info", 0).Error())
+
+	// hot-reload resets levels to configured values
+	assert.Nil(t, a.initLog())
+	assert.Equal(t, map[string]string{"/api/payments/*": "TRACE", "payments": "DEBUG"}, a.LogLevels())
+
+	assert.Nil(t, setTestConfig(a, `
+	log {
+	  level_overrides = ["payments"]
+	}
+	`))
+	assert.Equal(t, "'log.level_overrides' value 'payments' is invalid, expected scope=level", a.initLog().Error())
+
+	assert.Nil(t, setTestConfig(a, `
+	log {
+	  level_overrides = ["/api=verbose"]
+	}
+	`))
+	assert.Equal(t, "'log.level_overrides' value '/api=verbose' is invalid: log: unknown log level 'verbose'", a.initLog().Error())
+}
+
+func TestRuntimeControlLogLevels(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Runtime Control Log Levels]: %s", ts.URL)
+
+	sink := &testAuditSink{records: make(chan *AuditRecord, 10)}
+	assert.Nil(t, ts.app.AddAuditSink("loglevel_test", sink))
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	runtime {
+	  control {
+	    enable = true
+	  }
+	}
+	security {
+	  admin_auth {
+	    tokens = ["admintoken"]
+	  }
+	}
+	server {
+	  audit {
+	    enable = true
+	    routes = ["form_submit"]
+	    sinks = ["loglevel_test"]
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initAdmin())
+	assert.Nil(t, ts.app.initAudit())
+	defer ts.app.stopAudit()
+	assert.Nil(t, ts.app.initRuntimeControl())
+
+	send := func(method, query string) (*http.Response, map[string]string) {
+		req, _ := http.NewRequest(method, ts.URL+"/_aah/runtime/log_levels"+query, nil)
+		req.Header.Set(ahttp.HeaderAuthorization, "Bearer admintoken")
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		levels := map[string]string{}
+		if resp.StatusCode == http.StatusOK {
+			assert.Nil(t, json.NewDecoder(resp.Body).Decode(&levels))
+		}
+		return resp, levels
+	}
+
+	resp, err := http.Get(ts.URL + "/_aah/runtime/log_levels")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, levels := send(http.MethodPost, "?scope=/api/payments/*&level=trace&ttl=15m&actor=ops")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "TRACE", levels["/api/payments/*"])
+
+	r := waitAuditRecord(t, sink)
+	assert.Equal(t, "log_level:/api/payments/*", r.Entity)
+	assert.Equal(t, "ops", r.Subject)
+	assert.Equal(t, "trace", r.Params["level"])
+	assert.Equal(t, "15m0s", r.Params["ttl"])
+
+	resp, _ = send(http.MethodPost, "?scope=payments&level=verbose")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = send(http.MethodPost, "?scope=payments&level=debug&ttl=soon")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, levels = send(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, map[string]string{"/api/payments/*": "TRACE"}, levels)
+}
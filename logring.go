@@ -0,0 +1,187 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// flushRequestLogs method flushes the buffered access log and dump log
+// messages on server shutdown.
+func (a *Application) flushRequestLogs(ctx context.Context) error {
+	if a.accessLog != nil {
+		if err := a.accessLog.ring.Close(ctx); err != nil {
+			return err
+		}
+	}
+	if a.dumpLog != nil {
+		return a.dumpLog.ring.Close(ctx)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Log ring buffer
+//______________________________________________________________________________
+
+const defaultLogRingSize = 512
+
+type logRingSlot struct {
+	seq   uint64
+	value interface{}
+}
+
+// logRing is a bounded lock-free ring buffer of log messages with dedicated
+// writer goroutine, so that request goroutines are not blocked on disk I/O
+// of access log and dump log. Ring size is rounded up to power of two.
+//
+// Message is written by the caller goroutine itself if the ring is full or
+// closed, so messages are not lost under high throughput and on shutdown.
+type logRing struct {
+	// counters are placed first for 64-bit atomic alignment
+	head     uint64
+	tail     uint64
+	mask     uint64
+	pending  int64
+	overflow int64
+	closed   int32
+	slots    []logRingSlot
+	write    func(v interface{})
+	notify   chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func newLogRing(size int, write func(v interface{})) *logRing {
+	if size <= 0 {
+		size = defaultLogRingSize
+	}
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+
+	lr := &logRing{
+		mask:   uint64(n - 1),
+		slots:  make([]logRingSlot, n),
+		write:  write,
+		notify: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for i := range lr.slots {
+		lr.slots[i].seq = uint64(i)
+	}
+	go lr.run()
+	return lr
+}
+
+// Push method adds the message into ring buffer, if ring is full or closed
+// message is written by the caller goroutine.
+func (lr *logRing) Push(v interface{}) {
+	atomic.AddInt64(&lr.pending, 1)
+	if atomic.LoadInt32(&lr.closed) == 1 || !lr.enqueue(v) {
+		atomic.AddInt64(&lr.pending, -1)
+		if atomic.LoadInt32(&lr.closed) == 0 {
+			atomic.AddInt64(&lr.overflow, 1)
+		}
+		lr.write(v)
+		return
+	}
+	atomic.AddInt64(&lr.pending, -1)
+
+	select {
+	case lr.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Overflow method returns the count of messages written by the caller
+// goroutine since the ring was full.
+func (lr *logRing) Overflow() int64 {
+	return atomic.LoadInt64(&lr.overflow)
+}
+
+// Close method stops accepting the messages into ring buffer and waits for
+// the writer goroutine to flush the buffered messages until given context
+// is done.
+func (lr *logRing) Close(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&lr.closed, 0, 1) {
+		<-lr.done
+		return nil
+	}
+
+	// wait for the in-flight push, it's very short
+	for atomic.LoadInt64(&lr.pending) > 0 {
+		runtime.Gosched()
+	}
+	close(lr.stop)
+
+	select {
+	case <-lr.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// enqueue method publishes the message into next free slot, returns false
+// if the ring is full.
+func (lr *logRing) enqueue(v interface{}) bool {
+	for {
+		pos := atomic.LoadUint64(&lr.head)
+		slot := &lr.slots[pos&lr.mask]
+		diff := int64(atomic.LoadUint64(&slot.seq)) - int64(pos)
+		switch {
+		case diff == 0:
+			if atomic.CompareAndSwapUint64(&lr.head, pos, pos+1) {
+				slot.value = v
+				atomic.StoreUint64(&slot.seq, pos+1)
+				return true
+			}
+		case diff < 0:
+			return false
+		}
+	}
+}
+
+// drain method writes all the published messages, it's called only from
+// writer goroutine.
+func (lr *logRing) drain() {
+	for {
+		slot := &lr.slots[lr.tail&lr.mask]
+		if atomic.LoadUint64(&slot.seq) != lr.tail+1 {
+			return
+		}
+		v := slot.value
+		slot.value = nil
+		atomic.StoreUint64(&slot.seq, lr.tail+lr.mask+1)
+		lr.tail++
+		lr.write(v)
+	}
+}
+
+func (lr *logRing) run() {
+	defer close(lr.done)
+	for {
+		lr.drain()
+		select {
+		case <-lr.notify:
+		case <-lr.stop:
+			lr.drain()
+			return
+		}
+	}
+}
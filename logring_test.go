@@ -0,0 +1,74 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogRing(t *testing.T) {
+	var mu sync.Mutex
+	written := make(map[int]int)
+	block := make(chan struct{})
+	lr := newLogRing(6, func(v interface{}) {
+		if v.(int) == 0 {
+			<-block
+		}
+		mu.Lock()
+		written[v.(int)]++
+		mu.Unlock()
+	})
+	assert.Equal(t, 8, len(lr.slots))
+
+	// writer goroutine is blocked, so ring gets full and caller writes it
+	lr.Push(0)
+	time.Sleep(10 * time.Millisecond)
+	for i := 1; i <= 10; i++ {
+		lr.Push(i)
+	}
+	assert.Equal(t, int64(2), lr.Overflow())
+	close(block)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				lr.Push(100 + g*1000 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Nil(t, lr.Close(context.Background()))
+	assert.Nil(t, lr.Close(context.Background()))
+
+	// closed ring writes by the caller
+	lr.Push(-1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 8000+12, len(written))
+	for v, cnt := range written {
+		assert.Equal(t, 1, cnt, v)
+	}
+}
+
+func TestLogRingCloseTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	lr := newLogRing(0, func(v interface{}) { <-block })
+	assert.Equal(t, defaultLogRingSize, len(lr.slots))
+	lr.Push("msg")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, lr.Close(ctx))
+}
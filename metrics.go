@@ -0,0 +1,581 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"regexp"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+)
+
+// metricsContentType is Prometheus text exposition format content type.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const metricLabelSep = "\xff"
+
+var (
+	metricNameRegex  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	metricLabelRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	defaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	defaultSizeBuckets    = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+	defaultAllocBuckets   = []float64{1024, 10240, 102400, 1048576, 10485760, 104857600}
+	defaultCPUBuckets     = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+	metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	metricsHelpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// Metrics method returns the application metrics registry, use it to
+// register custom counters and gauges. Registered metrics are exposed on
+// metrics endpoint, see `server.metrics { ... }`.
+//
+//	ordersTotal, err := aah.App().Metrics().NewCounter("orders_total",
+//		"Total number of orders.", "status")
+//
+//	ordersTotal.Inc("paid")
+func (a *Application) Metrics() *Metrics {
+	return a.metrics
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Metrics
+//______________________________________________________________________________
+
+// Metrics is the registry of application metrics, it writes them in
+// Prometheus text exposition format.
+type Metrics struct {
+	mu         sync.RWMutex
+	collectors []metricCollector
+	names      map[string]bool
+	req        *requestCollectors
+}
+
+// NewCounter method registers the counter with given name, help text and
+// label names. Counter value only goes up.
+func (m *Metrics) NewCounter(name, help string, labelNames ...string) (*MetricCounter, error) {
+	c := &MetricCounter{metricVec: newMetricVec(name, help, labelNames)}
+	if err := m.register(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewGauge method registers the gauge with given name, help text and label
+// names. Gauge value can go up and down.
+func (m *Metrics) NewGauge(name, help string, labelNames ...string) (*MetricGauge, error) {
+	g := &MetricGauge{metricVec: newMetricVec(name, help, labelNames)}
+	if err := m.register(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// WriteText method writes the registered metrics and Go runtime metrics in
+// Prometheus text exposition format.
+func (m *Metrics) WriteText(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	m.writeText(buf)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (m *Metrics) writeText(buf *bytes.Buffer) {
+	m.mu.RLock()
+	collectors := m.collectors
+	m.mu.RUnlock()
+	for _, c := range collectors {
+		c.write(buf)
+	}
+	writeRuntimeMetrics(buf)
+}
+
+func (m *Metrics) register(c metricCollector) error {
+	v := c.vec()
+	if !metricNameRegex.MatchString(v.name) {
+		return fmt.Errorf("metrics: invalid metric name '%s'", v.name)
+	}
+	if strings.HasPrefix(v.name, "go_") {
+		return fmt.Errorf("metrics: metric name prefix 'go_' is reserved for Go runtime metrics")
+	}
+	for _, l := range v.labelNames {
+		if !metricLabelRegex.MatchString(l) || strings.HasPrefix(l, "__") {
+			return fmt.Errorf("metrics: invalid label name '%s' on metric '%s'", l, v.name)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.names[v.name] {
+		return fmt.Errorf("metrics: metric '%s' is already registered", v.name)
+	}
+	m.names[v.name] = true
+	m.collectors = append(m.collectors[:len(m.collectors):len(m.collectors)], c)
+	return nil
+}
+
+// MetricCounter is a metric whose value only goes up, values are labeled
+// in the order of label names given at registration. Call is ignored if
+// label values count does not match.
+type MetricCounter struct {
+	*metricVec
+}
+
+// Inc method increments the counter by 1.
+func (c *MetricCounter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add method adds the given value to the counter, negative value is ignored.
+func (c *MetricCounter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.update(labelValues, func(s *metricSeries) { s.value += v })
+}
+
+func (c *MetricCounter) write(buf *bytes.Buffer) {
+	c.writeSeries(buf, "counter")
+}
+
+// MetricGauge is a metric whose value can go up and down, values are
+// labeled in the order of label names given at registration. Call is
+// ignored if label values count does not match.
+type MetricGauge struct {
+	*metricVec
+}
+
+// Set method sets the gauge value.
+func (g *MetricGauge) Set(v float64, labelValues ...string) {
+	g.update(labelValues, func(s *metricSeries) { s.value = v })
+}
+
+// Inc method increments the gauge by 1.
+func (g *MetricGauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec method decrements the gauge by 1.
+func (g *MetricGauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Add method adds the given value to the gauge, use negative value to
+// subtract.
+func (g *MetricGauge) Add(v float64, labelValues ...string) {
+	g.update(labelValues, func(s *metricSeries) { s.value += v })
+}
+
+func (g *MetricGauge) write(buf *bytes.Buffer) {
+	g.writeSeries(buf, "gauge")
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initMetrics method initializes the request metrics and metrics endpoint
+// from config `server.metrics { ... }`. Metrics are exposed in Prometheus
+// text exposition format, endpoint requires `security.admin_auth` config
+// if `admin_auth` is enabled. Application routes takes precedence over
+// the endpoint.
+//
+//	server {
+//	  metrics {
+//	    enable = true
+//	    path = "/metrics"
+//	    admin_auth = false
+//	    resource {
+//	      sample_ratio = 0.01
+//	    }
+//	  }
+//	}
+func (a *Application) initMetrics() error {
+	keyPrefix := "server.metrics"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.reqMetrics = nil
+		return nil
+	}
+
+	path := cfg.StringDefault(keyPrefix+".path", "/metrics")
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("'%s.path' value must begin with '/'", keyPrefix)
+	}
+	adminAuth := cfg.BoolDefault(keyPrefix+".admin_auth", false)
+	if adminAuth && !cfg.IsExists("security.admin_auth") {
+		return fmt.Errorf("'%s.admin_auth' requires 'security.admin_auth' config", keyPrefix)
+	}
+	var sampleRatio float64
+	if v, found := cfg.Get(keyPrefix + ".resource.sample_ratio"); found {
+		ratio, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return fmt.Errorf("'%s.resource.sample_ratio' value must be between 0 and 1", keyPrefix)
+		}
+		sampleRatio = ratio
+	}
+
+	// request metrics are registered once, values are retained on hot-reload
+	if a.metrics.req == nil {
+		rc, err := newRequestCollectors(a.metrics)
+		if err != nil {
+			return err
+		}
+		a.metrics.req = rc
+	}
+	a.reqMetrics = &requestMetrics{requestCollectors: a.metrics.req, path: path,
+		adminAuth: adminAuth, sampleRatio: sampleRatio}
+	return nil
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{names: make(map[string]bool)}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Request metrics
+//______________________________________________________________________________
+
+type requestMetrics struct {
+	*requestCollectors
+	path        string
+	adminAuth   bool
+	sampleRatio float64
+}
+
+type requestCollectors struct {
+	requests *MetricCounter
+	latency  *metricHistogram
+	size     *metricHistogram
+	inFlight *MetricGauge
+	alloc    *metricHistogram
+	cpu      *metricHistogram
+}
+
+// resourceSample holds the allocation and CPU time at the beginning of
+// sampled request.
+type resourceSample struct {
+	allocBytes uint64
+	cpuTime    time.Duration
+	cpuTimeOk  bool
+}
+
+func newRequestCollectors(m *Metrics) (*requestCollectors, error) {
+	var err error
+	c := &requestCollectors{
+		latency: &metricHistogram{
+			metricVec: newMetricVec("aah_http_request_duration_seconds",
+				"HTTP request latency in seconds by route.", []string{"method", "route"}),
+			buckets: defaultLatencyBuckets,
+		},
+		size: &metricHistogram{
+			metricVec: newMetricVec("aah_http_response_size_bytes",
+				"HTTP response size in bytes by route.", []string{"method", "route"}),
+			buckets: defaultSizeBuckets,
+		},
+		alloc: &metricHistogram{
+			metricVec: newMetricVec("aah_http_request_alloc_bytes",
+				"Approximate heap allocation in bytes of sampled HTTP requests by route.", []string{"method", "route"}),
+			buckets: defaultAllocBuckets,
+		},
+		cpu: &metricHistogram{
+			metricVec: newMetricVec("aah_http_request_cpu_seconds",
+				"CPU time in seconds of sampled HTTP requests by route.", []string{"method", "route"}),
+			buckets: defaultCPUBuckets,
+		},
+	}
+	if c.requests, err = m.NewCounter("aah_http_requests_total",
+		"Total number of HTTP requests by route and status.", "method", "route", "status"); err != nil {
+		return nil, err
+	}
+	if err = m.register(c.latency); err != nil {
+		return nil, err
+	}
+	if err = m.register(c.size); err != nil {
+		return nil, err
+	}
+	if c.inFlight, err = m.NewGauge("aah_http_requests_in_flight",
+		"Number of HTTP requests currently being served."); err != nil {
+		return nil, err
+	}
+	if err = m.register(c.alloc); err != nil {
+		return nil, err
+	}
+	if err = m.register(c.cpu); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Begin method marks the request as in-flight, returns the resource sample
+// if request is sampled by `server.metrics.resource.sample_ratio` otherwise
+// nil.
+//
+// Sampled request goroutine is locked to its OS thread until `Record`, so
+// that thread CPU time is of the request. Allocation is the process wide
+// heap allocation during the request, it's approximate on concurrent
+// requests.
+func (rm *requestMetrics) Begin() *resourceSample {
+	rm.inFlight.Inc()
+	if rm.sampleRatio <= 0 || (rm.sampleRatio < 1 && rand.Float64() >= rm.sampleRatio) {
+		return nil
+	}
+	runtime.LockOSThread()
+	rs := &resourceSample{allocBytes: heapAllocBytes()}
+	rs.cpuTime, rs.cpuTimeOk = threadCPUTime()
+	return rs
+}
+
+// Record method records the request count, latency and response size by
+// route. Route path is used as label value to keep the cardinality bounded,
+// it's empty if route is not found. Allocation and CPU time is recorded for
+// the sampled request.
+func (rm *requestMetrics) Record(ctx *Context, startTime time.Time, rs *resourceSample) {
+	rm.inFlight.Dec()
+
+	var route string
+	if ctx.route != nil {
+		route = ctx.route.Path
+	}
+	method := ctx.Req.Method
+	rm.requests.Inc(method, route, strconv.Itoa(ctx.Res.Status()))
+	rm.latency.Observe(time.Since(startTime).Seconds(), method, route)
+	rm.size.Observe(float64(ctx.Res.BytesWritten()), method, route)
+
+	if rs != nil {
+		if cpuTime, ok := threadCPUTime(); ok && rs.cpuTimeOk {
+			rm.cpu.Observe((cpuTime - rs.cpuTime).Seconds(), method, route)
+		}
+		runtime.UnlockOSThread()
+		if alloc := heapAllocBytes(); alloc >= rs.allocBytes {
+			rm.alloc.Observe(float64(alloc-rs.allocBytes), method, route)
+		}
+	}
+}
+
+// Serve method replies the metrics on metrics endpoint. Returns true if
+// request is served otherwise false.
+func (rm *requestMetrics) Serve(ctx *Context) bool {
+	if ctx.Req.Path != rm.path || ctx.Req.Method != ahttp.MethodGet {
+		return false
+	}
+	if rm.adminAuth && !ctx.a.verifyAdmin(ctx) {
+		return true
+	}
+
+	buf := new(bytes.Buffer)
+	ctx.a.metrics.writeText(buf)
+	ctx.Reply().Ok().ContentType(metricsContentType).Binary(buf.Bytes())
+	return true
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Metric vector
+//______________________________________________________________________________
+
+type metricCollector interface {
+	vec() *metricVec
+	write(buf *bytes.Buffer)
+}
+
+type metricSeries struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	count       uint64
+}
+
+type metricVec struct {
+	mu         sync.Mutex
+	name       string
+	help       string
+	labelNames []string
+	series     map[string]*metricSeries
+}
+
+func newMetricVec(name, help string, labelNames []string) *metricVec {
+	return &metricVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		series:     make(map[string]*metricSeries),
+	}
+}
+
+func (v *metricVec) vec() *metricVec {
+	return v
+}
+
+func (v *metricVec) update(labelValues []string, fn func(s *metricSeries)) {
+	if len(labelValues) != len(v.labelNames) {
+		return
+	}
+	key := strings.Join(labelValues, metricLabelSep)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, found := v.series[key]
+	if !found {
+		s = &metricSeries{labelValues: append([]string(nil), labelValues...)}
+		v.series[key] = s
+	}
+	fn(s)
+}
+
+// sortedSeries method returns the series sorted by label values, so the
+// output is stable between scrapes.
+func (v *metricVec) sortedSeries() []metricSeries {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]metricSeries, 0, len(keys))
+	for _, k := range keys {
+		s := *v.series[k]
+		s.counts = append([]uint64(nil), s.counts...)
+		result = append(result, s)
+	}
+	v.mu.Unlock()
+	return result
+}
+
+func (v *metricVec) writeHeader(buf *bytes.Buffer, typ string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", v.name, metricsHelpEscaper.Replace(v.help), v.name, typ)
+}
+
+func (v *metricVec) writeSeries(buf *bytes.Buffer, typ string) {
+	v.writeHeader(buf, typ)
+	series := v.sortedSeries()
+	if len(series) == 0 && len(v.labelNames) == 0 {
+		writeMetricSample(buf, v.name, nil, nil, 0)
+		return
+	}
+	for _, s := range series {
+		writeMetricSample(buf, v.name, v.labelNames, s.labelValues, s.value)
+	}
+}
+
+// metricHistogram counts the observations into cumulative buckets.
+type metricHistogram struct {
+	*metricVec
+	buckets []float64
+}
+
+func (h *metricHistogram) Observe(v float64, labelValues ...string) {
+	h.update(labelValues, func(s *metricSeries) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(h.buckets))
+		}
+		for i, b := range h.buckets {
+			if v <= b {
+				s.counts[i]++
+			}
+		}
+		s.value += v
+		s.count++
+	})
+}
+
+func (h *metricHistogram) write(buf *bytes.Buffer) {
+	h.writeHeader(buf, "histogram")
+	names := append(h.labelNames[:len(h.labelNames):len(h.labelNames)], "le")
+	for _, s := range h.sortedSeries() {
+		values := append(s.labelValues[:len(s.labelValues):len(s.labelValues)], "")
+		for i, b := range h.buckets {
+			values[len(values)-1] = formatMetricValue(b)
+			writeMetricSample(buf, h.name+"_bucket", names, values, float64(s.counts[i]))
+		}
+		values[len(values)-1] = "+Inf"
+		writeMetricSample(buf, h.name+"_bucket", names, values, float64(s.count))
+		writeMetricSample(buf, h.name+"_sum", h.labelNames, s.labelValues, s.value)
+		writeMetricSample(buf, h.name+"_count", h.labelNames, s.labelValues, float64(s.count))
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func writeRuntimeMetrics(buf *bytes.Buffer) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	for _, m := range []struct {
+		name, help, typ string
+		value           float64
+	}{
+		{"go_goroutines", "Number of goroutines that currently exist.", "gauge", float64(runtime.NumGoroutine())},
+		{"go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", "gauge", float64(ms.Alloc)},
+		{"go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", "gauge", float64(ms.HeapInuse)},
+		{"go_memstats_heap_objects", "Number of allocated objects.", "gauge", float64(ms.HeapObjects)},
+		{"go_memstats_sys_bytes", "Number of bytes obtained from system.", "gauge", float64(ms.Sys)},
+		{"go_memstats_gc_cycles_total", "Number of completed GC cycles.", "counter", float64(ms.NumGC)},
+		{"go_memstats_gc_pause_seconds_total", "Total GC pause duration in seconds.", "counter", float64(ms.PauseTotalNs) / 1e9},
+	} {
+		fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.typ)
+		writeMetricSample(buf, m.name, nil, nil, m.value)
+	}
+	fmt.Fprint(buf, "# HELP go_info Information about the Go environment.\n# TYPE go_info gauge\n")
+	writeMetricSample(buf, "go_info", []string{"version"}, []string{runtime.Version()}, 1)
+}
+
+// heapAllocBytes method returns the cumulative bytes allocated on heap by
+// the process.
+func heapAllocBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+func writeMetricSample(buf *bytes.Buffer, name string, labelNames, labelValues []string, v float64) {
+	buf.WriteString(name)
+	if len(labelNames) > 0 {
+		buf.WriteByte('{')
+		for i, l := range labelNames {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(l)
+			buf.WriteString(`="`)
+			buf.WriteString(metricsLabelEscaper.Replace(labelValues[i]))
+			buf.WriteByte('"')
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(formatMetricValue(v))
+	buf.WriteByte('\n')
+}
+
+func formatMetricValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,15 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build !linux
+
+package aah
+
+import "time"
+
+// threadCPUTime method returns false, CPU time of OS thread is supported
+// only on Linux.
+func threadCPUTime() (time.Duration, bool) {
+	return 0, false
+}
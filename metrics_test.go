@@ -0,0 +1,152 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Metrics]: %s", ts.URL)
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  metrics {
+	    enable = true
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initMetrics())
+
+	orders, err := ts.app.Metrics().NewCounter("orders_total", "Total number of orders.", "status")
+	assert.Nil(t, err)
+	orders.Inc("paid")
+	orders.Add(2, "paid")
+	orders.Inc("paid", "extra") // label values mismatch, ignored
+	queue, err := ts.app.Metrics().NewGauge("queue_depth", "Current queue depth.")
+	assert.Nil(t, err)
+	queue.Set(5)
+	queue.Dec()
+
+	resp, err := http.Get(ts.URL + "/get-text.html")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/metrics")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, metricsContentType, resp.Header.Get(ahttp.HeaderContentType))
+	body := responseBody(resp)
+	assert.True(t, strings.Contains(body, "# TYPE aah_http_requests_total counter\n"))
+	assert.True(t, strings.Contains(body, `aah_http_requests_total{method="GET",route="/get-text.html",status="200"} 1`+"\n"))
+	assert.True(t, strings.Contains(body, `aah_http_request_duration_seconds_bucket{method="GET",route="/get-text.html",le="+Inf"} 1`+"\n"))
+	assert.True(t, strings.Contains(body, `aah_http_response_size_bytes_count{method="GET",route="/get-text.html"} 1`+"\n"))
+	assert.True(t, strings.Contains(body, "aah_http_requests_in_flight 1\n"))
+	assert.True(t, strings.Contains(body, `orders_total{status="paid"} 3`+"\n"))
+	assert.True(t, strings.Contains(body, "queue_depth 4\n"))
+	assert.True(t, strings.Contains(body, "# TYPE go_goroutines gauge\n"))
+
+	// request metrics are retained on re-initialize
+	assert.Nil(t, ts.app.initMetrics())
+	buf := new(bytes.Buffer)
+	assert.Nil(t, ts.app.Metrics().WriteText(buf))
+	assert.True(t, strings.Contains(buf.String(), `aah_http_requests_total{method="GET",route="/get-text.html",status="200"} 1`+"\n"))
+
+	// registration errors
+	_, err = ts.app.Metrics().NewCounter("orders_total", "Duplicate.")
+	assert.Equal(t, "metrics: metric 'orders_total' is already registered", err.Error())
+	_, err = ts.app.Metrics().NewGauge("queue-depth", "Invalid name.")
+	assert.Equal(t, "metrics: invalid metric name 'queue-depth'", err.Error())
+	_, err = ts.app.Metrics().NewGauge("go_custom", "Reserved prefix.")
+	assert.Equal(t, "metrics: metric name prefix 'go_' is reserved for Go runtime metrics", err.Error())
+	_, err = ts.app.Metrics().NewCounter("jobs_total", "Invalid label.", "__name")
+	assert.Equal(t, "metrics: invalid label name '__name' on metric 'jobs_total'", err.Error())
+
+	// endpoint guarded by admin auth
+	ts.app.Config().SetBool("server.metrics.admin_auth", true)
+	err = ts.app.initMetrics()
+	assert.Equal(t, "'server.metrics.admin_auth' requires 'security.admin_auth' config", err.Error())
+
+	ts.app.Config().SetString("server.metrics.path", "metrics")
+	err = ts.app.initMetrics()
+	assert.Equal(t, "'server.metrics.path' value must begin with '/'", err.Error())
+
+	ts.app.Config().SetBool("server.metrics.enable", false)
+	assert.Nil(t, ts.app.initMetrics())
+	assert.Nil(t, ts.app.reqMetrics)
+}
+
+func TestMetricsHistogram(t *testing.T) {
+	h := &metricHistogram{
+		metricVec: newMetricVec("job_duration_seconds", "Job duration.", []string{"job"}),
+		buckets:   []float64{0.1, 1},
+	}
+	h.Observe(0.05, "sync")
+	h.Observe(0.5, "sync")
+	h.Observe(5, "sync")
+
+	buf := new(bytes.Buffer)
+	h.write(buf)
+	assert.Equal(t, `# HELP job_duration_seconds Job duration.
+# TYPE job_duration_seconds histogram
+job_duration_seconds_bucket{job="sync",le="0.1"} 1
+job_duration_seconds_bucket{job="sync",le="1"} 2
+job_duration_seconds_bucket{job="sync",le="+Inf"} 3
+job_duration_seconds_sum{job="sync"} 5.55
+job_duration_seconds_count{job="sync"} 3
+`, buf.String())
+
+	buf.Reset()
+	writeMetricSample(buf, "build_info", []string{"version"}, []string{"v1 \"beta\"\n"}, 1)
+	assert.Equal(t, `build_info{version="v1 \"beta\"\n"} 1`+"\n", buf.String())
+}
+
+func TestMetricsResourceSampling(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  metrics {
+	    enable = true
+	    resource {
+	      sample_ratio = 1
+	    }
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initMetrics())
+	assert.Equal(t, float64(1), ts.app.reqMetrics.sampleRatio)
+
+	resp, err := http.Get(ts.URL + "/get-text.html")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/metrics")
+	assert.Nil(t, err)
+	body := responseBody(resp)
+	assert.True(t, strings.Contains(body, "# TYPE aah_http_request_alloc_bytes histogram\n"))
+	assert.True(t, strings.Contains(body, `aah_http_request_alloc_bytes_count{method="GET",route="/get-text.html"} 1`+"\n"))
+	if _, ok := threadCPUTime(); ok {
+		assert.True(t, strings.Contains(body, `aah_http_request_cpu_seconds_count{method="GET",route="/get-text.html"} 1`+"\n"))
+	}
+
+	// sampling disabled
+	ts.app.Config().SetFloat64("server.metrics.resource.sample_ratio", 0)
+	assert.Nil(t, ts.app.initMetrics())
+	assert.Nil(t, ts.app.reqMetrics.Begin())
+
+	ts.app.Config().SetFloat64("server.metrics.resource.sample_ratio", 1.5)
+	assert.Equal(t, "'server.metrics.resource.sample_ratio' value must be between 0 and 1", ts.app.initMetrics().Error())
+}
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"reflect"
 
+	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/util"
 	"aahframe.work/log"
 )
 
@@ -126,6 +128,8 @@ type finallyInterceptor interface {
 //	- Executes Interceptors (Before, Before<ActionName>, After, After<ActionName>,
 //				Panic, Panic<ActionName>, Finally, Finally<ActionName>)
 // 	- Invokes Controller Action
+// 	- Renders Controller Action return values `(T, error)`, refer to
+//	  `Context.handleActionResult`
 func ActionMiddleware(ctx *Context, m *Middleware) {
 	if err := ctx.setTarget(ctx.route); err == errTargetNotFound {
 		// No controller or action found for the route
@@ -133,6 +137,15 @@ func ActionMiddleware(ctx *Context, m *Middleware) {
 		return
 	}
 
+	// Request body deferred by `Expect: 100-continue` policy `defer`, the
+	// authentication and authorization has passed at this point
+	if ctx.deferBody {
+		ctx.deferBody = false
+		if parseRequestBody(ctx) == flowAbort {
+			return
+		}
+	}
+
 	// Finally action and method. Always executed if present
 	defer func() {
 		if finallyActionMethod := ctx.targetrv.MethodByName(incpFinallyActionName + ctx.action.Name); finallyActionMethod.IsValid() {
@@ -191,7 +204,7 @@ func ActionMiddleware(ctx *Context, m *Middleware) {
 		}
 
 		ctx.Log().Debugf("Calling action: %s.%s", ctx.controller.FqName, ctx.action.Name)
-		ctx.actionrv.Call(actionArgs)
+		ctx.handleActionResult(ctx.actionrv.Call(actionArgs))
 	}
 
 	// After action method
@@ -210,3 +223,96 @@ func ActionMiddleware(ctx *Context, m *Middleware) {
 		}
 	}
 }
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Action result
+//______________________________________________________________________________
+
+// statusCoder interface is implemented by the application errors which
+// carries HTTP status code, used while mapping action returned error.
+type statusCoder interface {
+	StatusCode() int
+}
+
+// handleActionResult method renders the controller action return values.
+// Action can return `error`, `T` or `(T, error)`.
+//
+//	- Non-nil error is sent to aah error handling pipeline; `*aah.Error` is
+//	  used as-is, error which implements `StatusCode() int` is mapped to
+//	  its status code otherwise `500 Internal Server Error`.
+//	- Non-nil value is rendered based on content negotiation (`Accept`
+//	  header, `render.default`), `aah.Render` value is rendered as-is.
+//	- Nil value without error replies `204 No Content`.
+//
+// Explicit reply of the action (Reply().JSON, Redirect, etc.) takes precedence
+// over the returned value.
+func (ctx *Context) handleActionResult(results []reflect.Value) {
+	if len(results) == 0 {
+		return
+	}
+
+	if last := results[len(results)-1]; last.Type() == errorType {
+		results = results[:len(results)-1]
+		if !last.IsNil() {
+			err := last.Interface().(error)
+			if e, ok := err.(*Error); ok {
+				ctx.Reply().Error(e)
+				return
+			}
+			if m, found := ctx.a.errRegistry.Lookup(err); found {
+				ctx.Reply().Error(newErrorFromMapping(ctx, err, m))
+				return
+			}
+			code := http.StatusInternalServerError
+			if sc, ok := err.(statusCoder); ok {
+				code = sc.StatusCode()
+			}
+			ctx.Reply().Error(&Error{Reason: err, Code: code, Message: http.StatusText(code)})
+			return
+		}
+	}
+	if len(results) == 0 {
+		return
+	}
+
+	r := ctx.Reply()
+	if r.Rdr != nil || r.err != nil || r.redirect || r.done {
+		return // explicit reply
+	}
+
+	rv := results[0]
+	if !rv.IsValid() || ((rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface ||
+		rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil()) {
+		r.NoContent()
+		return
+	}
+
+	data := rv.Interface()
+	if rdr, ok := data.(Render); ok {
+		r.Render(rdr)
+		return
+	}
+
+	ct := r.ContType
+	if len(ct) == 0 {
+		ct = ctx.detectContentType()
+	}
+	switch util.OnlyMIME(ct) {
+	case ahttp.ContentTypeXML.Mime, ahttp.ContentTypeXMLText.Mime:
+		r.XML(data)
+	case ahttp.ContentTypePlainText.Mime:
+		r.Text("%v", data)
+	case ahttp.ContentTypeHTML.Mime:
+		if ctx.a.viewMgr == nil {
+			r.JSON(data)
+			return
+		}
+		if d, ok := data.(Data); ok {
+			r.HTML(d)
+		} else {
+			r.HTML(Data{"Data": data})
+		}
+	default:
+		r.JSON(data)
+	}
+}
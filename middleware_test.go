@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
 	"aahframe.work/ahttp"
+	aerrors "aahframe.work/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -77,3 +79,132 @@ func thirdPartyMiddleware3(w http.ResponseWriter, r *http.Request) {
 func invaildHandlerType(e *Event) {
 	fmt.Println("This is invaild handler type")
 }
+
+type notFoundErr struct{}
+
+func (notFoundErr) Error() string   { return "user not found" }
+func (notFoundErr) StatusCode() int { return http.StatusNotFound }
+
+func TestActionResult(t *testing.T) {
+	newResultCtx := func(accept string) *Context {
+		r := httptest.NewRequest("GET", "http://localhost:8080/users/1", nil)
+		r.Header.Set(ahttp.HeaderAccept, accept)
+		ctx := newContext(httptest.NewRecorder(), r)
+		ctx.a = newApp()
+		return ctx
+	}
+	results := func(values ...interface{}) []reflect.Value {
+		var rv []reflect.Value
+		for _, v := range values {
+			if v == nil {
+				rv = append(rv, reflect.Zero(errorType))
+				continue
+			}
+			rv = append(rv, reflect.ValueOf(v))
+		}
+		return rv
+	}
+	user := map[string]interface{}{"id": 1, "name": "gopher"}
+
+	// (T, nil) with JSON
+	ctx := newResultCtx("application/json")
+	ctx.handleActionResult(results(user, nil))
+	assert.Equal(t, &jsonRender{Data: user}, ctx.Reply().Rdr)
+	assert.Equal(t, http.StatusOK, ctx.Reply().Code)
+
+	// (T, nil) with XML
+	ctx = newResultCtx("application/xml")
+	ctx.handleActionResult(results("gopher", nil))
+	assert.Equal(t, &xmlRender{Data: "gopher"}, ctx.Reply().Rdr)
+
+	// (T, nil) with text
+	ctx = newResultCtx("text/plain")
+	ctx.handleActionResult(results("gopher", nil))
+	assert.Equal(t, &textRender{Format: "%v", Values: []interface{}{"gopher"}}, ctx.Reply().Rdr)
+
+	// (nil, nil)
+	ctx = newResultCtx("application/json")
+	ctx.handleActionResult([]reflect.Value{reflect.Zero(reflect.TypeOf(user)), reflect.Zero(errorType)})
+	assert.Nil(t, ctx.Reply().Rdr)
+	assert.Equal(t, http.StatusNoContent, ctx.Reply().Code)
+
+	// (T, error) status coder
+	ctx = newResultCtx("application/json")
+	ctx.handleActionResult([]reflect.Value{reflect.ValueOf(user), reflect.ValueOf(notFoundErr{}).Convert(errorType)})
+	assert.Nil(t, ctx.Reply().Rdr)
+	assert.Equal(t, http.StatusNotFound, ctx.Reply().err.Code)
+	assert.Equal(t, "user not found", ctx.Reply().err.Reason.Error())
+
+	// error
+	ctx = newResultCtx("application/json")
+	ctx.handleActionResult([]reflect.Value{reflect.ValueOf(fmt.Errorf("db down")).Convert(errorType)})
+	assert.Equal(t, http.StatusInternalServerError, ctx.Reply().err.Code)
+
+	// *aah.Error is used as-is
+	ctx = newResultCtx("application/json")
+	aerr := &Error{Code: http.StatusConflict, Message: "duplicate"}
+	ctx.handleActionResult([]reflect.Value{reflect.ValueOf(aerr).Convert(errorType)})
+	assert.Equal(t, aerr, ctx.Reply().err)
+
+	// explicit reply takes precedence
+	ctx = newResultCtx("application/json")
+	ctx.Reply().Text("explicit")
+	ctx.handleActionResult(results(user, nil))
+	assert.Equal(t, &textRender{Format: "explicit"}, ctx.Reply().Rdr)
+}
+
+func TestActionResultErrorRegistry(t *testing.T) {
+	errNoRows := fmt.Errorf("no rows in result set")
+	newResultCtx := func(a *Application) *Context {
+		r := httptest.NewRequest("GET", "http://localhost:8080/users/1", nil)
+		r.Header.Set(ahttp.HeaderAccept, "application/json")
+		ctx := newContext(httptest.NewRecorder(), r)
+		ctx.a = a
+		return ctx
+	}
+	handle := func(ctx *Context, err error) {
+		ctx.handleActionResult([]reflect.Value{reflect.ValueOf(err).Convert(errorType)})
+	}
+
+	a := newApp()
+	a.ErrorRegistry().Register(errNoRows, aerrors.Mapping{Status: http.StatusNotFound})
+	a.ErrorRegistry().RegisterKind(aerrors.KindConflict, aerrors.Mapping{
+		Type:   "https://example.com/probs/conflict",
+		Fields: map[string]interface{}{"retry": false},
+	})
+
+	// sentinel error
+	ctx := newResultCtx(a)
+	handle(ctx, errNoRows)
+	assert.Equal(t, http.StatusNotFound, ctx.Reply().err.Code)
+	assert.Equal(t, "Not Found", ctx.Reply().err.Message)
+	assert.Nil(t, ctx.Reply().err.problem)
+
+	// typed error with kind
+	ctx = newResultCtx(a)
+	handle(ctx, aerrors.Unauthorized("token expired"))
+	assert.Equal(t, http.StatusUnauthorized, ctx.Reply().err.Code)
+
+	// problem details
+	ctx = newResultCtx(a)
+	handle(ctx, aerrors.Conflict("duplicate email").WithField("field", "email"))
+	err := ctx.Reply().err
+	assert.Equal(t, http.StatusConflict, err.Code)
+	assert.Equal(t, &aerrors.Problem{
+		Type:       "https://example.com/probs/conflict",
+		Title:      "Conflict",
+		Status:     http.StatusConflict,
+		Detail:     "Conflict",
+		Instance:   "/users/1",
+		Extensions: map[string]interface{}{"retry": false, "field": "email"},
+	}, err.problem)
+
+	(&errorManager{a: a}).DefaultHandler(ctx, err)
+	assert.Equal(t, contentTypeProblemJSON, ctx.Reply().ContType)
+	assert.Equal(t, &jsonRender{Data: err.problem}, ctx.Reply().Rdr)
+
+	// unmapped error falls back
+	ctx = newResultCtx(a)
+	handle(ctx, fmt.Errorf("db down"))
+	assert.Equal(t, http.StatusInternalServerError, ctx.Reply().err.Code)
+}
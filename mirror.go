@@ -0,0 +1,183 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+)
+
+// headerXAahMirror request header is added to the mirrored requests, so
+// shadow upstream can identify the mirrored traffic.
+const headerXAahMirror = "X-Aah-Mirror"
+
+// hop-by-hop headers are not forwarded to shadow upstream
+var hopByHopHeaders = []string{
+	"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initMirror method initializes the request mirroring (shadow traffic) from
+// config `server.mirror { ... }`.
+//
+//	server {
+//	  mirror {
+//	    enable = true
+//	    upstream = "http://shadow.example.com:8080"
+//	    percentage = 10
+//	    timeout = "5s"
+//	    max_body_size = "1mb"
+//	    max_concurrent = 100
+//	  }
+//	}
+func (a *Application) initMirror() error {
+	keyPrefix := "server.mirror"
+	if !a.Config().BoolDefault(keyPrefix+".enable", false) {
+		a.mirror = nil
+		return nil
+	}
+
+	upstream := a.Config().StringDefault(keyPrefix+".upstream", "")
+	u, err := url.Parse(upstream)
+	if err != nil || ess.IsStrEmpty(u.Scheme) || ess.IsStrEmpty(u.Host) {
+		return fmt.Errorf("'%s.upstream' is not a valid URL: %s", keyPrefix, upstream)
+	}
+
+	percentage := a.Config().IntDefault(keyPrefix+".percentage", 100)
+	if percentage <= 0 || percentage > 100 {
+		return fmt.Errorf("'%s.percentage' value must be between 1 and 100", keyPrefix)
+	}
+
+	timeout, err := settings.ParseDuration(a.Config(), keyPrefix+".timeout", 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	maxBodySize, err := ess.StrToBytes(a.Config().StringDefault(keyPrefix+".max_body_size", "1mb"))
+	if err != nil {
+		return fmt.Errorf("'%s.max_body_size': %s", keyPrefix, err)
+	}
+
+	maxConcurrent := a.Config().IntDefault(keyPrefix+".max_concurrent", 100)
+	if maxConcurrent <= 0 {
+		return fmt.Errorf("'%s.max_concurrent' value must be greater than zero", keyPrefix)
+	}
+
+	// mirror requests share the aah HTTP client transport and its metrics
+	client := &http.Client{Timeout: timeout}
+	if a.httpClient != nil {
+		client.Transport = a.httpClient.Transport
+	}
+
+	a.mirror = &mirrorManager{
+		a:           a,
+		upstream:    strings.TrimSuffix(u.String(), "/"),
+		percentage:  percentage,
+		maxBodySize: maxBodySize,
+		sem:         make(chan struct{}, maxConcurrent),
+		client:      client,
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Mirror manager
+//______________________________________________________________________________
+
+type mirrorManager struct {
+	a           *Application
+	upstream    string
+	percentage  int
+	maxBodySize int64
+	sem         chan struct{}
+	client      *http.Client
+}
+
+// Mirror method asynchronously sends the copy of request (headers and body)
+// to the shadow upstream for the configured percentage of requests.
+// Shadow upstream response is discarded, it does not affect the primary
+// response. Request is not mirrored if body exceeds `max_body_size` or
+// concurrent mirror requests reaches `max_concurrent`.
+func (m *mirrorManager) Mirror(ctx *Context) {
+	if m.percentage < 100 && rand.Intn(100) >= m.percentage {
+		return
+	}
+
+	var body []byte
+	r := ctx.Req.Unwrap()
+	if r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		if r.ContentLength > m.maxBodySize {
+			ctx.Log().Debugf("mirror: request body size exceeds the limit %d, skip it", m.maxBodySize)
+			return
+		}
+
+		var err error
+		body, err = ioutil.ReadAll(io.LimitReader(r.Body, m.maxBodySize+1))
+		r.Body = &mirrorBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		if err != nil {
+			ctx.Log().Errorf("mirror: unable to read request body: %s", err)
+			return
+		}
+		if int64(len(body)) > m.maxBodySize {
+			ctx.Log().Debugf("mirror: request body size exceeds the limit %d, skip it", m.maxBodySize)
+			return
+		}
+	}
+
+	req, err := http.NewRequest(r.Method, m.upstream+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		ctx.Log().Errorf("mirror: unable to create request: %s", err)
+		return
+	}
+	for k, v := range r.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	for _, h := range hopByHopHeaders {
+		req.Header.Del(h)
+	}
+	req.Host = r.Host
+	req.Header.Set(headerXAahMirror, "true")
+
+	select {
+	case m.sem <- struct{}{}:
+	default:
+		ctx.Log().Debug("mirror: maximum concurrent mirror requests reached, skip it")
+		return
+	}
+
+	go m.send(req)
+}
+
+func (m *mirrorManager) send(req *http.Request) {
+	defer func() { <-m.sem }()
+	defer m.a.aahRecover()
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		m.a.Log().Warnf("mirror: request failed %s %s: %s", req.Method, req.URL, err)
+		return
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	ess.CloseQuietly(res.Body)
+}
+
+type mirrorBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type mirroredRequest struct {
+	method, uri, body, header string
+}
+
+func TestRequestMirror(t *testing.T) {
+	mirrored := make(chan *mirroredRequest, 1)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		mirrored <- &mirroredRequest{method: r.Method, uri: r.URL.RequestURI(),
+			body: string(b), header: r.Header.Get(headerXAahMirror)}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer shadow.Close()
+
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Request Mirror]: %s", ts.URL)
+
+	assert.Nil(t, mergeTestConfig(ts.app, fmt.Sprintf(`
+	server {
+	  mirror {
+	    enable = true
+	    upstream = "%s"
+	    max_body_size = "1kb"
+	  }
+	}
+	`, shadow.URL)))
+	err := ts.app.initMirror()
+	assert.Nil(t, err)
+
+	req, _ := http.NewRequest(ahttp.MethodPost, ts.URL+"/not-exists?mode=shadow", strings.NewReader("name=aah"))
+	req.Header.Set(ahttp.HeaderContentType, ahttp.ContentTypeForm.String())
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	select {
+	case mr := <-mirrored:
+		assert.Equal(t, ahttp.MethodPost, mr.method)
+		assert.Equal(t, "/not-exists?mode=shadow", mr.uri)
+		assert.Equal(t, "name=aah", mr.body)
+		assert.Equal(t, "true", mr.header)
+	case <-time.After(2 * time.Second):
+		t.Error("mirrored request not received")
+	}
+
+	// body exceeds the limit, not mirrored
+	req, _ = http.NewRequest(ahttp.MethodPost, ts.URL+"/not-exists", strings.NewReader(strings.Repeat("a", 2048)))
+	resp, err = http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	select {
+	case <-mirrored:
+		t.Error("request should not be mirrored")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRequestMirrorConfigErrors(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	for _, c := range []struct{ cfg, err string }{
+		{`server { mirror { enable = true; } }`,
+			"'server.mirror.upstream' is not a valid URL: "},
+		{`server { mirror {
+			enable = true
+			upstream = "http://localhost:9090"
+			percentage = 0
+		} }`, "'server.mirror.percentage' value must be between 1 and 100"},
+		{`server { mirror {
+			enable = true
+			upstream = "http://localhost:9090"
+			timeout = "5"
+		} }`, "'server.mirror.timeout' value is not a valid time unit"},
+	} {
+		assert.Nil(t, setTestConfig(a, c.cfg))
+		err := a.initMirror()
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
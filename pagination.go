@@ -0,0 +1,192 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+
+	"aahframe.work/ahttp"
+)
+
+const (
+	defaultPageParamName = "page"
+	defaultPageWindow    = 2
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Pagination
+//______________________________________________________________________________
+
+// Pagination holds the current page details and page links of the request.
+// Page links preserve the current request path (route params) and query
+// string, only the page query param is replaced. Configuration goes into
+// `aah.conf`.
+//
+//	pagination {
+//	  param_name = "page"
+//	  window = 2
+//	  link_header = true
+//	}
+type Pagination struct {
+	Page       int
+	PerPage    int
+	TotalItems int
+	TotalPages int
+	Offset     int
+
+	// First, Prev, Next and Last links are nil if not applicable for
+	// current page.
+	First *PageLink
+	Prev  *PageLink
+	Next  *PageLink
+	Last  *PageLink
+
+	// Pages has the page links around the current page within the window,
+	// first and last page links are always present. Skipped pages are
+	// represented with `PageLink.Gap`.
+	Pages []*PageLink
+}
+
+// PageLink holds the single page link details.
+type PageLink struct {
+	Number  int
+	URL     string
+	Current bool
+	Gap     bool
+}
+
+// HasPrev method returns true if previous page exists otherwise false.
+func (p *Pagination) HasPrev() bool {
+	return p.Prev != nil
+}
+
+// HasNext method returns true if next page exists otherwise false.
+func (p *Pagination) HasNext() bool {
+	return p.Next != nil
+}
+
+// String method is stringer interface.
+func (p *Pagination) String() string {
+	return fmt.Sprintf("pagination(page:%d perpage:%d totalitems:%d totalpages:%d)",
+		p.Page, p.PerPage, p.TotalItems, p.TotalPages)
+}
+
+// Paginate method creates the pagination for current request by given
+// total no. of items and items per page. Current page number is taken from
+// query param `pagination.param_name`. If `pagination.link_header` is enabled
+// then HTTP header `Link` is added with `rel` first, prev, next and last.
+//
+//	p := ctx.Paginate(totalProducts, 20)
+//	products := fetchProducts(p.Offset, p.PerPage)
+func (ctx *Context) Paginate(totalItems, perPage int) *Pagination {
+	p := ctx.a.newPagination(ctx.Req, totalItems, perPage)
+	if ctx.a.Config().BoolDefault("pagination.link_header", true) {
+		for _, l := range []struct {
+			rel  string
+			link *PageLink
+		}{{"first", p.First}, {"prev", p.Prev}, {"next", p.Next}, {"last", p.Last}} {
+			if l.link != nil {
+				ctx.Reply().HeaderAppend(ahttp.HeaderLink, fmt.Sprintf(`<%s>; rel="%s"`, l.link.URL, l.rel))
+			}
+		}
+	}
+	return p
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// View Template methods
+//______________________________________________________________________________
+
+// tmplPaginate method creates the pagination for current request.
+// Mapped to Go template func.
+func (vm *viewManager) tmplPaginate(viewArgs map[string]interface{}, totalItems, perPage int) *Pagination {
+	return vm.a.newPagination(viewArgs[KeyViewArgRequest].(*ahttp.Request), totalItems, perPage)
+}
+
+// tmplPageURL method returns the URL for given page number preserving the
+// current request path and query string. Mapped to Go template func.
+func (vm *viewManager) tmplPageURL(viewArgs map[string]interface{}, page int) template.URL {
+	/* #nosec */
+	return template.URL(vm.a.pageURL(viewArgs[KeyViewArgRequest].(*ahttp.Request), page))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) newPagination(req *ahttp.Request, totalItems, perPage int) *Pagination {
+	if perPage <= 0 {
+		perPage = 1
+	}
+	if totalItems < 0 {
+		totalItems = 0
+	}
+
+	p := &Pagination{PerPage: perPage, TotalItems: totalItems}
+	p.TotalPages = (totalItems + perPage - 1) / perPage
+	if p.TotalPages == 0 {
+		p.TotalPages = 1
+	}
+	p.Page, _ = strconv.Atoi(req.QueryValue(a.Config().StringDefault("pagination.param_name", defaultPageParamName)))
+	if p.Page < 1 {
+		p.Page = 1
+	} else if p.Page > p.TotalPages {
+		p.Page = p.TotalPages
+	}
+	p.Offset = (p.Page - 1) * perPage
+
+	link := func(n int) *PageLink {
+		return &PageLink{Number: n, URL: a.pageURL(req, n), Current: n == p.Page}
+	}
+	if p.Page > 1 {
+		p.First, p.Prev = link(1), link(p.Page-1)
+	}
+	if p.Page < p.TotalPages {
+		p.Next, p.Last = link(p.Page+1), link(p.TotalPages)
+	}
+
+	window := a.Config().IntDefault("pagination.window", defaultPageWindow)
+	start, end := p.Page-window, p.Page+window
+	if start < 1 {
+		start = 1
+	}
+	if end > p.TotalPages {
+		end = p.TotalPages
+	}
+	if start > 1 {
+		p.Pages = append(p.Pages, link(1))
+		if start > 2 {
+			p.Pages = append(p.Pages, &PageLink{Gap: true})
+		}
+	}
+	for n := start; n <= end; n++ {
+		p.Pages = append(p.Pages, link(n))
+	}
+	if end < p.TotalPages {
+		if end < p.TotalPages-1 {
+			p.Pages = append(p.Pages, &PageLink{Gap: true})
+		}
+		p.Pages = append(p.Pages, link(p.TotalPages))
+	}
+
+	return p
+}
+
+// pageURL method returns the request URI for given page number, first page
+// URL does not have page query param.
+func (a *Application) pageURL(req *ahttp.Request, page int) string {
+	paramName := a.Config().StringDefault("pagination.param_name", defaultPageParamName)
+	u := *req.URL()
+	q := u.Query()
+	if page > 1 {
+		q.Set(paramName, strconv.Itoa(page))
+	} else {
+		q.Del(paramName)
+	}
+	u.RawQuery = q.Encode()
+	return u.RequestURI()
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextPaginate(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `pagination { window = 1; }`))
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost:8080/categories/shoes?sort=price&page=5", nil))
+	ctx.a = a
+
+	p := ctx.Paginate(95, 10)
+	assert.Equal(t, 5, p.Page)
+	assert.Equal(t, 10, p.TotalPages)
+	assert.Equal(t, 40, p.Offset)
+	assert.True(t, p.HasPrev())
+	assert.True(t, p.HasNext())
+	assert.Equal(t, "/categories/shoes?sort=price", p.First.URL)
+	assert.Equal(t, "/categories/shoes?page=4&sort=price", p.Prev.URL)
+	assert.Equal(t, "/categories/shoes?page=6&sort=price", p.Next.URL)
+	assert.Equal(t, "/categories/shoes?page=10&sort=price", p.Last.URL)
+	assert.Equal(t, "pagination(page:5 perpage:10 totalitems:95 totalpages:10)", p.String())
+
+	var numbers []int
+	for _, pl := range p.Pages {
+		if pl.Gap {
+			numbers = append(numbers, 0)
+			continue
+		}
+		numbers = append(numbers, pl.Number)
+		assert.Equal(t, pl.Number == 5, pl.Current)
+	}
+	assert.Equal(t, []int{1, 0, 4, 5, 6, 0, 10}, numbers)
+
+	assert.Equal(t, []string{
+		`</categories/shoes?sort=price>; rel="first"`,
+		`</categories/shoes?page=4&sort=price>; rel="prev"`,
+		`</categories/shoes?page=6&sort=price>; rel="next"`,
+		`</categories/shoes?page=10&sort=price>; rel="last"`,
+	}, ctx.Res.Header()[ahttp.HeaderLink])
+}
+
+func TestPaginationBounds(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `pagination { param_name = "p"; link_header = false; }`))
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost:8080/items?p=50", nil))
+	ctx.a = a
+	p := ctx.Paginate(25, 10)
+	assert.Equal(t, 3, p.Page)
+	assert.True(t, p.HasPrev())
+	assert.False(t, p.HasNext())
+	assert.Equal(t, 3, len(p.Pages))
+	assert.Nil(t, ctx.Res.Header()[ahttp.HeaderLink])
+
+	ctx = newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost:8080/items?p=abc", nil))
+	ctx.a = a
+	p = ctx.Paginate(0, 0)
+	assert.Equal(t, 1, p.Page)
+	assert.Equal(t, 1, p.TotalPages)
+	assert.False(t, p.HasPrev())
+	assert.False(t, p.HasNext())
+	assert.Equal(t, "/items", a.pageURL(ctx.Req, 1))
+	assert.Equal(t, "/items?p=2", a.pageURL(ctx.Req, 2))
+}
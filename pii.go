@@ -0,0 +1,216 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"aahframe.work/internal/settings"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// IsPII method returns true if given config key, log field or session key
+// name is tagged as PII via `security.pii.fields`. Name is matched by
+// containment and case insensitive, for e.g.: `email` matches `user_email`.
+//
+// PII tagged names are redacted in app logs, dump log, failed requests,
+// audit records and effective config dump.
+func (a *Application) IsPII(name string) bool {
+	return isRedactField(a.settings().PIIFields, name)
+}
+
+// AddPIIHandler method adds the PII handler for given name, it's used for
+// subject access export and erasure requests, see `ExportPII` and
+// `ErasePII`. Handler which implements `PIIPurger` is called periodically
+// as per `security.pii.retention`.
+func (a *Application) AddPIIHandler(name string, h PIIHandler) error {
+	if h == nil {
+		return errors.New("aah: PII handler is nil")
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if _, found := a.piiHandlers[name]; found {
+		return fmt.Errorf("aah: PII handler '%s' is already added", name)
+	}
+	if a.piiHandlers == nil {
+		a.piiHandlers = make(map[string]PIIHandler)
+	}
+	a.piiHandlers[name] = h
+	return nil
+}
+
+// ExportPII method collects the personal data of given subject from all
+// the PII handlers for subject access request, result is keyed by the
+// handler name.
+func (a *Application) ExportPII(ctx context.Context, subject string) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for _, name := range a.piiHandlerNames() {
+		data, err := a.piiHandler(name).Export(ctx, subject)
+		if err != nil {
+			return nil, fmt.Errorf("aah: PII handler '%s' export: %s", name, err)
+		}
+		result[name] = data
+	}
+	return result, nil
+}
+
+// ErasePII method erases the personal data of given subject via all the
+// PII handlers. All the handlers are called, first error is returned.
+func (a *Application) ErasePII(ctx context.Context, subject string) error {
+	var err error
+	for _, name := range a.piiHandlerNames() {
+		if er := a.piiHandler(name).Erase(ctx, subject); er != nil {
+			a.Log().Errorf("PII handler '%s' erase failed: %s", name, er)
+			if err == nil {
+				err = fmt.Errorf("aah: PII handler '%s' erase: %s", name, er)
+			}
+		}
+	}
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// PII handler
+//______________________________________________________________________________
+
+// PIIHandler interface is to implement the subject access export and
+// erasure of personal data stored by the application, for e.g.: users
+// table, uploaded files, etc.
+type PIIHandler interface {
+	// Export method returns the personal data of given subject.
+	Export(ctx context.Context, subject string) (interface{}, error)
+
+	// Erase method erases or anonymizes the personal data of given subject.
+	Erase(ctx context.Context, subject string) error
+}
+
+// PIIPurger interface is optionally implemented by the `PIIHandler` to purge
+// the personal data older than retention period `security.pii.retention`.
+type PIIPurger interface {
+	Purge(ctx context.Context, before time.Time) error
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context methods
+//______________________________________________________________________________
+
+// SessionPII method returns the PII tagged values of current session, for
+// e.g.: to include in subject access export of logged in user. It returns
+// empty map if session does not exists.
+func (ctx *Context) SessionPII() map[string]interface{} {
+	values := make(map[string]interface{})
+	if ctx.subject == nil || ctx.subject.Session == nil {
+		return values
+	}
+	for k, v := range ctx.subject.Session.Values {
+		if ctx.a.IsPII(k) {
+			values[k] = v
+		}
+	}
+	return values
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initPII method initializes the PII retention purge from config
+// `security.pii { ... }`. PII handlers which implements `PIIPurger` are
+// called every `purge_interval` with time before `retention` period.
+//
+//	security {
+//	  pii {
+//	    fields = ["email", "phone", "address"]
+//	    retention = "2160h"
+//	    purge_interval = "24h"
+//	  }
+//	}
+func (a *Application) initPII() error {
+	keyPrefix := "security.pii"
+	retention, err := settings.ParseDuration(a.Config(), keyPrefix+".retention", 0)
+	if err != nil {
+		return err
+	}
+	interval, err := settings.ParseDuration(a.Config(), keyPrefix+".purge_interval", 24*time.Hour)
+	if err != nil {
+		return err
+	}
+	if interval == 0 {
+		return fmt.Errorf("'%s.purge_interval' value must be greater than zero", keyPrefix)
+	}
+
+	if a.piiStop != nil {
+		close(a.piiStop)
+		a.piiStop = nil
+	}
+	if retention == 0 {
+		return nil
+	}
+
+	stop := make(chan struct{})
+	a.piiStop = stop
+	a.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-stop:
+				return
+			case <-ticker.C:
+				a.purgePII(ctx, time.Now().Add(-retention))
+			}
+		}
+	})
+	return nil
+}
+
+func (a *Application) purgePII(ctx context.Context, before time.Time) {
+	for _, name := range a.piiHandlerNames() {
+		p, ok := a.piiHandler(name).(PIIPurger)
+		if !ok {
+			continue
+		}
+		if err := p.Purge(ctx, before); err != nil {
+			a.Log().Errorf("PII handler '%s' purge failed: %s", name, err)
+		}
+	}
+}
+
+// piiRedactFields method returns the given redact fields along with PII
+// tagged field names.
+func (a *Application) piiRedactFields(fields []string) []string {
+	pii := a.settings().PIIFields
+	if len(pii) == 0 {
+		return fields
+	}
+	return append(append([]string(nil), fields...), pii...)
+}
+
+func (a *Application) piiHandlerNames() []string {
+	a.RLock()
+	defer a.RUnlock()
+	names := make([]string, 0, len(a.piiHandlers))
+	for name := range a.piiHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (a *Application) piiHandler(name string) PIIHandler {
+	a.RLock()
+	defer a.RUnlock()
+	return a.piiHandlers[name]
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/security/session"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPIIHandler struct {
+	data   map[string]string
+	before time.Time
+	err    error
+}
+
+func (h *testPIIHandler) Export(_ context.Context, subject string) (interface{}, error) {
+	return h.data[subject], h.err
+}
+
+func (h *testPIIHandler) Erase(_ context.Context, subject string) error {
+	delete(h.data, subject)
+	return h.err
+}
+
+func (h *testPIIHandler) Purge(_ context.Context, before time.Time) error {
+	h.before = before
+	return nil
+}
+
+func TestPIIFields(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `
+	name = "piiapp"
+	support_email = "support@example.com"
+	security {
+		pii {
+			fields = ["email", "Phone"]
+		}
+	}
+	env { dev { } }
+	`))
+	if err := a.settingsHolder.Refresh(a.Config()); err != nil {
+		t.Fatalf("settings refresh: %v", err)
+	}
+	assert.True(t, a.IsPII("user_email"))
+	assert.True(t, a.IsPII("phone_number"))
+	assert.False(t, a.IsPII("username"))
+	assert.Equal(t, []string{"password", "email", "Phone"}, a.piiRedactFields([]string{"password"}))
+
+	// effective config dump
+	buf := new(bytes.Buffer)
+	assert.Nil(t, a.DumpConfig(buf, "hocon"))
+	assert.True(t, strings.Contains(buf.String(), `support_email = "******"`))
+	assert.True(t, strings.Contains(buf.String(), `name = "piiapp"`))
+
+	// session values
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	ctx.a = a
+	assert.Equal(t, map[string]interface{}{}, ctx.SessionPII())
+	ctx.Subject().Session = &session.Session{Values: map[string]interface{}{
+		"email": "jeeva@example.com", "theme": "dark"}}
+	assert.Equal(t, map[string]interface{}{"email": "jeeva@example.com"}, ctx.SessionPII())
+}
+
+func TestPIIHandlers(t *testing.T) {
+	a := newApp()
+	h := &testPIIHandler{data: map[string]string{"jeeva": "jeeva@example.com"}}
+	assert.Nil(t, a.AddPIIHandler("users", h))
+	assert.Equal(t, "aah: PII handler 'users' is already added", a.AddPIIHandler("users", h).Error())
+	assert.Equal(t, "aah: PII handler is nil", a.AddPIIHandler("nil", nil).Error())
+
+	data, err := a.ExportPII(context.Background(), "jeeva")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"users": "jeeva@example.com"}, data)
+
+	assert.Nil(t, a.ErasePII(context.Background(), "jeeva"))
+	data, _ = a.ExportPII(context.Background(), "jeeva")
+	assert.Equal(t, map[string]interface{}{"users": ""}, data)
+
+	before := time.Now().Add(-time.Hour)
+	a.purgePII(context.Background(), before)
+	assert.Equal(t, before, h.before)
+
+	h.err = errors.New("store unavailable")
+	_, err = a.ExportPII(context.Background(), "jeeva")
+	assert.Equal(t, "aah: PII handler 'users' export: store unavailable", err.Error())
+}
+
+func TestPIIRetentionConfig(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `
+	security {
+		pii {
+			retention = "720h"
+			purge_interval = "0s"
+		}
+	}
+	`))
+	assert.Equal(t, "'security.pii.purge_interval' value must be greater than zero", a.initPII().Error())
+
+	a.Config().SetString("security.pii.purge_interval", "1h")
+	assert.Nil(t, a.initPII())
+	assert.NotNil(t, a.piiStop)
+
+	a.Config().SetString("security.pii.retention", "0s")
+	assert.Nil(t, a.initPII())
+	assert.Nil(t, a.piiStop)
+
+	a.Config().SetString("security.pii.retention", "30d")
+	assert.Equal(t, "'security.pii.retention' value is not a valid time unit", a.initPII().Error())
+}
@@ -0,0 +1,135 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+)
+
+const (
+	headerXForwardedFor   = "X-Forwarded-For"
+	headerXForwardedHost  = "X-Forwarded-Host"
+	headerXForwardedProto = "X-Forwarded-Proto"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context methods
+//______________________________________________________________________________
+
+// Proxy method forwards the current request to given upstream URL with
+// request URI (e.g. `http://orders:8080` + `/api/v1/orders?page=2`) via the
+// application HTTP client transport, see `Application.HTTPClient`. Request
+// body and upstream response body are streamed without full buffering, see
+// `Reply().FromUpstream`.
+//
+// Upstream redirects are not followed and config `http_client.timeout` is not
+// applied, since the response body could be multi-GB download. Request is
+// canceled when the client goes away. It returns an error if the upstream
+// request fails, typically replied with `502 Bad Gateway`.
+//
+//	if err := ctx.Proxy("http://downloads:8080"); err != nil {
+//		ctx.Reply().Status(http.StatusBadGateway).Text("upstream unavailable")
+//	}
+func (ctx *Context) Proxy(upstream string) error {
+	u, err := url.Parse(upstream)
+	if err != nil || ess.IsStrEmpty(u.Scheme) || ess.IsStrEmpty(u.Host) {
+		return fmt.Errorf("aah: proxy upstream is not a valid URL: %s", upstream)
+	}
+
+	r := ctx.Req.Unwrap()
+	var body io.Reader
+	if r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		body = r.Body
+	}
+	req, err := http.NewRequest(r.Method, strings.TrimSuffix(upstream, "/")+r.URL.RequestURI(), body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(r.Context())
+	req.ContentLength = r.ContentLength
+	for k, v := range r.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	for _, h := range hopByHopHeaders {
+		req.Header.Del(h)
+	}
+
+	if prior := req.Header.Get(headerXForwardedFor); len(prior) > 0 {
+		req.Header.Set(headerXForwardedFor, prior+", "+ctx.Req.ClientIP())
+	} else {
+		req.Header.Set(headerXForwardedFor, ctx.Req.ClientIP())
+	}
+	req.Header.Set(headerXForwardedHost, ctx.Req.Host)
+	req.Header.Set(headerXForwardedProto, ctx.Req.Scheme)
+
+	res, err := ctx.a.upstreamClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("aah: proxy request to upstream failed: %s", err)
+	}
+	ctx.Reply().FromUpstream(res)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Reply methods
+//______________________________________________________________________________
+
+// FromUpstream method replies the given upstream response, typically from
+// `aah.App().HTTPClient()`. Status code and headers except hop-by-hop
+// headers are copied and body is streamed to the client without full
+// buffering, then upstream body is closed. Upstream `Content-Encoding` and
+// `Content-Length` are preserved, so reply is not Gzip compressed.
+//
+// Each read of upstream body is flushed to the client immediately if the
+// upstream length is unknown (e.g. chunked, `text/event-stream`).
+//
+// Note: Config `server.timeout.write` applies to the streamed reply.
+func (r *Reply) FromUpstream(res *http.Response) *Reply {
+	hdr := r.ctx.Res.Header()
+	for k, v := range res.Header {
+		hdr[k] = append([]string(nil), v...)
+	}
+	for _, h := range hopByHopHeaders {
+		hdr.Del(h)
+	}
+	r.ContType = res.Header.Get(ahttp.HeaderContentType)
+	r.Code = res.StatusCode
+	r.gzip = false
+
+	if !bodyAllowedForStatus(res.StatusCode) {
+		ess.CloseQuietly(res.Body)
+		return r
+	}
+	r.Render(&binaryRender{
+		Reader: res.Body,
+		Flush: res.ContentLength == -1 ||
+			strings.HasPrefix(res.Header.Get(ahttp.HeaderContentType), "text/event-stream"),
+	})
+	return r
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types
+//______________________________________________________________________________
+
+// flushWriter flushes the response after each write, it's used to pass
+// through the upstream flushes.
+type flushWriter struct {
+	w io.Writer
+	f http.Flusher
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.f.Flush()
+	return n, err
+}
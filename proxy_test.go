@@ -0,0 +1,91 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextProxy(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/upload":
+			b, _ := ioutil.ReadAll(r.Body)
+			w.Header().Set("X-Forwarded", r.Header.Get(headerXForwardedFor)+"|"+r.Header.Get(headerXForwardedHost))
+			w.Header().Set(ahttp.HeaderContentType, ahttp.ContentTypePlainText.String())
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "%s %s", r.URL.RawQuery, b)
+		case "/events":
+			w.Header().Set(ahttp.HeaderContentType, "text/event-stream")
+			for i := 0; i < 3; i++ {
+				fmt.Fprintf(w, "data: %d\n\n", i)
+				w.(http.Flusher).Flush()
+			}
+		case "/moved":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer upstream.Close()
+
+	proxy := func(method, path, body string) (*httptest.ResponseRecorder, error) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "http://localhost:8080"+path, strings.NewReader(body))
+		r.Header.Set(headerXForwardedFor, "10.0.0.1")
+		ctx := newContext(w, r)
+		ctx.a = ts.app
+		if err := ctx.Proxy(upstream.URL + "/"); err != nil {
+			return nil, err
+		}
+		if bodyAllowedForStatus(ctx.Reply().Code) {
+			ts.app.HTTPEngine().writeOnWire(ctx)
+		} else {
+			ctx.Res.WriteHeader(ctx.Reply().Code)
+		}
+		ahttp.ReleaseResponseWriter(ctx.Res)
+		return w, nil
+	}
+
+	w, err := proxy(ahttp.MethodPost, "/upload?name=report.csv", "a,b,c")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "name=report.csv a,b,c", w.Body.String())
+	assert.True(t, strings.HasPrefix(w.Header().Get("X-Forwarded"), "10.0.0.1, "))
+	assert.True(t, strings.HasSuffix(w.Header().Get("X-Forwarded"), "|localhost:8080"))
+	assert.Equal(t, "", w.Header().Get("Connection"))
+
+	w, err = proxy(ahttp.MethodGet, "/events", "")
+	assert.Nil(t, err)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "data: 0\n\ndata: 1\n\ndata: 2\n\n", w.Body.String())
+
+	// redirect is not followed
+	w, err = proxy(ahttp.MethodGet, "/moved", "")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/elsewhere", w.Header().Get(ahttp.HeaderLocation))
+
+	w, err = proxy(ahttp.MethodGet, "/not-modified", "")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	ctx := newContext(httptest.NewRecorder(), httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/", nil))
+	ctx.a = ts.app
+	assert.NotNil(t, ctx.Proxy("not-a-url"))
+	assert.NotNil(t, ctx.Proxy("http://127.0.0.1:1"))
+}
@@ -0,0 +1,253 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PROXY protocol header, refer to
+// https://www.haproxy.org/download/2.0/doc/proxy-protocol.txt
+var (
+	proxyV1Prefix    = []byte("PROXY ")
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	errProxyHeaderMissing = errors.New("aah: proxy protocol header is missing")
+	errProxyHeaderInvalid = errors.New("aah: proxy protocol header is invalid")
+)
+
+// proxyV1MaxLen is the maximum length of v1 header including CRLF.
+const proxyV1MaxLen = 107
+
+// proxyProtocolListener method wraps the given listener to parse the HAProxy
+// PROXY protocol v1/v2 header, if `server.proxy_protocol.enable` is true.
+// Client address from the header is used as connection remote address, so
+// `ahttp.Request` gets the real client IP behind AWS NLB, HAProxy, etc.
+func (a *Application) proxyProtocolListener(l net.Listener, enabled bool) net.Listener {
+	if !enabled {
+		return l
+	}
+	return &proxyListener{
+		Listener: l,
+		timeout:  a.settings().ProxyProtocolTimeout,
+		trusted:  a.settings().ProxyProtocolTrusted,
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// proxyListener type and its methods
+//______________________________________________________________________________
+
+type proxyListener struct {
+	net.Listener
+	timeout time.Duration
+	trusted []*net.IPNet
+}
+
+// Accept method wraps the accepted connection, header is read on first use
+// of connection so slow clients does not block the accept loop.
+func (l *proxyListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.isTrusted(c.RemoteAddr()) {
+		return c, nil
+	}
+	return &proxyConn{Conn: c, r: bufio.NewReader(c), timeout: l.timeout}, nil
+}
+
+// isTrusted method returns true if given address is from trusted sources,
+// no source is trusted if trusted list is empty.
+func (l *proxyListener) isTrusted(addr net.Addr) bool {
+	if len(l.trusted) == 0 {
+		return false
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		// unix domain socket is local
+		return true
+	}
+	for _, n := range l.trusted {
+		if n.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// proxyConn type and its methods
+//______________________________________________________________________________
+
+type proxyConn struct {
+	net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+	once    sync.Once
+	err     error
+	src     net.Addr
+	dst     net.Addr
+}
+
+func (c *proxyConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(b)
+}
+
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.src != nil {
+		return c.src
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.dst != nil {
+		return c.dst
+	}
+	return c.Conn.LocalAddr()
+}
+
+func (c *proxyConn) readHeader() {
+	if c.timeout > 0 {
+		_ = c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+		defer func() { _ = c.Conn.SetReadDeadline(time.Time{}) }()
+	}
+
+	// v1 header is at least 15 bytes and v2 header is at least 16 bytes
+	prefix, err := c.r.Peek(len(proxyV2Signature))
+	switch {
+	case err != nil:
+		c.err = fmt.Errorf("aah: proxy protocol header: %s", err)
+	case bytes.Equal(prefix, proxyV2Signature):
+		c.src, c.dst, c.err = readProxyV2(c.r)
+	case bytes.HasPrefix(prefix, proxyV1Prefix):
+		c.src, c.dst, c.err = readProxyV1(c.r)
+	default:
+		c.err = errProxyHeaderMissing
+	}
+
+	// connection with invalid header must be closed without response
+	if c.err != nil {
+		_ = c.Conn.Close()
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// readProxyV1 method reads the human-readable header, for e.g.:
+//
+//	PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n
+//
+// Protocol `UNKNOWN` returns nil addresses, connection addresses are used.
+func readProxyV1(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	var line []byte
+	for len(line) < proxyV1MaxLen {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, errProxyHeaderInvalid
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, errProxyHeaderInvalid
+	}
+
+	fields := strings.Fields(string(line[:len(line)-2]))
+	if len(fields) < 2 {
+		return nil, nil, errProxyHeaderInvalid
+	}
+	switch fields[1] {
+	case "UNKNOWN":
+		return nil, nil, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, nil, errProxyHeaderInvalid
+	}
+	if len(fields) != 6 {
+		return nil, nil, errProxyHeaderInvalid
+	}
+
+	srcIP, dstIP := net.ParseIP(fields[2]), net.ParseIP(fields[3])
+	srcPort, err1 := strconv.ParseUint(fields[4], 10, 16)
+	dstPort, err2 := strconv.ParseUint(fields[5], 10, 16)
+	if srcIP == nil || dstIP == nil || err1 != nil || err2 != nil {
+		return nil, nil, errProxyHeaderInvalid
+	}
+	if (fields[1] == "TCP4") != (srcIP.To4() != nil) {
+		return nil, nil, errProxyHeaderInvalid
+	}
+	return &net.TCPAddr{IP: srcIP, Port: int(srcPort)},
+		&net.TCPAddr{IP: dstIP, Port: int(dstPort)}, nil
+}
+
+// readProxyV2 method reads the binary header. Command `LOCAL`, unix and
+// unspecified address families return nil addresses, connection addresses
+// are used.
+func readProxyV2(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	hdr := make([]byte, 16)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, nil, errProxyHeaderInvalid
+	}
+	if hdr[12]>>4 != 2 {
+		return nil, nil, errProxyHeaderInvalid
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(hdr[14:16]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, errProxyHeaderInvalid
+	}
+
+	switch hdr[12] & 0x0F {
+	case 0x00: // LOCAL, for e.g.: health checks from proxy
+		return nil, nil, nil
+	case 0x01: // PROXY
+	default:
+		return nil, nil, errProxyHeaderInvalid
+	}
+
+	var ipLen int
+	switch hdr[13] >> 4 {
+	case 0x1: // AF_INET
+		ipLen = net.IPv4len
+	case 0x2: // AF_INET6
+		ipLen = net.IPv6len
+	default:
+		return nil, nil, nil
+	}
+	if len(payload) < 2*ipLen+4 {
+		return nil, nil, errProxyHeaderInvalid
+	}
+
+	srcIP := net.IP(append([]byte(nil), payload[:ipLen]...))
+	dstIP := net.IP(append([]byte(nil), payload[ipLen:2*ipLen]...))
+	srcPort := int(binary.BigEndian.Uint16(payload[2*ipLen:]))
+	dstPort := int(binary.BigEndian.Uint16(payload[2*ipLen+2:]))
+	if hdr[13]&0x0F == 0x2 { // DGRAM
+		return &net.UDPAddr{IP: srcIP, Port: srcPort}, &net.UDPAddr{IP: dstIP, Port: dstPort}, nil
+	}
+	return &net.TCPAddr{IP: srcIP, Port: srcPort}, &net.TCPAddr{IP: dstIP, Port: dstPort}, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyProtocolV1(t *testing.T) {
+	testcases := []struct {
+		header string
+		src    string
+		dst    string
+		err    error
+	}{
+		{"PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n", "192.168.0.1:56324", "192.168.0.11:443", nil},
+		{"PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n", "[2001:db8::1]:56324", "[2001:db8::2]:443", nil},
+		{"PROXY UNKNOWN\r\n", "", "", nil},
+		{"PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\n", "", "", errProxyHeaderInvalid},
+		{"PROXY TCP4 2001:db8::1 192.168.0.11 56324 443\r\n", "", "", errProxyHeaderInvalid},
+		{"PROXY TCP4 192.168.0.1 192.168.0.11 70000 443\r\n", "", "", errProxyHeaderInvalid},
+		{"PROXY UDP4 192.168.0.1 192.168.0.11 56324 443\r\n", "", "", errProxyHeaderInvalid},
+		{"PROXY TCP4 " + strings.Repeat("1", 100) + "\r\n", "", "", errProxyHeaderInvalid},
+	}
+	for _, tc := range testcases {
+		src, dst, err := readProxyV1(bufio.NewReader(strings.NewReader(tc.header)))
+		assert.Equal(t, tc.err, err, tc.header)
+		if len(tc.src) > 0 {
+			assert.Equal(t, tc.src, src.String())
+			assert.Equal(t, tc.dst, dst.String())
+		} else {
+			assert.Nil(t, src)
+		}
+	}
+}
+
+func TestProxyProtocolV2(t *testing.T) {
+	src, dst, err := readProxyV2(bufio.NewReader(bytes.NewReader(proxyV2Header(0x21, 0x11,
+		net.ParseIP("10.1.2.3").To4(), net.ParseIP("10.1.2.4").To4(), 41000, 80))))
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.2.3:41000", src.String())
+	assert.Equal(t, "10.1.2.4:80", dst.String())
+
+	src, _, err = readProxyV2(bufio.NewReader(bytes.NewReader(proxyV2Header(0x21, 0x21,
+		net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2"), 41000, 80))))
+	assert.Nil(t, err)
+	assert.Equal(t, "[2001:db8::1]:41000", src.String())
+
+	// LOCAL command
+	src, _, err = readProxyV2(bufio.NewReader(bytes.NewReader(proxyV2Header(0x20, 0x00, nil, nil, 0, 0))))
+	assert.Nil(t, err)
+	assert.Nil(t, src)
+
+	// invalid version
+	_, _, err = readProxyV2(bufio.NewReader(bytes.NewReader(proxyV2Header(0x11, 0x11,
+		net.ParseIP("10.1.2.3").To4(), net.ParseIP("10.1.2.4").To4(), 41000, 80))))
+	assert.Equal(t, errProxyHeaderInvalid, err)
+
+	// truncated addresses
+	hdr := proxyV2Header(0x21, 0x21, net.ParseIP("10.1.2.3").To4(), net.ParseIP("10.1.2.4").To4(), 41000, 80)
+	_, _, err = readProxyV2(bufio.NewReader(bytes.NewReader(hdr)))
+	assert.Equal(t, errProxyHeaderInvalid, err)
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	a := newWebApp1TestApp(t)
+	a.Config().SetBool("server.proxy_protocol.enable", true)
+	a.Config().SetString("server.proxy_protocol.header_timeout", "500ms")
+	err := a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.proxy_protocol.trusted_cidrs' is required to enable PROXY protocol", err.Error())
+
+	assert.Nil(t, mergeTestConfig(a, `server { proxy_protocol { trusted_cidrs = ["127.0.0.0/8"]; } }`))
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Nil(t, err)
+	assert.True(t, a.settings().ProxyProtocolEnabled)
+	assert.Equal(t, 500*time.Millisecond, a.settings().ProxyProtocolTimeout)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(ahttp.ClientIP(r)))
+	})}
+	go func() { _ = srv.Serve(a.proxyProtocolListener(l, true)) }()
+	defer srv.Close()
+
+	send := func(header []byte) string {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		assert.Nil(t, err)
+		defer conn.Close()
+		_, _ = conn.Write(header)
+		_, _ = fmt.Fprint(conn, "GET / HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n")
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		b, _ := ioutil.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	assert.Equal(t, "203.0.113.7", send([]byte("PROXY TCP4 203.0.113.7 10.0.0.1 51234 8080\r\n")))
+	assert.Equal(t, "198.51.100.9", send(proxyV2Header(0x21, 0x11,
+		net.ParseIP("198.51.100.9").To4(), net.ParseIP("10.0.0.1").To4(), 51234, 8080)))
+
+	// header is required for trusted sources
+	assert.Equal(t, "", send(nil))
+
+	// untrusted sources are served as-is
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+	pl := &proxyListener{trusted: []*net.IPNet{ipNet}}
+	assert.False(t, pl.isTrusted(&net.TCPAddr{IP: net.ParseIP("127.0.0.1")}))
+	assert.True(t, pl.isTrusted(&net.TCPAddr{IP: net.ParseIP("10.2.3.4")}))
+
+	// no source is trusted without trusted list
+	pl = &proxyListener{}
+	assert.False(t, pl.isTrusted(&net.TCPAddr{IP: net.ParseIP("10.2.3.4")}))
+	assert.False(t, pl.isTrusted(&net.UnixAddr{Name: "/tmp/app.sock", Net: "unix"}))
+
+	assert.Nil(t, mergeTestConfig(a, `server { proxy_protocol { trusted_cidrs = ["10.0.0.0/8", "10.0.0.0/33"]; } }`))
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.proxy_protocol.trusted_cidrs' has invalid CIDR '10.0.0.0/33'", err.Error())
+}
+
+func proxyV2Header(verCmd, fam byte, src, dst net.IP, srcPort, dstPort uint16) []byte {
+	var payload []byte
+	payload = append(payload, src...)
+	payload = append(payload, dst...)
+	if len(src) > 0 {
+		ports := make([]byte, 4)
+		binary.BigEndian.PutUint16(ports, srcPort)
+		binary.BigEndian.PutUint16(ports[2:], dstPort)
+		payload = append(payload, ports...)
+	}
+	hdr := append([]byte(nil), proxyV2Signature...)
+	hdr = append(hdr, verCmd, fam, 0, 0)
+	binary.BigEndian.PutUint16(hdr[14:], uint16(len(payload)))
+	return append(hdr, payload...)
+}
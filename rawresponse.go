@@ -0,0 +1,156 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRawResponseNotConfigured returned when raw response token is requested
+// without config `render.raw_response.sign_key`.
+var ErrRawResponseNotConfigured = errors.New("aah: render.raw_response sign key is not configured")
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// RawResponseToken method returns the signed token of given request path
+// valid for given duration. Add it as query parameter of
+// `render.raw_response.query_param` to get the response without compression
+// and minification, for e.g.: `/users/1?_aah_raw=<token>`.
+func (a *Application) RawResponseToken(path string, ttl time.Duration) (string, error) {
+	rr := a.rawResponse
+	if rr == nil || len(rr.signKey) == 0 {
+		return "", ErrRawResponseNotConfigured
+	}
+	expiry := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return expiry + "." + rr.sign(path, expiry), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initRawResponse method initializes the compression and minification bypass
+// of request from config `render.raw_response { ... }`, to simplify the
+// payload debugging in production. Request qualifies either with valid
+// signed query parameter or with header from trusted proxies.
+//
+//	render {
+//	  raw_response {
+//	    enable = true
+//	    query_param = "_aah_raw"
+//	    sign_key = "..."
+//	    header = "X-Aah-Raw-Response"
+//	    trusted_proxies = ["10.0.0.0/8"]
+//	  }
+//	}
+func (a *Application) initRawResponse() error {
+	keyPrefix := "render.raw_response"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.rawResponse = nil
+		return nil
+	}
+
+	rr := &rawResponse{
+		queryParam: cfg.StringDefault(keyPrefix+".query_param", "_aah_raw"),
+		header:     cfg.StringDefault(keyPrefix+".header", "X-Aah-Raw-Response"),
+	}
+	if key := cfg.StringDefault(keyPrefix+".sign_key", ""); len(key) > 0 {
+		if len(key) < 32 {
+			return fmt.Errorf("'%s.sign_key' value must be at least 32 characters", keyPrefix)
+		}
+		rr.signKey = []byte(key)
+	}
+
+	proxies, _ := cfg.StringList(keyPrefix + ".trusted_proxies")
+	for _, v := range proxies {
+		ipNet, err := parseIPNet(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("'%s.trusted_proxies' has invalid value '%s'", keyPrefix, v)
+		}
+		rr.trustedProxies = append(rr.trustedProxies, ipNet)
+	}
+
+	if len(rr.signKey) == 0 && len(rr.trustedProxies) == 0 {
+		return fmt.Errorf("'%s' requires 'sign_key' or 'trusted_proxies' config", keyPrefix)
+	}
+
+	a.rawResponse = rr
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Raw response
+//______________________________________________________________________________
+
+type rawResponse struct {
+	queryParam     string
+	header         string
+	signKey        []byte
+	trustedProxies []*net.IPNet
+}
+
+// Qualify method returns true if request has valid signed query parameter
+// or raw response header from trusted proxies.
+func (rr *rawResponse) Qualify(ctx *Context) bool {
+	if len(rr.signKey) > 0 {
+		if token := ctx.Req.QueryValue(rr.queryParam); len(token) > 0 && rr.verify(ctx.Req.Path, token) {
+			return true
+		}
+	}
+
+	if len(rr.trustedProxies) > 0 {
+		if v, err := strconv.ParseBool(ctx.Req.Header.Get(rr.header)); err == nil && v {
+			return rr.isTrustedProxy(ctx.Req.Unwrap().RemoteAddr)
+		}
+	}
+
+	return false
+}
+
+func (rr *rawResponse) verify(path, token string) bool {
+	idx := strings.IndexByte(token, '.')
+	if idx <= 0 {
+		return false
+	}
+	expiry, err := strconv.ParseInt(token[:idx], 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return false
+	}
+	return hmac.Equal([]byte(token[idx+1:]), []byte(rr.sign(path, token[:idx])))
+}
+
+func (rr *rawResponse) sign(path, expiry string) string {
+	mac := hmac.New(sha256.New, rr.signKey)
+	_, _ = mac.Write([]byte(path + "|" + expiry))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (rr *rawResponse) isTrustedProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range rr.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
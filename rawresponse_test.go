@@ -0,0 +1,91 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawResponseQualify(t *testing.T) {
+	a := newApp()
+	_, err := a.RawResponseToken("/users/1", time.Minute)
+	assert.Equal(t, ErrRawResponseNotConfigured, err)
+
+	assert.Nil(t, setTestConfig(a, `
+	render {
+	  raw_response {
+	    enable = true
+	    sign_key = "eFWLXEewECptbDVXExokRTLONWxrTjfV"
+	    trusted_proxies = ["10.0.0.0/8"]
+	  }
+	}
+	`))
+	assert.Nil(t, a.initRawResponse())
+
+	newCtx := func(target, remoteAddr string, hdr http.Header) *Context {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.RemoteAddr = remoteAddr
+		for k, v := range hdr {
+			r.Header[k] = v
+		}
+		return newContext(httptest.NewRecorder(), r)
+	}
+
+	token, err := a.RawResponseToken("/users/1", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, a.rawResponse.Qualify(newCtx("/users/1?_aah_raw="+token, "192.0.2.10:5040", nil)))
+
+	// token of other path, tampered and expired
+	assert.False(t, a.rawResponse.Qualify(newCtx("/users/2?_aah_raw="+token, "192.0.2.10:5040", nil)))
+	assert.False(t, a.rawResponse.Qualify(newCtx("/users/1?_aah_raw="+token+"x", "192.0.2.10:5040", nil)))
+	expired, _ := a.RawResponseToken("/users/1", -time.Minute)
+	assert.False(t, a.rawResponse.Qualify(newCtx("/users/1?_aah_raw="+expired, "192.0.2.10:5040", nil)))
+	assert.False(t, a.rawResponse.Qualify(newCtx("/users/1?_aah_raw=invalid", "192.0.2.10:5040", nil)))
+
+	// header from trusted proxies only
+	hdr := http.Header{"X-Aah-Raw-Response": []string{"true"}}
+	assert.True(t, a.rawResponse.Qualify(newCtx("/users/1", "10.1.2.3:5040", hdr)))
+	assert.False(t, a.rawResponse.Qualify(newCtx("/users/1", "192.0.2.10:5040", hdr)))
+	assert.False(t, a.rawResponse.Qualify(newCtx("/users/1", "10.1.2.3:5040", nil)))
+}
+
+func TestRawResponseInitErrors(t *testing.T) {
+	a := newApp()
+
+	assert.Nil(t, setTestConfig(a, `render { raw_response { enable = true; } }`))
+	err := a.initRawResponse()
+	assert.Equal(t, "'render.raw_response' requires 'sign_key' or 'trusted_proxies' config", err.Error())
+
+	assert.Nil(t, setTestConfig(a, `
+	render {
+	  raw_response {
+	    enable = true
+	    sign_key = "short"
+	  }
+	}
+	`))
+	err = a.initRawResponse()
+	assert.Equal(t, "'render.raw_response.sign_key' value must be at least 32 characters", err.Error())
+
+	assert.Nil(t, setTestConfig(a, `
+	render {
+	  raw_response {
+	    enable = true
+	    trusted_proxies = ["10.0.0"]
+	  }
+	}
+	`))
+	err = a.initRawResponse()
+	assert.Equal(t, "'render.raw_response.trusted_proxies' has invalid value '10.0.0'", err.Error())
+
+	assert.Nil(t, setTestConfig(a, `render { raw_response { enable = false; } }`))
+	assert.Nil(t, a.initRawResponse())
+	assert.Nil(t, a.rawResponse)
+}
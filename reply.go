@@ -15,14 +15,19 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
 	"aahframe.work/internal/util"
+	"aahframe.work/soap"
 )
 
+const noCacheControl = "no-cache, no-store, must-revalidate"
+
 var (
 	bufPool = &sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
 )
@@ -36,13 +41,26 @@ type Reply struct {
 	redirect bool
 	done     bool
 	gzip     bool
+	etagSet  bool
 	path     string
+	etag     string
+	jsonView string
 	ctx      *Context
 	body     *bytes.Buffer
 	cookies  []*http.Cookie
+	trailers []trailer
 	err      *Error
 }
 
+type trailer struct {
+	key string
+	fn  TrailerFunc
+}
+
+// TrailerFunc func type returns the value of HTTP trailer, it's called after
+// the response body is written.
+type TrailerFunc func() string
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Reply - HTTP Status Code
 //______________________________________________________________________________
@@ -125,6 +143,16 @@ func (r *Reply) Conflict() *Reply {
 	return r.Status(http.StatusConflict)
 }
 
+// RequestURITooLong method sets the HTTP Code as 414 RFC 7231, 6.5.12.
+func (r *Reply) RequestURITooLong() *Reply {
+	return r.Status(http.StatusRequestURITooLong)
+}
+
+// RequestHeaderFieldsTooLarge method sets the HTTP Code as 431 RFC 6585, 5.
+func (r *Reply) RequestHeaderFieldsTooLarge() *Reply {
+	return r.Status(http.StatusRequestHeaderFieldsTooLarge)
+}
+
 // UnsupportedMediaType method sets the HTTP Code as 415 RFC 7231, 6.5.13
 func (r *Reply) UnsupportedMediaType() *Reply {
 	return r.Status(http.StatusUnsupportedMediaType)
@@ -161,6 +189,11 @@ func (r *Reply) ContentType(contentType string) *Reply {
 // and it sets HTTP 'Content-Type' as 'application/json; charset=utf-8'.
 func (r *Reply) JSON(data interface{}) *Reply {
 	r.ContentType(ahttp.ContentTypeJSON.String())
+	data = applyJSONView(data, r.resolveJSONView())
+	if rt := r.ctx.route; rt != nil && rt.JSONCacheTTL > 0 && r.ctx.a.jsonCache != nil {
+		r.Render(&cachedJSONRender{Data: data, TTL: rt.JSONCacheTTL, cache: r.ctx.a.jsonCache})
+		return r
+	}
 	r.Render(&jsonRender{Data: data})
 	return r
 }
@@ -171,7 +204,8 @@ func (r *Reply) JSON(data interface{}) *Reply {
 // See config `render.secure_json.prefix`.
 func (r *Reply) JSONSecure(data interface{}) *Reply {
 	r.ContentType(ahttp.ContentTypeJSON.String())
-	r.Render(&secureJSONRender{Data: data, Prefix: r.ctx.a.settings.SecureJSONPrefix})
+	data = applyJSONView(data, r.resolveJSONView())
+	r.Render(&secureJSONRender{Data: data, Prefix: r.ctx.a.settings().SecureJSONPrefix})
 	return r
 }
 
@@ -179,10 +213,21 @@ func (r *Reply) JSONSecure(data interface{}) *Reply {
 // and it sets HTTP 'Content-Type' as 'application/javascript; charset=utf-8'.
 func (r *Reply) JSONP(data interface{}, callback string) *Reply {
 	r.ContentType(ahttp.ContentTypeJavascript.String())
+	data = applyJSONView(data, r.resolveJSONView())
 	r.Render(&jsonpRender{Data: data, Callback: callback})
 	return r
 }
 
+// JSONView method sets the JSON view of the reply, struct fields tagged with
+// other views are not serialized by `JSON`, `JSONSecure` and `JSONP`. It
+// takes precedence over role and route JSON view, for e.g.:
+//
+//	ctx.Reply().JSONView("admin").JSON(user)
+func (r *Reply) JSONView(view string) *Reply {
+	r.jsonView = view
+	return r
+}
+
 // XML method renders given data as XML response and it sets
 // HTTP Content-Type as 'application/xml; charset=utf-8'.
 func (r *Reply) XML(data interface{}) *Reply {
@@ -191,6 +236,41 @@ func (r *Reply) XML(data interface{}) *Reply {
 	return r
 }
 
+// SOAP method renders given data as body of SOAP envelope, version is
+// negotiated from request `Content-Type`. It sets HTTP Content-Type as
+// 'text/xml; charset=utf-8' for SOAP 1.1 and
+// 'application/soap+xml; charset=utf-8' for SOAP 1.2. If data is
+// `*soap.Fault` then fault is rendered with HTTP status `500` or `400` for
+// SOAP 1.2 sender fault.
+//
+//	ctx.Reply().SOAP(&GetOrderResponse{Order: order})
+//	ctx.Reply().SOAP(soap.NewFault(soap.FaultClient, "order not found"))
+func (r *Reply) SOAP(data interface{}) *Reply {
+	v := soap.VersionOf(r.ctx.Req.Header.Get(ahttp.HeaderContentType))
+	if f, ok := data.(*soap.Fault); ok {
+		r.Status(f.StatusCode(v))
+	}
+	r.ContentType(v.ContentType())
+	r.Render(&soapRender{Version: v, Body: data})
+	return r
+}
+
+// WSDL method sends the given WSDL file to client and it sets
+// HTTP Content-Type as 'text/xml; charset=utf-8'. Typically it's served
+// for request `GET /service?wsdl`.
+//
+//	if _, found := ctx.Req.URL().Query()["wsdl"]; found {
+//		ctx.Reply().WSDL("static/orders.wsdl")
+//		return
+//	}
+//
+// Note: If give filepath is relative path then application base directory is used
+// as prefix.
+func (r *Reply) WSDL(file string) *Reply {
+	r.ContentType(ahttp.ContentTypeXMLText.String())
+	return r.File(file)
+}
+
 // Text method renders given data as Plain Text response with given values
 // and it sets HTTP Content-Type as 'text/plain; charset=utf-8'.
 func (r *Reply) Text(format string, values ...interface{}) *Reply {
@@ -377,6 +457,67 @@ func (r *Reply) Cookie(cookie *http.Cookie) *Reply {
 	return r
 }
 
+// Trailer method declares the HTTP trailer for the response, its value is
+// obtained from given func after the response body is written on the wire.
+// Typically used for streamed responses to send checksum, timing, etc.
+//
+//	h := sha256.New()
+//	ctx.Reply().
+//		Trailer("X-Content-Sha256", func() string {
+//			return hex.EncodeToString(h.Sum(nil))
+//		}).
+//		FromReader(io.TeeReader(reader, h))
+//
+// Note: Trailer is not sent if the trailer func returns empty value. For
+// `Reply().Done()` use `ctx.Res.Header()` with declared `Trailer` header,
+// refer to `http.ResponseWriter` godoc.
+func (r *Reply) Trailer(key string, fn TrailerFunc) *Reply {
+	if len(key) == 0 || fn == nil {
+		return r
+	}
+	key = http.CanonicalHeaderKey(key)
+	r.ctx.Res.Header().Add(ahttp.HeaderTrailer, key)
+	r.trailers = append(r.trailers, trailer{key: key, fn: fn})
+	return r
+}
+
+// ETag method sets the entity tag of the reply, it takes precedence over
+// the computed ETag of config `render.etag.enable`. Value is quoted if need
+// be, use prefix `W/` for weak ETag. Empty value disables the ETag for the
+// reply. Conditional request `If-None-Match` is honored with `304 Not
+// Modified` response.
+//
+//	ctx.Reply().ETag(fmt.Sprintf("%d-%d", order.ID, order.Version)).JSON(order)
+func (r *Reply) ETag(etag string) *Reply {
+	r.etagSet = true
+	r.etag = ""
+	if etag = strings.TrimSpace(etag); len(etag) > 0 {
+		r.etag = makeETag(etag, false)
+	}
+	return r
+}
+
+// CacheFor method sets the response header `Cache-Control` to cache the
+// response publicly for given duration, for e.g.: `public, max-age=3600`.
+// Zero or negative duration is same as `NoCache()`. Use method `Header`
+// for other directives.
+//
+//	ctx.Reply().CacheFor(10 * time.Minute).JSON(catalog)
+func (r *Reply) CacheFor(d time.Duration) *Reply {
+	if d <= 0 {
+		return r.NoCache()
+	}
+	r.ctx.Res.Header().Del(ahttp.HeaderExpires)
+	return r.Header(ahttp.HeaderCacheControl, "public, max-age="+strconv.FormatInt(int64(d/time.Second), 10))
+}
+
+// NoCache method sets the response headers `Cache-Control` and `Expires`
+// to prevent the caching of response by clients and intermediaries.
+func (r *Reply) NoCache() *Reply {
+	r.ctx.Res.Header().Set(ahttp.HeaderExpires, "0")
+	return r.Header(ahttp.HeaderCacheControl, noCacheControl)
+}
+
 // DisableGzip method allows you disable Gzip for the reply. By default every
 // response is gzip compressed if the client supports it and gzip enabled in
 // app config.
@@ -547,6 +688,21 @@ func (x *xmlRender) Render(w io.Writer) error {
 	return xml.NewEncoder(w).Encode(x.Data)
 }
 
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// SOAP Render
+//______________________________________________________________________________
+
+// soapRender renders the response SOAP envelope.
+type soapRender struct {
+	Version soap.Version
+	Body    interface{}
+}
+
+// Render method writes SOAP envelope into HTTP response.
+func (s *soapRender) Render(w io.Writer) error {
+	return soap.Encode(w, s.Version, nil, s.Body)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Data
 //______________________________________________________________________________
@@ -582,15 +738,20 @@ func (d Data) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 //______________________________________________________________________________
 
 // Binary renders given path or io.Reader into response and closes the file.
+// Flush true flushes the response after each write of reader.
 type binaryRender struct {
 	Path   string
 	Reader io.Reader
+	Flush  bool
 }
 
 // Render method writes File into HTTP response.
 func (f *binaryRender) Render(w io.Writer) error {
 	if f.Reader != nil {
 		defer ess.CloseQuietly(f.Reader)
+		if fl, ok := w.(http.Flusher); ok && f.Flush {
+			w = flushWriter{w: w, f: fl}
+		}
 		_, err := io.Copy(w, f.Reader)
 		return err
 	}
@@ -10,12 +10,16 @@ import (
 	"html/template"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
+	"aahframe.work/soap"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -134,6 +138,94 @@ func TestReplyDone(t *testing.T) {
 	assert.True(t, re1.done)
 }
 
+func TestReplyTrailer(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "http://localhost:8080/export", nil))
+
+	var written int
+	ctx.Reply().
+		Trailer("x-content-length", func() string { return fmt.Sprintf("%d", written) }).
+		Trailer("X-Empty", func() string { return "" }).
+		Trailer("", func() string { return "skipped" }).
+		Trailer("X-Nil", nil)
+	assert.Equal(t, []string{"X-Content-Length", "X-Empty"}, ctx.Res.Header()[ahttp.HeaderTrailer])
+
+	ctx.Res.WriteHeader(http.StatusOK)
+	written, _ = ctx.Res.Write([]byte("streamed response"))
+	ctx.writeTrailers()
+
+	result := w.Result()
+	assert.Equal(t, "17", result.Trailer.Get("X-Content-Length"))
+	assert.Equal(t, "", result.Trailer.Get("X-Empty"))
+	assert.Equal(t, "streamed response", w.Body.String())
+}
+
+func TestReplyCacheControl(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest("GET", "http://localhost:8080/catalog", nil))
+	hdr := ctx.Res.Header()
+
+	ctx.Reply().NoCache()
+	assert.Equal(t, "no-cache, no-store, must-revalidate", hdr.Get(ahttp.HeaderCacheControl))
+	assert.Equal(t, "0", hdr.Get(ahttp.HeaderExpires))
+
+	ctx.Reply().CacheFor(10*time.Minute + 500*time.Millisecond)
+	assert.Equal(t, "public, max-age=600", hdr.Get(ahttp.HeaderCacheControl))
+	assert.Equal(t, "", hdr.Get(ahttp.HeaderExpires))
+
+	ctx.Reply().CacheFor(0)
+	assert.Equal(t, "no-cache, no-store, must-revalidate", hdr.Get(ahttp.HeaderCacheControl))
+}
+
+func TestReplySOAP(t *testing.T) {
+	type getOrder struct {
+		ID string `xml:"id"`
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/orders", strings.NewReader(
+		`<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope">
+		  <env:Body><GetOrder><id>1001</id></GetOrder></env:Body>
+		</env:Envelope>`))
+	req.Header.Set(ahttp.HeaderContentType, `application/soap+xml; charset=utf-8; action="urn:GetOrder"`)
+	ctx := newContext(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "urn:GetOrder", ctx.SOAPAction())
+	env, err := ctx.SOAPEnvelope()
+	assert.Nil(t, err)
+	assert.Equal(t, soap.V12, env.Version())
+	order := &getOrder{}
+	assert.Nil(t, env.DecodeBody(order))
+	assert.Equal(t, "1001", order.ID)
+
+	re := ctx.Reply().SOAP(soap.NewFault(soap.FaultClient, "order not found"))
+	assert.Equal(t, http.StatusBadRequest, re.Code)
+	assert.Equal(t, "application/soap+xml; charset=utf-8", re.ContType)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, re.Rdr.Render(buf))
+	env, err = soap.Decode(buf)
+	assert.Nil(t, err)
+	err = env.DecodeBody(order)
+	assert.Equal(t, "soap: fault Client: order not found", err.Error())
+
+	// SOAP 1.1 action header
+	req.Header.Set(ahttp.HeaderContentType, "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", `"urn:GetOrder"`)
+	ctx = newContext(httptest.NewRecorder(), req)
+	assert.Equal(t, "urn:GetOrder", ctx.SOAPAction())
+	re = ctx.Reply().SOAP(&getOrder{ID: "1002"})
+	assert.Equal(t, "text/xml; charset=utf-8", re.ContType)
+
+	// WSDL
+	wsdl := filepath.Join(t.TempDir(), "orders.wsdl")
+	assert.Nil(t, os.WriteFile(wsdl, []byte(`<definitions/>`), 0644))
+	re = ctx.Reply().WSDL(wsdl)
+	assert.Equal(t, ahttp.ContentTypeXMLText.String(), re.ContType)
+	buf.Reset()
+	assert.Nil(t, re.Rdr.Render(buf))
+	assert.Equal(t, "<definitions/>", buf.String())
+}
+
 // customRender implements the interface `aah.Render`.
 type customRender struct {
 	// ... your fields goes here
@@ -0,0 +1,39 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"aahframe.work/router"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initResponseHeaders method initializes the global response header rules
+// from config `server.headers { ... }`, it's applied after the security
+// headers and followed by route header rules. Route or routes group defines
+// its rules with `headers { ... }` in the routes config, see
+// `router.HeaderRules`.
+//
+//	server {
+//	  headers {
+//	    add {
+//	      x_service_name = "orders"
+//	    }
+//	    override {
+//	      x_frame_options = "DENY"
+//	    }
+//	    remove = ["x_powered_by"]
+//	  }
+//	}
+func (a *Application) initResponseHeaders() error {
+	hr, err := router.NewHeaderRules(a.Config(), "server.headers", nil)
+	if err != nil {
+		return err
+	}
+	a.respHeaders = hr
+	return nil
+}
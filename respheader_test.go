@@ -0,0 +1,62 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseHeaderRules(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `
+	server {
+	  headers {
+	    add {
+	      x_service_name = "orders"
+	    }
+	    override {
+	      x_frame_options = "DENY"
+	    }
+	    remove = ["x_powered_by"]
+	  }
+	}
+	`))
+	assert.Nil(t, a.initResponseHeaders())
+
+	w := httptest.NewRecorder()
+	ctx := newContext(w, httptest.NewRequest(ahttp.MethodGet, "/", nil))
+	ctx.a = a
+	ctx.route = &router.Route{Headers: &router.HeaderRules{
+		Override: map[string]string{"X-Service-Name": "orders-api"},
+	}}
+	ctx.Res.Header().Set("X-Powered-By", "aah")
+	ctx.Res.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	ctx.writeHeaders()
+
+	assert.Equal(t, "", w.Header().Get("X-Powered-By"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "orders-api", w.Header().Get("X-Service-Name"))
+
+	// not configured
+	assert.Nil(t, setTestConfig(a, ``))
+	assert.Nil(t, a.initResponseHeaders())
+	assert.Nil(t, a.respHeaders)
+
+	assert.Nil(t, setTestConfig(a, `
+	server {
+	  headers {
+	    add {
+	      x_retry = 10
+	    }
+	  }
+	}
+	`))
+	assert.Equal(t, "'server.headers.add.x_retry' value must be string", a.initResponseHeaders().Error())
+}
@@ -0,0 +1,306 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+)
+
+// Response scan actions, see `server.response_scan.action`.
+const (
+	ResponseScanActionLog    = "log"
+	ResponseScanActionRedact = "redact"
+	ResponseScanActionBlock  = "block"
+)
+
+// builtinScanPatterns are the sensitive data patterns available by name in
+// `server.response_scan.patterns`.
+var builtinScanPatterns = map[string]string{
+	"ssn":         `\b\d{3}-\d{2}-\d{4}\b`,
+	"credit_card": `\b(?:4\d{3}|5[1-5]\d{2}|3[47]\d{2}|6011)[ -]?\d{4}[ -]?\d{4}[ -]?\d{1,4}\b`,
+	"api_key":     `\b(?:AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_\-]{35}|sk_live_[0-9A-Za-z]{24,}|gh[pousr]_[0-9A-Za-z]{36})\b`,
+	"private_key": `-----BEGIN (?:RSA |EC |DSA |OPENSSH )?PRIVATE KEY-----`,
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// AddResponseScanner method adds the response scanner, it's called with
+// rendered response body before it's written on the wire. Configured action
+// `server.response_scan.action` is applied on the findings. Scanners are
+// called in the order they are added after the configured patterns.
+func (a *Application) AddResponseScanner(s ResponseScanner) {
+	a.Lock()
+	defer a.Unlock()
+	a.respScanners = append(a.respScanners, s)
+	if a.respScan != nil {
+		a.respScan.addScanner(s)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Response scanner
+//______________________________________________________________________________
+
+// ResponseScanner interface is used to inspect the response body for
+// sensitive data such as SSNs, API keys, etc. for DLP-oriented deployments.
+type ResponseScanner interface {
+	// Scan method returns the sensitive data findings of given response body.
+	Scan(ctx *Context, body []byte) []ResponseScanFinding
+}
+
+// ResponseScanFinding holds the sensitive data location in the response
+// body, `Start` and `End` are byte offsets like `regexp` match index.
+type ResponseScanFinding struct {
+	Name  string
+	Start int
+	End   int
+}
+
+var _ ResponseScanner = (*patternScanner)(nil)
+
+type patternScanner struct {
+	names    []string
+	patterns map[string]*regexp.Regexp
+}
+
+func (ps *patternScanner) Scan(_ *Context, body []byte) []ResponseScanFinding {
+	var findings []ResponseScanFinding
+	for _, name := range ps.names {
+		for _, loc := range ps.patterns[name].FindAllIndex(body, -1) {
+			findings = append(findings, ResponseScanFinding{Name: name, Start: loc[0], End: loc[1]})
+		}
+	}
+	return findings
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initResponseScan method initializes the outbound response scanning from
+// config `server.response_scan { ... }`. Built-in patterns are `ssn`,
+// `credit_card`, `api_key` and `private_key`, custom patterns are regular
+// expressions. Action `log` only logs the findings, action `redact` replaces
+// the findings with `redact_with` value and action `block` responds with
+// `500 Internal Server Error` instead of response body.
+//
+// Replies which cannot be scanned i.e. binary, reader, file, non-textual
+// content type or exceeding `max_body_size` are blocked on action `block`
+// unless `allow_unscanned` is true, otherwise they are written as-is and
+// logged.
+//
+//	server {
+//	  response_scan {
+//	    enable = true
+//	    action = "redact"
+//	    redact_with = "[REDACTED]"
+//	    max_body_size = "1mb"
+//	    allow_unscanned = false
+//	    patterns = ["ssn", "api_key"]
+//	    custom {
+//	      employee_id = "EMP-[0-9]{6}"
+//	    }
+//	  }
+//	}
+func (a *Application) initResponseScan() error {
+	keyPrefix := "server.response_scan"
+	cfg := a.Config()
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		a.respScan = nil
+		return nil
+	}
+
+	action := cfg.StringDefault(keyPrefix+".action", ResponseScanActionLog)
+	if action != ResponseScanActionLog && action != ResponseScanActionRedact && action != ResponseScanActionBlock {
+		return fmt.Errorf("'%s.action' has unsupported value '%s', supported values are log, redact and block", keyPrefix, action)
+	}
+
+	rs := &responseScanner{
+		action:         action,
+		redactWith:     []byte(cfg.StringDefault(keyPrefix+".redact_with", "[REDACTED]")),
+		allowUnscanned: cfg.BoolDefault(keyPrefix+".allow_unscanned", false),
+	}
+
+	var err error
+	if rs.maxBodySize, err = ess.StrToBytes(cfg.StringDefault(keyPrefix+".max_body_size", "1mb")); err != nil {
+		return fmt.Errorf("'%s.max_body_size': %s", keyPrefix, err)
+	}
+
+	ps := &patternScanner{patterns: make(map[string]*regexp.Regexp)}
+	names, _ := cfg.StringList(keyPrefix + ".patterns")
+	for _, name := range names {
+		p, found := builtinScanPatterns[name]
+		if !found {
+			return fmt.Errorf("'%s.patterns' has unsupported value '%s', supported values are api_key, credit_card, private_key and ssn", keyPrefix, name)
+		}
+		ps.patterns[name] = regexp.MustCompile(p)
+	}
+	for _, name := range cfg.KeysByPath(keyPrefix + ".custom") {
+		re, er := regexp.Compile(cfg.StringDefault(keyPrefix+".custom."+name, ""))
+		if er != nil {
+			return fmt.Errorf("'%s.custom.%s': %s", keyPrefix, name, er)
+		}
+		ps.patterns[name] = re
+	}
+	for name := range ps.patterns {
+		ps.names = append(ps.names, name)
+	}
+	sort.Strings(ps.names)
+
+	var scanners []ResponseScanner
+	if len(ps.names) > 0 {
+		scanners = append(scanners, ps)
+	}
+	a.Lock()
+	rs.scanners.Store(append(scanners, a.respScanners...))
+	a.respScan = rs
+	a.Unlock()
+	return nil
+}
+
+type responseScanner struct {
+	action         string
+	redactWith     []byte
+	maxBodySize    int64
+	allowUnscanned bool
+
+	// scanners holds []ResponseScanner, it's replaced on add (copy-on-write)
+	// since it's read concurrently by the requests
+	scanners atomic.Value
+}
+
+// Inspect method scans the rendered response body and applies the configured
+// action on findings. Response body of non-textual content type or exceeding
+// `max_body_size` is not scanned, see `Unscanned`.
+func (rs *responseScanner) Inspect(ctx *Context) {
+	re := ctx.Reply()
+	if re.body == nil || re.body.Len() == 0 {
+		return
+	}
+	if !isScannableContentType(re.ContType) {
+		rs.Unscanned(ctx, "content type '"+re.ContType+"'")
+		return
+	}
+	if rs.maxBodySize > 0 && int64(re.body.Len()) > rs.maxBodySize {
+		rs.Unscanned(ctx, "body size exceeds max_body_size")
+		return
+	}
+
+	body := re.body.Bytes()
+	var findings []ResponseScanFinding
+	for _, s := range rs.loadScanners() {
+		findings = append(findings, s.Scan(ctx, body)...)
+	}
+	if len(findings) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(findings))
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		if !seen[f.Name] {
+			seen[f.Name] = true
+			names = append(names, f.Name)
+		}
+	}
+	ctx.Log().Warnf("Response scan found sensitive data [%s] in response of '%s %s', action: %s",
+		strings.Join(names, ", "), ctx.Req.Method, ctx.Req.Path, rs.action)
+
+	switch rs.action {
+	case ResponseScanActionRedact:
+		redacted := redactFindings(body, findings, rs.redactWith)
+		re.body.Reset()
+		_, _ = re.body.Write(redacted)
+	case ResponseScanActionBlock:
+		rs.block(ctx)
+	}
+}
+
+// Unscanned method handles the reply which cannot be scanned for the given
+// reason, it's blocked on action `block` unless `allow_unscanned` is
+// enabled, otherwise it's logged.
+func (rs *responseScanner) Unscanned(ctx *Context, reason string) {
+	if rs.action == ResponseScanActionBlock && !rs.allowUnscanned {
+		ctx.Log().Warnf("Response scan blocked unscanned response of '%s %s', reason: %s",
+			ctx.Req.Method, ctx.Req.Path, reason)
+		rs.block(ctx)
+		return
+	}
+	ctx.Log().Debugf("Response scan skipped response of '%s %s', reason: %s",
+		ctx.Req.Method, ctx.Req.Path, reason)
+}
+
+// block method replaces the reply with `500 Internal Server Error` plain
+// text, reader of the binary reply is closed.
+func (rs *responseScanner) block(ctx *Context) {
+	re := ctx.Reply()
+	if br, ok := re.Rdr.(*binaryRender); ok && br.Reader != nil {
+		ess.CloseQuietly(br.Reader)
+	}
+	text := http.StatusText(http.StatusInternalServerError)
+	re.Code = http.StatusInternalServerError
+	re.ContType = ahttp.ContentTypePlainText.String()
+	re.Rdr = &textRender{Format: text}
+	ctx.Res.Header().Set(ahttp.HeaderContentType, re.ContType)
+	ctx.Res.Header().Del(ahttp.HeaderContentDisposition)
+	if re.body != nil {
+		re.body.Reset()
+		_, _ = re.body.WriteString(text)
+	}
+}
+
+func (rs *responseScanner) loadScanners() []ResponseScanner {
+	scanners, _ := rs.scanners.Load().([]ResponseScanner)
+	return scanners
+}
+
+// addScanner method adds the scanner into the copy of scanners and swaps it.
+// Caller holds the application lock.
+func (rs *responseScanner) addScanner(s ResponseScanner) {
+	current := rs.loadScanners()
+	scanners := make([]ResponseScanner, 0, len(current)+1)
+	rs.scanners.Store(append(append(scanners, current...), s))
+}
+
+// redactFindings method returns the copy of body with findings replaced,
+// overlapping findings are merged.
+func redactFindings(body []byte, findings []ResponseScanFinding, with []byte) []byte {
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Start < findings[j].Start })
+	buf := bytes.NewBuffer(make([]byte, 0, len(body)))
+	pos := 0
+	for _, f := range findings {
+		if f.Start < 0 || f.End > len(body) || f.Start >= f.End {
+			continue
+		}
+		if f.Start < pos { // overlapping
+			if f.End > pos {
+				pos = f.End
+			}
+			continue
+		}
+		_, _ = buf.Write(body[pos:f.Start])
+		_, _ = buf.Write(with)
+		pos = f.End
+	}
+	_, _ = buf.Write(body[pos:])
+	return buf.Bytes()
+}
+
+func isScannableContentType(ct string) bool {
+	ct = strings.ToLower(ct)
+	return strings.HasPrefix(ct, "text/") || strings.Contains(ct, "json") ||
+		strings.Contains(ct, "xml") || strings.Contains(ct, "javascript")
+}
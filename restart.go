@@ -0,0 +1,81 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables used to pass the server listener and readiness pipe
+// file descriptors from old process into new process on zero-downtime
+// restart.
+const (
+	envListenerFD = "AAH_LISTENER_FD"
+	envReadyFD    = "AAH_READY_FD"
+)
+
+// filer interface is implemented by `*net.TCPListener` and
+// `*net.UnixListener`, used to obtain the listener file descriptor.
+type filer interface {
+	File() (*os.File, error)
+}
+
+// inheritedListener method returns the server listener passed by the old
+// process on zero-downtime restart otherwise nil.
+func inheritedListener() (net.Listener, error) {
+	v, found := os.LookupEnv(envListenerFD)
+	if !found {
+		return nil, nil
+	}
+	_ = os.Unsetenv(envListenerFD)
+
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("aah: invalid inherited listener fd '%s'", v)
+	}
+	f := os.NewFile(uintptr(fd), "listener")
+	defer func() { _ = f.Close() }()
+
+	listener, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("aah: unable to use inherited listener: %s", err)
+	}
+	return listener, nil
+}
+
+// notifyRestartReady method notifies the old process that new process is
+// ready to serve the requests, so old process can drain and shutdown.
+func notifyRestartReady() {
+	v, found := os.LookupEnv(envReadyFD)
+	if !found {
+		return
+	}
+	_ = os.Unsetenv(envReadyFD)
+
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return
+	}
+	f := os.NewFile(uintptr(fd), "ready")
+	_, _ = f.Write([]byte{1})
+	_ = f.Close()
+}
+
+// restartEnv method returns the current process environment without the
+// restart file descriptor variables.
+func restartEnv() []string {
+	var env []string
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, envListenerFD+"=") || strings.HasPrefix(e, envReadyFD+"=") {
+			continue
+		}
+		env = append(env, e)
+	}
+	return env
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build !windows
+
+package aah
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// listenForRestart method listens to OS signal `SIGUSR2` for zero-downtime
+// restart if `server.restart.enable` is true. On signal it starts the new
+// process of application binary with server listener file descriptor, once
+// new process is ready old process drains the in-flight requests and
+// shutdown gracefully with `server.timeout.grace_shutdown`.
+func (a *Application) listenForRestart() {
+	if !a.settings().RestartEnabled {
+		return
+	}
+
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGUSR2)
+	for range sc {
+		a.Log().Warn("Restart signal (SIGUSR2) received")
+		if err := a.restart(); err != nil {
+			a.Log().Errorf("Restart failed, current process continues to serve: %s", err)
+			continue
+		}
+		signal.Stop(sc)
+		close(a.restarted)
+		return
+	}
+}
+
+// restart method starts the new process with inherited server listener and
+// waits for its readiness until `server.timeout.grace_shutdown`.
+func (a *Application) restart() error {
+	a.RLock()
+	listener := a.listener
+	a.RUnlock()
+
+	fl, ok := listener.(filer)
+	if !ok {
+		return errors.New("server listener does not support file descriptor passing")
+	}
+	lf, err := fl.File()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lf.Close() }()
+
+	rp, wp, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rp.Close() }()
+
+	binary, err := os.Executable()
+	if err != nil {
+		_ = wp.Close()
+		return err
+	}
+
+	// ExtraFiles entry i becomes file descriptor 3+i in new process
+	cmd := exec.Command(binary, os.Args[1:]...)
+	cmd.Env = append(restartEnv(), envListenerFD+"=3", envReadyFD+"=4")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = []*os.File{lf, wp}
+	err = cmd.Start()
+	_ = wp.Close()
+	if err != nil {
+		return err
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		b := make([]byte, 1)
+		_, er := rp.Read(b)
+		ready <- er
+	}()
+
+	select {
+	case err = <-ready:
+		if err != nil {
+			err = fmt.Errorf("new process (pid %d) exited before ready", cmd.Process.Pid)
+		}
+	case <-time.After(a.settings().ShutdownGraceTimeout):
+		err = fmt.Errorf("new process (pid %d) is not ready within %s", cmd.Process.Pid, a.settings().ShutdownGraceTimeStr)
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	// Unix socket file is in use by new process
+	if ul, ok := listener.(*net.UnixListener); ok {
+		ul.SetUnlinkOnClose(false)
+	}
+	a.Log().Infof("New process (pid %d) is ready, draining in-flight requests of current process", cmd.Process.Pid)
+	return cmd.Process.Release()
+}
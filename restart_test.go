@@ -0,0 +1,91 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build !windows
+
+package aah
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerRestartInheritedListener(t *testing.T) {
+	// no inherited listener
+	l, err := inheritedListener()
+	assert.Nil(t, err)
+	assert.Nil(t, l)
+
+	ol, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ol.Close()
+	lf, err := ol.(*net.TCPListener).File()
+	assert.Nil(t, err)
+	defer lf.Close()
+
+	_ = os.Setenv(envListenerFD, strconv.Itoa(int(lf.Fd())))
+	l, err = inheritedListener()
+	assert.Nil(t, err)
+	assert.NotNil(t, l)
+	assert.Equal(t, ol.Addr().String(), l.Addr().String())
+	_, found := os.LookupEnv(envListenerFD)
+	assert.False(t, found)
+	_ = l.Close()
+
+	_ = os.Setenv(envListenerFD, "invalid")
+	_, err = inheritedListener()
+	assert.Equal(t, "aah: invalid inherited listener fd 'invalid'", err.Error())
+}
+
+func TestServerRestartNotifyReady(t *testing.T) {
+	rp, wp, err := os.Pipe()
+	assert.Nil(t, err)
+	defer rp.Close()
+
+	_ = os.Setenv(envReadyFD, strconv.Itoa(int(wp.Fd())))
+	_ = os.Setenv(envListenerFD, "3")
+	assert.NotContains(t, restartEnv(), envReadyFD+"="+strconv.Itoa(int(wp.Fd())))
+	assert.NotContains(t, restartEnv(), envListenerFD+"=3")
+	_ = os.Unsetenv(envListenerFD)
+
+	notifyRestartReady()
+	b := make([]byte, 1)
+	n, err := rp.Read(b)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	_, found := os.LookupEnv(envReadyFD)
+	assert.False(t, found)
+}
+
+func TestServerRestartSettings(t *testing.T) {
+	a := newApp()
+	assert.Nil(t, setTestConfig(a, `
+	server {
+	  restart {
+	    enable = true
+	  }
+	}
+	runtime {
+	  config_hotreload {
+	    signal = "SIGUSR2"
+	  }
+	}
+	env {
+	  dev { }
+	}`))
+	err := a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.restart.enable' uses signal SIGUSR2, choose different 'runtime.config_hotreload.signal'", err.Error())
+
+	a.cfg.SetString("runtime.config_hotreload.signal", "SIGHUP")
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+	assert.True(t, a.settings().RestartEnabled)
+
+	// listener of unix socket and tcp supports fd passing
+	var _ filer = (*net.TCPListener)(nil)
+	var _ filer = (*net.UnixListener)(nil)
+}
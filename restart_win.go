@@ -0,0 +1,15 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package aah
+
+// listenForRestart method is not supported on OS Windows, it does not have
+// signal `SIGUSR2` and listener file descriptor passing.
+func (a *Application) listenForRestart() {
+	if a.settings().RestartEnabled {
+		a.Log().Warn("OS Windows does not support zero-downtime restart, 'server.restart.enable' is ignored")
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"time"
+)
+
+// setRouteDeadlines method sets the connection read and write deadline of
+// current request as per route `timeout.read` and `timeout.write`, it
+// overrides `server.timeout.read` and `server.timeout.write` for the route.
+// For e.g.: long-polling and streaming endpoints.
+func (ctx *Context) setRouteDeadlines() {
+	rc := http.NewResponseController(ctx.Res)
+	now := time.Now()
+	if d := ctx.route.ReadTimeout; d > 0 {
+		if err := rc.SetReadDeadline(now.Add(d)); err != nil {
+			ctx.Log().Debugf("Unable to set route read deadline, Route: %s, Error: %v", ctx.route.Name, err)
+		}
+	}
+	if d := ctx.route.WriteTimeout; d > 0 {
+		if err := rc.SetWriteDeadline(now.Add(d)); err != nil {
+			ctx.Log().Debugf("Unable to set route write deadline, Route: %s, Error: %v", ctx.route.Name, err)
+		}
+	}
+}
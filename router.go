@@ -5,6 +5,7 @@
 package aah
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,16 +17,54 @@ import (
 	"aahframe.work/valpar"
 )
 
+// NotFoundHandlerFunc is a function type. It is used to define a custom not
+// found handler for an application, see `Application.SetNotFoundHandler`.
+type NotFoundHandlerFunc func(ctx *Context)
+
+// MethodNotAllowedHandlerFunc is a function type. It is used to define a custom
+// method not allowed handler for an application, `allowed` has the HTTP
+// methods supported by the request path. See `Application.SetMethodNotAllowedHandler`.
+type MethodNotAllowedHandlerFunc func(ctx *Context, allowed []string)
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Package methods
 //______________________________________________________________________________
 
 // RouteMiddleware method performs the routing logic.
+//
+// If route has concurrency limit `max_concurrent` then request waits in the
+// route queue for the free slot, on queue full or timeout request is
+// rejected with `503 Service Unavailable`.
+//
+// If route has `timeout` then request context deadline is set accordingly,
+// it can only shorten the server request timeout `server.timeout.request`.
+// Route `timeout.read` and `timeout.write` overrides the server read and
+// write timeouts for the request.
 func RouteMiddleware(ctx *Context, m *Middleware) {
 	if handleRoute(ctx) == flowAbort {
 		return
 	}
 
+	if ctx.route.Timeout > 0 {
+		c, cancel := context.WithTimeout(ctx.Req.Context(), ctx.route.Timeout)
+		defer cancel()
+		ctx.Req.SetContext(c)
+	}
+
+	if ctx.route.ReadTimeout > 0 || ctx.route.WriteTimeout > 0 {
+		ctx.setRouteDeadlines()
+	}
+
+	if bh := ctx.route.Bulkhead; bh != nil {
+		if !bh.Acquire() {
+			ctx.Log().Warnf("Route concurrency limit exceeded, Route: %s, InFlight: %d, Queued: %d",
+				ctx.route.Name, bh.InFlight(), bh.Queued())
+			ctx.Reply().ServiceUnavailable().Error(newError(ErrRouteConcurrencyExceeded, http.StatusServiceUnavailable))
+			return
+		}
+		defer bh.Release()
+	}
+
 	m.Next(ctx)
 }
 
@@ -59,10 +98,8 @@ func CORSMiddleware(ctx *Context, m *Middleware) {
 		ctx.Reply().Header(ahttp.HeaderAccessControlAllowOrigin, origin)
 	}
 
-	if len(cors.ExposeHeaders) > 0 {
-		ctx.Reply().Header(ahttp.HeaderAccessControlExposeHeaders, strings.Join(cors.ExposeHeaders, ", "))
-	} else if len(cors.AllowHeaders) > 0 {
-		ctx.Reply().Header(ahttp.HeaderAccessControlExposeHeaders, strings.Join(cors.AllowHeaders, ", "))
+	if exposeHeaders := cors.ExposeHeadersValue(); len(exposeHeaders) > 0 {
+		ctx.Reply().Header(ahttp.HeaderAccessControlExposeHeaders, exposeHeaders)
 	}
 
 	if cors.AllowCredentials {
@@ -100,7 +137,7 @@ func handleCORSPreflight(ctx *Context) {
 		method = h[0]
 	}
 	if cors.IsMethodAllowed(method) {
-		ctx.Reply().Header(ahttp.HeaderAccessControlAllowMethods, strings.Join(cors.AllowMethods, ", "))
+		ctx.Reply().Header(ahttp.HeaderAccessControlAllowMethods, cors.AllowMethodsValue())
 	} else {
 		ctx.Log().Warnf("CORS: preflight request - method not allowed '%s' for path %s",
 			method, ctx.Req.Path)
@@ -115,7 +152,7 @@ func handleCORSPreflight(ctx *Context) {
 	}
 	if cors.IsHeadersAllowed(hdrs) {
 		if len(cors.AllowHeaders) > 0 {
-			ctx.Reply().Header(ahttp.HeaderAccessControlAllowHeaders, strings.Join(cors.AllowHeaders, ", "))
+			ctx.Reply().Header(ahttp.HeaderAccessControlAllowHeaders, cors.AllowHeadersValue())
 		}
 	} else {
 		ctx.Log().Warnf("CORS: preflight request - headers not allowed '%s' for path %s",
@@ -128,6 +165,18 @@ func handleCORSPreflight(ctx *Context) {
 		ctx.Reply().Header(ahttp.HeaderAccessControlAllowCredentials, "true")
 	}
 
+	// Private Network Access (PNA) preflight, browser blocks the request
+	// if it's not allowed
+	if strings.EqualFold(ctx.Req.Header.Get(ahttp.HeaderAccessControlRequestPrivateNetwork), "true") {
+		ctx.Reply().HeaderAppend(ahttp.HeaderVary, ahttp.HeaderAccessControlRequestPrivateNetwork)
+		if cors.AllowPrivateNetwork {
+			ctx.Reply().Header(ahttp.HeaderAccessControlAllowPrivateNetwork, "true")
+		} else {
+			ctx.Log().Warnf("CORS: preflight request - private network access not allowed for path %s",
+				ctx.Req.Path)
+		}
+	}
+
 	if len(cors.MaxAge) > 0 {
 		ctx.Reply().Header(ahttp.HeaderAccessControlMaxAge, cors.MaxAge)
 	}
@@ -145,6 +194,25 @@ func (a *Application) initRouter() error {
 	if err != nil {
 		return fmt.Errorf("routes.conf: %s", err)
 	}
+
+	a.RLock()
+	defs := a.routeDefs
+	a.RUnlock()
+	for _, d := range defs {
+		if err = rtr.AddDefinition(d); err != nil {
+			return err
+		}
+	}
+
+	debugDefs, err := a.initDebug()
+	if err != nil {
+		return err
+	}
+	for _, d := range debugDefs {
+		if err = rtr.AddDefinition(d); err != nil {
+			return err
+		}
+	}
 	a.router = rtr
 	return nil
 }
@@ -158,6 +226,7 @@ func (a *Application) initRouter() error {
 //  - finding domain
 //  - finding route
 //  - handling static route
+//  - serving well-known endpoints
 //  - handling redirect trailing slash
 //  - auto options
 //  - route not found
@@ -177,23 +246,47 @@ func handleRoute(ctx *Context) flowResult {
 
 	route, urlParams, rts := ctx.domain.Lookup(ctx.Req.Unwrap())
 	if route == nil { // route not found
+		if ctx.a.wellKnown != nil && ctx.a.wellKnown.Serve(ctx) {
+			return flowAbort
+		}
+		if ctx.a.failedReqs != nil && ctx.a.failedReqs.Serve(ctx) {
+			return flowAbort
+		}
+		if ctx.a.serveConfigDump(ctx) {
+			return flowAbort
+		}
+		if ctx.a.reqMetrics != nil && ctx.a.reqMetrics.Serve(ctx) {
+			return flowAbort
+		}
+		if ctx.a.healthEndpoint != nil && ctx.a.healthEndpoint.Serve(ctx) {
+			return flowAbort
+		}
+		if ctx.a.runtimeCtl != nil && ctx.a.runtimeCtl.Serve(ctx) {
+			return flowAbort
+		}
+
 		if err := handleRtsOptionsMna(ctx, rts); err == nil {
 			return flowAbort
 		}
 
 		ctx.Log().Warnf("Route not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
-		ctx.Reply().NotFound().Error(newError(ErrRouteNotFound, http.StatusNotFound))
+		handleNotFound(ctx, ErrRouteNotFound)
 		return flowAbort
 	}
 	ctx.route = route
 	ctx.Req.URLParams = urlParams
 
+	// Maintenance mode, allowed routes are served as usual
+	if ctx.a.runtimeCtl != nil && ctx.a.runtimeCtl.IsUnderMaintenance(ctx) {
+		return flowAbort
+	}
+
 	// Serving static file
 	if route.IsStatic {
 		if err := ctx.a.staticMgr.Serve(ctx); err == errFileNotFound {
 			ctx.Log().Warnf("Static file not found, Host: %s, Path: %s", ctx.Req.Host, ctx.Req.Path)
 			ctx.Reply().done = false
-			ctx.Reply().NotFound().Error(newError(ErrStaticFileNotFound, http.StatusNotFound))
+			handleNotFound(ctx, ErrStaticFileNotFound)
 		}
 		return flowAbort
 	}
@@ -207,9 +300,37 @@ func handleRoute(ctx *Context) flowResult {
 		}
 	}
 
+	// Locale-prefixed route, locale from path takes precedence over
+	// HTTP header `Accept-Language`
+	if route.IsLocalized() {
+		if len(route.Locale) > 0 {
+			ctx.Req.SetLocale(ahttp.NewLocale(route.Locale))
+		}
+		if ctx.Req.Method == ahttp.MethodGet || ctx.Req.Method == ahttp.MethodHead {
+			addLocaleLinks(ctx)
+		}
+	}
+
 	return flowCont
 }
 
+// addLocaleLinks method adds the HTTP header `Link` with canonical and
+// alternate URLs of locale-prefixed route, for e.g.:
+//	Link: <https://example.com/en/products>; rel="canonical"
+//	Link: <https://example.com/de/products>; rel="alternate"; hreflang="de"
+func addLocaleLinks(ctx *Context) {
+	baseURL := ctx.Req.Scheme + "://" + ctx.Req.Host
+	reqPath := ctx.Req.Path
+	ctx.Reply().HeaderAppend(ahttp.HeaderLink, fmt.Sprintf(`<%s%s>; rel="canonical"`,
+		baseURL, ctx.route.LocalePath(ctx.domain.DefaultLocale, reqPath)))
+	for _, l := range ctx.route.Locales {
+		ctx.Reply().HeaderAppend(ahttp.HeaderLink, fmt.Sprintf(`<%s%s>; rel="alternate"; hreflang="%s"`,
+			baseURL, ctx.route.LocalePath(l, reqPath), l))
+	}
+	ctx.Reply().HeaderAppend(ahttp.HeaderLink, fmt.Sprintf(`<%s%s>; rel="alternate"; hreflang="x-default"`,
+		baseURL, ctx.route.LocalePath("", reqPath)))
+}
+
 // handleRtsOptionsMna method handles
 // 1) Redirect Trailing Slash
 // 2) Auto Options
@@ -243,18 +364,21 @@ func handleRtsOptionsMna(ctx *Context, rts bool) error {
 
 	// HTTP: OPTIONS
 	if reqMethod == ahttp.MethodOptions {
-		if domain.AutoOptions {
-			if processAllowedMethods(reply, domain.Allowed(reqMethod, reqPath), "Auto 'OPTIONS', ") {
-				ctx.Reply().Text("")
-				return nil
-			}
+		if processAllowedMethods(reply, domain.AutoOptionsAllowed(reqPath), "Auto 'OPTIONS', ") {
+			ctx.Reply().Text("")
+			return nil
 		}
 	}
 
 	// 405 Method Not Allowed
 	if domain.MethodNotAllowed {
 		if processAllowedMethods(reply, domain.Allowed(reqMethod, reqPath), "405 response, ") {
-			ctx.Reply().MethodNotAllowed().Error(newError(ErrHTTPMethodNotAllowed, http.StatusMethodNotAllowed))
+			reply.MethodNotAllowed()
+			if fn := ctx.a.mnaFn; fn != nil {
+				fn(ctx, strings.Split(ctx.Res.Header().Get(ahttp.HeaderAllow), ", "))
+			} else {
+				reply.Error(newError(ErrHTTPMethodNotAllowed, http.StatusMethodNotAllowed))
+			}
 			return nil
 		}
 	}
@@ -262,6 +386,17 @@ func handleRtsOptionsMna(ctx *Context, rts bool) error {
 	return errors.New("route not found")
 }
 
+// handleNotFound method replies not found via application not found handler
+// if registered otherwise default error handling.
+func handleNotFound(ctx *Context, err error) {
+	ctx.Reply().NotFound()
+	if fn := ctx.a.notFoundFn; fn != nil {
+		fn(ctx)
+		return
+	}
+	ctx.Reply().Error(newError(err, http.StatusNotFound))
+}
+
 func processAllowedMethods(reply *Reply, allowed, prefix string) bool {
 	if len(allowed) > 0 {
 		allowed += ", " + ahttp.MethodOptions
@@ -0,0 +1,123 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/internal/settings"
+)
+
+// Bulkhead limits the number of concurrent requests served by the route or
+// routes group, so that one slow endpoint cannot consume all the server
+// goroutines. Requests beyond the limit wait in the bounded queue for
+// `queue_timeout`, on queue full or timeout request is rejected.
+//
+//	max_concurrent = 20
+//	max_queue = 50
+//	queue_timeout = "1s"
+//
+// Bulkhead configured on the parent route is shared by its child routes.
+// Child route can define its own limit or opt-out via `max_concurrent = 0`.
+type Bulkhead struct {
+	MaxConcurrent int
+	MaxQueue      int
+	QueueTimeout  time.Duration
+
+	slots  chan struct{}
+	queued int32
+}
+
+// NewBulkhead method creates the bulkhead for given limits.
+func NewBulkhead(maxConcurrent, maxQueue int, queueTimeout time.Duration) *Bulkhead {
+	return &Bulkhead{
+		MaxConcurrent: maxConcurrent,
+		MaxQueue:      maxQueue,
+		QueueTimeout:  queueTimeout,
+		slots:         make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Acquire method reserves the slot for the request. If all the slots are
+// in-use then it waits in the queue up to queue timeout. Returns false if
+// queue is full or wait timed out.
+func (b *Bulkhead) Acquire() bool {
+	select {
+	case b.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if atomic.AddInt32(&b.queued, 1) > int32(b.MaxQueue) {
+		atomic.AddInt32(&b.queued, -1)
+		return false
+	}
+	defer atomic.AddInt32(&b.queued, -1)
+
+	timer := time.NewTimer(b.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case b.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// Release method releases the slot reserved by `Acquire`.
+func (b *Bulkhead) Release() {
+	<-b.slots
+}
+
+// InFlight method returns the number of requests currently being served.
+func (b *Bulkhead) InFlight() int {
+	return len(b.slots)
+}
+
+// Queued method returns the number of requests waiting in the queue.
+func (b *Bulkhead) Queued() int {
+	return int(atomic.LoadInt32(&b.queued))
+}
+
+// String method is Stringer interface.
+func (b *Bulkhead) String() string {
+	return fmt.Sprintf("bulkhead(maxconcurrent:%d maxqueue:%d queuetimeout:%s)",
+		b.MaxConcurrent, b.MaxQueue, b.QueueTimeout)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// parseBulkhead method returns the route bulkhead, if route does not define
+// `max_concurrent` then parent bulkhead is used.
+func parseBulkhead(cfg *config.Config, routeName string, parent *Bulkhead) (*Bulkhead, error) {
+	if !cfg.IsExists(routeName + ".max_concurrent") {
+		return parent, nil
+	}
+
+	maxConcurrent := cfg.IntDefault(routeName+".max_concurrent", 0)
+	if maxConcurrent < 0 {
+		return nil, fmt.Errorf("'%v.max_concurrent' value must not be negative", routeName)
+	}
+	if maxConcurrent == 0 {
+		return nil, nil
+	}
+
+	maxQueue := cfg.IntDefault(routeName+".max_queue", 0)
+	if maxQueue < 0 {
+		return nil, fmt.Errorf("'%v.max_queue' value must not be negative", routeName)
+	}
+
+	d, err := settings.ParseDuration(cfg, routeName+".queue_timeout", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBulkhead(maxConcurrent, maxQueue, d), nil
+}
@@ -16,6 +16,7 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/log"
 )
 
@@ -47,10 +48,11 @@ var (
 // Spec: https://www.w3.org/TR/cors/
 // Friendly Read: https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS
 type CORS struct {
-	AllowCredentials bool
-	allowAllOrigins  bool
-	allowAllMethods  bool
-	allowAllHeaders  bool
+	AllowCredentials    bool
+	AllowPrivateNetwork bool
+	allowAllOrigins     bool
+	allowAllMethods     bool
+	allowAllHeaders     bool
 
 	MaxAge        string
 	maxAgeStr     string
@@ -125,6 +127,37 @@ func (c *CORS) SetAllowCredentials(b bool) *CORS {
 	return c
 }
 
+// SetAllowPrivateNetwork method sets the given boolean into allow private
+// network, it's used to reply Private Network Access (PNA) preflight requests.
+//
+// Spec: https://wicg.github.io/private-network-access/
+func (c *CORS) SetAllowPrivateNetwork(b bool) *CORS {
+	c.AllowPrivateNetwork = b
+	return c
+}
+
+// AllowMethodsValue method returns the `Access-Control-Allow-Methods` header
+// value of configured allow methods.
+func (c *CORS) AllowMethodsValue() string {
+	return strings.Join(c.AllowMethods, ", ")
+}
+
+// AllowHeadersValue method returns the `Access-Control-Allow-Headers` header
+// value of configured allow headers.
+func (c *CORS) AllowHeadersValue() string {
+	return strings.Join(c.AllowHeaders, ", ")
+}
+
+// ExposeHeadersValue method returns the `Access-Control-Expose-Headers` header
+// value of configured expose headers, if not configured then allow headers
+// are exposed.
+func (c *CORS) ExposeHeadersValue() string {
+	if len(c.ExposeHeaders) > 0 {
+		return strings.Join(c.ExposeHeaders, ", ")
+	}
+	return c.AllowHeadersValue()
+}
+
 // IsOriginAllowed method check given origin is allowed or not.
 func (c *CORS) IsOriginAllowed(origin string) bool {
 	if len(origin) == 0 {
@@ -181,6 +214,7 @@ func (c CORS) String() string {
 	buf.WriteString(" expose-headers:")
 	buf.WriteString(strings.Join(c.ExposeHeaders, ","))
 	buf.WriteString(fmt.Sprintf(" allow-credentials:%v", c.AllowCredentials))
+	buf.WriteString(fmt.Sprintf(" allow-private-network:%v", c.AllowPrivateNetwork))
 	buf.WriteString(fmt.Sprintf(" max-age:%s", c.maxAgeStr))
 	buf.WriteByte(')')
 	return buf.String()
@@ -203,7 +237,7 @@ func (c *CORS) addHeaders(dst []string, src []string) []string {
 	return dst
 }
 
-func processBaseCORSSection(cfg *config.Config) *CORS {
+func processBaseCORSSection(cfg *config.Config) (*CORS, error) {
 	cors := &CORS{}
 
 	// Access-Control-Allow-Origin
@@ -231,16 +265,20 @@ func processBaseCORSSection(cfg *config.Config) *CORS {
 	// Access-Control-Allow-Credentials
 	cors.SetAllowCredentials(cfg.BoolDefault("allow_credentials", false))
 
+	// Access-Control-Allow-Private-Network
+	cors.SetAllowPrivateNetwork(cfg.BoolDefault("allow_private_network", false))
+
 	// Access-Control-Expose-Headers
 	if hdrs, found := cfg.StringList("expose_headers"); found {
 		cors.AddExposeHeaders(hdrs)
 	}
 
 	// Access-Control-Max-Age
-	cors.maxAgeStr = cfg.StringDefault("max_age", "24h")
-	cors.SetMaxAge(cors.maxAgeStr)
+	if err := cors.parseMaxAge(cfg, "24h"); err != nil {
+		return nil, err
+	}
 
-	return cors
+	return cors, nil
 }
 
 func processCORSSection(cfg *config.Config, parent *CORS) (*CORS, error) {
@@ -273,6 +311,9 @@ func processCORSSection(cfg *config.Config, parent *CORS) (*CORS, error) {
 	// Access-Control-Allow-Credentials
 	cors.SetAllowCredentials(cfg.BoolDefault("allow_credentials", parent.AllowCredentials))
 
+	// Access-Control-Allow-Private-Network
+	cors.SetAllowPrivateNetwork(cfg.BoolDefault("allow_private_network", parent.AllowPrivateNetwork))
+
 	// Access-Control-Expose-Headers
 	if hdrs, found := cfg.StringList("expose_headers"); found {
 		cors.AddExposeHeaders(hdrs)
@@ -281,8 +322,35 @@ func processCORSSection(cfg *config.Config, parent *CORS) (*CORS, error) {
 	}
 
 	// Access-Control-Max-Age
-	cors.maxAgeStr = cfg.StringDefault("max_age", parent.maxAgeStr)
-	cors.SetMaxAge(cors.maxAgeStr)
+	if err := cors.parseMaxAge(cfg, parent.maxAgeStr); err != nil {
+		return nil, err
+	}
 
 	return cors, nil
 }
+
+// parseMaxAge method parses the preflight cache duration from config
+// `max_age`. Value `0s` disables the preflight cache, browsers cap the
+// value, e.g. Chromium at `2h` and Firefox at `24h`. Plain integer value is
+// treated as seconds.
+func (c *CORS) parseMaxAge(cfg *config.Config, defaultValue string) error {
+	if v, found := cfg.String("max_age"); found {
+		if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			if secs < 0 {
+				return errors.New("cors: 'max_age' value must not be negative")
+			}
+			c.maxAgeStr = v
+			c.MaxAge = strconv.Itoa(secs)
+			return nil
+		}
+	}
+
+	def, _ := time.ParseDuration(defaultValue)
+	d, err := settings.ParseDuration(cfg, "max_age", def)
+	if err != nil {
+		return fmt.Errorf("cors: %s", err)
+	}
+	c.maxAgeStr = cfg.StringDefault("max_age", defaultValue)
+	c.MaxAge = strconv.Itoa(int(d.Seconds()))
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"aahframe.work/config"
 	"aahframe.work/essentials"
 	"aahframe.work/log"
 	"github.com/stretchr/testify/assert"
@@ -59,7 +60,10 @@ func TestRouterCORS2(t *testing.T) {
 	assert.True(t, domain.CORS.IsMethodAllowed("DELETE"))
 	assert.True(t, ess.IsSliceContainsString(domain.CORS.ExposeHeaders, "X-Base-Test2"))
 	assert.True(t, domain.CORS.AllowCredentials)
+	assert.True(t, domain.CORS.AllowPrivateNetwork)
 	assert.Equal(t, "172800", domain.CORS.MaxAge)
+	assert.Equal(t, "DELETE, OPTIONS", domain.CORS.AllowMethodsValue())
+	assert.Equal(t, "X-Base-Test2", domain.CORS.ExposeHeadersValue())
 
 	routes := router.Lookup("localhost:8080").routes
 	assert.NotNil(t, routes)
@@ -82,9 +86,47 @@ func TestRouterCORS2(t *testing.T) {
 	assert.True(t, deleteUserRoute.CORS.IsMethodAllowed("DELETE"))
 	assert.False(t, deleteUserRoute.CORS.IsMethodAllowed("HEAD"))
 	assert.True(t, deleteUserRoute.CORS.AllowCredentials)
+	assert.True(t, deleteUserRoute.CORS.AllowPrivateNetwork)
 	assert.Equal(t, "172800", deleteUserRoute.CORS.MaxAge)
 
 	updateUserRoute := routes["update_user"]
 	assert.Nil(t, updateUserRoute.CORS)
 
 }
+
+func TestRouterCORSMaxAgeAndPrivateNetwork(t *testing.T) {
+	parent := &CORS{AllowPrivateNetwork: true, maxAgeStr: "24h", MaxAge: "86400"}
+
+	cfg, _ := config.ParseString(`max_age = "0s";`)
+	cors, err := processCORSSection(cfg, parent)
+	assert.Nil(t, err)
+	assert.Equal(t, "0", cors.MaxAge)
+	assert.True(t, cors.AllowPrivateNetwork)
+
+	cfg, _ = config.ParseString(`allow_private_network = false;`)
+	cors, err = processCORSSection(cfg, parent)
+	assert.Nil(t, err)
+	assert.Equal(t, "86400", cors.MaxAge)
+	assert.False(t, cors.AllowPrivateNetwork)
+
+	cors = &CORS{}
+	cors.AddAllowHeaders([]string{"x-api-key", "authorization"})
+	assert.Equal(t, "X-Api-Key, Authorization", cors.AllowHeadersValue())
+	assert.Equal(t, "X-Api-Key, Authorization", cors.ExposeHeadersValue())
+
+	// plain integer value is seconds
+	cfg, _ = config.ParseString(`max_age = "565758";`)
+	cors, err = processCORSSection(cfg, parent)
+	assert.Nil(t, err)
+	assert.Equal(t, "565758", cors.MaxAge)
+
+	for _, c := range []struct{ cfg, err string }{
+		{`max_age = "48x";`, "cors: 'max_age' value is not a valid time unit"},
+		{`max_age = "-1";`, "cors: 'max_age' value must not be negative"},
+		{`max_age = "-1h";`, "cors: 'max_age' value must not be negative"},
+	} {
+		cfg, _ = config.ParseString(c.cfg)
+		_, err = processCORSSection(cfg, parent)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
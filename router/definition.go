@@ -0,0 +1,226 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+	"aahframe.work/security/anticsrf"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Route Definition
+//___________________________________
+
+// Definition holds the programmatic route details, it's added into domain
+// after `routes.conf` routes are processed. It's used by reusable modules
+// (admin UI, metrics, health, etc.) to register their routes.
+type Definition struct {
+	Host  string
+	Route *Route
+}
+
+// RouteOption is used to customize the programmatic route definition.
+type RouteOption func(d *Definition)
+
+// WithName option sets the route name, used for reverse URL. Default route
+// name is derived from controller and action, for e.g.: `admin_users`.
+func WithName(name string) RouteOption {
+	return func(d *Definition) { d.Route.Name = name }
+}
+
+// WithHost option sets the domain host of the route as configured in
+// `routes.conf`, for e.g.: `admin.sample.com`. By default route is added
+// into root domain.
+func WithHost(host string) RouteOption {
+	return func(d *Definition) { d.Host = host }
+}
+
+// WithAuth option sets the route auth scheme name(s), for e.g.: `form_auth`.
+// By default domain `default_auth` value is used.
+func WithAuth(auth string) RouteOption {
+	return func(d *Definition) { d.Route.Auth = auth }
+}
+
+// WithMaxBodySize option sets the route request max body size in bytes.
+// By default `request.max_body_size` value is used.
+func WithMaxBodySize(size int64) RouteOption {
+	return func(d *Definition) { d.Route.MaxBodySize = size }
+}
+
+// WithStreamBody option enables the request body streaming mode, body is not
+// parsed or buffered by the framework, handler reads it via `ctx.Req.Body()`.
+func WithStreamBody() RouteOption {
+	return func(d *Definition) { d.Route.StreamBody = true }
+}
+
+// WithTimeout option sets the route request timeout.
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(d *Definition) { d.Route.Timeout = timeout }
+}
+
+// WithJSONCache option sets the route JSON reply cache TTL, serialized JSON
+// of identical reply data is reused within the TTL.
+func WithJSONCache(ttl time.Duration) RouteOption {
+	return func(d *Definition) { d.Route.JSONCacheTTL = ttl }
+}
+
+// WithHeaderRules option sets the route response header rules, see
+// `HeaderRules`.
+func WithHeaderRules(hr *HeaderRules) RouteOption {
+	return func(d *Definition) { d.Route.Headers = hr }
+}
+
+// WithJSONView option sets the route JSON reply view, struct fields tagged
+// with other views are not serialized. See `aah.Reply.WithJSONView`.
+func WithJSONView(view string) RouteOption {
+	return func(d *Definition) { d.Route.JSONView = view }
+}
+
+// WithAntiCSRFPolicy option sets the route Anti-CSRF policy. By default
+// domain policy is used.
+func WithAntiCSRFPolicy(policy string) RouteOption {
+	return func(d *Definition) {
+		d.Route.AntiCSRFPolicy = policy
+		d.Route.IsAntiCSRFCheck = policy != anticsrf.PolicyExempt
+	}
+}
+
+// NewDefinition method creates the route definition for given HTTP method,
+// path and controller action. Action is either method value/expression of
+// the controller, for e.g.: `(*AdminController).Users` or string
+// `Controller.Action`, for e.g.: `admin/AdminController.Users`.
+func NewDefinition(method, routePath string, action interface{}, opts ...RouteOption) (*Definition, error) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if ess.IsStrEmpty(method) {
+		return nil, fmt.Errorf("router: route '%s' method value is empty", routePath)
+	}
+	if !strings.HasPrefix(routePath, SlashString) {
+		return nil, fmt.Errorf("router: route path '%s' must begin with '/'", routePath)
+	}
+
+	target, actionName, err := targetAndAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Definition{Route: &Route{
+		Path:   path.Clean(routePath),
+		Method: method,
+		Target: target,
+		Action: actionName,
+	}}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if len(d.Route.Name) == 0 {
+		d.Route.Name = strings.ToLower(strings.TrimSuffix(path.Base(target), "Controller") + "_" + actionName)
+	}
+	return d, nil
+}
+
+// AddDefinition method adds the given route definition into the domain,
+// it applies the domain default values such as auth, Anti-CSRF policy, CORS
+// and request max body size if not set.
+func (r *Router) AddDefinition(d *Definition) error {
+	domain := r.RootDomain()
+	if len(d.Host) > 0 {
+		domain = nil
+		for _, dm := range r.Domains {
+			if strings.EqualFold(dm.Host, d.Host) || dm.Key == strings.ToLower(d.Host) {
+				domain = dm
+				break
+			}
+		}
+	}
+	if domain == nil {
+		return fmt.Errorf("router: route '%s' domain not found for host '%s'", d.Route.Name, d.Host)
+	}
+	if domain.LookupByName(d.Route.Name) != nil {
+		return fmt.Errorf("router: route name '%s' already exists in domain '%s'", d.Route.Name, domain.Name)
+	}
+
+	route := *d.Route
+	if len(route.Auth) == 0 {
+		route.Auth = domain.DefaultAuth
+	}
+	if route.MaxBodySize == 0 && route.Method != methodWebSocket {
+		route.MaxBodySize, _ = ess.StrToBytes(r.appConfig().StringDefault("request.max_body_size", "5mb"))
+	}
+	if len(route.AntiCSRFPolicy) == 0 {
+		route.IsAntiCSRFCheck = domain.AntiCSRFEnabled
+		route.AntiCSRFPolicy = anticsrf.PolicyExempt
+		if domain.AntiCSRFEnabled {
+			route.AntiCSRFPolicy = domain.AntiCSRFPolicy
+		}
+	}
+	if domain.CORSEnabled && route.CORS == nil {
+		route.CORS = domain.CORS
+	}
+	if route.Method == ahttp.MethodGet {
+		route.AutoHead = domain.AutoHead
+	}
+	route.AutoOptions = domain.AutoOptions
+	if route.authorizationInfo == nil {
+		route.authorizationInfo = &authorizationInfo{Satisfy: "either"}
+	}
+
+	routes := []*Route{&route}
+	if len(domain.Locales) > 0 {
+		routes = localizeRoutes(routes, domain.Locales)
+	}
+	for _, rt := range routes {
+		if err := domain.AddRoute(rt); err != nil {
+			return fmt.Errorf("router: route '%s': %s", rt.Name, err)
+		}
+	}
+	return nil
+}
+
+// targetAndAction method returns the controller name (with namespace) and
+// action name for given action value.
+func targetAndAction(action interface{}) (string, string, error) {
+	var name string
+	switch v := action.(type) {
+	case string:
+		name = v
+	default:
+		fv := reflect.ValueOf(action)
+		if fv.Kind() != reflect.Func {
+			return "", "", fmt.Errorf("router: invalid route action type '%T'", action)
+		}
+		fn := runtime.FuncForPC(fv.Pointer())
+		if fn == nil {
+			return "", "", fmt.Errorf("router: unable to resolve route action '%T'", action)
+		}
+		// for e.g.: example.com/app/controllers/admin.(*AdminController).Users-fm
+		name = strings.TrimSuffix(fn.Name(), "-fm")
+		slash := strings.LastIndex(name, SlashString) + 1
+		pkgEnd := strings.Index(name[slash:], ".")
+		if pkgEnd == -1 {
+			return "", "", fmt.Errorf("router: unable to resolve route action '%s'", name)
+		}
+		pkgPath, rest := name[:slash+pkgEnd], name[slash+pkgEnd+1:]
+		if idx := strings.Index(pkgPath, "controllers"); idx > -1 {
+			pkgPath = strings.TrimPrefix(pkgPath[idx+11:], SlashString)
+		} else {
+			pkgPath = ""
+		}
+		name = path.Join(pkgPath, strings.Replace(strings.Trim(rest, "(*"), ")", "", 1))
+	}
+
+	idx := strings.LastIndex(name, ".")
+	if idx <= 0 || idx == len(name)-1 || strings.ContainsAny(name[:idx], "()*") {
+		return "", "", fmt.Errorf("router: route action '%s' is not a controller action", name)
+	}
+	return name[:idx], name[idx+1:], nil
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strings"
 
 	"aahframe.work/ahttp"
@@ -28,6 +29,7 @@ type Domain struct {
 	MethodNotAllowed      bool
 	RedirectTrailingSlash bool
 	AutoOptions           bool
+	AutoHead              bool
 	AntiCSRFEnabled       bool
 	CORSEnabled           bool
 	Key                   string
@@ -35,6 +37,9 @@ type Domain struct {
 	Host                  string
 	Port                  string
 	DefaultAuth           string
+	AntiCSRFPolicy        string
+	DefaultLocale         string
+	Locales               []string
 	CORS                  *CORS
 	CatchAllRoute         *Route
 	trees                 map[string]*tree
@@ -61,12 +66,30 @@ func (d *Domain) Lookup(req *http.Request) (*Route, ahttp.URLParams, bool) {
 				tree, found = d.trees[h[0]]
 			}
 		}
-		if !found {
+		if !found && req.Method != ahttp.MethodHead {
 			return nil, nil, false
 		}
 	}
 
-	route, urlParams, rts := tree.lookup(req.URL.EscapedPath())
+	var route *Route
+	var urlParams ahttp.URLParams
+	var rts bool
+	if found {
+		route, urlParams, rts = tree.lookup(req.URL.EscapedPath())
+	}
+
+	// Auto HEAD, derived from GET route
+	if route == nil && req.Method == ahttp.MethodHead {
+		if gt, ok := d.trees[ahttp.MethodGet]; ok {
+			gr, gp, grts := gt.lookup(req.URL.EscapedPath())
+			if gr != nil && gr.AutoHead {
+				return gr, gp, false
+			}
+			if !found {
+				rts = grts
+			}
+		}
+	}
 
 	// Catch All
 	if route == nil && !rts && d.CatchAllRoute != nil {
@@ -84,12 +107,27 @@ func (d *Domain) LookupByName(name string) *Route {
 	return nil
 }
 
+// Routes method returns the domain routes sorted by route name.
+func (d *Domain) Routes() []*Route {
+	routes := make([]*Route, 0, len(d.routes))
+	for _, r := range d.routes {
+		routes = append(routes, r)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Name < routes[j].Name })
+	return routes
+}
+
 // AddRoute method adds the given route into domain routing tree.
 func (d *Domain) AddRoute(route *Route) error {
 	if ess.IsStrEmpty(route.Method) {
 		return errors.New("router: method value is empty")
 	}
 
+	// Auto HEAD is derived from GET route only
+	if route.Method != ahttp.MethodGet {
+		route.AutoHead = false
+	}
+
 	t := d.trees[route.Method]
 	if t == nil {
 		t = &tree{root: new(node), tralingSlash: d.RedirectTrailingSlash}
@@ -99,35 +137,24 @@ func (d *Domain) AddRoute(route *Route) error {
 	if err := t.add(route.Path, route); err != nil {
 		return err
 	}
+	t.root.inferwnode()
 
 	d.routes[route.Name] = route
 	return nil
 }
 
 // Allowed method returns the value for header `Allow` otherwise empty string.
-func (d *Domain) Allowed(requestMethod, path string) (allowed string) {
-	if path == "*" { // server-wide
-		for method := range d.trees {
-			if method != ahttp.MethodOptions {
-				// add request method to list of allowed methods
-				allowed = suffixCommaValue(allowed, method)
-			}
-		}
-		return
-	}
-
-	// specific path
-	for method := range d.trees {
-		// Skip the requested method - we already tried this one
-		if method != requestMethod && method != ahttp.MethodOptions {
-			if value, _, _ := d.trees[method].lookup(path); value != nil {
-				// add request method to list of allowed methods
-				allowed = suffixCommaValue(allowed, method)
-			}
-		}
-	}
+// HTTP methods are sorted to have consistent header value. HTTP method `HEAD`
+// is included for the GET routes which has auto HEAD enabled.
+func (d *Domain) Allowed(requestMethod, path string) string {
+	return d.allowed(requestMethod, path, false)
+}
 
-	return
+// AutoOptionsAllowed method returns the value for header `Allow` for the
+// auto `OPTIONS` response, only the routes which has auto OPTIONS enabled
+// are considered otherwise empty string.
+func (d *Domain) AutoOptionsAllowed(path string) string {
+	return d.allowed(ahttp.MethodOptions, path, true)
 }
 
 // RouteURLNamedArgs composes reverse URL by route name and key-value pair arguments.
@@ -162,7 +189,7 @@ func (d *Domain) RouteURLNamedArgs(routeName string, args map[string]interface{}
 		if segment[0] == paramByte || segment[0] == wildByte {
 			argName := segment[1:]
 			if arg, found := args[argName]; found {
-				reverseURL = path.Join(reverseURL, url.PathEscape(fmt.Sprintf("%v", arg)))
+				reverseURL = path.Join(reverseURL, escapeRouteArg(segment[0], arg))
 				delete(args, argName)
 				continue
 			}
@@ -218,11 +245,6 @@ func (d *Domain) RouteURL(routeName string, args ...interface{}) string {
 		return ""
 	}
 
-	var values []string
-	for _, v := range args {
-		values = append(values, url.PathEscape(fmt.Sprintf("%v", v)))
-	}
-
 	// compose URL with values
 	reverseURL := "/"
 	idx := 0
@@ -232,7 +254,7 @@ func (d *Domain) RouteURL(routeName string, args ...interface{}) string {
 		}
 
 		if segment[0] == paramByte || segment[0] == wildByte {
-			reverseURL = path.Join(reverseURL, values[idx])
+			reverseURL = path.Join(reverseURL, escapeRouteArg(segment[0], args[idx]))
 			idx++
 			continue
 		}
@@ -255,6 +277,45 @@ func (d *Domain) inferKey() {
 	}
 }
 
+// allowed method returns the allowed HTTP methods for given path, if
+// `autoOptions` is true then routes which has auto OPTIONS disabled are
+// skipped.
+func (d *Domain) allowed(requestMethod, path string, autoOptions bool) string {
+	found := make(map[string]bool)
+	for method, t := range d.trees {
+		if method == ahttp.MethodOptions {
+			continue
+		}
+		if path == "*" { // server-wide
+			found[method] = true
+			if method == ahttp.MethodGet && d.AutoHead {
+				found[ahttp.MethodHead] = true
+			}
+			continue
+		}
+
+		// Skip the requested method - we already tried this one
+		if method == requestMethod {
+			continue
+		}
+		route, _, _ := t.lookup(path)
+		if route == nil || (autoOptions && !route.AutoOptions) {
+			continue
+		}
+		found[method] = true
+		if method == ahttp.MethodGet && route.AutoHead && requestMethod != ahttp.MethodHead {
+			found[ahttp.MethodHead] = true
+		}
+	}
+
+	methods := make([]string, 0, len(found))
+	for method := range found {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
 func (d *Domain) isAuthConfigured(secMgr *security.Manager) ([]string, bool) {
 	if !ess.IsStrEmpty(d.DefaultAuth) && secMgr.AuthScheme(d.DefaultAuth) != nil {
 		return []string{}, true
@@ -268,10 +329,36 @@ func (d *Domain) isAuthConfigured(secMgr *security.Manager) ([]string, bool) {
 
 		if r.Auth == "" {
 			names = append(names, r.Name)
-		} else if secMgr.AuthScheme(r.Auth) == nil {
+			continue
+		}
+
+		authNames := r.AuthSchemeNames()
+		if len(authNames) == 0 {
 			names = append(names, r.Name)
+			continue
+		}
+
+		for _, name := range authNames {
+			if secMgr.AuthScheme(name) == nil {
+				names = append(names, r.Name)
+				break
+			}
 		}
 	}
 
 	return names, len(names) == 0
 }
+
+// escapeRouteArg method escapes the reverse URL argument value. Catch-all
+// value can have multiple path segments e.g. 'css/app.css', so each segment
+// is escaped.
+func escapeRouteArg(kind byte, arg interface{}) string {
+	if kind != wildByte {
+		return url.PathEscape(fmt.Sprintf("%v", arg))
+	}
+	parts := strings.Split(fmt.Sprintf("%v", arg), "/")
+	for i := range parts {
+		parts[i] = url.PathEscape(parts[i])
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"aahframe.work/config"
+)
+
+// HeaderRules holds the response header rules from config
+// `headers { ... }`, applied in the order `remove`, `override` and `add`.
+// Header names are canonicalized, underscore is treated as hyphen
+// e.g. `x_frame_options` => `X-Frame-Options`.
+//
+//	headers {
+//	  # set only if header is not set by the application
+//	  add {
+//	    x_service_name = "orders"
+//	  }
+//	  # always set, replaces the existing value
+//	  override {
+//	    x_frame_options = "DENY"
+//	  }
+//	  remove = ["x_powered_by"]
+//	}
+//
+// Header rules configured on the parent route are inherited by its child
+// routes, child route rule of the same header takes precedence.
+type HeaderRules struct {
+	Add      map[string]string
+	Override map[string]string
+	Remove   []string
+}
+
+// NewHeaderRules method parses the header rules from given config key and
+// merges it with parent rules. Returns parent rules if config key does not
+// exists.
+func NewHeaderRules(cfg *config.Config, keyPrefix string, parent *HeaderRules) (*HeaderRules, error) {
+	if !cfg.IsExists(keyPrefix) {
+		return parent, nil
+	}
+
+	hr := &HeaderRules{Add: make(map[string]string), Override: make(map[string]string)}
+	if parent != nil {
+		for k, v := range parent.Add {
+			hr.Add[k] = v
+		}
+		for k, v := range parent.Override {
+			hr.Override[k] = v
+		}
+		hr.Remove = append(hr.Remove, parent.Remove...)
+	}
+
+	for _, rule := range []struct {
+		name  string
+		value map[string]string
+	}{{"add", hr.Add}, {"override", hr.Override}} {
+		for _, k := range cfg.KeysByPath(keyPrefix + "." + rule.name) {
+			value, _ := cfg.Get(keyPrefix + "." + rule.name + "." + k)
+			v, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("'%s.%s.%s' value must be string", keyPrefix, rule.name, k)
+			}
+			name := canonicalHeaderName(k)
+			hr.unset(name)
+			rule.value[name] = v
+		}
+	}
+
+	names, _ := cfg.StringList(keyPrefix + ".remove")
+	for _, n := range names {
+		if n = strings.TrimSpace(n); len(n) == 0 {
+			return nil, fmt.Errorf("'%s.remove' value must not contain empty header name", keyPrefix)
+		}
+		name := canonicalHeaderName(n)
+		hr.unset(name)
+		hr.Remove = append(hr.Remove, name)
+	}
+	sort.Strings(hr.Remove)
+
+	return hr, nil
+}
+
+// Apply method applies the header rules on given response header.
+func (hr *HeaderRules) Apply(hdr http.Header) {
+	for _, name := range hr.Remove {
+		hdr.Del(name)
+	}
+	for name, value := range hr.Override {
+		hdr.Set(name, value)
+	}
+	for name, value := range hr.Add {
+		if _, found := hdr[name]; !found {
+			hdr.Set(name, value)
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// unset method removes the existing rule of given header, so that later
+// rule of the header takes precedence.
+func (hr *HeaderRules) unset(name string) {
+	delete(hr.Add, name)
+	delete(hr.Override, name)
+	for i, n := range hr.Remove {
+		if n == name {
+			hr.Remove = append(hr.Remove[:i], hr.Remove[i+1:]...)
+			break
+		}
+	}
+}
+
+func canonicalHeaderName(name string) string {
+	return http.CanonicalHeaderKey(strings.Replace(strings.TrimSpace(name), "_", "-", -1))
+}
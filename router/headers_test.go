@@ -0,0 +1,90 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"net/http"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterHeaderRules(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	parent {
+	  headers {
+	    add {
+	      x_service_name = "orders"
+	      cache_control = "no-cache"
+	    }
+	    override {
+	      x_frame_options = "DENY"
+	    }
+	    remove = ["x_powered_by", "Server"]
+	  }
+	}
+	child {
+	  headers {
+	    add {
+	      x_frame_options = "SAMEORIGIN"
+	    }
+	    override {
+	      x_service_name = "orders-v2"
+	    }
+	    remove = ["cache_control"]
+	  }
+	}
+	invalid {
+	  headers {
+	    remove = [" "]
+	  }
+	}
+	invalid_value {
+	  headers {
+	    add {
+	      x_retry = 10
+	    }
+	  }
+	}
+	`)
+
+	parent, err := NewHeaderRules(cfg, "parent.headers", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"X-Service-Name": "orders", "Cache-Control": "no-cache"}, parent.Add)
+	assert.Equal(t, map[string]string{"X-Frame-Options": "DENY"}, parent.Override)
+	assert.Equal(t, []string{"Server", "X-Powered-By"}, parent.Remove)
+
+	hdr := http.Header{}
+	hdr.Set("Cache-Control", "max-age=60")
+	hdr.Set("X-Frame-Options", "ALLOW")
+	hdr.Set("X-Powered-By", "aah")
+	hdr.Set("Server", "aah")
+	parent.Apply(hdr)
+	assert.Equal(t, http.Header{
+		"Cache-Control":   {"max-age=60"},
+		"X-Frame-Options": {"DENY"},
+		"X-Service-Name":  {"orders"},
+	}, hdr)
+
+	// inherited rules, child rule of the same header takes precedence
+	child, err := NewHeaderRules(cfg, "child.headers", parent)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"X-Frame-Options": "SAMEORIGIN"}, child.Add)
+	assert.Equal(t, map[string]string{"X-Service-Name": "orders-v2"}, child.Override)
+	assert.Equal(t, []string{"Cache-Control", "Server", "X-Powered-By"}, child.Remove)
+	assert.Equal(t, "orders", parent.Add["X-Service-Name"])
+
+	// not configured
+	hr, err := NewHeaderRules(cfg, "notexists.headers", parent)
+	assert.Nil(t, err)
+	assert.True(t, hr == parent)
+
+	_, err = NewHeaderRules(cfg, "invalid.headers", nil)
+	assert.Equal(t, "'invalid.headers.remove' value must not contain empty header name", err.Error())
+
+	_, err = NewHeaderRules(cfg, "invalid_value.headers", nil)
+	assert.Equal(t, "'invalid_value.headers.add.x_retry' value must be string", err.Error())
+}
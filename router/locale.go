@@ -0,0 +1,101 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+// localeRouteNameSep is used to compose the localized route name,
+// for e.g.: `products@de`.
+const localeRouteNameSep = "@"
+
+// LocalePath method returns the request path for given locale. The route
+// locale prefix is replaced with given locale, for e.g.: `/de/products` =>
+// `/en/products`. Empty locale returns the unprefixed path.
+func (r *Route) LocalePath(locale, reqPath string) string {
+	if prefix := "/" + r.Locale; len(r.Locale) > 0 && len(reqPath) >= len(prefix) &&
+		strings.EqualFold(reqPath[:len(prefix)], prefix) {
+		reqPath = reqPath[len(prefix):]
+	}
+	if len(locale) == 0 {
+		if len(reqPath) == 0 {
+			return SlashString
+		}
+		return reqPath
+	}
+	if reqPath == SlashString {
+		reqPath = ""
+	}
+	return "/" + locale + reqPath
+}
+
+// IsLocalized method returns true if route is part of locale-prefixed routes
+// otherwise false.
+func (r *Route) IsLocalized() bool {
+	return len(r.Locales) > 0
+}
+
+// parseLocalePaths method parses the domain locale-prefixed routes config.
+// Domain routes are duplicated for each configured locale with path prefix,
+// for e.g.: `/products` becomes `/en/products`, `/de/products`. Unprefixed
+// route is retained as is.
+//
+//	i18n_path {
+//	  enable = true
+//	  locales = ["en", "de"]
+//	  default = "en"
+//	}
+//
+// Route locale is available via `Route.Locale` and the reverse route URL
+// via route name `<route-name>@<locale>`.
+func parseLocalePaths(cfg *config.Config, domainKey string) ([]string, string, error) {
+	if !cfg.BoolDefault("i18n_path.enable", false) {
+		return nil, "", nil
+	}
+
+	var locales []string
+	values, _ := cfg.StringList("i18n_path.locales")
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); len(v) > 0 && !ess.IsSliceContainsString(locales, v) {
+			locales = append(locales, v)
+		}
+	}
+	if len(locales) == 0 {
+		return nil, "", fmt.Errorf("'%s.i18n_path.locales' value is required", domainKey)
+	}
+
+	defaultLocale := strings.ToLower(strings.TrimSpace(cfg.StringDefault("i18n_path.default", locales[0])))
+	if !ess.IsSliceContainsString(locales, defaultLocale) {
+		return nil, "", fmt.Errorf("'%s.i18n_path.default' value '%s' is not in locales", domainKey, defaultLocale)
+	}
+
+	return locales, defaultLocale, nil
+}
+
+// localizeRoutes method returns the given routes along with locale-prefixed
+// duplicate for each locale. WebSocket routes are not localized.
+func localizeRoutes(routes []*Route, locales []string) []*Route {
+	result := make([]*Route, 0, len(routes)*(len(locales)+1))
+	for _, r := range routes {
+		result = append(result, r)
+		if r.Method == methodWebSocket {
+			continue
+		}
+		r.Locales = locales
+		for _, l := range locales {
+			lr := *r
+			lr.Name = r.Name + localeRouteNameSep + l
+			lr.Path = r.LocalePath(l, r.Path)
+			lr.Locale = l
+			result = append(result, &lr)
+		}
+	}
+	return result
+}
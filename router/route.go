@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
 	"aahframe.work/config"
 	"aahframe.work/security"
@@ -23,7 +24,16 @@ type Route struct {
 	IsAntiCSRFCheck bool
 	IsStatic        bool
 	ListDir         bool
+	AutoHead        bool
+	AutoOptions     bool
+	StreamBody      bool
 	MaxBodySize     int64
+	Timeout         time.Duration
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	JSONCacheTTL    time.Duration
+	AntiCSRFPolicy  string
+	JSONView        string
 	Name            string
 	Path            string
 	Method          string
@@ -33,12 +43,41 @@ type Route struct {
 	Auth            string
 	Dir             string
 	File            string
+	Locale          string
+	Locales         []string
 	CORS            *CORS
+	Bulkhead        *Bulkhead
+	Headers         *HeaderRules
 	Constraints     map[string]string
 
 	authorizationInfo *authorizationInfo
 }
 
+// AuthSchemeNames method returns the route auth scheme names in the configured
+// order. Route can have chain of auth schemes e.g. `auth = "jwt, apikey, form_auth"`.
+func (r *Route) AuthSchemeNames() []string {
+	auth := r.Auth
+	if r.IsAuthOptional() {
+		auth = auth[len(authOptionalPrefix) : len(auth)-1]
+	}
+
+	var names []string
+	for _, name := range strings.Split(auth, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// IsAuthOptional method returns true if route auth is optional
+// e.g. `auth = "optional(form_auth)"` otherwise false. On optional auth,
+// subject is populated when request has credentials, still route serves
+// the anonymous users.
+func (r *Route) IsAuthOptional() bool {
+	return strings.HasPrefix(r.Auth, authOptionalPrefix) && strings.HasSuffix(r.Auth, ")")
+}
+
 // IsDir method returns true if serving directory otherwise false.
 func (r *Route) IsDir() bool {
 	return len(r.Dir) > 0 && len(r.File) == 0
@@ -140,14 +179,27 @@ func (r *Route) String() string {
 
 type parentRouteInfo struct {
 	AntiCSRFCheck     bool
+	AntiCSRFPolicy    string
 	CORSEnabled       bool
+	AutoHead          bool
+	AutoOptions       bool
+	StreamBody        bool
 	ParentName        string
 	PrefixPath        string
+	NamePrefix        string
+	ConfigDir         string
 	Target            string
 	Auth              string
+	JSONView          string
 	MaxBodySizeStr    string
 	CORS              *CORS
 	AuthorizationInfo *authorizationInfo
+	Bulkhead          *Bulkhead
+	Headers           *HeaderRules
+	Timeout           time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	JSONCacheTTL      time.Duration
 }
 
 type authorizationInfo struct {
@@ -187,8 +239,12 @@ func (a *authorizationInfo) String() string {
 }
 
 func parseAuthorizationInfo(cfg *config.Config, routeName string, parentRoute *parentRouteInfo) (*authorizationInfo, error) {
+	parentInfo := parentRoute.AuthorizationInfo
+	if parentInfo == nil {
+		parentInfo = &authorizationInfo{}
+	}
 	info := &authorizationInfo{
-		Satisfy: cfg.StringDefault(routeName+".authorization.satisfy", parentRoute.AuthorizationInfo.Satisfy),
+		Satisfy: cfg.StringDefault(routeName+".authorization.satisfy", parentInfo.Satisfy),
 	}
 
 	roles, found := cfg.StringList(routeName + ".authorization.roles")
@@ -203,7 +259,7 @@ func parseAuthorizationInfo(cfg *config.Config, routeName string, parentRoute *p
 		}
 		info.Roles = roles
 	} else {
-		info.Roles = parentRoute.AuthorizationInfo.Roles
+		info.Roles = parentInfo.Roles
 	}
 
 	permissions, found := cfg.StringList(routeName + ".authorization.permissions")
@@ -218,7 +274,7 @@ func parseAuthorizationInfo(cfg *config.Config, routeName string, parentRoute *p
 		}
 		info.Permissions = permissions
 	} else {
-		info.Permissions = parentRoute.AuthorizationInfo.Permissions
+		info.Permissions = parentInfo.Permissions
 	}
 
 	// Check statisfy
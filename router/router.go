@@ -20,8 +20,10 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/log"
 	"aahframe.work/security"
+	"aahframe.work/security/anticsrf"
 	"aahframe.work/security/scheme"
 )
 
@@ -29,6 +31,7 @@ const (
 	wildcardSubdomainPrefix = "*."
 	methodWebSocket         = "WS"
 	autoRouteNameSuffix     = "__aah"
+	authOptionalPrefix      = "optional("
 )
 
 var (
@@ -223,6 +226,20 @@ func (r *Router) CreateRouteURL(host, routeName string, margs map[string]interfa
 	return r.composeRouteURL(domain, host, domain.RouteURLNamedArgs(routeName, margs), anchor)
 }
 
+// RouteByName method returns the route for given host and route name, it
+// supports the same route name notation as reverse URL e.g. `sub.routename`.
+// Returns nil if not found.
+func (r *Router) RouteByName(host, routeName string) *Route {
+	domain, routeName := r.lookupRouteURLDomain(host, routeName)
+	if domain == nil {
+		return nil
+	}
+	if i := strings.IndexByte(routeName, '#'); i > 0 {
+		routeName = routeName[:i]
+	}
+	return domain.LookupByName(routeName)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Router unexpoted methods
 //______________________________________________________________________________
@@ -329,6 +346,7 @@ func (r *Router) processRoutesConfig() (err error) {
 			MethodNotAllowed:      domainCfg.BoolDefault("method_not_allowed", true),
 			RedirectTrailingSlash: domainCfg.BoolDefault("redirect_trailing_slash", true),
 			AutoOptions:           domainCfg.BoolDefault("auto_options", true),
+			AutoHead:              domainCfg.BoolDefault("auto_head", true),
 			DefaultAuth:           domainCfg.StringDefault("default_auth", ""),
 			AntiCSRFEnabled:       domainCfg.BoolDefault("anti_csrf_check", true),
 			CORSEnabled:           domainCfg.BoolDefault("cors.enable", false),
@@ -336,10 +354,24 @@ func (r *Router) processRoutesConfig() (err error) {
 			routes:                make(map[string]*Route),
 		}
 
+		// Domain level Anti-CSRF policy
+		if domain.AntiCSRFPolicy, err = parseAntiCSRFPolicy(domainCfg, "anti_csrf_policy",
+			domain.AntiCSRFEnabled, anticsrf.PolicyToken); err != nil {
+			return
+		}
+		domain.AntiCSRFEnabled = domain.AntiCSRFPolicy != anticsrf.PolicyExempt
+
+		// Domain level locale-prefixed routes
+		if domain.Locales, domain.DefaultLocale, err = parseLocalePaths(domainCfg, key); err != nil {
+			return
+		}
+
 		// Domain Level CORS configuration
 		if domain.CORSEnabled {
 			baseCORSCfg, _ := domainCfg.GetSubConfig("cors")
-			domain.CORS = processBaseCORSSection(baseCORSCfg)
+			if domain.CORS, err = processBaseCORSSection(baseCORSCfg); err != nil {
+				return
+			}
 		}
 
 		// Catch All route
@@ -408,9 +440,6 @@ func (r *Router) processRoutesConfig() (err error) {
 		}
 
 		r.Domains[idx] = domain
-		for _, t := range domain.trees {
-			t.root.inferwnode()
-		}
 	} // End of domains
 
 	// find out root domain
@@ -439,6 +468,8 @@ func (r *Router) processStaticRoutes(domain *Domain, domainCfg *config.Config) e
 	}
 
 	for idx := range routes {
+		routes[idx].AutoHead = domain.AutoHead
+		routes[idx].AutoOptions = domain.AutoOptions
 		if err = domain.AddRoute(routes[idx]); err != nil {
 			return err
 		}
@@ -455,17 +486,25 @@ func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config) error {
 
 	maxBodySizeStr := r.appConfig().StringDefault("request.max_body_size", "5mb")
 	routes, err := parseSectionRoutes(routesCfg, &parentRouteInfo{
+		ConfigDir:         path.Dir(r.configPath),
 		Auth:              domain.DefaultAuth,
 		MaxBodySizeStr:    maxBodySizeStr,
 		CORS:              domain.CORS,
 		AntiCSRFCheck:     domain.AntiCSRFEnabled,
+		AntiCSRFPolicy:    domain.AntiCSRFPolicy,
 		CORSEnabled:       domain.CORSEnabled,
+		AutoHead:          domain.AutoHead,
+		AutoOptions:       domain.AutoOptions,
 		AuthorizationInfo: &authorizationInfo{Satisfy: "either"},
 	})
 	if err != nil {
 		return err
 	}
 
+	if len(domain.Locales) > 0 {
+		routes = localizeRoutes(routes, domain.Locales)
+	}
+
 	for idx := range routes {
 		if err = domain.AddRoute(routes[idx]); err != nil {
 			return err
@@ -491,23 +530,53 @@ func (r *Router) processRoutes(domain *Domain, domainCfg *config.Config) error {
 				name := kn + "_login_submit" + autoRouteNameSuffix // for e.g.: form_auth_login_submit__aah
 				if domain.LookupByName(name) == nil {              // add only if not exists
 					_ = domain.AddRoute(&Route{Name: name, Path: sv.LoginSubmitURL,
-						Method: ahttp.MethodPost, Auth: kn, MaxBodySize: maxBodySize})
+						Method: ahttp.MethodPost, Auth: kn, MaxBodySize: maxBodySize, AutoOptions: domain.AutoOptions})
 				}
 			case *scheme.OAuth2:
 				_ = domain.AddRoute(&Route{
-					Name:   kn + "_login" + autoRouteNameSuffix,
-					Path:   sv.LoginURL,
-					Method: ahttp.MethodGet,
-					Auth:   kn,
+					Name:        kn + "_login" + autoRouteNameSuffix,
+					Path:        sv.LoginURL,
+					Method:      ahttp.MethodGet,
+					Auth:        kn,
+					AutoOptions: domain.AutoOptions,
 				})
 				_ = domain.AddRoute(&Route{
-					Name:   kn + "_redirect" + autoRouteNameSuffix,
-					Path:   sv.RedirectURL,
-					Method: ahttp.MethodGet,
-					Auth:   kn,
+					Name:        kn + "_redirect" + autoRouteNameSuffix,
+					Path:        sv.RedirectURL,
+					Method:      ahttp.MethodGet,
+					Auth:        kn,
+					AutoOptions: domain.AutoOptions,
 				})
 			}
 		}
+
+		// Add password reset and email verification routes for configured controller
+		if verifier := r.app.SecurityManager().Verifier; verifier != nil {
+			maxBodySize, _ := ess.StrToBytes(maxBodySizeStr)
+			for _, f := range verifier.Flows() {
+				for _, ri := range f.Routes {
+					name := ri.Name + autoRouteNameSuffix // for e.g.: password_reset_request__aah
+					if domain.LookupByName(name) != nil { // add only if not exists
+						continue
+					}
+					if err = domain.AddRoute(&Route{
+						Name:            name,
+						Path:            ri.Path,
+						Method:          ri.Method,
+						Target:          f.Controller,
+						Action:          ri.Action,
+						Auth:            "anonymous",
+						MaxBodySize:     maxBodySize,
+						IsAntiCSRFCheck: domain.AntiCSRFEnabled,
+						AntiCSRFPolicy:  domain.AntiCSRFPolicy,
+						AutoHead:        domain.AutoHead,
+						AutoOptions:     domain.AutoOptions,
+					}); err != nil {
+						return err
+					}
+				}
+			}
+		}
 	}
 
 	return nil
@@ -561,7 +630,7 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 		routeAction := cfg.StringDefault(routeName+".action", findActionByHTTPMethod(routeMethod))
 
 		notToSkip := true
-		if cfg.IsExists(routeName + ".routes") {
+		if cfg.IsExists(routeName+".routes") || cfg.IsExists(routeName+".includes") {
 			if ess.IsStrEmpty(routeTarget) || ess.IsStrEmpty(routeAction) {
 				notToSkip = false
 			}
@@ -580,16 +649,30 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 		routeAuth := strings.TrimSpace(cfg.StringDefault(routeName+".auth", routeInfo.Auth))
 
 		// getting route max body size, GitHub go-aah/aah#83
-		routeMaxBodySize, er := ess.StrToBytes(cfg.StringDefault(routeName+".max_body_size", routeInfo.MaxBodySizeStr))
+		routeMaxBodySizeStr := cfg.StringDefault(routeName+".max_body_size", routeInfo.MaxBodySizeStr)
+		routeMaxBodySize, er := ess.StrToBytes(routeMaxBodySizeStr)
 		if er != nil {
 			log.Warnf("'%v.max_body_size' value is not a valid size unit, fallback to global limit", routeName)
+			routeMaxBodySizeStr = routeInfo.MaxBodySizeStr
+			routeMaxBodySize, _ = ess.StrToBytes(routeMaxBodySizeStr)
 		}
+
+		// Request body streaming mode, body is not parsed by the framework
+		routeStreamBody := cfg.BoolDefault(routeName+".stream_body", routeInfo.StreamBody)
 		if !payloadSupported.MatchString(routeMethod) {
 			routeMaxBodySize = 0
+			routeStreamBody = false
 		}
 
 		// getting Anti-CSRF check value, GitHub go-aah/aah#115
 		routeAntiCSRFCheck := cfg.BoolDefault(routeName+".anti_csrf_check", routeInfo.AntiCSRFCheck)
+		routeAntiCSRFPolicy, er := parseAntiCSRFPolicy(cfg, routeName+".anti_csrf_policy",
+			routeAntiCSRFCheck, routeInfo.AntiCSRFPolicy)
+		if er != nil {
+			err = er
+			return
+		}
+		routeAntiCSRFCheck = routeAntiCSRFPolicy != anticsrf.PolicyExempt
 
 		// Authorization Info
 		routeAuthorizationInfo, er := parseAuthorizationInfo(cfg, routeName, routeInfo)
@@ -612,17 +695,64 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 			}
 		}
 
-		// 'anti_csrf_check', 'cors' and 'max_body_size' not applicable for WebSocket
+		// Bulkhead, per route or routes group concurrency limit
+		routeBulkhead, er := parseBulkhead(cfg, routeName, routeInfo.Bulkhead)
+		if er != nil {
+			err = er
+			return
+		}
+
+		// Request, read and write timeouts, per route or routes group
+		routeTimeout, routeReadTimeout, routeWriteTimeout, er := parseRouteTimeouts(cfg, routeName, routeInfo)
+		if er != nil {
+			err = er
+			return
+		}
+
+		// JSON reply cache, per route or routes group
+		routeJSONCacheTTL, er := settings.ParseDuration(cfg, routeName+".json_cache", routeInfo.JSONCacheTTL)
+		if er != nil {
+			err = er
+			return
+		}
+
+		// JSON reply view, per route or routes group
+		routeJSONView := cfg.StringDefault(routeName+".json_view", routeInfo.JSONView)
+
+		// Response header rules, per route or routes group
+		routeHeaders, er := NewHeaderRules(cfg, routeName+".headers", routeInfo.Headers)
+		if er != nil {
+			err = er
+			return
+		}
+
+		// Automatic HEAD (derived from GET route) and OPTIONS handling, per
+		// route or routes group
+		routeAutoHead := cfg.BoolDefault(routeName+".auto_head", routeInfo.AutoHead)
+		routeAutoOptions := cfg.BoolDefault(routeName+".auto_options", routeInfo.AutoOptions)
+
+		// 'anti_csrf_check', 'cors', 'max_body_size', 'stream_body',
+		// 'max_concurrent', 'timeout', 'json_cache', 'json_view' and 'headers'
+		// not applicable for WebSocket
 		if routeMethod == methodWebSocket {
 			routeAntiCSRFCheck = false
+			routeAntiCSRFPolicy = anticsrf.PolicyExempt
 			cors = nil
 			routeMaxBodySize = 0
+			routeStreamBody = false
+			routeBulkhead = nil
+			routeTimeout = 0
+			routeReadTimeout = 0
+			routeWriteTimeout = 0
+			routeJSONCacheTTL = 0
+			routeJSONView = ""
+			routeHeaders = nil
 		}
 
 		if notToSkip {
 			for _, m := range strings.Split(routeMethod, ",") {
 				routes = append(routes, &Route{
-					Name:              routeName,
+					Name:              routeInfo.NamePrefix + routeName,
 					Path:              actualRoutePath,
 					Method:            strings.TrimSpace(m),
 					Target:            routeTarget,
@@ -630,27 +760,53 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 					ParentName:        routeInfo.ParentName,
 					Auth:              routeAuth,
 					MaxBodySize:       routeMaxBodySize,
+					StreamBody:        routeStreamBody,
+					Timeout:           routeTimeout,
+					ReadTimeout:       routeReadTimeout,
+					WriteTimeout:      routeWriteTimeout,
+					JSONCacheTTL:      routeJSONCacheTTL,
+					JSONView:          routeJSONView,
+					AutoHead:          routeAutoHead,
+					AutoOptions:       routeAutoOptions,
 					IsAntiCSRFCheck:   routeAntiCSRFCheck,
+					AntiCSRFPolicy:    routeAntiCSRFPolicy,
 					CORS:              cors,
+					Bulkhead:          routeBulkhead,
+					Headers:           routeHeaders,
 					Constraints:       routeConstraints,
 					authorizationInfo: routeAuthorizationInfo,
 				})
 			}
 		}
 
+		childRouteInfo := &parentRouteInfo{
+			ParentName:        routeInfo.NamePrefix + routeName,
+			PrefixPath:        routePath,
+			NamePrefix:        routeInfo.NamePrefix,
+			ConfigDir:         routeInfo.ConfigDir,
+			Target:            routeTarget,
+			Auth:              routeAuth,
+			MaxBodySizeStr:    routeMaxBodySizeStr,
+			StreamBody:        cfg.BoolDefault(routeName+".stream_body", routeInfo.StreamBody),
+			AntiCSRFCheck:     routeAntiCSRFCheck,
+			AntiCSRFPolicy:    routeAntiCSRFPolicy,
+			CORS:              cors,
+			CORSEnabled:       routeInfo.CORSEnabled,
+			AutoHead:          routeAutoHead,
+			AutoOptions:       routeAutoOptions,
+			AuthorizationInfo: routeAuthorizationInfo,
+			Bulkhead:          routeBulkhead,
+			Headers:           routeHeaders,
+			Timeout:           routeTimeout,
+			ReadTimeout:       routeReadTimeout,
+			WriteTimeout:      routeWriteTimeout,
+			JSONCacheTTL:      routeJSONCacheTTL,
+			JSONView:          routeJSONView,
+		}
+
 		// loading child routes
 		if childRoutes, found := cfg.GetSubConfig(routeName + ".routes"); found {
-			croutes, er := parseSectionRoutes(childRoutes, &parentRouteInfo{
-				ParentName:        routeName,
-				PrefixPath:        routePath,
-				Target:            routeTarget,
-				Auth:              routeAuth,
-				MaxBodySizeStr:    routeInfo.MaxBodySizeStr,
-				AntiCSRFCheck:     routeAntiCSRFCheck,
-				CORS:              cors,
-				CORSEnabled:       routeInfo.CORSEnabled,
-				AuthorizationInfo: routeAuthorizationInfo,
-			})
+			croutes, er := parseSectionRoutes(childRoutes, childRouteInfo)
 			if er != nil {
 				err = er
 				return
@@ -658,11 +814,77 @@ func parseSectionRoutes(cfg *config.Config, routeInfo *parentRouteInfo) (routes
 
 			routes = append(routes, croutes...)
 		}
+
+		// loading included route files, for e.g.: includes = ["routes/admin.conf"]
+		if cfg.IsExists(routeName + ".includes") {
+			iroutes, er := parseIncludedRoutes(cfg, routeName, childRouteInfo)
+			if er != nil {
+				err = er
+				return
+			}
+
+			routes = append(routes, iroutes...)
+		}
 	}
 
 	return
 }
 
+// parseIncludedRoutes method loads the route files of `<route>.includes` and
+// parses it as child routes of the given route. File path is relative to
+// the routes config directory. Route names of included files are prefixed
+// with `<route>.namespace` value followed by `.` if configured, for e.g.:
+// namespace `admin` and route `users` becomes `admin.users`.
+func parseIncludedRoutes(cfg *config.Config, routeName string, routeInfo *parentRouteInfo) ([]*Route, error) {
+	files, found := cfg.StringList(routeName + ".includes")
+	if !found {
+		files = []string{cfg.StringDefault(routeName+".includes", "")}
+	}
+
+	includeInfo := *routeInfo
+	if ns := strings.TrimSpace(cfg.StringDefault(routeName+".namespace", "")); len(ns) > 0 {
+		includeInfo.NamePrefix += ns + "."
+	}
+
+	var routes []*Route
+	for _, f := range files {
+		if f = strings.TrimSpace(f); len(f) == 0 {
+			return nil, fmt.Errorf("'%v.includes' value is empty", routeName)
+		}
+		if !path.IsAbs(f) {
+			f = path.Join(routeInfo.ConfigDir, f)
+		}
+		includeCfg, err := config.LoadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("'%v.includes' %s: %s", routeName, f, err)
+		}
+		iroutes, err := parseSectionRoutes(includeCfg, &includeInfo)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", f, err)
+		}
+		routes = append(routes, iroutes...)
+	}
+	return routes, nil
+}
+
+// parseAntiCSRFPolicy method returns the Anti-CSRF policy for given key. If
+// not configured, it's derived from 'anti_csrf_check' value and parent policy.
+func parseAntiCSRFPolicy(cfg *config.Config, key string, check bool, parentPolicy string) (string, error) {
+	defaultPolicy := anticsrf.PolicyExempt
+	if check {
+		defaultPolicy = parentPolicy
+		if len(defaultPolicy) == 0 || defaultPolicy == anticsrf.PolicyExempt {
+			defaultPolicy = anticsrf.PolicyToken
+		}
+	}
+
+	policy := strings.ToLower(strings.TrimSpace(cfg.StringDefault(key, defaultPolicy)))
+	if !anticsrf.IsValidPolicy(policy) {
+		return "", fmt.Errorf("'%v' has unsupported value '%v', supported values are token, origin and exempt", key, policy)
+	}
+	return policy, nil
+}
+
 func parseStaticSection(cfg *config.Config) (routes []*Route, err error) {
 	for _, routeName := range cfg.Keys() {
 		route := &Route{Name: routeName, Method: ahttp.MethodGet, IsStatic: true}
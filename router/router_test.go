@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
@@ -73,6 +74,12 @@ func TestRouterLoadConfiguration(t *testing.T) {
 	routeNotFound := domain.LookupByName("cancel_booking_not_found")
 	assert.Nil(t, routeNotFound)
 
+	routes := domain.Routes()
+	assert.True(t, len(routes) > 0)
+	for i := 1; i < len(routes); i++ {
+		assert.True(t, routes[i-1].Name < routes[i].Name)
+	}
+
 	// Method missing
 	err = domain.AddRoute(&Route{
 		Name: "MethodMissing",
@@ -390,6 +397,28 @@ func TestRouterDomainRouteURL(t *testing.T) {
 	assert.Equal(t, "//localhost:8080/hotels/12345678/booking", result)
 }
 
+func TestRouterRouteByName(t *testing.T) {
+	router, err := createRouter("routes.conf")
+	assert.Nil(t, err, "")
+
+	route := router.RouteByName("localhost:8080", "public_assets")
+	assert.NotNil(t, route)
+	assert.True(t, route.IsStatic)
+	assert.Equal(t, "/static/*filepath", route.Path)
+
+	route = router.RouteByName("localhost:8080", "app_index#welcome")
+	assert.NotNil(t, route)
+	assert.Equal(t, "app_index", route.Name)
+
+	assert.Nil(t, router.RouteByName("localhost:8080", "not_exists_routename"))
+
+	// catch-all parameter keeps the path separator
+	result := router.CreateRouteURL("localhost:8080", "public_assets", map[string]interface{}{
+		"filepath": "css/aah app.css",
+	})
+	assert.Equal(t, "//localhost:8080/static/css/aah%20app.css", result)
+}
+
 func TestRouterDomainAddRoute(t *testing.T) {
 	domain := &Domain{
 		Host:   "aahframe.work",
@@ -704,3 +733,561 @@ func testdataBaseDir() string {
 	}
 	return filepath.Join(wd, ".testdata")
 }
+
+func TestRouterAntiCSRFPolicy(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	route1 {
+		anti_csrf_policy = "origin"
+	}
+	route2 {
+		anti_csrf_policy = "header"
+	}
+	`)
+
+	policy, err := parseAntiCSRFPolicy(cfg, "route1.anti_csrf_policy", true, "token")
+	assert.Nil(t, err)
+	assert.Equal(t, "origin", policy)
+
+	policy, err = parseAntiCSRFPolicy(cfg, "route3.anti_csrf_policy", true, "origin")
+	assert.Nil(t, err)
+	assert.Equal(t, "origin", policy)
+
+	policy, err = parseAntiCSRFPolicy(cfg, "route3.anti_csrf_policy", true, "exempt")
+	assert.Nil(t, err)
+	assert.Equal(t, "token", policy)
+
+	policy, err = parseAntiCSRFPolicy(cfg, "route3.anti_csrf_policy", false, "token")
+	assert.Nil(t, err)
+	assert.Equal(t, "exempt", policy)
+
+	_, err = parseAntiCSRFPolicy(cfg, "route2.anti_csrf_policy", true, "token")
+	assert.Equal(t, "'route2.anti_csrf_policy' has unsupported value 'header', supported values are token, origin and exempt", err.Error())
+}
+
+func TestRouteAuthSchemeNames(t *testing.T) {
+	r := &Route{Auth: "jwt_auth, api_key ,form_auth,"}
+	assert.Equal(t, []string{"jwt_auth", "api_key", "form_auth"}, r.AuthSchemeNames())
+
+	r = &Route{Auth: "form_auth"}
+	assert.Equal(t, []string{"form_auth"}, r.AuthSchemeNames())
+
+	r = &Route{}
+	assert.Nil(t, r.AuthSchemeNames())
+}
+
+func TestRouteAuthOptional(t *testing.T) {
+	r := &Route{Auth: "optional(form_auth)"}
+	assert.True(t, r.IsAuthOptional())
+	assert.Equal(t, []string{"form_auth"}, r.AuthSchemeNames())
+
+	r = &Route{Auth: "optional(jwt_auth, form_auth)"}
+	assert.True(t, r.IsAuthOptional())
+	assert.Equal(t, []string{"jwt_auth", "form_auth"}, r.AuthSchemeNames())
+
+	r = &Route{Auth: "form_auth"}
+	assert.False(t, r.IsAuthOptional())
+
+	sec := security.New()
+	_ = sec.AddAuthScheme("form_auth", &scheme.FormAuth{LoginSubmitURL: "/login"})
+	d := &Domain{routes: map[string]*Route{
+		"index":   {Name: "index", Auth: "optional(form_auth)"},
+		"profile": {Name: "profile", Auth: "optional()"},
+		"admin":   {Name: "admin", Auth: "form_auth, jwt_auth"},
+	}}
+	names, result := d.isAuthConfigured(sec)
+	assert.False(t, result)
+	assert.Equal(t, 2, len(names))
+	assert.NotContains(t, names, "index")
+}
+
+func TestRouteBulkhead(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	api {
+		path = "/api"
+		controller = "APIController"
+		max_concurrent = 2
+		max_queue = 1
+		queue_timeout = "50ms"
+		routes {
+			users {
+				path = "/users"
+				action = "Users"
+			}
+			reports {
+				path = "/reports"
+				action = "Reports"
+				max_concurrent = 1
+			}
+			health {
+				path = "/health"
+				action = "Health"
+				max_concurrent = 0
+			}
+		}
+	}
+	`)
+
+	routes, err := parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Nil(t, err)
+	bulkheads := map[string]*Bulkhead{}
+	for _, r := range routes {
+		bulkheads[r.Name] = r.Bulkhead
+	}
+	assert.Equal(t, 2, bulkheads["api"].MaxConcurrent)
+	assert.Equal(t, 50*time.Millisecond, bulkheads["api"].QueueTimeout)
+	assert.True(t, bulkheads["api"] == bulkheads["users"])
+	assert.Equal(t, 1, bulkheads["reports"].MaxConcurrent)
+	assert.Equal(t, time.Second, bulkheads["reports"].QueueTimeout)
+	assert.Nil(t, bulkheads["health"])
+
+	bh := bulkheads["api"]
+	assert.True(t, bh.Acquire())
+	assert.True(t, bh.Acquire())
+	assert.Equal(t, 2, bh.InFlight())
+
+	// waits in the queue and gets the slot once released
+	acquired := make(chan bool)
+	go func() { acquired <- bh.Acquire() }()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 1, bh.Queued())
+	assert.False(t, bh.Acquire()) // queue full
+	bh.Release()
+	assert.True(t, <-acquired)
+
+	// queue timeout
+	assert.False(t, bh.Acquire())
+	assert.Equal(t, 0, bh.Queued())
+	bh.Release()
+	bh.Release()
+	assert.Equal(t, 0, bh.InFlight())
+
+	for _, c := range []struct{ cfg, err string }{
+		{`route1 { max_concurrent = -1; }`, "'route1.max_concurrent' value must not be negative"},
+		{`route1 {
+			max_concurrent = 5
+			max_queue = -1
+		}`, "'route1.max_queue' value must not be negative"},
+		{`route1 {
+			max_concurrent = 5
+			queue_timeout = "5"
+		}`, "'route1.queue_timeout' value is not a valid time unit"},
+	} {
+		cfg, _ := config.ParseString(c.cfg)
+		_, err := parseBulkhead(cfg, "route1", nil)
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
+
+func TestRouteJSONCache(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	api {
+		path = "/api"
+		controller = "APIController"
+		json_cache = "5s"
+		routes {
+			products {
+				path = "/products"
+				action = "Products"
+			}
+			orders {
+				path = "/orders"
+				action = "Orders"
+				json_cache = "0s"
+			}
+		}
+	}
+	`)
+
+	routes, err := parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Nil(t, err)
+	ttls := map[string]time.Duration{}
+	for _, r := range routes {
+		ttls[r.Name] = r.JSONCacheTTL
+	}
+	assert.Equal(t, 5*time.Second, ttls["products"])
+	assert.Equal(t, time.Duration(0), ttls["orders"])
+
+	cfg, _ = config.ParseString(`
+	api {
+		path = "/api"
+		controller = "APIController"
+		json_cache = "5"
+	}
+	`)
+	_, err = parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Equal(t, "'api.json_cache' value is not a valid time unit", err.Error())
+}
+
+func TestRouteJSONView(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	api {
+		path = "/api"
+		controller = "APIController"
+		json_view = "public"
+		routes {
+			users {
+				path = "/users"
+				action = "Users"
+			}
+			admin_users {
+				path = "/admin/users"
+				action = "AdminUsers"
+				json_view = "admin"
+			}
+		}
+	}
+	`)
+
+	routes, err := parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Nil(t, err)
+	views := map[string]string{}
+	for _, r := range routes {
+		views[r.Name] = r.JSONView
+	}
+	assert.Equal(t, "public", views["users"])
+	assert.Equal(t, "admin", views["admin_users"])
+}
+
+func TestRouteTimeout(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	api {
+		path = "/api"
+		controller = "APIController"
+		timeout = "5s"
+		routes {
+			users {
+				path = "/users"
+				action = "Users"
+			}
+			reports {
+				path = "/reports"
+				action = "Reports"
+				timeout = "500ms"
+			}
+		}
+	}
+	`)
+
+	routes, err := parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Nil(t, err)
+	timeouts := map[string]time.Duration{}
+	for _, r := range routes {
+		timeouts[r.Name] = r.Timeout
+	}
+	assert.Equal(t, 5*time.Second, timeouts["api"])
+	assert.Equal(t, 5*time.Second, timeouts["users"])
+	assert.Equal(t, 500*time.Millisecond, timeouts["reports"])
+
+	cfg, _ = config.ParseString(`
+	api {
+		path = "/api"
+		controller = "APIController"
+		timeout = "5"
+	}
+	`)
+	_, err = parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Equal(t, "'api.timeout' value is not a valid time unit", err.Error())
+
+	cfg, _ = config.ParseString(`
+	events {
+		path = "/events"
+		controller = "EventController"
+		timeout {
+			request = "2m"
+			write = "10m"
+		}
+		routes {
+			poll {
+				path = "/poll"
+				action = "Poll"
+				timeout {
+					read = "30s"
+				}
+			}
+		}
+	}
+	`)
+	routes, err = parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Nil(t, err)
+	for _, r := range routes {
+		assert.Equal(t, 2*time.Minute, r.Timeout)
+		assert.Equal(t, 10*time.Minute, r.WriteTimeout)
+		if r.Name == "poll" {
+			assert.Equal(t, 30*time.Second, r.ReadTimeout)
+		} else {
+			assert.Equal(t, time.Duration(0), r.ReadTimeout)
+		}
+	}
+
+	cfg, _ = config.ParseString(`
+	events {
+		path = "/events"
+		controller = "EventController"
+		timeout {
+			write = "-1s"
+		}
+	}
+	`)
+	_, err = parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Equal(t, "'events.timeout.write' value must not be negative", err.Error())
+}
+
+func TestRouteStreamBody(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	uploads {
+		path = "/uploads"
+		method = "POST"
+		controller = "UploadController"
+		max_body_size = "1gb"
+		stream_body = true
+		routes {
+			object {
+				path = "/object"
+				method = "POST"
+				action = "Object"
+			}
+			metadata {
+				path = "/metadata"
+				method = "POST"
+				action = "Metadata"
+				stream_body = false
+				max_body_size = "64kb"
+			}
+			list {
+				path = "/list"
+				method = "GET"
+				action = "List"
+			}
+		}
+	}
+	`)
+
+	routes, err := parseSectionRoutes(cfg, &parentRouteInfo{MaxBodySizeStr: "5mb"})
+	assert.Nil(t, err)
+	byName := map[string]*Route{}
+	for _, r := range routes {
+		byName[r.Name] = r
+	}
+	assert.True(t, byName["uploads"].StreamBody)
+	assert.Equal(t, int64(1<<30), byName["uploads"].MaxBodySize)
+	assert.True(t, byName["object"].StreamBody)
+	assert.Equal(t, int64(1<<30), byName["object"].MaxBodySize)
+	assert.False(t, byName["metadata"].StreamBody)
+	assert.Equal(t, int64(64<<10), byName["metadata"].MaxBodySize)
+	assert.False(t, byName["list"].StreamBody)
+	assert.Equal(t, int64(0), byName["list"].MaxBodySize)
+}
+
+func TestRouteLocalePaths(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	i18n_path {
+		enable = true
+		locales = ["en", "DE", "en"]
+	}
+	`)
+	locales, defaultLocale, err := parseLocalePaths(cfg, "localhost")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"en", "de"}, locales)
+	assert.Equal(t, "en", defaultLocale)
+
+	cfg, _ = config.ParseString(`
+	index {
+		path = "/"
+		controller = "SiteController"
+	}
+	products {
+		path = "/products/:id"
+		controller = "ProductController"
+	}
+	`)
+	routes, err := parseSectionRoutes(cfg, &parentRouteInfo{})
+	assert.Nil(t, err)
+
+	domain := &Domain{Locales: locales, DefaultLocale: defaultLocale,
+		trees: make(map[string]*tree), routes: make(map[string]*Route)}
+	for _, r := range localizeRoutes(routes, domain.Locales) {
+		assert.Nil(t, domain.AddRoute(r))
+	}
+	assert.Equal(t, 6, len(domain.routes))
+	assert.Equal(t, "/de", domain.LookupByName("index@de").Path)
+	assert.Equal(t, "/en/products/:id", domain.LookupByName("products@en").Path)
+
+	req := createHTTPRequest("localhost:8080", "/de/products/10")
+	req.Method = ahttp.MethodGet
+	route, params, _ := domain.Lookup(req)
+	assert.NotNil(t, route)
+	assert.Equal(t, "products@de", route.Name)
+	assert.Equal(t, "de", route.Locale)
+	assert.True(t, route.IsLocalized())
+	assert.Equal(t, "10", params.Get("id"))
+	assert.Equal(t, "/en/products/10", route.LocalePath("en", "/de/products/10"))
+	assert.Equal(t, "/products/10", route.LocalePath("", "/DE/products/10"))
+
+	req = createHTTPRequest("localhost:8080", "/")
+	req.Method = ahttp.MethodGet
+	route, _, _ = domain.Lookup(req)
+	assert.Equal(t, "index", route.Name)
+	assert.Equal(t, "", route.Locale)
+	assert.Equal(t, "/de", route.LocalePath("de", "/"))
+	assert.Equal(t, "/", domain.LookupByName("index@en").LocalePath("", "/en"))
+
+	cfg, _ = config.ParseString(`
+	i18n_path {
+		enable = true
+		locales = ["en", "de"]
+		default = "fr"
+	}
+	`)
+	_, _, err = parseLocalePaths(cfg, "localhost")
+	assert.Equal(t, "'localhost.i18n_path.default' value 'fr' is not in locales", err.Error())
+
+	cfg, _ = config.ParseString(`i18n_path { enable = true; }`)
+	_, _, err = parseLocalePaths(cfg, "localhost")
+	assert.Equal(t, "'localhost.i18n_path.locales' value is required", err.Error())
+}
+
+func TestRouteIncludes(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "routes")
+	defer func() { _ = os.RemoveAll(dir) }()
+	_ = os.MkdirAll(filepath.Join(dir, "routes"), 0755)
+	_ = ioutil.WriteFile(filepath.Join(dir, "routes", "admin.conf"), []byte(`
+	users {
+		path = "/users"
+		controller = "AdminController"
+		routes {
+			user_edit {
+				path = "/:id"
+				method = "PUT"
+			}
+		}
+	}
+	`), 0644)
+
+	rfs := new(vfs.VFS)
+	_ = rfs.AddMount("/app/config", dir)
+	forge.RegisterFS(&aahFS{fs: rfs})
+
+	cfg, _ := config.ParseString(`
+	admin {
+		path = "/admin"
+		auth = "form_auth"
+		includes = "routes/admin.conf"
+		namespace = "admin"
+	}
+	`)
+	routes, err := parseSectionRoutes(cfg, &parentRouteInfo{ConfigDir: "/app/config"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(routes))
+	assert.Equal(t, "admin.users", routes[0].Name)
+	assert.Equal(t, "/admin/users", routes[0].Path)
+	assert.Equal(t, "form_auth", routes[0].Auth)
+	assert.Equal(t, "admin.user_edit", routes[1].Name)
+	assert.Equal(t, "/admin/users/:id", routes[1].Path)
+	assert.Equal(t, "admin.users", routes[1].ParentName)
+
+	cfg, _ = config.ParseString(`
+	admin {
+		path = "/admin"
+		includes = ["routes/notexists.conf"]
+	}
+	`)
+	_, err = parseSectionRoutes(cfg, &parentRouteInfo{ConfigDir: "/app/config"})
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "'admin.includes' /app/config/routes/notexists.conf"))
+}
+
+type adminController struct{}
+
+func (c *adminController) Users() {}
+
+func TestRouteDefinition(t *testing.T) {
+	router, err := createRouter("routes.conf")
+	assert.Nil(t, err)
+
+	d, err := NewDefinition("get", "/admin/users/", (*adminController).Users, WithAuth("form_auth"))
+	assert.Nil(t, err)
+	assert.Equal(t, "adminController", d.Route.Target)
+	assert.Equal(t, "Users", d.Route.Action)
+	assert.Equal(t, "admin_users", d.Route.Name)
+	assert.Equal(t, "/admin/users", d.Route.Path)
+	assert.Nil(t, router.AddDefinition(d))
+
+	d, err = NewDefinition("POST", "/admin/users", "admin/AdminController.Create", WithName("admin_create_user"))
+	assert.Nil(t, err)
+	assert.Equal(t, "admin/AdminController", d.Route.Target)
+	assert.Nil(t, router.AddDefinition(d))
+
+	req := createHTTPRequest("localhost:8080", "/admin/users")
+	req.Method = ahttp.MethodGet
+	route, _, _ := router.Lookup(req.Host).Lookup(req)
+	assert.Equal(t, "admin_users", route.Name)
+	assert.Equal(t, "form_auth", route.Auth)
+	assert.True(t, route.MaxBodySize > 0)
+
+	req.Method = ahttp.MethodPost
+	route, _, _ = router.Lookup(req.Host).Lookup(req)
+	assert.Equal(t, "admin_create_user", route.Name)
+	assert.Equal(t, "admin/AdminController", route.Target)
+
+	err = router.AddDefinition(d)
+	assert.True(t, strings.HasPrefix(err.Error(), "router: route name 'admin_create_user' already exists"))
+
+	// wildcard route added in code
+	d, err = NewDefinition("GET", "/share/*filepath", "ShareController.Serve", WithName("share_files"))
+	assert.Nil(t, err)
+	assert.Nil(t, router.AddDefinition(d))
+	req = createHTTPRequest("localhost:8080", "/share/docs/readme.txt")
+	req.Method = ahttp.MethodGet
+	route, urlParams, _ := router.Lookup(req.Host).Lookup(req)
+	assert.Equal(t, "share_files", route.Name)
+	assert.Equal(t, "docs/readme.txt", urlParams.Get("filepath"))
+
+	d, _ = NewDefinition("GET", "/admin/health", "HealthController.Index", WithHost("unknown.example.com"))
+	err = router.AddDefinition(d)
+	assert.Equal(t, "router: route 'health_index' domain not found for host 'unknown.example.com'", err.Error())
+
+	_, err = NewDefinition("GET", "admin", "HealthController.Index")
+	assert.Equal(t, "router: route path 'admin' must begin with '/'", err.Error())
+
+	_, err = NewDefinition("GET", "/admin", 10)
+	assert.Equal(t, "router: invalid route action type 'int'", err.Error())
+
+	_, err = NewDefinition("GET", "/admin", "Index")
+	assert.Equal(t, "router: route action 'Index' is not a controller action", err.Error())
+}
+
+func TestRouteAutoHeadAndOptions(t *testing.T) {
+	router, err := createRouter("routes.conf")
+	assert.Nil(t, err)
+
+	domain := router.Lookup("localhost:8080")
+	assert.True(t, domain.AutoHead)
+
+	// HEAD derived from GET route
+	req := createHTTPRequest("localhost:8080", "/hotels/12345")
+	req.Method = ahttp.MethodHead
+	route, urlParams, _ := domain.Lookup(req)
+	assert.NotNil(t, route)
+	assert.Equal(t, "show_hotels", route.Name)
+	assert.Equal(t, ahttp.MethodGet, route.Method)
+	assert.Equal(t, "12345", urlParams.Get("id"))
+
+	// auto HEAD disabled on route
+	req = createHTTPRequest("localhost:8080", "/hotels/12345/booking")
+	req.Method = ahttp.MethodHead
+	route, _, _ = domain.Lookup(req)
+	assert.Nil(t, route)
+	assert.Equal(t, "GET, POST", domain.Allowed(ahttp.MethodHead, "/hotels/12345/booking"))
+
+	// auto HEAD is set only on GET routes
+	assert.True(t, domain.LookupByName("show_hotels").AutoHead)
+	assert.False(t, domain.LookupByName("login").AutoHead)
+
+	// Allow header value
+	assert.Equal(t, "GET, HEAD, POST", domain.Allowed(ahttp.MethodPut, "/register"))
+	assert.Equal(t, "GET, HEAD, POST", domain.AutoOptionsAllowed("/register"))
+	assert.Equal(t, "POST", domain.AutoOptionsAllowed("/hotels/12345/booking"))
+	assert.Equal(t, "", domain.AutoOptionsAllowed("/not-exists"))
+	assert.True(t, strings.Contains(domain.Allowed(ahttp.MethodOptions, "*"), ahttp.MethodHead))
+}
@@ -8,6 +8,10 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/internal/settings"
 )
 
 const (
@@ -15,13 +19,6 @@ const (
 	ruleEndByte   = ']'
 )
 
-func suffixCommaValue(s, v string) string {
-	if len(s) == 0 {
-		return v
-	}
-	return s + ", " + v
-}
-
 func findActionByHTTPMethod(method string) string {
 	if action, found := HTTPMethodActionMap[method]; found {
 		return action
@@ -101,3 +98,37 @@ func addSlashPrefix(v string) string {
 	}
 	return "/" + v
 }
+
+// parseRouteTimeouts method returns the route request, read and write
+// timeouts. Route `timeout` is either request timeout value or section of
+// timeouts, if route does not define it then parent timeouts are used.
+//
+//	timeout = "30s"
+//
+//	timeout {
+//	  request = "30s"
+//	  read = "1m"
+//	  write = "10m"
+//	}
+func parseRouteTimeouts(cfg *config.Config, routeName string, parent *parentRouteInfo) (request, read, write time.Duration, err error) {
+	key := routeName + ".timeout"
+	if _, found := cfg.GetSubConfig(key); !found {
+		request, err = parseRouteTimeout(cfg, key, parent.Timeout)
+		return request, parent.ReadTimeout, parent.WriteTimeout, err
+	}
+	if request, err = parseRouteTimeout(cfg, key+".request", parent.Timeout); err != nil {
+		return
+	}
+	if read, err = parseRouteTimeout(cfg, key+".read", parent.ReadTimeout); err != nil {
+		return
+	}
+	write, err = parseRouteTimeout(cfg, key+".write", parent.WriteTimeout)
+	return
+}
+
+// parseRouteTimeout method returns the route timeout value of given key, if
+// not defined then parent timeout is used. Value `0s` means route uses
+// server timeout.
+func parseRouteTimeout(cfg *config.Config, key string, parent time.Duration) (time.Duration, error) {
+	return settings.ParseDuration(cfg, key, parent)
+}
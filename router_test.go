@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"aahframe.work/ahttp"
@@ -119,3 +120,62 @@ func TestRouterCORS(t *testing.T) {
 	}
 	CORSMiddleware(ctx5, &Middleware{})
 }
+
+func TestRouterCORSPrivateNetworkPreflight(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	preflight := func(allow bool) http.Header {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(ahttp.MethodOptions, "http://localhost:8080/users/edit", nil)
+		r.Header.Set(ahttp.HeaderAccessControlRequestMethod, ahttp.MethodGet)
+		r.Header.Set(ahttp.HeaderAccessControlRequestPrivateNetwork, "true")
+		r.Header.Set(ahttp.HeaderOrigin, "http://sample.com")
+		ctx := newContext(w, r)
+		ctx.a = ts.app
+		ctx.domain = &router.Domain{CORSEnabled: true}
+		cors := &router.CORS{AllowOrigins: []string{"http://sample.com"}}
+		cors.AddAllowMethods([]string{ahttp.MethodGet}).SetAllowPrivateNetwork(allow).SetMaxAge("2h")
+		ctx.route = &router.Route{CORS: cors}
+		CORSMiddleware(ctx, &Middleware{})
+		return w.Header()
+	}
+
+	hdr := preflight(true)
+	assert.Equal(t, "true", hdr.Get(ahttp.HeaderAccessControlAllowPrivateNetwork))
+	assert.Equal(t, "7200", hdr.Get(ahttp.HeaderAccessControlMaxAge))
+	assert.True(t, strings.Contains(strings.Join(hdr.Values(ahttp.HeaderVary), ","), ahttp.HeaderAccessControlRequestPrivateNetwork))
+
+	hdr = preflight(false)
+	assert.Equal(t, "", hdr.Get(ahttp.HeaderAccessControlAllowPrivateNetwork))
+}
+
+func TestRouterNotFoundAndMethodNotAllowedHandlers(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Not Found and Method Not Allowed handlers]: %s", ts.URL)
+
+	ts.app.SetNotFoundHandler(func(ctx *Context) {
+		ctx.Reply().JSON(map[string]interface{}{"error": "not_found", "path": ctx.Req.Path})
+	})
+	ts.app.SetMethodNotAllowedHandler(func(ctx *Context, allowed []string) {
+		ctx.Reply().JSON(map[string]interface{}{"error": "method_not_allowed", "allowed": allowed})
+	})
+	defer func() {
+		ts.app.SetNotFoundHandler(nil)
+		ts.app.SetMethodNotAllowedHandler(nil)
+	}()
+
+	resp, err := http.Get(ts.URL + "/not-exists")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"error":"not_found","path":"/not-exists"}`, strings.TrimSpace(responseBody(resp)))
+
+	resp, err = http.Post(ts.URL+"/binary-bytes", ahttp.ContentTypeJSON.String(), strings.NewReader(`{}`))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "GET, HEAD, OPTIONS", resp.Header.Get(ahttp.HeaderAllow))
+	assert.Equal(t, `{"allowed":["GET","HEAD","OPTIONS"],"error":"method_not_allowed"}`, strings.TrimSpace(responseBody(resp)))
+}
@@ -0,0 +1,408 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+)
+
+// Runtime toggle names, see `Application.SetRuntimeToggle`.
+const (
+	ToggleAccessLog      = "access_log"
+	ToggleDumpLog        = "dump_log"
+	ToggleMaintenance    = "maintenance"
+	ToggleSlowRequestLog = "slow_request_log"
+)
+
+var runtimeToggles = []string{ToggleAccessLog, ToggleDumpLog, ToggleMaintenance, ToggleSlowRequestLog}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// RuntimeToggles method returns the current state of runtime toggles.
+func (a *Application) RuntimeToggles() map[string]bool {
+	s := a.settings()
+	toggles := map[string]bool{
+		ToggleAccessLog: s.AccessLogEnabled,
+		ToggleDumpLog:   s.DumpLogEnabled,
+	}
+	if rc := a.runtimeCtl; rc != nil {
+		toggles[ToggleMaintenance] = rc.isMaintenance()
+		toggles[ToggleSlowRequestLog] = rc.isSlowRequestLog()
+	}
+	return toggles
+}
+
+// SetRuntimeToggle method turns on or off the given runtime toggle without
+// restart. Change is logged and recorded in the audit trail with given actor
+// if `server.audit` is enabled. Toggles are reset to configured values on
+// hot-reload.
+//
+//	err := aah.App().SetRuntimeToggle(aah.ToggleMaintenance, true, "deploy-bot")
+func (a *Application) SetRuntimeToggle(name string, on bool, actor string) error {
+	return a.setRuntimeToggle(name, on, &toggleOrigin{
+		subject: firstNonZeroString(actor, "app"),
+		method:  "API",
+	})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initRuntimeControl method initializes the runtime toggles from config
+// `runtime.maintenance { ... }`, `server.slow_request_log { ... }` and the
+// runtime control admin endpoint and signal from `runtime.control { ... }`.
+// Control endpoint requires `security.admin_auth` config.
+//
+//	runtime {
+//	  maintenance {
+//	    enable = false
+//	    retry_after = "5m"
+//	    allow_routes = ["health_check"]
+//	  }
+//	  control {
+//	    enable = true
+//	    path = "/_aah/runtime"
+//	    signal {
+//	      name = "SIGUSR1"
+//	      toggle = "dump_log"
+//	    }
+//	  }
+//	}
+//	server {
+//	  slow_request_log {
+//	    enable = true
+//	    threshold = "1s"
+//	  }
+//	}
+func (a *Application) initRuntimeControl() error {
+	cfg := a.Config()
+	rc := &runtimeControl{allowRoutes: make(map[string]bool)}
+
+	keyPrefix := "runtime.maintenance"
+	if cfg.BoolDefault(keyPrefix+".enable", false) {
+		rc.maintenance = 1
+	}
+	retryAfter, err := settings.ParseDuration(cfg, keyPrefix+".retry_after", 0)
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		rc.retryAfter = strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	}
+	routes, _ := cfg.StringList(keyPrefix + ".allow_routes")
+	for _, r := range routes {
+		rc.allowRoutes[r] = true
+	}
+
+	keyPrefix = "server.slow_request_log"
+	if cfg.BoolDefault(keyPrefix+".enable", false) {
+		rc.slowRequestLog = 1
+	}
+	if rc.slowThreshold, err = settings.ParseDuration(cfg, keyPrefix+".threshold", time.Second); err != nil {
+		return err
+	}
+	if rc.slowThreshold <= 0 {
+		return fmt.Errorf("'%s.threshold' value must be greater than zero", keyPrefix)
+	}
+
+	keyPrefix = "runtime.control"
+	var sig os.Signal
+	toggle := ""
+	if cfg.BoolDefault(keyPrefix+".enable", false) {
+		if a.adminGuard == nil {
+			return fmt.Errorf("'%s' requires 'security.admin_auth' config", keyPrefix)
+		}
+		rc.path = cfg.StringDefault(keyPrefix+".path", "/_aah/runtime")
+		if !strings.HasPrefix(rc.path, "/") {
+			return fmt.Errorf("'%s.path' value must begin with '/'", keyPrefix)
+		}
+
+		if name := cfg.StringDefault(keyPrefix+".signal.name", ""); len(name) > 0 {
+			toggle = cfg.StringDefault(keyPrefix+".signal.toggle", "")
+			if !isRuntimeToggle(toggle) {
+				return fmt.Errorf("'%s.signal.toggle' value must be one of %s",
+					keyPrefix, strings.Join(runtimeToggles, ", "))
+			}
+			if sig, err = a.controlSignal(strings.ToUpper(name)); err != nil {
+				return err
+			}
+			rc.signalName = strings.ToUpper(name)
+		}
+	}
+
+	a.stopRuntimeControl()
+	a.runtimeCtl = rc
+	if sig != nil {
+		stop := make(chan struct{})
+		rc.stop = stop
+		a.Go(func(ctx context.Context) { a.listenForToggle(ctx, stop, sig, rc.signalName, toggle) })
+	}
+	return nil
+}
+
+func (a *Application) stopRuntimeControl() {
+	if rc := a.runtimeCtl; rc != nil && rc.stop != nil {
+		close(rc.stop)
+		rc.stop = nil
+	}
+}
+
+// listenForToggle method flips the given runtime toggle on every OS signal
+// until application shutdown or runtime control reinitialize.
+func (a *Application) listenForToggle(ctx context.Context, stop chan struct{}, sig os.Signal, sigName, name string) {
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, sig)
+	defer signal.Stop(sc)
+
+	for {
+		select {
+		case <-sc:
+			on := !a.RuntimeToggles()[name]
+			if err := a.setRuntimeToggle(name, on, &toggleOrigin{subject: "signal", method: "SIGNAL", path: sigName}); err != nil {
+				a.Log().Errorf("Unable to toggle '%s' on signal %s: %v", name, sigName, err)
+			}
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *Application) setRuntimeToggle(name string, on bool, o *toggleOrigin) error {
+	rc := a.runtimeCtl
+	if rc == nil {
+		return errors.New("aah: runtime control is not initialized")
+	}
+
+	a.toggleMu.Lock()
+	defer a.toggleMu.Unlock()
+	switch name {
+	case ToggleAccessLog:
+		if on && a.accessLog == nil {
+			if err := a.initAccessLog(); err != nil {
+				return err
+			}
+		}
+		a.settingsHolder.Update(func(s *settings.Settings) { s.AccessLogEnabled = on })
+	case ToggleDumpLog:
+		if on && a.dumpLog == nil {
+			if err := a.initDumpLog(); err != nil {
+				return err
+			}
+		}
+		a.settingsHolder.Update(func(s *settings.Settings) { s.DumpLogEnabled = on })
+	case ToggleMaintenance:
+		atomic.StoreInt32(&rc.maintenance, boolToInt32(on))
+	case ToggleSlowRequestLog:
+		atomic.StoreInt32(&rc.slowRequestLog, boolToInt32(on))
+	default:
+		return fmt.Errorf("aah: unknown runtime toggle '%s'", name)
+	}
+
+	state := "off"
+	if on {
+		state = "on"
+	}
+	a.Log().Warnf("Runtime toggle '%s' is turned %s by %s", name, state, o.subject)
+	a.auditRuntimeChange("toggle:"+name, map[string]string{name: state}, o)
+	return nil
+}
+
+// auditRuntimeChange method records the runtime change in the audit trail
+// if `server.audit` is enabled.
+func (a *Application) auditRuntimeChange(entity string, params map[string]string, o *toggleOrigin) {
+	if at := a.audit; at != nil {
+		at.enqueue(&AuditRecord{
+			ID:       ess.NewGUID(),
+			Time:     time.Now(),
+			Subject:  o.subject,
+			Route:    "runtime_control",
+			Method:   o.method,
+			Path:     o.path,
+			Params:   params,
+			Entity:   entity,
+			Status:   http.StatusOK,
+			Result:   AuditSuccess,
+			ClientIP: o.clientIP,
+		}, a.Log())
+	}
+}
+
+func isRuntimeToggle(name string) bool {
+	for _, t := range runtimeToggles {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+func boolToInt32(v bool) int32 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Runtime control
+//______________________________________________________________________________
+
+type runtimeControl struct {
+	maintenance    int32
+	slowRequestLog int32
+	slowThreshold  time.Duration
+	retryAfter     string
+	allowRoutes    map[string]bool
+	path           string
+	signalName     string
+	stop           chan struct{}
+}
+
+// toggleOrigin holds who and how the runtime toggle is changed.
+type toggleOrigin struct {
+	subject  string
+	method   string
+	path     string
+	clientIP string
+}
+
+func (rc *runtimeControl) isMaintenance() bool {
+	return atomic.LoadInt32(&rc.maintenance) == 1
+}
+
+func (rc *runtimeControl) isSlowRequestLog() bool {
+	return atomic.LoadInt32(&rc.slowRequestLog) == 1
+}
+
+// origin method returns the admin endpoint request origin, request must be
+// parsed before.
+func (rc *runtimeControl) origin(ctx *Context) *toggleOrigin {
+	r := ctx.Req.Unwrap()
+	o := &toggleOrigin{
+		subject:  firstNonZeroString(r.Form.Get("actor"), "admin"),
+		method:   r.Method,
+		path:     ctx.Req.Path,
+		clientIP: ctx.Req.ClientIP(),
+	}
+	if cert := ctx.Req.ClientCertificate(); cert != nil && len(cert.Subject.CommonName) > 0 {
+		o.subject = cert.Subject.CommonName
+	}
+	return o
+}
+
+// IsUnderMaintenance method returns true if application is in maintenance
+// mode and given route is not allowed, it replies `503` with `Retry-After`.
+func (rc *runtimeControl) IsUnderMaintenance(ctx *Context) bool {
+	if !rc.isMaintenance() || rc.allowRoutes[ctx.route.Name] {
+		return false
+	}
+	if len(rc.retryAfter) > 0 {
+		ctx.Reply().Header(ahttp.HeaderRetryAfter, rc.retryAfter)
+	}
+	ctx.Reply().ServiceUnavailable().Error(newError(ErrMaintenance, http.StatusServiceUnavailable))
+	return true
+}
+
+// LogSlowRequest method logs the request which took longer than
+// `server.slow_request_log.threshold`.
+func (rc *runtimeControl) LogSlowRequest(ctx *Context, start time.Time) {
+	elapsed := time.Since(start)
+	if elapsed < rc.slowThreshold {
+		return
+	}
+	routeName := ""
+	if ctx.route != nil {
+		routeName = ctx.route.Name
+	}
+	ctx.Log().Warnf("Slow request, Method: %s, Path: %s, Route: %s, Status: %d, Duration: %s, Threshold: %s",
+		ctx.Req.Method, ctx.Req.Path, routeName, ctx.Res.Status(), elapsed, rc.slowThreshold)
+}
+
+// Serve method replies the runtime toggles state on `GET` and updates the
+// toggles on `POST` with form or query parameters, for e.g.:
+// `maintenance=true&dump_log=false`. Sub path `/log_levels` serves the
+// logging levels. Optional parameter `actor` is recorded as subject, mTLS
+// client certificate common name takes precedence. Returns true if request
+// is served otherwise false.
+func (rc *runtimeControl) Serve(ctx *Context) bool {
+	isLogLevels := ctx.Req.Path == rc.path+"/log_levels"
+	if len(rc.path) == 0 || (ctx.Req.Path != rc.path && !isLogLevels) ||
+		(ctx.Req.Method != ahttp.MethodGet && ctx.Req.Method != ahttp.MethodPost) {
+		return false
+	}
+	if !ctx.a.verifyAdmin(ctx) {
+		return true
+	}
+	if isLogLevels {
+		rc.serveLogLevels(ctx)
+		return true
+	}
+
+	if ctx.Req.Method == ahttp.MethodPost {
+		r := ctx.Req.Unwrap()
+		if err := r.ParseForm(); err != nil {
+			ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest, err.Error()))
+			return true
+		}
+
+		changes := make(map[string]bool)
+		for k, v := range r.Form {
+			if k == "actor" {
+				continue
+			}
+			if !isRuntimeToggle(k) {
+				ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest,
+					fmt.Sprintf("unknown runtime toggle '%s'", k)))
+				return true
+			}
+			on, err := strconv.ParseBool(v[0])
+			if err != nil {
+				ctx.Reply().BadRequest().Error(newErrorWithData(ErrInvalidRequestParameter, http.StatusBadRequest,
+					fmt.Sprintf("invalid value '%s' for runtime toggle '%s'", v[0], k)))
+				return true
+			}
+			changes[k] = on
+		}
+
+		o := rc.origin(ctx)
+		names := make([]string, 0, len(changes))
+		for k := range changes {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := ctx.a.setRuntimeToggle(name, changes[name], o); err != nil {
+				ctx.Log().Errorf("Unable to toggle '%s': %v", name, err)
+				ctx.Reply().InternalServerError().Error(newError(ErrGeneric, http.StatusInternalServerError))
+				return true
+			}
+		}
+	}
+
+	ctx.Reply().Ok().
+		NoCache().
+		JSON(ctx.a.RuntimeToggles())
+	return true
+}
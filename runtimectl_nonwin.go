@@ -0,0 +1,37 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build !windows
+
+package aah
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// controlSignal method returns the OS signal for given name of
+// `runtime.control.signal.name`, it must not conflict with hot-reload and
+// zero-downtime restart signals.
+func (a *Application) controlSignal(name string) (os.Signal, error) {
+	var sig os.Signal
+	switch name {
+	case "SIGUSR1":
+		sig = syscall.SIGUSR1
+	case "SIGUSR2":
+		sig = syscall.SIGUSR2
+	default:
+		return nil, errors.New("'runtime.control.signal.name' value must be SIGUSR1 or SIGUSR2")
+	}
+
+	s := a.settings()
+	if s.HotReloadEnabled && s.HotReloadSignal() == sig {
+		return nil, errors.New("'runtime.control.signal.name' conflicts with 'runtime.config_hotreload.signal'")
+	}
+	if s.RestartEnabled && sig == syscall.SIGUSR2 {
+		return nil, errors.New("'runtime.control.signal.name' SIGUSR2 is used by 'server.restart.enable'")
+	}
+	return sig, nil
+}
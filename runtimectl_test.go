@@ -0,0 +1,172 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuntimeControl(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Runtime Control]: %s", ts.URL)
+
+	sink := &testAuditSink{records: make(chan *AuditRecord, 10)}
+	assert.Nil(t, ts.app.AddAuditSink("runtime_test", sink))
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	runtime {
+	  control {
+	    enable = true
+	  }
+	  maintenance {
+	    retry_after = "90s"
+	    allow_routes = ["text_get"]
+	  }
+	}
+	security {
+	  admin_auth {
+	    tokens = ["admintoken"]
+	  }
+	}
+	server {
+	  audit {
+	    enable = true
+	    routes = ["form_submit"]
+	    sinks = ["runtime_test"]
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initAdmin())
+	assert.Nil(t, ts.app.initAudit())
+	defer ts.app.stopAudit()
+	assert.Nil(t, ts.app.initRuntimeControl())
+
+	send := func(method, query string) (*http.Response, map[string]bool) {
+		req, _ := http.NewRequest(method, ts.URL+"/_aah/runtime"+query, nil)
+		req.Header.Set(ahttp.HeaderAuthorization, "Bearer admintoken")
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		toggles := map[string]bool{}
+		if resp.StatusCode == http.StatusOK {
+			assert.Nil(t, json.NewDecoder(resp.Body).Decode(&toggles))
+		}
+		return resp, toggles
+	}
+
+	resp, err := http.Get(ts.URL + "/_aah/runtime")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, toggles := send(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.False(t, toggles[ToggleMaintenance])
+	assert.False(t, toggles[ToggleSlowRequestLog])
+
+	// turn on maintenance mode
+	resp, toggles = send(http.MethodPost, "?maintenance=true&slow_request_log=1&actor=ops")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, toggles[ToggleMaintenance])
+	assert.True(t, toggles[ToggleSlowRequestLog])
+
+	r := waitAuditRecord(t, sink)
+	assert.Equal(t, "toggle:maintenance", r.Entity)
+	assert.Equal(t, "ops", r.Subject)
+	assert.Equal(t, "on", r.Params[ToggleMaintenance])
+	assert.Equal(t, http.MethodPost, r.Method)
+	r = waitAuditRecord(t, sink)
+	assert.Equal(t, "toggle:slow_request_log", r.Entity)
+
+	resp, err = http.Get(ts.URL + "/")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "90", resp.Header.Get(ahttp.HeaderRetryAfter))
+
+	resp, err = http.Get(ts.URL + "/get-text.html")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// invalid toggle and value
+	resp, _ = send(http.MethodPost, "?readonly=true")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = send(http.MethodPost, "?maintenance=maybe")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.True(t, ts.app.RuntimeToggles()[ToggleMaintenance])
+
+	// programmatic toggle
+	assert.Nil(t, ts.app.SetRuntimeToggle(ToggleMaintenance, false, "deploy-bot"))
+	r = waitAuditRecord(t, sink)
+	assert.Equal(t, "deploy-bot", r.Subject)
+	assert.Equal(t, "off", r.Params[ToggleMaintenance])
+	assert.Equal(t, "aah: unknown runtime toggle 'readonly'",
+		ts.app.SetRuntimeToggle("readonly", true, "").Error())
+
+	resp, err = http.Get(ts.URL + "/")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// hot-reload resets toggles to configured values
+	assert.Nil(t, ts.app.initRuntimeControl())
+	assert.False(t, ts.app.RuntimeToggles()[ToggleSlowRequestLog])
+}
+
+func TestRuntimeControlInitErrors(t *testing.T) {
+	a := newApp()
+
+	assert.Nil(t, setTestConfig(a, `
+	runtime {
+	  control {
+	    enable = true
+	  }
+	}
+	`))
+	err := a.initRuntimeControl()
+	assert.Equal(t, "'runtime.control' requires 'security.admin_auth' config", err.Error())
+
+	assert.Nil(t, setTestConfig(a, `
+	runtime {
+	  control {
+	    enable = true
+	    signal {
+	      name = "SIGUSR1"
+	      toggle = "readonly"
+	    }
+	  }
+	}
+	security {
+	  admin_auth {
+	    tokens = ["admintoken"]
+	  }
+	}
+	`))
+	assert.Nil(t, a.initAdmin())
+	err = a.initRuntimeControl()
+	assert.Equal(t, "'runtime.control.signal.toggle' value must be one of access_log, dump_log, maintenance, slow_request_log", err.Error())
+
+	assert.Nil(t, setTestConfig(a, `
+	server {
+	  slow_request_log {
+	    threshold = "0s"
+	  }
+	}
+	`))
+	err = a.initRuntimeControl()
+	assert.Equal(t, "'server.slow_request_log.threshold' value must be greater than zero", err.Error())
+
+	assert.Nil(t, setTestConfig(a, `
+	runtime {
+	  maintenance {
+	    retry_after = "5x"
+	  }
+	}
+	`))
+	err = a.initRuntimeControl()
+	assert.Equal(t, "'runtime.maintenance.retry_after' value is not a valid time unit", err.Error())
+}
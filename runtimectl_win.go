@@ -0,0 +1,18 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package aah
+
+import (
+	"errors"
+	"os"
+)
+
+// controlSignal method is not supported on OS Windows, it does not have
+// signals `SIGUSR1` and `SIGUSR2`.
+func (a *Application) controlSignal(name string) (os.Signal, error) {
+	return nil, errors.New("'runtime.control.signal' is not supported on OS Windows")
+}
@@ -7,16 +7,20 @@ package aah
 import (
 	"net/http"
 	"net/url"
-	"strings"
+	"time"
 
 	"aahframe.work/ahttp"
+	"aahframe.work/cache"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/internal/util"
 	"aahframe.work/security"
 	"aahframe.work/security/anticsrf"
 	"aahframe.work/security/authc"
 	"aahframe.work/security/authz"
+	"aahframe.work/security/nonce"
 	"aahframe.work/security/scheme"
+	"aahframe.work/security/session"
 )
 
 const (
@@ -29,6 +33,10 @@ const (
 	// KeyOAuth2Token key name is used to store OAuth2 Access Token into `aah.Context`.
 	KeyOAuth2Token = "_aahOAuth2Token"
 
+	// KeyImpersonatorAuthcInfo key name is used to store original user's
+	// `AuthenticationInfo` instance into session while impersonating.
+	KeyImpersonatorAuthcInfo = "_aahImpersonatorAuthcInfo"
+
 	keyAntiCSRF       = "_aahAntiCSRF"
 	keyOAuth2StateKey = "_aahOAuth2State"
 	keyAuthScheme     = "_aahAuthScheme"
@@ -41,15 +49,39 @@ const (
 func (a *Application) initSecurity() error {
 	asecmgr := security.New()
 	asecmgr.IsSSLEnabled = a.IsSSLEnabled()
+	if name := a.Config().StringDefault("security.nonce_store.cache", ""); len(name) > 0 {
+		asecmgr.NonceStore = nonce.New(func() cache.Cache { return a.CacheManager().Cache(name) })
+	}
 	if err := asecmgr.Init(a.Config()); err != nil {
 		return err
 	}
 
+	if asecmgr.Verifier != nil {
+		asecmgr.Verifier.SetMailer(a.verifyMailer)
+	}
+	asecmgr.SessionManager.OnExpired(a.publishSessionExpired)
+
+	// stop the previous session cleanup on hot-reload
+	if a.securityMgr != nil && a.securityMgr.SessionManager != nil {
+		a.securityMgr.SessionManager.Close()
+	}
+
 	a.securityMgr = asecmgr
-	a.settings.AuthSchemeExists = len(a.securityMgr.AuthSchemes()) > 0
+	authSchemeExists := len(a.securityMgr.AuthSchemes()) > 0
+	a.settingsHolder.Update(func(s *settings.Settings) { s.AuthSchemeExists = authSchemeExists })
 	return nil
 }
 
+// publishSessionExpired method publishes the event `OnSessionExpired` with
+// subject of expired session.
+func (a *Application) publishSessionExpired(s *session.Session) {
+	subject := &security.Subject{Session: s}
+	if authcInfo, ok := s.Get(KeyViewArgAuthcInfo).(*authc.AuthenticationInfo); ok {
+		subject.AuthenticationInfo = authcInfo
+	}
+	a.PublishEvent(EventOnSessionExpired, subject)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Authentication and Authorization Middleware
 //______________________________________________________________________________
@@ -59,11 +91,17 @@ func AuthcAuthzMiddleware(ctx *Context, m *Middleware) {
 	// Continue with the flow, if -
 	// 		- Auth scheme is not defined in `security.conf`
 	// 		- Route auth is `anonymous`
-	if !ctx.a.settings.AuthSchemeExists || ctx.route.Auth == "anonymous" {
+	if !ctx.a.settings().AuthSchemeExists || ctx.route.Auth == "anonymous" {
 		m.Next(ctx)
 		return
 	}
 
+	// Route auth is optional e.g. `optional(form_auth)`
+	if ctx.route.IsAuthOptional() {
+		doOptionalAuth(ctx, m)
+		return
+	}
+
 	// If session is authenticated then populate subject and continue the request flow.
 	if ctx.Subject().IsAuthenticated() {
 		if key := ctx.Session().GetString(keyAuthScheme); key != "" {
@@ -80,29 +118,79 @@ func AuthcAuthzMiddleware(ctx *Context, m *Middleware) {
 	}
 
 	// Supports one or more auth scheme on route
-	var result flowResult
-	for _, s := range strings.Split(ctx.route.Auth, ",") {
-		authScheme := ctx.a.SecurityManager().AuthScheme(strings.TrimSpace(s))
-		ctx.Log().Debugf("Processing route auth scheme: %s", authScheme.Key())
-		switch authScheme.Scheme() {
-		case "form":
-			result = doFormAuth(authScheme, ctx)
-		case "oauth2":
-			result = doOAuth2(authScheme, ctx)
-		default:
-			result = doAuthScheme(authScheme, ctx)
-		}
+	authScheme := routeAuthScheme(ctx)
+	if doRouteAuthScheme(authScheme, ctx) == flowCont && hasAccess(ctx) == flowCont {
+		m.Next(ctx)
+	}
+}
 
-		if result == flowCont {
-			break
+// doOptionalAuth method processes the route which has optional auth
+// e.g. `auth = "optional(form_auth)"`. Subject is populated when request
+// has credentials for the auth scheme, otherwise request continues as anonymous.
+// Invalid credentials are rejected as usual. Route authorization is not
+// applied, since route serves anonymous users too.
+func doOptionalAuth(ctx *Context, m *Middleware) {
+	if ctx.Subject().IsAuthenticated() {
+		if key := ctx.Session().GetString(keyAuthScheme); key != "" {
+			populateAuthorizationInfo(ctx.a.SecurityManager().AuthScheme(key), ctx)
 		}
+		m.Next(ctx)
+		return
+	}
+
+	authScheme := credentialsAuthScheme(ctx)
+	if authScheme == nil {
+		ctx.Log().Debug("Optional auth: credentials not found, continuing as anonymous")
+		m.Next(ctx)
+		return
 	}
 
-	if result == flowCont && hasAccess(ctx) == flowCont {
+	if doRouteAuthScheme(authScheme, ctx) == flowCont {
 		m.Next(ctx)
 	}
 }
 
+func doRouteAuthScheme(authScheme scheme.Schemer, ctx *Context) flowResult {
+	ctx.Log().Debugf("Processing route auth scheme: %s", authScheme.Key())
+	switch authScheme.Scheme() {
+	case "form":
+		return doFormAuth(authScheme, ctx)
+	case "oauth2":
+		return doOAuth2(authScheme, ctx)
+	}
+	return doAuthScheme(authScheme, ctx)
+}
+
+// routeAuthScheme method returns the applicable auth scheme for the request.
+// On auth scheme chain e.g. `auth = "jwt, apikey, form_auth"`, first auth scheme
+// that recognizes the request credentials wins. If none of them recognizes then
+// last auth scheme is used as fallback, typically it challenges the client
+// (login page redirect, WWW-Authenticate header, etc.).
+func routeAuthScheme(ctx *Context) scheme.Schemer {
+	names := ctx.route.AuthSchemeNames()
+	if len(names) == 1 {
+		return ctx.a.SecurityManager().AuthScheme(names[0])
+	}
+
+	if authScheme := credentialsAuthScheme(ctx); authScheme != nil {
+		return authScheme
+	}
+	return ctx.a.SecurityManager().AuthScheme(names[len(names)-1])
+}
+
+// credentialsAuthScheme method returns the first route auth scheme which
+// recognizes the request credentials otherwise nil.
+func credentialsAuthScheme(ctx *Context) scheme.Schemer {
+	for _, name := range ctx.route.AuthSchemeNames() {
+		authScheme := ctx.a.SecurityManager().AuthScheme(name)
+		if scheme.HasCredentials(authScheme, ctx.Req) {
+			ctx.Log().Debugf("Auth scheme '%s' recognized the request credentials", name)
+			return authScheme
+		}
+	}
+	return nil
+}
+
 // doFormAuth method does Form Authentication and Authorization.
 func doFormAuth(authScheme scheme.Schemer, ctx *Context) flowResult {
 	formAuth := authScheme.(*scheme.FormAuth)
@@ -233,20 +321,29 @@ func doAuthentication(authScheme scheme.Schemer, ctx *Context) flowResult {
 		var err error
 		authcInfo, err = authScheme.DoAuthenticate(authScheme.ExtractAuthenticationToken(ctx.Req))
 		if err != nil || authcInfo == nil {
-			switch sa := authScheme.(type) {
-			case *scheme.FormAuth:
-				ctx.Log().Infof("%s: Authentication is failed, sending to login failure URL", authScheme.Key())
-				ctx.Reply().Redirect(util.AddQueryString(sa.LoginFailureURL, "_rt", ctx.Req.FormValue("_rt")))
-			case *scheme.BasicAuth:
-				ctx.Log().Infof("%s: Authentication is failed", authScheme.Key())
-				ctx.Reply().Header(ahttp.HeaderWWWAuthenticate, `Basic realm="`+sa.RealmName+`"`)
-				ctx.Reply().Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
-			}
-
+			authenticationFailed(authScheme, ctx)
 			return flowAbort
 		}
 	}
 
+	// Session fixation defense, assign new session ID on login
+	if err := ctx.regenerateSessionID(); err != nil {
+		ctx.Log().Errorf("%s: unable to regenerate session ID: %v", authScheme.Key(), err)
+		authenticationFailed(authScheme, ctx)
+		return flowAbort
+	}
+
+	// Concurrent sessions per principal
+	if ctx.a.SessionManager().IsConcurrencyControlEnabled() {
+		if p := authcInfo.PrimaryPrincipal(); p != nil {
+			if err := ctx.a.SessionManager().RegisterSession(p.Value, ctx.Session()); err != nil {
+				ctx.Log().Warnf("%s: %s, principal '%s'", authScheme.Key(), err, p.Value)
+				authenticationFailed(authScheme, ctx)
+				return flowAbort
+			}
+		}
+	}
+
 	populateAuthenticationInfo(authcInfo, ctx)
 	ctx.Session().IsAuthenticated = true
 	ctx.Session().Set(keyAuthScheme, authScheme.Key())
@@ -266,6 +363,18 @@ func doAuthentication(authScheme scheme.Schemer, ctx *Context) flowResult {
 	return flowCont
 }
 
+func authenticationFailed(authScheme scheme.Schemer, ctx *Context) {
+	switch sa := authScheme.(type) {
+	case *scheme.FormAuth:
+		ctx.Log().Infof("%s: Authentication is failed, sending to login failure URL", authScheme.Key())
+		ctx.Reply().Redirect(util.AddQueryString(sa.LoginFailureURL, "_rt", ctx.Req.FormValue("_rt")))
+	case *scheme.BasicAuth:
+		ctx.Log().Infof("%s: Authentication is failed", authScheme.Key())
+		ctx.Reply().Header(ahttp.HeaderWWWAuthenticate, `Basic realm="`+sa.RealmName+`"`)
+		ctx.Reply().Unauthorized().Error(newError(ErrAuthenticationFailed, http.StatusUnauthorized))
+	}
+}
+
 func populateAuthenticationInfo(authcInfo *authc.AuthenticationInfo, ctx *Context) {
 	ctx.Subject().AuthenticationInfo = authcInfo
 	ctx.logger = ctx.Log().WithField("principal", ctx.Subject().PrimaryPrincipal().Value)
@@ -292,21 +401,161 @@ func debugLogSubjectInfo(ctx *Context) {
 	ctx.Log().Debug(ctx.Subject().AuthorizationInfo)
 }
 
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Impersonation
+//______________________________________________________________________________
+
+// Impersonation type holds the details of impersonation, it is used as
+// event data of `OnImpersonate` and `OnImpersonateRevert` events for
+// auditing purpose.
+type Impersonation struct {
+	Impersonator *authc.AuthenticationInfo
+	Impersonated *authc.AuthenticationInfo
+	ClientIP     string
+	Time         time.Time
+}
+
+// Impersonate method allows the authenticated subject (typically an admin)
+// to act as given user. Original identity is retained in the session, use
+// `ctx.RevertImpersonation()` to switch back. Authorization info of the
+// given user is populated from subject's auth scheme.
+//
+// If config `security.impersonation.permission` is set, subject must have
+// that permission to impersonate.
+//
+// Note: Impersonation requires stateful session.
+func (ctx *Context) Impersonate(authcInfo *authc.AuthenticationInfo) error {
+	if authcInfo == nil || authcInfo.PrimaryPrincipal() == nil {
+		return ErrUnableToGetPrincipal
+	}
+	if !ctx.a.SessionManager().IsStateful() {
+		return ErrSessionNotStateful
+	}
+	if !ctx.Subject().IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	if ctx.Subject().IsImpersonated() {
+		return ErrImpersonationInProgress
+	}
+	if perm := ctx.a.Config().StringDefault("security.impersonation.permission", ""); len(perm) > 0 &&
+		!ctx.Subject().IsPermitted(perm) {
+		return ErrAccessDenied
+	}
+
+	if err := ctx.regenerateSessionID(); err != nil {
+		return err
+	}
+
+	impersonator := ctx.Subject().AuthenticationInfo
+	ctx.Session().Set(KeyImpersonatorAuthcInfo, impersonator)
+	ctx.switchSubject(authcInfo)
+	ctx.Subject().Impersonator = impersonator
+
+	ctx.Log().Warnf("Principal '%s' is impersonating '%s'",
+		impersonator.PrimaryPrincipal().Value, authcInfo.PrimaryPrincipal().Value)
+	ctx.a.PublishEventSync(EventOnImpersonate, ctx.newImpersonation(impersonator, authcInfo))
+	return nil
+}
+
+// RevertImpersonation method switches back to original identity of the subject.
+func (ctx *Context) RevertImpersonation() error {
+	if !ctx.Subject().IsImpersonated() {
+		return ErrNotImpersonated
+	}
+
+	if err := ctx.regenerateSessionID(); err != nil {
+		return err
+	}
+
+	impersonator, impersonated := ctx.Subject().Impersonator, ctx.Subject().AuthenticationInfo
+	ctx.Session().Del(KeyImpersonatorAuthcInfo)
+	ctx.switchSubject(impersonator)
+	ctx.Subject().Impersonator = nil
+
+	ctx.Log().Warnf("Principal '%s' reverted impersonation of '%s'",
+		impersonator.PrimaryPrincipal().Value, impersonated.PrimaryPrincipal().Value)
+	ctx.a.PublishEventSync(EventOnImpersonateRevert, ctx.newImpersonation(impersonator, impersonated))
+	return nil
+}
+
+// regenerateSessionID method assigns new session ID on privilege change if
+// `security.session.regenerate_id` is enabled. Newly created session already
+// has server generated ID, so it is skipped.
+func (ctx *Context) regenerateSessionID() error {
+	sm := ctx.a.SessionManager()
+	if !sm.IsStateful() || !sm.IsRegenerateIDEnabled() || ctx.Session().IsNew {
+		return nil
+	}
+	return ctx.Session().RegenerateID()
+}
+
+func (ctx *Context) switchSubject(authcInfo *authc.AuthenticationInfo) {
+	populateAuthenticationInfo(authcInfo, ctx)
+	ctx.Session().Set(KeyViewArgAuthcInfo, authcInfo)
+	if authScheme := ctx.a.SecurityManager().AuthScheme(ctx.Session().GetString(keyAuthScheme)); authScheme != nil {
+		populateAuthorizationInfo(authScheme, ctx)
+	}
+}
+
+func (ctx *Context) newImpersonation(impersonator, impersonated *authc.AuthenticationInfo) *Impersonation {
+	return &Impersonation{
+		Impersonator: impersonator,
+		Impersonated: impersonated,
+		ClientIP:     ctx.Req.ClientIP(),
+		Time:         time.Now(),
+	}
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Anti-CSRF Middleware
 //______________________________________________________________________________
 
 // AntiCSRFMiddleware provides feature to prevent Cross-Site Request Forgery (CSRF)
 // attacks.
+//
+// Anti-CSRF protection is applied per route policy `anti_csrf_policy`:
+//
+//   - `token` verifies the Anti-CSRF token (default, requires view engine).
+//
+//   - `origin` verifies the request origin only, suitable for JSON APIs.
+//
+//   - `exempt` skips the protection.
+//
+// Additionally, for unsafe HTTP methods request header `Sec-Fetch-Site` is
+// validated, if present.
 func AntiCSRFMiddleware(ctx *Context, m *Middleware) {
 	// If Anti-CSRF is not enabled, move on.
 	// It is highly recommended to enable it for web application.
-	if !ctx.a.SecurityManager().AntiCSRF.Enabled || !ctx.route.IsAntiCSRFCheck || ctx.a.ViewEngine() == nil {
+	if !ctx.a.SecurityManager().AntiCSRF.Enabled || !ctx.route.IsAntiCSRFCheck ||
+		(ctx.route.AntiCSRFPolicy != anticsrf.PolicyOrigin && ctx.a.ViewEngine() == nil) {
 		ctx.a.SecurityManager().AntiCSRF.ClearCookie(ctx.Res, ctx.Req)
 		m.Next(ctx)
 		return
 	}
 
+	if !anticsrf.IsSafeHTTPMethod(ctx.Req.Method) {
+		// Fetch metadata validation, supported browsers sends it
+		if !ctx.a.SecurityManager().AntiCSRF.IsAllowedSecFetchSite(ctx.Req) {
+			ctx.Log().Warnf("anticsrf: Cross-site request, Sec-Fetch-Site: %s", ctx.Req.Header.Get(ahttp.HeaderSecFetchSite))
+			ctx.Reply().Forbidden().Error(newError(anticsrf.ErrCrossSiteRequest, http.StatusForbidden))
+			return
+		}
+
+		if ctx.route.AntiCSRFPolicy == anticsrf.PolicyOrigin {
+			if err := anticsrf.VerifyOrigin(ctx.Req); err != nil {
+				ctx.Log().Warnf("anticsrf: Origin verification failed, %s", err)
+				ctx.Reply().Forbidden().Error(newError(err, http.StatusForbidden))
+				return
+			}
+		}
+	}
+
+	// Origin policy does not use Anti-CSRF token
+	if ctx.route.AntiCSRFPolicy == anticsrf.PolicyOrigin {
+		m.Next(ctx)
+		return
+	}
+
 	// Get cipher secret from anti-csrf cookie
 	secret := ctx.a.SecurityManager().AntiCSRF.CipherSecret(ctx.Req)
 	ctx.AddViewArg(keyAntiCSRF, secret)
@@ -0,0 +1,248 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package acrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// AEAD algorithm names supported by `NewAEAD`.
+const (
+	AlgAES256GCM         = "aes-256-gcm"
+	AlgXChaCha20Poly1305 = "xchacha20-poly1305"
+)
+
+const (
+	aeadKeySize        = 32
+	aeadMaxKeyIDLength = 255
+)
+
+var (
+	// ErrUnsupportedAlgorithm returned when given AEAD algorithm is not supported.
+	ErrUnsupportedAlgorithm = errors.New("security/crypto: unsupported algorithm")
+
+	// ErrInvalidKeySize returned when given AEAD key is not 32 bytes.
+	ErrInvalidKeySize = errors.New("security/crypto: invalid key size, it must be 32 bytes")
+
+	// ErrInvalidKeyID returned when given key ID is empty or longer than 255 bytes.
+	ErrInvalidKeyID = errors.New("security/crypto: invalid key id")
+
+	// ErrKeyNotFound returned when the key ID of ciphertext is not known to AEAD.
+	ErrKeyNotFound = errors.New("security/crypto: key not found")
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// AEAD
+//___________________________________
+
+// AEAD provides authenticated encryption with associated data using
+// AES-256-GCM or XChaCha20-Poly1305 along with key rotation. Each ciphertext
+// carries the ID of the key it was encrypted with, so values encrypted with
+// older keys can be decrypted after rotation.
+//
+// Ciphertext format is `len(keyID) | keyID | nonce | sealed data`.
+//
+//	a, err := acrypto.NewAEAD(acrypto.AlgXChaCha20Poly1305, "2019-01", key)
+//	ciphertext, err := a.Encrypt([]byte("my secret"), nil)
+//
+//	// rotate, new values are encrypted with "2019-02"
+//	err = a.Rotate("2019-02", newKey)
+type AEAD struct {
+	sync.RWMutex
+	alg     string
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewAEAD method creates the AEAD for given algorithm with primary key.
+// Key must be 32 bytes, use `DeriveKey` to derive it from secret.
+func NewAEAD(alg, keyID string, key []byte) (*AEAD, error) {
+	if alg != AlgAES256GCM && alg != AlgXChaCha20Poly1305 {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	a := &AEAD{alg: alg, keys: make(map[string]cipher.AEAD)}
+	if err := a.Rotate(keyID, key); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Algorithm method returns the AEAD algorithm name.
+func (a *AEAD) Algorithm() string {
+	return a.alg
+}
+
+// PrimaryKeyID method returns the key ID used for encryption.
+func (a *AEAD) PrimaryKeyID() string {
+	a.RLock()
+	defer a.RUnlock()
+	return a.primary
+}
+
+// AddKey method adds the given key for decryption only, typically the
+// previously used keys.
+func (a *AEAD) AddKey(keyID string, key []byte) error {
+	if len(keyID) == 0 || len(keyID) > aeadMaxKeyIDLength {
+		return ErrInvalidKeyID
+	}
+	c, err := newAEADCipher(a.alg, key)
+	if err != nil {
+		return err
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.keys[keyID] = c
+	return nil
+}
+
+// RemoveKey method removes the given key, primary key cannot be removed.
+func (a *AEAD) RemoveKey(keyID string) {
+	a.Lock()
+	defer a.Unlock()
+	if keyID != a.primary {
+		delete(a.keys, keyID)
+	}
+}
+
+// Rotate method adds the given key and makes it primary key for encryption.
+// Previous keys are retained for decryption.
+func (a *AEAD) Rotate(keyID string, key []byte) error {
+	if err := a.AddKey(keyID, key); err != nil {
+		return err
+	}
+	a.Lock()
+	defer a.Unlock()
+	a.primary = keyID
+	return nil
+}
+
+// Encrypt method encrypts and authenticates the given plaintext and
+// additional data using primary key.
+func (a *AEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	a.RLock()
+	keyID, c := a.primary, a.keys[a.primary]
+	a.RUnlock()
+
+	nonce := make([]byte, c.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(keyID)+len(nonce)+len(plaintext)+c.Overhead())
+	out = append(out, byte(len(keyID)))
+	out = append(out, keyID...)
+	out = append(out, nonce...)
+	return c.Seal(out, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt method authenticates and decrypts the given ciphertext and
+// additional data using the key it was encrypted with.
+func (a *AEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < 1 || len(ciphertext) < 1+int(ciphertext[0]) {
+		return nil, ErrUnableToDecrypt
+	}
+	keyID := string(ciphertext[1 : 1+ciphertext[0]])
+	ciphertext = ciphertext[1+len(keyID):]
+
+	a.RLock()
+	c, found := a.keys[keyID]
+	a.RUnlock()
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	if len(ciphertext) < c.NonceSize()+c.Overhead() {
+		return nil, ErrUnableToDecrypt
+	}
+	plaintext, err := c.Open(nil, ciphertext[:c.NonceSize()], ciphertext[c.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrUnableToDecrypt
+	}
+	return plaintext, nil
+}
+
+// EncryptString method is convenient method to encrypt the text and returns
+// URL safe base64 value.
+func (a *AEAD) EncryptString(text string) (string, error) {
+	b, err := a.Encrypt([]byte(text), nil)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecryptString method is convenient method to decrypt the value created by
+// `AEAD.EncryptString`.
+func (a *AEAD) DecryptString(encryptedText string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(encryptedText)
+	if err != nil {
+		return "", err
+	}
+	if b, err = a.Decrypt(b, nil); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package KDF/Compare methods
+//___________________________________
+
+// DeriveKey method derives the key of given length from secret using HKDF
+// with given SHA name. Salt is optional, info binds the derived key to
+// its purpose e.g. "session-encryption".
+//
+// Supported SHA's are SHA-1, SHA-224, SHA-256, SHA-384, SHA-512.
+func DeriveKey(sha string, secret, salt, info []byte, length int) ([]byte, error) {
+	h := hashFunc(sha)
+	if h == nil {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	key := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(h, secret, salt, info), key); err != nil {
+		return nil, fmt.Errorf("security/crypto: unable to derive key: %v", err)
+	}
+	return key, nil
+}
+
+// Equal method compares the given values in constant time. Returns true
+// if both are equal otherwise false.
+func Equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// EqualString method compares the given strings in constant time.
+func EqualString(a, b string) bool {
+	return Equal([]byte(a), []byte(b))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func newAEADCipher(alg string, key []byte) (cipher.AEAD, error) {
+	if len(key) != aeadKeySize {
+		return nil, ErrInvalidKeySize
+	}
+	if alg == AlgXChaCha20Poly1305 {
+		return chacha20poly1305.NewX(key)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
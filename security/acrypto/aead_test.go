@@ -0,0 +1,141 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package acrypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAEADEncryptAndDecrypt(t *testing.T) {
+	for _, alg := range []string{AlgAES256GCM, AlgXChaCha20Poly1305} {
+		t.Run(alg, func(t *testing.T) {
+			key1 := []byte("467b2d53632646a0a9c6cc0d498a7559")
+			key2 := []byte("b6a7f1e1d34c4e5f8a9b0c1d2e3f4a5b")
+
+			a, err := NewAEAD(alg, "k1", key1)
+			assert.Nil(t, err)
+			assert.Equal(t, alg, a.Algorithm())
+			assert.Equal(t, "k1", a.PrimaryKeyID())
+
+			text := []byte("This is the text gonna be encrypted and decrypted")
+			aad := []byte("user:1234")
+			c1, err := a.Encrypt(text, aad)
+			assert.Nil(t, err)
+			assert.Equal(t, "k1", string(c1[1:1+c1[0]]))
+
+			result, err := a.Decrypt(c1, aad)
+			assert.Nil(t, err)
+			assert.Equal(t, text, result)
+
+			// additional data mismatch
+			_, err = a.Decrypt(c1, []byte("user:5678"))
+			assert.Equal(t, ErrUnableToDecrypt, err)
+
+			// tampered
+			tampered := append([]byte(nil), c1...)
+			tampered[len(tampered)-1] ^= 0x01
+			_, err = a.Decrypt(tampered, aad)
+			assert.Equal(t, ErrUnableToDecrypt, err)
+
+			// key rotation
+			assert.Nil(t, a.Rotate("k2", key2))
+			assert.Equal(t, "k2", a.PrimaryKeyID())
+			c2, err := a.Encrypt(text, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, "k2", string(c2[1:1+c2[0]]))
+			result, err = a.Decrypt(c1, aad)
+			assert.Nil(t, err)
+			assert.Equal(t, text, result)
+
+			a.RemoveKey("k2") // primary cannot be removed
+			a.RemoveKey("k1")
+			_, err = a.Decrypt(c1, aad)
+			assert.Equal(t, ErrKeyNotFound, err)
+			result, err = a.Decrypt(c2, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, text, result)
+
+			// string
+			encryptedText, err := a.EncryptString(string(text))
+			assert.Nil(t, err)
+			str, err := a.DecryptString(encryptedText)
+			assert.Nil(t, err)
+			assert.Equal(t, string(text), str)
+
+			_, err = a.Decrypt([]byte{}, nil)
+			assert.Equal(t, ErrUnableToDecrypt, err)
+			_, err = a.Decrypt([]byte{2, 'k', '2', 1, 2}, nil)
+			assert.Equal(t, ErrUnableToDecrypt, err)
+		})
+	}
+}
+
+func TestAEADErrors(t *testing.T) {
+	a, err := NewAEAD("aes-128-cbc", "k1", []byte("467b2d53632646a0a9c6cc0d498a7559"))
+	assert.Nil(t, a)
+	assert.Equal(t, ErrUnsupportedAlgorithm, err)
+
+	a, err = NewAEAD(AlgAES256GCM, "k1", []byte("467b2d53632646a0"))
+	assert.Nil(t, a)
+	assert.Equal(t, ErrInvalidKeySize, err)
+
+	a, err = NewAEAD(AlgXChaCha20Poly1305, "", []byte("467b2d53632646a0a9c6cc0d498a7559"))
+	assert.Nil(t, a)
+	assert.Equal(t, ErrInvalidKeyID, err)
+}
+
+func TestAEADXChaCha20Poly1305Vector(t *testing.T) {
+	// Test vector from draft-irtf-cfrg-xchacha section A.3.1
+	key, _ := hex.DecodeString("808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+	nonce, _ := hex.DecodeString("404142434445464748494a4b4c4d4e4f5051525354555657")
+	aad, _ := hex.DecodeString("50515253c0c1c2c3c4c5c6c7")
+	plaintext := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	expected := "bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb" +
+		"731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b452" +
+		"2f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff9" +
+		"21f9664c97637da9768812f615c68b13b52e" +
+		"c0875924c1c7987947deafd8780acf49" // tag
+
+	c, err := newAEADCipher(AlgXChaCha20Poly1305, key)
+	assert.Nil(t, err)
+	sealed := c.Seal(nil, nonce, plaintext, aad)
+	assert.Equal(t, expected, hex.EncodeToString(sealed))
+
+	// AEAD opens the known ciphertext i.e. `len(keyID) | keyID | nonce | sealed data`
+	a, err := NewAEAD(AlgXChaCha20Poly1305, "k1", key)
+	assert.Nil(t, err)
+	ciphertext := append([]byte{2, 'k', '1'}, nonce...)
+	ciphertext = append(ciphertext, sealed...)
+	result, err := a.Decrypt(ciphertext, aad)
+	assert.Nil(t, err)
+	assert.Equal(t, plaintext, result)
+
+	// tampered tag
+	ciphertext[len(ciphertext)-1] ^= 0x01
+	_, err = a.Decrypt(ciphertext, aad)
+	assert.Equal(t, ErrUnableToDecrypt, err)
+}
+
+func TestDeriveKeyAndEqual(t *testing.T) {
+	// Test case 1 from RFC 5869
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	okm, err := DeriveKey("sha-256", ikm, salt, info, 42)
+	assert.Nil(t, err)
+	assert.Equal(t, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		hex.EncodeToString(okm))
+
+	_, err = DeriveKey("md5", ikm, nil, nil, 32)
+	assert.Equal(t, ErrUnsupportedAlgorithm, err)
+
+	assert.True(t, Equal([]byte("secret"), []byte("secret")))
+	assert.False(t, Equal([]byte("secret"), []byte("secreT")))
+	assert.False(t, EqualString("secret", "secret1"))
+	assert.True(t, EqualString("", ""))
+}
@@ -5,7 +5,6 @@
 package acrypto
 
 import (
-	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
 	"strconv"
@@ -67,5 +66,5 @@ func (pe *Pbkdf2Encoder) Compare(hash, password []byte) bool {
 
 	otherHash := pbkdf2.Key(password, salt, iter, len(dkHash), hashFunc(hashAlg))
 
-	return (subtle.ConstantTimeCompare(dkHash, otherHash) == 1)
+	return Equal(dkHash, otherHash)
 }
@@ -5,7 +5,6 @@
 package acrypto
 
 import (
-	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
 	"strconv"
@@ -85,5 +84,5 @@ func (se *ScryptEncoder) Compare(hash, password []byte) bool {
 		return false
 	}
 
-	return (subtle.ConstantTimeCompare(dkHash, otherHash) == 1)
+	return Equal(dkHash, otherHash)
 }
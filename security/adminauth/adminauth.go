@@ -0,0 +1,224 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package adminauth provides lightweight access control for aah framework
+// admin endpoints such as diagnosis (pprof), metrics, settings, etc. It's
+// independent of the application auth schemes. Supported mechanisms are
+// static bearer token, client IP allowlist and mTLS; when more than one is
+// configured all of them must pass.
+//
+// Configuration goes into `security.conf` under `security.admin_auth { ... }`.
+//
+//	security {
+//	  admin_auth {
+//	    tokens = ["c1f0f9b7e6bd4b6a8f2a"]
+//	    allow_ips = ["127.0.0.1", "10.0.0.0/8"]
+//	    mtls {
+//	      client_ca_file = "/etc/ssl/admin-ca.pem"
+//	      allow_common_names = ["ops-dashboard"]
+//	    }
+//	  }
+//	}
+package adminauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"aahframe.work/security/acrypto"
+)
+
+const keyPrefix = "security.admin_auth"
+
+var (
+	// ErrIPNotAllowed returned when client IP address is not in the allowlist.
+	ErrIPNotAllowed = errors.New("security/adminauth: ip address not allowed")
+
+	// ErrTokenInvalid returned when bearer token is missing or invalid.
+	ErrTokenInvalid = errors.New("security/adminauth: token invalid")
+
+	// ErrClientCertRequired returned when request does not have verified
+	// client certificate.
+	ErrClientCertRequired = errors.New("security/adminauth: client certificate required")
+
+	// ErrClientCertNotAllowed returned when client certificate common name
+	// is not allowed.
+	ErrClientCertNotAllowed = errors.New("security/adminauth: client certificate not allowed")
+)
+
+// New method creates the admin endpoint guard from config
+// `security.admin_auth`. Returns nil if it's not configured, nil guard
+// allows all the requests.
+func New(cfg *config.Config) (*Guard, error) {
+	if !cfg.IsExists(keyPrefix) {
+		return nil, nil
+	}
+
+	g := &Guard{}
+	g.tokens, _ = cfg.StringList(keyPrefix + ".tokens")
+	for _, t := range g.tokens {
+		if ess.IsStrEmpty(t) {
+			return nil, fmt.Errorf("'%s.tokens' must not have empty value", keyPrefix)
+		}
+	}
+
+	ips, _ := cfg.StringList(keyPrefix + ".allow_ips")
+	for _, v := range ips {
+		ipNet, err := parseIPNet(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("'%s.allow_ips' has invalid value '%s'", keyPrefix, v)
+		}
+		g.allowIPs = append(g.allowIPs, ipNet)
+	}
+
+	if caFile := cfg.StringDefault(keyPrefix+".mtls.client_ca_file", ""); len(caFile) > 0 {
+		b, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("'%s.mtls.client_ca_file': %s", keyPrefix, err)
+		}
+		g.clientCAs = x509.NewCertPool()
+		if !g.clientCAs.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("'%s.mtls.client_ca_file' does not have valid PEM certificate", keyPrefix)
+		}
+		g.commonNames, _ = cfg.StringList(keyPrefix + ".mtls.allow_common_names")
+	}
+
+	if len(g.tokens) == 0 && len(g.allowIPs) == 0 && g.clientCAs == nil {
+		return nil, fmt.Errorf("'%s' requires at least one of tokens, allow_ips or mtls", keyPrefix)
+	}
+	return g, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Guard
+//___________________________________
+
+// Guard verifies the incoming requests of admin endpoints.
+type Guard struct {
+	tokens      []string
+	allowIPs    []*net.IPNet
+	clientCAs   *x509.CertPool
+	commonNames []string
+}
+
+// Verify method verifies the given request against configured mechanisms.
+// Client IP address is taken from connection remote address, since proxy
+// headers can be spoofed.
+func (g *Guard) Verify(r *http.Request) error {
+	if g == nil {
+		return nil
+	}
+
+	if len(g.allowIPs) > 0 && !g.isIPAllowed(r) {
+		return ErrIPNotAllowed
+	}
+
+	if g.clientCAs != nil {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			return ErrClientCertRequired
+		}
+		cn := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		if len(g.commonNames) > 0 && !ess.IsSliceContainsString(g.commonNames, cn) {
+			return ErrClientCertNotAllowed
+		}
+	}
+
+	if len(g.tokens) > 0 && !g.isTokenValid(r) {
+		return ErrTokenInvalid
+	}
+	return nil
+}
+
+// Handler method returns the HTTP handler which verifies the request before
+// calling the given handler. Invalid token is responded with
+// `401 Unauthorized` and others with `403 Forbidden`.
+func (g *Guard) Handler(h http.Handler) http.Handler {
+	if g == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch err := g.Verify(r); err {
+		case nil:
+			h.ServeHTTP(w, r)
+		case ErrTokenInvalid:
+			w.Header().Set(ahttp.HeaderWWWAuthenticate, "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		default:
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+	})
+}
+
+// TLSConfig method returns the TLS config which requires and verifies
+// the client certificate if mTLS is configured otherwise nil.
+func (g *Guard) TLSConfig() *tls.Config {
+	if g == nil || g.clientCAs == nil {
+		return nil
+	}
+	return &tls.Config{
+		ClientCAs:  g.clientCAs,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (g *Guard) isIPAllowed(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range g.allowIPs {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Guard) isTokenValid(r *http.Request) bool {
+	hv := r.Header.Get(ahttp.HeaderAuthorization)
+	if len(hv) < 7 || !strings.EqualFold(hv[:7], "bearer ") {
+		return false
+	}
+	token := strings.TrimSpace(hv[7:])
+	valid := false
+	for _, t := range g.tokens {
+		// compare with all tokens, so timing does not reveal the position
+		if acrypto.EqualString(t, token) {
+			valid = true
+		}
+	}
+	return valid
+}
+
+func parseIPNet(v string) (*net.IPNet, error) {
+	if strings.Contains(v, "/") {
+		_, ipNet, err := net.ParseCIDR(v)
+		return ipNet, err
+	}
+	ip := net.ParseIP(v)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip address: %s", v)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package adminauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuthNotConfigured(t *testing.T) {
+	cfg, _ := config.ParseString(`security { }`)
+	g, err := New(cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, g)
+	assert.Nil(t, g.Verify(httptest.NewRequest("GET", "/diagnosis", nil)))
+	assert.Nil(t, g.TLSConfig())
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	assert.NotNil(t, g.Handler(h))
+}
+
+func TestAdminAuthTokenAndIP(t *testing.T) {
+	cfg, _ := config.ParseString(`security {
+		admin_auth {
+			tokens = ["token1", "token2"]
+			allow_ips = ["127.0.0.1", "10.0.0.0/8", "::1"]
+		}
+	}`)
+	g, err := New(cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, g.TLSConfig())
+
+	newReq := func(remoteAddr, authz string) *http.Request {
+		r := httptest.NewRequest("GET", "/diagnosis", nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set("X-Forwarded-For", "10.1.1.1")
+		if len(authz) > 0 {
+			r.Header.Set("Authorization", authz)
+		}
+		return r
+	}
+
+	assert.Nil(t, g.Verify(newReq("127.0.0.1:5678", "Bearer token1")))
+	assert.Nil(t, g.Verify(newReq("10.20.30.40:5678", "bearer token2")))
+	assert.Nil(t, g.Verify(newReq("[::1]:5678", "Bearer token2")))
+	assert.Equal(t, ErrIPNotAllowed, g.Verify(newReq("192.168.1.10:5678", "Bearer token1")))
+	assert.Equal(t, ErrTokenInvalid, g.Verify(newReq("127.0.0.1:5678", "Bearer token3")))
+	assert.Equal(t, ErrTokenInvalid, g.Verify(newReq("127.0.0.1:5678", "Basic dG9rZW4x")))
+	assert.Equal(t, ErrTokenInvalid, g.Verify(newReq("127.0.0.1:5678", "")))
+
+	h := g.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("diagnosis"))
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newReq("127.0.0.1:5678", "Bearer token1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "diagnosis", w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newReq("127.0.0.1:5678", ""))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newReq("192.168.1.10:5678", "Bearer token1"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminAuthMTLS(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := createTestCert(t, caFile, "ops-dashboard")
+
+	cfg, _ := config.ParseString(`security {
+		admin_auth {
+			mtls {
+				client_ca_file = "` + filepath.ToSlash(caFile) + `"
+				allow_common_names = ["ops-dashboard"]
+			}
+		}
+	}`)
+	g, err := New(cfg)
+	assert.Nil(t, err)
+
+	tlsCfg := g.TLSConfig()
+	assert.NotNil(t, tlsCfg)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsCfg.ClientAuth)
+
+	r := httptest.NewRequest("GET", "/diagnosis", nil)
+	assert.Equal(t, ErrClientCertRequired, g.Verify(r))
+
+	r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	assert.Nil(t, g.Verify(r))
+
+	g.commonNames = []string{"billing"}
+	assert.Equal(t, ErrClientCertNotAllowed, g.Verify(r))
+}
+
+func TestAdminAuthConfigErrors(t *testing.T) {
+	testcases := []struct {
+		cfg string
+		err string
+	}{
+		{
+			cfg: `security { admin_auth { } }`,
+			err: "'security.admin_auth' requires at least one of tokens, allow_ips or mtls",
+		},
+		{
+			cfg: `security { admin_auth { tokens = [""]; } }`,
+			err: "'security.admin_auth.tokens' must not have empty value",
+		},
+		{
+			cfg: `security { admin_auth { allow_ips = ["10.0.0.0/33"]; } }`,
+			err: "'security.admin_auth.allow_ips' has invalid value '10.0.0.0/33'",
+		},
+		{
+			cfg: `security { admin_auth { allow_ips = ["localhost"]; } }`,
+			err: "'security.admin_auth.allow_ips' has invalid value 'localhost'",
+		},
+		{
+			cfg: `security { admin_auth { mtls { client_ca_file = "adminauth_test.go"; } } }`,
+			err: "'security.admin_auth.mtls.client_ca_file' does not have valid PEM certificate",
+		},
+	}
+
+	for _, tc := range testcases {
+		cfg, err := config.ParseString(tc.cfg)
+		assert.Nil(t, err)
+		g, err := New(cfg)
+		assert.Nil(t, g)
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
+
+func createTestCert(t *testing.T, file, commonName string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	return cert
+}
@@ -5,13 +5,15 @@
 package anticsrf
 
 import (
-	"crypto/subtle"
 	"errors"
 	"net/http"
+	"strings"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+	"aahframe.work/security/acrypto"
 	"aahframe.work/security/cookie"
 )
 
@@ -21,17 +23,35 @@ var (
 	ErrMalformedReferer = errors.New("security/anticsrf: malformed referer")
 	ErrBadReferer       = errors.New("security/anticsrf: bad referer")
 	ErrNoCookieFound    = errors.New("security/anticsrf: no cookie found")
+	ErrBadOrigin        = errors.New("security/anticsrf: bad origin")
+	ErrCrossSiteRequest = errors.New("security/anticsrf: cross-site request")
+)
+
+// Anti-CSRF route policies, configured via route config `anti_csrf_policy`.
+//
+//   - `token` verifies the Anti-CSRF token, typically HTML form based routes.
+//
+//   - `origin` verifies only the request origin via `Origin` or `Referer`
+//     header, typically JSON API routes consumed by browser.
+//
+//   - `exempt` skips the Anti-CSRF protection.
+const (
+	PolicyToken  = "token"
+	PolicyOrigin = "origin"
+	PolicyExempt = "exempt"
 )
 
 // AntiCSRF struct hold the implementation of Anti CSRF (aka XSRF) protection.
 type AntiCSRF struct {
 	Enabled       bool
+	SecFetchSite  bool
 	cfg           *config.Config
 	cookieMgr     *cookie.Manager
 	secretLength  int
 	cookieName    string
 	headerName    string
 	formFieldName string
+	allowedSites  []string
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -51,6 +71,13 @@ func New(cfg *config.Config) (*AntiCSRF, error) {
 	c.headerName = c.cfg.StringDefault(keyPrefix+".header_name", "X-Anti-CSRF-Token")
 	c.formFieldName = c.cfg.StringDefault(keyPrefix+".form_field_name", "anti_csrf_token")
 
+	// Fetch metadata request header 'Sec-Fetch-Site' validation
+	c.SecFetchSite = c.cfg.BoolDefault(keyPrefix+".sec_fetch_site.enable", true)
+	c.allowedSites, _ = c.cfg.StringList(keyPrefix + ".sec_fetch_site.allowed")
+	if len(c.allowedSites) == 0 {
+		c.allowedSites = []string{"same-origin", "same-site", "none"}
+	}
+
 	// Anit CSRF cookie options
 	c.cookieName = c.cfg.StringDefault(keyPrefix+".prefix", "aah") + "_anti_csrf"
 	opts := &cookie.Options{
@@ -68,6 +95,9 @@ func New(cfg *config.Config) (*AntiCSRF, error) {
 	if opts.MaxAge, err = toSeconds(ttl); err != nil {
 		return nil, err
 	}
+	if opts.ClockSkew, err = settings.ClockSkew(c.cfg); err != nil {
+		return nil, err
+	}
 
 	if c.cookieMgr, err = cookie.NewManager(opts,
 		c.cfg.StringDefault(keyPrefix+".sign_key", ""),
@@ -82,6 +112,11 @@ func New(cfg *config.Config) (*AntiCSRF, error) {
 // AntiCSRF methods
 //___________________________________
 
+// FormFieldName method returns the Anti-CSRF token form field name.
+func (ac *AntiCSRF) FormFieldName() string {
+	return ac.formFieldName
+}
+
 // GenerateSecret method generates new secure secret by configured length.
 func (ac *AntiCSRF) GenerateSecret() []byte {
 	return ess.GenerateSecureRandomKey(ac.secretLength)
@@ -124,9 +159,20 @@ func (ac *AntiCSRF) RequestCipherSecret(r *ahttp.Request) []byte {
 	return ac.unsaltCipherToken(tokenBytes)
 }
 
+// IsAllowedSecFetchSite method returns true if request header `Sec-Fetch-Site`
+// value is allowed per config `security.anti_csrf.sec_fetch_site.allowed`
+// or header not present (older browsers and non-browser clients) otherwise false.
+func (ac *AntiCSRF) IsAllowedSecFetchSite(r *ahttp.Request) bool {
+	site := r.Header.Get(ahttp.HeaderSecFetchSite)
+	if !ac.SecFetchSite || len(site) == 0 {
+		return true
+	}
+	return ess.IsSliceContainsString(ac.allowedSites, strings.ToLower(site))
+}
+
 // IsAuthentic method compares the given secret and request secret.
 func (ac *AntiCSRF) IsAuthentic(secret, requestSecret []byte) bool {
-	return subtle.ConstantTimeCompare(secret, requestSecret) == 1
+	return acrypto.Equal(secret, requestSecret)
 }
 
 // SaltCipherSecret method returns salted chiper secret.
@@ -128,3 +128,58 @@ func TestAntiCSRFTimeUnit(t *testing.T) {
 	assert.Equal(t, int64(0), v)
 	assert.Equal(t, errors.New("unsupported time unit '10s' on 'security.anti_csrf.ttl'"), err)
 }
+
+func TestAntiCSRFSecFetchSite(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	security {
+		anti_csrf {
+			enable = true
+		}
+	}
+	`)
+	antiCSRF, err := New(cfg)
+	assert.Nil(t, err)
+	assert.True(t, antiCSRF.SecFetchSite)
+
+	req := ahttp.AcquireRequest(httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/login", nil))
+	assert.True(t, antiCSRF.IsAllowedSecFetchSite(req))
+
+	for site, allowed := range map[string]bool{"same-origin": true, "same-site": true,
+		"none": true, "cross-site": false, "Cross-Site": false} {
+		req.Header.Set(ahttp.HeaderSecFetchSite, site)
+		assert.Equal(t, allowed, antiCSRF.IsAllowedSecFetchSite(req), site)
+	}
+
+	antiCSRF.SecFetchSite = false
+	assert.True(t, antiCSRF.IsAllowedSecFetchSite(req))
+}
+
+func TestAntiCSRFVerifyOrigin(t *testing.T) {
+	req := ahttp.AcquireRequest(httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/api/v1/users", nil))
+	assert.Equal(t, ErrNoReferer, VerifyOrigin(req))
+
+	// opaque origin, e.g. sandboxed iframe with no-referrer policy
+	req.Header.Set(ahttp.HeaderOrigin, "null")
+	assert.Equal(t, ErrBadOrigin, VerifyOrigin(req))
+	req.Header.Del(ahttp.HeaderOrigin)
+
+	req.Header.Set(ahttp.HeaderReferer, "http://localhost:8080/users.html")
+	assert.Nil(t, VerifyOrigin(req))
+
+	req.Header.Set(ahttp.HeaderOrigin, "null")
+	assert.Equal(t, ErrBadOrigin, VerifyOrigin(req))
+
+	req.Header.Set(ahttp.HeaderOrigin, "http://localhost:8080")
+	assert.Nil(t, VerifyOrigin(req))
+
+	req.Header.Set(ahttp.HeaderOrigin, "https://evil.example.com")
+	assert.Equal(t, ErrBadOrigin, VerifyOrigin(req))
+
+	req.Header.Set(ahttp.HeaderOrigin, "http://local host:%zz")
+	assert.Equal(t, ErrMalformedReferer, VerifyOrigin(req))
+
+	assert.True(t, IsValidPolicy(PolicyToken))
+	assert.True(t, IsValidPolicy(PolicyOrigin))
+	assert.True(t, IsValidPolicy(PolicyExempt))
+	assert.False(t, IsValidPolicy("header"))
+}
@@ -35,6 +35,38 @@ func IsSameOrigin(a, b *url.URL) bool {
 	return (a.Scheme == b.Scheme && a.Host == b.Host)
 }
 
+// IsValidPolicy method returns true if given value is supported Anti-CSRF
+// route policy otherwise false.
+func IsValidPolicy(policy string) bool {
+	return policy == PolicyToken || policy == PolicyOrigin || policy == PolicyExempt
+}
+
+// VerifyOrigin method verifies the request origin from `Origin` header,
+// if not present then `Referer` header. Opaque origin `null` (e.g. sandboxed
+// iframe) is rejected and so does the request without both headers.
+func VerifyOrigin(r *ahttp.Request) error {
+	origin := r.Header.Get(ahttp.HeaderOrigin)
+	if origin == "null" {
+		return ErrBadOrigin
+	}
+	if len(origin) == 0 {
+		origin = r.Referer()
+	}
+	if len(origin) == 0 {
+		return ErrNoReferer
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return ErrMalformedReferer
+	}
+
+	if !IsSameOrigin(r.URL(), u) {
+		return ErrBadOrigin
+	}
+	return nil
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Unexported methods
 //___________________________________
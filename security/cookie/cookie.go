@@ -27,13 +27,27 @@ var (
 	ErrCookieTimestampIsTooNew  = errors.New("security/cookie: timestamp is too new")
 	ErrCookieTimestampIsExpired = errors.New("security/cookie: timestamp expried")
 	ErrSignVerificationIsFailed = errors.New("security/cookie: sign verification is failed")
+	ErrCookieEncKeyIsInvalid    = errors.New("security/cookie: enc key must be 16, 24 or 32 bytes")
 )
 
+// cookieEncKeyInfo is HKDF info to derive the cookie encryption key from
+// configured `enc_key`.
+var cookieEncKeyInfo = []byte("aah-cookie-encryption")
+
+// cookieEncKeyID is the AEAD key ID, every encrypted value is prefixed with
+// it. It's used to distinguish the legacy AES-CTR encrypted values.
+const cookieEncKeyID = "1"
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Package methods
 //___________________________________
 
-// NewManager method returns the new cookie manager.
+// NewManager method returns the new cookie manager. Cookie value is encrypted
+// with XChaCha20-Poly1305, its key is derived from given `encKey` using HKDF.
+// Given `encKey` must be 16, 24 or 32 bytes.
+//
+// Values encrypted with AES-CTR by earlier versions are still decrypted if
+// the sign key is configured, since AES-CTR is not authenticated.
 func NewManager(opts *Options, signKey, encKey string) (*Manager, error) {
 	m := &Manager{Options: opts, maxCookieSize: 4096, sha: "sha-256"}
 
@@ -44,11 +58,19 @@ func NewManager(opts *Options, signKey, encKey string) (*Manager, error) {
 	}
 
 	// Enc key
-	var err error
 	m.isEncKey = !ess.IsStrEmpty(encKey)
 	if m.isEncKey {
-		m.encKey = []byte(encKey)
-		if m.cipherBlock, err = aes.NewCipher(m.encKey); err != nil {
+		if l := len(encKey); l != 16 && l != 24 && l != 32 {
+			return nil, ErrCookieEncKeyIsInvalid
+		}
+		key, err := acrypto.DeriveKey("sha-256", []byte(encKey), nil, cookieEncKeyInfo, 32)
+		if err != nil {
+			return nil, err
+		}
+		if m.aead, err = acrypto.NewAEAD(acrypto.AlgXChaCha20Poly1305, cookieEncKeyID, key); err != nil {
+			return nil, err
+		}
+		if m.legacyBlock, err = aes.NewCipher([]byte(encKey)); err != nil {
 			return nil, err
 		}
 	}
@@ -93,8 +115,8 @@ type Manager struct {
 	signKey       []byte
 	sha           string
 	isEncKey      bool
-	encKey        []byte
-	cipherBlock   cipher.Block
+	aead          *acrypto.AEAD
+	legacyBlock   cipher.Block
 	maxCookieSize int
 }
 
@@ -107,6 +129,10 @@ type Options struct {
 	HTTPOnly bool
 	Secure   bool
 	SameSite string
+
+	// ClockSkew is the tolerance applied on cookie timestamp validation,
+	// see config `security.clock_skew`.
+	ClockSkew time.Duration
 }
 
 // New method creates new cookie instance for given value with cookie manager options.
@@ -133,14 +159,18 @@ func (m *Manager) Write(w http.ResponseWriter, value string) {
 // Encode method encodes given value.
 //
 // It performs:
-//   1) Encrypts it if encryption key configured
+//   1) Encrypts it if encryption key configured, cookie name is bound as
+//      additional data
 //   2) Signs the value if sign key configured
 //   3) Encodes value into Base64 string
 //   4) Checks max cookie size i.e 4Kb
 func (m *Manager) Encode(b []byte) (string, error) {
 	// Encrypt it
 	if m.isEncKey {
-		b = acrypto.AESEncrypt(m.cipherBlock, b)
+		var err error
+		if b, err = m.aead.Encrypt(b, []byte(m.Options.Name)); err != nil {
+			return "", err
+		}
 	}
 
 	// Encode it
@@ -179,8 +209,23 @@ func (m *Manager) Encode(b []byte) (string, error) {
 //   3) Validates the signed data
 //   4) Validates timestamp
 //   5) Decodes the value using Base64
-//   6) Decrypts the value
+//   6) Decrypts the value, legacy AES-CTR value is decrypted if signed
 func (m *Manager) Decode(value string) ([]byte, error) {
+	return m.decode(value, true)
+}
+
+// DecodeExpired method decodes the secure cookie value same as `Decode`
+// except the timestamp expiry validation. It is used to read the expired
+// value for cleanup purpose.
+func (m *Manager) DecodeExpired(value string) ([]byte, error) {
+	return m.decode(value, false)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (m *Manager) decode(value string, checkExpiry bool) ([]byte, error) {
 	// Check cookie max size.
 	if len(value) > m.maxCookieSize {
 		return nil, ErrCookieValueIsTooLarge
@@ -213,10 +258,11 @@ func (m *Manager) Decode(value string) ([]byte, error) {
 		return nil, ErrCookieInvaildTimestamp
 	}
 	t2 := currentTimestamp()
-	if t1 > t2 {
+	skew := int64(m.Options.ClockSkew / time.Second)
+	if t1 > t2+skew {
 		return nil, ErrCookieTimestampIsTooNew
 	}
-	if m.Options.MaxAge != 0 && t1 < t2-m.Options.MaxAge {
+	if checkExpiry && m.Options.MaxAge != 0 && t1 < t2-m.Options.MaxAge-skew {
 		return nil, ErrCookieTimestampIsExpired
 	}
 
@@ -226,7 +272,10 @@ func (m *Manager) Decode(value string) ([]byte, error) {
 		return nil, err
 	}
 	if m.isEncKey {
-		if b, err = acrypto.AESDecrypt(m.cipherBlock, b); err != nil {
+		if m.isLegacyValue(b) {
+			return acrypto.AESDecrypt(m.legacyBlock, b)
+		}
+		if b, err = m.aead.Decrypt(b, []byte(m.Options.Name)); err != nil {
 			return nil, err
 		}
 	}
@@ -234,9 +283,12 @@ func (m *Manager) Decode(value string) ([]byte, error) {
 	return b, nil
 }
 
-//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
-// Unexported methods
-//___________________________________
+// isLegacyValue method returns true if the given encrypted value is not
+// prefixed with AEAD key ID and its signature is verified.
+func (m *Manager) isLegacyValue(b []byte) bool {
+	return m.isSignKey && !(len(b) > len(cookieEncKeyID) &&
+		int(b[0]) == len(cookieEncKeyID) && string(b[1:1+len(cookieEncKeyID)]) == cookieEncKeyID)
+}
 
 // currentTimestamp method return current UTC time in unix format.
 func currentTimestamp() int64 {
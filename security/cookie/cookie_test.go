@@ -5,11 +5,14 @@
 package cookie
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/essentials"
+	"aahframe.work/security/acrypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,4 +73,66 @@ func TestCookieManager(t *testing.T) {
 
 	_, err = cm.Decode(value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value + value)
 	assert.Equal(t, ErrCookieValueIsTooLarge, err)
+
+	// expired value
+	opts.MaxAge = -1
+	_, err = cm.Decode(result)
+	assert.Equal(t, ErrCookieTimestampIsExpired, err)
+	obj, err = cm.DecodeExpired(result)
+	assert.Nil(t, err)
+	assert.Equal(t, value, string(obj))
+
+	// expired within clock skew tolerance
+	opts.ClockSkew = 2 * time.Second
+	obj, err = cm.Decode(result)
+	assert.Nil(t, err)
+	assert.Equal(t, value, string(obj))
+
+	// encrypted value is bound to cookie name
+	other, err := NewManager(&Options{Name: "other", MaxAge: 1800}, "", "KYqklJsgeclPpZutTeQKNOTWlpksRBwA")
+	assert.Nil(t, err)
+	_, err = other.Decode(result)
+	assert.Equal(t, acrypto.ErrUnableToDecrypt, err)
+
+	// invalid enc key length
+	_, err = NewManager(&Options{Name: "aah"}, "", "short-key")
+	assert.Equal(t, ErrCookieEncKeyIsInvalid, err)
+}
+
+func TestCookieLegacyValue(t *testing.T) {
+	signKey, encKey := "eFWLXEewECptbDVXExokRTLONWxrTjfV", "KYqklJsgeclPpZutTeQKNOTWlpksRBwA"
+	cm, err := NewManager(&Options{Name: "aah", MaxAge: 1800}, signKey, encKey)
+	assert.Nil(t, err)
+
+	// value encoded by earlier versions, encrypted with AES-CTR
+	b := ess.EncodeToBase64(acrypto.AESEncrypt(cm.legacyBlock, []byte("legacy session value")))
+	b = []byte(fmt.Sprintf("aah|%d|%s|", currentTimestamp(), b))
+	b = append(b, acrypto.Sign([]byte(signKey), b[:len(b)-1], "sha-256")...)
+	legacy := string(ess.EncodeToBase64(b[len("aah|"):]))
+
+	obj, err := cm.Decode(legacy)
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy session value", string(obj))
+
+	// unsigned legacy value is not decrypted, since AES-CTR is not authenticated
+	unsigned, err := NewManager(&Options{Name: "aah", MaxAge: 1800}, "", encKey)
+	assert.Nil(t, err)
+	_, err = unsigned.Decode(legacy)
+	assert.NotNil(t, err)
+}
+
+func TestCookieClockSkew(t *testing.T) {
+	cm, err := NewManager(&Options{Name: "aah", MaxAge: 1800}, "", "")
+	assert.Nil(t, err)
+
+	// timestamp from a server whose clock is 10 seconds ahead
+	future := fmt.Sprintf("%d|%s|", currentTimestamp()+10, ess.EncodeToBase64([]byte("value")))
+	encoded := string(ess.EncodeToBase64([]byte(future)))
+	_, err = cm.Decode(encoded)
+	assert.Equal(t, ErrCookieTimestampIsTooNew, err)
+
+	cm.Options.ClockSkew = 30 * time.Second
+	obj, err := cm.Decode(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, "value", string(obj))
 }
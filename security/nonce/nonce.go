@@ -0,0 +1,248 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package nonce provides short-lived token store for aah framework security
+// features such as OAuth2 state, replay protection and password reset
+// tokens. Entries are namespaced per feature and expire after the TTL.
+//
+// Store is backed by aah cache subsystem, configure the cache name via
+// `security.nonce_store.cache`. In-memory store is used if not configured,
+// it's suitable for single instance application.
+//
+//	security {
+//	  nonce_store {
+//	    cache = "security_nonces"
+//	  }
+//	}
+package nonce
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/essentials"
+)
+
+// Namespaces used by aah security features.
+const (
+	NamespaceOAuth2State = "oauth2_state"
+	NamespaceReplay      = "replay"
+	NamespaceVerify      = "verify"
+)
+
+var (
+	// ErrNonceExists returned when nonce already exists in the namespace.
+	ErrNonceExists = errors.New("security/nonce: nonce exists")
+
+	// ErrNonceNotFound returned when nonce does not exist, expired or
+	// already consumed.
+	ErrNonceNotFound = errors.New("security/nonce: nonce not found")
+
+	// ErrCacheNotAvailable returned when configured cache is not available
+	// in the cache manager.
+	ErrCacheNotAvailable = errors.New("security/nonce: cache not available")
+)
+
+// CacheFunc func type returns the cache used by nonce store. It's resolved on
+// each operation, since application creates caches after the initialization.
+type CacheFunc func() cache.Cache
+
+// New method creates the nonce store for given cache func. If it's nil
+// in-memory store is used.
+func New(fn CacheFunc) *Store {
+	s := &Store{cacheFn: fn}
+	if fn == nil {
+		mem := &memoryCache{entries: make(map[string]*memoryEntry)}
+		s.cacheFn = func() cache.Cache { return mem }
+	}
+	return s
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Store
+//___________________________________
+
+// Store is short-lived token store with namespace and TTL semantics.
+//
+// Note: Single-use guarantee of `Consume` and `IsReplay` depends on the
+// atomicity of underlying cache `Put` and `Delete` operations.
+type Store struct {
+	cacheFn CacheFunc
+}
+
+// Put method adds the nonce with value into given namespace for TTL. Returns
+// `nonce.ErrNonceExists` if nonce already exists.
+func (s *Store) Put(namespace, nonce string, value interface{}, ttl time.Duration) error {
+	c := s.cacheFn()
+	if c == nil {
+		return ErrCacheNotAvailable
+	}
+	if value == nil {
+		value = true
+	}
+	if err := c.Put(key(namespace, nonce), value, ttl); err != nil {
+		if err == cache.ErrEntryExists {
+			return ErrNonceExists
+		}
+		return err
+	}
+	return nil
+}
+
+// Issue method generates the secure random nonce of given length, adds it
+// into namespace with value for TTL and returns it.
+func (s *Store) Issue(namespace string, length int, value interface{}, ttl time.Duration) (string, error) {
+	nonce := ess.SecureRandomString(length)
+	if err := s.Put(namespace, nonce, value, ttl); err != nil {
+		return "", err
+	}
+	return nonce, nil
+}
+
+// Get method returns the value of nonce from given namespace.
+func (s *Store) Get(namespace, nonce string) (interface{}, error) {
+	c := s.cacheFn()
+	if c == nil {
+		return nil, ErrCacheNotAvailable
+	}
+	v := c.Get(key(namespace, nonce))
+	if v == nil {
+		return nil, ErrNonceNotFound
+	}
+	return v, nil
+}
+
+// Consume method returns the value of nonce from given namespace and deletes
+// it, so the nonce can be used only once.
+func (s *Store) Consume(namespace, nonce string) (interface{}, error) {
+	v, err := s.Get(namespace, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.cacheFn().Delete(key(namespace, nonce)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Delete method deletes the nonce from given namespace.
+func (s *Store) Delete(namespace, nonce string) error {
+	c := s.cacheFn()
+	if c == nil {
+		return ErrCacheNotAvailable
+	}
+	return c.Delete(key(namespace, nonce))
+}
+
+// Exists method returns true if nonce exists in given namespace and its not
+// expired otherwise false.
+func (s *Store) Exists(namespace, nonce string) bool {
+	c := s.cacheFn()
+	return c != nil && c.Exists(key(namespace, nonce))
+}
+
+// IsReplay method records the given nonce in namespace for TTL and returns
+// true if it was already seen within TTL or store is not available otherwise
+// false. Typically used for replay protection of signed requests, webhooks, etc.
+func (s *Store) IsReplay(namespace, nonce string, ttl time.Duration) bool {
+	return s.Put(namespace, nonce, nil, ttl) != nil
+}
+
+func key(namespace, nonce string) string {
+	return "nonce:" + namespace + ":" + nonce
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// In-memory cache
+//___________________________________
+
+var _ cache.Cache = (*memoryCache)(nil)
+
+type memoryEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+func (m *memoryCache) Name() string {
+	return "nonce_memory"
+}
+
+func (m *memoryCache) Get(k string) interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.entry(k); e != nil {
+		return e.value
+	}
+	return nil
+}
+
+func (m *memoryCache) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.entry(k); e != nil {
+		return e.value, nil
+	}
+	m.entries[k] = &memoryEntry{value: v, expiresAt: time.Now().Add(d)}
+	return v, nil
+}
+
+func (m *memoryCache) Put(k string, v interface{}, d time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeExpired()
+	if e := m.entry(k); e != nil {
+		return cache.ErrEntryExists
+	}
+	m.entries[k] = &memoryEntry{value: v, expiresAt: time.Now().Add(d)}
+	return nil
+}
+
+func (m *memoryCache) Delete(k string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, k)
+	return nil
+}
+
+func (m *memoryCache) Exists(k string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.entry(k) != nil
+}
+
+func (m *memoryCache) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*memoryEntry)
+	return nil
+}
+
+// entry method returns the unexpired entry, caller must hold the lock.
+func (m *memoryCache) entry(k string) *memoryEntry {
+	e, found := m.entries[k]
+	if !found {
+		return nil
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(m.entries, k)
+		return nil
+	}
+	return e
+}
+
+func (m *memoryCache) removeExpired() {
+	now := time.Now()
+	for k, e := range m.entries {
+		if now.After(e.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package nonce
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonceStore(t *testing.T) {
+	s := New(nil)
+
+	n, err := s.Issue(NamespaceVerify, 24, "jeeva@example.com", time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, 24, len(n))
+	assert.True(t, s.Exists(NamespaceVerify, n))
+	assert.False(t, s.Exists(NamespaceOAuth2State, n))
+
+	v, err := s.Get(NamespaceVerify, n)
+	assert.Nil(t, err)
+	assert.Equal(t, "jeeva@example.com", v)
+
+	assert.Equal(t, ErrNonceExists, s.Put(NamespaceVerify, n, "other", time.Minute))
+
+	// single-use
+	v, err = s.Consume(NamespaceVerify, n)
+	assert.Nil(t, err)
+	assert.Equal(t, "jeeva@example.com", v)
+	_, err = s.Consume(NamespaceVerify, n)
+	assert.Equal(t, ErrNonceNotFound, err)
+
+	// replay
+	assert.False(t, s.IsReplay(NamespaceReplay, "request-1", time.Minute))
+	assert.True(t, s.IsReplay(NamespaceReplay, "request-1", time.Minute))
+	assert.Nil(t, s.Delete(NamespaceReplay, "request-1"))
+	assert.False(t, s.IsReplay(NamespaceReplay, "request-1", time.Minute))
+
+	// expiry
+	assert.Nil(t, s.Put(NamespaceReplay, "request-2", nil, 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, s.Exists(NamespaceReplay, "request-2"))
+	_, err = s.Get(NamespaceReplay, "request-2")
+	assert.Equal(t, ErrNonceNotFound, err)
+	assert.False(t, s.IsReplay(NamespaceReplay, "request-2", time.Minute))
+}
+
+func TestNonceStoreCacheNotAvailable(t *testing.T) {
+	s := New(func() cache.Cache { return nil })
+
+	assert.Equal(t, ErrCacheNotAvailable, s.Put(NamespaceReplay, "n1", nil, time.Minute))
+	_, err := s.Issue(NamespaceReplay, 16, nil, time.Minute)
+	assert.Equal(t, ErrCacheNotAvailable, err)
+	_, err = s.Get(NamespaceReplay, "n1")
+	assert.Equal(t, ErrCacheNotAvailable, err)
+	_, err = s.Consume(NamespaceReplay, "n1")
+	assert.Equal(t, ErrCacheNotAvailable, err)
+	assert.Equal(t, ErrCacheNotAvailable, s.Delete(NamespaceReplay, "n1"))
+	assert.False(t, s.Exists(NamespaceReplay, "n1"))
+	assert.True(t, s.IsReplay(NamespaceReplay, "n1", time.Minute))
+}
@@ -142,6 +142,13 @@ func (b *BasicAuth) DoAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *au
 	return authzInfo
 }
 
+// HasCredentials method returns true if request has Basic Authorization header
+// otherwise false.
+func (b *BasicAuth) HasCredentials(r *ahttp.Request) bool {
+	_, _, ok := r.Unwrap().BasicAuth()
+	return ok
+}
+
 // ExtractAuthenticationToken method extracts the authentication token information
 // from the HTTP request.
 func (b *BasicAuth) ExtractAuthenticationToken(r *ahttp.Request) *authc.AuthenticationToken {
@@ -5,6 +5,8 @@
 package scheme
 
 import (
+	"strings"
+
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
 	"aahframe.work/log"
@@ -78,6 +80,16 @@ func (f *FormAuth) DoAuthenticate(authcToken *authc.AuthenticationToken) (*authc
 	return authcInfo, nil
 }
 
+// HasCredentials method returns true if request is login form submission
+// with identity field otherwise false.
+func (f *FormAuth) HasCredentials(r *ahttp.Request) bool {
+	if r.Method != ahttp.MethodPost ||
+		strings.TrimSuffix(r.Path, "/") != strings.TrimSuffix(f.LoginSubmitURL, "/") {
+		return false
+	}
+	return len(r.FormValue(f.FieldIdentity)) > 0
+}
+
 // ExtractAuthenticationToken method extracts the authentication token information
 // from the HTTP request.
 func (f *FormAuth) ExtractAuthenticationToken(r *ahttp.Request) *authc.AuthenticationToken {
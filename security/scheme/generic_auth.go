@@ -6,6 +6,7 @@ package scheme
 
 import (
 	"net/http"
+	"strings"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
@@ -18,6 +19,7 @@ var _ Schemer = (*GenericAuth)(nil)
 type GenericAuth struct {
 	BaseAuth
 	IdentityHeader   string
+	IdentityPrefix   string
 	CredentialHeader string
 }
 
@@ -28,10 +30,26 @@ func (g *GenericAuth) Init(cfg *config.Config, keyName string) error {
 	g.KeyPrefix = "security.auth_schemes." + keyName
 	g.Name, _ = g.AppConfig.String(g.ConfigKey("scheme"))
 	g.IdentityHeader = http.CanonicalHeaderKey(g.AppConfig.StringDefault(g.ConfigKey("header.identity"), "Authorization"))
+	g.IdentityPrefix = g.AppConfig.StringDefault(g.ConfigKey("header.identity_prefix"), "")
 	g.CredentialHeader = g.AppConfig.StringDefault(g.ConfigKey("header.credential"), "")
 	return nil
 }
 
+// HasCredentials method returns true if request has identity header and
+// its value starts with configured `header.identity_prefix` (e.g. "Bearer ")
+// otherwise false.
+//
+// Header `Authorization` is shared by auth schemes, so it's recognized only
+// with `header.identity_prefix`, otherwise generic scheme would claim the
+// credentials of other auth schemes (e.g. Basic) on route auth chain.
+func (g *GenericAuth) HasCredentials(r *ahttp.Request) bool {
+	if g.IdentityHeader == ahttp.HeaderAuthorization && len(g.IdentityPrefix) == 0 {
+		return false
+	}
+	identity := r.Header.Get(g.IdentityHeader)
+	return len(identity) > 0 && strings.HasPrefix(identity, g.IdentityPrefix)
+}
+
 // ExtractAuthenticationToken method extracts the authentication token information
 // from the HTTP request.
 func (g *GenericAuth) ExtractAuthenticationToken(r *ahttp.Request) *authc.AuthenticationToken {
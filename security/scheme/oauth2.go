@@ -19,8 +19,10 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/security/acrypto"
 	"aahframe.work/security/authc"
+	"aahframe.work/security/nonce"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/amazon"
 	"golang.org/x/oauth2/bitbucket"
@@ -52,6 +54,9 @@ import (
 
 var _ Schemer = (*OAuth2)(nil)
 
+// oauth2StateTTL is validity duration of OAuth2 state value.
+const oauth2StateTTL = 10 * time.Minute
+
 // OAuth2 Errors
 var (
 	ErrOAuth2MissingStateOrCode = errors.New("oauth2: callback missing state or code")
@@ -75,6 +80,8 @@ type OAuth2 struct {
 	signSha         string
 	signKey         []byte
 	oauthCfg        *oauth2.Config
+	nonceStore      *nonce.Store
+	clockSkew       time.Duration
 }
 
 // Init method initialize the OAuth2 auth scheme during an application start.
@@ -97,6 +104,11 @@ func (o *OAuth2) Init(appCfg *config.Config, keyName string) error {
 	o.signSha = "sha-256"
 	o.signKey = []byte(o.AppConfig.StringDefault(o.ConfigKey("client.sign_key"), ess.SecureRandomString(32)))
 
+	var err error
+	if o.clockSkew, err = settings.ClockSkew(o.AppConfig); err != nil {
+		return err
+	}
+
 	o.oauthCfg = &oauth2.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
@@ -176,6 +188,12 @@ func (o *OAuth2) ProviderAuthURL(r *ahttp.Request) (string, string) {
 	return state, authURL
 }
 
+// SetNonceStore method sets the security nonce store, it's used to allow
+// the state value only once within its validity.
+func (o *OAuth2) SetNonceStore(s *nonce.Store) {
+	o.nonceStore = s
+}
+
 // ValidateCallback method validates the incoming OAuth2 provider redirect request
 // and gets Access token from OAuth2 provider.
 func (o *OAuth2) ValidateCallback(state string, r *ahttp.Request) (*oauth2.Token, error) {
@@ -198,6 +216,12 @@ func (o *OAuth2) ValidateCallback(state string, r *ahttp.Request) (*oauth2.Token
 	return token, nil
 }
 
+// HasCredentials method returns true if request is OAuth2 login or provider
+// callback otherwise false.
+func (o *OAuth2) HasCredentials(r *ahttp.Request) bool {
+	return r.Path == o.LoginURL || r.Path == o.RedirectURL
+}
+
 // Principal method calls the registered interface `SubjectPrincipalProvider`
 // to obtain Subject principals.
 func (o *OAuth2) Principal(keyName string, v ess.Valuer) ([]*authc.Principal, error) {
@@ -226,10 +250,17 @@ func (o *OAuth2) validateStateKey(state, signedState string) bool {
 		return false
 	}
 
-	// check duration, aah state key is only valid for 10 minutes
+	// check duration, aah state key is only valid for 10 minutes with
+	// clock skew tolerance
 	utcNano, _ := strconv.ParseInt(state[33:], 10, 64)
-	min := time.Now().UTC().Sub(time.Unix(0, utcNano).UTC()).Minutes()
-	return int(min) <= 10
+	elapsed := time.Now().UTC().Sub(time.Unix(0, utcNano).UTC())
+	if elapsed > oauth2StateTTL+o.clockSkew || elapsed < -o.clockSkew {
+		return false
+	}
+
+	// state value is single-use
+	return o.nonceStore == nil ||
+		!o.nonceStore.IsReplay(nonce.NamespaceOAuth2State, signedState, oauth2StateTTL+o.clockSkew+time.Minute)
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -21,6 +21,7 @@ import (
 	"aahframe.work/essentials"
 	"aahframe.work/security/acrypto"
 	"aahframe.work/security/authc"
+	"aahframe.work/security/nonce"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 )
@@ -182,6 +183,12 @@ func TestOAuth2SignAndVerify(t *testing.T) {
 	tstr := fmt.Sprintf("%v", time.Now().UTC().Truncate(time.Minute*20).UnixNano())
 	result = oauth.validateStateKey(state[:33]+":"+tstr, stateSigned)
 	assert.False(t, result)
+
+	// single-use state
+	oauth.SetNonceStore(nonce.New(nil))
+	state, stateSigned = oauth.generateStateKey()
+	assert.True(t, oauth.validateStateKey(state, stateSigned))
+	assert.False(t, oauth.validateStateKey(state, stateSigned))
 }
 
 func TestOAuth2LifeCycle(t *testing.T) {
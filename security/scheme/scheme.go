@@ -45,6 +45,13 @@ type Schemer interface {
 	ExtractAuthenticationToken(r *ahttp.Request) *authc.AuthenticationToken
 }
 
+// CredentialsChecker interface is optional for auth scheme, it reports whether
+// the request carries credentials for the auth scheme. It's used on route
+// auth scheme chaining to find the applicable auth scheme.
+type CredentialsChecker interface {
+	HasCredentials(r *ahttp.Request) bool
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Package methods
 //___________________________________
@@ -64,6 +71,17 @@ func New(authSchemeType string) Schemer {
 	return nil
 }
 
+// HasCredentials method returns true if the request carries credentials for
+// given auth scheme otherwise false. If auth scheme does not implement
+// `CredentialsChecker` then extracted authentication token is checked.
+func HasCredentials(s Schemer, r *ahttp.Request) bool {
+	if c, ok := s.(CredentialsChecker); ok {
+		return c.HasCredentials(r)
+	}
+	token := s.ExtractAuthenticationToken(r)
+	return token != nil && (len(token.Identity) > 0 || len(token.Credential) > 0)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Unexported methods
 //___________________________________
@@ -20,8 +20,10 @@ import (
 	"aahframe.work/security/acrypto"
 	"aahframe.work/security/anticsrf"
 	"aahframe.work/security/authc"
+	"aahframe.work/security/nonce"
 	"aahframe.work/security/scheme"
 	"aahframe.work/security/session"
+	"aahframe.work/security/verify"
 )
 
 var (
@@ -79,8 +81,10 @@ type (
 	Manager struct {
 		IsSSLEnabled   bool
 		SessionManager *session.Manager
+		NonceStore     *nonce.Store
 		SecureHeaders  *SecureHeaders
 		AntiCSRF       *anticsrf.AntiCSRF
+		Verifier       *verify.Manager
 		appCfg         *config.Config
 		authSchemes    map[string]scheme.Schemer
 	}
@@ -116,6 +120,11 @@ func (m *Manager) Init(appCfg *config.Config) error {
 	Scrypt = acrypto.PasswordAlgorithm("scrypt")
 	Pbkdf2 = acrypto.PasswordAlgorithm("pbkdf2")
 
+	// Initialize nonce store, in-memory if it's not set
+	if m.NonceStore == nil {
+		m.NonceStore = nonce.New(nil)
+	}
+
 	// Initialize Anti-CSRF
 	if m.AntiCSRF, err = anticsrf.New(m.appCfg); err != nil {
 		return err
@@ -142,6 +151,17 @@ func (m *Manager) Init(appCfg *config.Config) error {
 		if err = authScheme.Init(m.appCfg, keyAuthScheme); err != nil {
 			return err
 		}
+		if oauth, ok := authScheme.(*scheme.OAuth2); ok {
+			oauth.SetNonceStore(m.NonceStore)
+		}
+	}
+
+	// Initialize password reset and email verification token flows
+	if m.Verifier, err = verify.New(m.appCfg); err != nil {
+		return err
+	}
+	if m.Verifier != nil {
+		m.Verifier.SetNonceStore(m.NonceStore)
 	}
 
 	// Initialize session manager
@@ -284,4 +304,5 @@ func init() {
 	gob.Register(&authc.AuthenticationInfo{})
 	gob.Register(&authc.Principal{})
 	gob.Register(make([]authc.Principal, 0))
+	gob.Register(verify.Token{})
 }
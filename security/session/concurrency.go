@@ -0,0 +1,238 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+)
+
+// Concurrent session control strategies, used when principal reaches
+// `security.session.concurrency.max_sessions`.
+const (
+	StrategyEvictOldest = "evict_oldest"
+	StrategyDenyNew     = "deny_new"
+)
+
+// ErrSessionLimitExceeded returned when principal reached the maximum allowed
+// concurrent sessions and strategy is `deny_new`.
+var ErrSessionLimitExceeded = errors.New("security/session: concurrent session limit exceeded")
+
+// tracker holds the principal sessions for the process lifetime, so tracking
+// survives the session manager re-initialization on config hot-reload.
+var tracker = &sessionTracker{sessions: make(map[string][]*ConcurrentSession)}
+
+// ConcurrentSession holds the active session details of principal.
+type ConcurrentSession struct {
+	ID          string
+	Principal   string
+	CreatedTime time.Time
+}
+
+type concurrencyControl struct {
+	*sessionTracker
+	maxSessions int
+	strategy    string
+}
+
+type sessionTracker struct {
+	sync.Mutex
+	sessions map[string][]*ConcurrentSession
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Manager concurrency methods
+//___________________________________
+
+// IsConcurrencyControlEnabled method returns true if maximum concurrent
+// sessions per principal is configured otherwise false.
+//
+// Note: Sessions are tracked per application instance, limit is not enforced
+// across multiple instances sharing the same session store.
+func (m *Manager) IsConcurrencyControlEnabled() bool {
+	return m.concurrency != nil
+}
+
+// RegisterSession method tracks the given session for principal. If principal
+// reached the maximum sessions, as per strategy either oldest sessions are
+// evicted from store or `session.ErrSessionLimitExceeded` is returned.
+func (m *Manager) RegisterSession(principal string, s *Session) error {
+	if !m.IsConcurrencyControlEnabled() {
+		return nil
+	}
+
+	cc := m.concurrency
+	cc.Lock()
+	defer cc.Unlock()
+
+	active := m.activeSessions(principal, s.ID)
+	if len(active) >= cc.maxSessions {
+		if cc.strategy == StrategyDenyNew {
+			cc.sessions[principal] = active
+			return ErrSessionLimitExceeded
+		}
+
+		evict := len(active) - cc.maxSessions + 1
+		for _, es := range active[:evict] {
+			log.Infof("Evicting session '%s' of principal '%s', maximum sessions reached", es.ID, principal)
+			if err := m.store.Delete(es.ID); err != nil {
+				log.Error(err)
+			}
+		}
+		active = active[evict:]
+	}
+
+	createdTime := time.Now()
+	if s.CreatedTime != nil {
+		createdTime = *s.CreatedTime
+	}
+	cc.sessions[principal] = append(active, &ConcurrentSession{
+		ID:          s.ID,
+		Principal:   principal,
+		CreatedTime: createdTime,
+	})
+	return nil
+}
+
+// Sessions method returns the active sessions of given principal ordered by
+// created time.
+func (m *Manager) Sessions(principal string) []*ConcurrentSession {
+	if !m.IsConcurrencyControlEnabled() {
+		return nil
+	}
+
+	cc := m.concurrency
+	cc.Lock()
+	defer cc.Unlock()
+	active := m.activeSessions(principal, "")
+	cc.sessions[principal] = active
+
+	result := make([]*ConcurrentSession, len(active))
+	copy(result, active)
+	return result
+}
+
+// RevokeSession method deletes the given session ID of principal from store.
+// Subsequent request of that session is treated as new session.
+func (m *Manager) RevokeSession(principal, id string) error {
+	if !m.IsConcurrencyControlEnabled() {
+		return nil
+	}
+
+	cc := m.concurrency
+	cc.Lock()
+	defer cc.Unlock()
+	for idx, s := range cc.sessions[principal] {
+		if s.ID == id {
+			cc.sessions[principal] = append(cc.sessions[principal][:idx], cc.sessions[principal][idx+1:]...)
+			return m.store.Delete(id)
+		}
+	}
+	return nil
+}
+
+// RevokeSessions method deletes all the sessions of given principal from store.
+func (m *Manager) RevokeSessions(principal string) error {
+	if !m.IsConcurrencyControlEnabled() {
+		return nil
+	}
+
+	cc := m.concurrency
+	cc.Lock()
+	defer cc.Unlock()
+	var err error
+	for _, s := range cc.sessions[principal] {
+		if er := m.store.Delete(s.ID); er != nil {
+			err = er
+		}
+	}
+	delete(cc.sessions, principal)
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func newConcurrencyControl(cfg *config.Config, keyPrefix string, isCookieStore bool) (*concurrencyControl, error) {
+	maxSessions := cfg.IntDefault(keyPrefix+".max_sessions", 0)
+	if maxSessions <= 0 {
+		return nil, nil
+	}
+
+	if isCookieStore {
+		return nil, fmt.Errorf("session: '%s' requires server side session store", keyPrefix)
+	}
+
+	strategy := cfg.StringDefault(keyPrefix+".strategy", StrategyEvictOldest)
+	if strategy != StrategyEvictOldest && strategy != StrategyDenyNew {
+		return nil, fmt.Errorf("session: unsupported value '%s' for '%s.strategy'", strategy, keyPrefix)
+	}
+
+	return &concurrencyControl{
+		sessionTracker: tracker,
+		maxSessions:    maxSessions,
+		strategy:       strategy,
+	}, nil
+}
+
+// activeSessions method returns the principal sessions which still exists
+// in the store, sorted by created time. Caller must hold the lock.
+func (m *Manager) activeSessions(principal, skipID string) []*ConcurrentSession {
+	var active []*ConcurrentSession
+	for _, s := range m.concurrency.sessions[principal] {
+		if s.ID != skipID && m.store.IsExists(s.ID) {
+			active = append(active, s)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreatedTime.Before(active[j].CreatedTime)
+	})
+	return active
+}
+
+// unregisterSession method removes the session ID from concurrency tracking.
+func (m *Manager) unregisterSession(id string) {
+	if !m.IsConcurrencyControlEnabled() {
+		return
+	}
+
+	cc := m.concurrency
+	cc.Lock()
+	defer cc.Unlock()
+	for principal, sessions := range cc.sessions {
+		for idx, s := range sessions {
+			if s.ID == id {
+				cc.sessions[principal] = append(sessions[:idx], sessions[idx+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// renameSession method updates the session ID in concurrency tracking.
+func (m *Manager) renameSession(oldID, newID string) {
+	if !m.IsConcurrencyControlEnabled() {
+		return
+	}
+
+	cc := m.concurrency
+	cc.Lock()
+	defer cc.Unlock()
+	for _, sessions := range cc.sessions {
+		for _, s := range sessions {
+			if s.ID == oldID {
+				s.ID = newID
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionConcurrencyEvictOldest(t *testing.T) {
+	defer ess.DeleteFiles(filepath.Join(getTestdataPath(), "session"))
+
+	m := createTestManager(t, concurrencyTestConfig("evict_oldest"))
+	assert.True(t, m.IsConcurrencyControlEnabled())
+
+	s1 := createSavedSession(t, m)
+	s2 := createSavedSession(t, m)
+	assert.Nil(t, m.RegisterSession("jeeva", s1))
+	assert.Nil(t, m.RegisterSession("jeeva", s2))
+	assert.Equal(t, 2, len(m.Sessions("jeeva")))
+
+	s3 := createSavedSession(t, m)
+	assert.Nil(t, m.RegisterSession("jeeva", s3))
+
+	sessions := m.Sessions("jeeva")
+	assert.Equal(t, 2, len(sessions))
+	assert.Equal(t, s2.ID, sessions[0].ID)
+	assert.Equal(t, s3.ID, sessions[1].ID)
+	assert.False(t, m.store.IsExists(s1.ID))
+
+	// revoke
+	assert.Nil(t, m.RevokeSession("jeeva", s2.ID))
+	assert.False(t, m.store.IsExists(s2.ID))
+	assert.Equal(t, 1, len(m.Sessions("jeeva")))
+
+	assert.Nil(t, m.RevokeSessions("jeeva"))
+	assert.False(t, m.store.IsExists(s3.ID))
+	assert.Equal(t, 0, len(m.Sessions("jeeva")))
+}
+
+func TestSessionConcurrencyDenyNew(t *testing.T) {
+	defer ess.DeleteFiles(filepath.Join(getTestdataPath(), "session"))
+
+	m := createTestManager(t, concurrencyTestConfig("deny_new"))
+
+	s1 := createSavedSession(t, m)
+	s2 := createSavedSession(t, m)
+	assert.Nil(t, m.RegisterSession("jeeva", s1))
+	assert.Nil(t, m.RegisterSession("jeeva", s2))
+
+	s3 := createSavedSession(t, m)
+	assert.Equal(t, ErrSessionLimitExceeded, m.RegisterSession("jeeva", s3))
+	assert.Nil(t, m.RegisterSession("other", s3))
+
+	// logout frees up the slot
+	w := httptest.NewRecorder()
+	assert.Nil(t, m.DeleteSession(w, s1))
+	s4 := createSavedSession(t, m)
+	assert.Nil(t, m.RegisterSession("jeeva", s4))
+	assert.Equal(t, 2, len(m.Sessions("jeeva")))
+}
+
+func TestSessionConcurrencyAcrossReinit(t *testing.T) {
+	defer ess.DeleteFiles(filepath.Join(getTestdataPath(), "session"))
+
+	m := createTestManager(t, concurrencyTestConfig("deny_new"))
+	s1 := createSavedSession(t, m)
+	s2 := createSavedSession(t, m)
+	assert.Nil(t, m.RegisterSession("reinit", s1))
+	assert.Nil(t, m.RegisterSession("reinit", s2))
+
+	// session manager re-initialized on config hot-reload
+	m2 := createTestManager(t, concurrencyTestConfig("deny_new"))
+	assert.Equal(t, 2, len(m2.Sessions("reinit")))
+	assert.Equal(t, ErrSessionLimitExceeded, m2.RegisterSession("reinit", createSavedSession(t, m2)))
+	assert.Nil(t, m2.RevokeSessions("reinit"))
+}
+
+func TestSessionConcurrencyConfigErrors(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	security {
+	  session {
+	    concurrency {
+	      max_sessions = 2
+	    }
+	  }
+	}
+	`)
+	_, err := NewManager(cfg)
+	assert.Equal(t, "session: 'security.session.concurrency' requires server side session store", err.Error())
+
+	cfg, _ = config.ParseString(concurrencyTestConfig("reject"))
+	_, err = NewManager(cfg)
+	assert.Equal(t, "session: unsupported value 'reject' for 'security.session.concurrency.strategy'", err.Error())
+
+	m := createTestManager(t, `security { }`)
+	assert.False(t, m.IsConcurrencyControlEnabled())
+	assert.Nil(t, m.RegisterSession("jeeva", m.NewSession()))
+	assert.Nil(t, m.Sessions("jeeva"))
+}
+
+func TestSessionRegenerateID(t *testing.T) {
+	defer ess.DeleteFiles(filepath.Join(getTestdataPath(), "session"))
+
+	m := createTestManager(t, concurrencyTestConfig("evict_oldest"))
+	assert.True(t, m.IsRegenerateIDEnabled())
+
+	s := createSavedSession(t, m)
+	assert.Nil(t, m.RegisterSession("jeeva", s))
+
+	oldID := s.ID
+	assert.Nil(t, s.RegenerateID())
+	assert.NotEqual(t, oldID, s.ID)
+	assert.Equal(t, 32, len(s.ID))
+	assert.Equal(t, "jeeva", s.GetString("username"))
+
+	// old ID is invalidated, data moved to new ID
+	assert.False(t, m.store.IsExists(oldID))
+	assert.True(t, m.store.IsExists(s.ID))
+	rs, err := m.DecodeToSession(m.store.Read(s.ID))
+	assert.Nil(t, err)
+	assert.Equal(t, s.ID, rs.ID)
+	assert.Equal(t, "jeeva", rs.GetString("username"))
+
+	sessions := m.Sessions("jeeva")
+	assert.Equal(t, 1, len(sessions))
+	assert.Equal(t, s.ID, sessions[0].ID)
+
+	// cookie store
+	cm := createTestManager(t, `security { session { regenerate_id = false; } }`)
+	assert.False(t, cm.IsRegenerateIDEnabled())
+	cs := cm.NewSession()
+	oldID = cs.ID
+	assert.Nil(t, cs.RegenerateID())
+	assert.NotEqual(t, oldID, cs.ID)
+}
+
+func createSavedSession(t *testing.T, m *Manager) *Session {
+	s := m.NewSession()
+	ct := time.Now()
+	s.CreatedTime = &ct
+	s.Set("username", "jeeva")
+	assert.Nil(t, m.SaveSession(httptest.NewRecorder(), s))
+	time.Sleep(time.Millisecond)
+	return s
+}
+
+func concurrencyTestConfig(strategy string) string {
+	return `
+	security {
+	  session {
+	    mode = "stateful"
+	    store {
+	      type = "file"
+	      filepath = "testdata/session"
+	    }
+
+	    concurrency {
+	      max_sessions = 2
+	      strategy = "` + strategy + `"
+	    }
+	  }
+	}
+	`
+}
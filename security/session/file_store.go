@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"aahframe.work/config"
@@ -19,6 +20,7 @@ import (
 
 // Storer interface comply
 var _ Storer = (*FileStore)(nil)
+var _ IDRegenerator = (*FileStore)(nil)
 
 // FileStore is the aah framework session store implementation.
 type FileStore struct {
@@ -81,6 +83,20 @@ func (f *FileStore) Save(id, value string) error {
 	return ioutil.WriteFile(sessionFile, []byte(value), 0600)
 }
 
+// Regenerate method writes the session file of new id and removes the session
+// file of old id, under the same lock.
+func (f *FileStore) Regenerate(oldID, newID, value string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+	if err := ioutil.WriteFile(filepath.Join(f.path, f.filePrefix+"_"+newID), []byte(value), 0600); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(f.path, f.filePrefix+"_"+oldID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 // Delete method deletes the session file for given id.
 func (f *FileStore) Delete(id string) error {
 	f.m.Lock()
@@ -111,12 +127,14 @@ func (f *FileStore) Cleanup(m *Manager) {
 		if sdata, err := ioutil.ReadFile(sfile); err == nil {
 			if _, err := m.DecodeToSession(string(sdata)); err == cookie.ErrCookieTimestampIsExpired {
 				f.m.Lock()
-				if err := os.Remove(sfile); !os.IsNotExist(err) {
+				err := os.Remove(sfile)
+				f.m.Unlock()
+				if err != nil && !os.IsNotExist(err) {
 					log.Error(err)
 				} else {
 					cnt++
+					m.Expired(strings.TrimPrefix(filepath.Base(sfile), f.filePrefix+"_"), string(sdata))
 				}
-				f.m.Unlock()
 			}
 		}
 	}
@@ -138,3 +138,43 @@ func TestSessionFileStoreDeleteAndCleanup(t *testing.T) {
 	assert.Equal(t, 0, len(files))
 	assert.False(t, m.store.IsExists(sid))
 }
+
+func TestSessionExpiredCallback(t *testing.T) {
+	sessionDir := filepath.Join(getTestdataPath(), "session-expired")
+	defer ess.DeleteFiles(sessionDir)
+
+	m := createTestManager(t, `
+	security {
+	  session {
+	    store {
+	      type = "file"
+	      filepath = "testdata/session-expired"
+	    }
+	    ttl = "30m"
+	    cleanup_interval = "0m"
+	    sign_key = "eFWLXEewECptbDVXExokRTLONWxrTjfV"
+	  }
+	}
+  `)
+	assert.Nil(t, m.stopCleanup)
+	m.Close()
+
+	var expired []*Session
+	m.OnExpired(func(s *Session) {
+		expired = append(expired, s)
+	})
+
+	s := m.NewSession()
+	s.Set("user", "jeeva")
+	encodedStr, err := m.Encode(s)
+	assert.Nil(t, err)
+
+	m.Expired(s.ID, encodedStr)
+	assert.Equal(t, 1, len(expired))
+	assert.Equal(t, s.ID, expired[0].ID)
+	assert.Equal(t, "jeeva", expired[0].GetString("user"))
+
+	// invalid data is not notified
+	m.Expired("invalid", "invalid-data")
+	assert.Equal(t, 1, len(expired))
+}
@@ -34,6 +34,7 @@ import (
 
 	"aahframe.work/config"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/log"
 	"aahframe.work/security/cookie"
 )
@@ -56,6 +57,13 @@ type Storer interface {
 	Cleanup(m *Manager)
 }
 
+// IDRegenerator interface is implemented by the session store which supports
+// moving the session data from old ID to new ID atomically. Otherwise session
+// manager saves the new ID and then deletes the old ID.
+type IDRegenerator interface {
+	Regenerate(oldID, newID, value string) error
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Package methods
 //___________________________________
@@ -99,6 +107,13 @@ func NewManager(appCfg *config.Config) (*Manager, error) {
 	}
 
 	m.idLength = m.cfg.IntDefault(keyPrefix+".id_length", 32)
+	m.regenerateIDEnabled = m.cfg.BoolDefault(keyPrefix+".regenerate_id", true)
+
+	// Serializer for typed values
+	m.serializerName = m.cfg.StringDefault(keyPrefix+".serializer", "gob")
+	if _, found := registerSerializers[m.serializerName]; !found {
+		return nil, fmt.Errorf("session: serializer name '%v' not exists", m.serializerName)
+	}
 
 	// Cookie Options
 	opts := &cookie.Options{
@@ -110,6 +125,10 @@ func NewManager(appCfg *config.Config) (*Manager, error) {
 		Secure: m.cfg.BoolDefault("server.ssl.enable", false),
 	}
 
+	if opts.ClockSkew, err = settings.ClockSkew(m.cfg); err != nil {
+		return nil, err
+	}
+
 	// TTL value
 	if opts.MaxAge, err = toSeconds(m.cfg.StringDefault(keyPrefix+".ttl", "0m")); err != nil {
 		return nil, err
@@ -127,16 +146,15 @@ func NewManager(appCfg *config.Config) (*Manager, error) {
 		return nil, err
 	}
 
-	// Schedule cleanup
-	if !m.IsCookieStore() {
-		go func(sm *Manager) {
-			ticker := time.NewTicker(time.Duration(sm.cleanupInterval) * time.Second)
-			for {
-				<-ticker.C
-				log.Infof("Running expired session cleanup at %v", time.Now())
-				sm.store.Cleanup(sm)
-			}
-		}(m)
+	// Concurrent sessions per principal
+	if m.concurrency, err = newConcurrencyControl(m.cfg, keyPrefix+".concurrency", m.IsCookieStore()); err != nil {
+		return nil, err
+	}
+
+	// Schedule cleanup, interval `0m` disables it
+	if !m.IsCookieStore() && m.cleanupInterval > 0 {
+		m.stopCleanup = make(chan struct{})
+		go m.runCleanup(m.stopCleanup)
 	}
 
 	return m, nil
@@ -148,15 +166,25 @@ func NewManager(appCfg *config.Config) (*Manager, error) {
 
 // Manager is a session manager to manage sessions.
 type Manager struct {
-	idLength        int
-	cleanupInterval int64
-	mode            string
-	storeName       string
-	store           Storer
-	cfg             *config.Config
-	cookieMgr       *cookie.Manager
+	idLength            int
+	regenerateIDEnabled bool
+	cleanupInterval     int64
+	mode                string
+	storeName           string
+	store               Storer
+	cfg                 *config.Config
+	cookieMgr           *cookie.Manager
+	concurrency         *concurrencyControl
+	serializerName      string
+	schemas             map[string]*valueSchema
+	stopCleanup         chan struct{}
+	onExpired           []ExpiredFunc
 }
 
+// ExpiredFunc func type is invoked when the expired session is cleaned up
+// from the session store.
+type ExpiredFunc func(s *Session)
+
 // NewSession method creates a new session for the request.
 func (m *Manager) NewSession() *Session {
 	s := sessionPool.Get().(*Session)
@@ -164,6 +192,7 @@ func (m *Manager) NewSession() *Session {
 	s.IsNew = true
 	t := time.Now()
 	s.CreatedTime = &t
+	s.mgr = m
 	return s
 }
 
@@ -198,15 +227,46 @@ func (m *Manager) GetSession(r *http.Request) *Session {
 			if id, err := m.DecodeToString(scookie.Value); err == nil {
 				log.Debugf("Cleaning expried session: %s", id)
 				_ = m.store.Delete(id)
+				m.Expired(id, encodedStr)
 			}
 		}
 		return nil
 	}
 
 	session.IsNew = false
+	session.mgr = m
 	return session
 }
 
+// AddSchema method registers the current schema version of the session value
+// for given key and migration func from previous version `version-1`. Values
+// stored with older version are migrated on `Session.GetInto` step by step
+// e.g. 1 => 2 => 3.
+//
+//	sessionMgr.AddSchema("cart", 2, func(from int, data []byte, s session.Serializer) ([]byte, error) {
+//		var old models.CartV1
+//		if err := s.Unmarshal(data, &old); err != nil {
+//			return nil, err
+//		}
+//		return s.Marshal(models.CartFromV1(old))
+//	})
+func (m *Manager) AddSchema(key string, version int, fn MigrateFunc) {
+	if m.schemas == nil {
+		m.schemas = make(map[string]*valueSchema)
+	}
+	vs, found := m.schemas[key]
+	if !found {
+		vs = &valueSchema{migrates: make(map[int]MigrateFunc)}
+		m.schemas[key] = vs
+	}
+	if version > vs.version {
+		vs.version = version
+	}
+	if fn != nil {
+		vs.migrates[version-1] = fn
+	}
+}
+
 // SaveSession method saves the given session into store.
 // Add writes the cookie into response.
 func (m *Manager) SaveSession(w http.ResponseWriter, s *Session) error {
@@ -252,6 +312,7 @@ func (m *Manager) DeleteSession(w http.ResponseWriter, s *Session) error {
 			// store delete had an error, log it and go forward to clean the cookie
 			log.Error(err)
 		}
+		m.unregisterSession(s.ID)
 	}
 
 	opts := *m.cookieMgr.Options
@@ -314,11 +375,60 @@ func (m *Manager) IsCookieStore() bool {
 	return m.storeName == "cookie"
 }
 
+// IsRegenerateIDEnabled method returns true if session ID regeneration on
+// authentication and privilege change is enabled otherwise false.
+// Config `security.session.regenerate_id`, default value is `true`.
+func (m *Manager) IsRegenerateIDEnabled() bool {
+	return m.regenerateIDEnabled
+}
+
 // IsPath method returns true if session cookie config 'path' is prefix of request path.
 func (m *Manager) IsPath(p string) bool {
 	return strings.HasPrefix(p, m.cookieMgr.Options.Path)
 }
 
+// OnExpired method adds the callback func which is invoked for each expired
+// session cleaned up from the session store either by background cleanup
+// `security.session.cleanup_interval` or on request.
+func (m *Manager) OnExpired(fn ExpiredFunc) {
+	m.onExpired = append(m.onExpired, fn)
+}
+
+// Expired method is called by the session store after it deletes the expired
+// session data of given session ID. Session data is decoded without the
+// timestamp expiry validation and passed to `OnExpired` callbacks.
+func (m *Manager) Expired(id, encodedStr string) {
+	m.unregisterSession(id)
+	if len(m.onExpired) == 0 {
+		return
+	}
+
+	b, err := m.cookieMgr.DecodeExpired(encodedStr)
+	if err != nil {
+		log.Errorf("session: unable to decode expired session '%s': %v", id, err)
+		return
+	}
+
+	var s Session
+	if err = decodeGob(&s, b); err != nil {
+		log.Errorf("session: unable to decode expired session '%s': %v", id, err)
+		return
+	}
+	s.mgr = m
+
+	for _, fn := range m.onExpired {
+		fn(&s)
+	}
+}
+
+// Close method stops the background expired session cleanup.
+func (m *Manager) Close() {
+	if m.stopCleanup != nil {
+		close(m.stopCleanup)
+		m.stopCleanup = nil
+	}
+}
+
 // ReleaseSession method puts session object back to pool.
 func ReleaseSession(s *Session) {
 	if s != nil {
@@ -326,3 +436,50 @@ func ReleaseSession(s *Session) {
 		sessionPool.Put(s)
 	}
 }
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// regenerateID method assigns new ID to the given session. For server side
+// session store, session data is saved with new ID and old ID is deleted from
+// the store, concurrency tracking is updated accordingly.
+func (m *Manager) regenerateID(s *Session) error {
+	oldID := s.ID
+	s.ID = ess.SecureRandomString(m.idLength)
+	if m.IsCookieStore() {
+		return nil
+	}
+
+	encoded, err := m.Encode(s)
+	if err != nil {
+		s.ID = oldID
+		return err
+	}
+	if r, ok := m.store.(IDRegenerator); ok {
+		err = r.Regenerate(oldID, s.ID, encoded)
+	} else if err = m.store.Save(s.ID, encoded); err == nil {
+		err = m.store.Delete(oldID)
+	}
+	if err != nil {
+		s.ID = oldID
+		return err
+	}
+
+	m.renameSession(oldID, s.ID)
+	return nil
+}
+
+func (m *Manager) runCleanup(stop chan struct{}) {
+	ticker := time.NewTicker(time.Duration(m.cleanupInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			log.Infof("Running expired session cleanup at %v", time.Now())
+			m.store.Cleanup(m)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"aahframe.work/config"
 	"aahframe.work/essentials"
@@ -120,6 +121,21 @@ func TestSessionStoreNotExists(t *testing.T) {
 	assert.Equal(t, "session: store name 'custom' not exists", err.Error())
 }
 
+func TestSessionClockSkew(t *testing.T) {
+	m := createTestManager(t, `security { clock_skew = "30s"; }`)
+	assert.Equal(t, 30*time.Second, m.cookieMgr.Options.ClockSkew)
+
+	for cfgStr, errStr := range map[string]string{
+		`security { clock_skew = "1h"; }`:  "'security.clock_skew' value must be less than 1h",
+		`security { clock_skew = "-5s"; }`: "'security.clock_skew' value must not be negative",
+	} {
+		cfg, _ := config.ParseString(cfgStr)
+		m, err := NewManager(cfg)
+		assert.Nil(t, m)
+		assert.Equal(t, errStr, err.Error())
+	}
+}
+
 func TestSessionManagerMisc(t *testing.T) {
 	m := createTestManager(t, `
 	security {
@@ -7,6 +7,8 @@ package session
 import (
 	"fmt"
 	"time"
+
+	"aahframe.work/essentials"
 )
 
 const flashKeyPrefix = "_flash_"
@@ -38,6 +40,7 @@ type Session struct {
 	CreatedTime *time.Time
 
 	maxAge int
+	mgr    *Manager
 }
 
 // Get method returns the value for given key otherwise nil.
@@ -142,6 +145,22 @@ func (s *Session) GetFloat64(key string) float64 {
 	return 0
 }
 
+// RegenerateID method assigns a new session ID to the session, values are
+// retained. For server side session store, session data is moved to the new
+// ID and old ID is invalidated in the store at once. The new ID is written
+// into the session cookie at the end of the request.
+//
+// It closes the session fixation gap, aah calls it on successful
+// authentication and impersonation if `security.session.regenerate_id`
+// is enabled.
+func (s *Session) RegenerateID() error {
+	if s.mgr == nil {
+		s.ID = ess.SecureRandomString(len(s.ID))
+		return nil
+	}
+	return s.mgr.regenerateID(s)
+}
+
 // String method is stringer interface implementation.
 func (s Session) String() string {
 	return fmt.Sprintf("session(id:%s createdat:%s isnew:%v isauthenticated:%v values:%v)",
@@ -160,4 +179,5 @@ func (s *Session) Reset() {
 	s.CreatedTime = nil
 	s.IsAuthenticated = false
 	s.maxAge = 0
+	s.mgr = nil
 }
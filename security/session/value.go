@@ -0,0 +1,211 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrSessionKeyNotExists returned when given key does not exist in the
+	// session object.
+	ErrSessionKeyNotExists = errors.New("security/session: key not exists")
+
+	// ErrSessionSerializerIsNil returned when suppiled serializer is nil.
+	ErrSessionSerializerIsNil = errors.New("security/session: serializer value is nil")
+
+	registerSerializers = map[string]Serializer{
+		"gob":  &gobSerializer{},
+		"json": &jsonSerializer{},
+	}
+)
+
+func init() {
+	gob.Register(EncodedValue{})
+}
+
+// Serializer is interface for implementing pluggable session value
+// serialization used by `Session.SetFrom` and `Session.GetInto`. aah provides
+// `gob` and `json`, any other (e.g. msgpack) can be added via `AddSerializer`.
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// MigrateFunc func type is used to migrate the serialized session value from
+// the given schema version to the next version.
+type MigrateFunc func(fromVersion int, data []byte, s Serializer) ([]byte, error)
+
+// EncodedValue holds the serialized session value along with serializer name
+// and schema version.
+type EncodedValue struct {
+	Serializer string
+	Version    int
+	Data       []byte
+}
+
+// AddSerializer method allows you to add user created session value
+// serializer for aah framework application.
+func AddSerializer(name string, s Serializer) error {
+	if s == nil {
+		return ErrSessionSerializerIsNil
+	}
+
+	if _, found := registerSerializers[name]; found {
+		return fmt.Errorf("session: serializer name '%v' is already added, skip it", name)
+	}
+
+	registerSerializers[name] = s
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Session typed value methods
+//___________________________________
+
+// SetFrom method serializes the given value using configured serializer
+// `security.session.serializer` and sets it for the given key along with
+// current schema version of the key.
+//
+//	err := ctx.Session().SetFrom("cart", cart)
+func (s *Session) SetFrom(key string, value interface{}) error {
+	name, serializer, err := s.serializer("")
+	if err != nil {
+		return err
+	}
+
+	b, err := serializer.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("session: unable to serialize key '%s': %v", key, err)
+	}
+
+	s.Set(key, EncodedValue{Serializer: name, Version: s.mgr.schemaVersion(key), Data: b})
+	return nil
+}
+
+// GetInto method deserializes the session value of given key into `dst`.
+// The value is migrated to current schema version of the key if its stored
+// with older version, see `Manager.AddSchema`. Value set via `Session.Set`
+// is assigned as-is if its type is assignable to `dst`.
+//
+//	var cart models.Cart
+//	if err := ctx.Session().GetInto("cart", &cart); err != nil {
+//		// handle error
+//	}
+func (s *Session) GetInto(key string, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("session: destination for key '%s' must be a non-nil pointer", key)
+	}
+
+	v, found := s.Values[key]
+	if !found {
+		return ErrSessionKeyNotExists
+	}
+
+	ev, ok := v.(EncodedValue)
+	if !ok {
+		vv := reflect.ValueOf(v)
+		if !vv.IsValid() || !vv.Type().AssignableTo(rv.Elem().Type()) {
+			return fmt.Errorf("session: value of key '%s' is not assignable to %T", key, dst)
+		}
+		rv.Elem().Set(vv)
+		return nil
+	}
+
+	_, serializer, err := s.serializer(ev.Serializer)
+	if err != nil {
+		return err
+	}
+
+	if version := s.mgr.schemaVersion(key); ev.Version < version {
+		if ev.Data, err = s.mgr.migrate(key, ev.Version, ev.Data, serializer); err != nil {
+			return err
+		}
+		ev.Version = version
+		s.Set(key, ev)
+	}
+
+	if err = serializer.Unmarshal(ev.Data, dst); err != nil {
+		return fmt.Errorf("session: unable to deserialize key '%s': %v", key, err)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (s *Session) serializer(name string) (string, Serializer, error) {
+	if len(name) == 0 {
+		name = "gob"
+		if s.mgr != nil {
+			name = s.mgr.serializerName
+		}
+	}
+	serializer, found := registerSerializers[name]
+	if !found {
+		return name, nil, fmt.Errorf("session: serializer name '%v' not exists", name)
+	}
+	return name, serializer, nil
+}
+
+type valueSchema struct {
+	version  int
+	migrates map[int]MigrateFunc
+}
+
+func (m *Manager) schemaVersion(key string) int {
+	if m == nil {
+		return 0
+	}
+	if vs, found := m.schemas[key]; found {
+		return vs.version
+	}
+	return 0
+}
+
+func (m *Manager) migrate(key string, from int, data []byte, s Serializer) ([]byte, error) {
+	vs := m.schemas[key]
+	var err error
+	for v := from; v < vs.version; v++ {
+		fn, found := vs.migrates[v]
+		if !found {
+			return nil, fmt.Errorf("session: no migration for key '%s' from schema version %d", key, v)
+		}
+		if data, err = fn(v, data, s); err != nil {
+			return nil, fmt.Errorf("session: unable to migrate key '%s' from schema version %d: %v", key, v, err)
+		}
+	}
+	return data, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Serializers
+//___________________________________
+
+type gobSerializer struct{}
+
+func (gobSerializer) Marshal(v interface{}) ([]byte, error) {
+	return encodeGob(v)
+}
+
+func (gobSerializer) Unmarshal(data []byte, v interface{}) error {
+	return decodeGob(v, data)
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package session
+
+import (
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCart struct {
+	Items []string
+	Total int
+}
+
+type testCartV1 struct {
+	Item string
+}
+
+func TestSessionTypedValues(t *testing.T) {
+	m := createTestManager(t, `
+		security {
+			session {
+				serializer = "json"
+			}
+		}
+	`)
+
+	s := m.NewSession()
+	err := s.SetFrom("cart", testCart{Items: []string{"book"}, Total: 25})
+	assert.Nil(t, err)
+
+	ev := s.Get("cart").(EncodedValue)
+	assert.Equal(t, "json", ev.Serializer)
+	assert.Equal(t, 0, ev.Version)
+	assert.Equal(t, `{"Items":["book"],"Total":25}`, string(ev.Data))
+
+	var cart testCart
+	err = s.GetInto("cart", &cart)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"book"}, cart.Items)
+	assert.Equal(t, 25, cart.Total)
+
+	// survives session encode and decode
+	encodedStr, err := m.Encode(s)
+	assert.Nil(t, err)
+	rs, err := m.DecodeToSession(encodedStr)
+	assert.Nil(t, err)
+	var rcart testCart
+	assert.Nil(t, rs.GetInto("cart", &rcart))
+	assert.Equal(t, cart, rcart)
+
+	// plain value
+	s.Set("count", 5)
+	var count int
+	assert.Nil(t, s.GetInto("count", &count))
+	assert.Equal(t, 5, count)
+	var name string
+	err = s.GetInto("count", &name)
+	assert.Equal(t, "session: value of key 'count' is not assignable to *string", err.Error())
+
+	assert.Equal(t, ErrSessionKeyNotExists, s.GetInto("notexists", &cart))
+	err = s.GetInto("cart", cart)
+	assert.Equal(t, "session: destination for key 'cart' must be a non-nil pointer", err.Error())
+
+	// gob serializer when session is not created by manager
+	gs := &Session{Values: make(map[string]interface{})}
+	assert.Nil(t, gs.SetFrom("cart", cart))
+	assert.Equal(t, "gob", gs.Get("cart").(EncodedValue).Serializer)
+	var gcart testCart
+	assert.Nil(t, gs.GetInto("cart", &gcart))
+	assert.Equal(t, cart, gcart)
+}
+
+func TestSessionTypedValueMigration(t *testing.T) {
+	m := createTestManager(t, `
+		security {
+			session {
+				serializer = "json"
+			}
+		}
+	`)
+
+	s := m.NewSession()
+	assert.Nil(t, s.SetFrom("cart", testCartV1{Item: "pen"}))
+
+	m.AddSchema("cart", 1, func(from int, data []byte, sz Serializer) ([]byte, error) {
+		var old testCartV1
+		if err := sz.Unmarshal(data, &old); err != nil {
+			return nil, err
+		}
+		return sz.Marshal(testCart{Items: []string{old.Item}, Total: 1})
+	})
+
+	var cart testCart
+	err := s.GetInto("cart", &cart)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pen"}, cart.Items)
+	assert.Equal(t, 1, s.Get("cart").(EncodedValue).Version)
+
+	// new values are stored with current version
+	assert.Nil(t, s.SetFrom("cart", cart))
+	assert.Equal(t, 1, s.Get("cart").(EncodedValue).Version)
+
+	// missing migration step
+	m.AddSchema("cart", 3, nil)
+	err = s.GetInto("cart", &cart)
+	assert.Equal(t, "session: no migration for key 'cart' from schema version 1", err.Error())
+}
+
+type testSerializer struct{ jsonSerializer }
+
+func TestSessionSerializerRegister(t *testing.T) {
+	assert.Equal(t, ErrSessionSerializerIsNil, AddSerializer("custom", nil))
+	assert.Nil(t, AddSerializer("custom", &testSerializer{}))
+	err := AddSerializer("custom", &testSerializer{})
+	assert.Equal(t, "session: serializer name 'custom' is already added, skip it", err.Error())
+
+	cfg, _ := config.ParseString(`security { session { serializer = "msgpack"; } }`)
+	m, err := NewManager(cfg)
+	assert.Nil(t, m)
+	assert.Equal(t, "session: serializer name 'msgpack' not exists", err.Error())
+
+	s := &Session{Values: map[string]interface{}{
+		"cart": EncodedValue{Serializer: "msgpack"},
+	}}
+	var cart testCart
+	err = s.GetInto("cart", &cart)
+	assert.Equal(t, "session: serializer name 'msgpack' not exists", err.Error())
+}
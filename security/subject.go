@@ -32,10 +32,16 @@ import (
 // Subject instance provides a convenience wrapper method for all authentication
 // (primary principal, is-authenticated, logout) and authorization (hasrole,
 // hasanyrole, hasallroles, ispermitted, ispermittedall) purpose.
+//
+// Impersonation
+//
+// While impersonating another user, `Subject.Impersonator` holds the original
+// authentication info and the remaining fields represents impersonated user.
 type Subject struct {
 	AuthenticationInfo *authc.AuthenticationInfo
 	AuthorizationInfo  *authz.AuthorizationInfo
 	Session            *session.Session
+	Impersonator       *authc.AuthenticationInfo
 }
 
 // PrimaryPrincipal method is convenience wrapper. See `AuthenticationInfo.PrimaryPrincipal`.
@@ -62,6 +68,12 @@ func (s *Subject) IsAuthenticated() bool {
 	return s.Session.IsAuthenticated
 }
 
+// IsImpersonated method returns true if subject is impersonated by another user
+// otherwise false.
+func (s *Subject) IsImpersonated() bool {
+	return s.Impersonator != nil
+}
+
 // Logout method is convenience wrapper. See `Session.Clear`.
 func (s *Subject) Logout() {
 	if s.Session != nil {
@@ -103,6 +115,7 @@ func (s *Subject) Reset() {
 	s.AuthenticationInfo = nil
 	s.AuthorizationInfo = nil
 	s.Session = nil
+	s.Impersonator = nil
 }
 
 // String method is stringer interface implementation.
@@ -75,3 +75,15 @@ func TestSecuritySubject(t *testing.T) {
 
 	ReleaseSubject(sub)
 }
+
+func TestSecuritySubjectImpersonated(t *testing.T) {
+	sub := AcquireSubject()
+	assert.False(t, sub.IsImpersonated())
+
+	sub.Impersonator = authc.NewAuthenticationInfo()
+	assert.True(t, sub.IsImpersonated())
+
+	sub.Reset()
+	assert.False(t, sub.IsImpersonated())
+	ReleaseSubject(sub)
+}
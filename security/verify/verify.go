@@ -0,0 +1,486 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package verify provides opt-in token based flows for password reset and
+// email verification. Tokens are random, time bound and single-use.
+// Persistence is pluggable via `verify.Storer` interface, store type `nonce`
+// uses the security nonce store. Stores never see the raw token value, tokens
+// are persisted and looked up by its SHA-256 digest. Delivery of token to the user (typically
+// an email) via `verify.Mailer` interface.
+//
+// Configuration goes into `security.conf` under `security.verify { ... }`.
+//
+//	security {
+//	  verify {
+//	    store.type = "memory"
+//	    password_reset {
+//	      enable = true
+//	      ttl = "1h"
+//	      token_length = 32
+//	      routes {
+//	        controller = "AccountController"
+//	        request_path = "/password/reset"
+//	        confirm_path = "/password/reset/:token"
+//	      }
+//	    }
+//	    email_verification {
+//	      enable = true
+//	      ttl = "24h"
+//	    }
+//	  }
+//	}
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+	"aahframe.work/security/nonce"
+)
+
+// Token purposes supported by verify flows.
+const (
+	PasswordReset     = "password_reset"
+	EmailVerification = "email_verification"
+)
+
+var (
+	// ErrStoreIsNil returned when supplied store is nil.
+	ErrStoreIsNil = errors.New("security/verify: store value is nil")
+
+	// ErrFlowNotEnabled returned when token flow is not enabled in the configuration.
+	ErrFlowNotEnabled = errors.New("security/verify: flow is not enabled")
+
+	// ErrTokenNotFound returned when token does not exists or already consumed.
+	ErrTokenNotFound = errors.New("security/verify: token not found")
+
+	// ErrTokenExpired returned when token is expired.
+	ErrTokenExpired = errors.New("security/verify: token is expired")
+
+	// ErrTokenPurposeMismatch returned when token was issued for different purpose.
+	ErrTokenPurposeMismatch = errors.New("security/verify: token purpose mismatch")
+
+	registerStores = map[string]Storer{"memory": &MemoryStore{}, "nonce": &NonceStore{}}
+	flowPurposes   = []string{PasswordReset, EmailVerification}
+)
+
+type (
+	// Storer is interface for implementing pluggable token persistence.
+	// Method `Read` and `Delete` have to return `verify.ErrTokenNotFound` if token
+	// does not exists, it's used for single-use enforcement.
+	//
+	// Token value supplied to the store is SHA-256 digest of the token in hex,
+	// raw token value is never persisted. Store instance is kept across the
+	// security re-initialization (e.g. config hot-reload), so `Init` must not
+	// discard the already stored tokens.
+	Storer interface {
+		Init(appCfg *config.Config) error
+		Save(t *Token) error
+		Read(value string) (*Token, error)
+		Delete(value string) error
+	}
+
+	// Mailer is interface for sending generated token to the user,
+	// typically via email.
+	Mailer interface {
+		Send(t *Token) error
+	}
+
+	// MailerFunc is func type of `verify.Mailer` interface.
+	MailerFunc func(t *Token) error
+)
+
+// Send method calls mailer func with given token.
+func (f MailerFunc) Send(t *Token) error {
+	return f(t)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package methods
+//___________________________________
+
+// AddStore method allows you to add user created token store
+// for aah framework application.
+func AddStore(name string, store Storer) error {
+	if store == nil {
+		return ErrStoreIsNil
+	}
+
+	if _, found := registerStores[name]; found {
+		return fmt.Errorf("verify: store name '%v' is already added, skip it", name)
+	}
+
+	registerStores[name] = store
+	return nil
+}
+
+// New method initializes the verify manager based on configuration
+// `security.verify { ... }`. Returns nil manager if none of the flows are enabled.
+func New(appCfg *config.Config) (*Manager, error) {
+	keyPrefix := "security.verify"
+	m := &Manager{flows: make(map[string]*Flow)}
+	for _, purpose := range flowPurposes {
+		f, err := parseFlow(appCfg, keyPrefix+"."+purpose, purpose)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			m.flows[purpose] = f
+		}
+	}
+
+	if len(m.flows) == 0 {
+		return nil, nil
+	}
+
+	storeName := appCfg.StringDefault(keyPrefix+".store.type", "memory")
+	store, found := registerStores[storeName]
+	if !found {
+		return nil, fmt.Errorf("verify: store name '%v' not exists", storeName)
+	}
+	if err := store.Init(appCfg); err != nil {
+		return nil, err
+	}
+	m.store = store
+
+	return m, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Manager
+//___________________________________
+
+// Manager holds enabled token flows, store and mailer.
+type Manager struct {
+	flows  map[string]*Flow
+	store  Storer
+	mailer Mailer
+}
+
+// Flow method returns the token flow for given purpose otherwise nil.
+func (m *Manager) Flow(purpose string) *Flow {
+	return m.flows[purpose]
+}
+
+// Flows method returns all the enabled token flows.
+func (m *Manager) Flows() map[string]*Flow {
+	return m.flows
+}
+
+// IsEnabled method returns true if token flow is enabled for given purpose
+// otherwise false.
+func (m *Manager) IsEnabled(purpose string) bool {
+	_, found := m.flows[purpose]
+	return found
+}
+
+// SetMailer method sets the mailer, which is used to send generated token.
+func (m *Manager) SetMailer(mailer Mailer) {
+	m.mailer = mailer
+}
+
+// SetNonceStore method sets the security nonce store for store type `nonce`
+// i.e. `security.verify.store.type = "nonce"`, otherwise it's no-op.
+func (m *Manager) SetNonceStore(s *nonce.Store) {
+	if _, ok := m.store.(*NonceStore); ok {
+		m.store = &NonceStore{store: s}
+	}
+}
+
+// Generate method creates a new token for given purpose and subject (e.g. username,
+// email), persists it via store and sends it via mailer if it's set.
+func (m *Manager) Generate(purpose, subject string) (*Token, error) {
+	f := m.Flow(purpose)
+	if f == nil {
+		return nil, ErrFlowNotEnabled
+	}
+
+	now := time.Now()
+	t := &Token{
+		Value:       ess.SecureRandomString(f.TokenLength),
+		Purpose:     purpose,
+		Subject:     subject,
+		CreatedTime: now,
+		ExpiresAt:   now.Add(f.TTL),
+	}
+	st := *t
+	st.Value = tokenKey(t.Value)
+	if err := m.store.Save(&st); err != nil {
+		return nil, err
+	}
+
+	if m.mailer != nil {
+		if err := m.mailer.Send(t); err != nil {
+			_ = m.store.Delete(st.Value)
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Validate method checks given token value for purpose and expiry without
+// consuming it. Typically used to render the confirm page.
+func (m *Manager) Validate(purpose, value string) (*Token, error) {
+	if !m.IsEnabled(purpose) {
+		return nil, ErrFlowNotEnabled
+	}
+
+	key := tokenKey(value)
+	st, err := m.store.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if st.Purpose != purpose {
+		return nil, ErrTokenPurposeMismatch
+	}
+
+	// Expiry is issued by this server, so clock skew is not applicable
+	if st.IsExpired() {
+		_ = m.store.Delete(key)
+		return nil, ErrTokenExpired
+	}
+
+	t := *st
+	t.Value = value
+	return &t, nil
+}
+
+// Consume method validates the given token value and removes it from store,
+// so token can be used only once.
+func (m *Manager) Consume(purpose, value string) (*Token, error) {
+	t, err := m.Validate(purpose, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// token consumed by concurrent request, store returns ErrTokenNotFound
+	if err = m.store.Delete(tokenKey(value)); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Flow and Token
+//___________________________________
+
+type (
+	// Flow holds the configuration of single token flow.
+	Flow struct {
+		Purpose     string
+		TokenLength int
+		TTL         time.Duration
+		Controller  string
+		Routes      []*RouteInfo
+	}
+
+	// RouteInfo holds the scaffolding route details of token flow, router
+	// adds these routes for configured controller.
+	RouteInfo struct {
+		Name   string
+		Method string
+		Path   string
+		Action string
+	}
+
+	// Token holds the generated token details.
+	Token struct {
+		Value       string
+		Purpose     string
+		Subject     string
+		CreatedTime time.Time
+		ExpiresAt   time.Time
+	}
+)
+
+// IsExpired method returns true if token is expired otherwise false.
+func (t *Token) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// MemoryStore
+//___________________________________
+
+var _ Storer = (*MemoryStore)(nil)
+
+// MemoryStore is default token store, it keeps tokens in-memory. It's suitable
+// for single instance application, use persistent store otherwise.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]*Token
+}
+
+// Init method initializes the in-memory token store, already stored tokens
+// are retained on re-initialization.
+func (s *MemoryStore) Init(appCfg *config.Config) error {
+	s.mu.Lock()
+	if s.tokens == nil {
+		s.tokens = make(map[string]*Token)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Save method stores the given token.
+func (s *MemoryStore) Save(t *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeExpired()
+	s.tokens[t.Value] = t
+	return nil
+}
+
+// Read method returns the token for given value.
+func (s *MemoryStore) Read(value string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, found := s.tokens[value]; found {
+		return t, nil
+	}
+	return nil, ErrTokenNotFound
+}
+
+// Delete method removes the token for given value.
+func (s *MemoryStore) Delete(value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.tokens[value]; !found {
+		return ErrTokenNotFound
+	}
+	delete(s.tokens, value)
+	return nil
+}
+
+func (s *MemoryStore) removeExpired() {
+	for k, t := range s.tokens {
+		if t.IsExpired() {
+			delete(s.tokens, k)
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// NonceStore
+//___________________________________
+
+var _ Storer = (*NonceStore)(nil)
+
+// NonceStore is token store backed by security nonce store under namespace
+// `nonce.NamespaceVerify`, store is set via `Manager.SetNonceStore`.
+type NonceStore struct {
+	store *nonce.Store
+}
+
+// Init method initializes the nonce token store.
+func (s *NonceStore) Init(appCfg *config.Config) error {
+	return nil
+}
+
+// Save method stores the given token until its expiry.
+func (s *NonceStore) Save(t *Token) error {
+	if s.store == nil {
+		return ErrStoreIsNil
+	}
+	return s.store.Put(nonce.NamespaceVerify, t.Value, *t, time.Until(t.ExpiresAt))
+}
+
+// Read method returns the token for given value.
+func (s *NonceStore) Read(value string) (*Token, error) {
+	if s.store == nil {
+		return nil, ErrStoreIsNil
+	}
+	v, err := s.store.Get(nonce.NamespaceVerify, value)
+	if err != nil {
+		return nil, ErrTokenNotFound
+	}
+	t, ok := v.(Token)
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return &t, nil
+}
+
+// Delete method removes the token for given value.
+func (s *NonceStore) Delete(value string) error {
+	if s.store == nil {
+		return ErrStoreIsNil
+	}
+	if _, err := s.store.Consume(nonce.NamespaceVerify, value); err != nil {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// tokenKey method returns the SHA-256 digest of token value in hex, it's
+// used as store key.
+func tokenKey(value string) string {
+	h := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(h[:])
+}
+
+func parseFlow(cfg *config.Config, keyPrefix, purpose string) (*Flow, error) {
+	if !cfg.BoolDefault(keyPrefix+".enable", false) {
+		return nil, nil
+	}
+
+	defaultTTL := time.Hour
+	if purpose == EmailVerification {
+		defaultTTL = 24 * time.Hour
+	}
+	ttl, err := settings.ParseDuration(cfg, keyPrefix+".ttl", defaultTTL)
+	if err != nil {
+		return nil, fmt.Errorf("verify: %s", err)
+	}
+
+	f := &Flow{
+		Purpose:     purpose,
+		TokenLength: cfg.IntDefault(keyPrefix+".token_length", 32),
+		TTL:         ttl,
+		Controller:  cfg.StringDefault(keyPrefix+".routes.controller", ""),
+	}
+	if f.TokenLength < 16 {
+		return nil, fmt.Errorf("verify: '%s.token_length' must be at least 16", keyPrefix)
+	}
+
+	// Route scaffolding, only when controller is configured
+	if ess.IsStrEmpty(f.Controller) {
+		return f, nil
+	}
+
+	basePath := "/" + strings.Replace(purpose, "_", "-", -1)
+	requestPath := cfg.StringDefault(keyPrefix+".routes.request_path", basePath)
+	confirmPath := cfg.StringDefault(keyPrefix+".routes.confirm_path", basePath+"/:token")
+	actionPrefix := "PasswordReset"
+	if purpose == EmailVerification {
+		actionPrefix = "EmailVerification"
+	}
+
+	f.Routes = []*RouteInfo{
+		{Name: purpose + "_request", Method: ahttp.MethodPost, Path: requestPath, Action: actionPrefix + "Request"},
+		{Name: purpose + "_confirm", Method: ahttp.MethodGet, Path: confirmPath, Action: actionPrefix + "Confirm"},
+	}
+	if purpose == PasswordReset {
+		f.Routes = append(f.Routes, &RouteInfo{Name: purpose + "_complete",
+			Method: ahttp.MethodPost, Path: confirmPath, Action: actionPrefix + "Complete"})
+	}
+
+	return f, nil
+}
@@ -0,0 +1,238 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package verify
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/security/nonce"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyNotEnabled(t *testing.T) {
+	cfg, _ := config.ParseString(`security { }`)
+	m, err := New(cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, m)
+}
+
+func TestVerifyGenerateAndConsume(t *testing.T) {
+	m := createTestManager(t, `
+	security {
+	  verify {
+	    password_reset {
+	      enable = true
+	      ttl = "30m"
+	    }
+	  }
+	}
+	`)
+
+	assert.True(t, m.IsEnabled(PasswordReset))
+	assert.False(t, m.IsEnabled(EmailVerification))
+	assert.Equal(t, 30*time.Minute, m.Flow(PasswordReset).TTL)
+	assert.Equal(t, 0, len(m.Flow(PasswordReset).Routes))
+
+	var sent *Token
+	m.SetMailer(MailerFunc(func(t *Token) error {
+		sent = t
+		return nil
+	}))
+
+	tk, err := m.Generate(PasswordReset, "jeeva@example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, tk, sent)
+	assert.Equal(t, 32, len(tk.Value))
+	assert.Equal(t, "jeeva@example.com", tk.Subject)
+
+	_, err = m.Generate(EmailVerification, "jeeva@example.com")
+	assert.Equal(t, ErrFlowNotEnabled, err)
+
+	vt, err := m.Validate(PasswordReset, tk.Value)
+	assert.Nil(t, err)
+	assert.Equal(t, tk, vt)
+
+	ct, err := m.Consume(PasswordReset, tk.Value)
+	assert.Nil(t, err)
+	assert.Equal(t, tk, ct)
+
+	// single-use
+	_, err = m.Consume(PasswordReset, tk.Value)
+	assert.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestVerifyExpiredAndPurpose(t *testing.T) {
+	m := createTestManager(t, `
+	security {
+	  verify {
+	    password_reset {
+	      enable = true
+	    }
+	    email_verification {
+	      enable = true
+	    }
+	  }
+	}
+	`)
+	assert.Equal(t, 24*time.Hour, m.Flow(EmailVerification).TTL)
+
+	tk, err := m.Generate(EmailVerification, "jeeva")
+	assert.Nil(t, err)
+
+	_, err = m.Consume(PasswordReset, tk.Value)
+	assert.Equal(t, ErrTokenPurposeMismatch, err)
+
+	st, err := m.store.Read(tokenKey(tk.Value))
+	assert.Nil(t, err)
+	st.ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = m.Validate(EmailVerification, tk.Value)
+	assert.Equal(t, ErrTokenExpired, err)
+
+	_, err = m.Validate(EmailVerification, tk.Value)
+	assert.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestVerifyTokenHashedInStore(t *testing.T) {
+	m := createTestManager(t, `security { verify { password_reset { enable = true; } } }`)
+	tk, err := m.Generate(PasswordReset, "jeeva")
+	assert.Nil(t, err)
+
+	_, err = m.store.Read(tk.Value)
+	assert.Equal(t, ErrTokenNotFound, err)
+	st, err := m.store.Read(tokenKey(tk.Value))
+	assert.Nil(t, err)
+	assert.Equal(t, 64, len(st.Value))
+	assert.NotEqual(t, tk.Value, st.Value)
+
+	// re-initialization (e.g. hot-reload) retains the issued tokens
+	m2 := createTestManager(t, `security { verify { password_reset { enable = true; } } }`)
+	vt, err := m2.Validate(PasswordReset, tk.Value)
+	assert.Nil(t, err)
+	assert.Equal(t, tk.Value, vt.Value)
+}
+
+func TestVerifyMailerError(t *testing.T) {
+	m := createTestManager(t, `
+	security {
+	  verify {
+	    email_verification {
+	      enable = true
+	    }
+	  }
+	}
+	`)
+	m.SetMailer(MailerFunc(func(t *Token) error {
+		return errors.New("smtp unavailable")
+	}))
+
+	tk, err := m.Generate(EmailVerification, "jeeva")
+	assert.Nil(t, tk)
+	assert.Equal(t, "smtp unavailable", err.Error())
+}
+
+func TestVerifyNonceStore(t *testing.T) {
+	m := createTestManager(t, `
+	security {
+	  verify {
+	    store {
+	      type = "nonce"
+	    }
+	    password_reset {
+	      enable = true
+	    }
+	  }
+	}
+	`)
+
+	// nonce store is not set
+	_, err := m.Generate(PasswordReset, "jeeva")
+	assert.Equal(t, ErrStoreIsNil, err)
+
+	m.SetNonceStore(nonce.New(nil))
+	tk, err := m.Generate(PasswordReset, "jeeva")
+	assert.Nil(t, err)
+
+	vt, err := m.Validate(PasswordReset, tk.Value)
+	assert.Nil(t, err)
+	assert.Equal(t, "jeeva", vt.Subject)
+
+	_, err = m.Consume(PasswordReset, tk.Value)
+	assert.Nil(t, err)
+	_, err = m.Consume(PasswordReset, tk.Value)
+	assert.Equal(t, ErrTokenNotFound, err)
+
+	// no-op for other store types
+	mm := createTestManager(t, `security { verify { password_reset { enable = true; } } }`)
+	mm.SetNonceStore(nonce.New(nil))
+	_, ok := mm.store.(*MemoryStore)
+	assert.True(t, ok)
+}
+
+func TestVerifyRouteScaffolding(t *testing.T) {
+	m := createTestManager(t, `
+	security {
+	  verify {
+	    password_reset {
+	      enable = true
+	      routes {
+	        controller = "AccountController"
+	        request_path = "/account/password/reset"
+	      }
+	    }
+	  }
+	}
+	`)
+
+	routes := m.Flow(PasswordReset).Routes
+	assert.Equal(t, 3, len(routes))
+	assert.Equal(t, "password_reset_request", routes[0].Name)
+	assert.Equal(t, ahttp.MethodPost, routes[0].Method)
+	assert.Equal(t, "/account/password/reset", routes[0].Path)
+	assert.Equal(t, "PasswordResetRequest", routes[0].Action)
+	assert.Equal(t, "/password-reset/:token", routes[1].Path)
+	assert.Equal(t, "PasswordResetConfirm", routes[1].Action)
+	assert.Equal(t, ahttp.MethodPost, routes[2].Method)
+	assert.Equal(t, "PasswordResetComplete", routes[2].Action)
+}
+
+func TestVerifyConfigErrors(t *testing.T) {
+	for _, c := range []struct{ cfg, err string }{
+		{`security { verify { password_reset {
+			enable = true
+			ttl = "30"
+		} } }`, "verify: 'security.verify.password_reset.ttl' value is not a valid time unit"},
+		{`security { verify { password_reset {
+			enable = true
+			token_length = 8
+		} } }`, "verify: 'security.verify.password_reset.token_length' must be at least 16"},
+		{`security { verify {
+			store { type = "redis"; }
+			email_verification { enable = true; }
+		} }`, "verify: store name 'redis' not exists"},
+	} {
+		cfg, _ := config.ParseString(c.cfg)
+		_, err := New(cfg)
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+
+	assert.Equal(t, ErrStoreIsNil, AddStore("custom", nil))
+	err := AddStore("memory", &MemoryStore{})
+	assert.Equal(t, "verify: store name 'memory' is already added, skip it", err.Error())
+}
+
+func createTestManager(t *testing.T, cfgStr string) *Manager {
+	cfg, err := config.ParseString(cfgStr)
+	if err != nil {
+		t.Fatalf("unable to parse test config: %v", err)
+	}
+	m, err := New(cfg)
+	assert.Nil(t, err, "unexpected")
+	return m
+}
@@ -98,9 +98,24 @@ func TestSecuritySessionTemplateFuns(t *testing.T) {
 	assert.False(t, vm.tmplIsPermitted(viewArgs, "*"))
 	assert.False(t, vm.tmplIsPermittedAll(viewArgs, "news:read,write", "manage:*"))
 
+	assert.False(t, vm.tmplIsImpersonated(viewArgs))
+	assert.Nil(t, vm.tmplImpersonator(viewArgs))
+
+	viewArgs[KeyViewArgSubject].(*security.Subject).Impersonator = testGetAuthenticationInfo()
+	assert.True(t, vm.tmplIsImpersonated(viewArgs))
+	assert.Equal(t, "jeeva", vm.tmplImpersonator(viewArgs).Value)
+
 	delete(viewArgs, KeyViewArgSubject)
 	v4 := vm.tmplIsAuthenticated(viewArgs)
 	assert.False(t, v4)
+	assert.False(t, vm.tmplIsImpersonated(viewArgs))
+}
+
+func TestSecurityImpersonateErrors(t *testing.T) {
+	ctx := newContext(nil, nil)
+	assert.Equal(t, ErrUnableToGetPrincipal, ctx.Impersonate(nil))
+	assert.Equal(t, ErrUnableToGetPrincipal, ctx.Impersonate(authc.NewAuthenticationInfo()))
+	assert.Equal(t, ErrNotImpersonated, ctx.RevertImpersonation())
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -395,6 +410,78 @@ func TestSecurityHandleBasicAuthcAndAuthz(t *testing.T) {
 	r3.SetBasicAuth("jeeva", "welcome123")
 	ctx1.Req = ahttp.AcquireRequest(r3)
 	AuthcAuthzMiddleware(ctx1, &Middleware{})
+
+	// optional auth, without credentials continues as anonymous
+	ctx2 := ts.app.he.newContext()
+	ctx2.Req = ahttp.AcquireRequest(r2)
+	ctx2.Res = ahttp.AcquireResponseWriter(httptest.NewRecorder())
+	ctx2.route = &router.Route{Auth: "optional(basic_auth)"}
+	AuthcAuthzMiddleware(ctx2, &Middleware{})
+	assert.Equal(t, http.StatusOK, ctx2.Reply().Code)
+	assert.False(t, ctx2.Subject().IsAuthenticated())
+
+	// optional auth, with credentials populates subject
+	ctx3 := ts.app.he.newContext()
+	ctx3.Req = ahttp.AcquireRequest(r3)
+	ctx3.Res = ahttp.AcquireResponseWriter(httptest.NewRecorder())
+	ctx3.route = &router.Route{Auth: "optional(basic_auth)"}
+	AuthcAuthzMiddleware(ctx3, &Middleware{})
+	assert.Equal(t, http.StatusOK, ctx3.Reply().Code)
+	assert.True(t, ctx3.Subject().IsAuthenticated())
+}
+
+func TestSecurityRouteAuthSchemeChain(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	cfg, err := config.ParseString(`
+		security {
+		  auth_schemes {
+		    jwt_auth {
+		      scheme = "generic"
+		      authenticator = "security/Authentication"
+		      authorizer = "security/Authorization"
+		      header {
+		        identity_prefix = "Bearer "
+		      }
+		    }
+		    token_auth {
+		      scheme = "generic"
+		      authenticator = "security/Authentication"
+		      authorizer = "security/Authorization"
+		    }
+		    basic_auth {
+		      scheme = "basic"
+		      authenticator = "security/Authentication"
+		      authorizer = "security/Authorization"
+		    }
+		  }
+		}
+	`)
+	assert.Nil(t, err)
+	assert.Nil(t, ts.app.Config().Merge(cfg))
+	assert.Nil(t, ts.app.initSecurity())
+
+	testCases := []struct {
+		auth, authorization, expected string
+	}{
+		{"jwt_auth, basic_auth", "Basic amVldmE6d2VsY29tZTEyMw==", "basic_auth"},
+		{"jwt_auth, basic_auth", "Bearer eyJhbGciOiJIUzI1NiJ9", "jwt_auth"},
+		{"token_auth, basic_auth", "Basic amVldmE6d2VsY29tZTEyMw==", "basic_auth"},
+		{"basic_auth, jwt_auth", "Bearer eyJhbGciOiJIUzI1NiJ9", "jwt_auth"},
+		{"basic_auth, jwt_auth", "", "jwt_auth"},
+	}
+	for _, tc := range testCases {
+		r := httptest.NewRequest(ahttp.MethodGet, "http://localhost:8080/api/v1/users", nil)
+		if len(tc.authorization) > 0 {
+			r.Header.Set(ahttp.HeaderAuthorization, tc.authorization)
+		}
+		ctx := ts.app.he.newContext()
+		ctx.Req = ahttp.AcquireRequest(r)
+		ctx.route = &router.Route{Auth: tc.auth}
+		assert.Equal(t, tc.expected, routeAuthScheme(ctx).Key(), tc.auth)
+	}
 }
 
 func TestSecurityAntiCSRF(t *testing.T) {
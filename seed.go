@@ -0,0 +1,324 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+)
+
+// SeedFunc func type is the data seed function, it's applied once per
+// environment profile, see `Application.AddSeed`.
+type SeedFunc func(ctx context.Context) error
+
+// SeedFileFunc func type executes the content of seed file, for e.g.: SQL
+// statements via database module.
+//
+//	aah.App().SetSeedFileFunc(func(ctx context.Context, name string, content []byte) error {
+//		_, err := db.ExecContext(ctx, string(content))
+//		return err
+//	})
+type SeedFileFunc func(ctx context.Context, name string, content []byte) error
+
+// SeedMarkerStore interface is used to persist the idempotency markers of
+// applied seeds, so that seed is applied only once. Key is composed of
+// environment profile and seed name e.g. `dev/users` or `dev/001_users.sql`.
+//
+// By default markers are stored in JSON file `seed.marker_file`, database
+// module could implement it with the table in the same database.
+type SeedMarkerStore interface {
+	IsApplied(ctx context.Context, key string) (bool, error)
+	MarkApplied(ctx context.Context, key string) error
+}
+
+// AddSeed method adds the data seed function for given environment
+// profiles, if profiles is not provided then it's applied on all the profiles
+// of config `seed.profiles`. Seed functions are applied in the order of
+// registration after the seed files.
+//
+//	aah.App().AddSeed("users", func(ctx context.Context) error {
+//		return users.CreateDefaults(ctx)
+//	}, "dev", "test")
+func (a *Application) AddSeed(name string, fn SeedFunc, profiles ...string) error {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 || fn == nil {
+		return errors.New("aah: seed name and func is required")
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	for _, s := range a.seeds {
+		if s.name == name {
+			return fmt.Errorf("aah: seed name '%s' already exists", name)
+		}
+	}
+	a.seeds = append(a.seeds, &seedEntry{name: name, fn: fn, profiles: profiles})
+	return nil
+}
+
+// SetSeedFileFunc method sets the executor of seed files, it's required if
+// the seed files exists for the environment profile.
+func (a *Application) SetSeedFileFunc(fn SeedFileFunc) {
+	a.Lock()
+	defer a.Unlock()
+	a.seedFileFn = fn
+}
+
+// SetSeedMarkerStore method sets the store of seed idempotency markers, it
+// replaces the default JSON file store.
+func (a *Application) SetSeedMarkerStore(store SeedMarkerStore) {
+	a.Lock()
+	defer a.Unlock()
+	a.seedMarkers = store
+}
+
+// RunSeeds method applies the pending data seeds of active environment
+// profile and returns the count of applied seeds. Seeds already applied per
+// marker store are skipped. It's invoked at startup if
+// `seed.on_startup = true` or on demand via `<app-binary> seed`.
+func (a *Application) RunSeeds(ctx context.Context) (int, error) {
+	sd := a.seeder
+	if sd == nil {
+		return 0, errors.New("aah: seed is not initialized")
+	}
+	profile := a.EnvProfile()
+	if !ess.IsSliceContainsString(sd.profiles, profile) {
+		return 0, fmt.Errorf("aah: seed is not allowed for environment profile '%s', see 'seed.profiles'", profile)
+	}
+
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, sd.timeout)
+	defer cancel()
+
+	tasks, err := a.seedTasks(profile)
+	if err != nil {
+		return 0, err
+	}
+
+	a.RLock()
+	store := a.seedMarkers
+	a.RUnlock()
+	if store == nil {
+		store = &fileSeedMarkerStore{file: sd.markerFile}
+	}
+
+	applied := 0
+	for _, t := range tasks {
+		key := profile + "/" + t.name
+		done, err := store.IsApplied(ctx, key)
+		if err != nil {
+			return applied, fmt.Errorf("aah: seed '%s': %v", key, err)
+		}
+		if done {
+			a.Log().Debugf("Seed '%s' is already applied", key)
+			continue
+		}
+		if err = t.fn(ctx); err != nil {
+			return applied, fmt.Errorf("aah: seed '%s': %v", key, err)
+		}
+		if err = store.MarkApplied(ctx, key); err != nil {
+			return applied, fmt.Errorf("aah: seed '%s': %v", key, err)
+		}
+		a.Log().Infof("Seed '%s' is applied", key)
+		applied++
+	}
+	return applied, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initSeed method initializes the data seeding from config `seed { ... }`.
+// Seed files are read from `<app-base-dir>/<seed.dir>/<profile>/` in the
+// order of file name.
+//
+//	seed {
+//	  profiles = ["dev", "test"]
+//	  on_startup = false
+//	  dir = "seeds"
+//	  marker_file = "seeds/.applied.json"
+//	  timeout = "5m"
+//	}
+func (a *Application) initSeed() error {
+	keyPrefix := "seed"
+	cfg := a.Config()
+
+	profiles, found := cfg.StringList(keyPrefix + ".profiles")
+	if !found {
+		profiles = []string{"dev", "test"}
+	}
+	timeout, err := settings.ParseDuration(cfg, keyPrefix+".timeout", 5*time.Minute)
+	if err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("'%s.timeout' value must be greater than zero", keyPrefix)
+	}
+
+	markerFile := cfg.StringDefault(keyPrefix+".marker_file", "seeds/.applied.json")
+	if !filepath.IsAbs(markerFile) {
+		markerFile = filepath.Join(a.BaseDir(), markerFile)
+	}
+
+	a.seeder = &seeder{
+		profiles:   profiles,
+		onStartup:  cfg.BoolDefault(keyPrefix+".on_startup", false),
+		dir:        strings.Trim(filepath.ToSlash(cfg.StringDefault(keyPrefix+".dir", "seeds")), "/"),
+		markerFile: markerFile,
+		timeout:    timeout,
+	}
+	return nil
+}
+
+// runStartupSeeds method applies the seeds at startup if `seed.on_startup`
+// is true and active environment profile is allowed.
+func (a *Application) runStartupSeeds() error {
+	sd := a.seeder
+	if sd == nil || !sd.onStartup {
+		return nil
+	}
+	if !ess.IsSliceContainsString(sd.profiles, a.EnvProfile()) {
+		a.Log().Debugf("Seed is skipped for environment profile '%s'", a.EnvProfile())
+		return nil
+	}
+	_, err := a.RunSeeds(context.Background())
+	return err
+}
+
+// seedTasks method returns the seed files followed by seed functions of
+// given environment profile.
+func (a *Application) seedTasks(profile string) ([]*seedTask, error) {
+	var tasks []*seedTask
+
+	dir := path.Join(a.VirtualBaseDir(), a.seeder.dir, profile)
+	if a.VFS().IsExists(dir) {
+		files, err := a.VFS().ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+
+		a.RLock()
+		fileFn := a.seedFileFn
+		a.RUnlock()
+		for _, fi := range files {
+			if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+				continue
+			}
+			if fileFn == nil {
+				return nil, fmt.Errorf("aah: seed file '%s' exists, however seed file func is not set, use `SetSeedFileFunc`", fi.Name())
+			}
+			name, file := fi.Name(), path.Join(dir, fi.Name())
+			tasks = append(tasks, &seedTask{name: name, fn: func(ctx context.Context) error {
+				content, err := a.VFS().ReadFile(file)
+				if err != nil {
+					return err
+				}
+				return fileFn(ctx, name, content)
+			}})
+		}
+	}
+
+	a.RLock()
+	defer a.RUnlock()
+	for _, s := range a.seeds {
+		if len(s.profiles) == 0 || ess.IsSliceContainsString(s.profiles, profile) {
+			tasks = append(tasks, &seedTask{name: s.name, fn: s.fn})
+		}
+	}
+	return tasks, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Seeder and marker store
+//______________________________________________________________________________
+
+type seeder struct {
+	onStartup  bool
+	dir        string
+	markerFile string
+	timeout    time.Duration
+	profiles   []string
+	mu         sync.Mutex
+}
+
+type seedEntry struct {
+	name     string
+	fn       SeedFunc
+	profiles []string
+}
+
+type seedTask struct {
+	name string
+	fn   SeedFunc
+}
+
+// fileSeedMarkerStore stores the applied seed markers with its applied time
+// in the JSON file.
+type fileSeedMarkerStore struct {
+	file string
+}
+
+func (fs *fileSeedMarkerStore) IsApplied(_ context.Context, key string) (bool, error) {
+	markers, err := fs.load()
+	if err != nil {
+		return false, err
+	}
+	_, found := markers[key]
+	return found, nil
+}
+
+func (fs *fileSeedMarkerStore) MarkApplied(_ context.Context, key string) error {
+	markers, err := fs.load()
+	if err != nil {
+		return err
+	}
+	markers[key] = time.Now().UTC().Format(time.RFC3339)
+	b, err := json.MarshalIndent(markers, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = ess.MkDirAll(filepath.Dir(fs.file), 0755); err != nil {
+		return err
+	}
+
+	// write into temp file and rename, so that markers are not corrupted
+	tmp := fs.file + ".tmp"
+	if err = ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fs.file)
+}
+
+func (fs *fileSeedMarkerStore) load() (map[string]string, error) {
+	markers := make(map[string]string)
+	b, err := ioutil.ReadFile(fs.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return markers, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &markers); err != nil {
+		return nil, fmt.Errorf("seed marker file '%s': %v", fs.file, err)
+	}
+	return markers, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedRun(t *testing.T) {
+	a := newWebApp1TestApp(t)
+	tmpDir, err := ioutil.TempDir("", "aah-seed")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	markerFile := filepath.Join(tmpDir, "applied.json")
+	assert.Nil(t, mergeTestConfig(a, fmt.Sprintf(`
+	seed {
+	  on_startup = true
+	  marker_file = "%s"
+	}
+	`, filepath.ToSlash(markerFile))))
+	assert.Nil(t, a.initSeed())
+	assert.Equal(t, []string{"dev", "test"}, a.seeder.profiles)
+
+	var applied []string
+	assert.Nil(t, a.AddSeed("users", func(ctx context.Context) error {
+		applied = append(applied, "users")
+		return nil
+	}))
+	assert.Nil(t, a.AddSeed("orders", func(ctx context.Context) error {
+		applied = append(applied, "orders")
+		return nil
+	}, "test"))
+	assert.Equal(t, errors.New("aah: seed name 'users' already exists"),
+		a.AddSeed("users", func(ctx context.Context) error { return nil }))
+	assert.Equal(t, errors.New("aah: seed name and func is required"), a.AddSeed("nop", nil))
+
+	// seed file exists, however file func is not set
+	_, err = a.RunSeeds(context.Background())
+	assert.True(t, strings.Contains(err.Error(), "seed file '001_users.sql' exists"))
+
+	a.SetSeedFileFunc(func(ctx context.Context, name string, content []byte) error {
+		assert.True(t, strings.Contains(string(content), "INSERT INTO users"))
+		applied = append(applied, name)
+		return nil
+	})
+	assert.Nil(t, a.runStartupSeeds())
+	assert.Equal(t, []string{"001_users.sql", "users"}, applied)
+
+	// idempotent
+	n, err := a.RunSeeds(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, 2, len(applied))
+
+	markers, err := (&fileSeedMarkerStore{file: markerFile}).load()
+	assert.Nil(t, err)
+	assert.Contains(t, markers, "dev/001_users.sql")
+	assert.Contains(t, markers, "dev/users")
+
+	// seed func error is not marked
+	store := &testSeedMarkerStore{markers: make(map[string]bool)}
+	a.SetSeedMarkerStore(store)
+	assert.Nil(t, a.AddSeed("failing", func(ctx context.Context) error { return errors.New("db is down") }))
+	n, err = a.RunSeeds(context.Background())
+	assert.Equal(t, 2, n)
+	assert.Equal(t, errors.New("aah: seed 'dev/failing': db is down"), err)
+	assert.False(t, store.markers["dev/failing"])
+
+	// profile not allowed
+	a.seeder.profiles = []string{"test"}
+	assert.Nil(t, a.runStartupSeeds())
+	_, err = a.RunSeeds(context.Background())
+	assert.Equal(t, errors.New("aah: seed is not allowed for environment profile 'dev', see 'seed.profiles'"), err)
+}
+
+type testSeedMarkerStore struct {
+	markers map[string]bool
+}
+
+func (s *testSeedMarkerStore) IsApplied(_ context.Context, key string) (bool, error) {
+	return s.markers[key], nil
+}
+
+func (s *testSeedMarkerStore) MarkApplied(_ context.Context, key string) error {
+	s.markers[key] = true
+	return nil
+}
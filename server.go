@@ -7,6 +7,8 @@ package aah
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -19,6 +21,8 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
 	"aahframe.work/internal/settings"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -29,7 +33,7 @@ import (
 func (a *Application) Start() {
 	defer a.aahRecover()
 
-	if !a.settings.Initialized {
+	if !a.settings().Initialized {
 		a.Log().Fatal("aah application is not initialized, call `aah.Init` before the `aah.Start`.")
 	}
 
@@ -46,6 +50,8 @@ func (a *Application) Start() {
 	a.Log().Infof("App Single Binary Mode: %v", a.VFS().IsEmbeddedMode())
 	a.Log().Infof("App Profile: %s", a.EnvProfile())
 	a.Log().Infof("App TLS/SSL Enabled: %t", a.IsSSLEnabled())
+	a.Log().Infof("App HTTP/2 Enabled: %t, h2c: %t", a.settings().HTTP2Enabled, a.settings().H2CEnabled)
+	a.Log().Infof("App HTTP/3 Enabled: %t", a.settings().HTTP3Enabled)
 	if a.diagnosis != nil {
 		a.Log().Infof("App Diagnosis Enabled: true, mode: %s", a.diagnosis.Mode)
 	}
@@ -75,12 +81,12 @@ func (a *Application) Start() {
 	}
 
 	if !a.IsEnvProfile(settings.DefaultEnvProfile) {
-		a.Log().Infof("App Config Hot-Reload Enabled: %v", a.settings.HotReloadEnabled)
-		if a.settings.HotReloadEnabled {
-			a.Log().Infof("App Config Hot-Reload Signal: %s", a.settings.HotReloadSignalStr)
+		a.Log().Infof("App Config Hot-Reload Enabled: %v", a.settings().HotReloadEnabled)
+		if a.settings().HotReloadEnabled {
+			a.Log().Infof("App Config Hot-Reload Signal: %s", a.settings().HotReloadSignalStr)
 		}
 	}
-	a.Log().Infof("App Shutdown Grace Timeout: %s", a.settings.ShutdownGraceTimeStr)
+	a.Log().Infof("App Shutdown Grace Timeout: %s", a.settings().ShutdownGraceTimeStr)
 
 	if a.Log().IsLevelDebug() {
 		a.Log().Debug("Subscribed event callbacks")
@@ -94,21 +100,47 @@ func (a *Application) Start() {
 	// Publish `OnStart` event
 	a.EventStore().sortAndPublishSync(&Event{Name: EventOnStart})
 
+	// Wait for startup dependencies before accepting the traffic
+	if err := a.waitForDependencies(); err != nil {
+		a.Log().Fatal(err)
+	}
+
+	// Apply the data seeds of environment profile, if enabled
+	if err := a.runStartupSeeds(); err != nil {
+		a.Log().Fatal(err)
+	}
+
 	hl := a.Log().ToGoLogger()
 	hl.SetOutput(ioutil.Discard)
 
+	timeouts := a.Timeouts()
 	a.server = &http.Server{
-		Handler:        a,
-		ReadTimeout:    a.settings.HTTPReadTimeout,
-		WriteTimeout:   a.settings.HTTPWriteTimeout,
-		MaxHeaderBytes: a.settings.HTTPMaxHdrBytes,
-		ErrorLog:       hl,
+		Handler:           a,
+		ReadTimeout:       timeouts.ReadTimeout(),
+		ReadHeaderTimeout: timeouts.ReadHeaderTimeout(),
+		WriteTimeout:      timeouts.WriteTimeout(),
+		IdleTimeout:       timeouts.IdleTimeout(),
+		MaxHeaderBytes:    a.settings().HTTPMaxHdrBytes,
+		ErrorLog:          hl,
 	}
 
 	a.server.SetKeepAlivesEnabled(a.Config().BoolDefault("server.keep_alive", true))
+	a.limitConnections(a.server)
 	a.writePID()
 
 	go a.listenForHotReload()
+	go a.listenForRestart()
+	a.watchConfig()
+
+	// systemd socket activation
+	if err := a.activateSockets(); err != nil {
+		a.Log().Fatal(err)
+	}
+
+	// Additional listeners
+	if err := a.startListeners(); err != nil {
+		a.Log().Fatal(err)
+	}
 
 	// Unix Socket
 	if strings.HasPrefix(a.HTTPAddress(), "unix") {
@@ -119,7 +151,7 @@ func (a *Application) Start() {
 		a.Log().Infof("aah go diagnosis server running on %s",
 			a.diagnosis.Config.StringDefault("runtime.diagnosis.http.address", ":7070"))
 	}
-	a.server.Addr = fmt.Sprintf("%s:%s", a.HTTPAddress(), a.HTTPPort())
+	a.server.Addr = net.JoinHostPort(a.HTTPAddress(), a.HTTPPort())
 
 	// HTTPS
 	if a.IsSSLEnabled() {
@@ -142,14 +174,40 @@ func (a *Application) Shutdown() {
 	// Publish `OnPreShutdown` event
 	a.EventStore().sortAndPublishSync(&Event{Name: EventOnPreShutdown})
 
-	ctx, cancel := context.WithTimeout(context.Background(), a.settings.ShutdownGraceTimeout)
+	// Readiness fails from now on, so load balancers drain the traffic
+	a.drainHealth()
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.Timeouts().GraceTimeout())
 	defer cancel()
 
-	a.Log().Warn("aah go server graceful shutdown triggered with timeout of ", a.settings.ShutdownGraceTimeStr)
+	a.Log().Warn("aah go server graceful shutdown triggered with timeout of ", a.settings().ShutdownGraceTimeStr)
+
+	// WebSocket connections are hijacked, so not tracked by go server
+	if a.wse != nil {
+		a.wse.Shutdown(ctx)
+	}
+
 	if err := a.server.Shutdown(ctx); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
+	a.shutdownListeners(ctx)
+	if err := a.goGroup.stop(ctx); err != nil {
+		a.Log().Warnf("Background goroutines did not complete within grace timeout: %d running", a.GoroutineMetrics().Running)
+	}
 	a.shutdownRedirectServer()
+	a.shutdownHTTP3()
+
+	// In-flight requests are completed, buffered request logs are written
+	// fully irrespective of grace timeout
+	if err := a.flushRequestLogs(context.Background()); err != nil {
+		a.Log().Error(err)
+	}
+	if a.cfgWatcher != nil {
+		a.cfgWatcher.Stop()
+	}
+	if a.cfgPoller != nil {
+		a.cfgPoller.Stop()
+	}
 	a.Log().Info("aah go server shutdown successfully")
 
 	// Publish `OnPostShutdown` event
@@ -162,7 +220,8 @@ func (a *Application) Shutdown() {
 
 func (a *Application) writePID() {
 	// Get the application PID
-	a.settings.Pid = os.Getpid()
+	pid := os.Getpid()
+	a.settingsHolder.Update(func(s *settings.Settings) { s.Pid = pid })
 
 	pidFile := a.Config().StringDefault("pid_file", "")
 	if ess.IsStrEmpty(pidFile) {
@@ -173,25 +232,40 @@ func (a *Application) writePID() {
 		pidFile += ".pid"
 	}
 
-	if err := ioutil.WriteFile(pidFile, []byte(strconv.Itoa(a.settings.Pid)), 0644); err != nil {
+	if err := ioutil.WriteFile(pidFile, []byte(strconv.Itoa(a.settings().Pid)), 0644); err != nil {
 		a.Log().Error(err)
 	}
 }
 
 func (a *Application) startUnix() {
-	sockFile := a.HTTPAddress()[5:]
-	if err := os.Remove(sockFile); !os.IsNotExist(err) {
-		a.Log().Fatal(err)
-	}
-
-	listener, err := net.Listen("unix", sockFile)
+	listener, err := inheritedListener()
 	if err != nil {
 		a.Log().Fatal(err)
 		return
 	}
+	if listener == nil {
+		listener = a.activatedListener("")
+	}
+	if listener == nil {
+		sockFile := a.HTTPAddress()[5:]
+		if err := os.Remove(sockFile); err != nil && !os.IsNotExist(err) {
+			a.Log().Fatal(err)
+		}
 
+		if listener, err = net.Listen("unix", sockFile); err != nil {
+			a.Log().Fatal(err)
+			return
+		}
+	}
+	a.Lock()
+	a.listener = listener
+	a.Unlock()
+	notifyRestartReady()
+
+	a.configureH2C()
 	a.server.Addr = a.HTTPAddress()
 	a.Log().Infof("aah go server running on %v", a.server.Addr)
+	listener = a.proxyProtocolListener(listener, a.settings().ProxyProtocolEnabled)
 	if err := a.server.Serve(listener); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
@@ -200,19 +274,29 @@ func (a *Application) startUnix() {
 func (a *Application) startHTTPS() {
 	// Add cert, if let's encrypt enabled
 	if a.IsLetsEncryptEnabled() {
-		a.Log().Infof("Let's Encypyt CA Cert enabled")
-		a.server.TLSConfig = a.settings.Autocert.TLSConfig()
-		a.settings.SSLCert, a.settings.SSLKey = "", ""
+		a.Log().Infof("Let's Encypyt CA Cert enabled, challenge: %s", a.settings().LetsEncryptChallenge)
+		tlsCfg, err := a.letsEncryptTLSConfig()
+		if err != nil {
+			a.Log().Error(err)
+			return
+		}
+		a.server.TLSConfig = tlsCfg
+		a.settingsHolder.Update(func(s *settings.Settings) { s.SSLCert, s.SSLKey = "", "" })
 	} else {
 		if a.tlsCfg != nil {
 			a.Log().Info("Adding user provided TLS Config")
 			a.server.TLSConfig = a.tlsCfg
 		}
-		a.Log().Infof("SSLCert: %s, SSLKey: %s", a.settings.SSLCert, a.settings.SSLKey)
+		a.Log().Infof("SSLCert: %s, SSLKey: %s", a.settings().SSLCert, a.settings().SSLKey)
+	}
+
+	if err := a.configureTLS(); err != nil {
+		a.Log().Error(err)
+		return
 	}
 
 	// Disable HTTP/2, if configured
-	if a.Config().BoolDefault("server.ssl.disable_http2", false) {
+	if !a.settings().HTTP2Enabled {
 		// To disable HTTP/2 is-
 		//  - Don't add "h2" to TLSConfig.NextProtos
 		//  - Initialize TLSNextProto with empty map
@@ -227,29 +311,188 @@ func (a *Application) startHTTPS() {
 			a.server.TLSConfig.NextProtos = nextProtos
 		}
 		a.server.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	} else if err := http2.ConfigureServer(a.server, a.http2Server()); err != nil {
+		a.Log().Error(err)
+		return
+	}
+
+	// start HTTP/3 server if enabled
+	if err := a.startHTTP3(); err != nil {
+		a.Log().Error(err)
 	}
 
 	// start HTTP redirect server if enabled
 	go a.startHTTPRedirect()
 
+	listener, err := a.listen("https")
+	if err != nil {
+		a.Log().Error(err)
+		return
+	}
+
 	a.printStartupNote()
-	if err := a.server.ListenAndServeTLS(a.settings.SSLCert, a.settings.SSLKey); err != nil && err != http.ErrServerClosed {
+	if err := a.server.ServeTLS(listener, a.settings().SSLCert, a.settings().SSLKey); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
 }
 
 func (a *Application) startHTTP() {
+	a.configureH2C()
+
+	listener, err := a.listen("http")
+	if err != nil {
+		a.Log().Error(err)
+		return
+	}
+
 	a.printStartupNote()
-	if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := a.server.Serve(listener); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
 }
 
+// configureClientAuth method configures the mutual TLS (mTLS) client
+// certificate verification on HTTPS server as per `server.ssl.client_auth.*`.
+// Verified client certificate is available via `Request.ClientCertificate`.
+func (a *Application) configureClientAuth() error {
+	if !a.settings().SSLClientAuthEnabled {
+		return nil
+	}
+	a.server.TLSConfig.ClientAuth = a.settings().SSLClientAuthMode
+	if !ess.IsStrEmpty(a.settings().SSLClientCACert) {
+		caCert, err := ioutil.ReadFile(a.settings().SSLClientCACert)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("unable to parse SSL client CA cert: %s", a.settings().SSLClientCACert)
+		}
+		a.server.TLSConfig.ClientCAs = pool
+	}
+	a.Log().Infof("SSL client certificate authentication enabled, mode: %s",
+		a.Config().StringDefault("server.ssl.client_auth.mode", "require_and_verify"))
+	return nil
+}
+
+// http2Server method returns the HTTP/2 server settings from config
+// `server.http2.*`.
+func (a *Application) http2Server() *http2.Server {
+	return &http2.Server{
+		MaxConcurrentStreams: uint32(a.settings().HTTP2MaxStreams),
+		MaxReadFrameSize:     uint32(a.settings().HTTP2MaxFrameSize),
+		IdleTimeout:          a.Timeouts().IdleTimeout(),
+	}
+}
+
+// configureH2C method wraps the server handler with HTTP/2 cleartext (h2c)
+// handler if `server.http2.h2c` is enabled. It supports both prior
+// knowledge and HTTP/1.1 `Upgrade: h2c` requests.
+func (a *Application) configureH2C() {
+	if a.settings().H2CEnabled {
+		a.Log().Info("HTTP/2 cleartext (h2c) enabled")
+		a.server.Handler = h2c.NewHandler(a.server.Handler, a.http2Server())
+	}
+}
+
+// listen method creates the TCP listener for server address. If the
+// `server.port` is `0` then OS assigns the ephemeral port, bound address
+// is published as per `server.bound_address { ... }`. On zero-downtime
+// restart, listener inherited from old process is used. With
+// `server.socket_activation`, listener passed by systemd is used.
+func (a *Application) listen(scheme string) (net.Listener, error) {
+	listener, err := inheritedListener()
+	if err != nil {
+		return nil, err
+	}
+	if listener == nil {
+		listener = a.activatedListener("")
+	}
+	if listener == nil {
+		network, err := a.HTTPNetwork()
+		if err != nil {
+			return nil, err
+		}
+
+		if listener, err = net.Listen(network, a.server.Addr); err != nil {
+			return nil, err
+		}
+	}
+
+	a.Lock()
+	a.boundAddr = listener.Addr()
+	a.listener = listener
+	a.Unlock()
+	if err = a.publishBoundAddress(scheme); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	notifyRestartReady()
+	return a.proxyProtocolListener(listener, a.settings().ProxyProtocolEnabled), nil
+}
+
+// publishBoundAddress method writes the server bound address in JSON form
+// into file, stdout and sets the port into environment variable, it's
+// useful for test harnesses and orchestrators that assign ports dynamically.
+//
+//	server {
+//	  port = "0"
+//	  bound_address {
+//	    file = "/var/run/myapp/address.json"
+//	    stdout = true
+//	    env = "MYAPP_PORT"
+//	  }
+//	}
+func (a *Application) publishBoundAddress(scheme string) error {
+	keyPrefix := "server.bound_address"
+	cfg := a.Config()
+	if !cfg.IsExists(keyPrefix) {
+		return nil
+	}
+
+	tcpAddr, ok := a.BoundAddr().(*net.TCPAddr)
+	if !ok {
+		return nil
+	}
+	host := a.HTTPAddress()
+	if ess.IsStrEmpty(host) {
+		host = tcpAddr.IP.String()
+	}
+	info := &boundAddress{
+		Scheme:  scheme,
+		Address: host,
+		Port:    tcpAddr.Port,
+		URL:     fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(tcpAddr.Port))),
+		Pid:     os.Getpid(),
+	}
+	b, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	if envName := cfg.StringDefault(keyPrefix+".env", ""); !ess.IsStrEmpty(envName) {
+		if err = os.Setenv(envName, strconv.Itoa(tcpAddr.Port)); err != nil {
+			return fmt.Errorf("'%s.env': %s", keyPrefix, err)
+		}
+	}
+
+	if file := cfg.StringDefault(keyPrefix+".file", ""); !ess.IsStrEmpty(file) {
+		if err = ioutil.WriteFile(file, append(b, '\n'), 0644); err != nil {
+			return fmt.Errorf("'%s.file': %s", keyPrefix, err)
+		}
+	}
+
+	if cfg.BoolDefault(keyPrefix+".stdout", false) {
+		fmt.Fprintln(os.Stdout, string(b))
+	}
+	return nil
+}
+
 func (a *Application) startHTTPRedirect() {
 	cfg := a.Config()
 	keyPrefix := "server.ssl.redirect_http"
 	if !cfg.BoolDefault(keyPrefix+".enable", false) {
-		if a.IsLetsEncryptEnabled() {
+		if a.IsLetsEncryptEnabled() && a.settings().LetsEncryptChallenge != "dns-01" {
 			a.Log().Fatalf("Enable HTTP => HTTPS redirect (server.ssl.redirect_http), its required by Let's Encrypt. " +
 				" Read more https://community.letsencrypt.org/t/important-what-you-need-to-know-about-tls-sni-validation-issues/50811, " +
 				"https://github.com/golang/go/issues/21890")
@@ -266,9 +509,15 @@ func (a *Application) startHTTPRedirect() {
 	}
 	redirectCode := cfg.IntDefault(keyPrefix+".code", http.StatusTemporaryRedirect)
 
-	a.Log().Infof("aah go redirect server running on %s:%s", address, fromPort)
+	network, err := a.HTTPNetwork()
+	if err != nil {
+		a.Log().Errorf("%s, unable to start redirect server", err)
+		return
+	}
+
+	a.Log().Infof("aah go redirect server running on %s", net.JoinHostPort(address, fromPort))
 	a.redirectServer = &http.Server{
-		Addr: address + ":" + fromPort,
+		Addr: net.JoinHostPort(address, fromPort),
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.Method != ahttp.MethodGet && r.Method != ahttp.MethodHead {
 				http.Error(w, "Use HTTPS", http.StatusBadRequest)
@@ -279,7 +528,12 @@ func (a *Application) startHTTPRedirect() {
 		}),
 	}
 
-	if err := a.redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	listener, err := net.Listen(network, a.redirectServer.Addr)
+	if err != nil {
+		a.Log().Error(err)
+		return
+	}
+	if err := a.redirectServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 		a.Log().Error(err)
 	}
 }
@@ -294,7 +548,20 @@ func (a *Application) printStartupNote() {
 	port := firstNonZeroString(
 		a.Config().StringDefault("server.port", settings.DefaultHTTPPort),
 		a.Config().StringDefault("server.proxyport", ""))
-	a.Log().Infof("aah go server running on %s:%s", a.HTTPAddress(), a.parsePort(port))
+	if tcpAddr, ok := a.BoundAddr().(*net.TCPAddr); ok && port == "0" {
+		port = strconv.Itoa(tcpAddr.Port)
+	}
+	a.Log().Infof("aah go server running on %s", net.JoinHostPort(a.HTTPAddress(), a.parsePort(port)))
+}
+
+// boundAddress holds the server bound address details, it's published in
+// JSON form.
+type boundAddress struct {
+	Scheme  string `json:"scheme"`
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	URL     string `json:"url"`
+	Pid     int    `json:"pid"`
 }
 
 func parseHost(address, toPort string) string {
@@ -304,9 +571,12 @@ func parseHost(address, toPort string) string {
 	}
 
 	if ess.IsStrEmpty(toPort) {
+		if strings.Contains(host, ":") {
+			return "[" + host + "]"
+		}
 		return host
 	}
-	return host + ":" + toPort
+	return net.JoinHostPort(host, toPort)
 }
 
 func firstNonZeroString(values ...string) string {
@@ -5,14 +5,30 @@
 package aah
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"aahframe.work/config"
 	"aahframe.work/essentials"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
 )
 
 func TestServerStartHTTP(t *testing.T) {
@@ -85,3 +101,301 @@ func TestServerHTTPRedirect(t *testing.T) {
 	assert.Equal(t, 307, resp.StatusCode)
 	assert.True(t, strings.Contains(responseBody(resp), "Temporary Redirect"))
 }
+
+func TestServerEphemeralPort(t *testing.T) {
+	addrFile := filepath.Join(testdataBaseDir(), "bound-address.json")
+	defer ess.DeleteFiles(addrFile)
+
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	cfg, _ := config.ParseString(`
+	server {
+	  address = "127.0.0.1"
+	  port = "0"
+	  bound_address {
+	    file = "` + filepath.ToSlash(addrFile) + `"
+	    env = "AAH_TEST_BOUND_PORT"
+	  }
+	}
+	`)
+	err := a.Config().Merge(cfg)
+	assert.Nil(t, err)
+	defer os.Unsetenv("AAH_TEST_BOUND_PORT")
+
+	assert.Nil(t, a.BoundAddr())
+	a.server = &http.Server{Addr: a.HTTPAddress() + ":" + a.HTTPPort()}
+	listener, err := a.listen("http")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	port := a.BoundAddr().(*net.TCPAddr).Port
+	assert.True(t, port > 0)
+	assert.Equal(t, strconv.Itoa(port), os.Getenv("AAH_TEST_BOUND_PORT"))
+
+	b, err := ioutil.ReadFile(addrFile)
+	assert.Nil(t, err)
+	info := &boundAddress{}
+	err = json.Unmarshal(b, info)
+	assert.Nil(t, err)
+	assert.Equal(t, "http", info.Scheme)
+	assert.Equal(t, "127.0.0.1", info.Address)
+	assert.Equal(t, port, info.Port)
+	assert.Equal(t, "http://127.0.0.1:"+strconv.Itoa(port), info.URL)
+	assert.Equal(t, os.Getpid(), info.Pid)
+}
+
+func TestServerNetwork(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+
+	network, err := a.HTTPNetwork()
+	assert.Nil(t, err)
+	assert.Equal(t, "tcp", network)
+
+	a.Config().SetString("server.network", "tcp6")
+	network, err = a.HTTPNetwork()
+	assert.Nil(t, err)
+	assert.Equal(t, "tcp6", network)
+
+	a.Config().SetString("server.network", "udp")
+	_, err = a.HTTPNetwork()
+	assert.Equal(t, "'server.network' has unsupported value 'udp', supported values are tcp4, tcp6 and dual", err.Error())
+
+	a.Config().SetString("server.address", "[::1]")
+	assert.Equal(t, "::1", a.HTTPAddress())
+
+	// IPv4-only listener
+	a.Config().SetString("server.network", "tcp4")
+	a.Config().SetString("server.address", "127.0.0.1")
+	a.server = &http.Server{Addr: net.JoinHostPort(a.HTTPAddress(), "0")}
+	listener, err := a.listen("http")
+	assert.Nil(t, err)
+	assert.NotNil(t, a.BoundAddr().(*net.TCPAddr).IP.To4())
+	_ = listener.Close()
+
+	// redirect host formatting
+	assert.Equal(t, "[::1]:8443", parseHost("[::1]:8080", "8443"))
+	assert.Equal(t, "[::1]", parseHost("[::1]:8080", ""))
+	assert.Equal(t, "localhost:8443", parseHost("localhost:8080", "8443"))
+	assert.Equal(t, "localhost", parseHost("localhost", "8443"))
+}
+
+func TestServerHTTP2H2C(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	cfg, _ := config.ParseString(`
+	server {
+	  http2 {
+	    h2c = true
+	    max_concurrent_streams = 100
+	    max_read_frame_size = "32kb"
+	  }
+	}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+	assert.True(t, a.settings().HTTP2Enabled)
+	assert.True(t, a.settings().H2CEnabled)
+	assert.Equal(t, 100, a.settings().HTTP2MaxStreams)
+	assert.Equal(t, 32768, a.settings().HTTP2MaxFrameSize)
+
+	a.server = &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})}
+	a.configureH2C()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() { _ = a.server.Serve(listener) }()
+	defer a.server.Close()
+
+	// HTTP/2 with prior knowledge over cleartext
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	resp, err := client.Get("http://" + listener.Addr().String())
+	assert.Nil(t, err)
+	assert.Equal(t, "HTTP/2.0", responseBody(resp))
+
+	// invalid values
+	a.Config().SetInt("server.http2.max_concurrent_streams", 0)
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.True(t, strings.Contains(err.Error(), "server.http2.max_concurrent_streams"))
+
+	a.Config().SetInt("server.http2.max_concurrent_streams", 100)
+	a.Config().SetString("server.http2.max_read_frame_size", "16mb")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.http2.max_read_frame_size' value must be at least 16kb and less than 16mb", err.Error())
+}
+
+func TestServerListeners(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+	sockFile := filepath.Join(os.TempDir(), "aah-listeners-test.sock")
+	defer ess.DeleteFiles(sockFile)
+	cfg, _ := config.ParseString(`
+	server {
+	  listeners {
+	    nginx {
+	      address = "unix:` + sockFile + `"
+	      timeout {
+	        write = "2m"
+	      }
+	    }
+	    internal {
+	      address = "127.0.0.1"
+	      port = "0"
+	      timeout {
+	        read = "5s"
+	      }
+	    }
+	  }
+	}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+	assert.Equal(t, 2, len(a.settings().Listeners))
+	internal, nginx := a.settings().Listeners[0], a.settings().Listeners[1]
+	assert.Equal(t, "internal", internal.Name)
+	assert.Equal(t, "tcp", internal.Network)
+	assert.Equal(t, "127.0.0.1:0", internal.Address)
+	assert.Equal(t, 5*time.Second, internal.ReadTimeout)
+	assert.Equal(t, a.settings().HTTPWriteTimeout, internal.WriteTimeout)
+	assert.True(t, nginx.IsUnix())
+	assert.Equal(t, sockFile, nginx.Address)
+	assert.Equal(t, a.settings().HTTPReadTimeout, nginx.ReadTimeout)
+	assert.Equal(t, 2*time.Minute, nginx.WriteTimeout)
+
+	a.server = &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("listener ok"))
+	})}
+	assert.Nil(t, a.startListeners())
+	defer a.shutdownListeners(context.Background())
+	assert.Equal(t, 2, len(a.listenerSrvs))
+	assert.Equal(t, 5*time.Second, a.listenerSrvs[0].ReadTimeout)
+
+	// request over unix domain socket
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+			return net.Dial("unix", sockFile)
+		},
+	}}
+	var resp *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Get("http://unix/"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Nil(t, err)
+	assert.Equal(t, "listener ok", responseBody(resp))
+
+	// invalid values
+	a.Config().SetString("server.listeners.internal.port", "")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.listeners.internal.port' is required for TCP listener", err.Error())
+
+	a.Config().SetString("server.listeners.internal.port", "9090")
+	a.Config().SetString("server.listeners.nginx.timeout.write", "2d")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.listeners.nginx.timeout.write' value is not a valid time unit", err.Error())
+}
+
+func TestServerTimeouts(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+
+	timeouts := a.Timeouts()
+	assert.Equal(t, 90*time.Second, timeouts.ReadTimeout())
+	assert.Equal(t, 90*time.Second, timeouts.ReadHeaderTimeout())
+	assert.Equal(t, 90*time.Second, timeouts.IdleTimeout())
+	assert.Equal(t, 90*time.Second, timeouts.RequestTimeout())
+	assert.Equal(t, 60*time.Second, timeouts.GraceTimeout())
+
+	a.Config().SetString("server.timeout.read_header", "500ms")
+	a.Config().SetString("server.timeout.idle", "2m")
+	a.Config().SetString("server.timeout.grace_shutdown", "2h")
+	err := a.settingsHolder.Refresh(a.Config())
+	assert.Nil(t, err)
+	timeouts = a.Timeouts()
+	assert.Equal(t, 500*time.Millisecond, timeouts.ReadHeaderTimeout())
+	assert.Equal(t, 2*time.Minute, timeouts.IdleTimeout())
+	assert.Equal(t, 2*time.Hour, timeouts.GraceTimeout())
+	assert.Equal(t, 2*time.Minute, a.http2Server().IdleTimeout)
+
+	// invalid grace shutdown falls back to default value
+	a.Config().SetString("server.timeout.grace_shutdown", "2d")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute, a.Timeouts().GraceTimeout())
+
+	a.Config().SetString("server.timeout.read_header", "-1s")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.NotNil(t, err)
+}
+
+func TestServerClientAuth(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	a := newTestApp(t, importPath)
+
+	caFile := filepath.Join(t.TempDir(), "client-ca.pem")
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "aah test client CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+
+	cfg, _ := config.ParseString(`
+	server {
+	  ssl {
+	    enable = true
+	    cert = "` + caFile + `"
+	    key = "` + caFile + `"
+	    client_auth {
+	      enable = true
+	      ca_cert = "` + caFile + `"
+	      mode = "verify_if_given"
+	    }
+	  }
+	}
+	`)
+	assert.Nil(t, a.Config().Merge(cfg))
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+	assert.True(t, a.settings().SSLClientAuthEnabled)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, a.settings().SSLClientAuthMode)
+
+	a.server = &http.Server{}
+	assert.Nil(t, a.configureTLS())
+	assert.Equal(t, tls.VerifyClientCertIfGiven, a.server.TLSConfig.ClientAuth)
+	assert.NotNil(t, a.server.TLSConfig.ClientCAs)
+
+	// invalid values
+	a.Config().SetString("server.ssl.client_auth.mode", "strict")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.True(t, strings.HasPrefix(err.Error(), "'server.ssl.client_auth.mode' has unsupported value 'strict'"))
+
+	a.Config().SetString("server.ssl.client_auth.mode", "require_and_verify")
+	a.Config().SetString("server.ssl.client_auth.ca_cert", "")
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.ssl.client_auth.ca_cert' is required for mode 'require_and_verify'", err.Error())
+
+	a.Config().SetString("server.ssl.client_auth.mode", "request")
+	assert.Nil(t, a.settingsHolder.Refresh(a.Config()))
+	assert.Equal(t, tls.RequestClientCert, a.settings().SSLClientAuthMode)
+
+	a.Config().SetBool("server.ssl.enable", false)
+	err = a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.ssl.client_auth.enable' requires TLS, enable 'server.ssl.enable'", err.Error())
+}
@@ -0,0 +1,381 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package soap provides SOAP 1.1 and 1.2 envelope parsing, encoding and
+// fault handling for legacy integrations served by aah application.
+// Body payload is a regular `encoding/xml` struct.
+//
+//	type GetOrder struct {
+//		XMLName xml.Name `xml:"urn:orders GetOrder"`
+//		ID      string   `xml:"id"`
+//	}
+//
+//	env, err := soap.Decode(r.Body)
+//	req := &GetOrder{}
+//	err = env.DecodeBody(req)
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// SOAP envelope namespaces.
+const (
+	Namespace11 = "http://schemas.xmlsoap.org/soap/envelope/"
+	Namespace12 = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// Fault codes, these are SOAP 1.1 names and mapped to SOAP 1.2 names
+// `Sender` and `Receiver` on encoding.
+const (
+	FaultVersionMismatch = "VersionMismatch"
+	FaultMustUnderstand  = "MustUnderstand"
+	FaultClient          = "Client"
+	FaultServer          = "Server"
+)
+
+// Errors
+var (
+	ErrInvalidEnvelope = errors.New("soap: invalid envelope")
+	ErrEmptyBody       = errors.New("soap: body is empty")
+)
+
+// Version type represents the SOAP version.
+type Version uint8
+
+// SOAP versions
+const (
+	V11 Version = iota + 1
+	V12
+)
+
+// VersionOf method returns the SOAP version for given HTTP content type,
+// `application/soap+xml` is SOAP 1.2 otherwise SOAP 1.1.
+func VersionOf(contentType string) Version {
+	if mt, _, _ := mime.ParseMediaType(contentType); mt == "application/soap+xml" {
+		return V12
+	}
+	return V11
+}
+
+// ContentType method returns the HTTP content type of SOAP version.
+func (v Version) ContentType() string {
+	if v == V12 {
+		return "application/soap+xml; charset=utf-8"
+	}
+	return "text/xml; charset=utf-8"
+}
+
+// Namespace method returns the envelope namespace of SOAP version.
+func (v Version) Namespace() string {
+	if v == V12 {
+		return Namespace12
+	}
+	return Namespace11
+}
+
+// String method is stringer interface.
+func (v Version) String() string {
+	if v == V12 {
+		return "1.2"
+	}
+	return "1.1"
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Envelope
+//______________________________________________________________________________
+
+// Envelope is the parsed SOAP envelope, header and body contents are kept
+// as raw XML to decode into application types.
+type Envelope struct {
+	XMLName xml.Name   `xml:"Envelope"`
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Header  *Header    `xml:"Header"`
+	Body    Body       `xml:"Body"`
+}
+
+// Header holds the raw XML of SOAP header entries.
+type Header struct {
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content []byte     `xml:",innerxml"`
+}
+
+// Body holds the raw XML of SOAP body.
+type Body struct {
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content []byte     `xml:",innerxml"`
+}
+
+// Decode method parses the SOAP envelope from given reader.
+func Decode(r io.Reader) (*Envelope, error) {
+	env := &Envelope{}
+	if err := xml.NewDecoder(r).Decode(env); err != nil {
+		return nil, fmt.Errorf("soap: %v", err)
+	}
+	if env.XMLName.Space != Namespace11 && env.XMLName.Space != Namespace12 {
+		return nil, ErrInvalidEnvelope
+	}
+	return env, nil
+}
+
+// Version method returns the SOAP version of envelope.
+func (e *Envelope) Version() Version {
+	if e.XMLName.Space == Namespace12 {
+		return V12
+	}
+	return V11
+}
+
+// DecodeHeader method unmarshals the SOAP header into given value, header
+// is optional so it does nothing if envelope doesn't have one.
+func (e *Envelope) DecodeHeader(v interface{}) error {
+	if e.Header == nil {
+		return nil
+	}
+	d, se, err := e.element(e.Header.Content, e.Header.Attrs)
+	if err == ErrEmptyBody {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return decodeElement(d, se, v)
+}
+
+// DecodeBody method unmarshals the first body element into given value.
+// It returns `*Fault` as an error if the body is a SOAP fault.
+func (e *Envelope) DecodeBody(v interface{}) error {
+	d, se, err := e.element(e.Body.Content, e.Body.Attrs)
+	if err != nil {
+		return err
+	}
+	if se.Name.Local == "Fault" && se.Name.Space == e.XMLName.Space {
+		fi := &faultIn{}
+		if err = decodeElement(d, se, fi); err != nil {
+			return err
+		}
+		return fi.fault()
+	}
+	return decodeElement(d, se, v)
+}
+
+// element method returns the decoder positioned at first element of raw
+// XML content. Content is wrapped with namespace declarations of envelope
+// and given parent, so the prefixes are resolved same as in request.
+func (e *Envelope) element(content []byte, attrs []xml.Attr) (*xml.Decoder, xml.StartElement, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("<scope")
+	for _, a := range append(append([]xml.Attr{}, e.Attrs...), attrs...) {
+		switch {
+		case a.Name.Space == "xmlns":
+			buf.WriteString(" xmlns:" + a.Name.Local + `="`)
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			buf.WriteString(` xmlns="`)
+		default:
+			continue
+		}
+		_ = xml.EscapeText(buf, []byte(a.Value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+	buf.Write(content)
+	buf.WriteString("</scope>")
+
+	d := xml.NewDecoder(buf)
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, xml.StartElement{}, fmt.Errorf("soap: %v", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 1 {
+				return d, t, nil
+			}
+			depth++
+		case xml.EndElement:
+			return nil, xml.StartElement{}, ErrEmptyBody
+		}
+	}
+}
+
+// Encode method writes the SOAP envelope of given version with header and
+// body into writer. Header is optional, pass nil to omit it. Body is
+// encoded as fault if it's `*Fault`.
+func Encode(w io.Writer, v Version, header, body interface{}) error {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(buf, `<soap:Envelope xmlns:soap="%s">`, v.Namespace())
+	if header != nil {
+		buf.WriteString("<soap:Header>")
+		if err := xml.NewEncoder(buf).Encode(header); err != nil {
+			return err
+		}
+		buf.WriteString("</soap:Header>")
+	}
+
+	buf.WriteString("<soap:Body>")
+	if f, ok := body.(*Fault); ok {
+		body = f.encodable(v)
+	}
+	if body != nil {
+		if err := xml.NewEncoder(buf).Encode(body); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("</soap:Body></soap:Envelope>")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Fault
+//______________________________________________________________________________
+
+// Fault is the SOAP fault, it's encoded as per envelope version. On decoding
+// `Detail` holds the raw inner XML of fault detail as `[]byte`.
+type Fault struct {
+	Code   string
+	String string
+	Actor  string
+	Detail interface{}
+}
+
+// NewFault method creates the SOAP fault for given code and reason.
+func NewFault(code, reason string) *Fault {
+	return &Fault{Code: code, String: reason}
+}
+
+// Error method is error interface.
+func (f *Fault) Error() string {
+	return fmt.Sprintf("soap: fault %s: %s", f.Code, f.String)
+}
+
+// StatusCode method returns the HTTP status code of fault for given SOAP
+// version. SOAP 1.2 sender fault is `400`, all others are `500`.
+func (f *Fault) StatusCode(v Version) int {
+	if v == V12 && f.Code == FaultClient {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func (f *Fault) encodable(v Version) interface{} {
+	var detail *faultDetail
+	switch d := f.Detail.(type) {
+	case nil:
+	case []byte:
+		detail = &faultDetail{Raw: d}
+	default:
+		detail = &faultDetail{Value: d}
+	}
+
+	if v == V12 {
+		code := f.Code
+		switch code {
+		case FaultClient:
+			code = "Sender"
+		case FaultServer:
+			code = "Receiver"
+		}
+		f12 := &fault12{Code: "soap:" + code, Role: f.Actor, Detail: detail}
+		f12.Reason.Text.Lang, f12.Reason.Text.Value = "en", f.String
+		return f12
+	}
+	return &fault11{Code: "soap:" + f.Code, String: f.String, Actor: f.Actor, Detail: detail}
+}
+
+// fault11 and fault12 are encoding types, element names carry the `soap`
+// prefix declared on envelope.
+type fault11 struct {
+	XMLName xml.Name     `xml:"soap:Fault"`
+	Code    string       `xml:"faultcode"`
+	String  string       `xml:"faultstring"`
+	Actor   string       `xml:"faultactor,omitempty"`
+	Detail  *faultDetail `xml:"detail"`
+}
+
+type fault12 struct {
+	XMLName xml.Name `xml:"soap:Fault"`
+	Code    string   `xml:"soap:Code>soap:Value"`
+	Reason  struct {
+		Text struct {
+			Lang  string `xml:"xml:lang,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"soap:Text"`
+	} `xml:"soap:Reason"`
+	Role   string       `xml:"soap:Role,omitempty"`
+	Detail *faultDetail `xml:"soap:Detail"`
+}
+
+type faultDetail struct {
+	Value interface{}
+	Raw   []byte `xml:",innerxml"`
+}
+
+// faultIn is the decoding type of both SOAP 1.1 and 1.2 fault.
+type faultIn struct {
+	Code11   string       `xml:"faultcode"`
+	String11 string       `xml:"faultstring"`
+	Actor11  string       `xml:"faultactor"`
+	Detail11 *faultDetail `xml:"detail"`
+	Code12   string       `xml:"Code>Value"`
+	Reason12 string       `xml:"Reason>Text"`
+	Role12   string       `xml:"Role"`
+	Detail12 *faultDetail `xml:"Detail"`
+}
+
+func (fi *faultIn) fault() *Fault {
+	f := &Fault{}
+	if len(fi.Code12) > 0 {
+		switch code := localName(fi.Code12); code {
+		case "Sender":
+			f.Code = FaultClient
+		case "Receiver":
+			f.Code = FaultServer
+		default:
+			f.Code = code
+		}
+		f.String, f.Actor = fi.Reason12, fi.Role12
+		if fi.Detail12 != nil {
+			f.Detail = fi.Detail12.Raw
+		}
+		return f
+	}
+
+	f.Code, f.String, f.Actor = localName(fi.Code11), fi.String11, fi.Actor11
+	if fi.Detail11 != nil {
+		f.Detail = fi.Detail11.Raw
+	}
+	return f
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func decodeElement(d *xml.Decoder, se xml.StartElement, v interface{}) error {
+	if err := d.DecodeElement(v, &se); err != nil {
+		return fmt.Errorf("soap: %v", err)
+	}
+	return nil
+}
+
+func localName(qname string) string {
+	for i := len(qname) - 1; i >= 0; i-- {
+		if qname[i] == ':' {
+			return qname[i+1:]
+		}
+	}
+	return qname
+}
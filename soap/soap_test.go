@@ -0,0 +1,126 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type getOrder struct {
+	XMLName xml.Name `xml:"urn:orders GetOrder"`
+	ID      string   `xml:"id"`
+}
+
+type authHeader struct {
+	XMLName xml.Name `xml:"urn:orders Auth"`
+	Token   string   `xml:"token"`
+}
+
+type orderError struct {
+	XMLName xml.Name `xml:"urn:orders OrderError"`
+	Reason  string   `xml:"reason"`
+}
+
+func TestSOAPVersion(t *testing.T) {
+	assert.Equal(t, V12, VersionOf("application/soap+xml; charset=utf-8; action=\"urn:GetOrder\""))
+	assert.Equal(t, V11, VersionOf("text/xml; charset=utf-8"))
+	assert.Equal(t, V11, VersionOf(""))
+	assert.Equal(t, "text/xml; charset=utf-8", V11.ContentType())
+	assert.Equal(t, "application/soap+xml; charset=utf-8", V12.ContentType())
+	assert.Equal(t, "1.2", V12.String())
+}
+
+func TestSOAPDecode(t *testing.T) {
+	env, err := Decode(strings.NewReader(`<?xml version="1.0"?>
+<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:o="urn:orders">
+  <soapenv:Header><o:Auth><token>s3cret</token></o:Auth></soapenv:Header>
+  <soapenv:Body>
+    <!-- order lookup -->
+    <o:GetOrder><id>1001</id></o:GetOrder>
+  </soapenv:Body>
+</soapenv:Envelope>`))
+	assert.Nil(t, err)
+	assert.Equal(t, V11, env.Version())
+
+	auth := &authHeader{}
+	assert.Nil(t, env.DecodeHeader(auth))
+	assert.Equal(t, "s3cret", auth.Token)
+
+	req := &getOrder{}
+	assert.Nil(t, env.DecodeBody(req))
+	assert.Equal(t, "1001", req.ID)
+
+	// no header
+	env, err = Decode(strings.NewReader(`<Envelope xmlns="http://www.w3.org/2003/05/soap-envelope"><Body/></Envelope>`))
+	assert.Nil(t, err)
+	assert.Equal(t, V12, env.Version())
+	assert.Nil(t, env.DecodeHeader(auth))
+	assert.Equal(t, ErrEmptyBody, env.DecodeBody(req))
+
+	// invalid envelopes
+	_, err = Decode(strings.NewReader(`<Envelope xmlns="urn:other"><Body/></Envelope>`))
+	assert.Equal(t, ErrInvalidEnvelope, err)
+	_, err = Decode(strings.NewReader(`<Envelope`))
+	assert.True(t, strings.HasPrefix(err.Error(), "soap: "))
+}
+
+func TestSOAPEncode(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, Encode(buf, V11, &authHeader{Token: "abc"}, &getOrder{ID: "1001"}))
+
+	env, err := Decode(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, V11, env.Version())
+	auth, req := &authHeader{}, &getOrder{}
+	assert.Nil(t, env.DecodeHeader(auth))
+	assert.Nil(t, env.DecodeBody(req))
+	assert.Equal(t, "abc", auth.Token)
+	assert.Equal(t, "1001", req.ID)
+}
+
+func TestSOAPFault(t *testing.T) {
+	for _, v := range []Version{V11, V12} {
+		f := NewFault(FaultClient, "order not found")
+		f.Actor = "urn:orders"
+		f.Detail = &orderError{Reason: "unknown id"}
+
+		buf := &bytes.Buffer{}
+		assert.Nil(t, Encode(buf, v, nil, f))
+		if v == V12 {
+			assert.True(t, strings.Contains(buf.String(), "<soap:Value>soap:Sender</soap:Value>"))
+			assert.Equal(t, http.StatusBadRequest, f.StatusCode(v))
+		} else {
+			assert.True(t, strings.Contains(buf.String(), "<faultcode>soap:Client</faultcode>"))
+			assert.Equal(t, http.StatusInternalServerError, f.StatusCode(v))
+		}
+
+		env, err := Decode(buf)
+		assert.Nil(t, err)
+		err = env.DecodeBody(&getOrder{})
+		df, ok := err.(*Fault)
+		assert.True(t, ok, v.String())
+		assert.Equal(t, FaultClient, df.Code)
+		assert.Equal(t, "order not found", df.String)
+		assert.Equal(t, "urn:orders", df.Actor)
+		assert.Equal(t, "soap: fault Client: order not found", df.Error())
+
+		oe := &orderError{}
+		assert.Nil(t, xml.Unmarshal(df.Detail.([]byte), oe))
+		assert.Equal(t, "unknown id", oe.Reason)
+
+		// raw detail is written as is
+		buf.Reset()
+		assert.Nil(t, Encode(buf, v, nil, df))
+		assert.True(t, strings.Contains(buf.String(), "<reason>unknown id</reason>"))
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, NewFault(FaultServer, "db down").StatusCode(V12))
+}
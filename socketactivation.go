@@ -0,0 +1,126 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables and first file descriptor of systemd socket
+// activation, refer to `man sd_listen_fds`.
+const (
+	envListenPID     = "LISTEN_PID"
+	envListenFDs     = "LISTEN_FDS"
+	envListenFDNames = "LISTEN_FDNAMES"
+	sdListenFDsStart = 3
+)
+
+// activateSockets method obtains the listeners passed by systemd socket
+// activation if `server.socket_activation` is true. Listener with
+// `FileDescriptorName=` of additional listener name is used for that
+// listener, otherwise first one is used for the server.
+//
+//	# myapp.socket
+//	[Socket]
+//	ListenStream=8080
+//
+//	[Install]
+//	WantedBy=sockets.target
+func (a *Application) activateSockets() error {
+	if !a.settings().SocketActivation {
+		return nil
+	}
+
+	names, listeners, err := systemdListeners(sdListenFDsStart)
+	if err != nil {
+		return err
+	}
+	if len(listeners) == 0 {
+		a.Log().Warnf("'server.socket_activation' is enabled, however '%s' is not found for the process", envListenFDs)
+		return nil
+	}
+
+	sockets := make(map[string]net.Listener)
+	for _, l := range a.settings().Listeners {
+		for i, name := range names {
+			if name == l.Name && listeners[i] != nil {
+				sockets[l.Name] = listeners[i]
+				listeners[i] = nil
+			}
+		}
+	}
+	for i, l := range listeners {
+		if l == nil {
+			continue
+		}
+		if _, found := sockets[""]; found {
+			a.Log().Warnf("Socket activation: unused listener '%s' on %s, closing it", names[i], l.Addr())
+			_ = l.Close()
+			continue
+		}
+		sockets[""] = l
+	}
+
+	for name, l := range sockets {
+		a.Log().Infof("Socket activation: listener '%s' on %s", firstNonZeroString(name, "server"), l.Addr())
+	}
+
+	a.Lock()
+	a.activeSockets = sockets
+	a.Unlock()
+	return nil
+}
+
+// activatedListener method returns the socket activated listener of given
+// name and removes it, server listener name is empty. It returns nil if
+// not exists.
+func (a *Application) activatedListener(name string) net.Listener {
+	a.Lock()
+	defer a.Unlock()
+	l := a.activeSockets[name]
+	delete(a.activeSockets, name)
+	return l
+}
+
+// systemdListeners method returns the listeners passed by systemd along
+// with file descriptor names. Environment variables are unset, so child
+// processes does not inherit them.
+func systemdListeners(fdStart int) ([]string, []net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv(envListenPID))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil, nil
+	}
+	count, err := strconv.Atoi(os.Getenv(envListenFDs))
+	if err != nil || count < 1 {
+		return nil, nil, nil
+	}
+	fdNames := strings.Split(os.Getenv(envListenFDNames), ":")
+	for _, k := range []string{envListenPID, envListenFDs, envListenFDNames} {
+		_ = os.Unsetenv(k)
+	}
+
+	names := make([]string, count)
+	listeners := make([]net.Listener, 0, count)
+	for i := 0; i < count; i++ {
+		if i < len(fdNames) {
+			names[i] = fdNames[i]
+		}
+		f := os.NewFile(uintptr(fdStart+i), names[i])
+		l, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			for _, v := range listeners {
+				_ = v.Close()
+			}
+			return nil, nil, fmt.Errorf("aah: unable to use socket activated listener fd %d: %s", fdStart+i, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return names, listeners, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSocketActivationListeners(t *testing.T) {
+	// not for this process
+	_ = os.Setenv(envListenPID, "1")
+	_ = os.Setenv(envListenFDs, "1")
+	names, listeners, err := systemdListeners(sdListenFDsStart)
+	assert.Nil(t, err)
+	assert.Nil(t, names)
+	assert.Nil(t, listeners)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	f, err := l.(*net.TCPListener).File()
+	assert.Nil(t, err)
+
+	_ = os.Setenv(envListenPID, strconv.Itoa(os.Getpid()))
+	_ = os.Setenv(envListenFDs, "1")
+	_ = os.Setenv(envListenFDNames, "internal")
+	names, listeners, err = systemdListeners(int(f.Fd()))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"internal"}, names)
+	assert.Equal(t, 1, len(listeners))
+	assert.Equal(t, l.Addr().String(), listeners[0].Addr().String())
+	for _, k := range []string{envListenPID, envListenFDs, envListenFDNames} {
+		_, found := os.LookupEnv(k)
+		assert.False(t, found)
+	}
+
+	a := newApp()
+	a.activeSockets = map[string]net.Listener{"internal": listeners[0]}
+	assert.Nil(t, a.activatedListener(""))
+	assert.Equal(t, listeners[0], a.activatedListener("internal"))
+	assert.Nil(t, a.activatedListener("internal"))
+	_ = listeners[0].Close()
+}
@@ -0,0 +1,212 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+)
+
+// DependencyCheckFunc func type is used to check the connectivity of
+// startup dependency such as database, cache, etc. Returns nil when
+// dependency is available.
+type DependencyCheckFunc func(ctx context.Context) error
+
+// AddDependencyCheck method registers the startup dependency check for given
+// name. It's referred in the config `startup.wait_for.*.name`.
+//
+//	app.AddDependencyCheck("primary", func(ctx context.Context) error {
+//		return db.PingContext(ctx)
+//	})
+func (a *Application) AddDependencyCheck(name string, fn DependencyCheckFunc) {
+	a.Lock()
+	defer a.Unlock()
+	if a.depChecks == nil {
+		a.depChecks = make(map[string]DependencyCheckFunc)
+	}
+	a.depChecks[name] = fn
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initStartup method initializes the startup dependencies from config
+// `startup { ... }`. Type `tcp` checks the address is reachable, any other
+// type (e.g. `db`, `cache`) invokes the check registered via
+// `Application.AddDependencyCheck` for the given name.
+//
+//	startup {
+//	  wait_for {
+//	    postgres {
+//	      type = "tcp"
+//	      addr = "localhost:5432"
+//	    }
+//	    primary_db {
+//	      type = "db"
+//	      name = "primary"
+//	    }
+//	  }
+//	  timeout = "2m"
+//	  attempt_timeout = "5s"
+//	  backoff {
+//	    initial = "500ms"
+//	    max = "10s"
+//	    multiplier = 2
+//	  }
+//	}
+func (a *Application) initStartup() error {
+	keyPrefix := "startup"
+	cfg := a.Config()
+	names := cfg.KeysByPath(keyPrefix + ".wait_for")
+	if len(names) == 0 {
+		a.startup = nil
+		return nil
+	}
+
+	durations := map[string]time.Duration{}
+	for _, k := range []struct {
+		key string
+		def time.Duration
+	}{
+		{"timeout", 2 * time.Minute}, {"attempt_timeout", 5 * time.Second},
+		{"backoff.initial", 500 * time.Millisecond}, {"backoff.max", 10 * time.Second},
+	} {
+		key := keyPrefix + "." + k.key
+		d, err := settings.ParseDuration(cfg, key, k.def)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return fmt.Errorf("'%s' value must be greater than zero", key)
+		}
+		durations[k.key] = d
+	}
+
+	multiplier := cfg.IntDefault(keyPrefix+".backoff.multiplier", 2)
+	if multiplier < 1 {
+		return fmt.Errorf("'%s.backoff.multiplier' value must be greater than or equal to 1", keyPrefix)
+	}
+
+	s := &startupWaiter{
+		timeout:        durations["timeout"],
+		attemptTimeout: durations["attempt_timeout"],
+		initialBackoff: durations["backoff.initial"],
+		maxBackoff:     durations["backoff.max"],
+		multiplier:     multiplier,
+	}
+	for _, name := range names {
+		depKey := keyPrefix + ".wait_for." + name
+		dep := &startupDependency{
+			key:  name,
+			typ:  cfg.StringDefault(depKey+".type", ""),
+			addr: cfg.StringDefault(depKey+".addr", ""),
+			name: cfg.StringDefault(depKey+".name", name),
+		}
+		if ess.IsStrEmpty(dep.typ) {
+			return fmt.Errorf("'%s.type' key is missing", depKey)
+		}
+		if dep.typ == "tcp" && ess.IsStrEmpty(dep.addr) {
+			return fmt.Errorf("'%s.addr' key is missing", depKey)
+		}
+		s.deps = append(s.deps, dep)
+	}
+
+	a.startup = s
+	return nil
+}
+
+// waitForDependencies method waits until all the startup dependencies are
+// available, each dependency is retried with exponential backoff within
+// the `startup.timeout`.
+func (a *Application) waitForDependencies() error {
+	s := a.startup
+	if s == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	for _, dep := range s.deps {
+		check, err := a.dependencyCheck(dep)
+		if err != nil {
+			return err
+		}
+
+		backoff := s.initialBackoff
+		for attempt := 1; ; attempt++ {
+			actx, acancel := context.WithTimeout(ctx, s.attemptTimeout)
+			err = check(actx)
+			acancel()
+			if err == nil {
+				a.Log().Infof("Startup dependency '%s' (%s) is available", dep.key, dep.typ)
+				break
+			}
+
+			a.Log().Warnf("Startup dependency '%s' (%s) is not available, attempt %d, retry in %s: %v",
+				dep.key, dep.typ, attempt, backoff, err)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("startup dependency '%s' (%s) is not available within %s: %v",
+					dep.key, dep.typ, s.timeout, err)
+			case <-time.After(backoff):
+			}
+
+			if backoff *= time.Duration(s.multiplier); backoff > s.maxBackoff {
+				backoff = s.maxBackoff
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Application) dependencyCheck(dep *startupDependency) (DependencyCheckFunc, error) {
+	if dep.typ == "tcp" {
+		return func(ctx context.Context) error {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", dep.addr)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}, nil
+	}
+
+	a.RLock()
+	defer a.RUnlock()
+	check, found := a.depChecks[dep.name]
+	if !found {
+		return nil, fmt.Errorf("startup dependency '%s': check '%s' is not registered, use `AddDependencyCheck`",
+			dep.key, dep.name)
+	}
+	return check, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Startup waiter
+//______________________________________________________________________________
+
+type startupWaiter struct {
+	timeout        time.Duration
+	attemptTimeout time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     int
+	deps           []*startupDependency
+}
+
+type startupDependency struct {
+	key  string
+	typ  string
+	addr string
+	name string
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartupWaitForDependencies(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+
+	assert.Nil(t, setTestConfig(a, fmt.Sprintf(`
+	startup {
+	  wait_for {
+	    postgres {
+	      type = "tcp"
+	      addr = "%s"
+	    }
+	    primary_db {
+	      type = "db"
+	      name = "primary"
+	    }
+	  }
+	  timeout = "5s"
+	  backoff {
+	    initial = "10ms"
+	    max = "20ms"
+	  }
+	}
+	`, ln.Addr().String())))
+	err = a.initStartup()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(a.startup.deps))
+
+	// check is not registered
+	err = a.waitForDependencies()
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "check 'primary' is not registered"))
+
+	attempts := 0
+	a.AddDependencyCheck("primary", func(ctx context.Context) error {
+		if attempts++; attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	err = a.waitForDependencies()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// dependency not available within timeout
+	assert.Nil(t, setTestConfig(a, `
+	startup {
+	  wait_for {
+	    cache {
+	      type = "cache"
+	    }
+	  }
+	  timeout = "50ms"
+	  backoff {
+	    initial = "10ms"
+	  }
+	}
+	`))
+	err = a.initStartup()
+	assert.Nil(t, err)
+	a.AddDependencyCheck("cache", func(ctx context.Context) error {
+		return errors.New("no route to host")
+	})
+	err = a.waitForDependencies()
+	assert.NotNil(t, err)
+	assert.Equal(t, "startup dependency 'cache' (cache) is not available within 50ms: no route to host", err.Error())
+
+	// not configured
+	a.cfg = config.NewEmpty()
+	err = a.initStartup()
+	assert.Nil(t, err)
+	assert.Nil(t, a.waitForDependencies())
+}
+
+func TestStartupConfigErrors(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	for _, c := range []struct{ cfg, err string }{
+		{`startup { wait_for { postgres { addr = "localhost:5432"; } } }`,
+			"'startup.wait_for.postgres.type' key is missing"},
+		{`startup { wait_for { postgres { type = "tcp"; } } }`,
+			"'startup.wait_for.postgres.addr' key is missing"},
+		{`startup {
+			wait_for { primary { type = "db"; } }
+			timeout = "60"
+		}`, "'startup.timeout' value is not a valid time unit"},
+		{`startup {
+			wait_for { primary { type = "db"; } }
+			backoff { multiplier = 0; }
+		}`, "'startup.backoff.multiplier' value must be greater than or equal to 1"},
+	} {
+		assert.Nil(t, setTestConfig(a, c.cfg))
+		err := a.initStartup()
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
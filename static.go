@@ -6,10 +6,12 @@ package aah
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
@@ -21,6 +23,7 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/essentials"
 	"aahframe.work/internal/util"
+	"aahframe.work/router"
 	"aahframe.work/vfs"
 )
 
@@ -36,7 +39,8 @@ func (a *Application) initStatic() error {
 	a.staticMgr = &staticManager{
 		a:                     a,
 		mimeCacheHdrMap:       make(map[string]string),
-		noCacheHdrValue:       "no-cache, no-store, must-revalidate",
+		extCacheHdrMap:        make(map[string]string),
+		noCacheHdrValue:       noCacheControl,
 		dirListDateTimeFormat: "2006-01-02 15:04:05",
 	}
 
@@ -56,6 +60,30 @@ func (a *Application) initStatic() error {
 		}
 	}
 
+	// File extension cache headers, it takes precedence over MIME
+	keyPrefix = "cache.static.extensions"
+	for _, k := range a.Config().KeysByPath(keyPrefix) {
+		exts := strings.Split(a.Config().StringDefault(keyPrefix+"."+k+".ext", ""), ",")
+		for _, e := range exts {
+			if e = strings.TrimSpace(strings.ToLower(e)); !ess.IsStrEmpty(e) {
+				if e[0] != '.' {
+					e = "." + e
+				}
+				hdr := a.Config().StringDefault(keyPrefix+"."+k+".cache_control", a.staticMgr.defaultCacheHdr)
+				a.staticMgr.extCacheHdrMap[e] = hdr
+			}
+		}
+	}
+
+	// Static assets cache busting for reverse URLs
+	a.staticMgr.cacheBust = a.Config().BoolDefault("cache.static.cache_bust.enable", false)
+	a.staticMgr.cacheBustParam = a.Config().StringDefault("cache.static.cache_bust.query_param", "v")
+	if manifestFile := a.Config().StringDefault("cache.static.manifest", ""); !ess.IsStrEmpty(manifestFile) {
+		if err := a.staticMgr.loadManifest(manifestFile); err != nil {
+			return fmt.Errorf("'cache.static.manifest': %s", err)
+		}
+	}
+
 	return nil
 }
 
@@ -65,6 +93,35 @@ type staticManager struct {
 	noCacheHdrValue       string
 	dirListDateTimeFormat string
 	mimeCacheHdrMap       map[string]string
+	extCacheHdrMap        map[string]string
+	cacheBust             bool
+	cacheBustParam        string
+	manifest              map[string]string
+}
+
+// RouteURL method returns the reverse URL of static route with cache busting.
+// Asset file path is resolved to fingerprinted file path via asset manifest
+// `cache.static.manifest` (JSON object of `"css/app.css": "css/app-3f2a1b.css"`,
+// paths relative to static route directory). If asset not found in the manifest
+// and `cache.static.cache_bust.enable = true` then build version is added as
+// query parameter e.g. `/assets/css/app.css?v=1.0.0`.
+func (s *staticManager) RouteURL(host, routeName string, route *router.Route, file string) string {
+	var routeURL string
+	fingerprinted := false
+	if route.IsFile() {
+		routeURL = s.a.Router().CreateRouteURL(host, routeName, nil)
+	} else {
+		file = strings.TrimPrefix(file, "/")
+		if mapped, found := s.manifest[file]; found {
+			file, fingerprinted = mapped, true
+		}
+		routeURL = s.a.Router().CreateRouteURL(host, routeName, nil, file)
+	}
+
+	if fingerprinted || !s.cacheBust || len(routeURL) == 0 || s.a.BuildInfo() == nil {
+		return routeURL
+	}
+	return routeURL + "?" + s.cacheBustParam + "=" + url.QueryEscape(s.a.BuildInfo().Version)
 }
 
 func (s *staticManager) Serve(ctx *Context) error {
@@ -90,13 +147,18 @@ func (s *staticManager) Serve(ctx *Context) error {
 
 	gf, ok := f.(vfs.Gziper)
 	var fr io.ReadSeeker = f
-	if s.a.settings.GzipEnabled && ctx.Req.IsGzipAccepted {
+	var encoded bool
+	// byte-range request is served without Gzip, since range applies to the
+	// encoded content
+	if s.a.settings().GzipEnabled && ctx.Req.IsGzipAccepted && !isRangeRequest(ctx.Req) {
 		if ok && gf.IsGzip() {
 			ctx.Res.Header().Add(ahttp.HeaderVary, ahttp.HeaderAcceptEncoding)
 			ctx.Res.Header().Add(ahttp.HeaderContentEncoding, gzipContentEncoding)
 			fr = bytes.NewReader(gf.RawBytes())
-		} else if fi.Size() > defaultGzipMinSize && util.IsGzipWorthForFile(fi.Name()) {
-			ctx.Res = wrapGzipWriter(ctx.Res)
+			encoded = true
+		} else if fi.Size() >= s.a.settings().GzipMinSize && util.IsGzipWorthForFile(fi.Name()) {
+			ctx.Res = wrapGzipWriter(ctx.Res, s.a.settings().GzipLevel)
+			encoded = true
 		}
 	}
 
@@ -111,13 +173,22 @@ func (s *staticManager) Serve(ctx *Context) error {
 
 			// apply cache header if environment profile is `prod`
 			if s.a.IsEnvProfile("prod") {
-				ctx.Res.Header().Set(ahttp.HeaderCacheControl, s.cacheHeader(contentType))
+				ctx.Res.Header().Set(ahttp.HeaderCacheControl, s.cacheHeader(fi.Name(), contentType))
 			} else { // for static files hot-reload
 				ctx.Res.Header().Set(ahttp.HeaderExpires, "0")
 				ctx.Res.Header().Set(ahttp.HeaderCacheControl, s.noCacheHdrValue)
 			}
 		}
 
+		// ETag, conditional request is handled by 'http.ServeContent'
+		if s.a.settings().ETagEnabled {
+			etag := fileETag(fi, s.a.settings().ETagWeak)
+			if encoded {
+				etag = encodedETag(etag, gzipContentEncoding)
+			}
+			ctx.Res.Header().Set(ahttp.HeaderETag, etag)
+		}
+
 		// 'OnPreReply' server extension point
 		s.a.he.publishOnPreReplyEvent(ctx)
 
@@ -172,7 +243,28 @@ func (s *staticManager) open(ctx *Context) (vfs.File, error) {
 	return s.a.VFS().Open(resource)
 }
 
-func (s *staticManager) cacheHeader(contentType string) string {
+func (s *staticManager) loadManifest(manifestFile string) error {
+	var b []byte
+	var err error
+	if filepath.IsAbs(manifestFile) {
+		b, err = ioutil.ReadFile(manifestFile)
+	} else {
+		b, err = s.a.VFS().ReadFile(path.Join(s.a.VirtualBaseDir(), filepath.ToSlash(manifestFile)))
+	}
+	if err != nil {
+		return err
+	}
+
+	s.manifest = make(map[string]string)
+	return json.Unmarshal(b, &s.manifest)
+}
+
+// cacheHeader method returns the `Cache-Control` value of static file by its
+// extension, then by its MIME type otherwise default value.
+func (s *staticManager) cacheHeader(fileName, contentType string) string {
+	if hdrValue, found := s.extCacheHdrMap[strings.ToLower(path.Ext(fileName))]; found {
+		return hdrValue
+	}
 	if hdrValue, found := s.mimeCacheHdrMap[util.OnlyMIME(contentType)]; found {
 		return hdrValue
 	}
@@ -236,10 +328,10 @@ func parseCacheBustPart(name, part string) string {
 }
 
 // wrapGzipWriter method writes respective header for gzip and wraps write into
-// gzip writer.
-func wrapGzipWriter(res ahttp.ResponseWriter) ahttp.ResponseWriter {
+// gzip writer of given compression level.
+func wrapGzipWriter(res ahttp.ResponseWriter, level int) ahttp.ResponseWriter {
 	res.Header().Add(ahttp.HeaderVary, ahttp.HeaderAcceptEncoding)
 	res.Header().Add(ahttp.HeaderContentEncoding, gzipContentEncoding)
 	res.Header().Del(ahttp.HeaderContentLength)
-	return ahttp.WrapGzipWriter(res)
+	return ahttp.WrapGzipWriterLevel(res, level)
 }
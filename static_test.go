@@ -15,6 +15,7 @@ import (
 	"testing"
 
 	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
 	"aahframe.work/internal/util"
 	"github.com/stretchr/testify/assert"
 )
@@ -44,6 +45,16 @@ func TestStaticFilesDelivery(t *testing.T) {
 	assert.True(t, strings.Contains(responseBody(resp), "Minimal aah framework application template CSS."))
 	assert.Equal(t, "no-cache, no-store, must-revalidate", resp.Header.Get(ahttp.HeaderCacheControl))
 
+	// Static File - byte-range request
+	t.Log("Static File - byte-range request")
+	req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/assets/css/aah.css", nil)
+	req.Header.Set(ahttp.HeaderRange, "bytes=3-9")
+	resp, err = httpClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "bytes 3-9/700", resp.Header.Get(ahttp.HeaderContentRange))
+	assert.Equal(t, "Minimal", responseBody(resp))
+
 	// Directory Listing - /assets
 	t.Log("Directory Listing - /assets")
 	resp, err = httpClient.Get(ts.URL + "/assets")
@@ -152,17 +163,22 @@ func TestStaticCacheHeader(t *testing.T) {
 		defaultCacheHdr: "public, max-age=31536000",
 	}
 
-	str := sm.cacheHeader("application/json")
+	str := sm.cacheHeader("data.json", "application/json")
 	assert.Equal(t, "public, max-age=31536000", str)
 
-	str = sm.cacheHeader("image/png")
+	str = sm.cacheHeader("logo.png", "image/png")
 	assert.Equal(t, "public, max-age=604800, proxy-revalidate", str)
 
-	str = sm.cacheHeader("application/json; charset=utf-8")
+	str = sm.cacheHeader("data.json", "application/json; charset=utf-8")
 	assert.Equal(t, "public, max-age=31536000", str)
 
-	str = sm.cacheHeader("text/css")
+	str = sm.cacheHeader("app.css", "text/css")
 	assert.Equal(t, "public, max-age=604800, proxy-revalidate", str)
+
+	// file extension takes precedence over mime type
+	sm.extCacheHdrMap = map[string]string{".woff2": "public, max-age=31536000, immutable"}
+	str = sm.cacheHeader("fonts/Roboto.WOFF2", "font/woff2")
+	assert.Equal(t, "public, max-age=31536000, immutable", str)
 }
 
 func TestStaticWriteFileError(t *testing.T) {
@@ -183,3 +199,44 @@ func TestStaticWriteFileError(t *testing.T) {
 	sm.writeError(ahttp.AcquireResponseWriter(w2), ahttp.AcquireRequest(req), nil)
 	assert.Equal(t, "500 Internal Server Error", responseBody(w2.Result()))
 }
+
+func TestStaticRouteURLCacheBust(t *testing.T) {
+	importPath := filepath.Join(testdataBaseDir(), "webapp1")
+	ts := newTestServer(t, importPath)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Static Route URL Cache Bust]: %s", ts.URL)
+
+	manifestFile := filepath.Join(testdataBaseDir(), "asset-manifest.json")
+	defer ess.DeleteFiles(manifestFile)
+	err := ioutil.WriteFile(manifestFile, []byte(`{"css/aah.css": "css/aah-3f2a1b.css"}`), 0644)
+	assert.Nil(t, err)
+
+	ts.app.Config().SetString("cache.static.manifest", manifestFile)
+	ts.app.Config().SetBool("cache.static.cache_bust.enable", true)
+	err = ts.app.initStatic()
+	assert.Nil(t, err)
+	err = ts.app.initView()
+	assert.Nil(t, err)
+
+	vm := ts.app.viewMgr
+	viewArgs := map[string]interface{}{"Host": "localhost:8080"}
+
+	assert.Equal(t, "//localhost:8080/assets/css/aah-3f2a1b.css", string(vm.tmplURL(viewArgs, "public_assets", "css/aah.css")))
+	assert.Equal(t, "//localhost:8080/assets/js/aah.js?v=1.0.0", string(vm.tmplURL(viewArgs, "public_assets", "js/aah.js")))
+	assert.Equal(t, "//localhost:8080/favicon.ico?v=1.0.0", string(vm.tmplURL(viewArgs, "favicon")))
+	assert.Equal(t, "//localhost:8080/assets/js/aah.js?v=1.0.0", string(vm.tmplURLm(viewArgs, "public_assets",
+		map[string]interface{}{"filepath": "js/aah.js"})))
+
+	// cache bust not enabled
+	ts.app.Config().SetBool("cache.static.cache_bust.enable", false)
+	err = ts.app.initStatic()
+	assert.Nil(t, err)
+	assert.Equal(t, "//localhost:8080/assets/js/aah.js", string(vm.tmplURL(viewArgs, "public_assets", "js/aah.js")))
+
+	// invalid manifest
+	ts.app.Config().SetString("cache.static.manifest", "static/not-exists.json")
+	err = ts.app.initStatic()
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "'cache.static.manifest': "))
+}
@@ -0,0 +1,2 @@
+-- default users of dev profile
+INSERT INTO users (id, name) VALUES (1, 'aah');
@@ -0,0 +1,113 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/tls"
+)
+
+// TLS profiles of config `server.ssl.profile`, based on Mozilla server side
+// TLS recommendations.
+const (
+	TLSProfileModern       = "modern"
+	TLSProfileIntermediate = "intermediate"
+	TLSProfileOld          = "old"
+)
+
+var tlsProfileCurves = []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}
+
+var tlsIntermediateCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+}
+
+var tlsProfiles = map[string]*tlsProfile{
+	TLSProfileModern: {
+		minVersion: tls.VersionTLS13,
+		curves:     tlsProfileCurves,
+	},
+	TLSProfileIntermediate: {
+		minVersion:   tls.VersionTLS12,
+		cipherSuites: tlsIntermediateCipherSuites,
+		curves:       tlsProfileCurves,
+	},
+	TLSProfileOld: {
+		minVersion: tls.VersionTLS10,
+		cipherSuites: append(append([]uint16{}, tlsIntermediateCipherSuites...),
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_RSA_WITH_AES_128_CBC_SHA256,
+			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+		),
+		curves:       tlsProfileCurves,
+		preferServer: true,
+	},
+}
+
+// SetTLSConfigFunc method is used to register the hook to customize the HTTPS
+// server TLS config. Hook is called after aah applies the TLS profile
+// `server.ssl.profile`, client auth and Let's Encrypt settings, so it has
+// the final say.
+//
+// For e.g.:
+//	aah.App().SetTLSConfigFunc(func(tlsCfg *tls.Config) {
+//		tlsCfg.MinVersion = tls.VersionTLS12
+//		tlsCfg.SessionTicketsDisabled = true
+//	})
+func (a *Application) SetTLSConfigFunc(fn func(tlsCfg *tls.Config)) {
+	a.tlsCfgFn = fn
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// configureTLS method applies the TLS profile, client certificate
+// authentication and TLS config hook on HTTPS server TLS config.
+func (a *Application) configureTLS() error {
+	if a.server.TLSConfig == nil {
+		a.server.TLSConfig = &tls.Config{}
+	}
+
+	if p, found := tlsProfiles[a.settings().SSLProfile]; found {
+		p.apply(a.server.TLSConfig)
+		a.Log().Infof("TLS profile: %s", a.settings().SSLProfile)
+	}
+
+	if err := a.configureClientAuth(); err != nil {
+		return err
+	}
+
+	if a.tlsCfgFn != nil {
+		a.tlsCfgFn(a.server.TLSConfig)
+	}
+	return nil
+}
+
+type tlsProfile struct {
+	minVersion   uint16
+	cipherSuites []uint16
+	curves       []tls.CurveID
+	preferServer bool
+}
+
+func (p *tlsProfile) apply(tlsCfg *tls.Config) {
+	tlsCfg.MinVersion = p.minVersion
+	tlsCfg.CipherSuites = p.cipherSuites
+	tlsCfg.CurvePreferences = p.curves
+	tlsCfg.PreferServerCipherSuites = p.preferServer
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"aahframe.work/internal/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerTLSProfile(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	// no profile, Go defaults
+	a.server = &http.Server{}
+	assert.Nil(t, a.configureTLS())
+	assert.Equal(t, uint16(0), a.server.TLSConfig.MinVersion)
+	assert.Nil(t, a.server.TLSConfig.CipherSuites)
+
+	a.settingsHolder.Update(func(s *settings.Settings) { s.SSLProfile = TLSProfileModern })
+	a.server = &http.Server{}
+	assert.Nil(t, a.configureTLS())
+	assert.Equal(t, uint16(tls.VersionTLS13), a.server.TLSConfig.MinVersion)
+	assert.Nil(t, a.server.TLSConfig.CipherSuites)
+	assert.Equal(t, []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}, a.server.TLSConfig.CurvePreferences)
+
+	a.settingsHolder.Update(func(s *settings.Settings) { s.SSLProfile = TLSProfileIntermediate })
+	a.server = &http.Server{TLSConfig: &tls.Config{ServerName: "aahframework.org"}}
+	assert.Nil(t, a.configureTLS())
+	assert.Equal(t, uint16(tls.VersionTLS12), a.server.TLSConfig.MinVersion)
+	assert.Equal(t, 6, len(a.server.TLSConfig.CipherSuites))
+	assert.Equal(t, "aahframework.org", a.server.TLSConfig.ServerName)
+	assert.False(t, a.server.TLSConfig.PreferServerCipherSuites)
+
+	a.settingsHolder.Update(func(s *settings.Settings) { s.SSLProfile = TLSProfileOld })
+	a.server = &http.Server{}
+	assert.Nil(t, a.configureTLS())
+	assert.Equal(t, uint16(tls.VersionTLS10), a.server.TLSConfig.MinVersion)
+	assert.Equal(t, 18, len(a.server.TLSConfig.CipherSuites))
+	assert.True(t, a.server.TLSConfig.PreferServerCipherSuites)
+	assert.Equal(t, 6, len(tlsIntermediateCipherSuites))
+
+	// hook has the final say
+	a.SetTLSConfigFunc(func(tlsCfg *tls.Config) {
+		tlsCfg.MinVersion = tls.VersionTLS12
+		tlsCfg.SessionTicketsDisabled = true
+	})
+	a.server = &http.Server{}
+	assert.Nil(t, a.configureTLS())
+	assert.Equal(t, uint16(tls.VersionTLS12), a.server.TLSConfig.MinVersion)
+	assert.True(t, a.server.TLSConfig.SessionTicketsDisabled)
+
+	// invalid profile
+	a.Config().SetString("server.ssl.profile", "legacy")
+	err := a.settingsHolder.Refresh(a.Config())
+	assert.Equal(t, "'server.ssl.profile' has unsupported value 'legacy', supported values are modern, intermediate and old", err.Error())
+}
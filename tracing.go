@@ -0,0 +1,129 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"fmt"
+	"net/http"
+
+	"aahframe.work/tracing"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// AddTracingExporter method adds the span exporter for given name, for e.g.:
+// vendor specific collector. Then configure `name` parameter in the
+// configuration as `server.tracing.exporter = "name"`. Built-in exporters
+// are `otlp` and `log`.
+func (a *Application) AddTracingExporter(name string, e tracing.Exporter) error {
+	if e == nil {
+		return tracing.ErrExporterIsNil
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	if _, found := a.traceExporters[name]; found || name == "otlp" || name == "log" {
+		return fmt.Errorf("aah: tracing exporter '%s' is already added", name)
+	}
+	if a.traceExporters == nil {
+		a.traceExporters = make(map[string]tracing.Exporter)
+	}
+	a.traceExporters[name] = e
+	return nil
+}
+
+// Tracer method returns the application tracer, it's nil if tracing is not
+// enabled, see `server.tracing { ... }`.
+func (a *Application) Tracer() *tracing.Tracer {
+	return a.tracer
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context methods
+//______________________________________________________________________________
+
+// Span method returns the server span of current request, it's nil if
+// tracing is not enabled. Use `tracing.Start` to start the child span.
+//
+//	c, span := tracing.Start(ctx.Req.Context(), "orders.query")
+//	defer span.End()
+func (ctx *Context) Span() *tracing.Span {
+	if ctx.Req == nil {
+		return nil
+	}
+	return tracing.SpanFromContext(ctx.Req.Context())
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initTracing method initializes the request tracing from config
+// `server.tracing { ... }`, previous tracer is stopped after exporting its
+// pending spans.
+func (a *Application) initTracing() error {
+	a.RLock()
+	exporters := make(map[string]tracing.Exporter, len(a.traceExporters))
+	for k, v := range a.traceExporters {
+		exporters[k] = v
+	}
+	a.RUnlock()
+
+	t, err := tracing.New(a.Config(), a.Log(), exporters)
+	if err != nil {
+		return err
+	}
+
+	if a.tracer != nil {
+		a.tracer.Stop()
+	}
+	a.tracer = t
+	if t != nil {
+		a.Go(t.Run)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context Unexported methods
+//______________________________________________________________________________
+
+// startServerSpan method starts the server span of request with W3C trace
+// context of incoming request as parent, span is available via request
+// context.
+func (ctx *Context) startServerSpan(t *tracing.Tracer) *tracing.Span {
+	remote, _ := tracing.Extract(ctx.Req.Header)
+	c, span := t.StartServer(ctx.Req.Context(), "HTTP "+ctx.Req.Method, remote)
+	ctx.Req.SetContext(c)
+	return span
+}
+
+// endServerSpan method names the span by route and adds the status, route
+// and controller attributes then ends it.
+func (ctx *Context) endServerSpan(span *tracing.Span) {
+	status := ctx.Res.Status()
+	span.SetAttribute("http.request.method", ctx.Req.Method)
+	span.SetAttribute("url.path", ctx.Req.Path)
+	span.SetAttribute("http.response.status_code", status)
+	if ctx.route != nil {
+		span.SetName(ctx.Req.Method + " " + ctx.route.Path)
+		span.SetAttribute("http.route", ctx.route.Path)
+		span.SetAttribute("aah.route.name", ctx.route.Name)
+	}
+	if ctx.controller != nil && ctx.action != nil {
+		span.SetAttribute("aah.controller", ctx.controller.FqName)
+		span.SetAttribute("aah.action", ctx.action.Name)
+	}
+	if status >= http.StatusInternalServerError {
+		msg := http.StatusText(status)
+		if err := ctx.Reply().err; err != nil {
+			msg = err.Message
+		}
+		span.SetStatus(tracing.StatusError, msg)
+	}
+	span.End()
+}
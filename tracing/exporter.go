@@ -0,0 +1,175 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/internal/settings"
+	"aahframe.work/log"
+)
+
+const instrumentationScope = "aahframe.work"
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Log exporter
+//______________________________________________________________________________
+
+var _ Exporter = (*LogExporter)(nil)
+
+// LogExporter writes the finished spans into application log, it's useful
+// for development.
+type LogExporter struct {
+	Logger log.Loggerer
+}
+
+// Export method writes the spans into log.
+func (e *LogExporter) Export(_ context.Context, spans []*SpanData) error {
+	for _, sd := range spans {
+		e.Logger.WithFields(log.Fields{
+			"trace_id":  sd.SpanContext.TraceID.String(),
+			"span_id":   sd.SpanContext.SpanID.String(),
+			"parent_id": sd.ParentSpanID.String(),
+			"status":    int(sd.Status),
+			"duration":  sd.EndTime.Sub(sd.StartTime).String(),
+		}).Infof("tracing: span '%s' finished", sd.Name)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// OTLP exporter
+//______________________________________________________________________________
+
+var _ Exporter = (*OTLPExporter)(nil)
+
+// OTLPExporter sends the finished spans to OpenTelemetry collector using
+// OTLP/HTTP protocol with JSON encoding.
+type OTLPExporter struct {
+	Endpoint    string
+	Headers     map[string]string
+	ServiceName string
+	Client      *http.Client
+}
+
+// Export method sends the spans to collector endpoint.
+func (e *OTLPExporter) Export(ctx context.Context, spans []*SpanData) error {
+	body, err := json.Marshal(e.payload(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("tracing: collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (e *OTLPExporter) payload(spans []*SpanData) map[string]interface{} {
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, sd := range spans {
+		s := map[string]interface{}{
+			"traceId":           sd.SpanContext.TraceID.String(),
+			"spanId":            sd.SpanContext.SpanID.String(),
+			"name":              sd.Name,
+			"kind":              int(sd.Kind),
+			"startTimeUnixNano": strconv.FormatInt(sd.StartTime.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(sd.EndTime.UnixNano(), 10),
+			"attributes":        otlpAttributes(sd.Attributes),
+			"status":            map[string]interface{}{"code": int(sd.Status), "message": sd.StatusMessage},
+		}
+		if sd.ParentSpanID.IsValid() {
+			s["parentSpanId"] = sd.ParentSpanID.String()
+		}
+		if len(sd.SpanContext.TraceState) > 0 {
+			s["traceState"] = sd.SpanContext.TraceState
+		}
+		otlpSpans = append(otlpSpans, s)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": otlpAttributes([]Attribute{{Key: "service.name", Value: e.ServiceName}}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": instrumentationScope},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func newOTLPExporter(appCfg *config.Config, keyPrefix, serviceName string) (*OTLPExporter, error) {
+	e := &OTLPExporter{
+		Endpoint:    appCfg.StringDefault(keyPrefix+".endpoint", "http://localhost:4318/v1/traces"),
+		Headers:     make(map[string]string),
+		ServiceName: serviceName,
+	}
+	if !strings.HasPrefix(e.Endpoint, "http://") && !strings.HasPrefix(e.Endpoint, "https://") {
+		return nil, fmt.Errorf("tracing: '%s.endpoint' value must be HTTP URL", keyPrefix)
+	}
+	for _, k := range appCfg.KeysByPath(keyPrefix + ".headers") {
+		e.Headers[k] = appCfg.StringDefault(keyPrefix+".headers."+k, "")
+	}
+	timeout, err := settings.ParseDuration(appCfg, keyPrefix+".timeout", 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: %s", err)
+	}
+	e.Client = &http.Client{Timeout: timeout}
+	return e, nil
+}
+
+func otlpAttributes(attrs []Attribute) []interface{} {
+	result := make([]interface{}, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]interface{}
+		switch t := a.Value.(type) {
+		case string:
+			v = map[string]interface{}{"stringValue": t}
+		case bool:
+			v = map[string]interface{}{"boolValue": t}
+		case int:
+			v = map[string]interface{}{"intValue": strconv.Itoa(t)}
+		case int64:
+			v = map[string]interface{}{"intValue": strconv.FormatInt(t, 10)}
+		case float64:
+			v = map[string]interface{}{"doubleValue": t}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprint(t)}
+		}
+		result = append(result, map[string]interface{}{"key": a.Key, "value": v})
+	}
+	return result
+}
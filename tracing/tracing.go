@@ -0,0 +1,562 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package tracing provides OpenTelemetry compatible request tracing for aah
+// application. Trace context is propagated via W3C `traceparent` and
+// `tracestate` headers, finished spans are exported in batches to OTLP/HTTP
+// collector, application log or custom exporter.
+//
+//	server {
+//	  tracing {
+//	    enable = true
+//	    service_name = "orders"
+//	    sample_ratio = 0.25
+//	    exporter = "otlp"
+//	    otlp {
+//	      endpoint = "http://localhost:4318/v1/traces"
+//	    }
+//	  }
+//	}
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
+	"aahframe.work/log"
+)
+
+// W3C trace context header names.
+const (
+	HeaderTraceparent = "Traceparent"
+	HeaderTracestate  = "Tracestate"
+)
+
+// SpanKind values of span, same as OpenTelemetry span kind.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode values of span, same as OpenTelemetry status code.
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// ErrExporterIsNil returned when exporter is nil.
+var ErrExporterIsNil = errors.New("aah/tracing: exporter is nil")
+
+type (
+	// SpanKind is the role of span in the trace.
+	SpanKind int
+
+	// StatusCode is the status of span operation.
+	StatusCode int
+
+	// TraceID is 16 bytes trace identifier.
+	TraceID [16]byte
+
+	// SpanID is 8 bytes span identifier.
+	SpanID [8]byte
+
+	ctxKey struct{}
+)
+
+// Exporter interface is to implement the span destination, for e.g.:
+// vendor specific collector. Export is called sequentially from the tracer
+// worker goroutine with batch of finished spans, the slice must not be
+// retained after Export returns.
+type Exporter interface {
+	Export(ctx context.Context, spans []*SpanData) error
+}
+
+// New method creates the tracer from the config `server.tracing { ... }`,
+// custom exporters are looked up by name. Returns nil tracer if tracing is
+// not enabled.
+func New(appCfg *config.Config, logger log.Loggerer, exporters map[string]Exporter) (*Tracer, error) {
+	keyPrefix := "server.tracing"
+	if !appCfg.BoolDefault(keyPrefix+".enable", false) {
+		return nil, nil
+	}
+
+	t := &Tracer{
+		ServiceName: appCfg.StringDefault(keyPrefix+".service_name", appCfg.StringDefault("name", "aah")),
+		SampleRatio: 1,
+		BatchSize:   appCfg.IntDefault(keyPrefix+".batch_size", 512),
+		logger:      logger,
+		stop:        make(chan struct{}),
+	}
+	if v, found := appCfg.Get(keyPrefix + ".sample_ratio"); found {
+		ratio, err := strconv.ParseFloat(fmt.Sprint(v), 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("tracing: '%s.sample_ratio' value must be between 0 and 1", keyPrefix)
+		}
+		t.SampleRatio = ratio
+	}
+	if t.BatchSize <= 0 {
+		return nil, fmt.Errorf("tracing: '%s.batch_size' value must be greater than zero", keyPrefix)
+	}
+	queueSize := appCfg.IntDefault(keyPrefix+".queue_size", 2048)
+	if queueSize <= 0 {
+		return nil, fmt.Errorf("tracing: '%s.queue_size' value must be greater than zero", keyPrefix)
+	}
+	t.queue = make(chan *SpanData, queueSize)
+
+	var err error
+	if t.Interval, err = settings.ParseDuration(appCfg, keyPrefix+".interval", 5*time.Second); err != nil {
+		return nil, fmt.Errorf("tracing: %s", err)
+	}
+	if t.Interval == 0 {
+		return nil, fmt.Errorf("tracing: '%s.interval' value must be greater than zero", keyPrefix)
+	}
+
+	name := appCfg.StringDefault(keyPrefix+".exporter", "otlp")
+	switch name {
+	case "otlp":
+		if t.exporter, err = newOTLPExporter(appCfg, keyPrefix+".otlp", t.ServiceName); err != nil {
+			return nil, err
+		}
+	case "log":
+		t.exporter = &LogExporter{Logger: logger}
+	default:
+		e, found := exporters[name]
+		if !found {
+			return nil, fmt.Errorf("tracing: '%s.exporter' has unknown exporter '%s'", keyPrefix, name)
+		}
+		t.exporter = e
+	}
+
+	return t, nil
+}
+
+// ContextWithSpan method returns the copy of parent context with given span.
+func ContextWithSpan(parent context.Context, span *Span) context.Context {
+	return context.WithValue(parent, ctxKey{}, span)
+}
+
+// SpanFromContext method returns the current span from given context
+// otherwise nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(ctxKey{}).(*Span)
+	return span
+}
+
+// Start method starts the child span of current span in the given context,
+// typically the request context `ctx.Req.Context()`. Returned span is nil
+// if context does not have a span, `Span` methods are nil safe.
+//
+//	c, span := tracing.Start(ctx.Req.Context(), "orders.query")
+//	defer span.End()
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.start(ctx, name, SpanKindInternal, parent.sc, parent.sc.SpanID)
+}
+
+// Inject method adds the W3C trace context headers of current span in the
+// given context into header, for e.g.: outbound HTTP request.
+func Inject(ctx context.Context, hdr http.Header) {
+	if span := SpanFromContext(ctx); span != nil {
+		hdr.Set(HeaderTraceparent, span.sc.Traceparent())
+		if len(span.sc.TraceState) > 0 {
+			hdr.Set(HeaderTracestate, span.sc.TraceState)
+		}
+	}
+}
+
+// Extract method parses the W3C trace context headers from given header.
+// Returns false if `traceparent` header is absent or invalid.
+func Extract(hdr http.Header) (SpanContext, bool) {
+	sc, ok := ParseTraceparent(hdr.Get(HeaderTraceparent))
+	if ok {
+		sc.TraceState = hdr.Get(HeaderTracestate)
+	}
+	return sc, ok
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Tracer
+//______________________________________________________________________________
+
+// Tracer creates the spans and exports the finished sampled spans in
+// batches from its worker goroutine, see `Tracer.Run`.
+type Tracer struct {
+	ServiceName string
+	SampleRatio float64
+	BatchSize   int
+	Interval    time.Duration
+
+	exported int64
+	dropped  int64
+	failed   int64
+	exporter Exporter
+	queue    chan *SpanData
+	stop     chan struct{}
+	stopOnce sync.Once
+	logger   log.Loggerer
+}
+
+// StartServer method starts the server span of incoming request, remote
+// span context is parent if it's valid.
+func (t *Tracer) StartServer(ctx context.Context, name string, remote SpanContext) (context.Context, *Span) {
+	if remote.IsValid() {
+		return t.start(ctx, name, SpanKindServer, remote, remote.SpanID)
+	}
+	return t.start(ctx, name, SpanKindServer, SpanContext{}, SpanID{})
+}
+
+// Run method exports the finished spans until the given context is done
+// or tracer is stopped, pending spans are exported before it returns.
+func (t *Tracer) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+	batch := make([]*SpanData, 0, t.BatchSize)
+	for {
+		select {
+		case sd := <-t.queue:
+			if batch = append(batch, sd); len(batch) >= t.BatchSize {
+				batch = t.export(batch)
+			}
+		case <-ticker.C:
+			batch = t.export(batch)
+		case <-ctx.Done():
+			t.drain(batch)
+			return
+		case <-t.stop:
+			t.drain(batch)
+			return
+		}
+	}
+}
+
+// Stop method stops the tracer worker, pending spans are exported.
+func (t *Tracer) Stop() {
+	t.stopOnce.Do(func() { close(t.stop) })
+}
+
+// Metrics method returns the number of exported, dropped and export failed
+// spans.
+func (t *Tracer) Metrics() (exported, dropped, failed int64) {
+	return atomic.LoadInt64(&t.exported), atomic.LoadInt64(&t.dropped), atomic.LoadInt64(&t.failed)
+}
+
+func (t *Tracer) start(ctx context.Context, name string, kind SpanKind, parent SpanContext, parentID SpanID) (context.Context, *Span) {
+	sc := SpanContext{SpanID: newSpanID(), TraceState: parent.TraceState}
+	if parent.IsValid() {
+		sc.TraceID, sc.Sampled = parent.TraceID, parent.Sampled
+	} else {
+		sc.TraceID = newTraceID()
+		sc.Sampled = t.shouldSample(sc.TraceID)
+	}
+	span := &Span{
+		tracer:   t,
+		sc:       sc,
+		parentID: parentID,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// shouldSample method samples the trace by trace ID ratio, so the decision
+// is consistent for the trace.
+func (t *Tracer) shouldSample(id TraceID) bool {
+	switch {
+	case t.SampleRatio >= 1:
+		return true
+	case t.SampleRatio <= 0:
+		return false
+	}
+	return binary.BigEndian.Uint64(id[8:])>>1 < uint64(t.SampleRatio*(1<<63))
+}
+
+func (t *Tracer) enqueue(sd *SpanData) {
+	select {
+	case t.queue <- sd:
+	default:
+		atomic.AddInt64(&t.dropped, 1)
+	}
+}
+
+func (t *Tracer) export(batch []*SpanData) []*SpanData {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := t.exporter.Export(context.Background(), batch); err != nil {
+		atomic.AddInt64(&t.failed, int64(len(batch)))
+		t.logger.Errorf("tracing: unable to export %d spans: %v", len(batch), err)
+	} else {
+		atomic.AddInt64(&t.exported, int64(len(batch)))
+	}
+	return batch[:0]
+}
+
+func (t *Tracer) drain(batch []*SpanData) {
+	for {
+		select {
+		case sd := <-t.queue:
+			if batch = append(batch, sd); len(batch) >= t.BatchSize {
+				batch = t.export(batch)
+			}
+		default:
+			t.export(batch)
+			return
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Span
+//______________________________________________________________________________
+
+// Span is single operation within a trace, only sampled spans are exported.
+// Methods are safe to call on nil span, updates after `End` are ignored.
+type Span struct {
+	mu        sync.Mutex
+	tracer    *Tracer
+	sc        SpanContext
+	parentID  SpanID
+	name      string
+	kind      SpanKind
+	start     time.Time
+	attrs     []Attribute
+	status    StatusCode
+	statusMsg string
+	ended     bool
+}
+
+// Attribute is key and value pair of span, value type is string, bool,
+// int, int64 or float64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// SpanContext method returns the span context.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// IsRecording method returns true if span is sampled and not yet ended.
+func (s *Span) IsRecording() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sc.Sampled && !s.ended
+}
+
+// SetName method updates the span name.
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.name = name
+}
+
+// SetAttribute method sets the span attribute, existing value is replaced.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	for i := range s.attrs {
+		if s.attrs[i].Key == key {
+			s.attrs[i].Value = value
+			return
+		}
+	}
+	s.attrs = append(s.attrs, Attribute{Key: key, Value: value})
+}
+
+// SetStatus method sets the span status and its description.
+func (s *Span) SetStatus(code StatusCode, msg string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.status, s.statusMsg = code, msg
+}
+
+// SetError method sets the span status as error with given error message.
+func (s *Span) SetError(err error) {
+	if err != nil {
+		s.SetStatus(StatusError, err.Error())
+	}
+}
+
+// End method ends the span, subsequent calls are no-op.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	sd := &SpanData{
+		SpanContext:   s.sc,
+		ParentSpanID:  s.parentID,
+		Name:          s.name,
+		Kind:          s.kind,
+		StartTime:     s.start,
+		EndTime:       time.Now(),
+		Attributes:    s.attrs,
+		Status:        s.status,
+		StatusMessage: s.statusMsg,
+	}
+	s.mu.Unlock()
+
+	if sd.SpanContext.Sampled {
+		s.tracer.enqueue(sd)
+	}
+}
+
+// SpanData holds the finished span details supplied to the exporter.
+type SpanData struct {
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	Name          string
+	Kind          SpanKind
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Span context
+//______________________________________________________________________________
+
+// SpanContext holds the trace identity of span which is propagated across
+// the process boundaries.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Sampled    bool
+	TraceState string
+}
+
+// ParseTraceparent method parses the W3C `traceparent` header value, for
+// e.g.: `00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`. Returns
+// false if value is invalid.
+func ParseTraceparent(v string) (SpanContext, bool) {
+	var sc SpanContext
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		(parts[0] == "00" && len(parts) != 4) {
+		return sc, false
+	}
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 ||
+		!isLowerHex(parts[0]+parts[1]+parts[2]+parts[3]) {
+		return sc, false
+	}
+	_, _ = hex.Decode(sc.TraceID[:], []byte(parts[1]))
+	_, _ = hex.Decode(sc.SpanID[:], []byte(parts[2]))
+	flags, _ := hex.DecodeString(parts[3])
+	sc.Sampled = flags[0]&0x01 == 0x01
+	return sc, sc.IsValid()
+}
+
+// IsValid method returns true if trace ID and span ID are non-zero.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent method returns the W3C `traceparent` header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// IsValid method returns true if trace ID is non-zero.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// String method returns the lowercase hex value of trace ID.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid method returns true if span ID is non-zero.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// String method returns the lowercase hex value of span ID.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return !ess.IsStrEmpty(s)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type memExporter struct {
+	spans []*SpanData
+}
+
+func (e *memExporter) Export(_ context.Context, spans []*SpanData) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestTraceparent(t *testing.T) {
+	sc, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.True(t, sc.Sampled)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	// future version may have additional fields
+	sc, ok = ParseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra")
+	assert.True(t, ok)
+	assert.False(t, sc.Sampled)
+
+	for _, v := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+	} {
+		_, ok = ParseTraceparent(v)
+		assert.False(t, ok, v)
+	}
+
+	hdr := http.Header{}
+	hdr.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	hdr.Set(HeaderTracestate, "vendor=abc")
+	sc, ok = Extract(hdr)
+	assert.True(t, ok)
+	assert.Equal(t, "vendor=abc", sc.TraceState)
+}
+
+func TestTracerSpans(t *testing.T) {
+	exp := &memExporter{}
+	tr := createTestTracer(t, `
+	server {
+	  tracing {
+	    enable = true
+	    exporter = "mem"
+	    batch_size = 2
+	  }
+	}
+	`, exp)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, server := tr.StartServer(context.Background(), "HTTP GET", remote)
+	assert.True(t, server == SpanFromContext(ctx))
+	assert.True(t, server.IsRecording())
+
+	childCtx, child := Start(ctx, "orders.query")
+	child.SetAttribute("db.system", "postgresql")
+	child.SetError(errors.New("connection reset"))
+	child.End()
+	child.End()
+
+	hdr := http.Header{}
+	Inject(childCtx, hdr)
+	assert.Equal(t, child.SpanContext().Traceparent(), hdr.Get(HeaderTraceparent))
+
+	server.SetName("GET /orders/:id")
+	server.SetAttribute("http.response.status_code", 200)
+	server.End()
+	server.SetAttribute("ignored", true)
+	assert.False(t, server.IsRecording())
+
+	tr.Stop()
+	tr.Run(context.Background())
+	exported, dropped, failed := tr.Metrics()
+	assert.Equal(t, int64(2), exported)
+	assert.Equal(t, int64(0), dropped)
+	assert.Equal(t, int64(0), failed)
+
+	assert.Equal(t, 2, len(exp.spans))
+	cs, ss := exp.spans[0], exp.spans[1]
+	assert.Equal(t, "orders.query", cs.Name)
+	assert.Equal(t, SpanKindInternal, cs.Kind)
+	assert.Equal(t, remote.TraceID, cs.SpanContext.TraceID)
+	assert.Equal(t, ss.SpanContext.SpanID, cs.ParentSpanID)
+	assert.Equal(t, StatusError, cs.Status)
+	assert.Equal(t, "connection reset", cs.StatusMessage)
+	assert.Equal(t, []Attribute{{Key: "db.system", Value: "postgresql"}}, cs.Attributes)
+
+	assert.Equal(t, "GET /orders/:id", ss.Name)
+	assert.Equal(t, SpanKindServer, ss.Kind)
+	assert.Equal(t, remote.SpanID, ss.ParentSpanID)
+	assert.Equal(t, 1, len(ss.Attributes))
+
+	// without span in context
+	c, span := Start(context.Background(), "noop")
+	assert.Nil(t, span)
+	assert.Equal(t, context.Background(), c)
+	span.SetAttribute("key", "value")
+	span.End()
+}
+
+func TestTracerSampling(t *testing.T) {
+	exp := &memExporter{}
+	tr := createTestTracer(t, `
+	server {
+	  tracing {
+	    enable = true
+	    exporter = "mem"
+	    sample_ratio = 0
+	  }
+	}
+	`, exp)
+
+	ctx, server := tr.StartServer(context.Background(), "HTTP GET", SpanContext{})
+	assert.False(t, server.IsRecording())
+	_, child := Start(ctx, "child")
+	assert.Equal(t, server.SpanContext().TraceID, child.SpanContext().TraceID)
+	assert.Equal(t, "-00", child.SpanContext().Traceparent()[52:])
+	child.End()
+	server.End()
+
+	// sampled parent is honored regardless of ratio
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, sampled := tr.StartServer(context.Background(), "HTTP GET", remote)
+	sampled.End()
+
+	tr.Stop()
+	tr.Run(context.Background())
+	assert.Equal(t, 1, len(exp.spans))
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var payload map[string]interface{}
+	var auth string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer collector.Close()
+
+	cfg, _ := config.ParseString(`
+	server {
+	  tracing {
+	    enable = true
+	    service_name = "orders"
+	    otlp {
+	      endpoint = "` + collector.URL + `/v1/traces"
+	      timeout = "2s"
+	      headers {
+	        Authorization = "Bearer token"
+	      }
+	    }
+	  }
+	}
+	`)
+	l, _ := log.New(config.NewEmpty())
+	tr, err := New(cfg, l, nil)
+	assert.Nil(t, err)
+
+	_, span := tr.StartServer(context.Background(), "GET /orders", SpanContext{})
+	span.SetAttribute("http.response.status_code", 200)
+	span.End()
+	tr.Stop()
+	tr.Run(context.Background())
+	exported, _, _ := tr.Metrics()
+	assert.Equal(t, int64(1), exported)
+	assert.Equal(t, "Bearer token", auth)
+
+	rs := payload["resourceSpans"].([]interface{})[0].(map[string]interface{})
+	resAttr := rs["resource"].(map[string]interface{})["attributes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "service.name", resAttr["key"])
+	assert.Equal(t, map[string]interface{}{"stringValue": "orders"}, resAttr["value"])
+	s := rs["scopeSpans"].([]interface{})[0].(map[string]interface{})["spans"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "GET /orders", s["name"])
+	assert.Equal(t, float64(SpanKindServer), s["kind"])
+	assert.Equal(t, span.SpanContext().TraceID.String(), s["traceId"])
+	assert.Nil(t, s["parentSpanId"])
+	attr := s["attributes"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"intValue": "200"}, attr["value"])
+}
+
+func TestTracerConfigErrors(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	for cfgStr, msg := range map[string]string{
+		`server { tracing { enable = true; sample_ratio = 1.5; } }`:            "tracing: 'server.tracing.sample_ratio' value must be between 0 and 1",
+		`server { tracing { enable = true; batch_size = 0; } }`:                "tracing: 'server.tracing.batch_size' value must be greater than zero",
+		`server { tracing { enable = true; interval = "5x"; } }`:               "tracing: 'server.tracing.interval' value is not a valid time unit",
+		`server { tracing { enable = true; exporter = "zipkin"; } }`:           "tracing: 'server.tracing.exporter' has unknown exporter 'zipkin'",
+		`server { tracing { enable = true; otlp { endpoint = "udp://x"; } } }`: "tracing: 'server.tracing.otlp.endpoint' value must be HTTP URL",
+	} {
+		cfg, _ := config.ParseString(cfgStr)
+		_, err := New(cfg, l, nil)
+		assert.Equal(t, msg, err.Error(), cfgStr)
+	}
+
+	cfg, _ := config.ParseString(`server { tracing { enable = false; } }`)
+	tr, err := New(cfg, l, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tr)
+}
+
+func createTestTracer(t *testing.T, cfgStr string, e Exporter) *Tracer {
+	cfg, _ := config.ParseString(cfgStr)
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(ioutil.Discard)
+	tr, err := New(cfg, l, map[string]Exporter{"mem": e})
+	assert.Nil(t, err)
+	return tr
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/config"
+	"aahframe.work/internal/settings"
+	"aahframe.work/log"
+	"aahframe.work/tracing"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpanExporter struct {
+	spans chan *tracing.SpanData
+}
+
+func (e *testSpanExporter) Export(_ context.Context, spans []*tracing.SpanData) error {
+	for _, sd := range spans {
+		e.spans <- sd
+	}
+	return nil
+}
+
+func TestTracingServerSpan(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Tracing]: %s", ts.URL)
+
+	exp := &testSpanExporter{spans: make(chan *tracing.SpanData, 10)}
+	assert.Nil(t, ts.app.AddTracingExporter("test", exp))
+	assert.Equal(t, "aah: tracing exporter 'test' is already added", ts.app.AddTracingExporter("test", exp).Error())
+	assert.Equal(t, "aah: tracing exporter 'otlp' is already added", ts.app.AddTracingExporter("otlp", exp).Error())
+	assert.Equal(t, tracing.ErrExporterIsNil, ts.app.AddTracingExporter("nil", nil))
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	server {
+	  tracing {
+	    enable = true
+	    exporter = "test"
+	    batch_size = 1
+	  }
+	}
+	`))
+	assert.Nil(t, ts.app.initTracing())
+	defer ts.app.Tracer().Stop()
+
+	req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+"/get-text.html", nil)
+	req.Header.Set(tracing.HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var sd *tracing.SpanData
+	select {
+	case sd = <-exp.spans:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server span is not exported")
+	}
+	assert.Equal(t, "GET /get-text.html", sd.Name)
+	assert.Equal(t, tracing.SpanKindServer, sd.Kind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sd.SpanContext.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", sd.ParentSpanID.String())
+	assert.Equal(t, tracing.StatusUnset, sd.Status)
+
+	attrs := make(map[string]interface{})
+	for _, a := range sd.Attributes {
+		attrs[a.Key] = a.Value
+	}
+	assert.Equal(t, "/get-text.html", attrs["http.route"])
+	assert.Equal(t, http.StatusOK, attrs["http.response.status_code"])
+	assert.Contains(t, attrs["aah.controller"], "testSiteController")
+	assert.Equal(t, "Text", attrs["aah.action"])
+}
+
+func TestTracingContextLog(t *testing.T) {
+	a := newApp()
+	cfg, _ := config.ParseString(`
+	log {
+	  format = "json"
+	}
+	server {
+	  tracing {
+	    enable = true
+	    exporter = "log"
+	  }
+	}
+	`)
+	a.logger, _ = log.New(cfg)
+	buf := new(bytes.Buffer)
+	a.Log().(*log.Logger).SetWriter(buf)
+	a.settingsHolder.Update(func(s *settings.Settings) {
+		s.RequestIDHeaderKey = ahttp.HeaderXRequestID
+	})
+
+	tr, err := tracing.New(cfg, a.Log(), nil)
+	assert.Nil(t, err)
+	defer tr.Stop()
+	parent, _ := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rctx, span := tr.StartServer(context.Background(), "GET /", parent)
+	defer span.End()
+
+	r := httptest.NewRequest(ahttp.MethodGet, "/", nil).WithContext(rctx)
+	r.Header.Set(ahttp.HeaderXRequestID, "req-1")
+	ctx := newContext(nil, r)
+	ctx.a = a
+
+	ctx.Log().Info("traced request")
+	assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+	assert.Contains(t, buf.String(), `"traceid":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+}
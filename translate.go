@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"aahframe.work/ahttp"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// SetResponseTranslator method sets the response translator, it's called with
+// JSON, Secure JSON and JSONP reply data before it's rendered.
+func (a *Application) SetResponseTranslator(t ResponseTranslator) {
+	a.Lock()
+	defer a.Unlock()
+	a.respTranslator = t
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Response translator
+//______________________________________________________________________________
+
+// ResponseTranslator interface is used to translate the response data fields
+// such as enum values, messages, etc. based on request locale.
+type ResponseTranslator interface {
+	// Translate method returns the translated reply data. Request locale is
+	// available via `ctx.Req.Locale()` and i18n messages via `ctx.Msg`.
+	Translate(ctx *Context, data interface{}) interface{}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+func (a *Application) translateReply(ctx *Context) {
+	switch r := ctx.Reply().Rdr.(type) {
+	case *jsonRender:
+		r.Data = a.respTranslator.Translate(ctx, r.Data)
+	case *secureJSONRender:
+		r.Data = a.respTranslator.Translate(ctx, r.Data)
+	case *jsonpRender:
+		r.Data = a.respTranslator.Translate(ctx, r.Data)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Context Unexported methods
+//______________________________________________________________________________
+
+// writeContentLanguage method sets the response header `Content-Language`
+// from the resolved locale of message store if it's not set by the
+// application. Header `Vary: Accept-Language` is added unless locale is
+// overridden via URL Path or Query parameter, see `i18n.content_language`.
+func (ctx *Context) writeContentLanguage() {
+	if ctx.a.i18n == nil || !ctx.a.contentLang ||
+		len(ctx.Res.Header().Get(ahttp.HeaderContentLanguage)) > 0 {
+		return
+	}
+
+	if lang := ctx.a.i18n.Resolve(ctx.Req.Locale()); len(lang) > 0 {
+		ctx.Res.Header().Set(ahttp.HeaderContentLanguage, lang)
+	}
+	if len(firstNonZeroString(
+		ctx.Req.QueryValue(ctx.a.bindMgr.keyQueryParamName),
+		ctx.Req.PathValue(ctx.a.bindMgr.keyPathParamName))) == 0 {
+		ctx.Res.Header().Add(ahttp.HeaderVary, ahttp.HeaderAcceptLanguage)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+type testResponseTranslator struct{}
+
+func (testResponseTranslator) Translate(ctx *Context, data interface{}) interface{} {
+	if s, ok := data.(sample); ok {
+		s.ProductName = ctx.Req.Locale().String() + ": " + s.ProductName
+		return s
+	}
+	return data
+}
+
+func TestResponseContentLanguage(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Content Language]: %s", ts.URL)
+
+	get := func(path, lang string) *http.Response {
+		req, _ := http.NewRequest(ahttp.MethodGet, ts.URL+path, nil)
+		req.Header.Set(ahttp.HeaderAcceptLanguage, lang)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	resp := get("/get-xml", "en-US,en;q=0.8")
+	assert.Equal(t, "en-US", resp.Header.Get(ahttp.HeaderContentLanguage))
+	assert.True(t, strings.Contains(strings.Join(resp.Header.Values(ahttp.HeaderVary), ","), ahttp.HeaderAcceptLanguage))
+
+	// falls back to default locale
+	resp = get("/get-xml", "ja-JP")
+	assert.Equal(t, "en", resp.Header.Get(ahttp.HeaderContentLanguage))
+
+	// locale override via query param does not vary by header
+	resp = get("/get-xml?lang=en-US", "ja-JP")
+	assert.Equal(t, "en-US", resp.Header.Get(ahttp.HeaderContentLanguage))
+	assert.False(t, strings.Contains(strings.Join(resp.Header.Values(ahttp.HeaderVary), ","), ahttp.HeaderAcceptLanguage))
+
+	// translator is applied on JSON replies
+	ts.app.SetResponseTranslator(testResponseTranslator{})
+	defer ts.app.SetResponseTranslator(nil)
+	resp = get("/secure-json", "en-US")
+	assert.True(t, strings.Contains(responseBody(resp), `"ProductName":"en-US: JSONP product"`))
+
+	// disabled
+	ts.app.contentLang = false
+	resp = get("/get-xml", "en-US")
+	assert.Equal(t, "", resp.Header.Get(ahttp.HeaderContentLanguage))
+	ts.app.contentLang = true
+}
@@ -15,6 +15,7 @@ import (
 	"aahframe.work/internal/settings"
 	"aahframe.work/internal/util"
 	"aahframe.work/security"
+	"aahframe.work/security/authc"
 	"aahframe.work/view"
 )
 
@@ -71,6 +72,11 @@ func (a *Application) initView() error {
 		"ispermitted":     viewMgr.tmplIsPermitted,
 		"ispermittedall":  viewMgr.tmplIsPermittedAll,
 		"anticsrftoken":   viewMgr.tmplAntiCSRFToken,
+		"isimpersonated":  viewMgr.tmplIsImpersonated,
+		"impersonator":    viewMgr.tmplImpersonator,
+		"paginate":        viewMgr.tmplPaginate,
+		"pageurl":         viewMgr.tmplPageURL,
+		"forminput":       viewMgr.tmplFormInput,
 	})
 
 	if err := viewEngine.Init(a.VFS(), a.Config(), viewsDir); err != nil {
@@ -290,21 +296,42 @@ func (vm *viewManager) tmplI18n(viewArgs map[string]interface{}, key string, arg
 //
 
 // tmplURL method returns reverse URL by given route name and args.
-// Mapped to Go template func.
+// Mapped to Go template func. For static route, asset cache busting is
+// applied, see `staticManager.RouteURL`.
 func (vm *viewManager) tmplURL(viewArgs map[string]interface{}, args ...interface{}) template.URL {
 	if len(args) == 0 {
 		vm.a.Log().Errorf("router: template 'rurl' - route name is empty: %v", args)
 		return template.URL("#")
 	}
+	host, routeName := viewArgs["Host"].(string), args[0].(string)
+	if route := vm.a.Router().RouteByName(host, routeName); route != nil && route.IsStatic {
+		var file string
+		if len(args) > 1 {
+			file = fmt.Sprintf("%v", args[1])
+		}
+		/* #nosec */
+		return template.URL(vm.a.staticMgr.RouteURL(host, routeName, route, file))
+	}
+
 	/* #nosec */
-	return template.URL(vm.a.Router().CreateRouteURL(viewArgs["Host"].(string), args[0].(string), nil, args[1:]...))
+	return template.URL(vm.a.Router().CreateRouteURL(host, routeName, nil, args[1:]...))
 }
 
 // tmplURLm method returns reverse URL by given route name and
 // map[string]interface{}. Mapped to Go template func.
 func (vm *viewManager) tmplURLm(viewArgs map[string]interface{}, routeName string, args map[string]interface{}) template.URL {
+	host := viewArgs["Host"].(string)
+	if route := vm.a.Router().RouteByName(host, routeName); route != nil && route.IsStatic {
+		var file string
+		if v, found := args["filepath"]; found {
+			file = fmt.Sprintf("%v", v)
+		}
+		/* #nosec */
+		return template.URL(vm.a.staticMgr.RouteURL(host, routeName, route, file))
+	}
+
 	/* #nosec */
-	return template.URL(vm.a.Router().CreateRouteURL(viewArgs["Host"].(string), routeName, args))
+	return template.URL(vm.a.Router().CreateRouteURL(host, routeName, args))
 }
 
 //
@@ -388,6 +415,23 @@ func (vm *viewManager) tmplIsPermittedAll(viewArgs map[string]interface{}, permi
 	return false
 }
 
+// tmplIsImpersonated method returns the value of `Subject.IsImpersonated`.
+func (vm *viewManager) tmplIsImpersonated(viewArgs map[string]interface{}) bool {
+	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil {
+		return sub.IsImpersonated()
+	}
+	return false
+}
+
+// tmplImpersonator method returns the primary principal of original user while
+// impersonating otherwise nil. Typically used to render impersonation banner.
+func (vm *viewManager) tmplImpersonator(viewArgs map[string]interface{}) *authc.Principal {
+	if sub := vm.getSubjectFromViewArgs(viewArgs); sub != nil && sub.IsImpersonated() {
+		return sub.Impersonator.PrimaryPrincipal()
+	}
+	return nil
+}
+
 // tmplAntiCSRFToken method returns the salted Anti-CSRF secret for the view,
 // if enabled otherwise empty string.
 func (vm *viewManager) tmplAntiCSRFToken(viewArgs map[string]interface{}) string {
@@ -15,6 +15,7 @@ import (
 	"aahframe.work/ahttp"
 	"aahframe.work/ainsp"
 	"aahframe.work/essentials"
+	"aahframe.work/internal/settings"
 	"aahframe.work/view"
 	"github.com/stretchr/testify/assert"
 )
@@ -99,14 +100,14 @@ func TestViewResolveView(t *testing.T) {
 
 	// Namespace/Sub-package
 	t.Log("Namespace/Sub-package")
-	ts.app.settings.EnvProfile = "prod"
+	ts.app.settingsHolder.Update(func(s *settings.Settings) { s.EnvProfile = "prod" })
 	ctx.controller = &ainsp.Target{Type: reflect.TypeOf(AppController{}), Namespace: "frontend"}
 	ctx.Reply().HTMLf("index.html", Data{})
 	vm.resolve(ctx)
 	htmlRdr = ctx.Reply().Rdr.(*htmlRender)
 	assert.Equal(t, "index.html", htmlRdr.Filename)
 	assert.Equal(t, "View Not Found", htmlRdr.ViewArgs["ViewNotFound"])
-	ts.app.settings.EnvProfile = "dev"
+	ts.app.settingsHolder.Update(func(s *settings.Settings) { s.EnvProfile = "dev" })
 }
 
 func TestViewMinifier(t *testing.T) {
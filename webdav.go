@@ -0,0 +1,117 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"aahframe.work/ainsp"
+	"aahframe.work/router"
+	"aahframe.work/security/anticsrf"
+	"golang.org/x/net/webdav"
+)
+
+const (
+	webDAVTarget     = "aahframe.work/webDAVController.Serve"
+	webDAVPathSuffix = "/*filepath"
+)
+
+// webDAVMethods are the HTTP methods of WebDAV (RFC 4918) routes.
+var webDAVMethods = []string{"OPTIONS", "GET", "HEAD", "POST", "PUT", "DELETE",
+	"MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK", "PROPFIND", "PROPPATCH"}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app methods
+//______________________________________________________________________________
+
+// MountWebDAV method mounts the WebDAV handler on given route path prefix
+// backed by given file system, for e.g.: `webdav.Dir("/data/share")`. Routes
+// are registered for all WebDAV methods so aah authentication,
+// authorization, CORS, etc. are applied as usual, use route options to set
+// the auth scheme. Anti-CSRF is exempted and request body is streamed by
+// default. Use it from `func init() {...}`.
+//
+//	err := aah.App().MountWebDAV("/share", webdav.Dir("/data/share"),
+//		router.WithAuth("basic_auth"), router.WithMaxBodySize(1<<30))
+func (a *Application) MountWebDAV(prefix string, fs webdav.FileSystem, opts ...router.RouteOption) error {
+	if fs == nil {
+		return errors.New("aah: webdav file system is nil")
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("aah: webdav prefix '%s' must begin with '/'", prefix)
+	}
+	prefix = path.Clean(prefix)
+	if prefix == "/" {
+		return errors.New("aah: webdav prefix must not be root '/'")
+	}
+
+	a.Lock()
+	if _, found := a.webdavHandlers[prefix]; found {
+		a.Unlock()
+		return fmt.Errorf("aah: webdav prefix '%s' is already mounted", prefix)
+	}
+	if a.webdavHandlers == nil {
+		a.webdavHandlers = make(map[string]*webdav.Handler)
+		a.HTTPEngine().registry.Add((*webDAVController)(nil), []*ainsp.Method{{Name: "Serve"}})
+	}
+	a.webdavHandlers[prefix] = &webdav.Handler{
+		Prefix:     prefix,
+		FileSystem: fs,
+		LockSystem: webdav.NewMemLS(),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				a.Log().Warnf("WebDAV: %s %s: %v", r.Method, r.URL.Path, err)
+			}
+		},
+	}
+	a.Unlock()
+
+	// route names are derived from prefix and method, for e.g.:
+	// `webdav_share_propfind` and `webdav_share_propfind_root`
+	name := "webdav" + strings.Replace(prefix, "/", "_", -1)
+	for _, m := range webDAVMethods {
+		for _, rp := range [][2]string{{prefix, "_root"}, {prefix + webDAVPathSuffix, ""}} {
+			routeOpts := append([]router.RouteOption{
+				router.WithAntiCSRFPolicy(anticsrf.PolicyExempt),
+				router.WithStreamBody(),
+			}, opts...)
+			routeOpts = append(routeOpts, router.WithName(name+"_"+strings.ToLower(m)+rp[1]))
+			if err := a.AddRoute(m, rp[0], webDAVTarget, routeOpts...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// WebDAV controller
+//______________________________________________________________________________
+
+// webDAVController serves the WebDAV routes registered via
+// `Application.MountWebDAV`.
+type webDAVController struct {
+	*Context
+}
+
+// Serve method delegates the request to mounted WebDAV handler, response is
+// written directly.
+func (c *webDAVController) Serve() {
+	prefix := strings.TrimSuffix(c.route.Path, webDAVPathSuffix)
+	c.a.RLock()
+	h := c.a.webdavHandlers[prefix]
+	c.a.RUnlock()
+	if h == nil {
+		handleNotFound(c.Context, ErrRouteNotFound)
+		return
+	}
+
+	c.Reply().Done()
+	h.ServeHTTP(c.Res, c.Req.Unwrap())
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aahframe.work/router"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/webdav"
+)
+
+func TestWebDAVMount(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [WebDAV]: %s", ts.URL)
+
+	dir := t.TempDir()
+	assert.Nil(t, ts.app.MountWebDAV("/share/", webdav.Dir(dir), router.WithAuth("anonymous")))
+	assert.Nil(t, ts.app.initRouter())
+
+	route := ts.app.Router().RootDomain().LookupByName("webdav_share_propfind")
+	assert.NotNil(t, route)
+	assert.Equal(t, "/share/*filepath", route.Path)
+	assert.False(t, route.IsAntiCSRFCheck)
+	assert.True(t, route.StreamBody)
+	assert.NotNil(t, ts.app.Router().RootDomain().LookupByName("webdav_share_mkcol_root"))
+
+	send := func(method, path, body string) *http.Response {
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if method == "PROPFIND" {
+			req.Header.Set("Depth", "1")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	resp := send("MKCOL", "/share/docs", "")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = send(http.MethodPut, "/share/docs/notes.txt", "hello webdav")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "docs", "notes.txt"))
+	assert.Nil(t, err)
+	assert.Equal(t, "hello webdav", string(data))
+
+	resp = send("PROPFIND", "/share/docs/", "")
+	assert.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	assert.True(t, strings.Contains(responseBody(resp), "/share/docs/notes.txt"))
+
+	resp = send(http.MethodGet, "/share/docs/notes.txt", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "hello webdav", responseBody(resp))
+
+	resp = send(http.MethodDelete, "/share/docs/notes.txt", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// invalid mounts
+	err = ts.app.MountWebDAV("/share", webdav.Dir(dir))
+	assert.Equal(t, "aah: webdav prefix '/share' is already mounted", err.Error())
+	err = ts.app.MountWebDAV("share", webdav.Dir(dir))
+	assert.Equal(t, "aah: webdav prefix 'share' must begin with '/'", err.Error())
+	err = ts.app.MountWebDAV("/", webdav.Dir(dir))
+	assert.Equal(t, "aah: webdav prefix must not be root '/'", err.Error())
+	err = ts.app.MountWebDAV("/files", nil)
+	assert.Equal(t, "aah: webdav file system is nil", err.Error())
+}
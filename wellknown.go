@@ -0,0 +1,175 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/essentials"
+	"aahframe.work/vfs"
+)
+
+const wellKnownPathPrefix = "/.well-known/"
+
+// security.txt fields in the order of RFC 9116, config key => field name
+var securityTxtFields = [][2]string{
+	{"contact", "Contact"},
+	{"expires", "Expires"},
+	{"encryption", "Encryption"},
+	{"acknowledgments", "Acknowledgments"},
+	{"preferred_languages", "Preferred-Languages"},
+	{"canonical", "Canonical"},
+	{"policy", "Policy"},
+	{"hiring", "Hiring"},
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// app Unexported methods
+//______________________________________________________________________________
+
+// initWellKnown method initializes the `/.well-known/` endpoints from
+// config `well_known { ... }`. Application routes takes precedence over
+// these endpoints.
+//
+//	well_known {
+//	  security_txt {
+//	    contact = ["mailto:security@example.com"]
+//	    expires = "2027-01-01T00:00:00Z"
+//	  }
+//	  change_password {
+//	    redirect = "/account/password"
+//	  }
+//	  assetlinks {
+//	    file = "config/assetlinks.json"
+//	  }
+//	}
+func (a *Application) initWellKnown() error {
+	keyPrefix := "well_known"
+	if !a.Config().IsExists(keyPrefix) {
+		a.wellKnown = nil
+		return nil
+	}
+
+	wk := &wellKnownManager{a: a, entries: make(map[string]*wellKnownEntry)}
+
+	// security.txt
+	if a.Config().IsExists(keyPrefix + ".security_txt") {
+		e, err := wk.parseSecurityTxt(keyPrefix + ".security_txt")
+		if err != nil {
+			return err
+		}
+		wk.entries["security.txt"] = e
+	}
+
+	// change-password
+	if a.Config().IsExists(keyPrefix + ".change_password") {
+		redirect := a.Config().StringDefault(keyPrefix+".change_password.redirect", "")
+		if ess.IsStrEmpty(redirect) {
+			return fmt.Errorf("'%s.change_password.redirect' key is missing", keyPrefix)
+		}
+		wk.entries["change-password"] = &wellKnownEntry{redirect: redirect}
+	}
+
+	// assetlinks.json
+	if a.Config().IsExists(keyPrefix + ".assetlinks") {
+		e, err := wk.parseFile(keyPrefix+".assetlinks", ahttp.ContentTypeJSON.String())
+		if err != nil {
+			return err
+		}
+		wk.entries["assetlinks.json"] = e
+	}
+
+	a.wellKnown = wk
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Well-Known manager
+//______________________________________________________________________________
+
+type wellKnownEntry struct {
+	contentType string
+	content     []byte
+	redirect    string
+}
+
+type wellKnownManager struct {
+	a       *Application
+	entries map[string]*wellKnownEntry
+}
+
+// Serve method replies the configured well-known endpoint for the request
+// path. Returns true if request is served otherwise false.
+func (wk *wellKnownManager) Serve(ctx *Context) bool {
+	if !strings.HasPrefix(ctx.Req.Path, wellKnownPathPrefix) ||
+		(ctx.Req.Method != ahttp.MethodGet && ctx.Req.Method != ahttp.MethodHead) {
+		return false
+	}
+
+	e, found := wk.entries[ctx.Req.Path[len(wellKnownPathPrefix):]]
+	if !found {
+		return false
+	}
+
+	ctx.Log().Debugf("Serving well-known endpoint: %s", ctx.Req.Path)
+	if len(e.redirect) > 0 {
+		ctx.Reply().RedirectWithStatus(e.redirect, http.StatusFound)
+		return true
+	}
+
+	ctx.Reply().Ok().ContentType(e.contentType).Binary(e.content)
+	return true
+}
+
+func (wk *wellKnownManager) parseSecurityTxt(keyPrefix string) (*wellKnownEntry, error) {
+	if wk.a.Config().IsExists(keyPrefix + ".file") {
+		return wk.parseFile(keyPrefix, ahttp.ContentTypePlainText.String())
+	}
+
+	cfg := wk.a.Config()
+	if _, found := cfg.StringList(keyPrefix + ".contact"); !found {
+		return nil, fmt.Errorf("'%s.contact' key is missing", keyPrefix)
+	}
+	expires := cfg.StringDefault(keyPrefix+".expires", "")
+	if _, err := time.Parse(time.RFC3339, expires); err != nil {
+		return nil, fmt.Errorf("'%s.expires' must be a RFC 3339 date and time: %s", keyPrefix, err)
+	}
+
+	buf := &bytes.Buffer{}
+	for _, f := range securityTxtFields {
+		key := keyPrefix + "." + f[0]
+		values, found := cfg.StringList(key)
+		if !found {
+			if v := cfg.StringDefault(key, ""); len(v) > 0 {
+				values = []string{v}
+			}
+		}
+		for _, v := range values {
+			buf.WriteString(f[1] + ": " + strings.TrimSpace(v) + "\n")
+		}
+	}
+
+	return &wellKnownEntry{contentType: ahttp.ContentTypePlainText.String(), content: buf.Bytes()}, nil
+}
+
+func (wk *wellKnownManager) parseFile(keyPrefix, contentType string) (*wellKnownEntry, error) {
+	file := wk.a.Config().StringDefault(keyPrefix+".file", "")
+	if ess.IsStrEmpty(file) {
+		return nil, fmt.Errorf("'%s.file' key is missing", keyPrefix)
+	}
+
+	b, err := vfs.ReadFile(wk.a.VFS(), path.Join(wk.a.VirtualBaseDir(), file))
+	if err != nil {
+		return nil, fmt.Errorf("'%s.file' %s", keyPrefix, err)
+	}
+
+	return &wellKnownEntry{contentType: contentType, content: b}, nil
+}
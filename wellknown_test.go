@@ -0,0 +1,95 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package aah
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"aahframe.work/ahttp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWellKnownEndpoints(t *testing.T) {
+	ts := newWebApp1TestServer(t)
+	defer ts.Close()
+
+	t.Logf("Test Server URL [Well-Known Endpoints]: %s", ts.URL)
+
+	assert.Nil(t, mergeTestConfig(ts.app, `
+	well_known {
+	  security_txt {
+	    contact = ["mailto:security@example.com", "https://example.com/security"]
+	    expires = "2030-01-01T00:00:00Z"
+	    preferred_languages = "en, ta"
+	  }
+	  change_password {
+	    redirect = "/account/password"
+	  }
+	  assetlinks {
+	    file = "static/well-known/assetlinks.json"
+	  }
+	}
+	`))
+	err := ts.app.initWellKnown()
+	assert.Nil(t, err)
+
+	httpClient := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := httpClient.Get(ts.URL + "/.well-known/security.txt")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ahttp.ContentTypePlainText.String(), resp.Header.Get(ahttp.HeaderContentType))
+	assert.Equal(t, "Contact: mailto:security@example.com\n"+
+		"Contact: https://example.com/security\n"+
+		"Expires: 2030-01-01T00:00:00Z\n"+
+		"Preferred-Languages: en, ta\n", responseBody(resp))
+
+	resp, err = httpClient.Get(ts.URL + "/.well-known/change-password")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/account/password", resp.Header.Get(ahttp.HeaderLocation))
+
+	resp, err = httpClient.Get(ts.URL + "/.well-known/assetlinks.json")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, ahttp.ContentTypeJSON.String(), resp.Header.Get(ahttp.HeaderContentType))
+	assert.True(t, strings.Contains(responseBody(resp), "work.aahframe.example"))
+
+	resp, err = httpClient.Get(ts.URL + "/.well-known/not-exists")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestWellKnownConfigErrors(t *testing.T) {
+	a := newWebApp1TestApp(t)
+
+	for _, c := range []struct{ cfg, err string }{
+		{`well_known { security_txt { expires = "2030-01-01T00:00:00Z"; } }`,
+			"'well_known.security_txt.contact' key is missing"},
+		{`well_known { change_password { } }`,
+			"'well_known.change_password.redirect' key is missing"},
+		{`well_known { assetlinks { enable = true; } }`,
+			"'well_known.assetlinks.file' key is missing"},
+	} {
+		assert.Nil(t, setTestConfig(a, c.cfg))
+		err := a.initWellKnown()
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+
+	assert.Nil(t, setTestConfig(a, `well_known { security_txt {
+		contact = ["mailto:security@example.com"]
+		expires = "2030-01-01"
+	} }`))
+	err := a.initWellKnown()
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "'well_known.security_txt.expires' must be a RFC 3339 date and time"))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ws
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrCodecIsNil returned when codec is nil.
+var ErrCodecIsNil = errors.New("aahws: codec is nil")
+
+// Codec interface is to implement the WebSocket message codec of
+// subprotocol (`Sec-WebSocket-Protocol`), for e.g.: MsgPack, protobuf.
+// Binary codec messages are sent as binary frames otherwise text frames.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	Binary() bool
+}
+
+// DefaultSubprotocol codec is used when client does not request the
+// subprotocol or none of the requested subprotocol is supported.
+const DefaultSubprotocol = "json"
+
+var (
+	codecMu sync.RWMutex
+	codecs  = map[string]Codec{
+		"json": jsonCodec{},
+		"xml":  xmlCodec{},
+	}
+)
+
+// AddCodec method adds the codec for given subprotocol name into codec
+// registry, `json` and `xml` are available by default.
+//
+// For e.g.:
+//
+//	func init() {
+//		ws.AddCodec("msgpack", &MsgPackCodec{})
+//	}
+func AddCodec(subprotocol string, c Codec) error {
+	if c == nil {
+		return ErrCodecIsNil
+	}
+
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	if _, found := codecs[subprotocol]; found {
+		return fmt.Errorf("ws: codec for subprotocol '%s' is already added", subprotocol)
+	}
+	codecs[subprotocol] = c
+	return nil
+}
+
+func codecByName(subprotocol string) (Codec, bool) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	c, found := codecs[subprotocol]
+	return c, found
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Built-in codecs
+//______________________________________________________________________________
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Binary() bool                               { return false }
+
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error)      { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error { return xml.Unmarshal(data, v) }
+func (xmlCodec) Binary() bool                               { return false }
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"sync"
 
 	"aahframe.work/ainsp"
 	"aahframe.work/log"
@@ -48,6 +49,8 @@ type Context struct {
 	logger     log.Loggerer
 	reason     error
 	abortCode  int
+	codec      Codec
+	wmu        sync.Mutex
 }
 
 // ReadText method reads a text value from WebSocket client.
@@ -90,16 +93,33 @@ func (ctx *Context) ReadXML(t interface{}) error {
 	return xml.Unmarshal(data, t)
 }
 
+// ReadMessage method reads the message from WebSocket client and decodes it
+// into given object using negotiated subprotocol codec. Default is JSON.
+//
+// For e.g.:
+//
+//	var msg ChatMessage
+//	if err := ctx.ReadMessage(&msg); err != nil {
+//		return
+//	}
+func (ctx *Context) ReadMessage(t interface{}) error {
+	data, _, err := wsutil.ReadClientData(ctx.Conn)
+	if err != nil {
+		return createError(err)
+	}
+	return ctx.messageCodec().Unmarshal(data, t)
+}
+
 // ReplyText method sends Text data to the WebSocket client returns error
 // if client is gone, network error, etc.
 func (ctx *Context) ReplyText(v string) error {
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpText, []byte(v)))
+	return createError(ctx.write(gws.OpText, []byte(v)))
 }
 
 // ReplyBinary method sends Binary data to the WebSocket client returns
 // error if client is gone, network error, etc.
 func (ctx *Context) ReplyBinary(v []byte) error {
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpBinary, v))
+	return createError(ctx.write(gws.OpBinary, v))
 }
 
 // ReplyJSON method sends JSON data to the WebSocket client returns
@@ -109,7 +129,7 @@ func (ctx *Context) ReplyJSON(v interface{}) error {
 	if err != nil {
 		return err
 	}
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpText, b))
+	return createError(ctx.write(gws.OpText, b))
 }
 
 // ReplyXML method sends XML data to the WebSocket client returns
@@ -119,7 +139,29 @@ func (ctx *Context) ReplyXML(v interface{}) error {
 	if err != nil {
 		return err
 	}
-	return createError(wsutil.WriteServerMessage(ctx.Conn, gws.OpText, b))
+	return createError(ctx.write(gws.OpText, b))
+}
+
+// ReplyMessage method encodes the given object using negotiated subprotocol
+// codec and sends it to the WebSocket client. Binary codec message is sent
+// as binary frame otherwise text frame.
+func (ctx *Context) ReplyMessage(v interface{}) error {
+	c := ctx.messageCodec()
+	b, err := c.Marshal(v)
+	if err != nil {
+		return err
+	}
+	op := gws.OpText
+	if c.Binary() {
+		op = gws.OpBinary
+	}
+	return createError(ctx.write(op, b))
+}
+
+// Subprotocol method returns the negotiated WebSocket subprotocol, empty
+// string if client has not requested one.
+func (ctx *Context) Subprotocol() string {
+	return ctx.hs.Protocol
 }
 
 // Disconnect method disconnects the WebSocket connection immediately. Could be
@@ -161,6 +203,22 @@ func (ctx *Context) Abort(httpErroCode int) {
 // Context struct Unexported methods
 //______________________________________________________________________________
 
+// write method writes the message to the WebSocket client, it's serialized
+// since engine shutdown sends close frame concurrently.
+func (ctx *Context) write(op gws.OpCode, p []byte) error {
+	ctx.wmu.Lock()
+	defer ctx.wmu.Unlock()
+	return wsutil.WriteServerMessage(ctx.Conn, op, p)
+}
+
+func (ctx *Context) messageCodec() Codec {
+	if ctx.codec == nil {
+		c, _ := codecByName(DefaultSubprotocol)
+		return c
+	}
+	return ctx.codec
+}
+
 // CallAction method calls the defined action for the WebSocket.
 func (ctx *Context) callAction() {
 	ctx.Log().Debugf("Calling websocket: %s.%s", ctx.websocket.FqName, ctx.action.Name)
@@ -5,11 +5,14 @@
 package ws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/ainsp"
@@ -54,6 +57,7 @@ var (
 	ErrAbortRequest          = errors.New("aahws: abort request")
 	ErrConnectionClosed      = errors.New("aahws: connection closed")
 	ErrUseOfClosedConnection = errors.New("aahws: use of closed ws connection")
+	ErrEngineShutdown        = errors.New("aahws: engine is shutting down")
 )
 
 // IDGenerator func type used to implement custom WebSocket connection ID.
@@ -85,6 +89,24 @@ type Engine struct {
 	onPostDisconnect EventCallbackFunc
 	onError          EventCallbackFunc
 	idGenerator      IDGenerator
+	subprotocols     []string
+
+	// graceful shutdown
+	mu           sync.Mutex
+	conns        map[string]*Context
+	connsWg      sync.WaitGroup
+	shutdown     bool
+	closeCode    int
+	closeReason  string
+	drainTimeout time.Duration
+}
+
+// ShutdownStats holds the WebSocket connections details of engine shutdown.
+type ShutdownStats struct {
+	Total       int
+	Graceful    int
+	ForceClosed int
+	Elapsed     time.Duration
 }
 
 // AddWebSocket method adds the given WebSocket implementation into engine.
@@ -146,6 +168,12 @@ func (e *Engine) Handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if e.isShutdown() {
+		e.Log().Warnf("WS: %s, rejecting connection: %s", ErrEngineShutdown, r.URL.Path)
+		e.replyError(w, http.StatusServiceUnavailable)
+		return
+	}
+
 	if r.Method != ahttp.MethodGet {
 		e.Log().Errorf("WS: method not allowed: %s", r.Method)
 		e.replyError(w, http.StatusMethodNotAllowed)
@@ -170,13 +198,97 @@ func (e *Engine) Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// CallAction method calls the defined action for the WebSocket.
+	e.addConn(ctx)
 	ctx.callAction()
+	e.removeConn(ctx)
 
 	if e.onPostDisconnect != nil {
 		e.onPostDisconnect(EventOnPostDisconnect, ctx)
 	}
 }
 
+// ConnectionCount method returns the currently active WebSocket connections count.
+func (e *Engine) ConnectionCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.conns)
+}
+
+// Shutdown method gracefully shutdowns the WebSocket engine. New connections
+// are rejected, close frame with configured code and reason is sent to all
+// the connected clients and waits up to drain timeout for clients to
+// acknowledge the close. Remaining connections are force closed and it
+// waits for them to close until given context is done.
+//
+//	server {
+//	  websocket {
+//	    shutdown {
+//	      close_code = 1001
+//	      close_reason = "server shutting down"
+//	      drain_timeout = "5s"
+//	    }
+//	  }
+//	}
+func (e *Engine) Shutdown(ctx context.Context) *ShutdownStats {
+	start := time.Now()
+	e.mu.Lock()
+	e.shutdown = true
+	active := make([]*Context, 0, len(e.conns))
+	for _, wctx := range e.conns {
+		active = append(active, wctx)
+	}
+	e.mu.Unlock()
+
+	stats := &ShutdownStats{Total: len(active)}
+	if stats.Total == 0 {
+		return stats
+	}
+
+	e.Log().Infof("WS: Sending close frame to %d connection(s), drain timeout %s", stats.Total, e.drainTimeout)
+	body := gws.NewCloseFrameBody(gws.StatusCode(e.closeCode), e.closeReason)
+	for _, wctx := range active {
+		if err := wctx.write(gws.OpClose, body); err != nil {
+			wctx.Log().Debugf("WS: Unable to send close frame: %v", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		e.connsWg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(e.drainTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	e.mu.Lock()
+	for _, wctx := range e.conns {
+		stats.ForceClosed++
+		_ = wctx.Disconnect()
+	}
+	e.mu.Unlock()
+
+	// force closed connections are deregistered once its action returns
+	if stats.ForceClosed > 0 {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			e.Log().Warnf("WS: %d connection(s) are not closed within shutdown timeout", e.ConnectionCount())
+		}
+	}
+
+	stats.Graceful = stats.Total - stats.ForceClosed
+	stats.Elapsed = time.Since(start)
+	e.Log().Infof("WS: Shutdown completed [total=%d graceful=%d force_closed=%d elapsed=%s]",
+		stats.Total, stats.Graceful, stats.ForceClosed, stats.Elapsed)
+	return stats
+}
+
 // Log method provides logging methods at WebSocket engine.
 func (e *Engine) Log() log.Loggerer {
 	return e.app.Log()
@@ -232,7 +344,7 @@ func (e *Engine) connect(w http.ResponseWriter, r *http.Request, route *router.R
 	}
 
 	r.Method = ahttp.MethodGet // back to GET for upgrade
-	u := gws.HTTPUpgrader{Header: ctx.Header}
+	u := gws.HTTPUpgrader{Header: ctx.Header, Protocol: e.isSubprotocolSupported}
 	conn, _, hs, err := u.Upgrade(r, w)
 	if err != nil {
 		ctx.Log().Errorf("WS: Unable establish a WebSocket connection for '%s'", ctx.Req.Path)
@@ -244,6 +356,7 @@ func (e *Engine) connect(w http.ResponseWriter, r *http.Request, route *router.R
 	// WebSocket connection successful
 	ctx.hs = hs
 	ctx.Conn = conn
+	ctx.codec, _ = codecByName(firstNonEmpty(hs.Protocol, DefaultSubprotocol))
 
 	if e.onPostConnect != nil {
 		e.onPostConnect(EventOnPostConnect, ctx)
@@ -297,12 +410,49 @@ func (e *Engine) isSameOrigin(ctx *Context) bool {
 	return strings.EqualFold(ctx.Req.Host, o.Host)
 }
 
+// isSubprotocolSupported method is used for subprotocol negotiation, first
+// supported subprotocol in the client requested order is selected.
+func (e *Engine) isSubprotocolSupported(p string) bool {
+	if len(e.subprotocols) == 0 {
+		_, found := codecByName(p)
+		return found
+	}
+	for _, v := range e.subprotocols {
+		if v == p {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *Engine) publishOnErrorEvent(ctx *Context) {
 	if e.onError != nil {
 		e.onError(EventOnError, ctx)
 	}
 }
 
+func (e *Engine) isShutdown() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.shutdown
+}
+
+func (e *Engine) addConn(ctx *Context) {
+	e.mu.Lock()
+	e.conns[ctx.Req.ID] = ctx
+	e.connsWg.Add(1)
+	e.mu.Unlock()
+}
+
+func (e *Engine) removeConn(ctx *Context) {
+	e.mu.Lock()
+	if _, found := e.conns[ctx.Req.ID]; found {
+		delete(e.conns, ctx.Req.ID)
+		e.connsWg.Done()
+	}
+	e.mu.Unlock()
+}
+
 func (e *Engine) createID(ctx *Context) string {
 	if e.idGenerator == nil {
 		return ess.NewGUID()
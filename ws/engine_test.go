@@ -5,7 +5,9 @@
 package ws
 
 import (
+	"bytes"
 	"context"
+	"encoding/gob"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -17,6 +19,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/ahttp"
 	"aahframe.work/ainsp"
@@ -137,6 +140,53 @@ func TestEngineWSClient(t *testing.T) {
 
 }
 
+func TestEngineWSSubprotocol(t *testing.T) {
+	assert.Equal(t, ErrCodecIsNil, AddCodec("gob", nil))
+	assert.Nil(t, AddCodec("gob", gobCodec{}))
+	assert.Equal(t, "ws: codec for subprotocol 'gob' is already added", AddCodec("gob", gobCodec{}).Error())
+
+	ts := createWSTestServer(t, `server { websocket { enable = true; } }`, "routes.conf")
+	wsURL := strings.Replace(ts.ts.URL, "http", "ws", -1) + "/ws/message"
+
+	// binary codec negotiated
+	conn, _, hs, err := gws.Dialer{Protocols: []string{"unknown", "gob"}}.Dial(context.Background(), wsURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "gob", hs.Protocol)
+	b, _ := gobCodec{}.Marshal(testMessage{Content: "hello gob"})
+	assert.Nil(t, wsutil.WriteClientMessage(conn, gws.OpBinary, b))
+	b, op, err := wsutil.ReadServerData(conn)
+	assert.Nil(t, err)
+	assert.Equal(t, gws.OpBinary, op)
+	var m testMessage
+	assert.Nil(t, gobCodec{}.Unmarshal(b, &m))
+	assert.Equal(t, testMessage{Content: "hello gob", Subprotocol: "gob"}, m)
+	_ = conn.Close()
+
+	// no subprotocol requested, default JSON codec
+	conn, _, hs, err = gws.Dial(context.Background(), wsURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "", hs.Protocol)
+	assert.Nil(t, wsutil.WriteClientMessage(conn, gws.OpText, []byte(`{"Content":"hello json"}`)))
+	b, op, err = wsutil.ReadServerData(conn)
+	assert.Nil(t, err)
+	assert.Equal(t, gws.OpText, op)
+	assert.Equal(t, `{"Content":"hello json","Subprotocol":""}`, string(b))
+	_ = conn.Close()
+
+	// configured subprotocols only
+	ts = createWSTestServer(t, `server { websocket { subprotocols = ["xml"]; } }`, "routes.conf")
+	wsURL = strings.Replace(ts.ts.URL, "http", "ws", -1) + "/ws/message"
+	conn, _, hs, err = gws.Dialer{Protocols: []string{"gob", "xml"}}.Dial(context.Background(), wsURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "xml", hs.Protocol)
+	_ = conn.Close()
+
+	l, _ := log.New(config.NewEmpty())
+	cfg, _ := config.ParseString(`server { websocket { subprotocols = ["msgpack"]; } }`)
+	_, err = New(&app{cfg: cfg, l: l})
+	assert.Equal(t, "ws: 'server.websocket.subprotocols' codec not found for 'msgpack', use 'ws.AddCodec'", err.Error())
+}
+
 func TestEngineWSErrors(t *testing.T) {
 	cfgStr := `
     server {
@@ -159,6 +209,77 @@ func TestEngineWSErrors(t *testing.T) {
 	assert.Equal(t, "405 Method Not Allowed", w.Body.String())
 }
 
+func TestEngineWSShutdown(t *testing.T) {
+	cfgStr := `
+    server {
+      websocket {
+        enable = true
+        shutdown {
+          close_code = 1012
+          close_reason = "service restart"
+          drain_timeout = "200ms"
+        }
+      }
+    }
+  `
+
+	ts := createWSTestServer(t, cfgStr, "routes.conf")
+	wsURL := strings.Replace(ts.ts.URL, "http", "ws", -1) + "/ws/text"
+	assert.Equal(t, 1012, ts.wse.closeCode)
+
+	// client acknowledges the close frame
+	conn1, _, _, err := gws.Dial(context.Background(), wsURL)
+	assert.Nil(t, err)
+	closed := make(chan error, 1)
+	go func() {
+		_, _, err := wsutil.ReadServerData(conn1)
+		closed <- err
+	}()
+
+	// client does not read, so it gets force closed
+	conn2, _, _, err := gws.Dial(context.Background(), wsURL)
+	assert.Nil(t, err)
+	defer conn2.Close()
+
+	for i := 0; i < 50 && ts.wse.ConnectionCount() < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 2, ts.wse.ConnectionCount())
+
+	stats := ts.wse.Shutdown(context.Background())
+	assert.Equal(t, 2, stats.Total)
+	assert.Equal(t, 1, stats.Graceful)
+	assert.Equal(t, 1, stats.ForceClosed)
+	assert.Equal(t, 0, ts.wse.ConnectionCount())
+
+	err = <-closed
+	assert.NotNil(t, err)
+	if ce, ok := err.(wsutil.ClosedError); ok {
+		assert.Equal(t, gws.StatusCode(1012), ce.Code)
+		assert.Equal(t, "service restart", ce.Reason)
+	}
+
+	// new connections are rejected
+	_, _, _, err = gws.Dial(context.Background(), wsURL)
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasSuffix(err.Error(), "503"))
+}
+
+func TestEngineWSShutdownConfig(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	for _, c := range []struct{ cfg, err string }{
+		{`server { websocket { shutdown { close_code = 200; } } }`,
+			"ws: 'server.websocket.shutdown.close_code' has invalid value '200'"},
+		{`server { websocket { shutdown { drain_timeout = "5"; } } }`,
+			"ws: 'server.websocket.shutdown.drain_timeout' value is not a valid time unit"},
+	} {
+		cfg, _ := config.ParseString(c.cfg)
+		_, err := New(&app{cfg: cfg, l: l})
+		assert.NotNil(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
+
 type testServer struct {
 	ts  *httptest.Server
 	wse *Engine
@@ -253,6 +374,7 @@ func addWebSocket(t *testing.T, wse *Engine) {
 		{Name: "Binary", Parameters: []*ainsp.Parameter{{Name: "encoding", Type: reflect.TypeOf((*string)(nil))}}},
 		{Name: "JSON"},
 		{Name: "XML"},
+		{Name: "Message"},
 	})
 }
 
@@ -347,6 +469,41 @@ func (e *testWebSocket) XML() {
 	}
 }
 
+func (e *testWebSocket) Message() {
+	for {
+		var m testMessage
+		if err := e.ReadMessage(&m); err != nil {
+			e.Log().Error(err)
+			return
+		}
+
+		m.Subprotocol = e.Subprotocol()
+		if err := e.ReplyMessage(m); err != nil {
+			e.Log().Error(err)
+			return
+		}
+	}
+}
+
+type testMessage struct {
+	Content     string
+	Subprotocol string
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (gobCodec) Binary() bool { return true }
+
 func testdataBaseDir() string {
 	wd, _ := os.Getwd()
 	if idx := strings.Index(wd, "testdata"); idx > 0 {
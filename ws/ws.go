@@ -12,8 +12,12 @@ package ws
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"aahframe.work/ainsp"
+	"aahframe.work/internal/settings"
+
+	gws "github.com/gobwas/ws"
 )
 
 // New method creates aah WebSocket engine with given aah application instance :)
@@ -47,5 +51,27 @@ func New(app interface{}) (*Engine, error) {
 		}
 	}
 
+	// subprotocols, default is all the registered codecs
+	if subprotocols, found := a.Config().StringList(keyPrefix + ".subprotocols"); found {
+		for _, p := range subprotocols {
+			if _, found := codecByName(p); !found {
+				return nil, fmt.Errorf("ws: '%s.subprotocols' codec not found for '%s', use 'ws.AddCodec'", keyPrefix, p)
+			}
+		}
+		eng.subprotocols = subprotocols
+	}
+
+	// graceful shutdown
+	eng.conns = make(map[string]*Context)
+	eng.closeCode = a.Config().IntDefault(keyPrefix+".shutdown.close_code", int(gws.StatusGoingAway))
+	if eng.closeCode < 1000 || eng.closeCode > 4999 {
+		return nil, fmt.Errorf("ws: '%s.shutdown.close_code' has invalid value '%d'", keyPrefix, eng.closeCode)
+	}
+	eng.closeReason = a.Config().StringDefault(keyPrefix+".shutdown.close_reason", "server shutting down")
+	var err error
+	if eng.drainTimeout, err = settings.ParseDuration(a.Config(), keyPrefix+".shutdown.drain_timeout", 5*time.Second); err != nil {
+		return nil, fmt.Errorf("ws: %s", err)
+	}
+
 	return eng, nil
 }